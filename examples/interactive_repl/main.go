@@ -0,0 +1,31 @@
+// Package main demonstrates a terminal chat tool built with replkit.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/replkit"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func main() {
+	ctx := context.Background()
+
+	claudeClient, err := client.NewClaudeCodeClient(ctx, types.NewClaudeCodeConfig())
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+	defer claudeClient.Close()
+
+	// A session keeps the conversation history across turns
+	session, err := claudeClient.CreateSession(ctx, "")
+	if err != nil {
+		log.Fatalf("Failed to create session: %v", err)
+	}
+
+	if err := replkit.New(session, replkit.WithPrompt("claude> ")).Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -9,10 +9,14 @@ import (
 	"os"
 
 	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/mcpserver"
 	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
 )
 
 func main() {
+	// Serve the built-in MCP servers when the CLI starts this program as one
+	mcpserver.RunSubcommand()
+
 	fmt.Println("=== MCP Server Integration Example ===")
 
 	ctx := context.Background()
@@ -75,22 +79,18 @@ func listMCPServers(claudeClient *client.ClaudeCodeClient) {
 
 // addFilesystemMCPServer demonstrates adding a filesystem MCP server
 func addFilesystemMCPServer(ctx context.Context, claudeClient *client.ClaudeCodeClient) {
-	// Create MCP server configuration
-	mcpConfig := &types.MCPServerConfig{
-		Command: "npx",
-		Args:    []string{"@modelcontextprotocol/server-filesystem", "/tmp"},
-		Environment: map[string]string{
-			"NODE_ENV": "production",
-		},
-		Enabled: true,
+	// Use the built-in Go filesystem server, which runs as a subcommand of
+	// this program and needs no Node installation
+	mcpConfig, err := mcpserver.Command(mcpserver.BuiltinFilesystem, os.TempDir())
+	if err != nil {
+		fmt.Printf("Failed to configure filesystem MCP server: %v\n", err)
+		return
 	}
 
 	// Add the MCP server
-	err := claudeClient.AddMCPServer(ctx, "filesystem", mcpConfig)
+	err = claudeClient.AddMCPServer(ctx, "filesystem", mcpConfig)
 	if err != nil {
 		fmt.Printf("Failed to add filesystem MCP server: %v\n", err)
-		fmt.Println("Note: This requires the MCP server to be installed")
-		fmt.Println("Install with: npm install -g @modelcontextprotocol/server-filesystem")
 	} else {
 		fmt.Println("Successfully added filesystem MCP server")
 
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
@@ -454,13 +453,7 @@ func formatContent(content interface{}) string {
 	case string:
 		return v
 	case []types.ContentBlock:
-		var result strings.Builder
-		for _, block := range v {
-			if block.Type == "text" {
-				result.WriteString(block.Text)
-			}
-		}
-		return result.String()
+		return types.ExtractText(types.Blocks(v))
 	default:
 		return fmt.Sprintf("%v", content)
 	}
@@ -336,13 +336,7 @@ func formatContent(content interface{}) string {
 	case string:
 		return v
 	case []types.ContentBlock:
-		var result strings.Builder
-		for _, block := range v {
-			if block.Type == "text" {
-				result.WriteString(block.Text)
-			}
-		}
-		return result.String()
+		return types.ExtractText(types.Blocks(v))
 	default:
 		return fmt.Sprintf("%v", content)
 	}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
@@ -378,13 +377,7 @@ func errorHandlingExample() {
 
 // extractTextContent extracts text from content blocks
 func extractTextContent(content []types.ContentBlock) string {
-	var result strings.Builder
-	for _, block := range content {
-		if block.Type == "text" {
-			result.WriteString(block.Text)
-		}
-	}
-	return result.String()
+	return types.ExtractText(types.Blocks(content))
 }
 
 // formatContent formats message content for display
@@ -0,0 +1,130 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// editRequest is a tool request to edit a file.
+func editRequest(path string) types.ApprovalRequest {
+	return types.ApprovalRequest{
+		Kind:       types.ApprovalKindTool,
+		ToolName:   "Edit",
+		ToolInput:  map[string]any{"file_path": path, "new_string": "x"},
+		WorkingDir: "/repo",
+	}
+}
+
+func TestStdinProvider(t *testing.T) {
+	var out strings.Builder
+	provider := &StdinProvider{In: strings.NewReader("y\nno\nnot on Fridays\n"), Out: &out}
+
+	want := []types.ApprovalDecision{
+		{Approved: true},
+		{Reason: "denied by the user"},
+		{Reason: "not on Fridays"},
+	}
+	for i, expected := range want {
+		decision, err := provider.Approve(context.Background(), editRequest("/repo/main.go"))
+		if err != nil {
+			t.Fatalf("Approve %d failed: %v", i, err)
+		}
+		if decision != expected {
+			t.Errorf("Approve %d = %+v, want %+v", i, decision, expected)
+		}
+	}
+	if !strings.Contains(out.String(), "Claude wants approval: Edit: /repo/main.go") {
+		t.Errorf("Unexpected prompt: %q", out.String())
+	}
+
+	// No more input
+	if _, err := provider.Approve(context.Background(), editRequest("a")); err == nil {
+		t.Error("Expected an error at end of input")
+	}
+}
+
+func TestStdinProvider_Canceled(t *testing.T) {
+	reader, writer := io.Pipe()
+	provider := &StdinProvider{In: reader, Out: &strings.Builder{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := provider.Approve(ctx, editRequest("a")); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	// The pending read answers the next request
+	go func() { _, _ = writer.Write([]byte("yes\n")) }()
+	decision, err := provider.Approve(context.Background(), editRequest("a"))
+	if err != nil || !decision.Approved {
+		t.Errorf("Expected approval, got %+v, %v", decision, err)
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		var request types.ApprovalRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Invalid request: %v", err)
+		}
+		approved := !strings.Contains(request.Message, "prod")
+		_ = json.NewEncoder(w).Encode(types.ApprovalDecision{Approved: approved, Reason: "checked " + request.Message})
+	}))
+	defer server.Close()
+
+	provider := &HTTPProvider{URL: server.URL, Headers: map[string]string{"X-Token": "secret"}}
+	decision, err := provider.Approve(context.Background(), editRequest("/repo/dev.yaml"))
+	if err != nil || !decision.Approved || decision.Reason != "checked Edit: /repo/dev.yaml" {
+		t.Errorf("Unexpected decision %+v, %v", decision, err)
+	}
+	decision, err = provider.Approve(context.Background(), editRequest("/repo/prod.yaml"))
+	if err != nil || decision.Approved {
+		t.Errorf("Expected denial, got %+v, %v", decision, err)
+	}
+
+	provider.Headers = nil
+	if _, err := provider.Approve(context.Background(), editRequest("a")); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected a status error, got %v", err)
+	}
+}
+
+func TestForPaths(t *testing.T) {
+	asked := 0
+	provider := ForPaths(types.ApprovalFunc(func(context.Context, types.ApprovalRequest) (types.ApprovalDecision, error) {
+		asked++
+		return types.ApprovalDecision{Reason: "protected"}, nil
+	}), "deploy/prod/**", "*.tf")
+
+	tests := []struct {
+		request  types.ApprovalRequest
+		approved bool
+	}{
+		{editRequest("/repo/deploy/prod/app.yaml"), false},
+		{editRequest("deploy/prod"), false},
+		{editRequest("/repo/infra/main.tf"), false},
+		{editRequest("/repo/deploy/staging/app.yaml"), true},
+		{editRequest("/elsewhere/deploy/prod/app.yaml"), true},
+		{types.ApprovalRequest{ToolName: "Bash", ToolInput: map[string]any{"command": "ls"}}, true},
+	}
+	for _, tt := range tests {
+		decision, err := provider.Approve(context.Background(), tt.request)
+		if err != nil || decision.Approved != tt.approved {
+			t.Errorf("Approve(%v) = %+v, %v; want approved %v", tt.request.ToolInput, decision, err, tt.approved)
+		}
+	}
+	if asked != 3 {
+		t.Errorf("Expected 3 questions, got %d", asked)
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Package approval provides human-in-the-loop implementations of
+types.ApprovalProvider, used to gate tool calls through
+ClaudeCodeConfig.ApprovalProvider and Approval steps through
+workflow.WithApprovalProvider.
+
+# Providers
+
+  - StdinProvider prompts on the terminal and reads y/n from stdin; any other
+    answer rejects the request with that text as the reason.
+  - HTTPProvider POSTs each request to an endpoint that replies with a
+    decision, for approval services and review dashboards.
+  - SlackProvider posts to a Slack incoming webhook with Approve and Reject
+    buttons and waits for the click.
+
+# Basic Usage
+
+	func main() {
+		client.RunHookSubcommand()
+
+		config := types.NewClaudeCodeConfig()
+		config.ApprovalProvider = approval.NewStdinProvider()
+		// ...
+	}
+
+# Filtering
+
+When and ForPaths wrap a provider so only some requests need a human; the
+rest are approved automatically:
+
+	// Only ask before touching production manifests
+	config.ApprovalProvider = approval.ForPaths(slack, "deploy/prod/**", "*.tf")
+*/
+package approval
@@ -0,0 +1,78 @@
+package approval
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// When asks provider only for requests that match; other requests are
+// approved without asking.
+func When(match func(request types.ApprovalRequest) bool, provider types.ApprovalProvider) types.ApprovalProvider {
+	return types.ApprovalFunc(func(ctx context.Context, request types.ApprovalRequest) (types.ApprovalDecision, error) {
+		if !match(request) {
+			return types.ApprovalDecision{Approved: true}, nil
+		}
+		return provider.Approve(ctx, request)
+	})
+}
+
+// ForPaths asks provider only for tool calls on files matching one of the
+// patterns, such as "deploy/prod/**" or "*.tf". Patterns use filepath.Match
+// syntax against paths relative to the request's working directory; a
+// trailing "/**" matches everything below a directory. Tool calls without a
+// file path, such as Bash, are approved without asking.
+func ForPaths(provider types.ApprovalProvider, patterns ...string) types.ApprovalProvider {
+	return When(func(request types.ApprovalRequest) bool {
+		path := requestPath(request)
+		if path == "" {
+			return false
+		}
+		for _, pattern := range patterns {
+			if matchPath(pattern, path) {
+				return true
+			}
+		}
+		return false
+	}, provider)
+}
+
+// requestPath returns the file path of a tool call, relative to its working
+// directory when possible.
+func requestPath(request types.ApprovalRequest) string {
+	var path string
+	for _, key := range []string{"file_path", "notebook_path", "path"} {
+		if value, ok := request.ToolInput[key].(string); ok && value != "" {
+			path = value
+			break
+		}
+	}
+	if path == "" {
+		return ""
+	}
+
+	if filepath.IsAbs(path) && request.WorkingDir != "" {
+		if rel, err := filepath.Rel(request.WorkingDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// matchPath matches a slash-separated path against a pattern.
+func matchPath(pattern, path string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return path == dir || strings.HasPrefix(path, dir+"/")
+	}
+	if matched, _ := filepath.Match(pattern, path); matched {
+		return true
+	}
+	// Patterns without a directory match the base name anywhere
+	if !strings.Contains(pattern, "/") {
+		matched, _ := filepath.Match(pattern, filepath.Base(path))
+		return matched
+	}
+	return false
+}
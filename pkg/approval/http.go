@@ -0,0 +1,68 @@
+package approval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// HTTPProvider delegates approvals to an HTTP endpoint. Each request is
+// POSTed as JSON, and the endpoint replies, possibly after a human decided,
+// with a JSON ApprovalDecision such as {"approved": true}.
+type HTTPProvider struct {
+	// URL is the approval endpoint
+	URL string
+
+	// Headers are added to every request, e.g. for authentication
+	Headers map[string]string
+
+	// Client sends the requests (default http.DefaultClient)
+	Client *http.Client
+}
+
+// Approve posts the request and returns the endpoint's decision.
+func (p *HTTPProvider) Approve(ctx context.Context, request types.ApprovalRequest) (types.ApprovalDecision, error) {
+	if request.Message == "" {
+		request.Message = request.Summary()
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return types.ApprovalDecision{}, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JSON_MARSHAL", "failed to encode approval request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return types.ApprovalDecision{}, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "APPROVAL_URL", "invalid approval URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range p.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return types.ApprovalDecision{}, sdkerrors.NewNetworkError("approval", p.URL, err)
+	}
+	defer func() { _ = resp.Body.Close() }() // Ignore error, the body has been read
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return types.ApprovalDecision{}, sdkerrors.NewInternalError("APPROVAL_HTTP", fmt.Sprintf("approval endpoint returned %s: %s", resp.Status, message))
+	}
+
+	var decision types.ApprovalDecision
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return types.ApprovalDecision{}, sdkerrors.WrapError(err, sdkerrors.CategoryAPI, "APPROVAL_HTTP", "invalid approval response")
+	}
+	return decision, nil
+}
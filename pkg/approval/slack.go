@@ -0,0 +1,156 @@
+package approval
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// SlackProvider posts approval requests to a Slack incoming webhook with
+// Approve and Reject buttons. The buttons link to CallbackURL, which must
+// route to the provider's ServeHTTP so it can deliver the decision.
+//
+// Example usage:
+//
+//	slack := &approval.SlackProvider{
+//		WebhookURL:  os.Getenv("SLACK_WEBHOOK_URL"),
+//		CallbackURL: "https://agents.example.com/approvals",
+//	}
+//	http.Handle("/approvals", slack)
+//	config.ApprovalProvider = slack
+type SlackProvider struct {
+	// WebhookURL is the Slack incoming webhook
+	WebhookURL string
+
+	// CallbackURL is the public URL of ServeHTTP
+	CallbackURL string
+
+	// Client sends the webhook requests (default http.DefaultClient)
+	Client *http.Client
+
+	mu      sync.Mutex
+	pending map[string]chan types.ApprovalDecision
+}
+
+// Approve posts the request to Slack and waits until someone clicks a button
+// or ctx is done.
+func (p *SlackProvider) Approve(ctx context.Context, request types.ApprovalRequest) (types.ApprovalDecision, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return types.ApprovalDecision{}, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "APPROVAL_ID", "failed to generate approval ID")
+	}
+	token := hex.EncodeToString(id)
+
+	decided := make(chan types.ApprovalDecision, 1)
+	p.mu.Lock()
+	if p.pending == nil {
+		p.pending = make(map[string]chan types.ApprovalDecision)
+	}
+	p.pending[token] = decided
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.pending, token)
+		p.mu.Unlock()
+	}()
+
+	if err := p.post(ctx, request, token); err != nil {
+		return types.ApprovalDecision{}, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return types.ApprovalDecision{}, ctx.Err()
+	case decision := <-decided:
+		return decision, nil
+	}
+}
+
+// post sends the Slack message for a request.
+func (p *SlackProvider) post(ctx context.Context, request types.ApprovalRequest, token string) error {
+	link := func(decision string) string {
+		return p.CallbackURL + "?" + url.Values{"id": {token}, "decision": {decision}}.Encode()
+	}
+	summary := request.Summary()
+
+	body, err := json.Marshal(map[string]any{
+		"text": "Claude wants approval: " + summary,
+		"blocks": []any{
+			map[string]any{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": "*Claude wants approval*\n```" + summary + "```"},
+			},
+			map[string]any{
+				"type": "actions",
+				"elements": []any{
+					map[string]any{"type": "button", "style": "primary", "text": map[string]any{"type": "plain_text", "text": "Approve"}, "url": link("approve")},
+					map[string]any{"type": "button", "style": "danger", "text": map[string]any{"type": "plain_text", "text": "Reject"}, "url": link("reject")},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JSON_MARSHAL", "failed to encode Slack message")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "APPROVAL_URL", "invalid Slack webhook URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return sdkerrors.NewNetworkError("slack webhook", "", err)
+	}
+	defer func() { _ = resp.Body.Close() }() // Ignore error, the body has been read
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return sdkerrors.NewInternalError("APPROVAL_SLACK", fmt.Sprintf("Slack webhook returned %s: %s", resp.Status, message))
+	}
+	return nil
+}
+
+// ServeHTTP receives the clicks on the Approve and Reject buttons.
+func (p *SlackProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("id")
+	decision := r.URL.Query().Get("decision")
+	if decision != "approve" && decision != "reject" {
+		http.Error(w, "invalid decision", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	decided, ok := p.pending[token]
+	delete(p.pending, token)
+	p.mu.Unlock()
+	if !ok {
+		http.Error(w, "this request was already answered or has expired", http.StatusNotFound)
+		return
+	}
+
+	if decision == "approve" {
+		decided <- types.ApprovalDecision{Approved: true}
+	} else {
+		decided <- types.ApprovalDecision{Reason: "rejected in Slack"}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, "<p>Request %sd. You can close this page.</p>", html.EscapeString(decision))
+}
@@ -0,0 +1,83 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// slackMessage is the subset of a webhook message used by the tests.
+type slackMessage struct {
+	Text   string `json:"text"`
+	Blocks []struct {
+		Elements []struct {
+			URL string `json:"url"`
+		} `json:"elements"`
+	} `json:"blocks"`
+}
+
+func TestSlackProvider(t *testing.T) {
+	provider := &SlackProvider{CallbackURL: "https://agents.example.com/approvals"}
+	callbacks := httptest.NewServer(provider)
+	defer callbacks.Close()
+
+	// The webhook clicks a button as soon as the message arrives: the choice
+	// 0 approves and 1 rejects
+	choice := make(chan int, 1)
+	click := make(chan string, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message slackMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("Invalid message: %v", err)
+		}
+		if !strings.Contains(message.Text, "Edit: /repo/main.go") {
+			t.Errorf("Unexpected message text %q", message.Text)
+		}
+		buttons := message.Blocks[1].Elements
+		click <- buttons[<-choice].URL
+	}))
+	defer webhook.Close()
+	provider.WebhookURL = webhook.URL
+
+	go func() {
+		for link := range click {
+			parsed, _ := url.Parse(link)
+			resp, err := http.Get(callbacks.URL + "?" + parsed.RawQuery)
+			if err != nil {
+				t.Errorf("Click failed: %v", err)
+				continue
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Click returned %s", resp.Status)
+			}
+		}
+	}()
+	defer close(click)
+
+	choice <- 0
+	decision, err := provider.Approve(context.Background(), editRequest("/repo/main.go"))
+	if err != nil || !decision.Approved {
+		t.Errorf("Expected approval, got %+v, %v", decision, err)
+	}
+
+	choice <- 1
+	decision, err = provider.Approve(context.Background(), editRequest("/repo/main.go"))
+	if err != nil || decision.Approved || decision.Reason != "rejected in Slack" {
+		t.Errorf("Expected rejection, got %+v, %v", decision, err)
+	}
+
+	// Answered requests cannot be answered again
+	resp, err := http.Get(callbacks.URL + "?id=unknown&decision=approve")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown request, got %s", resp.Status)
+	}
+}
@@ -0,0 +1,89 @@
+package approval
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// StdinProvider asks for approval on a terminal. Requests are asked one at a
+// time; an answer starting with "y" approves.
+type StdinProvider struct {
+	// In is read for answers (default os.Stdin)
+	In io.Reader
+
+	// Out receives the questions (default os.Stderr)
+	Out io.Writer
+
+	mu      sync.Mutex
+	reader  *bufio.Reader
+	pending chan stdinLine
+}
+
+// stdinLine is an answer read from In.
+type stdinLine struct {
+	text string
+	err  error
+}
+
+// NewStdinProvider returns a provider that asks on stdin and stderr.
+func NewStdinProvider() *StdinProvider {
+	return &StdinProvider{}
+}
+
+// Approve prints the request and waits for a yes or no answer. Other answers
+// deny the request with the answer as the reason.
+func (p *StdinProvider) Approve(ctx context.Context, request types.ApprovalRequest) (types.ApprovalDecision, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.reader == nil {
+		in := p.In
+		if in == nil {
+			in = os.Stdin
+		}
+		p.reader = bufio.NewReader(in)
+	}
+	out := p.Out
+	if out == nil {
+		out = os.Stderr
+	}
+
+	fmt.Fprintf(out, "\nClaude wants approval: %s\nApprove? [y/N]: ", request.Summary())
+
+	// Read in the background so a canceled context does not wait for input;
+	// a read left pending by a canceled request answers the next one
+	if p.pending == nil {
+		pending := make(chan stdinLine, 1)
+		go func(reader *bufio.Reader) {
+			text, err := reader.ReadString('\n')
+			pending <- stdinLine{text, err}
+		}(p.reader)
+		p.pending = pending
+	}
+
+	select {
+	case <-ctx.Done():
+		return types.ApprovalDecision{}, ctx.Err()
+	case answer := <-p.pending:
+		p.pending = nil
+		text := strings.TrimSpace(answer.text)
+		if answer.err != nil && text == "" {
+			return types.ApprovalDecision{}, answer.err
+		}
+		switch strings.ToLower(text) {
+		case "y", "yes":
+			return types.ApprovalDecision{Approved: true}, nil
+		case "", "n", "no":
+			return types.ApprovalDecision{Reason: "denied by the user"}, nil
+		default:
+			return types.ApprovalDecision{Reason: text}, nil
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// DefaultPrefix is the key prefix transcripts are archived under. It groups
+// transcripts by the day they ended.
+const DefaultPrefix = "transcripts/{year}/{month}/{day}"
+
+// Transcript is a completed conversation to archive.
+type Transcript struct {
+	// SessionID identifies the conversation and names the archived objects
+	SessionID string
+
+	// JobID is the job that ran the conversation, if any
+	JobID string
+
+	// Model is the model that answered
+	Model string
+
+	// Messages is the conversation in order
+	Messages []types.Message
+
+	// Usage is the conversation's token usage, if known
+	Usage *types.TokenUsage
+
+	// StartedAt and EndedAt bound the conversation; a zero EndedAt is the
+	// time of archival
+	StartedAt time.Time
+	EndedAt   time.Time
+
+	// Labels are added to the archived metadata, after the archiver's labels
+	Labels map[string]string
+}
+
+// FromCheckpoint returns the transcript of a job checkpoint, labeled with
+// the job's query metadata.
+func FromCheckpoint(checkpoint *client.JobCheckpoint) *Transcript {
+	transcript := &Transcript{
+		SessionID: checkpoint.SessionID,
+		JobID:     checkpoint.JobID,
+		Messages:  checkpoint.Messages,
+		StartedAt: checkpoint.CreatedAt,
+		EndedAt:   checkpoint.UpdatedAt,
+		Labels:    checkpoint.Metadata,
+	}
+	if checkpoint.Response != nil {
+		transcript.Model = checkpoint.Response.Model
+		transcript.Usage = checkpoint.Response.Usage
+	}
+	if transcript.Model == "" && checkpoint.Request != nil {
+		transcript.Model = checkpoint.Request.Model
+	}
+	return transcript
+}
+
+// Metadata describes an archived transcript. It is stored next to the
+// transcript as a JSON object.
+type Metadata struct {
+	SessionID    string            `json:"session_id"`
+	JobID        string            `json:"job_id,omitempty"`
+	Model        string            `json:"model,omitempty"`
+	MessageCount int               `json:"message_count"`
+	Usage        *types.TokenUsage `json:"usage,omitempty"`
+	StartedAt    time.Time         `json:"started_at,omitempty"`
+	EndedAt      time.Time         `json:"ended_at"`
+	ArchivedAt   time.Time         `json:"archived_at"`
+
+	// RetainUntil is when the transcript may be deleted, if retention is set
+	RetainUntil *time.Time `json:"retain_until,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// TranscriptKey and TranscriptSHA256 identify the JSONL transcript
+	TranscriptKey    string `json:"transcript_key"`
+	TranscriptSHA256 string `json:"transcript_sha256"`
+
+	// MetadataKey is the key of this metadata object
+	MetadataKey string `json:"metadata_key"`
+}
+
+// Archiver writes transcripts to a Sink as a JSONL object with one message
+// per line and a JSON metadata object, named after the session.
+type Archiver struct {
+	sink      Sink
+	prefix    string
+	retention time.Duration
+	labels    map[string]string
+	now       func() time.Time
+}
+
+// Option configures an Archiver.
+type Option func(*Archiver)
+
+// WithPrefix sets the key prefix (default DefaultPrefix). The placeholders
+// {year}, {month} and {day} are replaced with the date the transcript
+// ended, in UTC, and {session} and {job} with its session and job IDs.
+func WithPrefix(prefix string) Option {
+	return func(a *Archiver) {
+		a.prefix = prefix
+	}
+}
+
+// WithRetention records that transcripts must be kept for the duration after
+// they are archived. Sinks that support it, such as an S3Sink with object
+// lock, enforce the retention; all sinks record it in the metadata.
+func WithRetention(retention time.Duration) Option {
+	return func(a *Archiver) {
+		a.retention = retention
+	}
+}
+
+// WithLabels adds labels, such as a team or data classification, to the
+// metadata of every transcript.
+func WithLabels(labels map[string]string) Option {
+	return func(a *Archiver) {
+		a.labels = labels
+	}
+}
+
+// New creates an archiver that writes to sink.
+func New(sink Sink, options ...Option) *Archiver {
+	a := &Archiver{sink: sink, prefix: DefaultPrefix, now: time.Now}
+	for _, option := range options {
+		option(a)
+	}
+	return a
+}
+
+// Archive writes a transcript and then its metadata, so that metadata only
+// exists for complete transcripts. It returns the archived metadata.
+func (a *Archiver) Archive(ctx context.Context, transcript *Transcript) (*Metadata, error) {
+	if transcript == nil || transcript.SessionID == "" {
+		return nil, sdkerrors.NewValidationError("sessionID", "", "required", "transcripts require a session ID")
+	}
+	if strings.ContainsAny(transcript.SessionID, `/\`) || transcript.SessionID == "." || transcript.SessionID == ".." {
+		return nil, sdkerrors.NewValidationError("sessionID", transcript.SessionID, "key-safe session ID", "invalid session ID")
+	}
+
+	now := a.now()
+	endedAt := transcript.EndedAt
+	if endedAt.IsZero() {
+		endedAt = now
+	}
+	base := path.Join(a.expandPrefix(transcript, endedAt.UTC()), transcript.SessionID)
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for i := range transcript.Messages {
+		if err := encoder.Encode(&transcript.Messages[i]); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "ARCHIVE_ENCODE", "failed to encode transcript")
+		}
+	}
+	sum := sha256.Sum256(body.Bytes())
+
+	metadata := &Metadata{
+		SessionID:        transcript.SessionID,
+		JobID:            transcript.JobID,
+		Model:            transcript.Model,
+		MessageCount:     len(transcript.Messages),
+		Usage:            transcript.Usage,
+		StartedAt:        transcript.StartedAt,
+		EndedAt:          endedAt,
+		ArchivedAt:       now,
+		Labels:           mergeLabels(a.labels, transcript.Labels),
+		TranscriptKey:    base + ".jsonl",
+		TranscriptSHA256: hex.EncodeToString(sum[:]),
+		MetadataKey:      base + ".metadata.json",
+	}
+	var retainUntil time.Time
+	if a.retention > 0 {
+		retainUntil = now.Add(a.retention).UTC()
+		metadata.RetainUntil = &retainUntil
+	}
+
+	objectMetadata := map[string]string{"session-id": transcript.SessionID}
+	if transcript.JobID != "" {
+		objectMetadata["job-id"] = transcript.JobID
+	}
+	if !retainUntil.IsZero() {
+		objectMetadata["retain-until"] = retainUntil.Format(time.RFC3339)
+	}
+
+	if err := a.put(ctx, &Object{
+		Key:         metadata.TranscriptKey,
+		Body:        body.Bytes(),
+		ContentType: "application/x-ndjson",
+		Metadata:    objectMetadata,
+		RetainUntil: retainUntil,
+	}); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "ARCHIVE_ENCODE", "failed to encode transcript metadata")
+	}
+	if err := a.put(ctx, &Object{
+		Key:         metadata.MetadataKey,
+		Body:        data,
+		ContentType: "application/json",
+		Metadata:    objectMetadata,
+		RetainUntil: retainUntil,
+	}); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// put writes an object, wrapping sink errors.
+func (a *Archiver) put(ctx context.Context, object *Object) error {
+	if err := a.sink.Put(ctx, object); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryNetwork, "ARCHIVE_PUT", "failed to archive "+object.Key)
+	}
+	return nil
+}
+
+// expandPrefix replaces the prefix placeholders for a transcript.
+func (a *Archiver) expandPrefix(transcript *Transcript, endedAt time.Time) string {
+	return strings.NewReplacer(
+		"{year}", endedAt.Format("2006"),
+		"{month}", endedAt.Format("01"),
+		"{day}", endedAt.Format("02"),
+		"{session}", transcript.SessionID,
+		"{job}", transcript.JobID,
+	).Replace(a.prefix)
+}
+
+// mergeLabels returns the labels of both maps, with later maps winning.
+func mergeLabels(maps ...map[string]string) map[string]string {
+	var merged map[string]string
+	for _, labels := range maps {
+		for key, value := range labels {
+			if merged == nil {
+				merged = make(map[string]string)
+			}
+			merged[key] = value
+		}
+	}
+	return merged
+}
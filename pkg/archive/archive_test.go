@@ -0,0 +1,172 @@
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// recordingSink keeps the objects put to it.
+type recordingSink struct {
+	mu      sync.Mutex
+	objects []*Object
+	err     error
+}
+
+func (s *recordingSink) Put(ctx context.Context, object *Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.objects = append(s.objects, object)
+	return nil
+}
+
+func TestArchiver_FileSink(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewFileSink(dir)
+	if err != nil {
+		t.Fatalf("NewFileSink failed: %v", err)
+	}
+
+	archiver := New(sink, WithRetention(24*time.Hour), WithLabels(map[string]string{"team": "platform", "env": "dev"}))
+	archivedAt := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	archiver.now = func() time.Time { return archivedAt }
+
+	metadata, err := archiver.Archive(context.Background(), &Transcript{
+		SessionID: "session-1",
+		Model:     "claude-sonnet",
+		Messages: []types.Message{
+			{Role: types.RoleUser, Content: "hello"},
+			{Role: types.RoleAssistant, Content: "hi\nthere"},
+		},
+		EndedAt: time.Date(2026, 3, 3, 23, 0, 0, 0, time.UTC),
+		Labels:  map[string]string{"env": "prod"},
+	})
+	if err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+
+	if metadata.TranscriptKey != "transcripts/2026/03/03/session-1.jsonl" || metadata.MetadataKey != "transcripts/2026/03/03/session-1.metadata.json" {
+		t.Errorf("Unexpected keys: %s %s", metadata.TranscriptKey, metadata.MetadataKey)
+	}
+	if metadata.RetainUntil == nil || !metadata.RetainUntil.Equal(archivedAt.Add(24*time.Hour)) {
+		t.Errorf("Unexpected retention: %v", metadata.RetainUntil)
+	}
+	if metadata.Labels["team"] != "platform" || metadata.Labels["env"] != "prod" || metadata.MessageCount != 2 {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+
+	file, err := os.Open(filepath.Join(dir, filepath.FromSlash(metadata.TranscriptKey)))
+	if err != nil {
+		t.Fatalf("Expected transcript file: %v", err)
+	}
+	defer file.Close()
+	var lines []types.Message
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var message types.Message
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			t.Fatalf("Invalid JSONL line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, message)
+	}
+	if len(lines) != 2 || lines[1].Content != "hi\nthere" {
+		t.Errorf("Unexpected transcript: %+v", lines)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(metadata.MetadataKey)))
+	if err != nil {
+		t.Fatalf("Expected metadata file: %v", err)
+	}
+	var stored Metadata
+	if err := json.Unmarshal(data, &stored); err != nil || stored.TranscriptSHA256 != metadata.TranscriptSHA256 || stored.Model != "claude-sonnet" {
+		t.Errorf("Unexpected stored metadata: %s (%v)", data, err)
+	}
+
+	if _, err := archiver.Archive(context.Background(), &Transcript{SessionID: "../escape"}); err == nil {
+		t.Error("Expected a path-like session ID to be rejected")
+	}
+	if err := sink.Put(context.Background(), &Object{Key: "a/../../escape"}); err == nil {
+		t.Error("Expected a key escaping the directory to be rejected")
+	}
+}
+
+func TestArchiver_Prefix(t *testing.T) {
+	sink := &recordingSink{}
+	archiver := New(sink, WithPrefix("org/{job}/{year}-{month}"))
+
+	_, err := archiver.Archive(context.Background(), &Transcript{
+		SessionID: "s",
+		JobID:     "nightly",
+		EndedAt:   time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+	if len(sink.objects) != 2 || sink.objects[0].Key != "org/nightly/2026-07/s.jsonl" || sink.objects[1].Key != "org/nightly/2026-07/s.metadata.json" {
+		t.Fatalf("Unexpected objects: %+v", sink.objects)
+	}
+	if meta := sink.objects[0].Metadata; meta["job-id"] != "nightly" || meta["retain-until"] != "" || !sink.objects[0].RetainUntil.IsZero() {
+		t.Errorf("Unexpected object metadata: %v", meta)
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	ctx := context.Background()
+	sink := &recordingSink{}
+	store := NewSessionStore(client.NewMemorySessionStore(), New(sink), nil)
+
+	checkpoint := &client.JobCheckpoint{
+		JobID:     "job",
+		SessionID: "session",
+		Request:   &types.QueryRequest{Model: "claude-haiku", Messages: []types.Message{{Role: types.RoleUser, Content: "go"}}},
+		Messages:  []types.Message{{Role: types.RoleUser, Content: "go"}},
+		Metadata:  map[string]string{"feature": "nightly-review"},
+		CreatedAt: time.Now(),
+	}
+	if err := store.SaveCheckpoint(ctx, checkpoint); err != nil {
+		t.Fatalf("SaveCheckpoint failed: %v", err)
+	}
+	if len(sink.objects) != 0 {
+		t.Fatalf("Expected running jobs not to be archived, got %d objects", len(sink.objects))
+	}
+
+	checkpoint.Completed = true
+	checkpoint.Messages = append(checkpoint.Messages, types.Message{Role: types.RoleAssistant, Content: "done"})
+	if err := store.SaveCheckpoint(ctx, checkpoint); err != nil {
+		t.Fatalf("SaveCheckpoint failed: %v", err)
+	}
+	if len(sink.objects) != 2 || sink.objects[0].Metadata["session-id"] != "session" {
+		t.Fatalf("Expected the completed job archived, got %+v", sink.objects)
+	}
+	var metadata Metadata
+	if err := json.Unmarshal(sink.objects[1].Body, &metadata); err != nil || metadata.Model != "claude-haiku" || metadata.MessageCount != 2 || metadata.JobID != "job" ||
+		metadata.Labels["feature"] != "nightly-review" {
+		t.Errorf("Unexpected metadata: %s (%v)", sink.objects[1].Body, err)
+	}
+	if loaded, err := store.LoadCheckpoint(ctx, "job"); err != nil || !loaded.Completed {
+		t.Errorf("Expected the wrapped store to keep the checkpoint: %+v %v", loaded, err)
+	}
+
+	// Archival failures are returned, or reported to the error handler
+	sink.err = errors.New("bucket unavailable")
+	if err := store.SaveCheckpoint(ctx, checkpoint); err == nil {
+		t.Error("Expected the archival failure to be returned")
+	}
+	var reported string
+	store = NewSessionStore(client.NewMemorySessionStore(), New(sink), func(jobID string, err error) { reported = jobID })
+	if err := store.SaveCheckpoint(ctx, checkpoint); err != nil || reported != "job" {
+		t.Errorf("Expected the failure reported for job, got %v %q", err, reported)
+	}
+}
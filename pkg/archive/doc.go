@@ -0,0 +1,42 @@
+/*
+Package archive writes completed Claude Code transcripts to object storage,
+for organizations required to retain AI interaction logs.
+
+An Archiver writes each transcript as a JSONL object, one message per line,
+followed by a JSON object of Metadata: the session and job, model, token
+usage, timing, labels, a SHA-256 of the transcript and, with WithRetention,
+the time until which it must be kept. Objects are named after the session
+under a configurable prefix, by default transcripts/{year}/{month}/{day}.
+
+# Basic Usage
+
+	sink, err := archive.NewS3Sink(archive.S3Config{
+		Bucket:         "acme-ai-audit",
+		Region:         "us-east-1",
+		ObjectLockMode: archive.ObjectLockCompliance,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	archiver := archive.New(sink,
+		archive.WithPrefix("claude/{year}/{month}/{day}"),
+		archive.WithRetention(7*365*24*time.Hour),
+		archive.WithLabels(map[string]string{"team": "platform"}),
+	)
+
+	// Archive every job run with RunJob once it completes
+	store, _ := client.NewFileSessionStore("/var/lib/myapp/checkpoints")
+	claudeClient.SetSessionStore(archive.NewSessionStore(store, archiver, nil))
+
+Transcripts from other sources can be archived directly with Archive.
+
+# Sinks
+
+S3Sink writes to Amazon S3 or an S3-compatible service, signing requests
+with AWS Signature Version 4 from configured or environment credentials.
+With ObjectLockMode set, retention is enforced with S3 Object Lock.
+NewGCSSink writes to Google Cloud Storage through its S3-compatible XML API
+with an HMAC key; enforce retention there with a bucket retention policy.
+FileSink writes to a local directory. Other destinations implement Sink.
+*/
+package archive
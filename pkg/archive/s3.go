@@ -0,0 +1,301 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5" // #nosec G501 - Content-MD5 is required by S3 for object lock uploads, not used for security
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// Object lock modes for S3Config.ObjectLockMode.
+const (
+	ObjectLockGovernance = "GOVERNANCE"
+	ObjectLockCompliance = "COMPLIANCE"
+)
+
+// S3Config configures an S3Sink.
+type S3Config struct {
+	// Bucket is the bucket objects are written to
+	Bucket string
+
+	// Region is the bucket's region (default $AWS_REGION, then us-east-1)
+	Region string
+
+	// Endpoint is the base URL of an S3-compatible service such as MinIO
+	// (default https://s3.<region>.amazonaws.com)
+	Endpoint string
+
+	// PathStyle addresses the bucket in the URL path rather than the host
+	// name. It is implied by a custom Endpoint.
+	PathStyle bool
+
+	// AccessKeyID, SecretAccessKey and SessionToken are the credentials
+	// (default $AWS_ACCESS_KEY_ID, $AWS_SECRET_ACCESS_KEY and
+	// $AWS_SESSION_TOKEN)
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// ObjectLockMode enforces an Archiver's retention with S3 Object Lock,
+	// which must be enabled on the bucket
+	ObjectLockMode string
+
+	// StorageClass is the storage class of written objects, such as
+	// GLACIER_IR (default STANDARD)
+	StorageClass string
+
+	// HTTPClient sends requests (default http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// S3Sink writes objects to Amazon S3 or an S3-compatible service, signing
+// requests with AWS Signature Version 4.
+type S3Sink struct {
+	config   S3Config
+	endpoint *url.URL
+	service  string
+	metaKey  string
+}
+
+// NewS3Sink creates a sink writing to an S3 bucket.
+func NewS3Sink(config S3Config) (*S3Sink, error) {
+	if config.Region == "" {
+		config.Region = os.Getenv("AWS_REGION")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.AccessKeyID == "" && config.SecretAccessKey == "" {
+		config.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		config.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		if config.SessionToken == "" {
+			config.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+		}
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	} else {
+		config.PathStyle = true
+	}
+	return newS3Sink(config, "x-amz-meta-")
+}
+
+// GCSConfig configures a Google Cloud Storage sink.
+type GCSConfig struct {
+	// Bucket is the bucket objects are written to
+	Bucket string
+
+	// AccessKeyID and Secret are an HMAC key of a service account with
+	// write access to the bucket
+	AccessKeyID string
+	Secret      string
+
+	// Endpoint is the XML API base URL (default https://storage.googleapis.com)
+	Endpoint string
+
+	// HTTPClient sends requests (default http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// NewGCSSink creates a sink writing to a Google Cloud Storage bucket through
+// its S3-compatible XML API. Retention is recorded in object metadata; use a
+// bucket retention policy to enforce it.
+func NewGCSSink(config GCSConfig) (*S3Sink, error) {
+	if config.Endpoint == "" {
+		config.Endpoint = "https://storage.googleapis.com"
+	}
+	return newS3Sink(S3Config{
+		Bucket:          config.Bucket,
+		Region:          "auto",
+		Endpoint:        config.Endpoint,
+		PathStyle:       true,
+		AccessKeyID:     config.AccessKeyID,
+		SecretAccessKey: config.Secret,
+		HTTPClient:      config.HTTPClient,
+	}, "x-goog-meta-")
+}
+
+// newS3Sink validates a completed configuration.
+func newS3Sink(config S3Config, metaKey string) (*S3Sink, error) {
+	var violations []sdkerrors.ValidationViolation
+	if config.Bucket == "" {
+		violations = append(violations, sdkerrors.ValidationViolation{Field: "bucket", Code: "required", Message: "bucket is required"})
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		violations = append(violations, sdkerrors.ValidationViolation{Field: "credentials", Code: "required", Message: "access key ID and secret are required"})
+	}
+	switch config.ObjectLockMode {
+	case "", ObjectLockGovernance, ObjectLockCompliance:
+	default:
+		violations = append(violations, sdkerrors.ValidationViolation{Field: "object_lock_mode", Code: "enum", Message: "object lock mode must be GOVERNANCE or COMPLIANCE", Value: config.ObjectLockMode})
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		violations = append(violations, sdkerrors.ValidationViolation{Field: "endpoint", Code: "url", Message: "endpoint must be an absolute URL", Value: config.Endpoint})
+	}
+	if len(violations) > 0 {
+		return nil, sdkerrors.NewValidationErrorWithViolations(violations)
+	}
+
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &S3Sink{config: config, endpoint: endpoint, service: "s3", metaKey: metaKey}, nil
+}
+
+// Put implements Sink with a signed PUT Object request.
+func (s *S3Sink) Put(ctx context.Context, object *Object) error {
+	target := *s.endpoint
+	key := strings.TrimPrefix(object.Key, "/")
+	if s.config.PathStyle {
+		target.Path = strings.TrimSuffix(target.Path, "/") + "/" + s.config.Bucket + "/" + key
+	} else {
+		target.Host = s.config.Bucket + "." + target.Host
+		target.Path = "/" + key
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(object.Body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(object.Body))
+	sum := md5.Sum(object.Body) // #nosec G401 - integrity check required by S3, not a security control
+	req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	if object.ContentType != "" {
+		req.Header.Set("Content-Type", object.ContentType)
+	}
+	for name, value := range object.Metadata {
+		req.Header.Set(s.metaKey+name, value)
+	}
+	if s.config.StorageClass != "" {
+		req.Header.Set("X-Amz-Storage-Class", s.config.StorageClass)
+	}
+	if s.config.ObjectLockMode != "" && !object.RetainUntil.IsZero() {
+		req.Header.Set("X-Amz-Object-Lock-Mode", s.config.ObjectLockMode)
+		req.Header.Set("X-Amz-Object-Lock-Retain-Until-Date", object.RetainUntil.UTC().Format(time.RFC3339))
+	}
+	if s.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+	s.sign(req, hashHex(object.Body), time.Now())
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("PUT %s: %s: %s", object.Key, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// sign adds AWS Signature Version 4 headers to a request, signing every
+// header already set along with the host.
+func (s *S3Sink) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.Join(strings.Fields(headers[name]), " ") + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		encodePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/" + s.service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, s.service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.config.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// encodePath URI-encodes each segment of a path as Signature Version 4
+// requires, leaving only unreserved characters unescaped.
+func encodePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = encodeURIComponent(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery returns the sorted, encoded query string.
+func canonicalQuery(values url.Values) string {
+	var pairs []string
+	for name, list := range values {
+		for _, value := range list {
+			pairs = append(pairs, encodeURIComponent(name)+"="+encodeURIComponent(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// encodeURIComponent percent-encodes all but unreserved characters.
+func encodeURIComponent(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,83 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// Object is a file written to a Sink.
+type Object struct {
+	// Key is the slash-separated object name
+	Key string
+
+	// Body is the object content
+	Body []byte
+
+	// ContentType is the MIME type of Body
+	ContentType string
+
+	// Metadata is stored with the object where the sink supports it
+	Metadata map[string]string
+
+	// RetainUntil is when the object may be deleted, or zero
+	RetainUntil time.Time
+}
+
+// Sink stores archived objects. Implementations must be safe for concurrent
+// use.
+type Sink interface {
+	// Put creates or replaces an object
+	Put(ctx context.Context, object *Object) error
+}
+
+// FileSink writes objects below a local directory, for development or for
+// directories synced to object storage by other means. Object metadata is
+// not stored.
+type FileSink struct {
+	dir string
+}
+
+// NewFileSink creates a sink writing below dir, creating it if needed.
+func NewFileSink(dir string) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "ARCHIVE_DIR", "failed to create archive directory")
+	}
+	return &FileSink{dir: dir}, nil
+}
+
+// Put implements Sink. Files are replaced atomically.
+func (s *FileSink) Put(ctx context.Context, object *Object) error {
+	clean := path.Clean("/" + object.Key)[1:]
+	if clean == "" || clean != strings.TrimPrefix(object.Key, "/") {
+		return sdkerrors.NewValidationError("key", object.Key, "clean relative key", "invalid object key")
+	}
+	target := filepath.Join(s.dir, filepath.FromSlash(clean))
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".archive-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(object.Body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package archive
+
+import (
+	"context"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+)
+
+// SessionStore wraps a client.SessionStore and archives the transcript of
+// each job when its completed checkpoint is saved.
+type SessionStore struct {
+	client.SessionStore
+	archiver *Archiver
+	onError  func(jobID string, err error)
+}
+
+var _ client.SessionStore = (*SessionStore)(nil)
+
+// NewSessionStore wraps store so that completed jobs are archived. When
+// onError is nil, an archival failure is returned from SaveCheckpoint, and
+// so from RunJob; otherwise it is reported to onError and the job succeeds.
+// The checkpoint is saved to store either way.
+func NewSessionStore(store client.SessionStore, archiver *Archiver, onError func(jobID string, err error)) *SessionStore {
+	return &SessionStore{SessionStore: store, archiver: archiver, onError: onError}
+}
+
+// SaveCheckpoint implements client.SessionStore.
+func (s *SessionStore) SaveCheckpoint(ctx context.Context, checkpoint *client.JobCheckpoint) error {
+	if err := s.SessionStore.SaveCheckpoint(ctx, checkpoint); err != nil {
+		return err
+	}
+	if !checkpoint.Completed {
+		return nil
+	}
+
+	if _, err := s.archiver.Archive(ctx, FromCheckpoint(checkpoint)); err != nil {
+		if s.onError == nil {
+			return err
+		}
+		s.onError(checkpoint.JobID, err)
+	}
+	return nil
+}
@@ -0,0 +1,511 @@
+package claudemd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// FileName is the name of a shared memory file checked into a project
+	FileName = "CLAUDE.md"
+
+	// LocalFileName is the name of a personal, usually git-ignored, memory
+	// file in a project
+	LocalFileName = "CLAUDE.local.md"
+)
+
+// ErrConflict is returned by Save when the file was changed by someone else
+// since it was read.
+var ErrConflict = errors.New("memory file changed since it was read")
+
+// Scope tells where a memory file applies.
+type Scope string
+
+const (
+	// ScopeUser is the user's memory in ~/.claude, loaded for every project
+	ScopeUser Scope = "user"
+
+	// ScopeParent is a CLAUDE.md in a directory above the project
+	ScopeParent Scope = "parent"
+
+	// ScopeProject is the project's CLAUDE.md or .claude/CLAUDE.md
+	ScopeProject Scope = "project"
+
+	// ScopeLocal is the project's CLAUDE.local.md
+	ScopeLocal Scope = "local"
+)
+
+// Section is a Markdown heading and the text under it, up to the next
+// heading of any level.
+type Section struct {
+	// Title is the heading text without the leading #s
+	Title string
+
+	// Level is the heading level, 1 for "#" through 6
+	Level int
+
+	// Body is the text under the heading, including its trailing newline
+	Body string
+}
+
+// File is a parsed memory file. The zero File is empty and has no path.
+type File struct {
+	// Path is the file the memory was read from and is saved to
+	Path string
+
+	// Scope tells where the file applies, when it was found by Discover
+	Scope Scope
+
+	// Preamble is the text before the first heading
+	Preamble string
+
+	// Sections lists the headings in file order
+	Sections []Section
+
+	// exists and hash record the file as it was read, for conflict checks
+	exists bool
+	hash   [sha256.Size]byte
+}
+
+// Parse splits memory file content into its preamble and sections. Headings
+// inside fenced code blocks are part of the text.
+func Parse(content string) *File {
+	file := &File{}
+	var current *Section
+	inFence := false
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if level, title, ok := parseHeading(line); ok && !inFence {
+			file.Sections = append(file.Sections, Section{Title: title, Level: level})
+			current = &file.Sections[len(file.Sections)-1]
+			continue
+		}
+
+		if current == nil {
+			file.Preamble += line
+		} else {
+			current.Body += line
+		}
+	}
+	return file
+}
+
+// parseHeading parses an ATX heading line such as "## Testing".
+func parseHeading(line string) (int, string, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0, "", false
+	}
+	title := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#"))
+	return level, title, true
+}
+
+// Read reads and parses a memory file. A missing file reads as an empty
+// File with the given path, so that it can be created with Save.
+func Read(path string) (*File, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - the caller names the memory file
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{Path: path}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file := Parse(string(data))
+	file.Path = path
+	file.exists = true
+	file.hash = sha256.Sum256(data)
+	return file, nil
+}
+
+// String renders the file as Markdown.
+func (f *File) String() string {
+	var b strings.Builder
+	b.WriteString(f.Preamble)
+	for _, section := range f.Sections {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString(strings.Repeat("#", section.Level) + " " + section.Title + "\n")
+		b.WriteString(section.Body)
+	}
+	return b.String()
+}
+
+// Section returns the first section with the given title, compared case
+// insensitively, or nil.
+func (f *File) Section(title string) *Section {
+	for i := range f.Sections {
+		if strings.EqualFold(f.Sections[i].Title, strings.TrimSpace(title)) {
+			return &f.Sections[i]
+		}
+	}
+	return nil
+}
+
+// SetSection replaces the body of the section with the given title, or
+// appends a new level-2 section.
+func (f *File) SetSection(title, body string) {
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	if section := f.Section(title); section != nil {
+		// Keep the blank line separating the section from the next one
+		if strings.HasSuffix(section.Body, "\n\n") && !strings.HasSuffix(body, "\n\n") {
+			body += "\n"
+		}
+		section.Body = body
+		return
+	}
+
+	// Separate the new section from the text before it
+	if last := f.lastText(); last != "" && !strings.HasSuffix(last, "\n\n") {
+		f.appendText("\n")
+	}
+	f.Sections = append(f.Sections, Section{Title: strings.TrimSpace(title), Level: 2, Body: body})
+}
+
+// RemoveSection removes the section with the given title and reports
+// whether there was one. Subsections under it are kept.
+func (f *File) RemoveSection(title string) bool {
+	for i := range f.Sections {
+		if strings.EqualFold(f.Sections[i].Title, strings.TrimSpace(title)) {
+			f.Sections = append(f.Sections[:i], f.Sections[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Instructions returns the bullet items of a section, without their "-" or
+// "*" markers.
+func (f *File) Instructions(title string) []string {
+	section := f.Section(title)
+	if section == nil {
+		return nil
+	}
+
+	var instructions []string
+	for _, line := range strings.Split(section.Body, "\n") {
+		if instruction, ok := bulletText(line); ok {
+			instructions = append(instructions, instruction)
+		}
+	}
+	return instructions
+}
+
+// AddInstruction adds a bullet to a section, creating the section if needed,
+// and reports whether it was added; an identical bullet is not repeated.
+func (f *File) AddInstruction(title, instruction string) bool {
+	instruction = strings.TrimSpace(instruction)
+	for _, existing := range f.Instructions(title) {
+		if existing == instruction {
+			return false
+		}
+	}
+
+	section := f.Section(title)
+	if section == nil {
+		f.SetSection(title, "")
+		section = f.Section(title)
+	}
+
+	// Add the bullet after the last one, or at the end of the body
+	lines := strings.SplitAfter(section.Body, "\n")
+	insert := len(lines)
+	for i, line := range lines {
+		if _, ok := bulletText(line); ok {
+			insert = i + 1
+		}
+	}
+	if insert == len(lines) {
+		for insert > 0 && strings.TrimSpace(lines[insert-1]) == "" {
+			insert--
+		}
+	}
+	if insert > 0 && !strings.HasSuffix(lines[insert-1], "\n") {
+		lines[insert-1] += "\n"
+	}
+
+	bullet := "- " + instruction + "\n"
+	lines = append(lines[:insert], append([]string{bullet}, lines[insert:]...)...)
+	section.Body = strings.Join(lines, "")
+	return true
+}
+
+// RemoveInstruction removes a bullet from a section and reports whether it
+// was there.
+func (f *File) RemoveInstruction(title, instruction string) bool {
+	section := f.Section(title)
+	if section == nil {
+		return false
+	}
+
+	instruction = strings.TrimSpace(instruction)
+	lines := strings.SplitAfter(section.Body, "\n")
+	for i, line := range lines {
+		if text, ok := bulletText(line); ok && text == instruction {
+			section.Body = strings.Join(append(lines[:i], lines[i+1:]...), "")
+			return true
+		}
+	}
+	return false
+}
+
+// bulletText returns the text of a top-level "-" or "*" list item.
+func bulletText(line string) (string, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	for _, marker := range []string{"- ", "* "} {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(line[len(marker):]), true
+		}
+	}
+	return "", false
+}
+
+// lastText returns the text the file currently ends with.
+func (f *File) lastText() string {
+	if len(f.Sections) == 0 {
+		return f.Preamble
+	}
+	return f.Sections[len(f.Sections)-1].Body
+}
+
+// appendText appends text to the end of the file.
+func (f *File) appendText(text string) {
+	if len(f.Sections) == 0 {
+		f.Preamble += text
+		return
+	}
+	f.Sections[len(f.Sections)-1].Body += text
+}
+
+// Save writes the file back to Path. It fails with ErrConflict if the file
+// was created, changed or deleted since Read, so that concurrent edits by a
+// person or another process are never overwritten. The file is replaced
+// atomically, and a later Save of the same File succeeds.
+func (f *File) Save() error {
+	if f.Path == "" {
+		return errors.New("memory file has no path")
+	}
+
+	current, err := os.ReadFile(f.Path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if f.exists {
+			return fmt.Errorf("%s: %w", f.Path, ErrConflict)
+		}
+	case err != nil:
+		return err
+	case !f.exists || sha256.Sum256(current) != f.hash:
+		return fmt.Errorf("%s: %w", f.Path, ErrConflict)
+	}
+
+	data := []byte(f.String())
+	if err := writeAtomic(f.Path, data); err != nil {
+		return err
+	}
+	f.exists = true
+	f.hash = sha256.Sum256(data)
+	return nil
+}
+
+// writeAtomic replaces path with data through a temporary file in the same
+// directory, keeping the existing file's permissions.
+func writeAtomic(path string, data []byte) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Ignore error, the file is gone after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// maxUpdateAttempts bounds the retries of Update after conflicts.
+const maxUpdateAttempts = 5
+
+// Update reads the memory file at path, applies update and saves the result,
+// creating the file if needed. When the file changes between the read and
+// the save, update is applied again to the new content. Nothing is written
+// if update fails or leaves the content unchanged.
+func Update(path string, update func(file *File) error) error {
+	for attempt := 0; ; attempt++ {
+		file, err := Read(path)
+		if err != nil {
+			return err
+		}
+		before := file.String()
+		if err := update(file); err != nil {
+			return err
+		}
+		if file.String() == before {
+			return nil
+		}
+
+		err = file.Save()
+		if !errors.Is(err, ErrConflict) || attempt+1 == maxUpdateAttempts {
+			return err
+		}
+	}
+}
+
+// Discover returns the memory files the claude CLI loads for a project in
+// dir, lowest precedence first: the user's ~/.claude/CLAUDE.md, the
+// CLAUDE.md files of the directories above dir, the project's CLAUDE.md and
+// .claude/CLAUDE.md, and CLAUDE.local.md. Files that do not exist are left
+// out.
+func Discover(dir string) ([]*File, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		path  string
+		scope Scope
+	}
+	var candidates []candidate
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, candidate{filepath.Join(home, ".claude", FileName), ScopeUser})
+	}
+
+	var parents []string
+	for parent := filepath.Dir(dir); parent != filepath.Dir(parent); parent = filepath.Dir(parent) {
+		parents = append([]string{parent}, parents...)
+	}
+	for _, parent := range parents {
+		candidates = append(candidates, candidate{filepath.Join(parent, FileName), ScopeParent})
+	}
+	candidates = append(candidates,
+		candidate{filepath.Join(dir, FileName), ScopeProject},
+		candidate{filepath.Join(dir, ".claude", FileName), ScopeProject},
+		candidate{filepath.Join(dir, LocalFileName), ScopeLocal},
+	)
+
+	var files []*File
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if seen[candidate.path] {
+			continue
+		}
+		seen[candidate.path] = true
+
+		if _, err := os.Stat(candidate.path); err != nil {
+			continue
+		}
+		file, err := Read(candidate.path)
+		if err != nil {
+			return nil, err
+		}
+		file.Scope = candidate.scope
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// Merge combines memory files, lowest precedence first, into one unsaved
+// File: preambles are concatenated, sections with the same title are joined
+// under the first one's heading, and repeated bullets are dropped.
+func Merge(files ...*File) *File {
+	merged := &File{}
+	for _, file := range files {
+		if preamble := strings.TrimSpace(file.Preamble); preamble != "" {
+			merged.Preamble = joinBlocks(merged.Preamble, preamble)
+		}
+
+		for _, section := range file.Sections {
+			existing := merged.Section(section.Title)
+			if existing == nil {
+				merged.Sections = append(merged.Sections, Section{Title: section.Title, Level: section.Level})
+				existing = &merged.Sections[len(merged.Sections)-1]
+			}
+			existing.Body = joinBlocks(existing.Body, dropRepeatedBullets(existing.Body, section.Body))
+		}
+	}
+
+	// Separate sections with a blank line
+	if merged.Preamble != "" && len(merged.Sections) > 0 {
+		merged.Preamble += "\n"
+	}
+	for i := 0; i < len(merged.Sections)-1; i++ {
+		merged.Sections[i].Body += "\n"
+	}
+	return merged
+}
+
+// dropRepeatedBullets removes the bullets of body that existing already has.
+func dropRepeatedBullets(existing, body string) string {
+	have := map[string]bool{}
+	for _, line := range strings.Split(existing, "\n") {
+		if text, ok := bulletText(line); ok {
+			have[text] = true
+		}
+	}
+
+	var kept bytes.Buffer
+	for _, line := range strings.SplitAfter(body, "\n") {
+		if text, ok := bulletText(line); ok && have[text] {
+			continue
+		}
+		kept.WriteString(line)
+	}
+	return kept.String()
+}
+
+// joinBlocks joins two blocks of text with a blank line, or a line break
+// between two parts of a list, trimming blank lines around them and ending
+// with a newline.
+func joinBlocks(a, b string) string {
+	a, b = strings.Trim(a, "\n"), strings.Trim(b, "\n")
+	switch {
+	case a == "" && b == "":
+		return ""
+	case a == "":
+		return b + "\n"
+	case b == "":
+		return a + "\n"
+	}
+
+	separator := "\n\n"
+	_, aList := bulletText(a[strings.LastIndex(a, "\n")+1:])
+	if _, bList := bulletText(b); aList && bList {
+		separator = "\n"
+	}
+	return a + separator + b + "\n"
+}
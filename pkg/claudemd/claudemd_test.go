@@ -0,0 +1,202 @@
+package claudemd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const sample = `# Project memory
+
+Shared instructions for this repository.
+
+## Testing
+
+- Run make test
+- Keep tests table-driven
+
+## Style
+Use gofmt.
+
+` + "```sh\n# not a heading\n```\n"
+
+func TestParse_RoundTrip(t *testing.T) {
+	file := Parse(sample)
+	if got := file.String(); got != sample {
+		t.Errorf("Expected an unchanged round trip, got:\n%s", got)
+	}
+
+	var titles []string
+	for _, section := range file.Sections {
+		titles = append(titles, section.Title)
+	}
+	if want := []string{"Project memory", "Testing", "Style"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("Expected sections %v, got %v", want, titles)
+	}
+	if body := file.Section("style").Body; body != "Use gofmt.\n\n```sh\n# not a heading\n```\n" {
+		t.Errorf("Expected the fenced heading in the Style body, got %q", body)
+	}
+}
+
+func TestFile_Instructions(t *testing.T) {
+	file := Parse(sample)
+
+	if !file.AddInstruction("Testing", "Run go test -race ./...") || file.AddInstruction("testing", "Keep tests table-driven") {
+		t.Error("Expected only the new instruction to be added")
+	}
+	if !file.RemoveInstruction("Testing", "Run make test") || file.RemoveInstruction("Testing", "Run make test") {
+		t.Error("Expected the instruction to be removed once")
+	}
+	if got, want := file.Instructions("Testing"), []string{"Keep tests table-driven", "Run go test -race ./..."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected instructions %v, got %v", want, got)
+	}
+
+	file.AddInstruction("Security", "Never commit secrets")
+	file.SetSection("Style", "Use gofumpt.")
+	if !file.RemoveSection("project memory") || file.RemoveSection("Missing") {
+		t.Error("Expected RemoveSection to report what it removed")
+	}
+
+	want := `## Testing
+
+- Keep tests table-driven
+- Run go test -race ./...
+
+## Style
+Use gofumpt.
+
+## Security
+- Never commit secrets
+`
+	if got := file.String(); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	err := Update(path, func(file *File) error {
+		file.AddInstruction("Testing", "Run go test ./...")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "## Testing\n- Run go test ./...\n" {
+		t.Errorf("Expected the file to be created, got %q", data)
+	}
+
+	// A concurrent edit between the read and the save is applied again
+	calls := 0
+	err = Update(path, func(file *File) error {
+		calls++
+		if calls == 1 {
+			if err := os.WriteFile(path, []byte("Edited by hand.\n\n## Testing\n- Run go test ./...\n"), 0o644); err != nil {
+				t.Fatalf("Failed to edit file: %v", err)
+			}
+		}
+		file.AddInstruction("Testing", "Use -race")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the update to be retried once, got %d calls", calls)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "Edited by hand.\n\n## Testing\n- Run go test ./...\n- Use -race\n" {
+		t.Errorf("Expected the hand edit to be kept, got %q", data)
+	}
+
+	failure := errors.New("no change")
+	if err := Update(path, func(file *File) error { return failure }); !errors.Is(err, failure) {
+		t.Errorf("Expected the update error, got %v", err)
+	}
+}
+
+func TestFile_SaveConflict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("## Notes\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	file, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("## Notes\n- changed elsewhere\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	file.AddInstruction("Notes", "mine")
+	if err := file.Save(); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected ErrConflict, got %v", err)
+	}
+
+	file, _ = Read(path)
+	file.AddInstruction("Notes", "mine")
+	if err := file.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	file.AddInstruction("Notes", "again")
+	if err := file.Save(); err != nil {
+		t.Errorf("Expected a second save of the same file to succeed, got %v", err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected permissions to be kept, got %v", info.Mode().Perm())
+	}
+}
+
+func TestDiscoverAndMerge(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	root := t.TempDir()
+	project := filepath.Join(root, "service")
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	write(filepath.Join(home, ".claude", FileName), "## Style\n- Prefer short functions\n")
+	write(filepath.Join(root, FileName), "Monorepo conventions.\n\n## Testing\n- Run make test\n")
+	write(filepath.Join(project, FileName), "## Testing\n- Run make test\n- Use testcontainers\n")
+	write(filepath.Join(project, LocalFileName), "## Style\n- Use my editor settings\n")
+
+	files, err := Discover(project)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	var scopes []Scope
+	for _, file := range files {
+		scopes = append(scopes, file.Scope)
+	}
+	if want := []Scope{ScopeUser, ScopeParent, ScopeProject, ScopeLocal}; !reflect.DeepEqual(scopes, want) {
+		t.Fatalf("Expected scopes %v, got %v", want, scopes)
+	}
+
+	merged := Merge(files...)
+	want := `Monorepo conventions.
+
+## Style
+- Prefer short functions
+- Use my editor settings
+
+## Testing
+- Run make test
+- Use testcontainers
+`
+	if got := merged.String(); got != want {
+		t.Errorf("Expected merged memory:\n%s\ngot:\n%s", want, got)
+	}
+	if err := merged.Save(); err == nil {
+		t.Error("Expected a merged file without a path not to be saved")
+	}
+}
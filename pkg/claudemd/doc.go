@@ -0,0 +1,41 @@
+/*
+Package claudemd reads, merges and updates the CLAUDE.md memory files the
+claude CLI loads into every conversation, so that automation can manage
+agent instructions alongside the code they describe.
+
+A memory file is Markdown: free text followed by sections under headings.
+Parse and Read split a file into its preamble and Sections, and File offers
+edits by section title and by bullet, leaving the rest of the text as it was
+written.
+
+# Updating Instructions
+
+Update reads a file, applies a change and saves it, creating the file if
+needed:
+
+	err := claudemd.Update("CLAUDE.md", func(file *claudemd.File) error {
+		file.AddInstruction("Testing", "Run go test -race ./... before committing")
+		file.RemoveInstruction("Testing", "Run make test")
+		file.SetSection("Generated Code", "Never edit files under gen/; run go generate instead.")
+		return nil
+	})
+
+Writes are conflict-safe: Save fails with ErrConflict when the file was
+changed since it was read, rather than overwriting someone's edit, and
+replaces the file atomically. Update retries by applying the change again to
+the new content.
+
+# Reading Merged Memory
+
+Discover finds the memory files the CLI loads for a project, lowest
+precedence first (user, parent directories, project, local), and Merge
+combines them into one view with repeated bullets removed:
+
+	files, err := claudemd.Discover(projectDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	memory := claudemd.Merge(files...)
+	fmt.Println(memory.Instructions("Testing"))
+*/
+package claudemd
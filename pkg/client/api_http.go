@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// apiBaseURL returns the Anthropic API base URL the CLI would use:
+// ANTHROPIC_BASE_URL from the client environment, the gateway's base URL,
+// ANTHROPIC_BASE_URL from the process environment, or the default.
+func (c *ClaudeCodeClient) apiBaseURL() string {
+	config := c.settings()
+	if baseURL := config.Environment["ANTHROPIC_BASE_URL"]; baseURL != "" {
+		return strings.TrimRight(baseURL, "/")
+	}
+	if config.Gateway != nil && config.Gateway.BaseURL != "" {
+		return strings.TrimRight(config.Gateway.BaseURL, "/")
+	}
+	if baseURL := os.Getenv("ANTHROPIC_BASE_URL"); baseURL != "" {
+		return strings.TrimRight(baseURL, "/")
+	}
+	return anthropicAPIBaseURL
+}
+
+// hasAPICredentials reports whether requests made directly to the API, or
+// to the gateway, can be authenticated.
+func (c *ClaudeCodeClient) hasAPICredentials(ctx context.Context) bool {
+	gateway := c.settings().Gateway
+	return c.apiKey(ctx) != "" || (gateway != nil && gateway.AuthToken != "")
+}
+
+// setAPIHeaders sets the headers the CLI sends with API requests: the API
+// key, and the gateway's bearer token and custom headers.
+func (c *ClaudeCodeClient) setAPIHeaders(ctx context.Context, req *http.Request) {
+	req.Header.Set("anthropic-version", types.APIVersion)
+	if apiKey := c.apiKey(ctx); apiKey != "" {
+		req.Header.Set("x-api-key", apiKey)
+	}
+
+	gateway := c.settings().Gateway
+	if gateway == nil {
+		return
+	}
+	if gateway.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+gateway.AuthToken)
+	}
+	for name, value := range gateway.Headers {
+		req.Header.Set(name, value)
+	}
+}
+
+// apiHTTPClient returns an HTTP client for direct API requests that routes
+// and trusts connections as the CLI does: through the configured proxy, or
+// the proxy variables of the process environment, with the gateway's client
+// certificate and the CA bundle or pinned certificates configured for the
+// gateway or the client. The caller closes its idle connections when done.
+func (c *ClaudeCodeClient) apiHTTPClient() (*http.Client, error) {
+	config := c.settings()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxy := config.Proxy; proxy != nil {
+		proxyURL := proxy.URLWithCredentials()
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  proxyURL,
+			HTTPSProxy: proxyURL,
+			NoProxy:    strings.Join(proxy.NoProxy, ","),
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	tlsConfig, err := apiTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// apiTLSConfig builds the TLS settings of the gateway and certificate trust
+// configuration, or returns nil if there are none.
+func apiTLSConfig(config *types.ClaudeCodeConfig) (*tls.Config, error) {
+	var gatewayTLS *types.TLSConfig
+	if config.Gateway != nil {
+		gatewayTLS = config.Gateway.TLS
+	}
+	trust := config.CertificateTrust
+	if gatewayTLS == nil && trust == nil {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	var extraCAs []string
+	if gatewayTLS != nil {
+		tlsConfig.InsecureSkipVerify = gatewayTLS.InsecureSkipVerify // #nosec G402 - opted into by the gateway configuration
+		if gatewayTLS.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(gatewayTLS.CertFile, gatewayTLS.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		if gatewayTLS.CAFile != "" {
+			extraCAs = append(extraCAs, gatewayTLS.CAFile)
+		}
+	}
+
+	switch {
+	case trust != nil && trust.PinnedCertsFile != "":
+		// Pinned certificates replace the system roots
+		certs, err := types.LoadCertificates(trust.PinnedCertsFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		for _, cert := range certs {
+			tlsConfig.RootCAs.AddCert(cert)
+		}
+		return tlsConfig, nil
+	case trust != nil && trust.CAFile != "":
+		extraCAs = append(extraCAs, trust.CAFile)
+	}
+
+	if len(extraCAs) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, path := range extraCAs {
+			certs, err := types.LoadCertificates(path)
+			if err != nil {
+				return nil, err
+			}
+			for _, cert := range certs {
+				pool.AddCert(cert)
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// hookApproval names the approval hook.
+const hookApproval = "approval"
+
+// defaultApprovalTimeout bounds the wait for a decision when ApprovalTimeout is unset.
+const defaultApprovalTimeout = 10 * time.Minute
+
+// approvalTokenEnv is the variable through which the approval hook receives
+// its token. The hook configuration is on the command line of the CLI and
+// the hook, so it holds only the server's URL.
+const approvalTokenEnv = "CLAUDE_SDK_APPROVAL_TOKEN"
+
+// approvalHook is the configuration of the approval hook: where the client's
+// approval server listens and the token that authenticates the hook, which
+// is passed in approvalTokenEnv rather than with the configuration.
+type approvalHook struct {
+	URL   string `json:"url"`
+	Token string `json:"-"`
+}
+
+// approvalServer receives approval requests from hook processes on loopback
+// and answers them with the client's ApprovalProvider.
+type approvalServer struct {
+	server *http.Server
+	hook   approvalHook
+}
+
+// approvalHookConfig starts the approval server on first use and returns the
+// hook configuration, or nil when no ApprovalProvider is set.
+func (c *ClaudeCodeClient) approvalHookConfig() (*approvalHook, error) {
+	if c.settings().ApprovalProvider == nil {
+		return nil, nil
+	}
+
+	c.approvalMu.Lock()
+	defer c.approvalMu.Unlock()
+
+	if c.approvals != nil {
+		return &c.approvals.hook, nil
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "APPROVAL_SERVER", "failed to generate approval token")
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "APPROVAL_SERVER", "failed to start approval server")
+	}
+
+	approvals := &approvalServer{
+		hook: approvalHook{URL: "http://" + listener.Addr().String() + "/approve", Token: hex.EncodeToString(token)},
+	}
+	approvals.server = &http.Server{
+		Handler:           http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { c.serveApproval(approvals.hook.Token, w, r) }),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = approvals.server.Serve(listener) }() // Serve returns when the server is closed
+
+	c.approvals = approvals
+	return &approvals.hook, nil
+}
+
+// approvalToken returns the token of the approval server, or "" if it has
+// not been started.
+func (c *ClaudeCodeClient) approvalToken() string {
+	c.approvalMu.Lock()
+	defer c.approvalMu.Unlock()
+
+	if c.approvals == nil {
+		return ""
+	}
+	return c.approvals.hook.Token
+}
+
+// serveApproval answers one approval request from a hook process.
+func (c *ClaudeCodeClient) serveApproval(token string, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var request types.ApprovalRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&request); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	request.Kind = types.ApprovalKindTool
+
+	config := c.settings()
+	timeout := config.ApprovalTimeout
+	if timeout <= 0 {
+		timeout = defaultApprovalTimeout
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	decision, err := config.ApprovalProvider.Approve(ctx, request)
+	if err != nil {
+		decision = types.ApprovalDecision{Reason: "approval failed: " + err.Error()}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(decision) // Ignore error, the hook denies on a broken response
+}
+
+// stopApprovals shuts down the approval server, if it was started.
+func (c *ClaudeCodeClient) stopApprovals() {
+	c.approvalMu.Lock()
+	defer c.approvalMu.Unlock()
+
+	if c.approvals != nil {
+		_ = c.approvals.server.Close() // Ignore error, pending hooks deny on a failed request
+		c.approvals = nil
+	}
+}
+
+// approvalHookEvent is the input of the approval hook.
+type approvalHookEvent struct {
+	SessionID string         `json:"session_id"`
+	CWD       string         `json:"cwd"`
+	ToolName  string         `json:"tool_name"`
+	ToolInput map[string]any `json:"tool_input"`
+}
+
+// runApprovalHook reads a PreToolUse hook event, asks the client's approval
+// server for a decision, and allows or denies the tool call.
+func runApprovalHook(config []byte, input io.Reader, output io.Writer) error {
+	var hook approvalHook
+	if err := json.Unmarshal(config, &hook); err != nil {
+		return fmt.Errorf("invalid hook configuration: %w", err)
+	}
+	if hook.Token = os.Getenv(approvalTokenEnv); hook.Token == "" {
+		return fmt.Errorf("%s is not set", approvalTokenEnv)
+	}
+
+	var event approvalHookEvent
+	if err := json.NewDecoder(input).Decode(&event); err != nil {
+		return fmt.Errorf("invalid hook input: %w", err)
+	}
+
+	body, err := json.Marshal(types.ApprovalRequest{
+		Kind:       types.ApprovalKindTool,
+		ToolName:   event.ToolName,
+		ToolInput:  event.ToolInput,
+		SessionID:  event.SessionID,
+		WorkingDir: event.CWD,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+hook.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("approval server unavailable: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }() // Ignore error, the body has been read
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("approval server returned %s", resp.Status)
+	}
+
+	var decision types.ApprovalDecision
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return fmt.Errorf("invalid approval response: %w", err)
+	}
+
+	if !decision.Approved {
+		reason := decision.Reason
+		if reason == "" {
+			reason = "the tool call was not approved"
+		}
+		return denyToolUse(output, reason)
+	}
+	return json.NewEncoder(output).Encode(map[string]any{
+		"hookSpecificOutput": map[string]any{
+			"hookEventName":            "PreToolUse",
+			"permissionDecision":       "allow",
+			"permissionDecisionReason": "approved",
+		},
+	})
+}
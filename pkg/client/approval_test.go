@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestApprovalHook(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	var requests []types.ApprovalRequest
+	client.config.ApprovalProvider = types.ApprovalFunc(func(_ context.Context, request types.ApprovalRequest) (types.ApprovalDecision, error) {
+		requests = append(requests, request)
+		if strings.Contains(request.Summary(), "prod") {
+			return types.ApprovalDecision{Reason: "production is frozen"}, nil
+		}
+		return types.ApprovalDecision{Approved: true}, nil
+	})
+	client.config.ApprovalTimeout = time.Minute
+
+	args, err := client.hookArgs(client.currentWorkingDir())
+	if err != nil {
+		t.Fatalf("hookArgs failed: %v", err)
+	}
+	var settings struct {
+		Hooks struct {
+			PreToolUse []struct {
+				Matcher string `json:"matcher"`
+				Hooks   []struct {
+					Command string `json:"command"`
+					Timeout int    `json:"timeout"`
+				} `json:"hooks"`
+			} `json:"PreToolUse"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal([]byte(args[1]), &settings); err != nil || len(settings.Hooks.PreToolUse) != 1 {
+		t.Fatalf("Unexpected settings %q: %v", args[1], err)
+	}
+	matcher := settings.Hooks.PreToolUse[0]
+	if matcher.Matcher != types.DefaultApprovalTools || matcher.Hooks[0].Timeout != 90 {
+		t.Errorf("Unexpected matcher %+v", matcher)
+	}
+
+	// The token is passed in the CLI's environment, never on a command line
+	token := client.approvals.hook.Token
+	if strings.Contains(strings.Join(args, " "), token) {
+		t.Fatalf("Expected the approval token to stay off the command line, got %q", args)
+	}
+	env := client.buildEnvironment()
+	if !containsString(env, approvalTokenEnv+"="+token) {
+		t.Fatalf("Expected %s in the CLI environment, got %v", approvalTokenEnv, env)
+	}
+
+	runHook := func(input string) string {
+		hook := exec.Command("sh", "-c", matcher.Hooks[0].Command) // #nosec G204 - hook command built by the test
+		hook.Env = append(os.Environ(), env...)
+		hook.Stdin = strings.NewReader(input)
+		output, err := hook.Output()
+		if err != nil {
+			t.Fatalf("Hook failed: %v", err)
+		}
+		return string(output)
+	}
+
+	output := runHook(`{"session_id":"s1","cwd":"/repo","tool_name":"Edit","tool_input":{"file_path":"/repo/main.go"}}`)
+	if !strings.Contains(output, `"permissionDecision":"allow"`) {
+		t.Errorf("Expected approval, got %s", output)
+	}
+	output = runHook(`{"tool_name":"Bash","tool_input":{"command":"kubectl --context prod apply"}}`)
+	if !strings.Contains(output, `"permissionDecision":"deny"`) || !strings.Contains(output, "production is frozen") {
+		t.Errorf("Expected denial, got %s", output)
+	}
+
+	if len(requests) != 2 || requests[0].SessionID != "s1" || requests[0].WorkingDir != "/repo" || requests[0].Kind != types.ApprovalKindTool {
+		t.Errorf("Unexpected requests %+v", requests)
+	}
+
+	// The hook fails without the token, which blocks the tool call
+	hook := exec.Command("sh", "-c", matcher.Hooks[0].Command) // #nosec G204 - hook command built by the test
+	hook.Stdin = strings.NewReader(`{"tool_name":"Edit","tool_input":{"file_path":"a"}}`)
+	if err := hook.Run(); err == nil {
+		t.Error("Expected the hook to fail without its token")
+	}
+
+	// The server rejects requests without the hook's token
+	resp, err := http.Post(client.approvals.hook.URL, "application/json", bytes.NewReader([]byte(`{}`)))
+	if err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 without a token, got %s", resp.Status)
+	}
+
+	// Closing the client stops the server, so hooks deny by failing
+	client.Close()
+	hook = exec.Command("sh", "-c", matcher.Hooks[0].Command) // #nosec G204 - hook command built by the test
+	hook.Env = append(os.Environ(), env...)
+	hook.Stdin = strings.NewReader(`{"tool_name":"Edit","tool_input":{"file_path":"a"}}`)
+	if err := hook.Run(); err == nil {
+		t.Error("Expected the hook to fail after Close")
+	} else if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 2 {
+		t.Errorf("Expected exit code 2, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// attachmentArgs make the CLI read the prompt from stdin as a stream-json
+// user message, which is how content blocks such as images are sent.
+var attachmentArgs = []string{"--input-format", "stream-json", "--output-format", "stream-json", "--verbose"}
+
+// promptOnStdin reports whether the prompt of request is written to stdin
+// rather than passed as an argument: when its messages carry attachments,
+// and for streaming queries when the control protocol is enabled.
+func (c *ClaudeCodeClient) promptOnStdin(request *types.QueryRequest, streaming bool) bool {
+	return types.HasAttachments(request.Messages) || (streaming && c.settings().ControlProtocol)
+}
+
+// stdinInput returns the stream-json stdin for a request whose prompt is
+// written to stdin, or nil when the prompt is passed as an argument. Any
+// attachments precede the prompt text in a single user message, and
+// relative attachment paths are resolved against workingDir.
+func (c *ClaudeCodeClient) stdinInput(request *types.QueryRequest, streaming bool, workingDir string) ([]byte, error) {
+	if !c.promptOnStdin(request, streaming) {
+		return nil, nil
+	}
+
+	var content []types.ContentBlock
+	for _, msg := range request.Messages {
+		for i := range msg.Attachments {
+			blocks, err := msg.Attachments[i].ContentBlocks(workingDir)
+			if err != nil {
+				return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "ATTACHMENT", "failed to load attachment")
+			}
+			for _, block := range blocks {
+				switch {
+				case block.Type == "text":
+					block.Text = c.redact(block.Text)
+				case block.Source != nil && block.Source.Type == types.ContentSourceText:
+					block.Source.Data = c.redact(block.Source.Data)
+				}
+				content = append(content, block)
+			}
+		}
+	}
+
+	prompt, err := c.messagesToPrompt(request.Messages)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "MESSAGE_CONVERSION", "failed to convert messages to prompt")
+	}
+	if prompt != "" {
+		content = append(content, types.NewTextBlock(c.redact(prompt)))
+	}
+
+	line, err := json.Marshal(map[string]any{
+		"type": "user",
+		"message": map[string]any{
+			"role":    types.RoleUser,
+			"content": content,
+		},
+	})
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JSON_MARSHAL", "failed to encode attachments")
+	}
+	return append(line, '\n'), nil
+}
+
+// parseStreamJSONOutput builds the response from the result line of
+// stream-json output.
+func parseStreamJSONOutput(output []byte) (*types.QueryResponse, error) {
+	var model string
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxCLILineSize)
+	for scanner.Scan() {
+		var line jobStreamLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if line.Message != nil && line.Message.Model != "" {
+			model = line.Message.Model
+		}
+		if line.Type != "result" {
+			continue
+		}
+		if line.IsError {
+			return nil, sdkerrors.NewInternalError("CLAUDE_EXECUTION", "query ended with an error result: "+line.Result)
+		}
+		return &types.QueryResponse{
+			Type:       "message",
+			Role:       types.RoleAssistant,
+			Content:    []types.ContentBlock{types.NewTextBlock(line.Result)},
+			Model:      model,
+			StopReason: "end_turn",
+			Usage:      line.Usage,
+			CreatedAt:  time.Now(),
+		}, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, sdkerrors.NewInternalError("CLAUDE_EXECUTION", "query ended without a result")
+}
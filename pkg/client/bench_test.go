@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Run with: go test -run '^$' -bench . -benchmem ./pkg/client
+//
+// The benchmarks cover the SDK's own overhead: parsing CLI output, moving
+// messages through channels, serializing options into CLI flags and starting
+// a subprocess. BenchmarkCLIStartup in tests/integration measures the real
+// CLI.
+
+// newBenchClient creates a test-mode client, optionally running script as
+// its CLI.
+func newBenchClient(b *testing.B, script string) *ClaudeCodeClient {
+	b.Helper()
+
+	dir := b.TempDir()
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: dir,
+		Model:            "claude-sonnet-4",
+	})
+	if err != nil {
+		b.Fatalf("Failed to create client: %v", err)
+	}
+	b.Cleanup(func() { client.Close() })
+
+	if script != "" {
+		if runtime.GOOS == "windows" {
+			b.Skip("fake CLI benchmarks rely on a POSIX shell")
+		}
+		cliPath := filepath.Join(dir, "claude")
+		if err := os.WriteFile(cliPath, []byte("#!/bin/sh\n"+script+"\n"), 0700); err != nil {
+			b.Fatalf("Failed to write fake CLI: %v", err)
+		}
+		client.claudeCodeCmd = cliPath
+	}
+	return client
+}
+
+// streamTranscript returns a stream-json transcript with the given number of
+// assistant turns, each using a tool.
+func streamTranscript(turns int) string {
+	var out strings.Builder
+	out.WriteString(`{"type":"system","subtype":"init","session_id":"bench","model":"claude-sonnet-4"}` + "\n")
+	for i := 0; i < turns; i++ {
+		fmt.Fprintf(&out, `{"type":"assistant","message":{"model":"claude-sonnet-4","content":[{"type":"text","text":"Reading file %d to understand the package layout."},{"type":"tool_use","id":"toolu_%d","name":"Read","input":{"file_path":"pkg/file%d.go"}}],"usage":{"input_tokens":1200,"output_tokens":80}}}`+"\n", i, i, i)
+		fmt.Fprintf(&out, `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_%d","content":"package pkg\n\nfunc F%d() {}\n"}]}}`+"\n", i, i)
+	}
+	out.WriteString(`{"type":"result","subtype":"success","result":"Done.","num_turns":10,"total_cost_usd":0.05,"usage":{"input_tokens":12000,"output_tokens":800}}` + "\n")
+	return out.String()
+}
+
+func BenchmarkParseStreamJSONOutput(b *testing.B) {
+	transcript := streamTranscript(10)
+	b.SetBytes(int64(len(transcript)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := parseStreamJSONOutput([]byte(transcript)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseStreamingOutput_Channel(b *testing.B) {
+	client := newBenchClient(b, "")
+
+	var out strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&out, "Claude: Step %d of the refactoring plan.\nTool: Read(pkg/file%d.go)\nResult: package pkg\n", i, i)
+	}
+	output := out.String()
+	b.SetBytes(int64(len(output)))
+	b.ReportAllocs()
+
+	messages := 0
+	for i := 0; i < b.N; i++ {
+		messageChan := make(chan *types.Message, 100)
+		done := make(chan int)
+		go func() {
+			count := 0
+			for range messageChan {
+				count++
+			}
+			done <- count
+		}()
+
+		client.parseStreamingOutput(strings.NewReader(output), messageChan, &QueryOptions{}, nil)
+		close(messageChan)
+		messages += <-done
+	}
+	b.ReportMetric(float64(messages)/b.Elapsed().Seconds(), "msgs/s")
+}
+
+func BenchmarkBuildClaudeArgs(b *testing.B) {
+	client := newBenchClient(b, "")
+	request := &types.QueryRequest{
+		Model:    "claude-sonnet-4",
+		System:   "Answer in English.",
+		Messages: []types.Message{{Role: types.RoleUser, Content: "Refactor the parser to stream its input"}},
+	}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.buildClaudeArgsForSession(request, true, "bench-session"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBuildQueryCommand(b *testing.B) {
+	client := newBenchClient(b, "")
+	session := &ClaudeCodeSession{ID: "bench-session", client: client}
+	options := &QueryOptions{
+		SystemPrompt:   "You are a careful Go developer.",
+		MaxTurns:       10,
+		AllowedTools:   []string{"Read", "Edit", "Bash(go test:*)"},
+		PermissionMode: PermissionModeAcceptEdits,
+		Model:          "claude-sonnet-4",
+		Stream:         true,
+	}
+	cmd := &types.Command{
+		Type:    types.CommandType("chat"),
+		Args:    []string{"Refactor the parser to stream its input"},
+		Options: client.convertQueryOptionsToCommandOptions(options),
+	}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.buildQueryCommand(session, cmd, options); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQueryStream_SubprocessStartup(b *testing.B) {
+	client := newBenchClient(b, `echo '{"type":"result","subtype":"success","result":"ok"}'`)
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "ping"}}}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		stream, err := client.QueryStream(context.Background(), request)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for {
+			chunk, err := stream.Recv()
+			if err != nil {
+				b.Fatal(err)
+			}
+			if chunk.Done {
+				break
+			}
+		}
+		_ = stream.Close()
+	}
+}
+
+// largeReadTranscript writes a stream-json transcript whose Read tool result
+// is size bytes, carried both in the tool result and in tool_use_result as
+// the CLI does, and returns its path.
+func largeReadTranscript(b *testing.B, size int) string {
+	b.Helper()
+
+	content := strings.Repeat("0123456789abcdef\\n", size/18)
+	var out strings.Builder
+	out.Grow(2*len(content) + 1024)
+	out.WriteString(`{"type":"system","subtype":"init","session_id":"bench","model":"claude-sonnet-4"}` + "\n")
+	out.WriteString(`{"type":"assistant","message":{"model":"claude-sonnet-4","content":[{"type":"tool_use","id":"toolu_1","name":"Read","input":{"file_path":"dump.log"}}]}}` + "\n")
+	out.WriteString(`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"` + content + `"}]},"tool_use_result":{"file":{"content":"` + content + `"}}}` + "\n")
+	out.WriteString(`{"type":"assistant","message":{"model":"claude-sonnet-4","content":[{"type":"text","text":"The log is large."}]}}` + "\n")
+	out.WriteString(`{"type":"result","subtype":"success","result":"The log is large.","num_turns":2,"usage":{"input_tokens":12000,"output_tokens":800}}` + "\n")
+
+	path := filepath.Join(b.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(out.String()), 0600); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+// BenchmarkQueryStream_LargeReadResult streams a 100MB Read result with file
+// access events and tool timeouts enabled, so every observer sees the line.
+//
+// With bufio.Scanner and string observers (1 CPU):
+//
+//	33523507946 ns/op	   6.26 MB/s	2103919797 B/op	     189 allocs/op
+//
+// With lineReader and byte observers:
+//
+//	2296235936 ns/op	  91.33 MB/s	 744981216 B/op	     165 allocs/op
+//
+// Sharing the line with the chunk's content:
+//
+//	2938793193 ns/op	  71.36 MB/s	 535333576 B/op	     647 allocs/op
+func BenchmarkQueryStream_LargeReadResult(b *testing.B) {
+	if testing.Short() {
+		b.Skip("writes a 100MB transcript")
+	}
+	path := largeReadTranscript(b, 100<<20)
+	client := newBenchClient(b, "cat "+path)
+	client.config.OnFileAccess = func(types.FileAccessEvent) {}
+	client.config.ToolTimeouts = map[string]time.Duration{"*": time.Hour}
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "read dump.log"}}}
+	b.SetBytes(200 << 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		stream, err := client.QueryStream(context.Background(), request)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for {
+			chunk, err := stream.Recv()
+			if err != nil {
+				b.Fatal(err)
+			}
+			if chunk.Done {
+				break
+			}
+		}
+		_ = stream.Close()
+	}
+}
+
+// BenchmarkRunJob_LargeReadResult runs a job whose transcript holds a 100MB
+// Read result.
+//
+// With bufio.Scanner and string observers (1 CPU):
+//
+//	41995048077 ns/op	   4.99 MB/s	2006336264 B/op	     197 allocs/op
+//
+// With lineReader and byte observers:
+//
+//	2237995137 ns/op	  93.71 MB/s	1097423709 B/op	     172 allocs/op
+func BenchmarkRunJob_LargeReadResult(b *testing.B) {
+	if testing.Short() {
+		b.Skip("writes a 100MB transcript")
+	}
+	path := largeReadTranscript(b, 100<<20)
+	client := newBenchClient(b, "cat "+path)
+	client.config.OnFileAccess = func(types.FileAccessEvent) {}
+	client.SetSessionStore(NewMemorySessionStore())
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "read dump.log"}}}
+	b.SetBytes(200 << 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.RunJob(context.Background(), fmt.Sprintf("job-%d", i), request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
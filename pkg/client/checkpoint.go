@@ -0,0 +1,651 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// ErrCheckpointNotFound is returned when a job has no checkpoint.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// DefaultCheckpointInterval is the minimum time between checkpoints of a running job.
+const DefaultCheckpointInterval = 10 * time.Second
+
+// JobResumePrompt is sent when a job is resumed after an interruption.
+const JobResumePrompt = "The previous run was interrupted. Continue the task from where you left off."
+
+// JobCheckpoint is the persisted progress of a long-running query.
+type JobCheckpoint struct {
+	// JobID identifies the job
+	JobID string `json:"job_id"`
+
+	// SessionID is the claude session the job runs in, used with --resume
+	SessionID string `json:"session_id"`
+
+	// Request is the original request
+	Request *types.QueryRequest `json:"request"`
+
+	// Turns is the number of assistant turns completed so far
+	Turns int `json:"turns"`
+
+	// Messages holds the conversation accumulated so far
+	Messages []types.Message `json:"messages,omitempty"`
+
+	// Metadata is the query metadata the job was started with, restored
+	// when it is resumed
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Completed reports whether the job finished
+	Completed bool `json:"completed"`
+
+	// Response is the final response of a completed job
+	Response *types.QueryResponse `json:"response,omitempty"`
+
+	// Error is the last error the job stopped with
+	Error string `json:"error,omitempty"`
+
+	// CreatedAt is when the job started
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the checkpoint was last saved
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SessionStore persists job checkpoints. Implementations must be safe for
+// concurrent use.
+type SessionStore interface {
+	// SaveCheckpoint creates or replaces a checkpoint
+	SaveCheckpoint(ctx context.Context, checkpoint *JobCheckpoint) error
+
+	// LoadCheckpoint returns a checkpoint, or ErrCheckpointNotFound
+	LoadCheckpoint(ctx context.Context, jobID string) (*JobCheckpoint, error)
+
+	// ListCheckpoints returns all checkpoints ordered by creation time
+	ListCheckpoints(ctx context.Context) ([]*JobCheckpoint, error)
+
+	// DeleteCheckpoint removes a checkpoint. Deleting a missing checkpoint is not an error.
+	DeleteCheckpoint(ctx context.Context, jobID string) error
+}
+
+// MemorySessionStore keeps checkpoints in memory. Checkpoints do not survive a restart.
+type MemorySessionStore struct {
+	checkpoints map[string][]byte
+	mu          sync.RWMutex
+}
+
+// NewMemorySessionStore creates an empty in-memory store.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{checkpoints: make(map[string][]byte)}
+}
+
+// SaveCheckpoint implements SessionStore.
+func (s *MemorySessionStore) SaveCheckpoint(ctx context.Context, checkpoint *JobCheckpoint) error {
+	// Store an encoded copy so later changes by the caller are not shared
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_ENCODE", "failed to encode checkpoint")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[checkpoint.JobID] = data
+	return nil
+}
+
+// LoadCheckpoint implements SessionStore.
+func (s *MemorySessionStore) LoadCheckpoint(ctx context.Context, jobID string) (*JobCheckpoint, error) {
+	s.mu.RLock()
+	data, ok := s.checkpoints[jobID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrCheckpointNotFound
+	}
+	return decodeCheckpoint(data, jobID)
+}
+
+// ListCheckpoints implements SessionStore.
+func (s *MemorySessionStore) ListCheckpoints(ctx context.Context) ([]*JobCheckpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	checkpoints := make([]*JobCheckpoint, 0, len(s.checkpoints))
+	for jobID, data := range s.checkpoints {
+		checkpoint, err := decodeCheckpoint(data, jobID)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	sortCheckpoints(checkpoints)
+	return checkpoints, nil
+}
+
+// DeleteCheckpoint implements SessionStore.
+func (s *MemorySessionStore) DeleteCheckpoint(ctx context.Context, jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.checkpoints, jobID)
+	return nil
+}
+
+// FileSessionStore keeps each checkpoint as a JSON file in a directory, so
+// jobs can be resumed after the host process restarts.
+type FileSessionStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileSessionStore creates a store in dir, creating the directory if needed.
+func NewFileSessionStore(dir string) (*FileSessionStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "CHECKPOINT_DIR", "failed to create checkpoint directory")
+	}
+	return &FileSessionStore{dir: dir}, nil
+}
+
+// SaveCheckpoint implements SessionStore. Files are replaced atomically.
+func (s *FileSessionStore) SaveCheckpoint(ctx context.Context, checkpoint *JobCheckpoint) error {
+	path, err := s.path(checkpoint.JobID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_ENCODE", "failed to encode checkpoint")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, ".checkpoint-*")
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_SAVE", "failed to save checkpoint")
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_SAVE", "failed to save checkpoint")
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_SAVE", "failed to save checkpoint")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_SAVE", "failed to save checkpoint")
+	}
+	return nil
+}
+
+// LoadCheckpoint implements SessionStore.
+func (s *FileSessionStore) LoadCheckpoint(ctx context.Context, jobID string) (*JobCheckpoint, error) {
+	path, err := s.path(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return readCheckpoint(path)
+}
+
+// ListCheckpoints implements SessionStore.
+func (s *FileSessionStore) ListCheckpoints(ctx context.Context) ([]*JobCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_LIST", "failed to list checkpoints")
+	}
+
+	checkpoints := make([]*JobCheckpoint, 0, len(paths))
+	for _, path := range paths {
+		checkpoint, err := readCheckpoint(path)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	sortCheckpoints(checkpoints)
+	return checkpoints, nil
+}
+
+// DeleteCheckpoint implements SessionStore.
+func (s *FileSessionStore) DeleteCheckpoint(ctx context.Context, jobID string) error {
+	path, err := s.path(jobID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_DELETE", "failed to delete checkpoint")
+	}
+	return nil
+}
+
+// path returns the file for a job, rejecting IDs that could escape the directory.
+func (s *FileSessionStore) path(jobID string) (string, error) {
+	if err := validateJobID(jobID); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, jobID+".json"), nil
+}
+
+func validateJobID(jobID string) error {
+	if jobID == "" || strings.ContainsAny(jobID, `/\`) || jobID == "." || jobID == ".." {
+		return sdkerrors.NewValidationError("jobID", jobID, "file-safe job ID", "invalid job ID")
+	}
+	return nil
+}
+
+func readCheckpoint(path string) (*JobCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrCheckpointNotFound
+	}
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_READ", "failed to read checkpoint")
+	}
+	return decodeCheckpoint(data, strings.TrimSuffix(filepath.Base(path), ".json"))
+}
+
+func decodeCheckpoint(data []byte, jobID string) (*JobCheckpoint, error) {
+	var checkpoint JobCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_DECODE", "failed to decode checkpoint "+jobID)
+	}
+	return &checkpoint, nil
+}
+
+// sortCheckpoints orders checkpoints by creation time, then job ID.
+func sortCheckpoints(checkpoints []*JobCheckpoint) {
+	sort.Slice(checkpoints, func(i, j int) bool {
+		if !checkpoints[i].CreatedAt.Equal(checkpoints[j].CreatedAt) {
+			return checkpoints[i].CreatedAt.Before(checkpoints[j].CreatedAt)
+		}
+		return checkpoints[i].JobID < checkpoints[j].JobID
+	})
+}
+
+// SetSessionStore sets the store used to checkpoint jobs started with RunJob.
+// Pass nil to disable checkpointing.
+func (c *ClaudeCodeClient) SetSessionStore(store SessionStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sessionStore = store
+}
+
+// SetCheckpointInterval sets the minimum time between checkpoints of a running
+// job. Zero checkpoints after every turn.
+func (c *ClaudeCodeClient) SetCheckpointInterval(interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkpointInterval = interval
+}
+
+// RunJob runs a long-running query in its own session, periodically
+// checkpointing the session ID, turn count, and accumulated messages to the
+// session store. If the host process restarts before the job completes,
+// ResumeJob continues it from the last checkpoint.
+func (c *ClaudeCodeClient) RunJob(ctx context.Context, jobID string, request *types.QueryRequest) (*types.QueryResponse, error) {
+	store, err := c.checkpointStore()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateJobID(jobID); err != nil {
+		return nil, err
+	}
+	if request == nil || len(request.Messages) == 0 {
+		return nil, sdkerrors.NewValidationError("request.messages", "", "non-empty", "job requests require at least one message")
+	}
+
+	now := time.Now()
+	checkpoint := &JobCheckpoint{
+		JobID:     jobID,
+		SessionID: generateSessionID(),
+		Request:   request,
+		Messages:  append([]types.Message(nil), request.Messages...),
+		Metadata:  QueryMetadata(ctx),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	args, err := c.buildClaudeArgsForSession(request, false, checkpoint.SessionID)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "ARGS_BUILD", "failed to build claude arguments")
+	}
+	input, err := c.stdinInput(request, false, c.currentWorkingDir())
+	if err != nil {
+		return nil, err
+	}
+
+	return c.runJob(ctx, store, checkpoint, args, input, request)
+}
+
+// ResumeJob continues a job from its last checkpoint using --resume. A job
+// that already completed returns its stored response without running again.
+func (c *ClaudeCodeClient) ResumeJob(ctx context.Context, jobID string) (*types.QueryResponse, error) {
+	store, err := c.checkpointStore()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := store.LoadCheckpoint(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.Completed {
+		return checkpoint.Response, nil
+	}
+
+	// Label the resumed run like the original one; metadata of ctx wins
+	ctx = WithQueryMetadata(WithQueryMetadata(ctx, checkpoint.Metadata), QueryMetadata(ctx))
+
+	request := &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: JobResumePrompt}},
+	}
+	if checkpoint.Request != nil {
+		request.Model = checkpoint.Request.Model
+		request.System = checkpoint.Request.System
+	}
+	checkpoint.Messages = append(checkpoint.Messages, request.Messages...)
+	checkpoint.Error = ""
+
+	args, err := c.buildClaudeArgsForSession(request, false, checkpoint.SessionID)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "ARGS_BUILD", "failed to build claude arguments")
+	}
+
+	return c.runJob(ctx, store, checkpoint, resumeArgs(args, checkpoint.SessionID), nil, request)
+}
+
+// checkpointStore returns the session store, or an error when none is set.
+func (c *ClaudeCodeClient) checkpointStore() (SessionStore, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
+	}
+	if c.sessionStore == nil {
+		return nil, sdkerrors.NewConfigurationError("sessionStore", "a session store is required to run jobs")
+	}
+	return c.sessionStore, nil
+}
+
+// jobStreamLine is the subset of the CLI's stream-json output recorded in checkpoints.
+type jobStreamLine struct {
+	Type      string `json:"type"`
+	SessionID string `json:"session_id"`
+	Message   *struct {
+		Model   string `json:"model"`
+		Content []struct {
+			Type      string          `json:"type"`
+			Text      string          `json:"text"`
+			ID        string          `json:"id"`
+			Name      string          `json:"name"`
+			Input     json.RawMessage `json:"input"`
+			ToolUseID string          `json:"tool_use_id"`
+			Content   json.RawMessage `json:"content"`
+
+			// Source holds the data of image blocks
+			Source *types.ContentSource `json:"source"`
+		} `json:"content"`
+	} `json:"message"`
+
+	// Result fields
+	Result   string            `json:"result"`
+	IsError  bool              `json:"is_error"`
+	NumTurns int               `json:"num_turns"`
+	Usage    *types.TokenUsage `json:"usage"`
+}
+
+// runJob executes the CLI with stream-json output, recording progress in the
+// checkpoint as turns complete. Input is the stdin of requests with attachments.
+func (c *ClaudeCodeClient) runJob(ctx context.Context, store SessionStore, checkpoint *JobCheckpoint, args []string, input []byte, request *types.QueryRequest) (*types.QueryResponse, error) {
+	c.mu.RLock()
+	interval := c.checkpointInterval
+	c.mu.RUnlock()
+
+	save := func() error {
+		checkpoint.UpdatedAt = time.Now()
+		// Save with a fresh context so a canceled job still records its progress
+		return store.SaveCheckpoint(context.Background(), checkpoint)
+	}
+	fail := func(err error) (*types.QueryResponse, error) {
+		checkpoint.Error = err.Error()
+		_ = save() // Ignore error, the job error is more useful to the caller
+		return nil, err
+	}
+
+	if err := save(); err != nil {
+		return nil, err
+	}
+
+	// The prompt is the last argument since jobs require messages, unless
+	// attachments already switched the CLI to stream-json
+	if input == nil {
+		prompt := args[len(args)-1]
+		args = append(append(args[:len(args)-1:len(args)-1], "--output-format", "stream-json", "--verbose"), prompt)
+	}
+
+	process, err := c.startCLI(ctx, args, input, request, true)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Track the process so Interrupt and Close can terminate it
+	processID := c.newProcessID("job")
+	if err := c.trackProcess(processID, process); err != nil {
+		_ = process.stdout.Close() // Ignore error during cleanup
+		_ = process.stderr.Close() // Ignore error during cleanup
+		_ = process.Wait()         // Ignore error, the process was killed
+		return fail(err)
+	}
+	defer c.untrackProcess(processID)
+
+	// An overrunning tool kills the process; closing stdout releases the
+	// reader even if a grandchild still holds the pipe
+	deadlines := newToolDeadlines(c.settings().ToolTimeouts, func() {
+		_ = process.Kill()         // Ignore error, best effort interrupt
+		_ = process.stdout.Close() // Ignore error, the reader is being released
+	})
+	defer deadlines.stop()
+
+	files := c.trackFileAccess(ctx, checkpoint.SessionID, c.currentWorkingDir())
+
+	var stderr bytes.Buffer
+	stderrDone := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&stderr, process.stderr) // Ignore error, stderr is diagnostic only
+		close(stderrDone)
+	}()
+
+	var (
+		response  *types.QueryResponse
+		resultErr error
+		model     string
+		lastSaved = time.Now()
+	)
+
+	var readErr error
+	lines := c.cliLineReader(process.stdout)
+	for {
+		raw, err := lines.next()
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			break
+		}
+		var line jobStreamLine
+		if err := json.Unmarshal(raw, &line); err != nil {
+			continue
+		}
+		c.publishFrame(raw)
+		if line.Type == "system" {
+			c.observeMCPInit(raw)
+		}
+		files.observeLine(raw)
+		if line.SessionID != "" {
+			checkpoint.SessionID = line.SessionID
+		}
+		if line.Message != nil && line.Message.Model != "" {
+			model = line.Message.Model
+		}
+
+		switch line.Type {
+		case "assistant":
+			if line.Message == nil {
+				continue
+			}
+			message := types.Message{Role: types.RoleAssistant}
+			var text strings.Builder
+			for _, block := range line.Message.Content {
+				switch block.Type {
+				case "text":
+					text.WriteString(block.Text)
+				case "image":
+					image := types.ContentBlock{Type: block.Type, Source: block.Source}
+					if attachment, err := image.Attachment(); err == nil {
+						message.Attachments = append(message.Attachments, attachment)
+					}
+				case "tool_use":
+					deadlines.start(block.ID, block.Name)
+					message.ToolCalls = append(message.ToolCalls, types.ToolCall{
+						ID:       block.ID,
+						Type:     "function",
+						Function: types.FunctionCall{Name: block.Name, Arguments: string(block.Input)},
+					})
+				}
+			}
+			message.Content = text.String()
+			checkpoint.Messages = append(checkpoint.Messages, message)
+			checkpoint.Turns++
+
+			if time.Since(lastSaved) >= interval {
+				if err := save(); err != nil {
+					_ = process.Kill() // Ignore error, the process is being abandoned
+					_ = process.stdout.Close()
+					_ = process.Wait()
+					return nil, err
+				}
+				lastSaved = time.Now()
+			}
+
+		case "user":
+			if line.Message == nil {
+				continue
+			}
+			for _, block := range line.Message.Content {
+				if block.Type == "tool_result" {
+					deadlines.finish(block.ToolUseID)
+					checkpoint.Messages = append(checkpoint.Messages, toolResultMessage(block.ToolUseID, block.Content))
+				}
+			}
+
+		case "result":
+			if line.IsError {
+				resultErr = sdkerrors.NewInternalError("CLAUDE_EXECUTION", "job ended with an error result: "+line.Result)
+				continue
+			}
+			response = &types.QueryResponse{
+				Type:       "message",
+				Role:       types.RoleAssistant,
+				Content:    []types.ContentBlock{types.NewTextBlock(line.Result)},
+				Model:      model,
+				StopReason: "end_turn",
+				Usage:      line.Usage,
+				CreatedAt:  time.Now(),
+			}
+		}
+	}
+	_ = process.stdout.Close() // Ignore error, output has been read
+	<-stderrDone
+	_ = process.stderr.Close() // Ignore error, stderr has been read
+
+	err = process.Wait()
+	if err == nil {
+		err = readErr
+	}
+	if timeoutErr := deadlines.err(); timeoutErr != nil {
+		return fail(timeoutErr)
+	}
+	if err != nil {
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			err = sdkerrors.NewInternalError("CLAUDE_EXECUTION", fmt.Sprintf("claude command failed: %s", stderr.String()))
+		} else {
+			err = sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CLAUDE_EXECUTION", "failed to execute claude command")
+		}
+		return fail(err)
+	}
+	if resultErr != nil {
+		return fail(resultErr)
+	}
+	if response == nil {
+		return fail(sdkerrors.NewInternalError("CLAUDE_EXECUTION", "job ended without a result"))
+	}
+
+	response.Metadata = withMetadataValue(ctx, response.Metadata)
+	checkpoint.Completed = true
+	checkpoint.Response = response
+	checkpoint.Error = ""
+	if err := save(); err != nil {
+		return nil, err
+	}
+
+	c.recordUsage(ctx, request, response)
+	return response, nil
+}
+
+// toolResultMessage converts tool result content, either a string or a list
+// of content blocks, to a tool message. Text becomes the message content;
+// images and other binary data become attachments, so their bytes and media
+// types survive checkpointing intact.
+func toolResultMessage(toolUseID string, content json.RawMessage) types.Message {
+	message := types.Message{Role: types.RoleTool, ToolCallID: toolUseID}
+
+	blocks, err := types.ParseToolResultContent(content)
+	if err != nil {
+		return message
+	}
+
+	var text strings.Builder
+	for i := range blocks {
+		switch {
+		case blocks[i].Type == "text":
+			text.WriteString(blocks[i].Text)
+		case blocks[i].IsBinary():
+			if attachment, err := blocks[i].Attachment(); err == nil {
+				message.Attachments = append(message.Attachments, attachment)
+			}
+		}
+	}
+	message.Content = text.String()
+	return message
+}
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// The fake CLI crashes after one turn unless resumed, then completes.
+const checkpointScript = `
+case "$*" in
+*--resume*)
+	echo '{"type":"assistant","message":{"model":"claude-sonnet-4","content":[{"type":"text","text":"finishing"}]}}'
+	echo '{"type":"result","subtype":"success","result":"all done","num_turns":1,"usage":{"input_tokens":4,"output_tokens":2}}'
+	;;
+*)
+	echo '{"type":"system","subtype":"init","session_id":"sess-job"}'
+	echo '{"type":"assistant","message":{"model":"claude-sonnet-4","content":[{"type":"text","text":"reading"},{"type":"tool_use","id":"toolu_1","name":"Read","input":{"file_path":"a.go"}}]}}'
+	echo '{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"package a"}]}]}}'
+	echo "killed" >&2
+	exit 137
+	;;
+esac`
+
+func TestRunJob_CheckpointsAndResumes(t *testing.T) {
+	store, err := NewFileSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSessionStore failed: %v", err)
+	}
+
+	client := newFakeCLIClient(t, checkpointScript)
+	client.SetSessionStore(store)
+	client.SetCheckpointInterval(0)
+
+	ctx := context.Background()
+	_, err = client.RunJob(ctx, "job-1", &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "refactor a.go"}},
+	})
+	if err == nil {
+		t.Fatal("Expected the interrupted job to fail")
+	}
+
+	checkpoint, err := store.LoadCheckpoint(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	if checkpoint.Completed || checkpoint.SessionID != "sess-job" || checkpoint.Turns != 1 || checkpoint.Error == "" {
+		t.Fatalf("Unexpected checkpoint: %+v", checkpoint)
+	}
+	if len(checkpoint.Messages) != 3 {
+		t.Fatalf("Expected prompt, assistant and tool messages, got %+v", checkpoint.Messages)
+	}
+	if call := checkpoint.Messages[1].ToolCalls; len(call) != 1 || call[0].Function.Name != "Read" {
+		t.Errorf("Tool call not recorded: %+v", checkpoint.Messages[1])
+	}
+	if tool := checkpoint.Messages[2]; tool.Role != types.RoleTool || tool.Content != "package a" || tool.ToolCallID != "toolu_1" {
+		t.Errorf("Tool result not recorded: %+v", tool)
+	}
+
+	// A new client picks the job up from the store
+	resumed := newFakeCLIClient(t, checkpointScript)
+	resumed.SetSessionStore(store)
+
+	response, err := resumed.ResumeJob(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("ResumeJob failed: %v", err)
+	}
+	if response.Content[0].Text != "all done" || response.Model != "claude-sonnet-4" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	checkpoint, err = store.LoadCheckpoint(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	if !checkpoint.Completed || checkpoint.Turns != 2 || checkpoint.Error != "" {
+		t.Errorf("Unexpected completed checkpoint: %+v", checkpoint)
+	}
+
+	// Resuming a completed job returns the stored response
+	again, err := resumed.ResumeJob(ctx, "job-1")
+	if err != nil || again.Content[0].Text != "all done" {
+		t.Errorf("Expected stored response, got %+v %v", again, err)
+	}
+}
+
+func TestRunJob_RequiresStore(t *testing.T) {
+	client := newFakeCLIClient(t, `exit 0`)
+
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}
+	if _, err := client.RunJob(context.Background(), "job", request); err == nil {
+		t.Error("Expected an error without a session store")
+	}
+
+	client.SetSessionStore(NewMemorySessionStore())
+	if _, err := client.RunJob(context.Background(), "../job", request); err == nil {
+		t.Error("Expected an invalid job ID to be rejected")
+	}
+	if _, err := client.ResumeJob(context.Background(), "missing"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("Expected ErrCheckpointNotFound, got %v", err)
+	}
+}
+
+func TestMemorySessionStore(t *testing.T) {
+	store := NewMemorySessionStore()
+	ctx := context.Background()
+
+	checkpoint := &JobCheckpoint{JobID: "a", Turns: 2}
+	if err := store.SaveCheckpoint(ctx, checkpoint); err != nil {
+		t.Fatalf("SaveCheckpoint failed: %v", err)
+	}
+	checkpoint.Turns = 5
+
+	loaded, err := store.LoadCheckpoint(ctx, "a")
+	if err != nil || loaded.Turns != 2 {
+		t.Errorf("Expected stored copy, got %+v %v", loaded, err)
+	}
+
+	list, err := store.ListCheckpoints(ctx)
+	if err != nil || len(list) != 1 {
+		t.Errorf("Unexpected list: %v %v", list, err)
+	}
+
+	if err := store.DeleteCheckpoint(ctx, "a"); err != nil {
+		t.Fatalf("DeleteCheckpoint failed: %v", err)
+	}
+	if _, err := store.LoadCheckpoint(ctx, "a"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("Expected ErrCheckpointNotFound, got %v", err)
+	}
+}
+
+func TestRunJob_BinaryToolResult(t *testing.T) {
+	// A screenshot with bytes that are not valid UTF-8, and gzip output
+	png := []byte("\x89PNG\r\n\x1a\n\x00\xff\xfe")
+	gzip := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff}
+	client := newFakeCLIClient(t, `
+echo '{"type":"system","subtype":"init","session_id":"sess-bin"}'
+echo '{"type":"assistant","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Read","input":{"file_path":"shot.png"}}]}}'
+echo '{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"two files"},{"type":"image","source":{"type":"base64","media_type":"image/png","data":"`+base64.StdEncoding.EncodeToString(png)+`"}},{"type":"binary","source":{"type":"base64","media_type":"application/gzip","data":"`+base64.StdEncoding.EncodeToString(gzip)+`"}}]}]}}'
+echo '{"type":"result","subtype":"success","result":"done"}'`)
+	store, err := NewFileSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSessionStore failed: %v", err)
+	}
+	client.SetSessionStore(store)
+
+	ctx := context.Background()
+	if _, err := client.RunJob(ctx, "job-bin", &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "look"}},
+	}); err != nil {
+		t.Fatalf("RunJob failed: %v", err)
+	}
+
+	// The bytes survive the round trip through the store
+	checkpoint, err := store.LoadCheckpoint(ctx, "job-bin")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	tool := checkpoint.Messages[len(checkpoint.Messages)-1]
+	if tool.Content != "two files" || len(tool.Attachments) != 2 {
+		t.Fatalf("Unexpected tool message: %+v", tool)
+	}
+	if image := tool.Attachments[0]; image.Type != types.AttachmentTypeImage || image.MimeType != "image/png" || !bytes.Equal(image.Data, png) {
+		t.Errorf("Image attachment = %+v", image)
+	}
+	if file := tool.Attachments[1]; file.Type != types.AttachmentTypeFile || file.MimeType != "application/gzip" || !bytes.Equal(file.Data, gzip) {
+		t.Errorf("Binary attachment = %+v", file)
+	}
+}
+
+func TestRunJob_AssistantImage(t *testing.T) {
+	client := newFakeCLIClient(t, `
+echo '{"type":"assistant","message":{"content":[{"type":"text","text":"A diagram"},{"type":"image","source":{"type":"base64","media_type":"image/png","data":"iVBORw0KGgo="}}]}}'
+echo '{"type":"result","subtype":"success","result":"done"}'`)
+	store := NewMemorySessionStore()
+	client.SetSessionStore(store)
+
+	ctx := context.Background()
+	if _, err := client.RunJob(ctx, "job-image", &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "draw"}},
+	}); err != nil {
+		t.Fatalf("RunJob failed: %v", err)
+	}
+
+	checkpoint, err := store.LoadCheckpoint(ctx, "job-image")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	assistant := checkpoint.Messages[len(checkpoint.Messages)-1]
+	if assistant.Content != "A diagram" || len(assistant.Attachments) != 1 || assistant.Attachments[0].Type != types.AttachmentTypeImage {
+		t.Errorf("Unexpected assistant message: %+v", assistant)
+	}
+}
+
+func TestContentBlock_StringToolResult(t *testing.T) {
+	var block types.ContentBlock
+	if err := json.Unmarshal([]byte(`{"type":"tool_result","tool_use_id":"toolu_1","content":"package a"}`), &block); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(block.Content) != 1 || block.Content[0].Text != "package a" || block.ToolUseID != "toolu_1" {
+		t.Errorf("Unexpected block: %+v", block)
+	}
+
+	data := []byte{0, 1, 2, 0xff}
+	encoded, err := json.Marshal(types.NewToolResultBlock("toolu_2", []types.ContentBlock{types.NewBinaryBlock("", data)}, false))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if err := json.Unmarshal(encoded, &block); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	decoded, err := block.Content[0].Bytes()
+	if err != nil || !bytes.Equal(decoded, data) || block.Content[0].MediaType() != "application/octet-stream" {
+		t.Errorf("Binary block did not round trip: %+v, %v", block.Content[0], err)
+	}
+}
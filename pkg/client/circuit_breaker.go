@@ -0,0 +1,219 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitCoolDown         = 30 * time.Second
+)
+
+// circuitOutcome is how a CLI process counts toward the circuit breaker.
+type circuitOutcome int
+
+const (
+	circuitIgnored circuitOutcome = iota
+	circuitSuccess
+	circuitFailure
+)
+
+// circuitBreaker applies a CircuitBreakerConfig to the processes a client
+// starts. Its methods do nothing on a nil breaker.
+type circuitBreaker struct {
+	config *types.CircuitBreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    types.CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+func newCircuitBreaker(config *types.CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{config: config, now: time.Now, state: types.CircuitClosed}
+}
+
+func (b *circuitBreaker) threshold() int {
+	if b.config.FailureThreshold > 0 {
+		return b.config.FailureThreshold
+	}
+	return defaultCircuitFailureThreshold
+}
+
+func (b *circuitBreaker) coolDown() time.Duration {
+	if b.config.CoolDown > 0 {
+		return b.config.CoolDown
+	}
+	return defaultCircuitCoolDown
+}
+
+func (b *circuitBreaker) maxProbes() int {
+	if b.config.HalfOpenProbes > 0 {
+		return b.config.HalfOpenProbes
+	}
+	return 1
+}
+
+// allow admits a process, reporting whether it is a half-open probe, or
+// returns a CircuitOpenError.
+func (b *circuitBreaker) allow() (bool, error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	from := b.state
+	if b.state == types.CircuitOpen {
+		if wait := b.openedAt.Add(b.coolDown()).Sub(b.now()); wait > 0 {
+			failures := b.failures
+			b.mu.Unlock()
+			return false, sdkerrors.NewCircuitOpenError(failures, wait)
+		}
+		b.state = types.CircuitHalfOpen
+	}
+
+	probe := false
+	var err error
+	if b.state == types.CircuitHalfOpen {
+		if b.probes < b.maxProbes() {
+			b.probes++
+			probe = true
+		} else {
+			// Wait for the probes in flight to decide
+			err = sdkerrors.NewCircuitOpenError(b.failures, 0)
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return probe, err
+}
+
+// record counts the outcome of a process admitted by allow.
+func (b *circuitBreaker) record(probe bool, outcome circuitOutcome) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	from := b.state
+	if probe {
+		b.probes--
+	}
+	switch outcome {
+	case circuitSuccess:
+		b.failures = 0
+		b.state = types.CircuitClosed
+	case circuitFailure:
+		b.failures++
+		// A failed probe reopens the circuit; failures of processes started
+		// before it opened do not extend the cool-down
+		if (b.state == types.CircuitHalfOpen && probe) ||
+			(b.state == types.CircuitClosed && b.failures >= b.threshold()) {
+			b.state = types.CircuitOpen
+			b.openedAt = b.now()
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// notify reports a state change to the configured callback.
+func (b *circuitBreaker) notify(from, to types.CircuitState) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(from, to)
+	}
+}
+
+// currentState returns the state, reporting an open circuit whose cool-down
+// has passed as half-open.
+func (b *circuitBreaker) currentState() types.CircuitState {
+	if b == nil {
+		return types.CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == types.CircuitOpen && !b.now().Before(b.openedAt.Add(b.coolDown())) {
+		return types.CircuitHalfOpen
+	}
+	return b.state
+}
+
+// watch records the outcome of process when it is waited on. Processes
+// killed by the SDK, such as interrupted queries and closed streams, and
+// processes whose context was canceled are ignored.
+func (b *circuitBreaker) watch(ctx context.Context, process *cliProcess, probe bool) {
+	if b == nil {
+		return
+	}
+
+	var killed atomic.Bool
+	kill := process.kill
+	process.kill = func() error {
+		killed.Store(true)
+		return kill()
+	}
+
+	var once sync.Once
+	wait := process.wait
+	process.wait = func() error {
+		err := wait()
+		once.Do(func() {
+			switch {
+			case err == nil:
+				b.record(probe, circuitSuccess)
+			case killed.Load() || errors.Is(ctx.Err(), context.Canceled):
+				b.record(probe, circuitIgnored)
+			default:
+				b.record(probe, circuitFailure)
+			}
+		})
+		return err
+	}
+}
+
+// spawnOutcome classifies a process that failed to start. Configuration and
+// validation errors are the caller's, not the CLI's, and are ignored.
+func spawnOutcome(err error) circuitOutcome {
+	var sdkErr sdkerrors.SDKError
+	if errors.As(err, &sdkErr) && sdkErr.Category() != sdkerrors.CategoryInternal {
+		return circuitIgnored
+	}
+	return circuitFailure
+}
+
+// circuit returns the circuit breaker for the current configuration, or nil
+// if none is configured. A new breaker, closed, replaces the old one when
+// the configuration changes.
+func (c *ClaudeCodeClient) circuit() *circuitBreaker {
+	config := c.settings().CircuitBreaker
+	if config == nil {
+		return nil
+	}
+
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+	if c.breaker == nil || c.breaker.config != config {
+		c.breaker = newCircuitBreaker(config)
+	}
+	return c.breaker
+}
+
+// CircuitState returns the state of the client's circuit breaker. It is
+// CircuitClosed when no breaker is configured.
+func (c *ClaudeCodeClient) CircuitState() types.CircuitState {
+	return c.circuit().currentState()
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	var changes []string
+	now := time.Unix(0, 0)
+	breaker := newCircuitBreaker(&types.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		CoolDown:         time.Minute,
+		OnStateChange: func(from, to types.CircuitState) {
+			changes = append(changes, string(from)+">"+string(to))
+		},
+	})
+	breaker.now = func() time.Time { return now }
+
+	fail := func() {
+		t.Helper()
+		probe, err := breaker.allow()
+		if err != nil {
+			t.Fatalf("allow failed: %v", err)
+		}
+		breaker.record(probe, circuitFailure)
+	}
+
+	fail()
+	breaker.record(false, circuitIgnored)
+	if state := breaker.currentState(); state != types.CircuitClosed {
+		t.Fatalf("State after one failure = %s, want closed", state)
+	}
+	fail()
+
+	_, err := breaker.allow()
+	var open *sdkerrors.CircuitOpenError
+	if !errors.As(err, &open) || open.Failures != 2 || open.RetryAfter != time.Minute {
+		t.Fatalf("Expected CircuitOpenError, got %v", err)
+	}
+
+	// After the cool-down one probe runs; its failure reopens the circuit
+	now = now.Add(time.Minute)
+	if state := breaker.currentState(); state != types.CircuitHalfOpen {
+		t.Errorf("State after cool-down = %s, want half_open", state)
+	}
+	probe, err := breaker.allow()
+	if err != nil || !probe {
+		t.Fatalf("Expected a probe, got probe=%v err=%v", probe, err)
+	}
+	if _, err := breaker.allow(); !errors.As(err, &open) {
+		t.Errorf("Expected a second query to fail fast while probing, got %v", err)
+	}
+	breaker.record(probe, circuitFailure)
+	if _, err := breaker.allow(); !errors.As(err, &open) {
+		t.Fatalf("Expected failed probe to reopen the circuit, got %v", err)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	probe, _ = breaker.allow()
+	breaker.record(probe, circuitSuccess)
+	if state := breaker.currentState(); state != types.CircuitClosed {
+		t.Errorf("State after successful probe = %s, want closed", state)
+	}
+
+	want := []string{"closed>open", "open>half_open", "half_open>open", "open>half_open", "half_open>closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("State changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("State changes = %v, want %v", changes, want)
+		}
+	}
+}
+
+func TestClaudeCodeClient_CircuitBreaker(t *testing.T) {
+	client := newFakeCLIClient(t, `if [ -f fail ]; then echo "API unavailable" >&2; exit 1; fi
+echo "handled $prompt"`)
+	client.config.CircuitBreaker = &types.CircuitBreakerConfig{FailureThreshold: 2, CoolDown: time.Minute}
+	now := time.Now()
+	client.circuit().now = func() time.Time { return now }
+
+	failMarker := filepath.Join(client.workingDir, "fail")
+	if err := os.WriteFile(failMarker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Query(ctx, userRequest("hello")); err == nil {
+			t.Fatal("Expected the failing CLI to return an error")
+		}
+	}
+	if state := client.CircuitState(); state != types.CircuitOpen {
+		t.Fatalf("CircuitState = %s, want open", state)
+	}
+
+	_, err := client.Query(ctx, userRequest("hello"))
+	var open *sdkerrors.CircuitOpenError
+	if !errors.As(err, &open) {
+		t.Fatalf("Expected CircuitOpenError, got %v", err)
+	}
+	if _, err := client.QueryStream(ctx, userRequest("hello")); !errors.As(err, &open) {
+		t.Errorf("Expected streaming query to fail fast, got %v", err)
+	}
+
+	// Once the CLI recovers, the probe after the cool-down closes the circuit
+	if err := os.Remove(failMarker); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := client.Query(ctx, userRequest("hello")); err != nil {
+		t.Fatalf("Probe query failed: %v", err)
+	}
+	if state := client.CircuitState(); state != types.CircuitClosed {
+		t.Errorf("CircuitState = %s, want closed", state)
+	}
+}
@@ -1,7 +1,7 @@
 package client
 
 import (
-	"bufio"
+	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/json"
@@ -12,11 +12,16 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/pricing"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/prompts"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/recorder"
 	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
 )
 
@@ -33,6 +38,9 @@ import (
 // - MCP Integration: Supports Model Context Protocol for tool extensions
 // - Streaming Support: Real-time response processing with chunk handling
 //
+// The client is safe for concurrent use by multiple goroutines. Each query runs
+// in its own claude process, and Interrupt and Close may be called at any time.
+//
 // Example usage:
 //
 //	config := &types.ClaudeCodeConfig{
@@ -56,12 +64,21 @@ type ClaudeCodeClient struct {
 	workingDir    string
 	sessionID     string
 	claudeCodeCmd string
+	addDirs       []string
 	mu            sync.RWMutex
 	closed        bool
 
 	// Process management
-	activeProcesses map[string]*exec.Cmd
+	activeProcesses map[string]*cliProcess
 	processMu       sync.Mutex
+	processSeq      atomic.Uint64
+
+	// Longest line of CLI output accepted, maxCLILineSize when zero; tests
+	// lower it to exceed it without writing that much
+	maxLineSize int
+
+	// Lifecycle state reported by State
+	lifecycle stateMachine
 
 	// MCP management
 	mcpManager *MCPManager
@@ -74,6 +91,68 @@ type ClaudeCodeClient struct {
 
 	// Session management
 	sessionManager *ClaudeCodeSessionManager
+
+	// Liveness checks for interactive streams
+	connMonitor *connectionMonitor
+
+	// Cassette recorder for capturing or replaying CLI invocations
+	recorder *recorder.Recorder
+
+	// Fake CLI serving the configured TestScript in test mode
+	scripted *scriptedCLI
+
+	// Price overrides and provider for cost estimation and usage tracking
+	modelPricing    pricing.Table
+	pricingProvider pricing.Provider
+	usageTracker    *pricing.UsageTracker
+
+	// Webhook delivery for query progress events (nil when disabled)
+	webhooks *webhookNotifier
+
+	// Checkpoint persistence for jobs started with RunJob
+	sessionStore       SessionStore
+	checkpointInterval time.Duration
+
+	// Capabilities of the installed CLI, probed on first use
+	features   *CLIFeatures
+	featuresMu sync.Mutex
+
+	// Config file watcher started by WatchConfig (nil when not watching)
+	configWatcher *configWatcher
+
+	// MCP server health from `claude mcp list` and stream-json init messages
+	mcpStatuses map[string]types.MCPServerStatus
+	mcpStatusMu sync.Mutex
+
+	// Prompt templates for QueryTemplate (nil uses prompts.Default)
+	prompts *prompts.Library
+
+	// Loopback server answering approval hooks (nil until first needed)
+	approvals  *approvalServer
+	approvalMu sync.Mutex
+
+	// Go tools set with SetToolRegistry (guarded by mu), the loopback MCP
+	// server offering them to the CLI (nil until first needed), the message
+	// streams receiving their progress by query route, and calls in flight
+	toolRegistry    *ToolRegistry
+	localTools      *localToolServer
+	progressStreams map[string]*progressStream
+	toolCalls       map[*localToolCall]struct{}
+	localToolsMu    sync.Mutex
+
+	// Snippets pinned into every query's system prompt (guarded by mu)
+	pins []types.PinnedSnippet
+
+	// Control channels of open streams, oldest first, and the subscribers
+	// receiving raw CLI output frames by subscription ID
+	controls    []*controlChannel
+	frameSubs   map[uint64]func(json.RawMessage)
+	frameSubSeq uint64
+	controlMu   sync.Mutex
+
+	// Circuit breaker for the configured CircuitBreaker (nil until first used)
+	breaker   *circuitBreaker
+	breakerMu sync.Mutex
 }
 
 // NewClaudeCodeClient creates a new Claude Code client with subprocess management.
@@ -135,13 +214,37 @@ func NewClaudeCodeClient(ctx context.Context, config *types.ClaudeCodeConfig) (*
 		return nil, sdkerrors.NewConfigurationError("working_directory", "working directory does not exist: "+config.WorkingDirectory)
 	}
 
+	// Validate additional workspace roots
+	addDirs := make([]string, 0, len(config.AddDirs))
+	for _, dir := range config.AddDirs {
+		absDir, err := resolveAdditionalDirectory(config.WorkingDirectory, dir)
+		if err != nil {
+			return nil, err
+		}
+		if !containsString(addDirs, absDir) {
+			addDirs = append(addDirs, absDir)
+		}
+	}
+
 	client := &ClaudeCodeClient{
 		config:          config,
 		workingDir:      config.WorkingDirectory,
 		sessionID:       config.SessionID,
 		claudeCodeCmd:   claudeCmd,
-		activeProcesses: make(map[string]*exec.Cmd),
+		addDirs:         append([]string(nil), addDirs...),
+		activeProcesses: make(map[string]*cliProcess),
+
+		checkpointInterval: DefaultCheckpointInterval,
 	}
+	client.lifecycle.state = types.ClientReady
+	// The resolved roots go in the client's copy, not the caller's config
+	client.updateSettings(func(config *types.ClaudeCodeConfig) {
+		config.AddDirs = addDirs
+	})
+	if config.TestMode && config.TestScript != nil {
+		client.scripted = newScriptedCLI(config.TestScript)
+	}
+	client.lifecycle.onChange = config.OnStateChange
 
 	// Initialize MCP manager
 	client.mcpManager = NewMCPManager(client)
@@ -155,6 +258,25 @@ func NewClaudeCodeClient(ctx context.Context, config *types.ClaudeCodeConfig) (*
 	// Initialize session manager
 	client.sessionManager = NewClaudeCodeSessionManager(client)
 
+	// Track usage with the client's current pricing
+	client.usageTracker = pricing.NewUsageTracker(pricing.ProviderFunc(client.lookupPrice))
+
+	// Start webhook delivery if configured
+	if config.Webhook != nil {
+		notifier, err := newWebhookNotifier(config.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		client.webhooks = notifier
+		client.webhooks.start()
+	}
+
+	// Start liveness checks if configured
+	if config.KeepAlive != nil {
+		client.connMonitor = newConnectionMonitor(config.KeepAlive)
+		client.connMonitor.start()
+	}
+
 	return client, nil
 }
 
@@ -175,43 +297,100 @@ func (c *ClaudeCodeClient) Query(ctx context.Context, request *types.QueryReques
 	if request == nil {
 		return nil, sdkerrors.NewValidationError("request", "", "required", "request cannot be nil")
 	}
+	request, err := c.policyRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
 
-	// Build claude command arguments
-	args, err := c.buildClaudeArgs(request, false)
+	// Build claude command arguments for the session and directories of ctx
+	scope := c.scopeFrom(ctx)
+	args, err := c.buildScopedClaudeArgs(request, false, scope)
 	if err != nil {
 		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "ARGS_BUILD", "failed to build claude arguments")
 	}
-
-	// Execute claude command
-	cmd := exec.CommandContext(ctx, c.claudeCodeCmd, args...) // #nosec G204 - claudeCodeCmd is validated during initialization
-	cmd.Dir = c.workingDir
-
-	// Set environment variables
-	cmd.Env = append(os.Environ(), c.buildEnvironment()...)
+	input, err := c.stdinInput(request, false, scope.workingDir)
+	if err != nil {
+		return nil, err
+	}
 
 	// Debug: print the command being executed
-	if c.config.Debug {
+	if c.settings().Debug {
 		fmt.Printf("[DEBUG] Executing: %s %s\n", c.claudeCodeCmd, strings.Join(args, " "))
-		fmt.Printf("[DEBUG] Working directory: %s\n", c.workingDir)
+		fmt.Printf("[DEBUG] Working directory: %s\n", scope.workingDir)
 		// Don't log environment variables as they may contain sensitive information
 		fmt.Printf("[DEBUG] Environment variables configured for authentication\n")
 	}
 
-	// Capture output
-	output, err := cmd.Output()
+	webhooks := c.trackWebhooks(ctx, request, scope.sessionID)
+
+	// Execute claude command
+	process, err := c.startCLI(ctx, args, input, request, true)
+	if err != nil {
+		webhooks.fail(err)
+		return nil, err
+	}
+
+	// Track the process so Interrupt and Close can terminate it
+	processID := c.newProcessID("query")
+	if err := c.trackProcess(processID, process); err != nil {
+		_ = process.stdout.Close() // Ignore error during cleanup
+		_ = process.stderr.Close() // Ignore error during cleanup
+		_ = process.Wait()         // Ignore error, the process was killed
+		webhooks.fail(err)
+		return nil, err
+	}
+	defer c.untrackProcess(processID)
+
+	// Capture output, draining stderr concurrently so the process cannot block on it
+	var stderr bytes.Buffer
+	stderrDone := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&stderr, process.stderr) // Ignore error, stderr is diagnostic only
+		close(stderrDone)
+	}()
+
+	output, readErr := io.ReadAll(process.stdout)
+	_ = process.stdout.Close() // Ignore error, output has been read
+	<-stderrDone
+	_ = process.stderr.Close() // Ignore error, stderr has been read
+
+	err = process.Wait()
+	if err == nil {
+		err = readErr
+	}
 	if err != nil {
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			return nil, sdkerrors.NewInternalError("CLAUDE_EXECUTION", fmt.Sprintf("claude command failed: %s", string(exitErr.Stderr)))
+		var exitErr interface{ ExitCode() int }
+		if process.interrupted.Load() {
+			err = sdkerrors.NewInternalError("INTERRUPTED", "query was interrupted")
+		} else if errors.As(err, &exitErr) {
+			err = sdkerrors.NewInternalError("CLAUDE_EXECUTION", fmt.Sprintf("claude command failed: %s", stderr.String()))
+		} else {
+			err = sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CLAUDE_EXECUTION", "failed to execute claude command")
 		}
-		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CLAUDE_EXECUTION", "failed to execute claude command")
+		webhooks.fail(err)
+		return nil, err
 	}
 
-	// Parse response
-	response, err := c.parseClaudeOutput(string(output))
+	// Parse response; attachments switch the output to stream-json
+	var response *types.QueryResponse
+	if input != nil {
+		response, err = parseStreamJSONOutput(output)
+	} else {
+		response, err = c.parseClaudeOutput(output)
+	}
 	if err != nil {
-		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryAPI, "RESPONSE_PARSE", "failed to parse claude output")
+		err = sdkerrors.WrapError(err, sdkerrors.CategoryAPI, "RESPONSE_PARSE", "failed to parse claude output")
+		webhooks.fail(err)
+		return nil, err
+	}
+
+	response.Metadata = withMetadataValue(ctx, response.Metadata)
+	c.recordUsage(ctx, request, response)
+	if err := c.policyResponse(ctx, response); err != nil {
+		webhooks.fail(err)
+		return nil, err
 	}
+	webhooks.observeResponse(response)
 
 	return response, nil
 }
@@ -233,51 +412,74 @@ func (c *ClaudeCodeClient) QueryStream(ctx context.Context, request *types.Query
 	if request == nil {
 		return nil, sdkerrors.NewValidationError("request", "", "required", "request cannot be nil")
 	}
+	request, err := c.policyRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build claude command arguments for streaming
-	args, err := c.buildClaudeArgs(request, true)
+	scope := c.scopeFrom(ctx)
+	args, err := c.buildScopedClaudeArgs(request, true, scope)
 	if err != nil {
 		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "ARGS_BUILD", "failed to build claude streaming arguments")
 	}
-
-	// Create and start claude process
-	cmd := exec.CommandContext(ctx, c.claudeCodeCmd, args...) // #nosec G204 - claudeCodeCmd is validated during initialization
-	cmd.Dir = c.workingDir
-	cmd.Env = append(os.Environ(), c.buildEnvironment()...)
-
-	// Create pipes for stdout
-	stdout, err := cmd.StdoutPipe()
+	input, err := c.stdinInput(request, true, scope.workingDir)
 	if err != nil {
-		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PIPE_CREATION", "failed to create stdout pipe")
+		return nil, err
 	}
 
-	// Start the process
-	if err := cmd.Start(); err != nil {
-		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PROCESS_START", "failed to start claude process")
-	}
-
-	// Track the process
-	processID := fmt.Sprintf("stream-%d", time.Now().UnixNano())
-	c.processMu.Lock()
-	c.activeProcesses[processID] = cmd
-	c.processMu.Unlock()
-
 	// Create streaming query stream
 	stream := &claudeCodeQueryStream{
-		cmd:       cmd,
-		stdout:    stdout,
 		ctx:       ctx,
-		processID: processID,
+		processID: c.newProcessID("stream"),
 		client:    c,
+		args:      args,
+		input:     input,
+		request:   request,
+		scope:     scope,
+		sessionID: scope.sessionID,
+		webhooks:  c.trackWebhooks(ctx, request, scope.sessionID),
+		files:     c.trackFileAccess(ctx, scope.sessionID, scope.workingDir),
+	}
+	stream.deadlines = newToolDeadlines(c.settings().ToolTimeouts, stream.abort)
+	if c.settings().ControlProtocol {
+		stream.control = newControlChannel()
+	}
+
+	// Start the claude process
+	if err := stream.startProcess(args); err != nil {
+		stream.webhooks.fail(err)
+		return nil, err
+	}
+	if stream.control != nil {
+		c.trackControl(stream.control)
+	}
+
+	if c.connMonitor != nil {
+		c.connMonitor.track(stream)
 	}
 
+	if c.settings().MessagePolicy != nil {
+		return &policyStream{QueryStream: stream, ctx: ctx, client: c}, nil
+	}
 	return stream, nil
 }
 
 // Close gracefully shuts down the client and terminates any active processes.
 // This method should be called when the client is no longer needed to prevent
 // resource leaks and orphaned processes.
+// Queries in flight return an error; Close is safe to call concurrently with them.
 func (c *ClaudeCodeClient) Close() error {
+	// Deferred first so the change is reported after the lock is released
+	defer c.transition(func(m *stateMachine) { m.closed = true })
+	// Deferred before the lock so that the project context manager, which
+	// locks the client while building, is stopped after it is released
+	defer func() {
+		if c.projectContextManager != nil {
+			c.projectContextManager.stopWatching()
+		}
+	}()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -287,6 +489,16 @@ func (c *ClaudeCodeClient) Close() error {
 
 	c.closed = true
 
+	// Stop liveness checks
+	if c.connMonitor != nil {
+		c.connMonitor.stop()
+	}
+
+	// Stop watching the config file
+	if c.configWatcher != nil {
+		c.configWatcher.stop()
+	}
+
 	// Close session manager
 	if c.sessionManager != nil {
 		_ = c.sessionManager.Close() // Ignore error during cleanup
@@ -294,14 +506,21 @@ func (c *ClaudeCodeClient) Close() error {
 
 	// Terminate all active processes
 	c.processMu.Lock()
-	for processID, cmd := range c.activeProcesses {
-		if cmd.Process != nil {
-			_ = cmd.Process.Kill() // Ignore error, best effort cleanup
-		}
+	for processID, process := range c.activeProcesses {
+		process.interrupt()
 		delete(c.activeProcesses, processID)
 	}
 	c.processMu.Unlock()
 
+	// Deliver queued webhook events
+	if c.webhooks != nil {
+		c.webhooks.stop()
+	}
+
+	// Stop answering approval hooks and local tool calls
+	c.stopApprovals()
+	c.stopLocalTools()
+
 	return nil
 }
 
@@ -327,44 +546,206 @@ func (c *ClaudeCodeClient) ExecuteCommands(ctx context.Context, cmdList *types.C
 // GetProjectContext returns information about the current project context.
 func (c *ClaudeCodeClient) GetProjectContext(ctx context.Context) (*types.ProjectContext, error) {
 	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	if c.closed {
-		c.mu.RUnlock()
 		return nil, sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
 	}
-	c.mu.RUnlock()
 
-	// Simplified to match official SDK scope - just working directory
+	// Simplified to match official SDK scope - working directory and additional roots
 	context := &types.ProjectContext{
 		WorkingDirectory: c.workingDir,
 	}
+	if len(c.addDirs) > 0 {
+		context.AdditionalDirectories = append([]string(nil), c.addDirs...)
+	}
 
 	return context, nil
 }
 
 // SetWorkingDirectory changes the working directory for Claude Code operations.
+// The path must be an existing directory and, when ProjectMarkers is set,
+// contain one of the markers. The cached enhanced project context is dropped
+// with the switch, so it is never served for the previous directory, and
+// OnWorkingDirectoryChange is called once the change has been made.
 func (c *ClaudeCodeClient) SetWorkingDirectory(ctx context.Context, path string) error {
+	if strings.TrimSpace(path) == "" {
+		return sdkerrors.NewValidationError("path", path, "required", "directory path cannot be empty")
+	}
+
+	// Convert to absolute path
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "PATH_ABS", "failed to convert to absolute path")
+	}
+
+	// Validate the directory exists and looks like a project
+	info, err := os.Stat(absPath)
+	if os.IsNotExist(err) {
+		return sdkerrors.NewValidationError("path", path, "exists", "directory does not exist")
+	}
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "PATH_STAT", "failed to inspect directory")
+	}
+	if !info.IsDir() {
+		return sdkerrors.NewValidationError("path", path, "directory", "path is not a directory")
+	}
+	markers := c.settings().ProjectMarkers
+	marker := findProjectMarker(absPath, markers)
+	if len(markers) > 0 && marker == "" {
+		return sdkerrors.NewValidationError("path", path, "project marker",
+			fmt.Sprintf("directory contains none of the project markers %s", strings.Join(markers, ", ")))
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
+	}
+	previous := c.workingDir
+	c.workingDir = absPath
+	c.updateSettings(func(config *types.ClaudeCodeConfig) {
+		config.WorkingDirectory = absPath
+	})
+	onChange := c.config.OnWorkingDirectoryChange
+	c.mu.Unlock()
+
+	// The cache is also keyed by directory, so a context built for the old
+	// directory in the meantime is never returned
+	c.projectContextManager.InvalidateCache()
+
+	if onChange != nil && previous != absPath {
+		onChange(types.WorkingDirectoryChange{From: previous, To: absPath, Marker: marker, Time: time.Now()})
+	}
+
+	return nil
+}
+
+// findProjectMarker returns the first marker present in dir, or "".
+func findProjectMarker(dir string, markers []string) string {
+	for _, marker := range markers {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return marker
+		}
+	}
+	return ""
+}
+
+// AddDirectory adds an additional workspace root that Claude Code may access.
+// The directory is passed to the CLI with --add-dir on every subsequent query,
+// which lets monorepo users expose sibling packages outside the working directory.
+// Relative paths are resolved against the current working directory.
+func (c *ClaudeCodeClient) AddDirectory(ctx context.Context, path string) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 
 	if c.closed {
+		c.mu.Unlock()
 		return sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
 	}
 
-	// Validate the directory exists
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return sdkerrors.NewValidationError("path", path, "exists", "directory does not exist")
+	absPath, err := resolveAdditionalDirectory(c.workingDir, path)
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+
+	if containsString(c.addDirs, absPath) {
+		c.mu.Unlock()
+		return nil
+	}
+
+	c.addDirs = append(c.addDirs, absPath)
+	c.updateSettings(func(config *types.ClaudeCodeConfig) {
+		config.AddDirs = append(config.AddDirs, absPath)
+	})
+	c.mu.Unlock()
+
+	// Project context now spans an extra root
+	c.projectContextManager.InvalidateCache()
+
+	return nil
+}
+
+// RemoveDirectory removes a previously added workspace root.
+func (c *ClaudeCodeClient) RemoveDirectory(ctx context.Context, path string) error {
+	c.mu.Lock()
+
+	if c.closed {
+		c.mu.Unlock()
+		return sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
+	}
+
+	absPath := path
+	if !filepath.IsAbs(absPath) {
+		absPath = filepath.Join(c.workingDir, absPath)
+	}
+	absPath = filepath.Clean(absPath)
+
+	index := -1
+	for i, dir := range c.addDirs {
+		if dir == absPath {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		c.mu.Unlock()
+		return sdkerrors.NewValidationError("path", path, "added directory", "directory has not been added")
+	}
+
+	c.addDirs = append(c.addDirs[:index], c.addDirs[index+1:]...)
+	c.updateSettings(func(config *types.ClaudeCodeConfig) {
+		config.AddDirs = append([]string(nil), c.addDirs...)
+	})
+	c.mu.Unlock()
+
+	c.projectContextManager.InvalidateCache()
+
+	return nil
+}
+
+// AdditionalDirectories returns the workspace roots added besides the working directory.
+func (c *ClaudeCodeClient) AdditionalDirectories() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]string(nil), c.addDirs...)
+}
+
+// resolveAdditionalDirectory validates a workspace root and returns its absolute path.
+func resolveAdditionalDirectory(workingDir, path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", sdkerrors.NewValidationError("path", path, "required", "directory path cannot be empty")
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workingDir, path)
 	}
 
-	// Convert to absolute path
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "PATH_ABS", "failed to convert to absolute path")
+		return "", sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "PATH_ABS", "failed to convert to absolute path")
 	}
 
-	c.workingDir = absPath
-	c.config.WorkingDirectory = absPath
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", sdkerrors.NewValidationError("path", absPath, "exists", "directory does not exist")
+	}
+	if !info.IsDir() {
+		return "", sdkerrors.NewValidationError("path", absPath, "directory", "path is not a directory")
+	}
 
-	return nil
+	return absPath, nil
+}
+
+// containsString reports whether values contains target.
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
 }
 
 // REMOVED: Complex project analysis methods beyond official SDK scope
@@ -437,16 +818,36 @@ func (c *ClaudeCodeClient) GetEnhancedProjectContext(ctx context.Context) (*type
 	return c.projectContextManager.GetEnhancedProjectContext(ctx)
 }
 
-// InvalidateProjectContextCache invalidates the cached project context.
+// InvalidateProjectContextCache invalidates the cached project context. The
+// cache already notices changes to manifests, git state and indexed sources.
 func (c *ClaudeCodeClient) InvalidateProjectContextCache() {
 	c.projectContextManager.InvalidateCache()
 }
 
-// SetProjectContextCacheDuration sets the cache duration for project context.
+// SetProjectContextCacheDuration caps how long the project context is cached;
+// zero caches it until a watched file changes.
 func (c *ClaudeCodeClient) SetProjectContextCacheDuration(duration time.Duration) {
 	c.projectContextManager.SetCacheDuration(duration)
 }
 
+// FocusPackage narrows the enhanced project context to one package of a
+// monorepo, given its name or path; see ProjectContextManager.FocusPackage.
+func (c *ClaudeCodeClient) FocusPackage(nameOrPath string) error {
+	return c.projectContextManager.FocusPackage(nameOrPath)
+}
+
+// GetPackageContext returns the enhanced context of one package of a
+// monorepo, given its name or path.
+func (c *ClaudeCodeClient) GetPackageContext(ctx context.Context, nameOrPath string) (*types.ProjectContext, error) {
+	return c.projectContextManager.PackageContext(ctx, nameOrPath)
+}
+
+// RegisterProjectDetector adds a detector to this client's enhanced project
+// context only. Use RegisterDetector to add one to every client.
+func (c *ClaudeCodeClient) RegisterProjectDetector(detector Detector) error {
+	return c.projectContextManager.RegisterDetector(detector)
+}
+
 // GetProjectContextCacheInfo returns information about the project context cache status.
 func (c *ClaudeCodeClient) GetProjectContextCacheInfo() map[string]any {
 	return c.projectContextManager.GetCacheInfo()
@@ -483,8 +884,8 @@ func (c *ClaudeCodeClient) Sessions() *ClaudeCodeSessionManager {
 }
 
 // CreateSession creates a new conversation session with Claude Code.
-// The sessionID must be a valid UUID or empty (in which case a new UUID is generated).
-// Non-UUID session IDs will be automatically converted to a deterministic UUID.
+// An empty sessionID generates a new UUID. Non-UUID IDs are treated as
+// external IDs and mapped to UUIDs; see ClaudeCodeSessionManager.CreateSession.
 func (c *ClaudeCodeClient) CreateSession(ctx context.Context, sessionID string) (*ClaudeCodeSession, error) {
 	return c.sessionManager.CreateSession(ctx, sessionID)
 }
@@ -501,7 +902,7 @@ func (c *ClaudeCodeClient) GenerateSessionID() string {
 	return GenerateSessionID()
 }
 
-// GetSession retrieves an existing session by ID.
+// GetSession retrieves an existing session by its session ID or external ID.
 func (c *ClaudeCodeClient) GetSession(sessionID string) (*ClaudeCodeSession, error) {
 	return c.sessionManager.GetSession(sessionID)
 }
@@ -513,6 +914,21 @@ func (c *ClaudeCodeClient) ListSessions() []string {
 
 // buildClaudeArgs constructs command-line arguments for the claude CLI based on the request.
 func (c *ClaudeCodeClient) buildClaudeArgs(request *types.QueryRequest, streaming bool) ([]string, error) {
+	return c.buildClaudeArgsForSession(request, streaming, c.sessionID)
+}
+
+// buildClaudeArgsForSession builds claude arguments that run in the given session.
+func (c *ClaudeCodeClient) buildClaudeArgsForSession(request *types.QueryRequest, streaming bool, sessionID string) ([]string, error) {
+	scope := c.defaultScope()
+	scope.sessionID = sessionID
+	return c.buildScopedClaudeArgs(request, streaming, scope)
+}
+
+// buildScopedClaudeArgs builds claude arguments that run in the scope's
+// session and directories.
+func (c *ClaudeCodeClient) buildScopedClaudeArgs(request *types.QueryRequest, streaming bool, scope queryScope) ([]string, error) {
+	config := c.settings()
+	sessionID := scope.sessionID
 	args := make([]string, 0)
 
 	// Add print flag for non-interactive use
@@ -523,47 +939,150 @@ func (c *ClaudeCodeClient) buildClaudeArgs(request *types.QueryRequest, streamin
 	// Add model selection
 	if request.Model != "" {
 		args = append(args, "--model", request.Model)
-	} else if c.config.Model != "" {
-		args = append(args, "--model", c.config.Model)
+	} else if config.Model != "" {
+		args = append(args, "--model", config.Model)
 	}
 
 	// Add session ID for conversation persistence
-	if c.sessionID != "" {
-		args = append(args, "--session-id", c.sessionID)
+	if sessionID != "" {
+		args = append(args, "--session-id", sessionID)
+	}
+
+	// Add additional workspace roots
+	for _, dir := range scope.addDirs {
+		args = append(args, "--add-dir", dir)
 	}
 
 	// Note: Claude CLI does not have a --stream flag
 	// Streaming is handled differently based on --print and --output-format flags
 
 	// Add MCP configuration if there are enabled servers
+	if violations := c.mcpViolations(); len(violations) > 0 {
+		return nil, sdkerrors.NewValidationErrorWithViolations(violations)
+	}
 	if enabledServers := c.mcpManager.GetEnabledServers(); len(enabledServers) > 0 {
-		configPath := filepath.Join(c.workingDir, ".claude", "mcp.json")
+		configPath := filepath.Join(scope.workingDir, ".claude", "mcp.json")
 		if _, err := os.Stat(configPath); err == nil {
 			args = append(args, "--mcp-config", configPath)
 		}
 	}
+	localTools, err := c.localToolArgs(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, localTools...)
 
 	// Add system prompt if provided
 	// Claude CLI uses --append-system-prompt instead of --system
-	if request.System != "" {
-		args = append(args, "--append-system-prompt", request.System)
+	if system := c.systemPrompt(request.System); system != "" {
+		args = append(args, "--append-system-prompt", c.redact(system))
 	}
 
 	// Note: Claude CLI does not support --max-tokens or --temperature flags
 	// These settings would need to be configured differently or omitted
 
-	// Convert messages to prompt
-	if len(request.Messages) > 0 {
+	// Install SDK hooks such as the Bash sandbox, and web domain permissions
+	hooks, err := c.hookArgs(scope.workingDir)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, hooks...)
+
+	// Add the scope's tool rules, and web domain and .claudeignore permission rules
+	permissions, err := c.permissionArgs(scope.workingDir, scope.allowedTools, scope.disallowedTools)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, permissions...)
+
+	// Add passthrough flags for CLI features the SDK does not model yet
+	args = append(args, extraArgs(config.ExtraArgs)...)
+
+	// Convert messages to prompt; with attachments or the control protocol
+	// it is written to stdin
+	if c.promptOnStdin(request, streaming) {
+		if streaming {
+			args = append(args, "--print")
+		}
+		args = append(args, attachmentArgs...)
+	} else if len(request.Messages) > 0 {
 		prompt, err := c.messagesToPrompt(request.Messages)
 		if err != nil {
 			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "MESSAGE_CONVERSION", "failed to convert messages to prompt")
 		}
-		args = append(args, prompt)
+		args = append(args, c.redact(prompt))
+	}
+
+	return args, nil
+}
+
+// permissionArgs returns the --allowedTools and --disallowedTools flags for
+// the allowed and disallowed tools, the web domain policy and, when enforced,
+// the .claudeignore of workingDir.
+func (c *ClaudeCodeClient) permissionArgs(workingDir string, allowed, disallowed []string) ([]string, error) {
+	allowed, deny, err := c.permissionRules(workingDir, allowed, disallowed)
+	if err != nil {
+		return nil, err
 	}
 
+	var args []string
+	if len(allowed) > 0 {
+		args = append(args, "--allowedTools", strings.Join(allowed, ","))
+	}
+	if len(deny) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(deny, ","))
+	}
 	return args, nil
 }
 
+// permissionRules returns the CLI allow and deny rules for the allowed and
+// disallowed tools, with wildcards expanded, followed by the rules of the
+// web domain policy, the ToolRegistry and, when enforced, the .claudeignore
+// of workingDir.
+func (c *ClaudeCodeClient) permissionRules(workingDir string, allowed, disallowed []string) ([]string, []string, error) {
+	config := c.settings()
+	allow, deny := config.WebDomains.PermissionRules()
+	allowed = append(expandToolRules(allowed), allow...)
+	if c.hasLocalTools() && !containsString(allowed, "mcp__"+LocalToolServer) {
+		allowed = append(allowed, "mcp__"+LocalToolServer)
+	}
+	deny = append(expandToolRules(disallowed), deny...)
+
+	if config.EnforceClaudeIgnore {
+		matcher, err := LoadIgnoreMatcher(workingDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		deny = append(deny, matcher.denyRules()...)
+	}
+	return allowed, deny, nil
+}
+
+// extraArgs converts passthrough flags to CLI arguments in a stable order. A nil
+// value produces a boolean flag.
+func extraArgs(extra map[string]*string) []string {
+	if len(extra) == 0 {
+		return nil
+	}
+
+	flags := make([]string, 0, len(extra))
+	for flag := range extra {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+
+	args := make([]string, 0, len(extra)*2)
+	for _, flag := range flags {
+		name := "--" + strings.TrimLeft(flag, "-")
+		if value := extra[flag]; value != nil {
+			args = append(args, name, *value)
+		} else {
+			args = append(args, name)
+		}
+	}
+	return args
+}
+
 // messagesToPrompt converts a slice of messages into a single prompt string for claude CLI.
 func (c *ClaudeCodeClient) messagesToPrompt(messages []types.Message) (string, error) {
 	if len(messages) == 0 {
@@ -617,38 +1136,56 @@ func (c *ClaudeCodeClient) extractTextContent(content any) string {
 
 // buildEnvironment constructs environment variables for the claude subprocess.
 func (c *ClaudeCodeClient) buildEnvironment() []string {
+	config := c.settings()
 	env := make([]string, 0)
 
 	// Handle authentication based on configured method
-	switch c.config.AuthMethod {
+	switch config.AuthMethod {
 	case types.AuthTypeAPIKey:
 		// Add API key from config for API key authentication
-		if c.config.APIKey != "" {
-			env = append(env, "ANTHROPIC_API_KEY="+c.config.APIKey)
+		if config.APIKey != "" {
+			env = append(env, "ANTHROPIC_API_KEY="+config.APIKey)
 		}
 	case types.AuthTypeSubscription:
 		// For subscription auth, the CLI handles authentication automatically
 		// No additional environment variables needed
 	default:
 		// Fallback: if API key is available, use it
-		if c.config.APIKey != "" {
-			env = append(env, "ANTHROPIC_API_KEY="+c.config.APIKey)
+		if config.APIKey != "" {
+			env = append(env, "ANTHROPIC_API_KEY="+config.APIKey)
 		}
 	}
 
+	// Route traffic through the configured proxy and gateway, trusting the
+	// configured certificates; custom variables may override them
+	env = append(env, config.Proxy.Env()...)
+	env = append(env, config.Gateway.Env()...)
+	env = append(env, config.CertificateTrust.Env()...)
+
 	// Add custom environment variables
-	for key, value := range c.config.Environment {
+	for key, value := range config.Environment {
 		env = append(env, fmt.Sprintf("%s=%s", key, value))
 	}
 
+	// An isolated config directory overrides any inherited or custom one
+	if config.IsolatedConfigDir != "" {
+		env = append(env, "CLAUDE_CONFIG_DIR="+config.IsolatedConfigDir)
+	}
+
+	// The approval hook reads its token from the environment the CLI passes
+	// on, which unlike the command line other local users cannot read
+	if token := c.approvalToken(); token != "" {
+		env = append(env, approvalTokenEnv+"="+token)
+	}
+
 	return env
 }
 
 // parseClaudeOutput parses the output from claude CLI into a QueryResponse.
-func (c *ClaudeCodeClient) parseClaudeOutput(output string) (*types.QueryResponse, error) {
+func (c *ClaudeCodeClient) parseClaudeOutput(output []byte) (*types.QueryResponse, error) {
 	// Try to parse as JSON first (in case of structured output)
 	var jsonResponse types.QueryResponse
-	if err := json.Unmarshal([]byte(output), &jsonResponse); err == nil {
+	if err := json.Unmarshal(output, &jsonResponse); err == nil {
 		return &jsonResponse, nil
 	}
 
@@ -657,7 +1194,7 @@ func (c *ClaudeCodeClient) parseClaudeOutput(output string) (*types.QueryRespons
 		Content: []types.ContentBlock{
 			{
 				Type: "text",
-				Text: strings.TrimSpace(output),
+				Text: string(bytes.TrimSpace(output)),
 			},
 		},
 		StopReason: "end_turn",
@@ -728,62 +1265,207 @@ func generateSessionID() string {
 	)
 }
 
+// maxCLILineSize bounds a single line of CLI output. Tool results carry whole
+// files, twice for Read results, so a 100MB file needs a line of over 200MB.
+const maxCLILineSize = 256 * 1024 * 1024
+
 // claudeCodeQueryStream implements QueryStream for Claude Code subprocess streaming.
 type claudeCodeQueryStream struct {
-	cmd       *exec.Cmd
+	process   *cliProcess
 	stdout    io.ReadCloser
 	ctx       context.Context
 	processID string
 	client    *ClaudeCodeClient
-	scanner   *bufio.Scanner
+	lines     *lineReader
 	closed    bool
 	mu        sync.Mutex
+
+	// Arguments, stdin, request and scope used to restart the process on reconnect
+	args       []string
+	input      []byte
+	request    *types.QueryRequest
+	scope      queryScope
+	sessionID  string
+	reconnects int
+
+	// Webhook events for this query (nil when disabled)
+	webhooks *webhookTracker
+
+	// Per-tool deadlines for this query (nil when disabled)
+	deadlines *toolDeadlines
+
+	// File access events for this query (nil when disabled)
+	files *fileAccessTracker
+
+	// Control requests for this query's process (nil when disabled)
+	control *controlChannel
+
+	// Liveness state, guarded by stateMu because Recv holds mu while blocked on reads
+	stateMu      sync.Mutex
+	exited       chan struct{}
+	waitErr      error
+	lastActivity time.Time
+	disconnect   *types.DisconnectEvent
+}
+
+// startProcess spawns the claude process for this stream.
+func (s *claudeCodeQueryStream) startProcess(args []string) error {
+	c := s.client
+
+	ctx := s.ctx
+	if s.control != nil {
+		ctx = withControl(ctx, s.control)
+	}
+	process, err := c.startCLI(ctx, args, s.input, s.request, false)
+	if err != nil {
+		return err
+	}
+
+	// Track the process so Interrupt and Close can terminate it
+	if err := c.trackProcess(s.processID, process); err != nil {
+		_ = process.stdout.Close() // Ignore error during cleanup
+		_ = process.Wait()         // Ignore error, the process was killed
+		return err
+	}
+
+	exited := make(chan struct{})
+
+	s.stateMu.Lock()
+	s.process = process
+	s.stdout = process.stdout
+	s.lines = nil
+	s.exited = exited
+	s.waitErr = nil
+	s.lastActivity = time.Now()
+	s.disconnect = nil
+	s.stateMu.Unlock()
+
+	// Reap the process as soon as it exits so liveness checks can observe it
+	go func() {
+		err := process.Wait()
+		s.stateMu.Lock()
+		s.waitErr = err
+		s.stateMu.Unlock()
+		close(exited)
+	}()
+
+	return nil
 }
 
 // Recv receives the next chunk from the streaming Claude Code process.
 func (s *claudeCodeQueryStream) Recv() (*types.StreamChunk, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if s.closed {
-		return nil, sdkerrors.NewInternalError("STREAM_CLOSED", "stream has been closed")
+	chunk, line, err := s.recv()
+	if err != nil || chunk.Done {
+		// The process has exited, so control requests can no longer be answered
+		s.control.close()
+
+		// A tool that overran its deadline interrupted the process
+		if timeoutErr := s.deadlines.err(); timeoutErr != nil {
+			chunk, err = nil, timeoutErr
+		}
 	}
 
-	// Check context cancellation
-	select {
-	case <-s.ctx.Done():
-		return nil, sdkerrors.WrapError(s.ctx.Err(), sdkerrors.CategoryNetwork, "CONTEXT_CANCELED", "request context canceled")
+	switch {
+	case err != nil:
+		s.webhooks.fail(err)
+	case chunk.Done:
+		chunk.Metadata = withMetadataValue(s.ctx, chunk.Metadata)
+		s.webhooks.complete(&types.WebhookResult{})
 	default:
+		s.webhooks.observeLine(line)
+		s.deadlines.observeLine(line)
+		s.files.observeLine(line)
+		s.control.observeLine(line)
+		s.client.observeMCPInit(line)
+		s.client.publishFrame(line)
 	}
+	return chunk, err
+}
 
-	// Initialize scanner if not already done
-	if s.scanner == nil {
-		s.scanner = bufio.NewScanner(s.stdout)
-		s.scanner.Buffer(make([]byte, 64*1024), 64*1024)
-	}
+// recv reads the next line from the process, reconnecting if configured. It
+// also returns the line's bytes so observers can decode it without copying
+// the chunk's content again.
+func (s *claudeCodeQueryStream) recv() (*types.StreamChunk, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Read the next line
-	if !s.scanner.Scan() {
-		// Check for scanning errors
-		if err := s.scanner.Err(); err != nil {
-			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryNetwork, "STREAM_READ", "failed to read from claude process")
+	for {
+		if s.closed {
+			return nil, nil, sdkerrors.NewInternalError("STREAM_CLOSED", "stream has been closed")
 		}
-		// Stream ended, check if process completed successfully
-		if err := s.cmd.Wait(); err != nil {
-			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PROCESS_ERROR", "claude process failed")
+
+		// Check context cancellation
+		select {
+		case <-s.ctx.Done():
+			return nil, nil, sdkerrors.WrapError(s.ctx.Err(), sdkerrors.CategoryNetwork, "CONTEXT_CANCELED", "request context canceled")
+		default:
 		}
-		return &types.StreamChunk{Done: true}, nil
-	}
 
-	line := s.scanner.Text()
+		// Initialize the reader if not already done
+		if s.lines == nil {
+			s.lines = s.client.cliLineReader(s.stdout)
+		}
 
-	// Parse the line into a stream chunk
-	chunk := &types.StreamChunk{
-		Content: line + "\n",
-		Done:    false,
-	}
+		// Read the next line
+		line, readErr := s.lines.next()
+		if readErr == nil {
+			s.touch()
 
-	return chunk, nil
+			// Parse the line into a stream chunk
+			chunk := &types.StreamChunk{
+				Content: lineString(line),
+				Done:    false,
+			}
+			if boundary, ok := types.ParseCompactBoundary(line); ok {
+				chunk.Type = types.ChunkTypeCompactBoundary
+				chunk.CompactBoundary = boundary
+			}
+			chunk.Images = types.ParseImages(line)
+
+			return chunk, line, nil
+		}
+
+		// Check for read errors. The process may be blocked writing the
+		// rest of its output, so stop it before waiting.
+		if readErr == io.EOF {
+			readErr = nil
+		} else {
+			s.killProcess()
+		}
+
+		// Stream ended, wait for the process to finish
+		<-s.exited
+		_, waitErr := s.exitStatus()
+
+		if s.ctx.Err() != nil {
+			return nil, nil, sdkerrors.WrapError(s.ctx.Err(), sdkerrors.CategoryNetwork, "CONTEXT_CANCELED", "request context canceled")
+		}
+
+		// Handle unexpected exits when liveness checks are enabled
+		if s.client.connMonitor != nil {
+			if waitErr != nil {
+				s.markDisconnected(types.DisconnectReasonProcessExited, time.Now())
+			}
+
+			if event := s.disconnectEvent(); event != nil {
+				if !event.Reconnecting {
+					return nil, nil, sdkerrors.NewConnectionError("claude process "+s.processID, string(event.Reason), waitErr)
+				}
+				if err := s.reconnect(); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+		}
+
+		if readErr != nil {
+			return nil, nil, sdkerrors.WrapError(readErr, sdkerrors.CategoryNetwork, "STREAM_READ", "failed to read from claude process")
+		}
+		if waitErr != nil {
+			return nil, nil, sdkerrors.WrapError(waitErr, sdkerrors.CategoryInternal, "PROCESS_ERROR", "claude process failed")
+		}
+		return &types.StreamChunk{Done: true}, nil, nil
+	}
 }
 
 // Close terminates the streaming Claude Code process and releases resources.
@@ -796,21 +1478,190 @@ func (s *claudeCodeQueryStream) Close() error {
 	}
 
 	s.closed = true
+	s.deadlines.stop()
+
+	// Terminate the process
+	s.killProcess()
 
 	// Close stdout pipe
 	if s.stdout != nil {
 		_ = s.stdout.Close() // Ignore error during cleanup
 	}
 
-	// Terminate the process
-	if s.cmd != nil && s.cmd.Process != nil {
-		_ = s.cmd.Process.Kill() // Ignore error, best effort cleanup
+	// Remove from client's active processes
+	s.client.untrackProcess(s.processID)
+	if s.control != nil {
+		s.client.untrackControl(s.control)
 	}
 
-	// Remove from client's active processes
-	s.client.processMu.Lock()
-	delete(s.client.activeProcesses, s.processID)
-	s.client.processMu.Unlock()
+	if s.client.connMonitor != nil {
+		s.client.connMonitor.untrack(s)
+	}
 
 	return nil
 }
+
+// reconnectPrompt is the user turn a reconnected stream sends to its resumed
+// session, which already holds the original prompt.
+const reconnectPrompt = "Continue from where you left off."
+
+// reconnect restarts the claude process, resuming the stream's session.
+func (s *claudeCodeQueryStream) reconnect() error {
+	s.reconnects++
+
+	if s.stdout != nil {
+		_ = s.stdout.Close() // Ignore error, the old process is gone
+	}
+
+	args, input, err := s.restartArgs()
+	if err == nil {
+		s.input = input
+		err = s.startProcess(args)
+	}
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryNetwork, "RECONNECT_FAILED", "failed to reconnect claude process")
+	}
+
+	return nil
+}
+
+// restartArgs returns the arguments and stdin of a reconnected process. A
+// stream in a session resumes it with reconnectPrompt in place of the
+// original prompt, which the session already received, so the user turn is
+// not sent twice; other streams rerun their request. The arguments are built
+// again rather than reused, since the private local tool config of the
+// previous process was removed when it exited.
+func (s *claudeCodeQueryStream) restartArgs() ([]string, []byte, error) {
+	if s.request == nil {
+		return resumeArgs(s.args, s.sessionID), s.input, nil
+	}
+
+	request := s.request
+	if s.sessionID != "" {
+		continuation := *s.request
+		continuation.Messages = []types.Message{{Role: types.RoleUser, Content: reconnectPrompt}}
+		request = &continuation
+	}
+	args, err := s.client.buildScopedClaudeArgs(request, true, s.scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	input, err := s.client.stdinInput(request, true, s.scope.workingDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resumeArgs(args, s.sessionID), input, nil
+}
+
+// resumeArgs rewrites the session flag so a restarted process resumes the
+// existing conversation instead of trying to create it again.
+func resumeArgs(args []string, sessionID string) []string {
+	resumed := make([]string, len(args))
+	copy(resumed, args)
+
+	if sessionID == "" {
+		return resumed
+	}
+
+	for i := 0; i < len(resumed)-1; i++ {
+		if resumed[i] == "--session-id" && resumed[i+1] == sessionID {
+			resumed[i] = "--resume"
+			break
+		}
+	}
+
+	return resumed
+}
+
+// touch records output activity on the stream.
+func (s *claudeCodeQueryStream) touch() {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	s.lastActivity = time.Now()
+}
+
+// lastActivityTime returns when the stream last produced output.
+func (s *claudeCodeQueryStream) lastActivityTime() time.Time {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	return s.lastActivity
+}
+
+// exitStatus reports whether the current process has exited and its wait error.
+func (s *claudeCodeQueryStream) exitStatus() (bool, error) {
+	s.stateMu.Lock()
+	exited := s.exited
+	s.stateMu.Unlock()
+
+	select {
+	case <-exited:
+		s.stateMu.Lock()
+		defer s.stateMu.Unlock()
+		return true, s.waitErr
+	default:
+		return false, nil
+	}
+}
+
+// markDisconnected records a disconnect of the current process and notifies the
+// OnDisconnect callback. It returns false if the disconnect was already recorded.
+func (s *claudeCodeQueryStream) markDisconnected(reason types.DisconnectReason, now time.Time) bool {
+	monitor := s.client.connMonitor
+	if monitor == nil {
+		return false
+	}
+
+	s.stateMu.Lock()
+	if s.disconnect != nil {
+		s.stateMu.Unlock()
+		return false
+	}
+	event := types.DisconnectEvent{
+		ProcessID:    s.processID,
+		SessionID:    s.sessionID,
+		Reason:       reason,
+		IdleFor:      now.Sub(s.lastActivity),
+		Reconnecting: monitor.config.AutoReconnect && s.reconnects < monitor.maxReconnectAttempts(),
+		Timestamp:    now,
+	}
+	s.disconnect = &event
+	s.stateMu.Unlock()
+
+	monitor.notify(event)
+	return true
+}
+
+// disconnectEvent returns the recorded disconnect for the current process, if any.
+func (s *claudeCodeQueryStream) disconnectEvent() *types.DisconnectEvent {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	return s.disconnect
+}
+
+// abort kills a stalled process and closes its output so blocked readers return
+// even if a grandchild process still holds the write end of the pipe.
+func (s *claudeCodeQueryStream) abort() {
+	s.killProcess()
+
+	s.stateMu.Lock()
+	stdout := s.stdout
+	s.stateMu.Unlock()
+
+	if stdout != nil {
+		_ = stdout.Close() // Ignore error, the reader is being released
+	}
+}
+
+// killProcess terminates the current process without waiting for readers.
+func (s *claudeCodeQueryStream) killProcess() {
+	s.stateMu.Lock()
+	process := s.process
+	s.stateMu.Unlock()
+
+	if process != nil {
+		_ = process.Kill() // Ignore error, best effort cleanup
+	}
+}
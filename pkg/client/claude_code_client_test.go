@@ -3,6 +3,8 @@ package client
 import (
 	"context"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
@@ -166,6 +168,42 @@ func TestBuildClaudeArgs(t *testing.T) {
 	}
 }
 
+func TestBuildClaudeArgs_ExtraArgs(t *testing.T) {
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Claude Code client: %v", err)
+	}
+	defer client.Close()
+
+	effort := "high"
+	client.config.ExtraArgs = map[string]*string{"--effort": &effort, "experimental-flag": nil}
+
+	args, err := client.buildClaudeArgs(&types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}},
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to build claude arguments: %v", err)
+	}
+
+	// Passthrough flags come before the prompt in a stable order
+	tail := strings.Join(args[len(args)-4:], " ")
+	if tail != "--effort high --experimental-flag hi" {
+		t.Errorf("Unexpected argument tail %q", tail)
+	}
+
+	options := &QueryOptions{ExtraArgs: map[string]*string{"effort": nil}}
+	command, err := client.buildQueryCommand(&ClaudeCodeSession{ID: "s"}, &types.Command{Args: []string{"hi"}}, options)
+	if err != nil {
+		t.Fatalf("buildQueryCommand failed: %v", err)
+	}
+	if tail := strings.Join(command[len(command)-3:], " "); tail != "--effort --experimental-flag hi" {
+		t.Errorf("Expected query options to override client flags, got %q", tail)
+	}
+}
+
 func TestMessagesToPrompt(t *testing.T) {
 	tempDir := t.TempDir()
 	config := &types.ClaudeCodeConfig{
@@ -255,7 +293,7 @@ func TestParseClaudeOutput(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			response, err := client.parseClaudeOutput(tt.output)
+			response, err := client.parseClaudeOutput([]byte(tt.output))
 			if err != nil {
 				t.Fatalf("parseClaudeOutput failed: %v", err)
 			}
@@ -449,3 +487,194 @@ func TestClaudeCodeClientIntegration(t *testing.T) {
 		t.Error("Expected content chunk, got done signal immediately")
 	}
 }
+
+func TestClaudeCodeClient_AddDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	sharedDir := filepath.Join(tempDir, "shared")
+	if err := os.Mkdir(sharedDir, 0750); err != nil {
+		t.Fatalf("Failed to create shared dir: %v", err)
+	}
+	libsDir := t.TempDir()
+
+	config := &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: tempDir,
+		AddDirs:          []string{"shared", sharedDir},
+	}
+
+	ctx := context.Background()
+	client, err := NewClaudeCodeClient(ctx, config)
+	if err != nil {
+		t.Fatalf("Failed to create Claude Code client: %v", err)
+	}
+	defer client.Close()
+
+	// Relative and absolute spellings of the same root are deduplicated
+	if dirs := client.AdditionalDirectories(); len(dirs) != 1 || dirs[0] != sharedDir {
+		t.Fatalf("Expected [%s], got %v", sharedDir, dirs)
+	}
+
+	if err := client.AddDirectory(ctx, libsDir); err != nil {
+		t.Fatalf("AddDirectory failed: %v", err)
+	}
+
+	if err := client.AddDirectory(ctx, filepath.Join(tempDir, "missing")); err == nil {
+		t.Error("Expected error for missing directory")
+	}
+
+	filePath := filepath.Join(tempDir, "file.txt")
+	if err := os.WriteFile(filePath, []byte("x"), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := client.AddDirectory(ctx, filePath); err == nil {
+		t.Error("Expected error when adding a file")
+	}
+
+	args, err := client.buildClaudeArgs(&types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}},
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to build args: %v", err)
+	}
+
+	var added []string
+	for i, arg := range args {
+		if arg == "--add-dir" && i+1 < len(args) {
+			added = append(added, args[i+1])
+		}
+	}
+	if len(added) != 2 || added[0] != sharedDir || added[1] != libsDir {
+		t.Errorf("Expected --add-dir for both roots, got %v", added)
+	}
+
+	projectCtx, err := client.GetProjectContext(ctx)
+	if err != nil {
+		t.Fatalf("GetProjectContext failed: %v", err)
+	}
+	if len(projectCtx.AdditionalDirectories) != 2 {
+		t.Errorf("Expected 2 additional directories in project context, got %v", projectCtx.AdditionalDirectories)
+	}
+
+	if err := client.RemoveDirectory(ctx, "shared"); err != nil {
+		t.Fatalf("RemoveDirectory failed: %v", err)
+	}
+	if dirs := client.AdditionalDirectories(); len(dirs) != 1 || dirs[0] != libsDir {
+		t.Errorf("Expected only %s after removal, got %v", libsDir, dirs)
+	}
+	if err := client.RemoveDirectory(ctx, "shared"); err == nil {
+		t.Error("Expected error when removing an unknown directory")
+	}
+
+	// The caller's config keeps the roots it was created with
+	if len(config.AddDirs) != 2 || config.AddDirs[0] != "shared" {
+		t.Errorf("Expected the caller's AddDirs to be unchanged, got %v", config.AddDirs)
+	}
+}
+
+func TestClaudeCodeClient_InvalidAddDirs(t *testing.T) {
+	config := &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+		AddDirs:          []string{"does-not-exist"},
+	}
+
+	if _, err := NewClaudeCodeClient(context.Background(), config); err == nil {
+		t.Error("Expected error for non-existent additional directory")
+	}
+}
+
+func TestClaudeCodeClient_SetWorkingDirectory(t *testing.T) {
+	client := newFakeCLIClient(t, `echo`)
+	ctx := context.Background()
+	original := client.currentWorkingDir()
+
+	var changes []types.WorkingDirectoryChange
+	client.config.OnWorkingDirectoryChange = func(change types.WorkingDirectoryChange) {
+		changes = append(changes, change)
+	}
+	client.config.ProjectMarkers = []string{"go.mod", ".git"}
+
+	file := filepath.Join(original, "notes.txt")
+	plain := t.TempDir()
+	project := t.TempDir()
+	if err := os.WriteFile(file, []byte("notes"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(project, ".git"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, message := range map[string]string{
+		"":                                "cannot be empty",
+		filepath.Join(original, "absent"): "does not exist",
+		file:                              "not a directory",
+		plain:                             "go.mod, .git",
+	} {
+		err := client.SetWorkingDirectory(ctx, path)
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("SetWorkingDirectory(%q): expected error containing %q, got %v", path, message, err)
+		}
+	}
+	if client.currentWorkingDir() != original || len(changes) != 0 {
+		t.Fatalf("Expected rejected paths to leave the client in %s, got %s (%d events)", original, client.currentWorkingDir(), len(changes))
+	}
+
+	before, err := client.GetEnhancedProjectContext(ctx)
+	if err != nil || before.WorkingDirectory != original {
+		t.Fatalf("Unexpected project context %+v (%v)", before, err)
+	}
+
+	if err := client.SetWorkingDirectory(ctx, project); err != nil {
+		t.Fatalf("SetWorkingDirectory failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].From != original || changes[0].To != project || changes[0].Marker != ".git" {
+		t.Errorf("Unexpected change events %+v", changes)
+	}
+
+	after, err := client.GetEnhancedProjectContext(ctx)
+	if err != nil || after.WorkingDirectory != project {
+		t.Errorf("Expected a fresh project context for %s, got %+v (%v)", project, after, err)
+	}
+}
+
+func TestQueryStream_Images(t *testing.T) {
+	client := newFakeCLIClient(t, `
+echo '{"type":"assistant","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"mcp__browser__screenshot","input":{}}]}}'
+echo '{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"image","source":{"type":"base64","media_type":"image/png","data":"iVBORw0KGgo="}}]}]}}'
+echo '{"type":"assistant","message":{"content":[{"type":"text","text":"Here is the chart"},{"type":"image","source":{"type":"url","url":"https://example.com/chart.png"}}]}}'
+echo '{"type":"result","result":"done"}'`)
+
+	stream, err := client.QueryStream(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "take a screenshot"}},
+	})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	var images []types.ImageBlock
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if chunk.Done {
+			break
+		}
+		images = append(images, chunk.Images...)
+	}
+
+	if len(images) != 2 {
+		t.Fatalf("Expected 2 images, got %+v", images)
+	}
+	screenshot := images[0]
+	if screenshot.ToolUseID != "toolu_1" || screenshot.MediaType != "image/png" || screenshot.DataURL() != "data:image/png;base64,iVBORw0KGgo=" {
+		t.Errorf("Unexpected screenshot: %+v", screenshot)
+	}
+	if data, err := screenshot.Bytes(); err != nil || string(data) != "\x89PNG\r\n\x1a\n" {
+		t.Errorf("Screenshot bytes = %q, %v", data, err)
+	}
+	if chart := images[1]; chart.ToolUseID != "" || chart.Source != types.ContentSourceURL || chart.DataURL() != "https://example.com/chart.png" {
+		t.Errorf("Unexpected chart: %+v", chart)
+	}
+}
@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -68,6 +70,18 @@ type ClaudeCodeSessionConfig struct {
 
 	// SessionDirectory where sessions are persisted (default: .claude/sessions)
 	SessionDirectory string
+
+	// AutoCompactTurns compacts a session's conversation before the next query
+	// once this many queries have run since the last compaction (0 disables)
+	AutoCompactTurns int
+
+	// OnCompact is called after a session's conversation has been compacted. It runs
+	// while the session is locked and must not call methods on the session
+	OnCompact func(*types.CompactBoundaryMessage)
+
+	// Registry maps external IDs to random session IDs. Without one, non-UUID
+	// IDs are mapped to deterministic UUIDs
+	Registry SessionRegistry
 }
 
 // DefaultClaudeCodeSessionConfig returns default session configuration.
@@ -83,17 +97,30 @@ func DefaultClaudeCodeSessionConfig() *ClaudeCodeSessionConfig {
 
 // ClaudeCodeSession represents a conversation session with Claude Code.
 type ClaudeCodeSession struct {
-	ID      string
-	client  *ClaudeCodeClient
+	ID     string
+	client *ClaudeCodeClient
+
+	// externalID is the non-UUID ID the session was created with, if any
+	externalID string
+
 	manager *ClaudeCodeSessionManager
 
-	// Session configuration
+	// Session configuration; an empty projectDir and nil addDirs use the
+	// client's directories
 	projectDir string
+	addDirs    []string
 	model      string
 
 	// Session metadata
 	metadata map[string]any
 
+	// started is set once the session has sent its first query
+	started bool
+
+	// Compaction tracking
+	turnsSinceCompact int
+	lastCompact       *types.CompactBoundaryMessage
+
 	// Session lifecycle
 	createdAt  time.Time
 	lastUsedAt time.Time
@@ -127,20 +154,20 @@ func NewClaudeCodeSessionManagerWithConfig(client *ClaudeCodeClient, config *Cla
 	return sm
 }
 
-// CreateSession creates a new Claude Code conversation session.
-// The sessionID must be a valid UUID or empty (in which case a new UUID is generated).
-// Non-UUID session IDs will be automatically converted to a deterministic UUID.
+// CreateSession creates a new Claude Code conversation session, or returns
+// the active session with the same ID. An empty sessionID generates a new
+// UUID. Non-UUID IDs are treated as external IDs: with a Registry they are
+// mapped to a random UUID the registry remembers, and without one they are
+// converted to a deterministic UUID.
 func (sm *ClaudeCodeSessionManager) CreateSession(ctx context.Context, sessionID string) (*ClaudeCodeSession, error) {
-	// Normalize the session ID to ensure it's a valid UUID
-	normalizedID, err := NormalizeSessionID(sessionID)
-	if err != nil {
-		return nil, FormatSessionIDError(sessionID)
-	}
-	sessionID = normalizedID
-
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	sessionID, externalID, err := sm.resolveSessionID(ctx, sessionID, true)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if session already exists
 	if existingSession, exists := sm.sessions[sessionID]; exists {
 		if !existingSession.IsExpired() {
@@ -159,10 +186,10 @@ func (sm *ClaudeCodeSessionManager) CreateSession(ctx context.Context, sessionID
 	// Create new session
 	session := &ClaudeCodeSession{
 		ID:         sessionID,
+		externalID: externalID,
 		client:     sm.client,
 		manager:    sm,
-		projectDir: sm.client.workingDir,
-		model:      sm.client.config.Model,
+		model:      sm.client.settings().Model,
 		metadata:   make(map[string]any),
 		createdAt:  time.Now(),
 		lastUsedAt: time.Now(),
@@ -170,8 +197,11 @@ func (sm *ClaudeCodeSessionManager) CreateSession(ctx context.Context, sessionID
 	}
 
 	// Initialize session metadata
-	session.metadata["project_dir"] = session.projectDir
+	session.metadata["project_dir"] = sm.client.currentWorkingDir()
 	session.metadata["model"] = session.model
+	if externalID != "" {
+		session.metadata["external_id"] = externalID
+	}
 
 	// Get project context for the session (simplified)
 	if projectCtx, err := sm.client.GetProjectContext(ctx); err == nil {
@@ -182,12 +212,16 @@ func (sm *ClaudeCodeSessionManager) CreateSession(ctx context.Context, sessionID
 	return session, nil
 }
 
-// GetSession retrieves an existing session by ID.
+// GetSession retrieves an existing session by its session ID or external ID.
 func (sm *ClaudeCodeSessionManager) GetSession(sessionID string) (*ClaudeCodeSession, error) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
-	session, exists := sm.sessions[sessionID]
+	id, _, err := sm.resolveSessionID(context.Background(), sessionID, false)
+	if err != nil {
+		return nil, err
+	}
+	session, exists := sm.sessions[id]
 	if !exists {
 		return nil, sdkerrors.NewValidationError("sessionID", sessionID, "existing session", "session not found")
 	}
@@ -214,15 +248,21 @@ func (sm *ClaudeCodeSessionManager) ListSessions() []string {
 	return sessionIDs
 }
 
-// CloseSession closes and removes a session.
+// CloseSession closes and removes a session, given its session ID or
+// external ID. The registry keeps the external ID's mapping, so creating a
+// session with it again resumes the conversation.
 func (sm *ClaudeCodeSessionManager) CloseSession(sessionID string) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	session, exists := sm.sessions[sessionID]
+	id, _, err := sm.resolveSessionID(context.Background(), sessionID, false)
+	if err != nil {
+		return err
+	}
+	session, exists := sm.sessions[id]
 	if exists {
 		_ = session.Close() // Ignore error during cleanup
-		delete(sm.sessions, sessionID)
+		delete(sm.sessions, id)
 	}
 
 	return nil
@@ -308,21 +348,20 @@ func (s *ClaudeCodeSession) Query(ctx context.Context, request *types.QueryReque
 	// Update last used time
 	s.lastUsedAt = time.Now()
 
-	// Create a session-aware request
-	sessionRequest := s.buildSessionRequest(request)
+	if err := s.autoCompactLocked(ctx); err != nil {
+		return nil, err
+	}
 
-	// Use the client's session ID for this query
-	originalSessionID := s.client.sessionID
-	s.client.sessionID = s.ID
-	defer func() {
-		s.client.sessionID = originalSessionID
-	}()
+	// Create a session-aware request
+	sessionRequest := s.buildSessionRequest(ctx, request)
 
-	// Send the query
-	response, err := s.client.Query(ctx, sessionRequest)
+	// Send the query in this session's conversation and directories
+	response, err := s.client.Query(s.queryContext(ctx), sessionRequest)
 	if err != nil {
 		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryAPI, "SESSION_QUERY", "session query failed")
 	}
+	s.started = true
+	s.turnsSinceCompact++
 
 	return response, nil
 }
@@ -344,26 +383,22 @@ func (s *ClaudeCodeSession) QueryStream(ctx context.Context, request *types.Quer
 	// Update last used time
 	s.lastUsedAt = time.Now()
 
-	// Create a session-aware request
-	sessionRequest := s.buildSessionRequest(request)
+	if err := s.autoCompactLocked(ctx); err != nil {
+		return nil, err
+	}
 
-	// Use the client's session ID for this query
-	originalSessionID := s.client.sessionID
-	s.client.sessionID = s.ID
+	// Create a session-aware request
+	sessionRequest := s.buildSessionRequest(ctx, request)
 
-	// Send the streaming query
-	stream, err := s.client.QueryStream(ctx, sessionRequest)
+	// Send the streaming query in this session's conversation and directories
+	stream, err := s.client.QueryStream(s.queryContext(ctx), sessionRequest)
 	if err != nil {
-		s.client.sessionID = originalSessionID
 		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryAPI, "SESSION_STREAM", "session streaming query failed")
 	}
+	s.started = true
+	s.turnsSinceCompact++
 
-	// Wrap the stream to restore session ID on close
-	return &claudeCodeSessionStream{
-		QueryStream:       stream,
-		session:           s,
-		originalSessionID: originalSessionID,
-	}, nil
+	return stream, nil
 }
 
 // ExecuteCommand executes a Claude Code command within this session.
@@ -383,15 +418,8 @@ func (s *ClaudeCodeSession) ExecuteCommand(ctx context.Context, cmd *types.Comma
 	// Update last used time
 	s.lastUsedAt = time.Now()
 
-	// Use the client's session ID for this command
-	originalSessionID := s.client.sessionID
-	s.client.sessionID = s.ID
-	defer func() {
-		s.client.sessionID = originalSessionID
-	}()
-
-	// Execute the command
-	return s.client.ExecuteCommand(ctx, cmd)
+	// Execute the command in this session's conversation and directories
+	return s.client.ExecuteCommand(s.queryContext(ctx), cmd)
 }
 
 // ExecuteSlashCommand executes a slash command within this session.
@@ -411,19 +439,101 @@ func (s *ClaudeCodeSession) ExecuteSlashCommand(ctx context.Context, slashComman
 	// Update last used time
 	s.lastUsedAt = time.Now()
 
-	// Use the client's session ID for this command
-	originalSessionID := s.client.sessionID
-	s.client.sessionID = s.ID
-	defer func() {
-		s.client.sessionID = originalSessionID
-	}()
+	// Execute the slash command in this session's conversation and directories
+	return s.client.ExecuteSlashCommand(s.queryContext(ctx), slashCommand)
+}
+
+// Compact asks the CLI to compact (summarize) the session's conversation history
+// so that long sessions stay within the model's context window. Optional
+// instructions guide what the summary should focus on.
+func (s *ClaudeCodeSession) Compact(ctx context.Context, instructions string) (*types.CompactBoundaryMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, sdkerrors.NewInternalError("SESSION_CLOSED", "session has been closed")
+	}
+
+	// Check expiration without calling IsExpired to avoid deadlock
+	if time.Since(s.lastUsedAt) > s.timeout {
+		return nil, sdkerrors.NewInternalError("SESSION_EXPIRED", "session has expired")
+	}
+
+	s.lastUsedAt = time.Now()
+
+	return s.compactLocked(ctx, types.CompactTriggerManual, instructions)
+}
+
+// LastCompaction returns the most recent compaction boundary, or nil if the
+// session has not been compacted.
+func (s *ClaudeCodeSession) LastCompaction() *types.CompactBoundaryMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lastCompact
+}
+
+// TurnsSinceCompact returns the number of queries sent since the last compaction.
+func (s *ClaudeCodeSession) TurnsSinceCompact() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.turnsSinceCompact
+}
 
-	// Execute the slash command
-	return s.client.ExecuteSlashCommand(ctx, slashCommand)
+// autoCompactLocked compacts the session if the auto-compact threshold has been
+// reached. The caller must hold s.mu.
+func (s *ClaudeCodeSession) autoCompactLocked(ctx context.Context) error {
+	if s.manager == nil || s.manager.config.AutoCompactTurns <= 0 {
+		return nil
+	}
+	if s.turnsSinceCompact < s.manager.config.AutoCompactTurns {
+		return nil
+	}
+
+	_, err := s.compactLocked(ctx, types.CompactTriggerAuto, "")
+	return err
+}
+
+// compactLocked runs the /compact command for this session. The caller must hold s.mu.
+func (s *ClaudeCodeSession) compactLocked(ctx context.Context, trigger types.CompactTrigger, instructions string) (*types.CompactBoundaryMessage, error) {
+	prompt := "/compact"
+	if instructions != "" {
+		prompt += " " + instructions
+	}
+
+	response, err := s.client.Query(s.queryContext(ctx), s.buildSessionRequest(ctx, &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: prompt}},
+	}))
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryAPI, "SESSION_COMPACT", "session compaction failed")
+	}
+
+	boundary := &types.CompactBoundaryMessage{
+		SessionID: s.ID,
+		Trigger:   trigger,
+		Timestamp: time.Now(),
+	}
+	var summary strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			summary.WriteString(block.Text)
+		}
+	}
+	boundary.Summary = summary.String()
+
+	s.turnsSinceCompact = 0
+	s.lastCompact = boundary
+
+	if s.manager != nil && s.manager.config.OnCompact != nil {
+		s.manager.config.OnCompact(boundary)
+	}
+
+	return boundary, nil
 }
 
 // buildSessionRequest creates a request configured for this session.
-func (s *ClaudeCodeSession) buildSessionRequest(request *types.QueryRequest) *types.QueryRequest {
+func (s *ClaudeCodeSession) buildSessionRequest(ctx context.Context, request *types.QueryRequest) *types.QueryRequest {
 	// Create a copy of the request
 	sessionRequest := *request
 
@@ -432,6 +542,11 @@ func (s *ClaudeCodeSession) buildSessionRequest(request *types.QueryRequest) *ty
 		sessionRequest.Model = s.model
 	}
 
+	// Start a new conversation with what was remembered from earlier ones
+	if !s.started {
+		sessionRequest.System = s.client.withMemories(ctx, sessionRequest.System, lastUserMessage(request))
+	}
+
 	return &sessionRequest
 }
 
@@ -456,15 +571,21 @@ func (s *ClaudeCodeSession) SetMetadata(key string, value any) {
 	s.metadata[key] = value
 }
 
-// GetProjectDirectory returns the project directory for this session.
+// GetProjectDirectory returns the directory this session's queries run in:
+// the one set with SetProjectDirectory, or the client's working directory.
 func (s *ClaudeCodeSession) GetProjectDirectory() string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	if s.projectDir == "" && s.client != nil {
+		return s.client.currentWorkingDir()
+	}
 	return s.projectDir
 }
 
-// SetProjectDirectory changes the project directory for this session.
+// SetProjectDirectory makes this session's queries run in dir instead of the
+// client's working directory, so one client can serve several checkouts
+// concurrently. The directory must exist.
 func (s *ClaudeCodeSession) SetProjectDirectory(dir string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -473,6 +594,9 @@ func (s *ClaudeCodeSession) SetProjectDirectory(dir string) error {
 	if err != nil {
 		return sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "PROJECT_DIR", "failed to resolve project directory")
 	}
+	if info, err := os.Stat(absDir); err != nil || !info.IsDir() {
+		return sdkerrors.NewValidationError("path", dir, "directory", "project directory does not exist or is not a directory")
+	}
 
 	s.projectDir = absDir
 	s.metadata["project_dir"] = absDir
@@ -480,6 +604,62 @@ func (s *ClaudeCodeSession) SetProjectDirectory(dir string) error {
 	return nil
 }
 
+// AdditionalDirectories returns the workspace roots this session's queries
+// may access besides the project directory.
+func (s *ClaudeCodeSession) AdditionalDirectories() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.addDirs == nil && s.client != nil {
+		return s.client.AdditionalDirectories()
+	}
+	return append([]string(nil), s.addDirs...)
+}
+
+// SetAdditionalDirectories replaces the client's additional workspace roots
+// for this session's queries. Relative paths are resolved against the
+// session's project directory; calling it with no directories gives the
+// session none.
+func (s *ClaudeCodeSession) SetAdditionalDirectories(dirs ...string) error {
+	base := s.GetProjectDirectory()
+
+	resolved := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		absDir, err := resolveAdditionalDirectory(base, dir)
+		if err != nil {
+			return err
+		}
+		if !containsString(resolved, absDir) {
+			resolved = append(resolved, absDir)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addDirs = resolved
+	return nil
+}
+
+// queryContext attaches this session's conversation and directories to ctx.
+// The caller must hold s.mu.
+func (s *ClaudeCodeSession) queryContext(ctx context.Context) context.Context {
+	return withQueryScope(ctx, s.scopeLocked(s.client.defaultScope()))
+}
+
+// scopeLocked applies this session's ID and directories to the client's
+// defaults. The caller must hold s.mu.
+func (s *ClaudeCodeSession) scopeLocked(defaults queryScope) queryScope {
+	scope := defaults
+	scope.sessionID = s.ID
+	if s.projectDir != "" {
+		scope.workingDir = s.projectDir
+	}
+	if s.addDirs != nil {
+		scope.addDirs = s.addDirs
+	}
+	return scope
+}
+
 // IsExpired checks if the session has expired.
 func (s *ClaudeCodeSession) IsExpired() bool {
 	s.mu.RLock()
@@ -541,19 +721,3 @@ func (s *ClaudeCodeSession) GetInfo() (*types.SimpleSessionInfo, error) {
 
 	return info, nil
 }
-
-// claudeCodeSessionStream wraps a QueryStream for session management.
-type claudeCodeSessionStream struct {
-	types.QueryStream
-	session           *ClaudeCodeSession
-	originalSessionID string
-}
-
-// Close closes the stream and restores the original session ID.
-func (css *claudeCodeSessionStream) Close() error {
-	// Restore original session ID
-	css.session.client.sessionID = css.originalSessionID
-
-	// Close the underlying stream
-	return css.QueryStream.Close()
-}
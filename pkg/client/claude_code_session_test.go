@@ -3,6 +3,12 @@ package client
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -581,3 +587,207 @@ func TestClaudeCodeClient_SessionIntegration(t *testing.T) {
 		t.Errorf("Expected remaining session to be %s, got %s", session1.ID, sessions[0])
 	}
 }
+
+// newFakeCLIClient creates a test client whose CLI is a shell script. The
+// script's last argument (the prompt) is available as $prompt.
+func newFakeCLIClient(t *testing.T, script string) *ClaudeCodeClient {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake CLI tests rely on a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	cliPath := filepath.Join(dir, "claude")
+	content := "#!/bin/sh\nfor prompt in \"$@\"; do :; done\n" + script + "\n"
+	if err := os.WriteFile(cliPath, []byte(content), 0700); err != nil {
+		t.Fatalf("Failed to write fake CLI: %v", err)
+	}
+
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: dir,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	client.claudeCodeCmd = cliPath
+	return client
+}
+
+func TestClaudeCodeSession_Compact(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+
+	session, err := client.CreateSession(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}
+	if _, err := session.Query(context.Background(), request); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if session.TurnsSinceCompact() != 1 {
+		t.Errorf("Expected 1 turn since compaction, got %d", session.TurnsSinceCompact())
+	}
+
+	boundary, err := session.Compact(context.Background(), "focus on the API")
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+
+	if boundary.Trigger != types.CompactTriggerManual || boundary.SessionID != session.ID {
+		t.Errorf("Unexpected boundary: %+v", boundary)
+	}
+	if boundary.Summary != "handled /compact focus on the API" {
+		t.Errorf("Expected /compact to be sent with instructions, got %q", boundary.Summary)
+	}
+	if session.TurnsSinceCompact() != 0 || session.LastCompaction() != boundary {
+		t.Error("Expected compaction to reset the turn counter and be recorded")
+	}
+}
+
+func TestClaudeCodeSession_AutoCompact(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "$prompt"`)
+
+	var boundaries []*types.CompactBoundaryMessage
+	config := DefaultClaudeCodeSessionConfig()
+	config.AutoCompactTurns = 2
+	config.OnCompact = func(boundary *types.CompactBoundaryMessage) {
+		boundaries = append(boundaries, boundary)
+	}
+	manager := NewClaudeCodeSessionManagerWithConfig(client, config)
+	defer manager.Close()
+
+	session, err := manager.CreateSession(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "next"}}}
+	for i := 0; i < 3; i++ {
+		if _, err := session.Query(context.Background(), request); err != nil {
+			t.Fatalf("Query %d failed: %v", i, err)
+		}
+	}
+
+	if len(boundaries) != 1 || boundaries[0].Trigger != types.CompactTriggerAuto {
+		t.Fatalf("Expected one automatic compaction, got %+v", boundaries)
+	}
+	if session.TurnsSinceCompact() != 1 {
+		t.Errorf("Expected 1 turn since compaction, got %d", session.TurnsSinceCompact())
+	}
+}
+
+func TestClaudeCodeSession_Directories(t *testing.T) {
+	client := newFakeCLIClient(t, `args="$*"; echo "$(pwd) ${args%% *}"; case "$args" in *--add-dir*) echo "add ${args#*--add-dir }";; esac`)
+	ctx := context.Background()
+	clientDir := client.currentWorkingDir()
+
+	checkouts := []string{t.TempDir(), t.TempDir()}
+	shared := t.TempDir()
+	sessions := make([]*ClaudeCodeSession, len(checkouts))
+	for i, dir := range checkouts {
+		session, err := client.CreateSession(ctx, "")
+		if err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		if err := session.SetProjectDirectory(dir); err != nil {
+			t.Fatalf("SetProjectDirectory failed: %v", err)
+		}
+		sessions[i] = session
+	}
+	if err := sessions[1].SetAdditionalDirectories(shared); err != nil {
+		t.Fatalf("SetAdditionalDirectories failed: %v", err)
+	}
+
+	// Queries in different checkouts run concurrently without touching the client
+	outputs := make([]string, len(sessions))
+	var wg sync.WaitGroup
+	for i, session := range sessions {
+		wg.Add(1)
+		go func(i int, session *ClaudeCodeSession) {
+			defer wg.Done()
+			response, err := session.Query(ctx, &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "where"}}})
+			if err != nil {
+				t.Errorf("Query failed: %v", err)
+				return
+			}
+			outputs[i] = response.Content[0].Text
+		}(i, session)
+	}
+	wg.Wait()
+
+	for i, dir := range checkouts {
+		resolved, _ := filepath.EvalSymlinks(dir)
+		if !strings.HasPrefix(outputs[i], resolved+" ") && !strings.HasPrefix(outputs[i], dir+" ") {
+			t.Errorf("Expected session %d to run in %s, got %q", i, dir, outputs[i])
+		}
+	}
+	if strings.Contains(outputs[0], "add ") {
+		t.Errorf("Expected session 0 to inherit no additional directories, got %q", outputs[0])
+	}
+	if !strings.Contains(outputs[1], "add "+shared) {
+		t.Errorf("Expected session 1 to pass --add-dir %s, got %q", shared, outputs[1])
+	}
+
+	if client.currentWorkingDir() != clientDir {
+		t.Errorf("Expected the client's directory to stay %s, got %s", clientDir, client.currentWorkingDir())
+	}
+	if sessions[1].GetProjectDirectory() != checkouts[1] || !reflect.DeepEqual(sessions[1].AdditionalDirectories(), []string{shared}) {
+		t.Errorf("Unexpected session directories %s %v", sessions[1].GetProjectDirectory(), sessions[1].AdditionalDirectories())
+	}
+
+	if err := sessions[0].SetProjectDirectory(filepath.Join(clientDir, "missing")); err == nil {
+		t.Error("Expected a missing project directory to be rejected")
+	}
+}
+
+func TestClaudeCodeSession_ProjectMCPConfig(t *testing.T) {
+	client := newFakeCLIClient(t, `while [ $# -gt 0 ]; do [ "$1" = --mcp-config ] && echo "$2"; shift; done`)
+	ctx := context.Background()
+	if err := client.mcpManager.AddServer("filesystem", &types.MCPServerConfig{Command: "npx", Enabled: true}); err != nil {
+		t.Fatalf("AddServer failed: %v", err)
+	}
+
+	// Only the session's checkout has a project MCP configuration
+	dir := t.TempDir()
+	writeTestFile(t, dir, ".claude/mcp.json", `{"mcpServers":{}}`)
+	configPath := filepath.Join(dir, ".claude", "mcp.json")
+
+	session, err := client.CreateSession(ctx, "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := session.SetProjectDirectory(dir); err != nil {
+		t.Fatalf("SetProjectDirectory failed: %v", err)
+	}
+
+	response, err := session.Query(ctx, &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if output := response.Content[0].Text; !strings.Contains(output, configPath) {
+		t.Errorf("Expected the session to pass --mcp-config %s, got %q", configPath, output)
+	}
+}
+
+func TestQueryMessages_CWD(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: $(pwd)"`)
+	dir := t.TempDir()
+
+	result, err := client.QueryMessagesSync(context.Background(), "where", &QueryOptions{CWD: dir})
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+	last := result.Messages[len(result.Messages)-1]
+	if resolved, _ := filepath.EvalSymlinks(dir); last.Content != dir && last.Content != resolved {
+		t.Errorf("Expected the query to run in %s, got %q", dir, last.Content)
+	}
+
+	if _, err := client.QueryMessages(context.Background(), "where", &QueryOptions{CWD: filepath.Join(dir, "missing")}); err == nil {
+		t.Error("Expected a missing CWD to be rejected")
+	}
+}
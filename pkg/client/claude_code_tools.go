@@ -498,9 +498,37 @@ func (tm *ClaudeCodeToolManager) DiscoverTools(ctx context.Context) ([]*ClaudeCo
 
 // queryMCPServerTools queries an MCP server for available tools.
 func (tm *ClaudeCodeToolManager) queryMCPServerTools(ctx context.Context, serverName string, config *types.MCPServerConfig) ([]*ClaudeCodeToolDefinition, error) {
-	// This would typically involve executing the MCP server with a discovery command
-	// For now, return empty as MCP servers would need to be running
-	return []*ClaudeCodeToolDefinition{}, nil
+	mcpTools, err := tm.client.ListMCPTools(ctx, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make([]*ClaudeCodeToolDefinition, 0, len(mcpTools))
+	for _, tool := range mcpTools {
+		definition := &ClaudeCodeToolDefinition{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Category:    "mcp",
+			Parameters:  make(map[string]ToolParameter),
+		}
+
+		if schema, err := tool.Schema(); err == nil {
+			for name, property := range schema.Properties {
+				definition.Parameters[name] = ToolParameter{
+					Type:        property.Type,
+					Description: property.Description,
+					Default:     property.Default,
+					Enum:        property.Enum,
+					Pattern:     property.Pattern,
+				}
+			}
+			definition.RequiredParameters = schema.Required
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
 }
 
 // GetTool retrieves a tool definition by name.
@@ -545,12 +573,32 @@ func (tm *ClaudeCodeToolManager) ListTools() []*ClaudeCodeToolDefinition {
 	return tools
 }
 
-// ExecuteTool executes a Claude Code tool.
+// ExecuteTool executes a Claude Code tool. Executions that exceed the tool's
+// entry in ClaudeCodeConfig.ToolTimeouts are canceled with a timeout error.
 func (tm *ClaudeCodeToolManager) ExecuteTool(ctx context.Context, tool *ClaudeCodeTool) (*ClaudeCodeToolResult, error) {
 	if tool == nil {
 		return nil, sdkerrors.NewValidationError("tool", "", "required", "tool cannot be nil")
 	}
 
+	timeout, ok := toolTimeout(tm.client.settings().ToolTimeouts, tool.Name)
+	if !ok {
+		return tm.executeTool(ctx, tool)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := tm.executeTool(ctx, tool)
+	if ctx.Err() == context.DeadlineExceeded {
+		err = sdkerrors.NewTimeoutError("tool "+tool.Name, timeout, result.ExecutionTime)
+		result.Success = false
+		result.Error = err.Error()
+	}
+	return result, err
+}
+
+// executeTool executes a tool, always returning a result with its execution time.
+func (tm *ClaudeCodeToolManager) executeTool(ctx context.Context, tool *ClaudeCodeTool) (*ClaudeCodeToolResult, error) {
 	start := time.Now()
 
 	// Determine tool type and execute appropriately
@@ -654,7 +702,8 @@ func (tm *ClaudeCodeToolManager) executeReadFile(ctx context.Context, params map
 	}
 
 	// Validate file path for security
-	if err := validateFilePath(path, tm.client.workingDir); err != nil {
+	workingDir := tm.client.currentWorkingDir()
+	if err := validateFilePath(path, workingDir); err != nil {
 		return &ClaudeCodeToolResult{
 			Success: false,
 			Error:   fmt.Sprintf("file path validation failed: %v", err),
@@ -663,7 +712,7 @@ func (tm *ClaudeCodeToolManager) executeReadFile(ctx context.Context, params map
 
 	// Resolve path relative to working directory
 	if !filepath.IsAbs(path) {
-		path = filepath.Join(tm.client.workingDir, path)
+		path = filepath.Join(workingDir, path)
 	}
 
 	// Read file - path validated above
@@ -697,7 +746,8 @@ func (tm *ClaudeCodeToolManager) executeWriteFile(ctx context.Context, params ma
 	}
 
 	// Validate file path for security
-	if err := validateFilePath(path, tm.client.workingDir); err != nil {
+	workingDir := tm.client.currentWorkingDir()
+	if err := validateFilePath(path, workingDir); err != nil {
 		return &ClaudeCodeToolResult{
 			Success: false,
 			Error:   fmt.Sprintf("file path validation failed: %v", err),
@@ -706,7 +756,7 @@ func (tm *ClaudeCodeToolManager) executeWriteFile(ctx context.Context, params ma
 
 	// Resolve path relative to working directory
 	if !filepath.IsAbs(path) {
-		path = filepath.Join(tm.client.workingDir, path)
+		path = filepath.Join(workingDir, path)
 	}
 
 	// Create directories if needed
@@ -760,8 +810,9 @@ func (tm *ClaudeCodeToolManager) executeListFiles(ctx context.Context, params ma
 	}
 
 	// Resolve path relative to working directory
+	workingDir := tm.client.currentWorkingDir()
 	if !filepath.IsAbs(path) {
-		path = filepath.Join(tm.client.workingDir, path)
+		path = filepath.Join(workingDir, path)
 	}
 
 	recursive := false
@@ -790,7 +841,7 @@ func (tm *ClaudeCodeToolManager) executeListFiles(ctx context.Context, params ma
 				}
 			}
 
-			relPath, _ := filepath.Rel(tm.client.workingDir, filePath)
+			relPath, _ := filepath.Rel(workingDir, filePath)
 			files = append(files, relPath)
 			return nil
 		})
@@ -819,7 +870,7 @@ func (tm *ClaudeCodeToolManager) executeListFiles(ctx context.Context, params ma
 				}
 			}
 
-			relPath, _ := filepath.Rel(tm.client.workingDir, filepath.Join(path, entry.Name()))
+			relPath, _ := filepath.Rel(workingDir, filepath.Join(path, entry.Name()))
 			files = append(files, relPath)
 		}
 	}
@@ -850,7 +901,7 @@ func (tm *ClaudeCodeToolManager) executeSearchCode(ctx context.Context, params m
 
 	// Resolve path relative to working directory
 	if !filepath.IsAbs(searchPath) {
-		searchPath = filepath.Join(tm.client.workingDir, searchPath)
+		searchPath = filepath.Join(tm.client.currentWorkingDir(), searchPath)
 	}
 
 	// Build grep command
@@ -944,10 +995,10 @@ func (tm *ClaudeCodeToolManager) executeRunCommand(ctx context.Context, params m
 		}, nil
 	}
 
-	workingDir := tm.client.workingDir
+	workingDir := tm.client.currentWorkingDir()
 	if wd, ok := params["working_dir"].(string); ok {
 		if !filepath.IsAbs(wd) {
-			workingDir = filepath.Join(tm.client.workingDir, wd)
+			workingDir = filepath.Join(workingDir, wd)
 		} else {
 			workingDir = wd
 		}
@@ -965,6 +1016,8 @@ func (tm *ClaudeCodeToolManager) executeRunCommand(ctx context.Context, params m
 	// Execute command through shell - validated for security above
 	cmd := exec.CommandContext(ctx, "sh", "-c", command) // #nosec G204 - command validated above
 	cmd.Dir = workingDir
+	// Don't wait on orphaned children still holding the output pipe after a timeout
+	cmd.WaitDelay = time.Second
 
 	output, err := cmd.CombinedOutput()
 
@@ -993,10 +1046,10 @@ func (tm *ClaudeCodeToolManager) executeRunCommand(ctx context.Context, params m
 }
 
 func (tm *ClaudeCodeToolManager) executeGitStatus(ctx context.Context, params map[string]any) (*ClaudeCodeToolResult, error) {
-	path := tm.client.workingDir
+	path := tm.client.currentWorkingDir()
 	if p, ok := params["path"].(string); ok {
 		if !filepath.IsAbs(p) {
-			path = filepath.Join(tm.client.workingDir, p)
+			path = filepath.Join(path, p)
 		} else {
 			path = p
 		}
@@ -1028,10 +1081,10 @@ func (tm *ClaudeCodeToolManager) executeGitStatus(ctx context.Context, params ma
 }
 
 func (tm *ClaudeCodeToolManager) executeGitDiff(ctx context.Context, params map[string]any) (*ClaudeCodeToolResult, error) {
-	path := tm.client.workingDir
+	path := tm.client.currentWorkingDir()
 	if p, ok := params["path"].(string); ok {
 		if !filepath.IsAbs(p) {
-			path = filepath.Join(tm.client.workingDir, p)
+			path = filepath.Join(path, p)
 		} else {
 			path = p
 		}
@@ -1292,6 +1345,18 @@ func (tm *ClaudeCodeToolManager) resultToContent(result *ClaudeCodeToolResult) (
 		}, nil
 	}
 
+	// Binary output and content blocks are passed through rather than
+	// stringified, keeping their bytes and media types
+	switch output := result.Output.(type) {
+	case []byte:
+		mediaType, _ := result.Metadata["media_type"].(string)
+		return []types.ContentBlock{types.NewBinaryBlock(mediaType, output)}, nil
+	case types.ContentBlock:
+		return []types.ContentBlock{output}, nil
+	case []types.ContentBlock:
+		return output, nil
+	}
+
 	// Convert output to JSON for consistent formatting
 	output, err := json.MarshalIndent(map[string]any{
 		"output":   result.Output,
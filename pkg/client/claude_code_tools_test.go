@@ -1054,6 +1054,31 @@ func TestClaudeCodeToolManager_ParameterValidation(t *testing.T) {
 	}
 }
 
+func TestClaudeCodeToolManager_BinaryResultContent(t *testing.T) {
+	toolManager := &ClaudeCodeToolManager{}
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	content, err := toolManager.resultToContent(&ClaudeCodeToolResult{Success: true, Output: png})
+	if err != nil {
+		t.Fatalf("resultToContent failed: %v", err)
+	}
+	if len(content) != 1 || content[0].Type != "image" || content[0].MediaType() != "image/png" {
+		t.Fatalf("Expected an image block, got %+v", content)
+	}
+	if data, err := content[0].Bytes(); err != nil || string(data) != string(png) {
+		t.Errorf("Image bytes changed: %q, %v", data, err)
+	}
+
+	content, err = toolManager.resultToContent(&ClaudeCodeToolResult{
+		Success:  true,
+		Output:   []byte{0x1f, 0x8b, 0x08},
+		Metadata: map[string]any{"media_type": "application/gzip"},
+	})
+	if err != nil || len(content) != 1 || content[0].Type != types.ContentBlockBinary || content[0].MediaType() != "application/gzip" {
+		t.Errorf("Expected a gzip binary block, got %+v, %v", content, err)
+	}
+}
+
 // Helper function to check if error contains message
 func errContains(err error, msg string) bool {
 	if err == nil {
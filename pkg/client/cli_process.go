@@ -0,0 +1,279 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/recorder"
+)
+
+// cliProcess is a running claude CLI invocation. It is backed either by a real
+// subprocess or, in replay mode, by a recorded cassette interaction.
+type cliProcess struct {
+	// stdout delivers the process output; callers must close it
+	stdout io.ReadCloser
+
+	// stderr delivers the error output when captured, otherwise nil
+	stderr io.ReadCloser
+
+	wait func() error
+	kill func() error
+
+	// interrupted is set when Interrupt or Close terminated the process
+	interrupted atomic.Bool
+}
+
+// Wait waits for the process to exit and returns its exit status.
+func (p *cliProcess) Wait() error {
+	return p.wait()
+}
+
+// Kill terminates the process.
+func (p *cliProcess) Kill() error {
+	return p.kill()
+}
+
+// interrupt terminates the process on behalf of the caller, recording that it
+// did not fail on its own. Its output is closed too, so blocked readers return
+// even if a grandchild process still holds the write end of a pipe.
+func (p *cliProcess) interrupt() {
+	p.interrupted.Store(true)
+	_ = p.kill()         // Ignore error, the process may already have exited
+	_ = p.stdout.Close() // Ignore error, the reader is being released
+	if p.stderr != nil {
+		_ = p.stderr.Close() // Ignore error, the reader is being released
+	}
+}
+
+// SetRecorder attaches a recorder to the client. In record mode every claude
+// CLI invocation is captured with its arguments, request options, output
+// frames, and timings; in replay mode invocations are served from the cassette
+// without running the CLI. Pass nil to detach the recorder.
+func (c *ClaudeCodeClient) SetRecorder(rec *recorder.Recorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.recorder = rec
+}
+
+// startCLI starts the claude CLI with the given arguments, writing input to its
+// stdin when set. Options are the request options that produced the arguments
+// and are only used for recording. Stderr is discarded unless captureStderr is set.
+// The configured faults, if any, are injected into its output, and its exit
+// status counts toward the circuit breaker, which may refuse to start it.
+// The caller must pass the process to trackProcess, which also completes the
+// client's transition out of the connecting state.
+func (c *ClaudeCodeClient) startCLI(ctx context.Context, args []string, input []byte, options any, captureStderr bool) (*cliProcess, error) {
+	breaker := c.circuit()
+	probe, err := breaker.allow()
+	if err != nil {
+		c.releaseLocalToolArgs(args)
+		return nil, err
+	}
+
+	c.transition(func(m *stateMachine) { m.starting++ })
+
+	process, err := c.spawnCLI(ctx, args, input, options, captureStderr)
+	if err != nil {
+		c.releaseLocalToolArgs(args)
+		breaker.record(probe, spawnOutcome(err))
+		c.transition(func(m *stateMachine) {
+			m.starting--
+			m.failed = true
+		})
+		return nil, err
+	}
+	// The CLI reads its config files at startup, so they can go once it exits
+	wait := process.wait
+	process.wait = func() error {
+		err := wait()
+		c.releaseLocalToolArgs(args)
+		return err
+	}
+	process = c.injectFaults(process)
+	breaker.watch(ctx, process, probe)
+	return process, nil
+}
+
+// spawnCLI starts the process for startCLI, replays it from the cassette, or
+// serves it from the test script.
+//
+// Stdout is backed by an OS pipe rather than cmd.StdoutPipe so the process can be
+// waited on in the background without discarding output that has not been read yet.
+func (c *ClaudeCodeClient) spawnCLI(ctx context.Context, args []string, input []byte, options any, captureStderr bool) (*cliProcess, error) {
+	c.mu.RLock()
+	rec := c.recorder
+	c.mu.RUnlock()
+	workingDir := c.scopeFrom(ctx).workingDir
+
+	if rec != nil && rec.Mode() == recorder.ModeReplay {
+		playback, err := rec.Replay(c.recorderRequest(args, workingDir, options))
+		if err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CASSETTE_REPLAY", "failed to replay claude invocation")
+		}
+
+		process := &cliProcess{
+			stdout: playback.Stdout(),
+			wait:   playback.Wait,
+			kill:   playback.Kill,
+		}
+		if captureStderr {
+			process.stderr = playback.Stderr()
+		}
+		return process, nil
+	}
+
+	// Test mode with a script never starts the CLI
+	if c.scripted != nil {
+		return c.scripted.start(ctx, args, input, options, captureStderr, c.settings().Model), nil
+	}
+
+	if err := c.checkCLIFlags(ctx, args); err != nil {
+		return nil, err
+	}
+
+	env, err := c.subprocessEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	env = withCredentials(ctx, env)
+
+	cmd := exec.CommandContext(ctx, c.claudeCodeCmd, args...) // #nosec G204 - claudeCodeCmd is validated during initialization
+	cmd.Dir = workingDir
+	cmd.Env = env
+	// A control channel keeps stdin open after the input is written
+	control := controlFrom(ctx)
+	var stdinReader, stdinWriter *os.File
+	if control != nil {
+		stdinReader, stdinWriter, err = os.Pipe()
+		if err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PIPE_CREATION", "failed to create stdin pipe")
+		}
+		cmd.Stdin = stdinReader
+	} else if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+
+	// Create pipes for stdout and, if requested, stderr
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		closeFiles(stdinReader, stdinWriter)
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PIPE_CREATION", "failed to create stdout pipe")
+	}
+	cmd.Stdout = stdoutWriter
+
+	var stderrReader, stderrWriter *os.File
+	if captureStderr {
+		stderrReader, stderrWriter, err = os.Pipe()
+		if err != nil {
+			closeFiles(stdinReader, stdinWriter, stdoutReader, stdoutWriter)
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PIPE_CREATION", "failed to create stderr pipe")
+		}
+		cmd.Stderr = stderrWriter
+	}
+
+	// Start the process
+	startErr := cmd.Start()
+
+	// The child holds its own copies of the write ends
+	_ = stdoutWriter.Close() // Ignore error, child process owns the write end now
+	if stderrWriter != nil {
+		_ = stderrWriter.Close() // Ignore error, child process owns the write end now
+	}
+	if stdinReader != nil {
+		_ = stdinReader.Close() // Ignore error, child process owns the read end now
+	}
+
+	if startErr != nil {
+		closeFiles(stdinWriter, stdoutReader, stderrReader)
+		return nil, sdkerrors.WrapError(startErr, sdkerrors.CategoryInternal, "PROCESS_START", "failed to start claude process")
+	}
+	if control != nil {
+		control.attach(stdinWriter, input)
+	}
+
+	process := &cliProcess{
+		stdout: stdoutReader,
+		wait:   cmd.Wait,
+		kill: func() error {
+			if cmd.Process == nil {
+				return nil
+			}
+			return cmd.Process.Kill()
+		},
+	}
+	if stderrReader != nil {
+		process.stderr = stderrReader
+	}
+
+	if rec != nil && rec.Mode() == recorder.ModeRecord {
+		recording := rec.Begin(c.recorderRequest(args, workingDir, options))
+		process.stdout = recording.Wrap(recorder.StreamStdout, process.stdout)
+		if process.stderr != nil {
+			process.stderr = recording.Wrap(recorder.StreamStderr, process.stderr)
+		}
+		process.wait = func() error {
+			err := cmd.Wait()
+			recording.Finish(err)
+			return err
+		}
+	}
+
+	return process, nil
+}
+
+// subprocessEnvironment returns the host environment allowed by the configured
+// policy followed by the client's own variables, which take precedence.
+// The isolated config directory, if configured, is created first.
+func (c *ClaudeCodeClient) subprocessEnvironment() ([]string, error) {
+	own := c.buildEnvironment()
+	env, err := c.settings().EnvPolicy.FilterWith(os.Environ(), own)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "ENV_POLICY", "invalid environment policy")
+	}
+	if dir := c.settings().IsolatedConfigDir; dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "CONFIG_DIR", "failed to create isolated config directory")
+		}
+	}
+	return append(env, own...), nil
+}
+
+// recorderRequest describes a CLI invocation for the recorder. Environment
+// values are omitted since they commonly hold credentials.
+func (c *ClaudeCodeClient) recorderRequest(args []string, workingDir string, options any) recorder.Request {
+	request := recorder.Request{
+		Args: append([]string(nil), args...),
+		Dir:  workingDir,
+	}
+
+	for _, entry := range c.buildEnvironment() {
+		if name, _, ok := strings.Cut(entry, "="); ok {
+			request.Env = append(request.Env, name)
+		}
+	}
+
+	if options != nil {
+		if data, err := json.Marshal(options); err == nil {
+			request.Options = data
+		}
+	}
+
+	return request
+}
+
+// closeFiles closes the non-nil files, ignoring errors, during cleanup.
+func closeFiles(files ...*os.File) {
+	for _, f := range files {
+		if f != nil {
+			_ = f.Close() // Ignore error during cleanup
+		}
+	}
+}
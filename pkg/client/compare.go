@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// CompareReadOnlyTools are the tools a Compare run may use when its options
+// select neither tools nor a profile. The runs share the working directory
+// and run at once, so the tools that modify files or run commands
+// (CompareWriteTools) are disallowed.
+var CompareReadOnlyTools = []string{"Glob", "Grep", "LS", "NotebookRead", "Read"}
+
+// CompareWriteTools are the tools disallowed by default in Compare runs.
+var CompareWriteTools = []string{"Bash", "Edit", "MultiEdit", "NotebookEdit", "Write"}
+
+// ModelConfig is one of the configurations Compare runs a prompt with.
+type ModelConfig struct {
+	// Name labels the configuration's result (defaults to Model)
+	Name string
+
+	// Model is the model to query, overriding Options.Model
+	Model string
+
+	// Options sets the query's other options, as for StreamTo
+	Options *QueryOptions
+}
+
+// ComparisonResult is the outcome of a prompt run with one ModelConfig.
+type ComparisonResult struct {
+	// Name and Model identify the configuration
+	Name  string
+	Model string
+
+	// Text is the reply text
+	Text string
+
+	// Result is the result the CLI reported, if any
+	Result *types.ResultMessage
+
+	// Latency is the wall time of the query
+	Latency time.Duration
+
+	// CostUSD is the cost the CLI reported, or else the cost of the
+	// reported usage at the client's prices
+	CostUSD float64
+
+	// Err is set if the query failed
+	Err error
+}
+
+// Comparison holds the results of Compare, in the order of its configs.
+type Comparison struct {
+	Prompt  string
+	Results []ComparisonResult
+}
+
+// Compare runs prompt with each config concurrently and returns the
+// results aligned with configs, for choosing between models or option
+// sets. A failed run sets its result's Err rather than failing the
+// comparison. Each run gets a new session unless its options select one;
+// two configs cannot select the same session. The runs share the working
+// directory, so a config whose options select neither AllowedTools,
+// DisallowedTools nor a Profile may only read files (CompareReadOnlyTools).
+func (c *ClaudeCodeClient) Compare(ctx context.Context, prompt string, configs []ModelConfig) (*Comparison, error) {
+	if strings.TrimSpace(prompt) == "" {
+		return nil, sdkerrors.NewValidationError("prompt", "", "required", "prompt cannot be empty")
+	}
+	if len(configs) == 0 {
+		return nil, sdkerrors.NewValidationError("configs", "", "at least one", "no configurations to compare")
+	}
+	sessions := map[string]bool{}
+	for _, config := range configs {
+		if config.Options == nil || config.Options.SessionID == "" {
+			continue
+		}
+		if sessions[config.Options.SessionID] {
+			return nil, sdkerrors.NewValidationError("configs", config.Options.SessionID, "unique session IDs",
+				"configurations cannot run in the same session")
+		}
+		sessions[config.Options.SessionID] = true
+	}
+
+	comparison := &Comparison{Prompt: prompt, Results: make([]ComparisonResult, len(configs))}
+	var wg sync.WaitGroup
+	for i, config := range configs {
+		wg.Add(1)
+		go func(result *ComparisonResult, config ModelConfig) {
+			defer wg.Done()
+			*result = c.compareRun(ctx, prompt, config)
+		}(&comparison.Results[i], config)
+	}
+	wg.Wait()
+	return comparison, nil
+}
+
+// compareRun runs prompt with one configuration.
+func (c *ClaudeCodeClient) compareRun(ctx context.Context, prompt string, config ModelConfig) ComparisonResult {
+	options := QueryOptions{}
+	if config.Options != nil {
+		options = *config.Options
+	}
+	if config.Model != "" {
+		options.Model = config.Model
+	}
+	if options.SessionID == "" {
+		options.SessionID = GenerateSessionID()
+	}
+	if len(options.AllowedTools) == 0 && len(options.DisallowedTools) == 0 && options.Profile == "" {
+		options.AllowedTools = CompareReadOnlyTools
+		options.DisallowedTools = CompareWriteTools
+	}
+
+	result := ComparisonResult{Name: config.Name, Model: options.Model}
+	if result.Name == "" {
+		result.Name = options.Model
+	}
+
+	var text strings.Builder
+	started := time.Now()
+	result.Result, result.Err = c.streamText(ctx, prompt, &options, func(delta string) error {
+		text.WriteString(delta)
+		return nil
+	})
+	result.Latency = time.Since(started)
+	result.Text = text.String()
+
+	if result.Result != nil {
+		result.CostUSD = result.Result.Cost()
+		if result.Result.TotalCostUSD == nil && result.Result.Usage != nil {
+			if price, ok := c.lookupPrice(result.Model); ok {
+				result.CostUSD = price.Cost(*result.Result.Usage)
+			}
+		}
+	}
+	return result
+}
+
+// Fastest returns the successful result with the lowest latency, or nil if
+// every run failed.
+func (c *Comparison) Fastest() *ComparisonResult {
+	return c.best(func(a, b *ComparisonResult) bool { return a.Latency < b.Latency })
+}
+
+// Cheapest returns the successful result with the lowest cost, or nil if
+// every run failed.
+func (c *Comparison) Cheapest() *ComparisonResult {
+	return c.best(func(a, b *ComparisonResult) bool { return a.CostUSD < b.CostUSD })
+}
+
+// best returns the first successful result no other successful result is
+// less than.
+func (c *Comparison) best(less func(a, b *ComparisonResult) bool) *ComparisonResult {
+	var best *ComparisonResult
+	for i := range c.Results {
+		result := &c.Results[i]
+		if result.Err == nil && (best == nil || less(result, best)) {
+			best = result
+		}
+	}
+	return best
+}
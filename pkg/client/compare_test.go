@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestClaudeCodeClient_Compare(t *testing.T) {
+	client := newFakeCLIClient(t, `
+model=default
+session=
+allowed=
+disallowed=
+while [ $# -gt 0 ]; do
+	case "$1" in
+	--model) model="$2" ;;
+	--session-id) session="$2" ;;
+	--allowedTools) allowed="$2" ;;
+	--disallowedTools) disallowed="$2" ;;
+	esac
+	shift
+done
+# Runs that select no tools may only read files
+case "$model,$allowed" in
+fast,Glob,Grep,LS,NotebookRead,Read) ;;
+fast,*) echo "allowed: $allowed" >&2; exit 3 ;;
+esac
+case "$model,$disallowed" in
+fast,*Write*) ;;
+fast,*) echo "disallowed: $disallowed" >&2; exit 3 ;;
+esac
+case "$model,$allowed" in
+slow,Bash) ;;
+slow,*) echo "allowed: $allowed" >&2; exit 3 ;;
+esac
+case "$model" in
+fast) echo '{"type":"assistant","message":{"content":[{"type":"text","text":"quick answer"}]}}'
+	echo '{"type":"result","subtype":"success","is_error":false,"total_cost_usd":0.002}' ;;
+slow) sleep 0.2
+	echo '{"type":"assistant","message":{"content":[{"type":"text","text":"careful answer"}]}}'
+	echo '{"type":"result","subtype":"success","is_error":false,"usage":{"input_tokens":1000000,"output_tokens":0}}' ;;
+*) echo "unknown model" >&2; exit 1 ;;
+esac
+[ -n "$session" ] || exit 2`)
+	client.SetModelPricing("slow", types.ModelPricing{InputCostPer1K: 0.003, Currency: "USD"})
+
+	comparison, err := client.Compare(context.Background(), "Explain defer", []ModelConfig{
+		{Name: "careful", Model: "slow", Options: &QueryOptions{AllowedTools: []string{"Bash"}}},
+		{Model: "fast"},
+		{Model: "broken"},
+	})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(comparison.Results) != 3 || comparison.Prompt != "Explain defer" {
+		t.Fatalf("Unexpected comparison: %+v", comparison)
+	}
+
+	careful, fast, broken := comparison.Results[0], comparison.Results[1], comparison.Results[2]
+	if careful.Name != "careful" || careful.Text != "careful answer" || careful.CostUSD != 3 || careful.Err != nil {
+		t.Errorf("Unexpected careful result: %+v", careful)
+	}
+	if fast.Name != "fast" || fast.Text != "quick answer" || fast.CostUSD != 0.002 || fast.Latency >= careful.Latency {
+		t.Errorf("Unexpected fast result: %+v", fast)
+	}
+	if broken.Err == nil {
+		t.Errorf("Expected the broken model to fail, got %+v", broken)
+	}
+
+	if best := comparison.Fastest(); best == nil || best.Name != "fast" {
+		t.Errorf("Fastest() = %+v", best)
+	}
+	if best := comparison.Cheapest(); best == nil || best.Name != "fast" {
+		t.Errorf("Cheapest() = %+v", best)
+	}
+
+	var validation *sdkerrors.ValidationError
+	if _, err := client.Compare(context.Background(), "hi", nil); !errors.As(err, &validation) {
+		t.Errorf("Expected a validation error without configs, got %v", err)
+	}
+
+	shared := &QueryOptions{SessionID: GenerateSessionID()}
+	if _, err := client.Compare(context.Background(), "hi", []ModelConfig{
+		{Model: "fast", Options: shared},
+		{Model: "slow", Options: shared},
+	}); !errors.As(err, &validation) {
+		t.Errorf("Expected a validation error for a shared session, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"fmt"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// settings returns the client configuration. Writers never modify the
+// configuration in place; they swap in an updated copy under c.mu, so the
+// returned value can be read without holding the lock.
+func (c *ClaudeCodeClient) settings() *types.ClaudeCodeConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.config
+}
+
+// updateSettings replaces the configuration with a copy changed by update.
+// The caller must hold c.mu for writing. AddDirs is cloned, so update may
+// append to it; other maps and slices shared with the previous copy must be
+// replaced rather than modified.
+func (c *ClaudeCodeClient) updateSettings(update func(config *types.ClaudeCodeConfig)) {
+	next := *c.config
+	next.AddDirs = append([]string(nil), next.AddDirs...)
+	update(&next)
+	c.config = &next
+}
+
+// currentWorkingDir returns the working directory queries run in.
+func (c *ClaudeCodeClient) currentWorkingDir() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.workingDir
+}
+
+// newProcessID returns a unique key for activeProcesses.
+func (c *ClaudeCodeClient) newProcessID(kind string) string {
+	return fmt.Sprintf("%s-%d", kind, c.processSeq.Add(1))
+}
+
+// trackProcess registers a started process so Interrupt and Close can
+// terminate it. If the client was closed while the process was starting, the
+// process is killed and an error is returned; the caller still owns its pipes.
+func (c *ClaudeCodeClient) trackProcess(processID string, process *cliProcess) error {
+	// Holding the read lock keeps Close from running until the process is
+	// registered, so no process outlives the client
+	c.mu.RLock()
+	closed := c.closed
+	if !closed {
+		c.processMu.Lock()
+		c.activeProcesses[processID] = process
+		c.processMu.Unlock()
+	}
+	c.mu.RUnlock()
+
+	c.transition(func(m *stateMachine) {
+		m.starting--
+		m.failed = false
+	})
+
+	if closed {
+		process.interrupt()
+		return sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
+	}
+	return nil
+}
+
+// untrackProcess removes a process registered with trackProcess.
+func (c *ClaudeCodeClient) untrackProcess(processID string) {
+	c.processMu.Lock()
+	delete(c.activeProcesses, processID)
+	c.processMu.Unlock()
+
+	c.transition(nil)
+}
+
+// Interrupt stops every query in flight by terminating its claude process,
+// and cancels the contexts of ToolRegistry handlers still running. Query
+// returns an INTERRUPTED error and open streams end with an error; unlike
+// Close, the client stays usable for new queries.
+//
+// Interrupt is safe to call from any goroutine, including concurrently with
+// Query and Close.
+func (c *ClaudeCodeClient) Interrupt() error {
+	c.mu.RLock()
+	if c.closed {
+		c.mu.RUnlock()
+		return sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
+	}
+
+	c.processMu.Lock()
+	for _, process := range c.activeProcesses {
+		process.interrupt()
+	}
+	c.processMu.Unlock()
+	c.mu.RUnlock()
+
+	// Stop Go tools still working for the killed queries
+	c.cancelToolCalls("", true)
+
+	// The client stays interrupting until the killed queries have returned
+	c.transition(nil)
+	return nil
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Run with -race to check the client's synchronization.
+func TestClient_ConcurrentQueries(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "answer to $prompt"`)
+	ctx := context.Background()
+	workingDir := client.currentWorkingDir()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				prompt := fmt.Sprintf("q%d-%d", i, j)
+				request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: prompt}}}
+
+				if i%2 == 0 {
+					response, err := client.Query(ctx, request)
+					if err != nil {
+						t.Errorf("Query %s failed: %v", prompt, err)
+						return
+					}
+					if got := response.GetTextContent(); got != "answer to "+prompt {
+						t.Errorf("Query %s got response %q", prompt, got)
+					}
+					continue
+				}
+
+				stream, err := client.QueryStream(ctx, request)
+				if err != nil {
+					t.Errorf("QueryStream %s failed: %v", prompt, err)
+					return
+				}
+				for {
+					chunk, err := stream.Recv()
+					if err != nil || chunk.Done {
+						break
+					}
+				}
+				_ = stream.Close()
+			}
+		}(i)
+	}
+
+	// Reconfigure the client while queries are being built
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 20; k++ {
+			config := types.NewClaudeCodeConfig()
+			config.Model = fmt.Sprintf("model-%d", k)
+			config.WorkingDirectory = workingDir
+			if err := client.ReloadConfig(ctx, config); err != nil {
+				t.Errorf("ReloadConfig failed: %v", err)
+			}
+			if err := client.SetWorkingDirectory(ctx, workingDir); err != nil {
+				t.Errorf("SetWorkingDirectory failed: %v", err)
+			}
+			if err := client.RegisterProfile(fmt.Sprintf("profile-%d", k), &types.Profile{Model: "claude-3-5-haiku-20241022"}); err != nil {
+				t.Errorf("RegisterProfile failed: %v", err)
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	client.processMu.Lock()
+	active := len(client.activeProcesses)
+	client.processMu.Unlock()
+	if active != 0 {
+		t.Errorf("Expected no active processes, got %d", active)
+	}
+}
+
+func TestClient_Interrupt(t *testing.T) {
+	client := newFakeCLIClient(t, `case "$prompt" in hi) sleep 30 ;; *) echo "handled $prompt" ;; esac`)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.Query(context.Background(), &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}})
+		errs <- err
+	}()
+	waitForProcesses(t, client, 1)
+
+	if err := client.Interrupt(); err != nil {
+		t.Fatalf("Interrupt failed: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		var sdkErr *sdkerrors.InternalError
+		if !errors.As(err, &sdkErr) || sdkErr.Code() != "INTERRUPTED" {
+			t.Errorf("Expected INTERRUPTED error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Query did not return after Interrupt")
+	}
+
+	// The client stays usable
+	response, err := client.Query(context.Background(), &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "again"}}})
+	if err != nil {
+		t.Fatalf("Query after Interrupt failed: %v", err)
+	}
+	if got := response.GetTextContent(); got != "handled again" {
+		t.Errorf("Expected response after Interrupt, got %q", got)
+	}
+}
+
+func TestClient_ConcurrentClose(t *testing.T) {
+	client := newFakeCLIClient(t, `sleep 30`)
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _ = client.Query(context.Background(), request)
+		}()
+		go func() {
+			defer wg.Done()
+			stream, err := client.QueryStream(context.Background(), request)
+			if err != nil {
+				return
+			}
+			defer stream.Close()
+			for {
+				chunk, err := stream.Recv()
+				if err != nil || chunk.Done {
+					return
+				}
+			}
+		}()
+	}
+	waitForProcesses(t, client, 1)
+
+	// Interrupt and Close race with each other and with queries still starting
+	var closers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		closers.Add(2)
+		go func() {
+			defer closers.Done()
+			_ = client.Interrupt()
+		}()
+		go func() {
+			defer closers.Done()
+			_ = client.Close()
+		}()
+	}
+	closers.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Queries did not return after Close")
+	}
+
+	client.processMu.Lock()
+	active := len(client.activeProcesses)
+	client.processMu.Unlock()
+	if active != 0 {
+		t.Errorf("Expected no processes to outlive Close, got %d", active)
+	}
+	if err := client.Interrupt(); err == nil {
+		t.Error("Expected Interrupt to fail after Close")
+	}
+}
+
+// waitForProcesses waits until at least n CLI processes are running.
+func waitForProcesses(t *testing.T, client *ClaudeCodeClient, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		client.processMu.Lock()
+		active := len(client.activeProcesses)
+		client.processMu.Unlock()
+		if active >= n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d processes", n)
+}
@@ -0,0 +1,387 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// defaultControlRequestTimeout bounds the wait for a control response when
+// neither the request nor ControlRequestTimeout sets a timeout.
+const defaultControlRequestTimeout = 30 * time.Second
+
+// controlChannel carries control requests to the claude process of a stream
+// over its stdin, and routes the CLI's control responses back to the callers
+// as the stream's reader receives them.
+type controlChannel struct {
+	mu sync.Mutex
+
+	// stdin is the process input, nil before the process starts and after
+	// the result arrives
+	stdin io.WriteCloser
+
+	// pending holds the callers waiting for a response, by request ID
+	pending map[string]chan json.RawMessage
+	seq     uint64
+}
+
+// controlRequestFrame is the stdin line carrying a control request.
+type controlRequestFrame struct {
+	Type      string          `json:"type"`
+	RequestID string          `json:"request_id"`
+	Request   json.RawMessage `json:"request"`
+}
+
+func newControlChannel() *controlChannel {
+	return &controlChannel{pending: make(map[string]chan json.RawMessage)}
+}
+
+type controlKey struct{}
+
+// withControl returns a context whose claude process keeps its stdin open for
+// the control channel.
+func withControl(ctx context.Context, control *controlChannel) context.Context {
+	return context.WithValue(ctx, controlKey{}, control)
+}
+
+// controlFrom returns the control channel attached to ctx, or nil.
+func controlFrom(ctx context.Context) *controlChannel {
+	control, _ := ctx.Value(controlKey{}).(*controlChannel)
+	return control
+}
+
+// attach makes stdin the input of the channel's process, replacing that of a
+// previous process, and writes input to it in the background. The lock is
+// held until input is written so control requests follow the prompt.
+func (ch *controlChannel) attach(stdin io.WriteCloser, input []byte) {
+	ch.mu.Lock()
+	ch.closeLocked()
+	ch.stdin = stdin
+
+	go func() {
+		defer ch.mu.Unlock()
+		if _, err := stdin.Write(input); err != nil {
+			ch.closeLocked()
+		}
+	}()
+}
+
+// open reports whether the channel's process accepts control requests.
+func (ch *controlChannel) open() bool {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return ch.stdin != nil
+}
+
+// send writes a control request to the process and waits for its response
+// frame.
+func (ch *controlChannel) send(ctx context.Context, request json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(request)
+	if !json.Valid(trimmed) || trimmed[0] != '{' {
+		return nil, sdkerrors.NewValidationError("request", "", "json object", "control request must be a JSON object")
+	}
+
+	ch.mu.Lock()
+	if ch.stdin == nil {
+		ch.mu.Unlock()
+		return nil, errControlClosed()
+	}
+
+	ch.seq++
+	requestID := fmt.Sprintf("req_%d", ch.seq)
+	frame, err := json.Marshal(controlRequestFrame{Type: "control_request", RequestID: requestID, Request: trimmed})
+	if err != nil {
+		ch.mu.Unlock()
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JSON_MARSHAL", "failed to encode control request")
+	}
+
+	reply := make(chan json.RawMessage, 1)
+	ch.pending[requestID] = reply
+	if _, err := ch.stdin.Write(append(frame, '\n')); err != nil {
+		ch.closeLocked()
+		ch.mu.Unlock()
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryNetwork, "CONTROL_WRITE", "failed to write control request to claude process")
+	}
+	ch.mu.Unlock()
+
+	select {
+	case response, ok := <-reply:
+		if !ok {
+			return nil, errControlClosed()
+		}
+		return response, nil
+	case <-ctx.Done():
+		ch.mu.Lock()
+		delete(ch.pending, requestID)
+		ch.mu.Unlock()
+		return nil, sdkerrors.WrapError(ctx.Err(), sdkerrors.CategoryNetwork, "CONTEXT_CANCELED", "request context canceled")
+	}
+}
+
+// observeLine delivers control_response frames to the callers waiting for
+// them, and closes stdin when the result arrives so the process can exit.
+func (ch *controlChannel) observeLine(line []byte) {
+	if ch == nil || !bytes.HasPrefix(line, []byte("{")) {
+		return
+	}
+
+	var frame struct {
+		Type     string `json:"type"`
+		Response struct {
+			RequestID string `json:"request_id"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(line, &frame); err != nil {
+		return
+	}
+
+	switch frame.Type {
+	case "control_response":
+		ch.mu.Lock()
+		reply, ok := ch.pending[frame.Response.RequestID]
+		delete(ch.pending, frame.Response.RequestID)
+		ch.mu.Unlock()
+		if ok {
+			reply <- json.RawMessage(bytes.TrimSuffix(line, []byte("\n")))
+		}
+	case "result":
+		ch.close()
+	}
+}
+
+// close closes the process's stdin and fails the requests still waiting.
+func (ch *controlChannel) close() {
+	if ch == nil {
+		return
+	}
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.closeLocked()
+}
+
+func (ch *controlChannel) closeLocked() {
+	if ch.stdin != nil {
+		_ = ch.stdin.Close() // Ignore error, the process may already have exited
+		ch.stdin = nil
+	}
+	for requestID, reply := range ch.pending {
+		close(reply)
+		delete(ch.pending, requestID)
+	}
+}
+
+func errControlClosed() error {
+	return sdkerrors.NewInternalError("CONTROL_CLOSED", "claude process no longer accepts control requests")
+}
+
+// SendRawControlRequest sends a control request to the claude process of the
+// most recently opened stream that still accepts them, and returns the CLI's
+// control_response frame unparsed. It lets new control-protocol features be
+// used before the SDK supports them.
+//
+// The request is the JSON object the CLI expects in the request field of a
+// control_request, e.g. {"subtype":"interrupt"}; the SDK assigns its request
+// ID. Only streams opened by QueryStream with ControlProtocol enabled accept
+// control requests, until their result arrives. The response is delivered
+// as the stream's reader receives it, so the stream must be read
+// concurrently.
+func (c *ClaudeCodeClient) SendRawControlRequest(ctx context.Context, request json.RawMessage) (json.RawMessage, error) {
+	c.mu.RLock()
+	closed := c.closed
+	c.mu.RUnlock()
+	if closed {
+		return nil, sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
+	}
+
+	var target *controlChannel
+	c.controlMu.Lock()
+	for i := len(c.controls) - 1; i >= 0 && target == nil; i-- {
+		if c.controls[i].open() {
+			target = c.controls[i]
+		}
+	}
+	c.controlMu.Unlock()
+
+	if target == nil {
+		return nil, sdkerrors.NewInternalError("CONTROL_UNAVAILABLE", "no open stream accepts control requests; enable ControlProtocol and use QueryStream")
+	}
+	return target.send(ctx, request)
+}
+
+// SendControlRequest sends a control request to the claude process of the
+// most recently opened stream, as SendRawControlRequest does, and decodes the
+// response matching its request ID. It waits up to the request's Timeout, or
+// ControlRequestTimeout, and returns a TIMEOUT_ERROR if the CLI has not
+// answered by then. A response reporting failure is returned along with a
+// CONTROL_ERROR error holding the CLI's message.
+func (c *ClaudeCodeClient) SendControlRequest(ctx context.Context, request types.ControlRequest) (*types.ControlResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "JSON_MARSHAL", "failed to encode control request")
+	}
+
+	timeout := request.Timeout
+	if timeout == 0 {
+		timeout = c.settings().ControlRequestTimeout
+	}
+	if timeout == 0 {
+		timeout = defaultControlRequestTimeout
+	}
+	start := time.Now()
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	frame, err := c.SendRawControlRequest(requestCtx, raw)
+	if err != nil {
+		if ctx.Err() == nil && requestCtx.Err() == context.DeadlineExceeded {
+			return nil, sdkerrors.NewTimeoutError("control request "+request.Subtype, timeout, time.Since(start))
+		}
+		return nil, err
+	}
+
+	response, err := types.ParseControlResponse(frame)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONTROL_RESPONSE", "invalid control response from claude")
+	}
+	if !response.Success() {
+		return response, sdkerrors.NewInternalError("CONTROL_ERROR", "control request "+request.Subtype+" failed: "+response.Error)
+	}
+	return response, nil
+}
+
+// InterruptTurn asks the claude process of the most recently opened stream to
+// stop its current turn. Unlike Interrupt, the process is not killed: the
+// stream receives the CLI's result for the interrupted turn.
+func (c *ClaudeCodeClient) InterruptTurn(ctx context.Context) (*types.InterruptAck, error) {
+	response, err := c.SendControlRequest(ctx, types.ControlRequest{Subtype: types.ControlSubtypeInterrupt})
+	if err != nil {
+		return nil, err
+	}
+	return &types.InterruptAck{RequestID: response.RequestID}, nil
+}
+
+// SetModel switches the model of the most recently opened stream for its
+// following turns.
+func (c *ClaudeCodeClient) SetModel(ctx context.Context, model string) (*types.SetModelAck, error) {
+	if model == "" {
+		return nil, sdkerrors.NewValidationError("model", "", "required", "model cannot be empty")
+	}
+
+	response, err := c.SendControlRequest(ctx, types.ControlRequest{
+		Subtype: types.ControlSubtypeSetModel,
+		Params:  map[string]any{"model": model},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ack := &types.SetModelAck{Model: model}
+	if err := response.Decode(ack); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONTROL_RESPONSE", "invalid set_model response from claude")
+	}
+	ack.RequestID = response.RequestID
+	return ack, nil
+}
+
+// SetPermissionMode changes how the most recently opened stream grants tool
+// permissions, e.g. to "acceptEdits" or "plan".
+func (c *ClaudeCodeClient) SetPermissionMode(ctx context.Context, mode string) (*types.PermissionModeAck, error) {
+	if mode == "" {
+		return nil, sdkerrors.NewValidationError("mode", "", "required", "permission mode cannot be empty")
+	}
+
+	response, err := c.SendControlRequest(ctx, types.ControlRequest{
+		Subtype: types.ControlSubtypeSetPermissionMode,
+		Params:  map[string]any{"mode": mode},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ack := &types.PermissionModeAck{Mode: mode}
+	if err := response.Decode(ack); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONTROL_RESPONSE", "invalid set_permission_mode response from claude")
+	}
+	ack.RequestID = response.RequestID
+	return ack, nil
+}
+
+// SubscribeRawFrames calls fn with every JSON frame the claude CLI writes to
+// the client's streams and jobs, including frames the SDK does not parse, such
+// as control responses and new message types. fn runs on the goroutine
+// reading the output, so it must not block, and must not modify the frame.
+// The returned function ends the subscription.
+func (c *ClaudeCodeClient) SubscribeRawFrames(fn func(frame json.RawMessage)) (unsubscribe func()) {
+	c.controlMu.Lock()
+	defer c.controlMu.Unlock()
+
+	if c.frameSubs == nil {
+		c.frameSubs = make(map[uint64]func(json.RawMessage))
+	}
+	c.frameSubSeq++
+	id := c.frameSubSeq
+	c.frameSubs[id] = fn
+
+	return func() {
+		c.controlMu.Lock()
+		defer c.controlMu.Unlock()
+		delete(c.frameSubs, id)
+	}
+}
+
+// publishFrame passes a line of CLI output to the raw frame subscribers if
+// it holds a JSON object.
+func (c *ClaudeCodeClient) publishFrame(line []byte) {
+	c.controlMu.Lock()
+	if len(c.frameSubs) == 0 {
+		c.controlMu.Unlock()
+		return
+	}
+	subscribers := make([]func(json.RawMessage), 0, len(c.frameSubs))
+	for _, fn := range c.frameSubs {
+		subscribers = append(subscribers, fn)
+	}
+	c.controlMu.Unlock()
+
+	line = bytes.TrimSpace(line)
+	if !bytes.HasPrefix(line, []byte("{")) {
+		return
+	}
+	frame := make(json.RawMessage, len(line))
+	copy(frame, line)
+	for _, fn := range subscribers {
+		fn(frame)
+	}
+}
+
+// trackControl registers the control channel of an open stream with the
+// client.
+func (c *ClaudeCodeClient) trackControl(control *controlChannel) {
+	c.controlMu.Lock()
+	defer c.controlMu.Unlock()
+	c.controls = append(c.controls, control)
+}
+
+// untrackControl removes a control channel registered with trackControl and
+// closes it.
+func (c *ClaudeCodeClient) untrackControl(control *controlChannel) {
+	c.controlMu.Lock()
+	for i, ch := range c.controls {
+		if ch == control {
+			c.controls = append(c.controls[:i], c.controls[i+1:]...)
+			break
+		}
+	}
+	c.controlMu.Unlock()
+	control.close()
+}
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// controlCLIScript echoes the prompt read from stdin, then answers control
+// requests until an interrupt or one with the subtype "finish" ends the
+// query. It rejects the permission mode "yolo" and never answers requests
+// with the subtype "slow".
+const controlCLIScript = `read -r prompt_line
+printf '%s\n' "$prompt_line"
+while read -r line; do
+  id=$(printf '%s' "$line" | sed -n 's/.*"request_id":"\([^"]*\)".*/\1/p')
+  case "$line" in
+    *'"subtype":"finish"'*|*'"subtype":"interrupt"'*)
+      echo '{"type":"control_response","response":{"subtype":"success","request_id":"'$id'"}}'
+      echo '{"type":"result","subtype":"success","result":"done"}' ;;
+    *'"mode":"yolo"'*)
+      echo '{"type":"control_response","response":{"subtype":"error","request_id":"'$id'","error":"unknown permission mode yolo"}}' ;;
+    *'"subtype":"slow"'*) ;;
+    *)
+      echo '{"type":"control_response","response":{"subtype":"success","request_id":"'$id'","response":{"model":"claude-opus-4"}}}' ;;
+  esac
+done`
+
+func TestSendRawControlRequest(t *testing.T) {
+	client := newFakeCLIClient(t, controlCLIScript)
+	client.config.ControlProtocol = true
+
+	var mu sync.Mutex
+	var frames []string
+	unsubscribe := client.SubscribeRawFrames(func(frame json.RawMessage) {
+		mu.Lock()
+		frames = append(frames, string(frame))
+		mu.Unlock()
+	})
+	defer unsubscribe()
+
+	ctx := context.Background()
+	stream, err := client.QueryStream(ctx, userRequest("hi"))
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		for {
+			chunk, err := stream.Recv()
+			if err != nil || chunk.Done {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	response, err := client.SendRawControlRequest(ctx, json.RawMessage(`{"subtype": "set_model", "model": "claude-opus-4"}`))
+	if err != nil {
+		t.Fatalf("SendRawControlRequest failed: %v", err)
+	}
+	var frame struct {
+		Type     string `json:"type"`
+		Response struct {
+			RequestID string          `json:"request_id"`
+			Response  json.RawMessage `json:"response"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(response, &frame); err != nil {
+		t.Fatalf("Invalid response frame %s: %v", response, err)
+	}
+	if frame.Type != "control_response" || frame.Response.RequestID != "req_1" || string(frame.Response.Response) != `{"model":"claude-opus-4"}` {
+		t.Errorf("Unexpected response frame: %s", response)
+	}
+
+	if _, err := client.SendRawControlRequest(ctx, json.RawMessage(`{"subtype":"finish"}`)); err != nil {
+		t.Fatalf("SendRawControlRequest failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	_, err = client.SendRawControlRequest(ctx, json.RawMessage(`{"subtype":"interrupt"}`))
+	if sdkErr, ok := err.(sdkerrors.SDKError); !ok || sdkErr.Code() != "CONTROL_UNAVAILABLE" {
+		t.Errorf("Expected CONTROL_UNAVAILABLE after the result, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(frames) != 4 {
+		t.Fatalf("Expected 4 frames, got %d: %v", len(frames), frames)
+	}
+	if !strings.Contains(frames[0], `"type":"user"`) || !strings.Contains(frames[0], `"text":"hi"`) {
+		t.Errorf("Expected the prompt on stdin, got %s", frames[0])
+	}
+	if !strings.Contains(frames[3], `"type":"result"`) {
+		t.Errorf("Expected the result frame last, got %s", frames[3])
+	}
+}
+
+func TestSendControlRequest(t *testing.T) {
+	client := newFakeCLIClient(t, controlCLIScript)
+	client.config.ControlProtocol = true
+
+	ctx := context.Background()
+	stream, err := client.QueryStream(ctx, userRequest("hi"))
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		for {
+			chunk, err := stream.Recv()
+			if err != nil || chunk.Done {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	modelAck, err := client.SetModel(ctx, "claude-opus-4")
+	if err != nil {
+		t.Fatalf("SetModel failed: %v", err)
+	}
+	if modelAck.RequestID != "req_1" || modelAck.Model != "claude-opus-4" {
+		t.Errorf("Unexpected set_model ack: %+v", modelAck)
+	}
+
+	modeAck, err := client.SetPermissionMode(ctx, "acceptEdits")
+	if err != nil {
+		t.Fatalf("SetPermissionMode failed: %v", err)
+	}
+	if modeAck.RequestID != "req_2" || modeAck.Mode != "acceptEdits" {
+		t.Errorf("Unexpected set_permission_mode ack: %+v", modeAck)
+	}
+
+	_, err = client.SetPermissionMode(ctx, "yolo")
+	if sdkErr, ok := err.(sdkerrors.SDKError); !ok || sdkErr.Code() != "CONTROL_ERROR" || !strings.Contains(err.Error(), "unknown permission mode yolo") {
+		t.Errorf("Expected CONTROL_ERROR with the CLI's message, got %v", err)
+	}
+
+	_, err = client.SendControlRequest(ctx, types.ControlRequest{Subtype: "slow", Timeout: 50 * time.Millisecond})
+	if timeoutErr, ok := err.(*sdkerrors.TimeoutError); !ok || timeoutErr.Timeout != 50*time.Millisecond {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+
+	for _, request := range []types.ControlRequest{
+		{},
+		{Subtype: "interrupt", Params: map[string]any{"subtype": "set_model"}},
+		{Subtype: "interrupt", Timeout: -time.Second},
+	} {
+		if _, err := client.SendControlRequest(ctx, request); err == nil {
+			t.Errorf("Expected an error for invalid request %+v", request)
+		}
+	}
+
+	interruptAck, err := client.InterruptTurn(ctx)
+	if err != nil {
+		t.Fatalf("InterruptTurn failed: %v", err)
+	}
+	if interruptAck.RequestID != "req_5" {
+		t.Errorf("Unexpected interrupt ack: %+v", interruptAck)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+}
+
+func TestSendRawControlRequest_Errors(t *testing.T) {
+	client := newFakeCLIClient(t, controlCLIScript)
+	ctx := context.Background()
+
+	_, err := client.SendRawControlRequest(ctx, json.RawMessage(`{"subtype":"interrupt"}`))
+	if sdkErr, ok := err.(sdkerrors.SDKError); !ok || sdkErr.Code() != "CONTROL_UNAVAILABLE" {
+		t.Errorf("Expected CONTROL_UNAVAILABLE without ControlProtocol, got %v", err)
+	}
+
+	client.config.ControlProtocol = true
+	stream, err := client.QueryStream(ctx, userRequest("hi"))
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	for _, request := range []string{`not json`, `["interrupt"]`, ``} {
+		_, err := client.SendRawControlRequest(ctx, json.RawMessage(request))
+		if sdkErr, ok := err.(sdkerrors.SDKError); !ok || sdkErr.Category() != sdkerrors.CategoryValidation {
+			t.Errorf("Expected a validation error for %q, got %v", request, err)
+		}
+	}
+
+	// Canceling gives up on the response; closing the stream ends control requests
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.SendRawControlRequest(canceled, json.RawMessage(`{"subtype":"interrupt"}`))
+	if sdkErr, ok := err.(sdkerrors.SDKError); !ok || sdkErr.Code() != "CONTEXT_CANCELED" {
+		t.Errorf("Expected CONTEXT_CANCELED, got %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	_, err = client.SendRawControlRequest(ctx, json.RawMessage(`{"subtype":"interrupt"}`))
+	if sdkErr, ok := err.(sdkerrors.SDKError); !ok || sdkErr.Code() != "CONTROL_UNAVAILABLE" {
+		t.Errorf("Expected CONTROL_UNAVAILABLE after Close, got %v", err)
+	}
+}
+
+func TestSubscribeRawFrames_Unsubscribe(t *testing.T) {
+	client := newFakeCLIClient(t, `echo '{"type":"system","subtype":"init"}'; echo 'not a frame'; echo '{"type":"result","subtype":"success","result":"ok"}'`)
+
+	var frames []string
+	unsubscribe := client.SubscribeRawFrames(func(frame json.RawMessage) {
+		frames = append(frames, string(frame))
+	})
+
+	drain := func() {
+		stream, err := client.QueryStream(context.Background(), userRequest("hi"))
+		if err != nil {
+			t.Fatalf("QueryStream failed: %v", err)
+		}
+		defer stream.Close()
+		for {
+			chunk, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("Recv failed: %v", err)
+			}
+			if chunk.Done {
+				return
+			}
+		}
+	}
+
+	drain()
+	if len(frames) != 2 || frames[0] != `{"type":"system","subtype":"init"}` {
+		t.Errorf("Expected the 2 JSON frames, got %v", frames)
+	}
+
+	unsubscribe()
+	drain()
+	if len(frames) != 2 {
+		t.Errorf("Expected no frames after unsubscribing, got %v", frames)
+	}
+}
+
+func TestControlChannel_ProcessExit(t *testing.T) {
+	client := newFakeCLIClient(t, `read -r prompt_line`)
+	client.config.ControlProtocol = true
+
+	stream, err := client.QueryStream(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	chunk, err := stream.Recv()
+	if err != nil || !chunk.Done {
+		t.Fatalf("Expected the stream to end, got %+v, %v", chunk, err)
+	}
+	_, err = client.SendRawControlRequest(context.Background(), json.RawMessage(`{"subtype":"interrupt"}`))
+	if sdkErr, ok := err.(sdkerrors.SDKError); !ok || sdkErr.Code() != "CONTROL_UNAVAILABLE" {
+		t.Errorf("Expected CONTROL_UNAVAILABLE after the process exited, got %v", err)
+	}
+}
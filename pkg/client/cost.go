@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/pricing"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// estimateTolerance is the relative error assumed for locally estimated token counts
+const estimateTolerance = 0.15
+
+// SetModelPricing overrides the price used for a model (or model prefix) by
+// EstimateCost and the usage tracker. Overrides take precedence over the
+// pricing provider.
+func (c *ClaudeCodeClient) SetModelPricing(model string, modelPricing types.ModelPricing) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Copy on write: lookupPrice reads the table after releasing the lock
+	overrides := make(pricing.Table, len(c.modelPricing)+1)
+	for name, price := range c.modelPricing {
+		overrides[name] = price
+	}
+	overrides[model] = pricing.FromModelPricing(modelPricing)
+	c.modelPricing = overrides
+}
+
+// SetPricingProvider replaces the built-in price table, for example with
+// Bedrock or Vertex AI pricing. A nil provider restores the built-in table.
+func (c *ClaudeCodeClient) SetPricingProvider(provider pricing.Provider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pricingProvider = provider
+}
+
+// UsageTracker returns the tracker that accumulates token usage and cost of
+// the responses returned by Query.
+func (c *ClaudeCodeClient) UsageTracker() *pricing.UsageTracker {
+	return c.usageTracker
+}
+
+// lookupPrice returns the price for a model, preferring client overrides over
+// the pricing provider.
+func (c *ClaudeCodeClient) lookupPrice(model string) (pricing.Price, bool) {
+	c.mu.RLock()
+	overrides, provider := c.modelPricing, c.pricingProvider
+	c.mu.RUnlock()
+
+	if provider == nil {
+		provider = pricing.Default()
+	}
+	return pricing.Chain{overrides, provider}.Price(model)
+}
+
+// recordUsage adds the usage of a response to the usage tracker, labeled
+// with the query metadata of ctx.
+func (c *ClaudeCodeClient) recordUsage(ctx context.Context, request *types.QueryRequest, response *types.QueryResponse) {
+	if c.usageTracker == nil || response == nil || response.Usage == nil {
+		return
+	}
+
+	model := response.Model
+	if model == "" {
+		model = request.Model
+	}
+	if model == "" {
+		model = c.settings().Model
+	}
+	c.usageTracker.RecordLabeled(model, *response.Usage, QueryMetadata(ctx))
+}
+
+// EstimateCost estimates the cost of a request before running it. Input tokens
+// are counted with CountTokens; output tokens range from zero up to the
+// request's MaxTokens (or the configured default). When the input count is
+// estimated locally, the input range is widened to account for estimator error.
+func (c *ClaudeCodeClient) EstimateCost(ctx context.Context, request *types.QueryRequest) (*types.CostEstimate, error) {
+	count, err := c.CountTokens(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	price, ok := c.lookupPrice(count.Model)
+	if !ok {
+		return nil, sdkerrors.NewValidationError("model", count.Model, "model with known pricing",
+			"no pricing available for model "+count.Model+"; use SetModelPricing to provide it")
+	}
+
+	maxOutput := request.MaxTokens
+	if maxOutput <= 0 {
+		maxOutput = c.settings().MaxTokens
+	}
+	if maxOutput <= 0 {
+		maxOutput = types.DefaultMaxTokens
+	}
+
+	minInput, maxInput := count.InputTokens, count.InputTokens
+	if count.Estimated {
+		minInput = int(float64(count.InputTokens) * (1 - estimateTolerance))
+		maxInput = int(float64(count.InputTokens)*(1+estimateTolerance) + 0.5)
+	}
+
+	return &types.CostEstimate{
+		Model:           count.Model,
+		MinInputTokens:  minInput,
+		MaxInputTokens:  maxInput,
+		MaxOutputTokens: maxOutput,
+		MinCost:         price.InputCost(minInput),
+		MaxCost:         price.Cost(types.TokenUsage{InputTokens: maxInput, OutputTokens: maxOutput}),
+		Currency:        price.Currency,
+		Estimated:       count.Estimated,
+	}, nil
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/pricing"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestClaudeCodeClient_EstimateCost(t *testing.T) {
+	// Keep the estimator path offline regardless of the host environment
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	request := &types.QueryRequest{
+		Model:     "claude-sonnet-4-20250514",
+		Messages:  []types.Message{{Role: types.RoleUser, Content: "Summarize the repository layout"}},
+		MaxTokens: 1000,
+	}
+
+	estimate, err := client.EstimateCost(context.Background(), request)
+	if err != nil {
+		t.Fatalf("EstimateCost failed: %v", err)
+	}
+
+	if !estimate.Estimated || estimate.Currency != "USD" || estimate.MaxOutputTokens != 1000 {
+		t.Errorf("Unexpected estimate: %+v", estimate)
+	}
+	if estimate.MinInputTokens >= estimate.MaxInputTokens {
+		t.Errorf("Expected a widened input range, got %d-%d", estimate.MinInputTokens, estimate.MaxInputTokens)
+	}
+
+	expectedMax := float64(estimate.MaxInputTokens)/1000*0.003 + 1000.0/1000*0.015
+	if math.Abs(estimate.MaxCost-expectedMax) > 1e-9 {
+		t.Errorf("Expected max cost %f, got %f", expectedMax, estimate.MaxCost)
+	}
+	if estimate.MinCost >= estimate.MaxCost {
+		t.Errorf("Expected min cost below max cost, got %f-%f", estimate.MinCost, estimate.MaxCost)
+	}
+
+	// Overrides take precedence over the built-in table
+	client.SetModelPricing("claude-sonnet-4", types.ModelPricing{InputCostPer1K: 0, OutputCostPer1K: 1, Currency: "EUR"})
+	estimate, err = client.EstimateCost(context.Background(), request)
+	if err != nil {
+		t.Fatalf("EstimateCost failed: %v", err)
+	}
+	if estimate.Currency != "EUR" || estimate.MaxCost != 1 {
+		t.Errorf("Expected override pricing to apply, got %+v", estimate)
+	}
+}
+
+func TestClaudeCodeClient_EstimateCostUnknownModel(t *testing.T) {
+	// Keep the estimator path offline regardless of the host environment
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	request := &types.QueryRequest{
+		Model:    "custom-model",
+		Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}},
+	}
+	if _, err := client.EstimateCost(context.Background(), request); err == nil {
+		t.Error("Expected error for model without pricing")
+	}
+}
+
+func TestClaudeCodeClient_PricingProvider(t *testing.T) {
+	// Keep the estimator path offline regardless of the host environment
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	client.SetPricingProvider(pricing.Table{
+		"claude-sonnet-4": {InputPerMTok: 6, OutputPerMTok: 30, Currency: "USD"},
+	})
+
+	request := &types.QueryRequest{
+		Model:     "us.anthropic.claude-sonnet-4-20250514-v1:0",
+		Messages:  []types.Message{{Role: types.RoleUser, Content: "hi"}},
+		MaxTokens: 1000,
+	}
+	estimate, err := client.EstimateCost(context.Background(), request)
+	if err != nil {
+		t.Fatalf("EstimateCost failed: %v", err)
+	}
+	if math.Abs(estimate.MaxCost-(float64(estimate.MaxInputTokens)*6+1000*30)/1e6) > 1e-9 {
+		t.Errorf("Expected provider pricing to apply, got %+v", estimate)
+	}
+
+	// The usage tracker prices usage with the same provider
+	client.recordUsage(context.Background(), request, &types.QueryResponse{
+		Model: request.Model,
+		Usage: &types.TokenUsage{InputTokens: 1000000, OutputTokens: 100000},
+	})
+	if cost := client.UsageTracker().TotalCost(); math.Abs(cost-9) > 1e-9 {
+		t.Errorf("Expected tracked cost 9, got %f", cost)
+	}
+}
+
+func TestClaudeCodeClient_SetModelPricingConcurrent(t *testing.T) {
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	// Run with -race: prices are looked up while overrides are added
+	done := make(chan struct{})
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				client.lookupPrice(fmt.Sprintf("model-%d", i%100))
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		client.SetModelPricing(fmt.Sprintf("model-%d", i), types.ModelPricing{InputCostPer1K: 0.001, Currency: "USD"})
+	}
+	close(done)
+
+	if price, ok := client.lookupPrice("model-99"); !ok || price.InputPerMTok != 1 {
+		t.Errorf("Expected the last override to apply, got %+v", price)
+	}
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"strings"
+)
+
+type apiKeyKey struct{}
+
+// WithAPIKey returns a context whose queries authenticate with apiKey instead
+// of the client's configured credentials. It lets one client serve many
+// users, such as a gateway issuing queries with each caller's own key.
+// QueryOptions.APIKey does the same for QueryMessages.
+func WithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, strings.TrimSpace(apiKey))
+}
+
+// apiKeyOverride returns the API key attached to ctx with WithAPIKey, or "".
+func apiKeyOverride(ctx context.Context) string {
+	apiKey, _ := ctx.Value(apiKeyKey{}).(string)
+	return apiKey
+}
+
+// apiKey returns the API key queries made with ctx authenticate with.
+func (c *ClaudeCodeClient) apiKey(ctx context.Context) string {
+	if apiKey := apiKeyOverride(ctx); apiKey != "" {
+		return apiKey
+	}
+	return c.settings().APIKey
+}
+
+// withCredentials applies the API key attached to ctx to a subprocess
+// environment. It is appended so that it takes precedence over the client's
+// key and the host's ANTHROPIC_API_KEY.
+func withCredentials(ctx context.Context, env []string) []string {
+	if apiKey := apiKeyOverride(ctx); apiKey != "" {
+		env = append(env, "ANTHROPIC_API_KEY="+apiKey)
+	}
+	return env
+}
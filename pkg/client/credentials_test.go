@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestWithAPIKey(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "key=$ANTHROPIC_API_KEY"`)
+	client.config.APIKey = "client-key"
+
+	response, err := client.Query(context.Background(), userRequest("hi"))
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if text := response.Content[0].Text; text != "key=client-key" {
+		t.Errorf("Expected the client's key, got %q", text)
+	}
+
+	// Concurrent queries each use their caller's key
+	var wg sync.WaitGroup
+	for _, key := range []string{"user-a", "user-b", "user-c"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			response, err := client.Query(WithAPIKey(context.Background(), key), userRequest("hi"))
+			if err != nil {
+				t.Errorf("Query failed: %v", err)
+				return
+			}
+			if text := response.Content[0].Text; text != "key="+key {
+				t.Errorf("Expected key %s, got %q", key, text)
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	if client.apiKey(WithAPIKey(context.Background(), " ")) != "client-key" {
+		t.Error("Expected a blank override to fall back to the client's key")
+	}
+}
+
+func TestQueryOptions_APIKey(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: key=$ANTHROPIC_API_KEY"`)
+	client.config.APIKey = "client-key"
+
+	result, err := client.QueryMessagesSync(context.Background(), "hi", &QueryOptions{APIKey: "user-key"})
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+	if output := result.Messages[len(result.Messages)-1].Content; !strings.Contains(output, "key=user-key") {
+		t.Errorf("Expected the per-query key, got %q", output)
+	}
+}
+
+// userRequest returns a request with a single user message.
+func userRequest(content string) *types.QueryRequest {
+	return &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: content}}}
+}
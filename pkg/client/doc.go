@@ -87,6 +87,84 @@ The SDK supports streaming responses for real-time output:
 	}
 	fmt.Printf("Complete response: %s\n", response.GetTextContent())
 
+Each chunk holds one line of CLI output, which can be hundreds of megabytes
+when a tool reads a large file, up to 256MB. Lines are read in linear time
+into a buffer that becomes the chunk's content without being copied again;
+internal observers such as file access tracking decode the same bytes and
+measure Read results without copying them.
+
+Images in a chunk's line, whether from Claude or from tool results such as
+browser screenshots, are parsed into chunk.Images for rendering:
+
+	for _, image := range chunk.Images {
+		fmt.Printf("<img src=%q>\n", image.DataURL())
+	}
+
+StreamTo writes the reply text straight into an io.Writer, flushing
+http.Flusher and bufio.Writer destinations as text arrives, and SSEHandler
+serves queries as server-sent events:
+
+	result, err := client.StreamTo(ctx, "Summarize the README", nil, os.Stdout)
+
+	http.Handle("/chat", client.SSEHandler(func(r *http.Request) (string, *client.QueryOptions, error) {
+		return r.FormValue("prompt"), &client.QueryOptions{Model: "claude-sonnet-4"}, nil
+	}))
+
+Compare runs one prompt against several models or option sets at once, each
+in a new session, and returns the replies in order with their latency and
+cost. The runs share the working directory, so unless a config's options
+select tools they may only read files:
+
+	comparison, err := client.Compare(ctx, "Refactor parser.go", []client.ModelConfig{
+		{Model: "claude-sonnet-4"},
+		{Model: "claude-opus-4"},
+		{Name: "terse", Model: "claude-sonnet-4", Options: &client.QueryOptions{SystemPrompt: "Answer briefly."}},
+	})
+	for _, result := range comparison.Results {
+		fmt.Printf("%s: %s, $%.4f\n", result.Name, result.Latency, result.CostUSD)
+	}
+	if cheapest := comparison.Cheapest(); cheapest != nil {
+		fmt.Println("cheapest:", cheapest.Name)
+	}
+
+# Attachments
+
+User messages can carry images and text files instead of pasting them into
+the prompt. Paths are relative to the working directory and are read when
+the query runs:
+
+	response, err := client.Query(ctx, &types.QueryRequest{
+		Messages: []types.Message{{
+			Role:    types.RoleUser,
+			Content: "Why does the page render like this?",
+			Attachments: []types.Attachment{
+				types.NewImageAttachment("screenshot.png"),
+				types.NewFileAttachment("web/page.html"),
+			},
+		}},
+	})
+
+Requests with attachments send the prompt to the CLI on stdin as stream-json
+rather than as an argument.
+
+Document attachments, such as a spec PDF, are sent as document blocks. Text
+documents longer than types.DocumentChunkSize are split into titled parts at
+line boundaries:
+
+	Attachments: []types.Attachment{types.NewDocumentAttachment("spec.pdf")}
+
+# Prompt Templates
+
+QueryTemplate renders a named template from the prompts package and runs it.
+The built-in templates cover common tasks such as code review:
+
+	result, err := client.QueryTemplate(ctx, prompts.CodeReview, map[string]any{
+		"code":  string(source),
+		"focus": "error handling",
+	}, nil)
+
+SetPromptLibrary replaces the built-in library with your own templates.
+
 # Session Management
 
 Sessions provide conversation persistence with UUID validation:
@@ -116,6 +194,69 @@ Sessions provide conversation persistence with UUID validation:
 	// List all active sessions
 	sessionIDs := client.ListSessions()
 
+Each session can run in its own checkout, so one client serves several
+projects concurrently without calling SetWorkingDirectory. A session's
+queries use its directories; the client's defaults are left untouched:
+
+	err = session.SetProjectDirectory("/src/service-a")
+	err = session.SetAdditionalDirectories("../shared")
+
+Sessions can also be keyed by your own conversation IDs. A SessionRegistry
+gives each external ID a random UUID for the CLI and remembers the mapping
+in both directions, so GetSession accepts either ID and a later
+CreateSession with the same external ID resumes the conversation:
+
+	registry, err := client.NewFileSessionRegistry(".claude/session-ids.json")
+	client.Sessions().SetRegistry(registry)
+
+	session, err := client.CreateSession(ctx, "JIRA-4821")
+	fmt.Println(session.ID, session.ExternalID()) // random UUID, "JIRA-4821"
+
+ListStoredSessions reads the CLI's own session history, including
+conversations from earlier runs, for "resume a previous conversation"
+pickers. Each entry has the session's directory, timestamps, CLI-generated
+summary and the start of its first prompt:
+
+	stored, err := client.ListStoredSessions(ctx)
+	for _, s := range stored {
+		fmt.Printf("%s  %s  %s\n", s.UpdatedAt.Format(time.Stamp), s.WorkingDirectory, s.FirstPrompt)
+	}
+	session, err := client.CreateSession(ctx, stored[0].ID)
+
+Branch forks a session's conversation after an earlier turn into a new
+session, for regenerating a reply or exploring a different follow-up. The
+original session is unchanged:
+
+	branch, err := session.Branch(ctx, 2) // keep the first two turns
+	response, err := branch.Query(ctx, &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "Try a different approach"}},
+	})
+
+Regenerate answers the last prompt again in a branch, optionally with a
+different model, temperature or system prompt, and returns the alternative
+alongside the original reply:
+
+	alt, err := session.Regenerate(ctx, client.RegenerateOptions{Model: "claude-opus-4"})
+	fmt.Printf("original: %s\nalternative: %s\n", alt.Original, alt.Response.GetTextContent())
+	session = alt.Session // keep the alternative
+
+PurgeSession deletes everything kept about a session for data-deletion
+requests: the open session, its job checkpoints, its registry mapping and
+the CLI's transcript files. The report lists what was removed:
+
+	purge, err := client.PurgeSession(ctx, "JIRA-4821")
+	log.Printf("purged %s: %d checkpoints, %d files", purge.SessionID, len(purge.Checkpoints), len(purge.Files))
+
+ServeWebSocket turns a WebSocket connection into a chat with a session. The
+browser sends {"prompt": "..."} messages and receives each reply as
+types.WebSocketEvent JSON: delta, tool_use and message events, then a result
+or error event. Any connection with ReadJSON and WriteJSON methods, such as
+gorilla/websocket's, can be served:
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	defer conn.Close()
+	err = session.ServeWebSocket(r.Context(), conn)
+
 # Tool System
 
 Claude Code provides various tools for file operations and code analysis:
@@ -178,6 +319,74 @@ The Model Context Protocol extends Claude Code with additional capabilities:
 	// Remove a server
 	err = client.RemoveMCPServer(ctx, "filesystem")
 
+MCPServerStatus reports each server's connection state, tool count, last
+error and check latency. Set OnMCPServerUnhealthy to be told when a server
+fails, and MonitorMCPServers to check periodically:
+
+	config.OnMCPServerUnhealthy = func(status types.MCPServerStatus) {
+		log.Printf("MCP server %s is %s: %s", status.Name, status.State, status.LastError)
+	}
+
+	statuses, err := client.MCPServerStatus(ctx)
+	client.MonitorMCPServers(ctx, time.Minute)
+
+ListMCPTools asks a server for its tools and their JSON input schemas, for
+building UIs or exact allowed tool lists:
+
+	tools, err := client.ListMCPTools(ctx, "github")
+	for _, tool := range tools {
+		fmt.Println(tool.QualifiedName(), tool.Description) // mcp__github__create_issue ...
+	}
+
+Server definitions can be shared with interactive CLI users through the CLI's
+.mcp.json format:
+
+	err = client.MCP().WriteConfigFile(".mcp.json")
+
+	servers, err := client.LoadMCPConfigFile(".mcp.json") // expands ${VAR} references
+	err = client.MCP().ImportConfigFile(".mcp.json")
+
+Package mcpserver provides filesystem, fetch and memory servers written in Go
+that run as subcommands of the embedding program, without Node or npx:
+
+	fs, err := mcpserver.Command(mcpserver.BuiltinFilesystem, "/path/to/project")
+	err = client.AddMCPServer(ctx, "filesystem", fs)
+
+# Local Go Tools
+
+A ToolRegistry lets the model call Go functions in the embedding program.
+The input schema is derived from the argument struct's `json` and
+`description` tags, and the client serves the tools to the CLI over a
+loopback MCP server as "mcp__sdk__<name>", allowed without prompting:
+
+	type lookupArguments struct {
+		OrderID string `json:"order_id" description:"Order to look up"`
+	}
+
+	registry := client.NewToolRegistry()
+	err := client.RegisterTool(registry, "lookup_order", "Look up an order",
+		func(ctx context.Context, args lookupArguments) (string, error) {
+			return orders.Describe(ctx, args.OrderID)
+		})
+	claude.SetToolRegistry(registry)
+
+Long-running tools report progress with mcpserver.ReportProgress and
+mcpserver.ReportLog. Updates reach OnToolProgress and, for QueryMessages,
+arrive in the message stream as system messages with the "tool_progress"
+subtype:
+
+	mcpserver.ReportProgress(ctx, 40, "scanning orders")
+
+	for msg := range messages {
+		if msg.Metadata["subtype"] == "tool_progress" {
+			fmt.Println(msg.Content) // lookup_order: 40% scanning orders
+		}
+	}
+
+Handler contexts are cancelled by Interrupt and Close, and when the query's
+context is cancelled; a handler that then fails is reported to the model as
+interrupted rather than left running orphaned work.
+
 # Project Context
 
 The SDK automatically detects and analyzes project information:
@@ -196,19 +405,85 @@ The SDK automatically detects and analyzes project information:
 	}
 	// Returns language, framework, dependencies, architecture patterns, etc.
 
-	// Change working directory
+	// Change working directory; the cached context is dropped with it
 	err = client.SetWorkingDirectory(ctx, "/new/project/path")
 
-	// Invalidate cached context when project changes
+	// Invalidate cached context after changes the cache does not watch
 	client.InvalidateProjectContextCache()
 
-	// Configure cache duration
+	// Optionally cap the cache age
 	client.SetProjectContextCacheDuration(5 * time.Minute)
 
 	// Get cache information
 	cacheInfo := client.GetProjectContextCacheInfo()
 	fmt.Printf("Cache info: %+v\n", cacheInfo)
 
+The enhanced context is cached until a file it was built from changes: the
+manifests (go.mod, package.json, pyproject.toml and their lock files), the
+ignore files, the workspace roots, git's HEAD, index and changed files, and
+the indexed Go sources. They are watched with fsnotify, so edits are picked
+up without a fixed TTL; the watch is stopped when the client is closed.
+
+The enhanced context's Stack lists the languages, frameworks and tools found
+by detectors. Built-in detectors cover Go, JavaScript, TypeScript, Python,
+common frameworks, Docker and Make; register a Detector to recognize more,
+for every client with RegisterDetector or for one with
+RegisterProjectDetector:
+
+	client.RegisterDetector(client.NewFileDetector(
+		types.Technology{Name: "Terraform", Kind: types.TechInfrastructure}, "*.tf"))
+
+	for _, language := range enhanced.Stack.Languages() {
+		fmt.Println(language.Name, language.Version)
+	}
+
+In a monorepo declared by go.work, pnpm-workspace.yaml, package.json
+workspaces or a Cargo workspace, the enhanced context's Workspace lists the
+subprojects. FocusPackage narrows the analysis to one of them, by name or
+path, and GetPackageContext analyzes one without changing the focus:
+
+	err = client.FocusPackage("services/api")
+	api, err := client.GetEnhancedProjectContext(ctx) // api.Focus == "services/api"
+
+SetWorkingDirectory rejects paths that are missing or not directories. Set
+ProjectMarkers to also require a marker such as go.mod or .git, and
+OnWorkingDirectoryChange to be told when the directory changes:
+
+	config.ProjectMarkers = []string{"go.mod", "package.json", ".git"}
+	config.OnWorkingDirectoryChange = func(change types.WorkingDirectoryChange) {
+		log.Printf("switched from %s to %s", change.From, change.To)
+	}
+
+Project analysis skips secrets, dependencies (DefaultIgnorePatterns) and the
+paths listed in the project's .gitignore and .claudeignore files. Set
+EnforceClaudeIgnore to also deny Claude's Read and Edit tools access to the
+.claudeignore paths:
+
+	config.EnforceClaudeIgnore = true
+
+# Pinned Context
+
+PinContext and PinFile pin snippets into the system prompt of every later
+query, after the query's own system prompt, such as a style guide or an API
+definition the model should always see. Their estimated size is counted by
+CountTokens and capped by PinnedContextMaxTokens (8000 tokens by default):
+
+	err := client.PinFile("docs/STYLE.md")
+	err = client.PinContext("glossary", "A widget is a unit of billable work.")
+	used, limit := client.PinnedContextTokens()
+	client.Unpin("glossary")
+
+# Long-Term Memory
+
+Set Memory to a types.MemoryRecaller, such as a memory.Manager, and the facts
+it recalls for the first prompt of each new session are added to that
+session's system prompt, up to MemoryRecallLimit facts (5 by default). A
+failing recall leaves the query without memories rather than failing it:
+
+	memories := memory.New(store)
+	_, err := memories.Remember(ctx, "Deploys run on Fridays", "release")
+	config.Memory = memories
+
 # Error Handling
 
 The client provides detailed error types for different scenarios:
@@ -223,6 +498,325 @@ The client provides detailed error types for different scenarios:
 		}
 	}
 
+Query options are validated before the CLI starts. Invalid settings, such as
+a negative MaxTurns, an unknown tool name, a tool both allowed and disallowed
+or an MCP server without a command, are reported together in one
+ValidationError whose Violations name each field:
+
+	var validationErr *errors.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, v := range validationErr.Violations {
+			log.Printf("%s: %s", v.Field, v.Message)
+		}
+	}
+
+# Profiles
+
+Profiles are named presets of model, permission mode, tools and turn budget,
+so services can share settings such as "fast-cheap", "deep-review" or "ci".
+They are defined in ClaudeCodeConfig.Profiles (or a config file) or registered
+at runtime, and selected per query or as the client default:
+
+	claude.RegisterProfile("ci", &types.Profile{
+		Model:          "claude-3-5-haiku-20241022",
+		PermissionMode: "rejectEdits",
+		AllowedTools:   []string{"Read", "Grep"},
+		MaxTurns:       5,
+	})
+
+	result, err := claude.QueryMessagesSync(ctx, "Review this diff", &client.QueryOptions{Profile: "ci"})
+
+	// Or apply it to every query that does not select a profile
+	err = claude.SetProfile("ci")
+
+Settings made explicitly on QueryOptions take precedence over the profile.
+
+# Configuration Reload
+
+A running client can pick up new MCP servers, profiles, tool timeouts and
+other settings without restarting the embedding service. ReloadConfig applies
+a config directly; WatchConfig reloads whenever a config file changes:
+
+	err := claude.WatchConfig(ctx, "/etc/myapp/claude.yaml", 0, func(err error) {
+		if err != nil {
+			log.Printf("reload failed: %v", err)
+		}
+	})
+
+Changes apply to queries and sessions started afterwards.
+
+# Webhook Notifications
+
+Long-running queries can report progress to a webhook instead of holding a
+stream open. Turn, tool and completion events (including cost) are POSTed in
+the background and signed with HMAC-SHA256 when a secret is set:
+
+	config.Webhook = &types.WebhookConfig{
+		URL:    "https://jobs.example.com/hooks/claude",
+		Secret: os.Getenv("WEBHOOK_SECRET"),
+	}
+
+Receivers check requests with VerifyWebhookSignature using the
+X-Claude-Timestamp and X-Claude-Signature headers.
+
+Unattended agents can post summaries, costs and errors straight to a chat
+channel by setting Format to WebhookFormatSlack or WebhookFormatTeams with
+the channel's incoming webhook URL.
+
+# CLI Flag Passthrough
+
+ExtraArgs passes flags the SDK does not model yet straight to the CLI. A nil
+value produces a boolean flag:
+
+	effort := "high"
+	config.ExtraArgs = map[string]*string{"--effort": &effort, "--experimental": nil}
+
+QueryOptions.ExtraArgs adds or overrides flags for a single query.
+
+SupportedFeatures reports which flags the installed CLI accepts. Set
+CLIFeatureCheck to types.CLIFeatureCheckWarn or types.CLIFeatureCheckError to
+check every invocation against it:
+
+	features, _ := claude.SupportedFeatures(ctx)
+	if features.Supports(client.FeatureForkSession) {
+		// ...
+	}
+
+# Control Protocol
+
+With ControlProtocol set, QueryStream writes the prompt to the CLI's stdin and
+keeps it open until the result arrives. SendRawControlRequest sends a control
+request to the most recent such stream and returns the CLI's raw
+control_response frame, so new protocol features can be used before the SDK
+models them. The stream must be read concurrently for the response to arrive:
+
+	config.ControlProtocol = true
+	stream, _ := claude.QueryStream(ctx, request)
+	go drain(stream)
+	frame, err := claude.SendRawControlRequest(ctx, json.RawMessage(`{"subtype":"interrupt"}`))
+
+SetModel, SetPermissionMode and InterruptTurn send typed requests and return
+the CLI's acknowledgement. SendControlRequest sends any request, matches the
+response by request ID and fails with a TIMEOUT_ERROR when the CLI does not
+answer within the request's Timeout or ControlRequestTimeout (default 30
+seconds). Requests the CLI rejects return a CONTROL_ERROR error:
+
+	if _, err := claude.SetModel(ctx, "claude-opus-4"); err != nil {
+		return err
+	}
+	resp, err := claude.SendControlRequest(ctx, types.ControlRequest{
+		Subtype: "mcp_status",
+		Timeout: 5 * time.Second,
+	})
+
+SubscribeRawFrames observes every JSON frame the CLI writes, including those
+the SDK does not parse:
+
+	unsubscribe := claude.SubscribeRawFrames(func(frame json.RawMessage) {
+		log.Printf("frame: %s", frame)
+	})
+	defer unsubscribe()
+
+# Subprocess Environment
+
+The CLI subprocess, and the MCP servers it starts, only inherit a minimal set
+of host variables (types.DefaultEnvAllowlist). EnvPolicy widens or narrows it:
+
+	config.EnvPolicy = &types.EnvPolicy{
+		Allow:     []string{"AWS_*"},
+		DenyRegex: []string{"(?i)secret|password"},
+	}
+
+Set InheritAll to pass the whole environment except denied variables.
+
+AWS and Google Cloud credentials are not in the default allowlist. When
+CLAUDE_CODE_USE_BEDROCK or CLAUDE_CODE_USE_VERTEX is set, in the host
+environment or in Environment, the provider's variables
+(types.BedrockEnvAllowlist or types.VertexEnvAllowlist) are passed too;
+with NoDefaults they must be allowed explicitly.
+
+Proxy routes the CLI, and the MCP servers it starts, through a corporate
+proxy without setting proxy variables by hand:
+
+	config.Proxy = &types.ProxyConfig{
+		URL:      "http://proxy.corp.example:3128",
+		Username: "svc-claude",
+		Password: os.Getenv("PROXY_PASSWORD"),
+		NoProxy:  []string{"localhost", ".corp.example"},
+	}
+
+Gateway sends API requests to an LLM gateway, for central logging and
+policy enforcement, with optional bearer token, headers and client
+certificate:
+
+	config.Gateway = &types.GatewayConfig{
+		BaseURL:   "https://llm-gateway.corp.example/anthropic",
+		AuthToken: os.Getenv("GATEWAY_TOKEN"),
+		Headers:   map[string]string{"X-Team": "platform"},
+		TLS:       &types.TLSConfig{CertFile: "client.pem", KeyFile: "client.key"},
+	}
+
+CertificateTrust adds a CA bundle, e.g. for a TLS-inspecting proxy, or pins
+the CLI to a set of certificates it trusts in place of its built-in roots.
+The files must exist and hold PEM certificates when the client is created:
+
+	config.CertificateTrust = &types.CertificateTrust{
+		PinnedCertsFile: "/etc/ssl/llm-gateway.pem",
+	}
+
+CountTokens and EstimateCost, which call the count-tokens endpoint
+directly, use the same proxy, gateway and certificate trust.
+
+Clients running in parallel share the CLI's settings, session history and
+locks in ~/.claude. IsolatedConfigDir gives a client its own directory,
+passed to the CLI as CLAUDE_CONFIG_DIR. An isolated directory holds no
+subscription login, so use API key authentication or log in with
+CLAUDE_CONFIG_DIR set to it:
+
+	config.IsolatedConfigDir = filepath.Join(os.TempDir(), "claude-worker-1")
+
+# Per-Request Credentials
+
+A single client can serve many users, each with their own API key, such as
+a gateway over shared infrastructure. WithAPIKey attaches a key to a context
+and QueryOptions.APIKey sets one for QueryMessages; the key replaces the
+client's credentials for that query only, including token counting:
+
+	response, err := client.Query(client.WithAPIKey(ctx, user.APIKey), request)
+
+# Query Metadata
+
+WithQueryMetadata tags the queries made with a context, such as with the
+feature, user or experiment they serve. The tags are added to responses,
+messages and stream chunks under types.QueryMetadataKey, to webhook and
+file access events, and to job checkpoints, and label the usage tracker so
+cost can be broken down by any tag:
+
+	ctx = client.WithQueryMetadata(ctx, map[string]string{"feature": "search"})
+	response, err := c.Query(ctx, request)
+	byFeature := c.UsageTracker().UsageByLabel("feature")
+
+QueryOptions.Metadata does the same for QueryMessages.
+
+# Tool Timeouts
+
+ToolTimeouts bounds individual tool executions so one runaway command cannot
+stall an automated pipeline. When a tool overruns, the query is interrupted
+and fails with an errors.TimeoutError:
+
+	config.ToolTimeouts = map[string]time.Duration{
+		"Bash":     30 * time.Second,
+		"WebFetch": 60 * time.Second,
+	}
+
+QueryOptions.ToolTimeouts overrides the client's timeouts for one query.
+
+# Bash Sandbox
+
+BashSandbox runs the commands of the Bash tool in a container instead of on
+the host. A PreToolUse hook rewrites each command into a `docker run` (or
+`podman exec` into an existing container); if the hook fails, the command is
+blocked. The hook re-executes the embedding program, so it must call
+RunHookSubcommand at the start of main:
+
+	func main() {
+		client.RunHookSubcommand()
+
+		config := types.NewClaudeCodeConfig()
+		config.BashSandbox = &types.BashSandbox{Image: "golang:1.22", Network: "none"}
+		// ...
+	}
+
+# Web Domain Policy
+
+WebDomains limits the domains WebFetch and WebSearch may reach. It is passed
+to the CLI as WebFetch permission rules, and a hook (which also needs
+RunHookSubcommand) denies other fetches and restricts search domains:
+
+	config.WebDomains = &types.WebDomainPolicy{
+		AllowedDomains: []string{"go.dev", "github.com"},
+		BlockedDomains: []string{"gist.github.com"},
+	}
+
+# Tool Permissions
+
+AllowedTools and DisallowedTools accept wildcards: "mcp__fs__*" covers every
+tool of an MCP server and "Notebook*" every matching built-in tool, while
+specifiers such as "Bash(npm run *)" scope a rule to matching uses. A deny
+rule always wins over an allow rule. ResolveTools reports the effective
+decision for each tool:
+
+	resolution, err := claude.ResolveTools(&client.QueryOptions{
+		AllowedTools:    []string{"Read", "mcp__fs__*"},
+		DisallowedTools: []string{"mcp__fs__delete_file"},
+	})
+	fmt.Print(resolution)
+
+# Tool Approvals
+
+ApprovalProvider asks a human before Claude edits files or runs commands. A
+PreToolUse hook (which also needs RunHookSubcommand) calls back into the
+client's loopback approval server for each tool matching ApprovalTools,
+authenticated with a token passed in the CLI's environment rather than on
+its command line, and denied calls are reported to Claude with the
+reviewer's reason. The approval package has terminal,
+HTTP callback and Slack providers:
+
+	config.ApprovalProvider = approval.ForPaths(approval.NewStdinProvider(), "deploy/prod/**")
+	config.ApprovalTimeout = 5 * time.Minute
+
+Calls are denied when the provider fails or does not answer in time.
+
+# File Access Events
+
+OnFileAccess reports every file read, written or edited through Claude's
+tools in streaming queries and jobs, for "files touched" views or compliance
+logs:
+
+	config.OnFileAccess = func(event types.FileAccessEvent) {
+		log.Printf("%s %s (%d bytes) in session %s", event.Op, event.Path, event.Bytes, event.SessionID)
+	}
+
+# Secret Redaction
+
+A Redactor scrubs secrets from prompts and system prompts before they reach
+the CLI, and from tool inputs sent to webhooks. The redact package provides
+one with detectors for common API keys, tokens and private keys:
+
+	config.Redactor = redact.New()
+
+Pass the same redactor to recorder.WithRedactor to scrub recorded cassettes.
+
+# Message Policies
+
+A MessagePolicy checks the system prompt and messages of each request before
+the CLI starts, and response text before it is returned, rewriting or
+blocking them. Tags it reports are recorded under "policy_tags" in the
+metadata of requests, responses, messages and stream chunks. The pii package
+provides one for personal data:
+
+	config.MessagePolicy = pii.New(nil)
+
+# Resumable Jobs
+
+RunJob runs a multi-minute task in its own session and checkpoints the session
+ID, turn count and accumulated messages to a SessionStore as turns complete.
+After a host restart, ResumeJob continues the job with --resume:
+
+	store, _ := client.NewFileSessionStore("/var/lib/myapp/checkpoints")
+	claude.SetSessionStore(store)
+
+	response, err := claude.RunJob(ctx, "nightly-refactor", request)
+	// ... after a restart
+	response, err = claude.ResumeJob(ctx, "nightly-refactor")
+
+The separate sqlitestore module provides a SessionStore backed by SQLite,
+which also keeps each job's transcript queryable. The archive package wraps
+any SessionStore to archive the transcripts of completed jobs to S3 or GCS.
+
+Images and other binary data in tool results are checkpointed as attachments
+of the tool message, with their bytes and media types intact.
+
 # Subprocess Management
 
 The client manages the Claude Code CLI subprocess lifecycle:
@@ -233,5 +827,108 @@ The client manages the Claude Code CLI subprocess lifecycle:
 
 All subprocess operations are handled internally, providing a clean API while
 ensuring proper resource management.
+
+# Test Mode
+
+With TestMode and a TestScript, the client never starts the CLI: queries are
+answered in-process with canned responses, so unit tests of code built on
+the SDK need no CLI, credentials or network. Responses can make tool calls,
+which drive file access events and webhooks as real ones would, fail with an
+error, or hang until the query's context is done. Prompts no response
+matches get a reply generated from the seed, the same for every run:
+
+	config.TestMode = true
+	config.TestScript = &types.TestScript{
+		Responses: []types.TestResponse{
+			{Match: "deploy", Error: "rate limited"},
+			{Match: "slow", Timeout: true},
+		},
+		Seed: 1,
+	}
+
+# Fault Injection
+
+Faults injects failures into the CLI's output to test retry and recovery
+logic: dropped lines, delayed lines, JSON lines truncated mid-object, and
+the process killed after a number of assistant turns. The lines affected are
+chosen from Seed, so a failure can be reproduced:
+
+	config.Faults = &types.FaultConfig{
+		DropRate:       0.05,
+		CorruptRate:    0.05,
+		KillAfterTurns: 3,
+		Seed:           time.Now().UnixNano(),
+	}
+
+# Circuit Breaker
+
+CircuitBreaker protects a service from piling retries onto a CLI or API that
+is down. After FailureThreshold consecutive CLI failures (processes that
+could not start, exited with an error or ran past their deadline) queries
+fail without starting the CLI, returning a CircuitOpenError, until CoolDown
+has passed. Then a probe query runs, and closes the circuit if it succeeds:
+
+	config.CircuitBreaker = &types.CircuitBreakerConfig{
+		FailureThreshold: 3,
+		CoolDown:         time.Minute,
+	}
+
+	var open *errors.CircuitOpenError
+	if errors.As(err, &open) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(open.RetryAfter.Seconds())+1))
+	}
+
+CircuitState reports the current state.
+
+# Concurrency
+
+A ClaudeCodeClient is safe for concurrent use. Every Query and QueryStream runs
+its own claude process, so concurrent queries never share a stdin or stdout
+pipe; each sees a consistent snapshot of the configuration even while
+ReloadConfig or SetWorkingDirectory runs. Interrupt stops every query in
+flight and leaves the client usable, while Close also rejects new queries and
+guarantees no process outlives it:
+
+	go func() {
+		<-stop
+		_ = claude.Interrupt() // Query returns an INTERRUPTED error
+	}()
+	response, err := claude.Query(ctx, request)
+
+# Client State
+
+State reports the client's lifecycle: Ready when idle, Connecting while a
+query starts the CLI, Busy while queries run, Interrupting until interrupted
+queries return, Disconnected after the CLI failed to start, and Closed. Set
+OnStateChange to drive a status indicator; changes are delivered in order:
+
+	config.OnStateChange = func(change types.ClientStateChange) {
+		statusBar.Set(string(change.To))
+	}
+
+# Graceful Shutdown
+
+A Manager tracks the clients and sessions of a service and shuts them all
+down with one call. Shutdown runs the OnShutdown hooks registered for the
+service's own dependents, waits for in-flight queries to finish, then closes
+sessions before their clients. Queries still running when the context ends
+are interrupted:
+
+	manager := client.NewManager()
+	claude, err := manager.NewClient(ctx, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	manager.OnShutdown("http", server.Shutdown)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := manager.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
 */
 package client
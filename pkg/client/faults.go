@@ -0,0 +1,126 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// injectFaults wraps the output of a process with the configured faults. It
+// returns the process unchanged when faults are disabled.
+func (c *ClaudeCodeClient) injectFaults(process *cliProcess) *cliProcess {
+	faults := c.settings().Faults
+	if faults == nil {
+		return process
+	}
+
+	process.stdout = &faultReader{
+		faults: faults,
+		source: bufio.NewReader(process.stdout),
+		closer: process.stdout,
+		rng:    rand.New(rand.NewSource(faults.Seed)), // #nosec G404 - faults must be reproducible
+		kill:   process.kill,
+		closed: make(chan struct{}),
+	}
+	return process
+}
+
+// faultReader applies a FaultConfig to process output line by line.
+type faultReader struct {
+	faults *types.FaultConfig
+	source *bufio.Reader
+	closer io.Closer
+	rng    *rand.Rand
+	kill   func() error
+
+	// pending is the rest of the line being read
+	pending []byte
+
+	// err is returned once pending is drained
+	err error
+
+	// turns counts the assistant turns written so far
+	turns int
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Read implements io.Reader.
+func (r *faultReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		line, err := r.source.ReadBytes('\n')
+		if err != nil {
+			r.err = err
+		}
+		if len(line) > 0 {
+			r.pending = r.apply(line)
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// apply returns a line as it is delivered after the faults, or nil if it is
+// dropped.
+func (r *faultReader) apply(line []byte) []byte {
+	if r.faults.Delay > 0 {
+		select {
+		case <-time.After(r.faults.Delay):
+		case <-r.closed:
+			return nil
+		}
+	}
+
+	if r.faults.DropRate > 0 && r.rng.Float64() < r.faults.DropRate {
+		return nil
+	}
+
+	if isTurnLine(line) {
+		r.turns++
+		if r.faults.KillAfterTurns > 0 && r.turns >= r.faults.KillAfterTurns {
+			// The line was written before the process died; nothing after it is
+			_ = r.kill() // Ignore error, the process may already have exited
+			r.err = io.EOF
+		}
+	}
+
+	if r.faults.CorruptRate > 0 && bytes.HasPrefix(bytes.TrimSpace(line), []byte("{")) && r.rng.Float64() < r.faults.CorruptRate {
+		line = append(line[:len(line)/2:len(line)/2], '\n')
+	}
+	return line
+}
+
+// Close implements io.Closer.
+func (r *faultReader) Close() error {
+	r.closeOnce.Do(func() { close(r.closed) })
+	return r.closer.Close()
+}
+
+// isTurnLine reports whether a line of output starts an assistant turn, in
+// either the stream-json or the text format.
+func isTurnLine(line []byte) bool {
+	trimmed := bytes.TrimSpace(line)
+	if bytes.HasPrefix(trimmed, []byte("Claude:")) || bytes.HasPrefix(trimmed, []byte("Assistant:")) {
+		return true
+	}
+	if !bytes.HasPrefix(trimmed, []byte("{")) || !bytes.Contains(trimmed, []byte(`"assistant"`)) {
+		return false
+	}
+	var message struct {
+		Type string `json:"type"`
+	}
+	return json.Unmarshal(trimmed, &message) == nil && message.Type == "assistant"
+}
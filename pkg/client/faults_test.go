@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// faultyStreamLines runs a scripted query with two tool calls through the
+// given faults and returns the streamed lines, without the random session
+// ID, and the stream error.
+func faultyStreamLines(t *testing.T, faults *types.FaultConfig) ([]string, error) {
+	t.Helper()
+	client := newScriptedClient(t, &types.TestScript{
+		Responses: []types.TestResponse{{
+			Text: "Done.",
+			ToolUses: []types.TestToolUse{
+				{Name: "Read", Input: map[string]any{"file_path": "a.go"}, Result: "package a"},
+				{Name: "Read", Input: map[string]any{"file_path": "b.go"}, Result: "package b"},
+			},
+		}},
+	})
+	client.config.Faults = faults
+
+	stream, err := client.QueryStream(context.Background(), userRequest("read both"))
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	var lines []string
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return lines, err
+		}
+		if chunk.Done {
+			return lines, nil
+		}
+		lines = append(lines, strings.ReplaceAll(chunk.Content, client.sessionID, "<session>"))
+	}
+}
+
+func TestFaults_KillAfterTurns(t *testing.T) {
+	lines, err := faultyStreamLines(t, &types.FaultConfig{KillAfterTurns: 2})
+	if err == nil {
+		t.Fatal("Expected the killed process to fail the stream")
+	}
+
+	// init, first tool call and result, second tool call
+	if len(lines) != 4 || !strings.Contains(lines[3], `"tool_use"`) {
+		t.Errorf("Expected output up to the second turn, got %v", lines)
+	}
+}
+
+func TestFaults_Drop(t *testing.T) {
+	lines, err := faultyStreamLines(t, &types.FaultConfig{DropRate: 1})
+	if err != nil || len(lines) != 0 {
+		t.Errorf("Expected every line to be dropped, got %v, %v", lines, err)
+	}
+
+	first, _ := faultyStreamLines(t, &types.FaultConfig{DropRate: 0.5, Seed: 3})
+	second, _ := faultyStreamLines(t, &types.FaultConfig{DropRate: 0.5, Seed: 3})
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("The same seed should drop the same lines:\n%v\n%v", first, second)
+	}
+	if len(first) == 0 || len(first) == 7 {
+		t.Errorf("Expected some lines to be dropped, got %d of 7", len(first))
+	}
+}
+
+func TestFaults_Corrupt(t *testing.T) {
+	lines, err := faultyStreamLines(t, &types.FaultConfig{CorruptRate: 1})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	if len(lines) != 7 {
+		t.Fatalf("Expected 7 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if json.Valid([]byte(line)) || !strings.HasSuffix(line, "\n") {
+			t.Errorf("Expected a truncated line, got %q", line)
+		}
+	}
+}
+
+func TestFaults_Delay(t *testing.T) {
+	start := time.Now()
+	lines, err := faultyStreamLines(t, &types.FaultConfig{Delay: 10 * time.Millisecond})
+	if err != nil || len(lines) != 7 {
+		t.Fatalf("Expected all lines, got %d, %v", len(lines), err)
+	}
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("Expected each line to be delayed, took %v", elapsed)
+	}
+}
+
+func TestFaults_QueryMessages(t *testing.T) {
+	client := newScriptedClient(t, &types.TestScript{
+		Responses: []types.TestResponse{{Text: "Never seen."}},
+	})
+	client.config.Faults = &types.FaultConfig{KillAfterTurns: 1}
+
+	// The turn line is delivered before the kill
+	result, err := client.QueryMessagesSync(context.Background(), "hi", nil)
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+	if last := result.Messages[len(result.Messages)-1]; last.Content != "Never seen." {
+		t.Errorf("Last message = %+v", last)
+	}
+}
+
+func TestIsTurnLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`{"type":"assistant","message":{"content":[]}}`, true},
+		{`{"type":"user","message":{"content":[{"type":"text","text":"assistant"}]}}`, false},
+		{"Claude: hello\n", true},
+		{"Result: ok\n", false},
+		{`{"type":"assist`, false},
+	}
+	for _, tt := range tests {
+		if got := isTurnLine([]byte(tt.line)); got != tt.want {
+			t.Errorf("isTurnLine(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// CLIFeature identifies an optional CLI capability by the flag that enables it.
+type CLIFeature string
+
+const (
+	// FeatureForkSession forks a resumed session into a new session ID
+	FeatureForkSession CLIFeature = "--fork-session"
+
+	// FeatureAgents defines custom subagents
+	FeatureAgents CLIFeature = "--agents"
+
+	// FeaturePartialMessages streams partial assistant messages
+	FeaturePartialMessages CLIFeature = "--include-partial-messages"
+
+	// FeatureSettings loads settings from a file or JSON string
+	FeatureSettings CLIFeature = "--settings"
+
+	// FeatureSettingSources selects which settings files are loaded
+	FeatureSettingSources CLIFeature = "--setting-sources"
+
+	// FeatureAddDir grants access to additional directories
+	FeatureAddDir CLIFeature = "--add-dir"
+
+	// FeatureSessionID runs a query in a caller-chosen session
+	FeatureSessionID CLIFeature = "--session-id"
+
+	// FeaturePermissionMode selects the permission mode
+	FeaturePermissionMode CLIFeature = "--permission-mode"
+
+	// FeatureMCPConfig loads MCP servers from a config file
+	FeatureMCPConfig CLIFeature = "--mcp-config"
+)
+
+// CLIFeatures describes the capabilities of the installed claude CLI.
+type CLIFeatures struct {
+	// Version is the CLI version, e.g. "1.0.98"
+	Version string
+
+	// Flags lists the long flags accepted by the CLI, sorted
+	Flags []string
+
+	flags map[string]bool
+}
+
+// Supports reports whether the CLI supports a feature.
+func (f *CLIFeatures) Supports(feature CLIFeature) bool {
+	return f.SupportsFlag(string(feature))
+}
+
+// SupportsFlag reports whether the CLI accepts a long flag, given with or
+// without the leading "--".
+func (f *CLIFeatures) SupportsFlag(flag string) bool {
+	if f == nil {
+		return false
+	}
+	return f.flags["--"+strings.TrimLeft(flag, "-")]
+}
+
+var (
+	helpFlagPattern = regexp.MustCompile(`--[a-zA-Z][a-zA-Z0-9-]*`)
+	versionPattern  = regexp.MustCompile(`\d+\.\d+\.\d+[0-9A-Za-z.+-]*`)
+	argFlagPattern  = regexp.MustCompile(`^--[a-zA-Z][a-zA-Z0-9-]*$`)
+)
+
+// parseCLIFeatures extracts the version and flags from `claude --version` and
+// `claude --help` output.
+func parseCLIFeatures(versionOutput, helpOutput string) *CLIFeatures {
+	features := &CLIFeatures{
+		Version: versionPattern.FindString(versionOutput),
+		flags:   make(map[string]bool),
+	}
+
+	for _, flag := range helpFlagPattern.FindAllString(helpOutput, -1) {
+		if !features.flags[flag] {
+			features.flags[flag] = true
+			features.Flags = append(features.Flags, flag)
+		}
+	}
+	sort.Strings(features.Flags)
+
+	return features
+}
+
+// SupportedFeatures probes the installed CLI's --version and --help output to
+// report which flags it supports. The result is cached for the client's lifetime.
+func (c *ClaudeCodeClient) SupportedFeatures(ctx context.Context) (*CLIFeatures, error) {
+	c.featuresMu.Lock()
+	defer c.featuresMu.Unlock()
+
+	if c.features != nil {
+		return c.features, nil
+	}
+
+	versionOutput, err := c.probeCLI(ctx, "--version")
+	if err != nil {
+		return nil, err
+	}
+	helpOutput, err := c.probeCLI(ctx, "--help")
+	if err != nil {
+		return nil, err
+	}
+
+	c.features = parseCLIFeatures(versionOutput, helpOutput)
+	return c.features, nil
+}
+
+// probeCLI runs an informational CLI command, such as --version or mcp list,
+// in the working directory and returns its output.
+func (c *ClaudeCodeClient) probeCLI(ctx context.Context, args ...string) (string, error) {
+	if c.scripted != nil {
+		return c.scripted.probe(args), nil
+	}
+
+	env, err := c.subprocessEnvironment()
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.claudeCodeCmd, args...) // #nosec G204 - claudeCodeCmd is validated during initialization
+	cmd.Dir = c.currentWorkingDir()
+	cmd.Env = env
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		return "", sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "CLI_PROBE", "failed to run claude "+strings.Join(args, " "))
+	}
+	return output.String(), nil
+}
+
+// checkCLIFlags validates the flags of an invocation against the installed CLI
+// according to the configured CLIFeatureCheck mode.
+func (c *ClaudeCodeClient) checkCLIFlags(ctx context.Context, args []string) error {
+	mode := c.settings().CLIFeatureCheck
+	if mode == types.CLIFeatureCheckOff {
+		return nil
+	}
+
+	features, err := c.SupportedFeatures(ctx)
+	if err != nil {
+		return err
+	}
+
+	var unsupported []string
+	for _, arg := range args {
+		if argFlagPattern.MatchString(arg) && !features.SupportsFlag(arg) {
+			unsupported = append(unsupported, arg)
+		}
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("claude CLI %s does not support %s", features.Version, strings.Join(unsupported, ", "))
+	if mode == types.CLIFeatureCheckError {
+		return sdkerrors.NewConfigurationError("cli_feature_check", message)
+	}
+
+	if onWarning := c.settings().OnCLIWarning; onWarning != nil {
+		onWarning(message)
+	} else {
+		fmt.Fprintln(os.Stderr, "claude-code-sdk: warning: "+message)
+	}
+	return nil
+}
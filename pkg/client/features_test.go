@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const featureScript = `
+case "$1" in
+--version) echo "1.0.98 (Claude Code)" ;;
+--help)
+	echo "Usage: claude [options] [prompt]"
+	echo "  -p, --print                 Print response and exit"
+	echo "  --model <model>             Model for the session"
+	echo "  --session-id <uuid>         Use a specific session ID"
+	echo "  --fork-session              Create a new session ID when resuming"
+	;;
+*) echo "handled $prompt" ;;
+esac`
+
+func TestParseCLIFeatures(t *testing.T) {
+	features := parseCLIFeatures("1.0.98 (Claude Code)\n", "  --print\n  --agents <json>  Define agents (--agents)\n")
+
+	if features.Version != "1.0.98" {
+		t.Errorf("Version = %q", features.Version)
+	}
+	if len(features.Flags) != 2 || !features.Supports(FeatureAgents) || !features.SupportsFlag("print") {
+		t.Errorf("Unexpected flags: %v", features.Flags)
+	}
+	if features.Supports(FeatureForkSession) {
+		t.Error("Expected --fork-session to be unsupported")
+	}
+}
+
+func TestSupportedFeatures(t *testing.T) {
+	client := newFakeCLIClient(t, featureScript)
+
+	features, err := client.SupportedFeatures(context.Background())
+	if err != nil {
+		t.Fatalf("SupportedFeatures failed: %v", err)
+	}
+	if features.Version != "1.0.98" || !features.Supports(FeatureForkSession) || features.Supports(FeaturePartialMessages) {
+		t.Errorf("Unexpected features: %+v", features)
+	}
+
+	cached, _ := client.SupportedFeatures(context.Background())
+	if cached != features {
+		t.Error("Expected features to be cached")
+	}
+}
+
+func TestCLIFeatureCheck(t *testing.T) {
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}
+
+	client := newFakeCLIClient(t, featureScript)
+	client.config.ExtraArgs = map[string]*string{"--include-partial-messages": nil}
+
+	// Off by default
+	if _, err := client.Query(context.Background(), request); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	var warnings []string
+	client.config.CLIFeatureCheck = types.CLIFeatureCheckWarn
+	client.config.OnCLIWarning = func(message string) { warnings = append(warnings, message) }
+	if _, err := client.Query(context.Background(), request); err != nil {
+		t.Fatalf("Query failed in warn mode: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "--include-partial-messages") {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+
+	client.config.CLIFeatureCheck = types.CLIFeatureCheckError
+	if _, err := client.Query(context.Background(), request); err == nil || !strings.Contains(err.Error(), "1.0.98") {
+		t.Errorf("Expected unsupported flag error, got %v", err)
+	}
+
+	client.config.ExtraArgs = nil
+	if _, err := client.Query(context.Background(), request); err != nil {
+		t.Errorf("Expected supported flags to pass, got %v", err)
+	}
+}
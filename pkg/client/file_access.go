@@ -0,0 +1,252 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fileAccessTracker turns the file tool uses of a single query into
+// FileAccessEvents. A nil tracker ignores all calls.
+type fileAccessTracker struct {
+	onAccess   func(types.FileAccessEvent)
+	workingDir string
+	metadata   map[string]string
+
+	mu        sync.Mutex
+	sessionID string
+	pending   map[string]types.FileAccessEvent
+}
+
+// trackFileAccess returns a tracker for a query in workingDir, or nil when
+// OnFileAccess is not set.
+func (c *ClaudeCodeClient) trackFileAccess(ctx context.Context, sessionID, workingDir string) *fileAccessTracker {
+	onAccess := c.settings().OnFileAccess
+	if onAccess == nil {
+		return nil
+	}
+	return &fileAccessTracker{
+		onAccess:   onAccess,
+		workingDir: workingDir,
+		metadata:   QueryMetadata(ctx),
+		sessionID:  sessionID,
+		pending:    make(map[string]types.FileAccessEvent),
+	}
+}
+
+// fileAccessLine is the subset of the CLI's stream-json output used to track file access.
+type fileAccessLine struct {
+	Type      string `json:"type"`
+	SessionID string `json:"session_id"`
+	Message   *struct {
+		Content []struct {
+			Type      string          `json:"type"`
+			ID        string          `json:"id"`
+			Name      string          `json:"name"`
+			Input     json.RawMessage `json:"input"`
+			ToolUseID string          `json:"tool_use_id"`
+			Content   textLength      `json:"content"`
+			IsError   bool            `json:"is_error"`
+		} `json:"content"`
+	} `json:"message"`
+
+	// ToolUseResult carries the raw file content of Read results
+	ToolUseResult *struct {
+		File *struct {
+			Content textLength `json:"content"`
+		} `json:"file"`
+	} `json:"tool_use_result"`
+}
+
+// textLength decodes tool result content, a string or a list of content
+// blocks, to the length of its text. Only the size of a Read result is
+// reported, so its content, which may be hundreds of megabytes, is measured
+// in place rather than copied into a string.
+type textLength int
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *textLength) UnmarshalJSON(data []byte) error {
+	*n = 0
+	switch {
+	case len(data) > 0 && data[0] == '"':
+		*n = textLength(unquotedLength(data))
+	case len(data) > 0 && data[0] == '[':
+		var blocks []struct {
+			Type string     `json:"type"`
+			Text textLength `json:"text"`
+		}
+		if err := json.Unmarshal(data, &blocks); err != nil {
+			return nil // Like toolResultMessage, unexpected content has no text
+		}
+		for _, block := range blocks {
+			if block.Type == "text" {
+				*n += block.Text
+			}
+		}
+	}
+	return nil
+}
+
+// unquotedLength returns the length in bytes of a valid JSON string literal
+// once decoded, matching encoding/json's handling of escapes, surrogate
+// pairs and invalid UTF-8.
+func unquotedLength(quoted []byte) int {
+	s := quoted[1 : len(quoted)-1]
+	n := 0
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && s[i+1] == 'u':
+			r := hexRune(s[i+2 : i+6])
+			i += 6
+			if utf16.IsSurrogate(r) {
+				if i+6 <= len(s) && s[i] == '\\' && s[i+1] == 'u' {
+					if utf16.DecodeRune(r, hexRune(s[i+2:i+6])) != utf8.RuneError {
+						n += 4
+						i += 6
+						continue
+					}
+				}
+				r = utf8.RuneError
+			}
+			n += utf8.RuneLen(r)
+		case c == '\\':
+			n++
+			i += 2
+		case c < utf8.RuneSelf:
+			n++
+			i++
+		default:
+			r, size := utf8.DecodeRune(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				n += utf8.RuneLen(utf8.RuneError)
+			} else {
+				n += size
+			}
+			i += size
+		}
+	}
+	return n
+}
+
+// hexRune parses the four hex digits of a \u escape.
+func hexRune(digits []byte) rune {
+	r, err := strconv.ParseUint(string(digits), 16, 32)
+	if err != nil {
+		return utf8.RuneError
+	}
+	return rune(r)
+}
+
+// fileToolInput is the union of the file tools' inputs.
+type fileToolInput struct {
+	FilePath     string `json:"file_path"`
+	NotebookPath string `json:"notebook_path"`
+	Content      string `json:"content"`
+	NewString    string `json:"new_string"`
+	NewSource    string `json:"new_source"`
+	Edits        []struct {
+		NewString string `json:"new_string"`
+	} `json:"edits"`
+}
+
+// observeLine records file tool uses and reports them when their results arrive.
+func (f *fileAccessTracker) observeLine(line []byte) {
+	if f == nil || !bytes.HasPrefix(bytes.TrimSpace(line), []byte("{")) {
+		return
+	}
+
+	var msg fileAccessLine
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	if msg.SessionID != "" {
+		f.sessionID = msg.SessionID
+	}
+	f.mu.Unlock()
+
+	if msg.Message == nil {
+		return
+	}
+
+	for _, block := range msg.Message.Content {
+		switch block.Type {
+		case "tool_use":
+			if event, ok := f.fileAccess(block.Name, block.Input); ok {
+				event.ToolUseID = block.ID
+				f.mu.Lock()
+				f.pending[block.ID] = event
+				f.mu.Unlock()
+			}
+
+		case "tool_result":
+			f.mu.Lock()
+			event, ok := f.pending[block.ToolUseID]
+			delete(f.pending, block.ToolUseID)
+			event.SessionID = f.sessionID
+			event.Metadata = f.metadata
+			f.mu.Unlock()
+			if !ok {
+				continue
+			}
+
+			event.Failed = block.IsError
+			if event.Op == types.FileOpRead && !event.Failed {
+				if msg.ToolUseResult != nil && msg.ToolUseResult.File != nil {
+					event.Bytes = int(msg.ToolUseResult.File.Content)
+				} else {
+					event.Bytes = int(block.Content)
+				}
+			}
+			event.Time = time.Now()
+			f.onAccess(event)
+		}
+	}
+}
+
+// fileAccess describes a tool use as a file access, if it is one.
+func (f *fileAccessTracker) fileAccess(tool string, raw json.RawMessage) (types.FileAccessEvent, bool) {
+	var input fileToolInput
+	if err := json.Unmarshal(raw, &input); err != nil {
+		return types.FileAccessEvent{}, false
+	}
+
+	event := types.FileAccessEvent{Tool: tool, Path: input.FilePath}
+	switch tool {
+	case "Read":
+		event.Op = types.FileOpRead
+	case "NotebookRead":
+		event.Op, event.Path = types.FileOpRead, input.NotebookPath
+	case "Write":
+		event.Op, event.Bytes = types.FileOpWrite, len(input.Content)
+	case "Edit":
+		event.Op, event.Bytes = types.FileOpEdit, len(input.NewString)
+	case "MultiEdit":
+		event.Op = types.FileOpEdit
+		for _, edit := range input.Edits {
+			event.Bytes += len(edit.NewString)
+		}
+	case "NotebookEdit":
+		event.Op, event.Path, event.Bytes = types.FileOpEdit, input.NotebookPath, len(input.NewSource)
+	default:
+		return types.FileAccessEvent{}, false
+	}
+
+	if event.Path == "" {
+		return types.FileAccessEvent{}, false
+	}
+	if !filepath.IsAbs(event.Path) {
+		event.Path = filepath.Join(f.workingDir, event.Path)
+	}
+	event.Path = filepath.Clean(event.Path)
+	return event, true
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestFileAccess_Stream(t *testing.T) {
+	client := newFakeCLIClient(t, `
+echo '{"type":"system","subtype":"init","session_id":"sess-1"}'
+echo '{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"main.go"}},{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"ls"}}]}}'
+echo '{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"     1  package main"}]},"tool_use_result":{"type":"text","file":{"filePath":"main.go","content":"package main"}}}'
+echo '{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":"main.go"}]}}'
+echo '{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t3","name":"Write","input":{"file_path":"/abs/out.txt","content":"hello"}},{"type":"tool_use","id":"t4","name":"MultiEdit","input":{"file_path":"pkg/../lib.go","edits":[{"old_string":"a","new_string":"abc"},{"old_string":"b","new_string":"de"}]}}]}}'
+echo '{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t3","content":"ok"},{"type":"tool_result","tool_use_id":"t4","content":"denied","is_error":true}]}}'
+echo '{"type":"result","result":"done"}'`)
+
+	var (
+		mu     sync.Mutex
+		events []types.FileAccessEvent
+	)
+	client.config.OnFileAccess = func(event types.FileAccessEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	stream, err := client.QueryStream(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "edit it"}},
+	})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if chunk.Done {
+			break
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 file events, got %+v", events)
+	}
+
+	want := []types.FileAccessEvent{
+		{Path: filepath.Join(client.workingDir, "main.go"), Op: types.FileOpRead, Bytes: 12, Tool: "Read", ToolUseID: "t1"},
+		{Path: "/abs/out.txt", Op: types.FileOpWrite, Bytes: 5, Tool: "Write", ToolUseID: "t3"},
+		{Path: filepath.Join(client.workingDir, "lib.go"), Op: types.FileOpEdit, Bytes: 5, Tool: "MultiEdit", ToolUseID: "t4", Failed: true},
+	}
+	for i, event := range events {
+		if event.Time.IsZero() || event.SessionID != "sess-1" {
+			t.Errorf("Event %d: missing time or session: %+v", i, event)
+		}
+		event.Time, event.SessionID = want[i].Time, ""
+		if !reflect.DeepEqual(event, want[i]) {
+			t.Errorf("Event %d = %+v, want %+v", i, event, want[i])
+		}
+	}
+}
+
+func TestFileAccess_Disabled(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	if tracker := client.trackFileAccess(context.Background(), "", ""); tracker != nil {
+		t.Error("Expected no tracker without OnFileAccess")
+	}
+
+	var tracker *fileAccessTracker
+	tracker.observeLine([]byte(`{"type":"assistant"}`)) // Must not panic
+}
+
+func TestTextLength(t *testing.T) {
+	tests := []string{
+		`"package main"`,
+		`"tab\tquote\" slash\\ unicode é emoji 😀"`,
+		`"lone \ud83d surrogate \udc00"`,
+		"\"invalid \xff utf-8 and é\"",
+		`[{"type":"text","text":"a\nb"},{"type":"image","text":"ignored"},{"type":"text","text":"c"}]`,
+		`null`,
+		`{"type":"text"}`,
+	}
+	for _, content := range tests {
+		var got struct {
+			Content textLength `json:"content"`
+		}
+		data := []byte(`{"content":` + content + `}`)
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s) failed: %v", content, err)
+		}
+		if want := len(toolResultMessage("", json.RawMessage(content)).Content); int(got.Content) != want {
+			t.Errorf("textLength(%s) = %d, want %d", content, got.Content, want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Run a target with: go test -run '^$' -fuzz FuzzParseStreamJSONOutput ./pkg/client
+//
+// The CLI's output is untrusted input to an embedding service: every parser
+// below must reject malformed lines without panicking.
+
+// streamJSONSeeds are representative stream-json lines, including the shapes
+// that previously needed special handling.
+var streamJSONSeeds = []string{
+	`{"type":"system","subtype":"init","session_id":"s1","model":"claude-sonnet-4","tools":["Read"],"mcp_servers":[{"name":"fs","status":"connected"}]}`,
+	`{"type":"assistant","message":{"model":"claude-sonnet-4","content":[{"type":"text","text":"hi"},{"type":"tool_use","id":"toolu_1","name":"Write","input":{"file_path":"a.go","content":"package a"}}]}}`,
+	`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"ok"}],"is_error":false}]}}`,
+	`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"plain"}]}}`,
+	`{"type":"system","subtype":"compact_boundary","compact_metadata":{"trigger":"auto","pre_tokens":1000}}`,
+	`{"type":"result","subtype":"success","result":"done","num_turns":1,"total_cost_usd":0.1,"usage":{"input_tokens":1,"output_tokens":2}}`,
+	`{"type":"result","is_error":true,"result":"boom"}`,
+	`{"type":"assistant","message":null}`,
+	`{"type":"assistant","message":{"content":null}}`,
+	`{"type":"user","message":{"content":"a string instead of blocks"}}`,
+	`{"type":1,"message":[]}`,
+	`{`,
+	``,
+	`Claude: plain text output`,
+}
+
+func FuzzParseStreamJSONOutput(f *testing.F) {
+	f.Add(strings.Join(streamJSONSeeds, "\n"))
+	for _, seed := range streamJSONSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, output string) {
+		response, err := parseStreamJSONOutput([]byte(output))
+		if err == nil && response == nil {
+			t.Error("parseStreamJSONOutput returned neither a response nor an error")
+		}
+	})
+}
+
+func FuzzToolResultMessage(f *testing.F) {
+	for _, seed := range []string{`"text"`, `[{"type":"text","text":"a"},{"type":"image"}]`, `[{"type":"image","source":{"type":"base64","media_type":"image/png","data":"iVBO"}}]`, `[]`, `null`, `{}`, `[1,2]`, `[null]`, `"unterminated`} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		_ = toolResultMessage("t1", json.RawMessage(content))
+	})
+}
+
+func FuzzStreamLineObservers(f *testing.F) {
+	for _, seed := range streamJSONSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		line := []byte(text)
+		client := &ClaudeCodeClient{config: types.NewClaudeCodeConfig(), workingDir: "/work"}
+		notifier, err := newWebhookNotifier(&types.WebhookConfig{URL: "http://127.0.0.1:1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.webhooks = notifier
+		client.config.OnFileAccess = func(types.FileAccessEvent) {}
+
+		tracker := client.trackWebhooks(context.Background(), &types.QueryRequest{}, "")
+		tracker.observeLine(line)
+		tracker.fail(context.Canceled)
+
+		client.trackFileAccess(context.Background(), "", "").observeLine(line)
+		client.observeMCPInit(line)
+
+		deadlines := newToolDeadlines(map[string]time.Duration{"*": time.Hour}, func() {})
+		deadlines.observeLine(line)
+		deadlines.stop()
+
+		_, _ = types.ParseCompactBoundary(line)
+		_, _ = types.ParseSystemInit(line)
+		_ = types.ParseImages(line)
+	})
+}
+
+func FuzzParseStreamEvent(f *testing.F) {
+	for _, seed := range []string{
+		`{"type":"message_start","message":{"id":"m","role":"assistant","model":"claude"}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"hi"}}`,
+		`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":3}}`,
+		`{"type":"error","error":{"type":"overloaded_error","message":"busy"}}`,
+		`{"type":"content_block_delta","delta":null}`,
+		`{"index":"zero"}`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		reader := &advancedStreamReader{opts: &types.StreamOptions{IncludeRawEvents: true}}
+		event, err := reader.parseStreamEvent(line)
+		if err == nil && event == nil {
+			t.Error("parseStreamEvent returned neither an event nor an error")
+		}
+	})
+}
+
+func FuzzParseStreamingOutput(f *testing.F) {
+	f.Add("Claude: hello\nmore text\nTool: Read {\"id\":\"t1\",\"name\":\"Read\",\"input\":{}}\nResult: ok\nMax turns reached\n")
+	f.Add("Tool:\nTool: {\nResult:\nAssistant:")
+	f.Add("Tool: {\"id\":null,\"name\":[1]}")
+
+	f.Fuzz(func(t *testing.T, output string) {
+		client := &ClaudeCodeClient{config: types.NewClaudeCodeConfig()}
+		messageChan := make(chan *types.Message, 1)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range messageChan {
+			}
+		}()
+
+		deadlines := newToolDeadlines(map[string]time.Duration{"*": time.Hour}, func() {})
+		client.parseStreamingOutput(strings.NewReader(output), messageChan, &QueryOptions{}, deadlines)
+		deadlines.stop()
+		close(messageChan)
+		<-done
+	})
+}
+
+func TestQueryStream_LongLine(t *testing.T) {
+	// A tool result with a large file arrives as a single line
+	client := newFakeCLIClient(t, `printf '{"type":"user","message":{"content":[{"type":"tool_result","content":"'; head -c 200000 /dev/zero | tr '\0' a; printf '"}]}}\n'`)
+
+	stream, err := client.QueryStream(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "read the log"}},
+	})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	chunk, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if len(chunk.Content) < 200000 {
+		t.Errorf("Expected the whole line, got %d bytes", len(chunk.Content))
+	}
+}
+
+func TestQueryStream_OversizedLine(t *testing.T) {
+	if testing.Short() {
+		t.Skip("writes more than maxCLILineSize")
+	}
+
+	// Output beyond the line limit fails the stream instead of hanging it
+	client := newFakeCLIClient(t, `head -c 20000000 /dev/zero | tr '\0' a; echo`)
+	client.maxLineSize = 16 * 1024 * 1024
+
+	stream, err := client.QueryStream(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "read the log"}},
+	})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Expected an error for an oversized line")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Recv hung on an oversized line")
+	}
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// HookSubcommandArg is the first argument with which the CLI re-executes the
+// current program to run an SDK hook, such as the Bash sandbox.
+const HookSubcommandArg = "__claude-hook"
+
+// errHooksNotReady is returned when SDK hooks are configured but the program
+// cannot run them.
+var errHooksNotReady = sdkerrors.NewConfigurationError("hooks", "BashSandbox, WebDomains and ApprovalProvider require calling client.RunHookSubcommand at the start of main")
+
+// hooksReady records that RunHookSubcommand was called, so hooks that
+// re-execute the program will not start it from the top.
+var hooksReady atomic.Bool
+
+// sdkHook is a PreToolUse hook implemented by the SDK.
+type sdkHook struct {
+	// matcher selects the tools the hook runs for
+	matcher string
+
+	// name identifies the hook to RunHookSubcommand
+	name string
+
+	// config is passed to the hook as JSON
+	config any
+
+	// timeout overrides the CLI's hook timeout, in seconds
+	timeout int
+}
+
+// hookArgs returns the --settings flag that installs the SDK's PreToolUse
+// hooks for a query in workingDir, or nil when none are configured.
+func (c *ClaudeCodeClient) hookArgs(workingDir string) ([]string, error) {
+	config := c.settings()
+
+	var hooks []sdkHook
+	if config.BashSandbox != nil {
+		hooks = append(hooks, sdkHook{matcher: "Bash", name: hookBashSandbox, config: bashSandboxHook{Sandbox: config.BashSandbox, HostDir: workingDir}})
+	}
+	if config.WebDomains != nil {
+		hooks = append(hooks, sdkHook{matcher: "WebFetch|WebSearch", name: hookWebDomains, config: config.WebDomains})
+	}
+	if config.ApprovalProvider != nil {
+		if !hooksReady.Load() {
+			return nil, errHooksNotReady
+		}
+		approval, err := c.approvalHookConfig()
+		if err != nil {
+			return nil, err
+		}
+		matcher := config.ApprovalTools
+		if matcher == "" {
+			matcher = types.DefaultApprovalTools
+		}
+		timeout := config.ApprovalTimeout
+		if timeout <= 0 {
+			timeout = defaultApprovalTimeout
+		}
+		// Leave the hook time to report the server's timeout as a denial
+		hooks = append(hooks, sdkHook{matcher: matcher, name: hookApproval, config: approval, timeout: int(timeout/time.Second) + 30})
+	}
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+
+	if !hooksReady.Load() {
+		return nil, errHooksNotReady
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "EXECUTABLE", "failed to locate executable for SDK hooks")
+	}
+
+	matchers := make([]any, 0, len(hooks))
+	for _, hook := range hooks {
+		config, err := json.Marshal(hook.config)
+		if err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONFIG_MARSHAL", "failed to marshal "+hook.name+" hook configuration")
+		}
+		command := map[string]any{
+			"type":    "command",
+			"command": shellJoin([]string{executable, HookSubcommandArg, hook.name, string(config)}),
+		}
+		if hook.timeout > 0 {
+			command["timeout"] = hook.timeout
+		}
+		matchers = append(matchers, map[string]any{
+			"matcher": hook.matcher,
+			"hooks":   []any{command},
+		})
+	}
+
+	data, err := json.Marshal(map[string]any{"hooks": map[string]any{"PreToolUse": matchers}})
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONFIG_MARSHAL", "failed to marshal hook settings")
+	}
+
+	return []string{"--settings", string(data)}, nil
+}
+
+// RunHookSubcommand runs an SDK hook and exits when the program was started
+// by the CLI as one. Otherwise it returns immediately. Programs that set
+// ClaudeCodeConfig.BashSandbox, WebDomains or ApprovalProvider must call it
+// first thing in main:
+//
+//	func main() {
+//		client.RunHookSubcommand()
+//		// ...
+//	}
+func RunHookSubcommand() {
+	hooksReady.Store(true)
+	if len(os.Args) < 4 || os.Args[1] != HookSubcommandArg {
+		return
+	}
+
+	var err error
+	switch os.Args[2] {
+	case hookBashSandbox:
+		err = runBashSandboxHook([]byte(os.Args[3]), os.Stdin, os.Stdout)
+	case hookWebDomains:
+		err = runWebDomainsHook([]byte(os.Args[3]), os.Stdin, os.Stdout)
+	case hookApproval:
+		err = runApprovalHook([]byte(os.Args[3]), os.Stdin, os.Stdout)
+	default:
+		err = fmt.Errorf("unknown hook %q", os.Args[2])
+	}
+
+	if err != nil {
+		// Exit code 2 blocks the tool call, so a broken hook never lets the
+		// call through unchecked
+		fmt.Fprintf(os.Stderr, "%s hook: %v\n", os.Args[2], err)
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+// preToolUseEvent is the input of a PreToolUse hook.
+type preToolUseEvent struct {
+	ToolName  string         `json:"tool_name"`
+	ToolInput map[string]any `json:"tool_input"`
+}
+
+// shellJoin quotes argv for a POSIX shell.
+func shellJoin(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		if arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+		}) < 0 {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestMain(m *testing.M) {
+	// Run SDK hooks when re-executed by the hook tests
+	RunHookSubcommand()
+	os.Exit(m.Run())
+}
+
+func TestHookArgs_RequiresRunHookSubcommand(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	client.config.WebDomains = &types.WebDomainPolicy{BlockedDomains: []string{"example.com"}}
+
+	hooksReady.Store(false)
+	defer hooksReady.Store(true)
+
+	if _, err := client.hookArgs(client.currentWorkingDir()); err == nil {
+		t.Error("Expected error when RunHookSubcommand was not called")
+	}
+
+	hooksReady.Store(true)
+	args, err := client.hookArgs(client.currentWorkingDir())
+	if err != nil || len(args) != 2 || args[0] != "--settings" {
+		t.Errorf("Unexpected hook args %v (%v)", args, err)
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	got := shellJoin([]string{"/usr/bin/app", "plain-arg", "", "with space", `it's`})
+	want := `/usr/bin/app plain-arg '' 'with space' 'it'\''s'`
+	if got != want {
+		t.Errorf("shellJoin() = %q, want %q", got, want)
+	}
+}
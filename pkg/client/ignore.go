@@ -0,0 +1,255 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// ClaudeIgnoreFile is the project file listing paths to keep out of Claude's
+// context, in .gitignore syntax.
+const ClaudeIgnoreFile = ".claudeignore"
+
+// DefaultIgnorePatterns are excluded from project context before .gitignore
+// and .claudeignore are applied; a "!" pattern in either file re-includes them.
+var DefaultIgnorePatterns = []string{
+	// Version control and dependencies
+	".git/", "node_modules/", "vendor/",
+
+	// Secrets
+	".env", ".env.*", "*.pem", "*.key", "*.p12", "*.pfx", "id_rsa*", "id_ecdsa*", "id_ed25519*",
+}
+
+// IgnoreMatcher matches project paths against .gitignore-style patterns from
+// DefaultIgnorePatterns and the root's .gitignore and .claudeignore files.
+// Later patterns take precedence, so .claudeignore can override .gitignore.
+// Files in nested directories are not consulted.
+type IgnoreMatcher struct {
+	rules []ignoreRule
+}
+
+// ignoreRule is a compiled .gitignore pattern.
+type ignoreRule struct {
+	pattern  string
+	source   string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+	regex    *regexp.Regexp
+}
+
+// LoadIgnoreMatcher builds the matcher for a project root. Missing ignore
+// files are not an error.
+func LoadIgnoreMatcher(root string) (*IgnoreMatcher, error) {
+	matcher := NewIgnoreMatcher(DefaultIgnorePatterns...)
+
+	for _, name := range []string{".gitignore", ClaudeIgnoreFile} {
+		patterns, err := readIgnoreFile(filepath.Join(root, name))
+		if err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "FILE_READ", "failed to read "+name)
+		}
+		matcher.add(name, patterns)
+	}
+
+	return matcher, nil
+}
+
+// NewIgnoreMatcher returns a matcher for the given .gitignore-style patterns.
+func NewIgnoreMatcher(patterns ...string) *IgnoreMatcher {
+	matcher := &IgnoreMatcher{}
+	matcher.add("", patterns)
+	return matcher
+}
+
+// readIgnoreFile returns the lines of an ignore file, or nil if it does not exist.
+func readIgnoreFile(path string) ([]string, error) {
+	file, err := os.Open(path) // #nosec G304 - ignore files are read from the project root
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }() // Ignore error, read-only file
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// add compiles patterns, skipping blank lines and comments.
+func (m *IgnoreMatcher) add(source string, patterns []string) {
+	for _, line := range patterns {
+		pattern := strings.TrimRight(line, " \t\r")
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		rule := ignoreRule{source: source}
+		if strings.HasPrefix(pattern, "!") {
+			rule.negate = true
+			pattern = pattern[1:]
+		} else if strings.HasPrefix(pattern, `\`) {
+			pattern = pattern[1:] // Escaped leading "#" or "!"
+		}
+		if strings.HasSuffix(pattern, "/") {
+			rule.dirOnly = true
+			pattern = strings.TrimRight(pattern, "/")
+		}
+		rule.anchored = strings.Contains(pattern, "/")
+		pattern = strings.TrimPrefix(pattern, "/")
+		if pattern == "" {
+			continue
+		}
+
+		rule.pattern = pattern
+		rule.regex = ignoreRegexp(pattern)
+		m.rules = append(m.rules, rule)
+	}
+}
+
+// ignoreRegexp converts a .gitignore glob to a regular expression.
+func ignoreRegexp(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if strings.HasPrefix(pattern[i:], "**") {
+				switch {
+				case strings.HasPrefix(pattern[i:], "**/"):
+					expr.WriteString("(?:.*/)?")
+					i += 2
+				default:
+					expr.WriteString(".*")
+					i++
+				}
+				continue
+			}
+			expr.WriteString("[^/]*")
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			if end := strings.IndexByte(pattern[i+1:], ']'); end >= 0 {
+				class := pattern[i+1 : i+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				expr.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+				i += end + 1
+				continue
+			}
+			expr.WriteString(`\[`)
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	expr.WriteString("$")
+	regex, err := regexp.Compile(expr.String())
+	if err != nil {
+		return regexp.MustCompile(`^` + regexp.QuoteMeta(pattern) + `$`)
+	}
+	return regex
+}
+
+// Match reports whether a path relative to the project root is ignored. A
+// path inside an ignored directory is ignored too.
+func (m *IgnoreMatcher) Match(relPath string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+
+	relPath = strings.Trim(filepath.ToSlash(filepath.Clean(relPath)), "/")
+	if relPath == "" || relPath == "." {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.matchOne(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.matchOne(relPath, isDir)
+}
+
+// matchOne applies the rules to a single path; the last matching rule wins.
+func (m *IgnoreMatcher) matchOne(relPath string, isDir bool) bool {
+	base := relPath[strings.LastIndex(relPath, "/")+1:]
+
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		target := base
+		if rule.anchored {
+			target = relPath
+		}
+		if rule.regex.MatchString(target) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// WalkDir walks root like filepath.WalkDir, skipping ignored files and
+// directories. Paths passed to fn are absolute when root is.
+func (m *IgnoreMatcher) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && path != root {
+			if rel, relErr := filepath.Rel(root, path); relErr == nil && m.Match(rel, entry.IsDir()) {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		return fn(path, entry, err)
+	})
+}
+
+// denyRules returns CLI permission rules denying Read and Edit of the paths
+// listed in .claudeignore. Negated patterns cannot be expressed as deny rules
+// and are skipped.
+func (m *IgnoreMatcher) denyRules() []string {
+	if m == nil {
+		return nil
+	}
+
+	var rules []string
+	for _, rule := range m.rules {
+		if rule.negate || rule.source != ClaudeIgnoreFile {
+			continue
+		}
+
+		pattern := rule.pattern
+		switch {
+		case !rule.anchored:
+			pattern = "**/" + pattern
+		case !strings.HasPrefix(pattern, "**/"):
+			pattern = "./" + pattern
+		}
+		if rule.dirOnly {
+			pattern += "/**"
+		}
+		rules = append(rules, "Read("+pattern+")", "Edit("+pattern+")")
+	}
+	return rules
+}
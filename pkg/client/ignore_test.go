@@ -0,0 +1,135 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIgnoreMatcher_Match(t *testing.T) {
+	matcher := NewIgnoreMatcher(
+		"# comment",
+		"*.log",
+		"!keep.log",
+		"build/",
+		"/root-only.txt",
+		"docs/**/*.pdf",
+		"**/secrets",
+		`\#literal`,
+		"tmp[0-9]",
+	)
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"app.log", false, true},
+		{"logs/deep/app.log", false, true},
+		{"logs/keep.log", false, false},
+		{"build", true, true},
+		{"build/out.bin", false, true},
+		{"src/build", false, false},
+		{"src/build/x.o", false, true},
+		{"root-only.txt", false, true},
+		{"sub/root-only.txt", false, false},
+		{"docs/a/b/manual.pdf", false, true},
+		{"docs/manual.pdf", false, true},
+		{"other/manual.pdf", false, false},
+		{"a/b/secrets", true, true},
+		{"a/b/secrets/key", false, true},
+		{"#literal", false, true},
+		{"tmp1", false, true},
+		{"tmpx", false, false},
+		{"main.go", false, false},
+	}
+	for _, tt := range tests {
+		if got := matcher.Match(tt.path, tt.isDir); got != tt.ignored {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.ignored)
+		}
+	}
+
+	var nilMatcher *IgnoreMatcher
+	if nilMatcher.Match("app.log", false) {
+		t.Error("Expected a nil matcher to ignore nothing")
+	}
+}
+
+func TestLoadIgnoreMatcher(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":          "dist/\n*.tmp\n",
+		ClaudeIgnoreFile:      "fixtures/\n!scratch.tmp\n/internal/keys.go\n",
+		".env":                "TOKEN=x",
+		"main.go":             "package main",
+		"scratch.tmp":         "",
+		"other.tmp":           "",
+		"dist/bundle.js":      "",
+		"fixtures/big.json":   "",
+		"internal/keys.go":    "package internal",
+		"internal/server.go":  "package internal",
+		"node_modules/x/i.js": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matcher, err := LoadIgnoreMatcher(root)
+	if err != nil {
+		t.Fatalf("LoadIgnoreMatcher failed: %v", err)
+	}
+
+	var walked []string
+	err = matcher.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			rel, _ := filepath.Rel(root, path)
+			walked = append(walked, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir failed: %v", err)
+	}
+
+	got := strings.Join(walked, " ")
+	want := ".claudeignore .gitignore internal/server.go main.go scratch.tmp"
+	if got != want {
+		t.Errorf("WalkDir visited %q, want %q", got, want)
+	}
+
+	rules := strings.Join(matcher.denyRules(), " ")
+	if rules != "Read(**/fixtures/**) Edit(**/fixtures/**) Read(./internal/keys.go) Edit(./internal/keys.go)" {
+		t.Errorf("Unexpected deny rules %q", rules)
+	}
+}
+
+func TestEnforceClaudeIgnore(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	if err := os.WriteFile(filepath.Join(client.workingDir, ClaudeIgnoreFile), []byte("secrets/\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	args, err := client.permissionArgs(client.currentWorkingDir(), []string{"Read"}, nil)
+	if err != nil || strings.Contains(strings.Join(args, " "), "--disallowedTools") {
+		t.Errorf("Expected no deny rules unless enforced, got %v (%v)", args, err)
+	}
+
+	client.config.EnforceClaudeIgnore = true
+	args, err = client.permissionArgs(client.currentWorkingDir(), []string{"Read"}, nil)
+	if err != nil {
+		t.Fatalf("permissionArgs failed: %v", err)
+	}
+	if got := strings.Join(args, " "); got != "--allowedTools Read --disallowedTools Read(**/secrets/**),Edit(**/secrets/**)" {
+		t.Errorf("Unexpected permission args %q", got)
+	}
+}
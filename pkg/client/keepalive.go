@@ -0,0 +1,139 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// defaultKeepAliveInterval is used when KeepAliveConfig.Interval is unset
+	defaultKeepAliveInterval = 10 * time.Second
+
+	// defaultMaxReconnectAttempts is used when KeepAliveConfig.MaxReconnectAttempts is unset
+	defaultMaxReconnectAttempts = 3
+)
+
+// connectionMonitor periodically checks the CLI processes backing interactive
+// streams and flags the ones that have died or stopped producing output.
+//
+// Detection works in two places:
+//   - The monitor goroutine notices processes that exited with an error or have
+//     been silent for longer than the configured idle timeout. Stalled processes
+//     are killed so that blocked readers are released.
+//   - Streams report unexpected process exits themselves when they hit EOF.
+//
+// In both cases the OnDisconnect callback fires exactly once per process, and
+// the stream either reconnects (when AutoReconnect is enabled) or returns a
+// ConnectionError from Recv instead of hanging.
+type connectionMonitor struct {
+	config  *types.KeepAliveConfig
+	streams map[string]*claudeCodeQueryStream
+	mu      sync.Mutex
+
+	ticker *time.Ticker
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// newConnectionMonitor creates a monitor for the given keepalive configuration.
+func newConnectionMonitor(config *types.KeepAliveConfig) *connectionMonitor {
+	return &connectionMonitor{
+		config:  config,
+		streams: make(map[string]*claudeCodeQueryStream),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// start launches the background liveness checks.
+func (m *connectionMonitor) start() {
+	interval := m.config.Interval
+	if interval <= 0 {
+		interval = defaultKeepAliveInterval
+	}
+
+	m.ticker = time.NewTicker(interval)
+	m.wg.Add(1)
+	go m.run()
+}
+
+// stop halts the background liveness checks and waits for them to finish.
+func (m *connectionMonitor) stop() {
+	close(m.stopCh)
+	if m.ticker != nil {
+		m.ticker.Stop()
+	}
+	m.wg.Wait()
+}
+
+// run executes liveness checks on every tick until stopped.
+func (m *connectionMonitor) run() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.ticker.C:
+			m.checkConnections(time.Now())
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// track registers a stream for liveness checks.
+func (m *connectionMonitor) track(stream *claudeCodeQueryStream) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.streams[stream.processID] = stream
+}
+
+// untrack removes a stream from liveness checks.
+func (m *connectionMonitor) untrack(stream *claudeCodeQueryStream) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.streams, stream.processID)
+}
+
+// checkConnections inspects every tracked stream once.
+func (m *connectionMonitor) checkConnections(now time.Time) {
+	m.mu.Lock()
+	streams := make([]*claudeCodeQueryStream, 0, len(m.streams))
+	for _, stream := range m.streams {
+		streams = append(streams, stream)
+	}
+	m.mu.Unlock()
+
+	for _, stream := range streams {
+		exited, waitErr := stream.exitStatus()
+		if exited {
+			if waitErr != nil {
+				stream.markDisconnected(types.DisconnectReasonProcessExited, now)
+			}
+			continue
+		}
+
+		if m.config.IdleTimeout > 0 && now.Sub(stream.lastActivityTime()) > m.config.IdleTimeout {
+			if stream.markDisconnected(types.DisconnectReasonIdleTimeout, now) {
+				stream.abort()
+			}
+		}
+	}
+}
+
+// maxReconnectAttempts returns the effective reconnect limit.
+func (m *connectionMonitor) maxReconnectAttempts() int {
+	if m.config.MaxReconnectAttempts > 0 {
+		return m.config.MaxReconnectAttempts
+	}
+	return defaultMaxReconnectAttempts
+}
+
+// notify invokes the OnDisconnect callback if one is configured.
+func (m *connectionMonitor) notify(event types.DisconnectEvent) {
+	if m.config.OnDisconnect != nil {
+		m.config.OnDisconnect(event)
+	}
+}
@@ -0,0 +1,322 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// newKeepAliveTestClient creates a test client whose streams run the given shell command.
+func newKeepAliveTestClient(t *testing.T, keepAlive *types.KeepAliveConfig) *ClaudeCodeClient {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("keepalive tests rely on a POSIX shell")
+	}
+
+	config := &types.ClaudeCodeConfig{
+		WorkingDirectory: t.TempDir(),
+		TestMode:         true,
+		KeepAlive:        keepAlive,
+	}
+
+	client, err := NewClaudeCodeClient(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	client.claudeCodeCmd = "sh"
+	return client
+}
+
+// startTestStream starts a monitored stream running the given arguments.
+func startTestStream(t *testing.T, client *ClaudeCodeClient, sessionID string, args ...string) *claudeCodeQueryStream {
+	t.Helper()
+
+	stream := &claudeCodeQueryStream{
+		ctx:       context.Background(),
+		processID: "test-stream",
+		client:    client,
+		args:      args,
+		sessionID: sessionID,
+	}
+	if err := stream.startProcess(args); err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+	client.connMonitor.track(stream)
+	t.Cleanup(func() { stream.Close() })
+
+	return stream
+}
+
+// disconnectRecorder collects OnDisconnect events.
+type disconnectRecorder struct {
+	mu     sync.Mutex
+	events []types.DisconnectEvent
+}
+
+func (r *disconnectRecorder) record(event types.DisconnectEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *disconnectRecorder) snapshot() []types.DisconnectEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]types.DisconnectEvent(nil), r.events...)
+}
+
+func TestKeepAlive_IdleTimeoutReleasesReader(t *testing.T) {
+	recorder := &disconnectRecorder{}
+	client := newKeepAliveTestClient(t, &types.KeepAliveConfig{
+		Interval:     time.Hour, // checks are triggered manually
+		IdleTimeout:  50 * time.Millisecond,
+		OnDisconnect: recorder.record,
+	})
+
+	stream := startTestStream(t, client, "", "-c", "sleep 30")
+
+	client.connMonitor.checkConnections(time.Now().Add(time.Second))
+
+	_, err := stream.Recv()
+	var connErr *sdkerrors.ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("Expected ConnectionError, got %v", err)
+	}
+
+	events := recorder.snapshot()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 disconnect event, got %d", len(events))
+	}
+	if events[0].Reason != types.DisconnectReasonIdleTimeout {
+		t.Errorf("Expected reason %s, got %s", types.DisconnectReasonIdleTimeout, events[0].Reason)
+	}
+	if events[0].Reconnecting {
+		t.Error("Expected no reconnect without AutoReconnect")
+	}
+}
+
+func TestKeepAlive_ProcessExitReported(t *testing.T) {
+	recorder := &disconnectRecorder{}
+	client := newKeepAliveTestClient(t, &types.KeepAliveConfig{
+		Interval:     time.Hour,
+		OnDisconnect: recorder.record,
+	})
+
+	stream := startTestStream(t, client, "", "-c", "exit 3")
+
+	_, err := stream.Recv()
+	var connErr *sdkerrors.ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("Expected ConnectionError, got %v", err)
+	}
+
+	// A later monitor pass must not report the same process twice
+	client.connMonitor.checkConnections(time.Now())
+
+	events := recorder.snapshot()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 disconnect event, got %d", len(events))
+	}
+	if events[0].Reason != types.DisconnectReasonProcessExited {
+		t.Errorf("Expected reason %s, got %s", types.DisconnectReasonProcessExited, events[0].Reason)
+	}
+}
+
+func TestKeepAlive_AutoReconnectResumesSession(t *testing.T) {
+	recorder := &disconnectRecorder{}
+	client := newKeepAliveTestClient(t, &types.KeepAliveConfig{
+		Interval:      time.Hour,
+		AutoReconnect: true,
+		OnDisconnect:  recorder.record,
+	})
+
+	sessionID := GenerateSessionID()
+	script := `if [ "$1" = "--resume" ]; then echo resumed; else exit 1; fi`
+	stream := startTestStream(t, client, sessionID, "-c", script, "sh", "--session-id", sessionID)
+
+	chunk, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected reconnect to succeed, got %v", err)
+	}
+	if chunk.Content != "resumed\n" {
+		t.Errorf("Expected output from resumed process, got %q", chunk.Content)
+	}
+
+	chunk, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Unexpected error after reconnect: %v", err)
+	}
+	if !chunk.Done {
+		t.Error("Expected stream to complete after resumed output")
+	}
+
+	events := recorder.snapshot()
+	if len(events) != 1 || !events[0].Reconnecting {
+		t.Fatalf("Expected a single reconnecting disconnect event, got %+v", events)
+	}
+}
+
+func TestKeepAlive_ReconnectDoesNotResendPrompt(t *testing.T) {
+	client := newKeepAliveTestClient(t, &types.KeepAliveConfig{
+		Interval:      time.Hour,
+		AutoReconnect: true,
+	})
+	dir := client.currentWorkingDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("attached notes"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Record each run's arguments and stdin; the first run drops out
+	cliPath := filepath.Join(dir, "claude")
+	script := `#!/bin/sh
+i=0
+while [ -e "args$i" ]; do i=$((i+1)); done
+printf '%s\n' "$@" > "args$i"
+cat > "stdin$i"
+[ $i = 0 ] && exit 1
+echo resumed`
+	if err := os.WriteFile(cliPath, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	client.claudeCodeCmd = cliPath
+
+	sessionID := GenerateSessionID()
+	ctx := withQueryScope(context.Background(), queryScope{sessionID: sessionID, workingDir: dir})
+	stream, err := client.QueryStream(ctx, &types.QueryRequest{Messages: []types.Message{{
+		Role:        types.RoleUser,
+		Content:     "summarize the notes",
+		Attachments: []types.Attachment{types.NewFileAttachment("notes.txt")},
+	}}})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	chunk, err := stream.Recv()
+	if err != nil || chunk.Content != "resumed\n" {
+		t.Fatalf("Expected output from the resumed process, got %+v, err %v", chunk, err)
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if stdin := read("stdin0"); !strings.Contains(stdin, "summarize the notes") {
+		t.Fatalf("Expected the first run to receive the prompt on stdin, got %q", stdin)
+	}
+	args, stdin := read("args1"), read("stdin1")
+	if !strings.Contains(args, "--resume\n"+sessionID+"\n") || strings.Contains(args, "--session-id") {
+		t.Errorf("Expected the restarted run to resume the session, got args %q", args)
+	}
+	if strings.Contains(args+stdin, "summarize the notes") || strings.Contains(args+stdin, "attached notes") {
+		t.Errorf("Expected the restarted run not to resend the prompt, got args %q and stdin %q", args, stdin)
+	}
+	if !strings.HasSuffix(args, reconnectPrompt+"\n") {
+		t.Errorf("Expected the restarted run to ask to continue, got args %q", args)
+	}
+}
+
+func TestKeepAlive_ReconnectWithLocalTools(t *testing.T) {
+	client := newKeepAliveTestClient(t, &types.KeepAliveConfig{
+		Interval:      time.Hour,
+		AutoReconnect: true,
+	})
+	client.SetToolRegistry(newOrderRegistry(t))
+	// Without a session the restarted process reruns the request
+	client.sessionID = ""
+	dir := client.currentWorkingDir()
+
+	// Copy each run's local tool config; the first run drops out
+	cliPath := filepath.Join(dir, "claude")
+	script := `#!/bin/sh
+i=0
+while [ -e "config$i" ]; do i=$((i+1)); done
+while [ $# -gt 0 ]; do
+	if [ "$1" = --mcp-config ]; then cp "$2" "config$i" || touch "config$i"; fi
+	shift
+done
+[ $i = 0 ] && exit 1
+echo resumed`
+	if err := os.WriteFile(cliPath, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	client.claudeCodeCmd = cliPath
+
+	stream, err := client.QueryStream(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "where is my order"}},
+	})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	chunk, err := stream.Recv()
+	if err != nil || chunk.Content != "resumed\n" {
+		t.Fatalf("Expected output from the restarted process, got %+v, err %v", chunk, err)
+	}
+	for _, name := range []string{"config0", "config1"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || !strings.Contains(string(data), LocalToolServer) {
+			t.Errorf("Expected %s to hold the local tool server, got %q, err %v", name, data, err)
+		}
+	}
+}
+
+func TestKeepAlive_CleanExitIsNotDisconnect(t *testing.T) {
+	recorder := &disconnectRecorder{}
+	client := newKeepAliveTestClient(t, &types.KeepAliveConfig{
+		Interval:     time.Hour,
+		IdleTimeout:  time.Hour,
+		OnDisconnect: recorder.record,
+	})
+
+	stream := startTestStream(t, client, "", "-c", "echo hello")
+
+	chunk, err := stream.Recv()
+	if err != nil || chunk.Content != "hello\n" {
+		t.Fatalf("Unexpected first chunk %+v, err %v", chunk, err)
+	}
+	chunk, err = stream.Recv()
+	if err != nil || !chunk.Done {
+		t.Fatalf("Expected done chunk, got %+v, err %v", chunk, err)
+	}
+
+	client.connMonitor.checkConnections(time.Now())
+	if events := recorder.snapshot(); len(events) != 0 {
+		t.Errorf("Expected no disconnect events, got %+v", events)
+	}
+}
+
+func TestResumeArgs(t *testing.T) {
+	sessionID := GenerateSessionID()
+	args := []string{"--model", "m", "--session-id", sessionID, "prompt"}
+
+	resumed := resumeArgs(args, sessionID)
+	if resumed[2] != "--resume" || resumed[3] != sessionID {
+		t.Errorf("Expected session flag to be rewritten to --resume, got %v", resumed)
+	}
+	if args[2] != "--session-id" {
+		t.Error("Expected original arguments to be left untouched")
+	}
+
+	unchanged := resumeArgs(args, "")
+	if unchanged[2] != "--session-id" {
+		t.Errorf("Expected arguments without session to be unchanged, got %v", unchanged)
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// errLineTooLong is returned when a line of CLI output exceeds the reader's limit.
+var errLineTooLong = errors.New("line of claude output exceeds the size limit")
+
+// lineReader reads newline-terminated lines of CLI output.
+//
+// bufio.Scanner searches the whole buffered line for a newline after every
+// read, and pipes deliver at most 64KB per read, so a tool result line of n
+// bytes costs O(n²/64KB). lineReader searches only newly read data, keeping
+// large lines linear.
+type lineReader struct {
+	r   *bufio.Reader
+	max int
+}
+
+func newLineReader(r io.Reader, max int) *lineReader {
+	return &lineReader{r: bufio.NewReaderSize(r, 64*1024), max: max}
+}
+
+// cliLineReader returns a lineReader for the client's CLI output, limited to
+// the client's maximum line size.
+func (c *ClaudeCodeClient) cliLineReader(r io.Reader) *lineReader {
+	max := c.maxLineSize
+	if max <= 0 {
+		max = maxCLILineSize
+	}
+	return newLineReader(r, max)
+}
+
+// next returns the next line, ending in exactly one "\n" even if the output
+// used "\r\n" or ended without a newline. The slice is owned by the caller
+// and is never larger than the limit allows. At the end of output next
+// returns io.EOF.
+func (l *lineReader) next() ([]byte, error) {
+	var line []byte
+	for {
+		fragment, err := l.r.ReadSlice('\n')
+		if len(line)+len(fragment) > l.max+1 {
+			return nil, errLineTooLong
+		}
+		if line == nil && err == nil {
+			// The common case: the whole line is in the buffer
+			line = make([]byte, len(fragment))
+			copy(line, fragment)
+			return terminateLine(line), nil
+		}
+		if need := len(line) + len(fragment); need > cap(line) {
+			// Double rather than let append grow large slices by 1.25x,
+			// which copies a long line several more times, but never past
+			// the limit, which would reserve up to twice the largest line
+			size := 2 * need
+			if size > l.max+1 {
+				size = l.max + 1
+			}
+			grown := make([]byte, len(line), size)
+			copy(grown, line)
+			line = grown
+		}
+		line = append(line, fragment...)
+
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && len(line) > 0:
+			return terminateLine(line), nil
+		case err != nil:
+			return nil, err
+		}
+		return terminateLine(line), nil
+	}
+}
+
+// lineString returns a line from next as a string without copying it, so a
+// large line is not held in memory twice. The line must not be modified
+// afterwards; observers of CLI output only decode it.
+func lineString(line []byte) string {
+	if len(line) == 0 {
+		return ""
+	}
+	return unsafe.String(&line[0], len(line)) // #nosec G103 - the line is not modified after conversion
+}
+
+// terminateLine replaces a line's ending with a single "\n".
+func terminateLine(line []byte) []byte {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	return append(line, '\n')
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestLineReader(t *testing.T) {
+	long := strings.Repeat("x", 200*1024)
+	reader := newLineReader(strings.NewReader("one\ntwo\r\n"+long+"\nlast"), 1024*1024)
+
+	for _, want := range []string{"one\n", "two\n", long + "\n", "last\n"} {
+		line, err := reader.next()
+		if err != nil {
+			t.Fatalf("next failed: %v", err)
+		}
+		if string(line) != want {
+			t.Errorf("next = %.20q (%d bytes), want %.20q (%d bytes)", line, len(line), want, len(want))
+		}
+	}
+	if _, err := reader.next(); err != io.EOF {
+		t.Errorf("Expected io.EOF at the end of output, got %v", err)
+	}
+}
+
+func TestLineReader_TooLong(t *testing.T) {
+	reader := newLineReader(strings.NewReader(strings.Repeat("x", 100*1024)+"\nok\n"), 64*1024)
+	if _, err := reader.next(); !errors.Is(err, errLineTooLong) {
+		t.Errorf("Expected errLineTooLong, got %v", err)
+	}
+
+	// The limit excludes the newline
+	reader = newLineReader(strings.NewReader(strings.Repeat("x", 100)+"\n"), 100)
+	if line, err := reader.next(); err != nil || len(line) != 101 {
+		t.Errorf("Expected a line at the limit, got %d bytes, %v", len(line), err)
+	}
+}
+
+func TestLineReader_GrowthCappedAtLimit(t *testing.T) {
+	// Doubling a buffer of 600KB would reserve 1.2MB for a line limited to 1MB
+	const max = 1024 * 1024
+	reader := newLineReader(strings.NewReader(strings.Repeat("x", 900*1024)+"\n"), max)
+	line, err := reader.next()
+	if err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+	if cap(line) > max+1 {
+		t.Errorf("Expected the line buffer to stay within the limit, got capacity %d", cap(line))
+	}
+}
+
+func TestLineString(t *testing.T) {
+	line := []byte("{\"type\":\"result\"}\n")
+	s := lineString(line)
+	if s != string(line) {
+		t.Errorf("lineString = %q, want %q", s, line)
+	}
+	if unsafe.StringData(s) != &line[0] {
+		t.Error("Expected lineString to share the line's memory")
+	}
+	if lineString(nil) != "" {
+		t.Error("Expected an empty string for an empty line")
+	}
+}
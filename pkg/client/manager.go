@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// drainPollInterval is how often Shutdown checks whether in-flight queries
+// have finished.
+const drainPollInterval = 20 * time.Millisecond
+
+// shutdownHook is a dependent registered with OnShutdown.
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager tracks the clients and sessions of a service so they can be shut
+// down together. Shutdown stops the service's own dependents, waits for
+// in-flight queries to finish and then closes sessions before the clients
+// they belong to.
+type Manager struct {
+	mu       sync.Mutex
+	clients  []*ClaudeCodeClient
+	sessions []*ClaudeCodeSession
+	hooks    []shutdownHook
+	shutdown bool
+}
+
+// NewManager creates an empty manager.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// NewClient creates a client with NewClaudeCodeClient and tracks it. It
+// fails once Shutdown has been called.
+func (m *Manager) NewClient(ctx context.Context, config *types.ClaudeCodeConfig) (*ClaudeCodeClient, error) {
+	if m.isShutdown() {
+		return nil, errManagerShutdown()
+	}
+	c, err := NewClaudeCodeClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Track(c); err != nil {
+		_ = c.Close() // Ignore error during cleanup
+		return nil, err
+	}
+	return c, nil
+}
+
+// Track adds a client created elsewhere to the manager. It fails once
+// Shutdown has been called.
+func (m *Manager) Track(c *ClaudeCodeClient) error {
+	if c == nil {
+		return sdkerrors.NewValidationError("client", "", "required", "client cannot be nil")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.shutdown {
+		return errManagerShutdown()
+	}
+	for _, tracked := range m.clients {
+		if tracked == c {
+			return nil
+		}
+	}
+	m.clients = append(m.clients, c)
+	return nil
+}
+
+// CreateSession creates a session on c, tracking both. An empty sessionID
+// generates a new one; see ClaudeCodeClient.CreateSession.
+func (m *Manager) CreateSession(ctx context.Context, c *ClaudeCodeClient, sessionID string) (*ClaudeCodeSession, error) {
+	if err := m.Track(c); err != nil {
+		return nil, err
+	}
+	session, err := c.CreateSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.shutdown {
+		_ = session.Close() // Ignore error during cleanup
+		return nil, errManagerShutdown()
+	}
+	m.sessions = append(m.sessions, session)
+	return session, nil
+}
+
+// OnShutdown registers a dependent of the managed clients, such as an HTTP
+// server, job queue or scheduler, to stop before the clients are drained.
+// Hooks run in reverse order of registration, like deferred calls, and
+// receive the Shutdown context.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// Clients returns the tracked clients in the order they were added.
+func (m *Manager) Clients() []*ClaudeCodeClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*ClaudeCodeClient(nil), m.clients...)
+}
+
+// Shutdown stops new clients and sessions from being created and closes
+// everything the manager tracks, in dependency order:
+//
+//  1. the OnShutdown hooks, newest first
+//  2. waiting for the clients' in-flight queries to finish
+//  3. the tracked sessions
+//  4. the clients, newest first
+//
+// If ctx is done before the queries finish, they are interrupted and the
+// sessions and clients are closed anyway; ctx's error is then returned
+// along with any hook errors. Calls after the first return nil.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.shutdown {
+		m.mu.Unlock()
+		return nil
+	}
+	m.shutdown = true
+	hooks, sessions, clients := m.hooks, m.sessions, m.clients
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].fn(ctx); err != nil {
+			errs = append(errs, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SHUTDOWN_HOOK", "shutdown of "+hooks[i].name+" failed"))
+		}
+	}
+
+	if err := drain(ctx, clients); err != nil {
+		errs = append(errs, err)
+		for _, c := range clients {
+			_ = c.Interrupt() // Ignore error, the client may already be closed
+		}
+	}
+
+	for _, session := range sessions {
+		_ = session.manager.CloseSession(session.ID) // Ignore error, the client may already have closed it
+	}
+	for i := len(clients) - 1; i >= 0; i-- {
+		if err := clients[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// drain waits until no client has a query in flight or ctx is done.
+func drain(ctx context.Context, clients []*ClaudeCodeClient) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		idle := true
+		for _, c := range clients {
+			switch c.State() {
+			case types.ClientConnecting, types.ClientBusy, types.ClientInterrupting:
+				idle = false
+			}
+		}
+		if idle {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return sdkerrors.WrapError(ctx.Err(), sdkerrors.CategoryInternal, "SHUTDOWN_TIMEOUT", "queries still running at shutdown were interrupted")
+		case <-ticker.C:
+		}
+	}
+}
+
+// isShutdown reports whether Shutdown has been called.
+func (m *Manager) isShutdown() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.shutdown
+}
+
+// errManagerShutdown is returned for clients and sessions requested after
+// Shutdown.
+func errManagerShutdown() error {
+	return sdkerrors.NewInternalError("MANAGER_SHUTDOWN", "manager has been shut down")
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	client := newFakeCLIClient(t, `sleep 0.2; echo "handled $prompt"`)
+
+	manager := NewManager()
+	if err := manager.Track(client); err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+	session, err := manager.CreateSession(context.Background(), client, "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	var order []string
+	manager.OnShutdown("server", func(context.Context) error {
+		order = append(order, "server:"+string(client.State()))
+		return nil
+	})
+	manager.OnShutdown("queue", func(context.Context) error {
+		order = append(order, "queue")
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}
+		_, err := session.Query(context.Background(), request)
+		done <- err
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for client.State() != types.ClientBusy && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Expected the in-flight query to finish, got %v", err)
+	}
+
+	if strings.Join(order, ",") != "queue,server:busy" {
+		t.Errorf("Expected hooks to run newest first before draining, got %v", order)
+	}
+	if client.State() != types.ClientClosed {
+		t.Errorf("Expected the client to be closed, got %s", client.State())
+	}
+	if _, err := client.GetSession(session.ID); err == nil {
+		t.Error("Expected the session to be closed")
+	}
+
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Errorf("Expected a second Shutdown to succeed, got %v", err)
+	}
+	if _, err := manager.NewClient(ctx, &types.ClaudeCodeConfig{TestMode: true}); err == nil {
+		t.Error("Expected NewClient to fail after Shutdown")
+	}
+	if err := manager.Track(client); err == nil {
+		t.Error("Expected Track to fail after Shutdown")
+	}
+}
+
+func TestManager_ShutdownTimeout(t *testing.T) {
+	client := newFakeCLIClient(t, `sleep 10`)
+
+	manager := NewManager()
+	if err := manager.Track(client); err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+	hookErr := errors.New("server still listening")
+	manager.OnShutdown("server", func(context.Context) error { return hookErr })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Query(context.Background(), &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}})
+		done <- err
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for client.State() != types.ClientBusy && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := manager.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, hookErr) {
+		t.Errorf("Expected the timeout and hook errors, got %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the running query to be interrupted")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Shutdown took %s", elapsed)
+	}
+	if client.State() != types.ClientClosed {
+		t.Errorf("Expected the client to be closed, got %s", client.State())
+	}
+}
@@ -80,18 +80,7 @@ func (m *MCPManager) AddServer(name string, config *types.MCPServerConfig) error
 	defer m.mu.Unlock()
 
 	// Create a copy to avoid external modifications
-	serverConfig := &types.MCPServerConfig{
-		Command:          config.Command,
-		Args:             make([]string, len(config.Args)),
-		Environment:      make(map[string]string),
-		WorkingDirectory: config.WorkingDirectory,
-		Enabled:          config.Enabled,
-	}
-
-	copy(serverConfig.Args, config.Args)
-	for k, v := range config.Environment {
-		serverConfig.Environment[k] = v
-	}
+	serverConfig := cloneMCPServerConfig(config)
 
 	m.servers[name] = serverConfig
 
@@ -122,6 +111,37 @@ func (m *MCPManager) RemoveServer(name string) error {
 	return nil
 }
 
+// replaceServers swaps the whole server set for servers, reporting whether
+// anything changed. Nothing is replaced if any server is invalid.
+func (m *MCPManager) replaceServers(servers map[string]*types.MCPServerConfig) (bool, error) {
+	for name, config := range servers {
+		if config == nil {
+			return false, sdkerrors.NewValidationError("mcp_servers."+name, "", "required", "server config cannot be nil")
+		}
+		if err := m.validateServerConfig(config); err != nil {
+			return false, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "SERVER_CONFIG", "invalid configuration for MCP server "+name)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, _ := json.Marshal(m.servers)   // Ignore error, server configs always marshal
+	replacement, _ := json.Marshal(servers) // Ignore error, server configs always marshal
+	if string(current) == string(replacement) {
+		return false, nil
+	}
+
+	m.servers = make(map[string]*types.MCPServerConfig, len(servers))
+	for name, config := range servers {
+		m.servers[name] = cloneMCPServerConfig(config)
+	}
+
+	m.updateClientConfig()
+
+	return true, nil
+}
+
 // EnableServer enables an MCP server.
 func (m *MCPManager) EnableServer(name string) error {
 	return m.setServerEnabled(name, true)
@@ -162,17 +182,7 @@ func (m *MCPManager) ListServers() map[string]*types.MCPServerConfig {
 	result := make(map[string]*types.MCPServerConfig)
 	for name, config := range m.servers {
 		// Create a copy to prevent external modifications
-		result[name] = &types.MCPServerConfig{
-			Command:          config.Command,
-			Args:             make([]string, len(config.Args)),
-			Environment:      make(map[string]string),
-			WorkingDirectory: config.WorkingDirectory,
-			Enabled:          config.Enabled,
-		}
-		copy(result[name].Args, config.Args)
-		for k, v := range config.Environment {
-			result[name].Environment[k] = v
-		}
+		result[name] = cloneMCPServerConfig(config)
 	}
 
 	return result
@@ -193,19 +203,7 @@ func (m *MCPManager) GetServer(name string) (*types.MCPServerConfig, error) {
 	}
 
 	// Return a copy to prevent external modifications
-	result := &types.MCPServerConfig{
-		Command:          config.Command,
-		Args:             make([]string, len(config.Args)),
-		Environment:      make(map[string]string),
-		WorkingDirectory: config.WorkingDirectory,
-		Enabled:          config.Enabled,
-	}
-	copy(result.Args, config.Args)
-	for k, v := range config.Environment {
-		result.Environment[k] = v
-	}
-
-	return result, nil
+	return cloneMCPServerConfig(config), nil
 }
 
 // ApplyConfiguration applies the current MCP server configuration to Claude Code.
@@ -215,54 +213,12 @@ func (m *MCPManager) ApplyConfiguration(ctx context.Context) error {
 	defer m.mu.RUnlock()
 
 	// Generate MCP configuration file path
-	configDir := filepath.Join(m.client.workingDir, ".claude")
+	configDir := filepath.Join(m.client.currentWorkingDir(), ".claude")
 	if err := os.MkdirAll(configDir, 0750); err != nil {
 		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONFIG_DIR", "failed to create config directory")
 	}
 
-	mcpConfigPath := filepath.Join(configDir, "mcp.json")
-
-	// Build MCP configuration
-	mcpConfig := make(map[string]any)
-	servers := make(map[string]any)
-
-	for name, config := range m.servers {
-		if !config.Enabled {
-			continue
-		}
-
-		serverConfig := map[string]any{
-			"command": config.Command,
-		}
-
-		if len(config.Args) > 0 {
-			serverConfig["args"] = config.Args
-		}
-
-		if len(config.Environment) > 0 {
-			serverConfig["env"] = config.Environment
-		}
-
-		if config.WorkingDirectory != "" {
-			serverConfig["cwd"] = config.WorkingDirectory
-		}
-
-		servers[name] = serverConfig
-	}
-
-	mcpConfig["mcpServers"] = servers
-
-	// Write configuration file
-	configData, err := json.MarshalIndent(mcpConfig, "", "  ")
-	if err != nil {
-		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONFIG_MARSHAL", "failed to marshal MCP configuration")
-	}
-
-	if err := os.WriteFile(mcpConfigPath, configData, 0600); err != nil {
-		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONFIG_WRITE", "failed to write MCP configuration file")
-	}
-
-	return nil
+	return m.writeConfigFile(filepath.Join(configDir, "mcp.json"))
 }
 
 // LoadFromFile loads MCP server configurations from a JSON file.
@@ -332,10 +288,45 @@ func (m *MCPManager) SaveToFile(filePath string) error {
 	return nil
 }
 
+// cloneMCPServerConfig returns a deep copy of an MCP server configuration.
+func cloneMCPServerConfig(config *types.MCPServerConfig) *types.MCPServerConfig {
+	clone := *config
+	clone.Args = make([]string, len(config.Args))
+	copy(clone.Args, config.Args)
+
+	clone.Environment = make(map[string]string, len(config.Environment))
+	for k, v := range config.Environment {
+		clone.Environment[k] = v
+	}
+
+	if config.Headers != nil {
+		clone.Headers = make(map[string]string, len(config.Headers))
+		for k, v := range config.Headers {
+			clone.Headers[k] = v
+		}
+	}
+
+	return &clone
+}
+
 // validateServerConfig validates an MCP server configuration.
 func (m *MCPManager) validateServerConfig(config *types.MCPServerConfig) error {
-	if config.Command == "" {
-		return sdkerrors.NewValidationError("command", "", "required", "command is required")
+	return validateMCPServerConfig(config)
+}
+
+// validateMCPServerConfig validates an MCP server configuration.
+func validateMCPServerConfig(config *types.MCPServerConfig) error {
+	switch config.Type {
+	case "", "stdio":
+		if config.Command == "" {
+			return sdkerrors.NewValidationError("command", "", "required", "command is required")
+		}
+	case "sse", "http":
+		if config.URL == "" {
+			return sdkerrors.NewValidationError("url", "", "required", "url is required for "+config.Type+" servers")
+		}
+	default:
+		return sdkerrors.NewValidationError("type", config.Type, "stdio, sse or http", "unsupported MCP server type")
 	}
 
 	// Validate working directory if provided
@@ -350,19 +341,16 @@ func (m *MCPManager) validateServerConfig(config *types.MCPServerConfig) error {
 
 // updateClientConfig updates the client's MCP server configuration.
 func (m *MCPManager) updateClientConfig() {
-	if m.client.config.MCPServers == nil {
-		m.client.config.MCPServers = make(map[string]*types.MCPServerConfig)
-	}
-
-	// Clear existing servers
-	for name := range m.client.config.MCPServers {
-		delete(m.client.config.MCPServers, name)
-	}
-
-	// Add current servers
+	servers := make(map[string]*types.MCPServerConfig, len(m.servers))
 	for name, config := range m.servers {
-		m.client.config.MCPServers[name] = config
+		servers[name] = config
 	}
+
+	m.client.mu.Lock()
+	defer m.client.mu.Unlock()
+	m.client.updateSettings(func(config *types.ClaudeCodeConfig) {
+		config.MCPServers = servers
+	})
 }
 
 // GetEnabledServers returns a list of enabled MCP servers.
@@ -374,17 +362,7 @@ func (m *MCPManager) GetEnabledServers() map[string]*types.MCPServerConfig {
 	for name, config := range m.servers {
 		if config.Enabled {
 			// Create a copy to prevent external modifications
-			result[name] = &types.MCPServerConfig{
-				Command:          config.Command,
-				Args:             make([]string, len(config.Args)),
-				Environment:      make(map[string]string),
-				WorkingDirectory: config.WorkingDirectory,
-				Enabled:          config.Enabled,
-			}
-			copy(result[name].Args, config.Args)
-			for k, v := range config.Environment {
-				result[name].Environment[k] = v
-			}
+			result[name] = cloneMCPServerConfig(config)
 		}
 	}
 
@@ -396,7 +374,7 @@ func (m *MCPManager) AddCommonServers() error {
 	// Filesystem server - for file system operations
 	filesystemServer := &types.MCPServerConfig{
 		Command: "npx",
-		Args:    []string{"@modelcontextprotocol/server-filesystem", m.client.workingDir},
+		Args:    []string{"@modelcontextprotocol/server-filesystem", m.client.currentWorkingDir()},
 		Enabled: false, // Disabled by default for security
 	}
 
@@ -408,7 +386,7 @@ func (m *MCPManager) AddCommonServers() error {
 	gitServer := &types.MCPServerConfig{
 		Command:          "npx",
 		Args:             []string{"@modelcontextprotocol/server-git"},
-		WorkingDirectory: m.client.workingDir,
+		WorkingDirectory: m.client.currentWorkingDir(),
 		Enabled:          false, // Disabled by default
 	}
 
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// mcpConfigFile is the CLI's .mcp.json format.
+type mcpConfigFile struct {
+	MCPServers map[string]mcpConfigFileServer `json:"mcpServers"`
+}
+
+// mcpConfigFileServer is a server entry in .mcp.json.
+type mcpConfigFileServer struct {
+	Type    string            `json:"type,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Cwd     string            `json:"cwd,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// mcpEnvPattern matches ${VAR} and ${VAR:-default} references in .mcp.json.
+var mcpEnvPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// WriteConfigFile writes the enabled servers to path in the CLI's .mcp.json
+// format, so SDK-managed servers can be checked into a project and used by
+// interactive `claude` sessions.
+func (m *MCPManager) WriteConfigFile(path string) error {
+	if path == "" {
+		return sdkerrors.NewValidationError("path", "", "required", "file path cannot be empty")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.writeConfigFile(path)
+}
+
+// ImportConfigFile adds the servers defined in a .mcp.json file (see
+// LoadMCPConfigFile), replacing servers with the same name.
+func (m *MCPManager) ImportConfigFile(path string) error {
+	servers, err := LoadMCPConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name, config := range servers {
+		m.servers[name] = config
+	}
+	m.updateClientConfig()
+
+	return nil
+}
+
+// writeConfigFile writes the enabled servers in .mcp.json format. The caller
+// must hold m.mu.
+func (m *MCPManager) writeConfigFile(path string) error {
+	file := mcpConfigFile{MCPServers: make(map[string]mcpConfigFileServer)}
+	for name, config := range m.servers {
+		if !config.Enabled {
+			continue
+		}
+		file.MCPServers[name] = mcpConfigFileServer{
+			Type:    config.Type,
+			Command: config.Command,
+			Args:    config.Args,
+			Env:     config.Environment,
+			Cwd:     config.WorkingDirectory,
+			URL:     config.URL,
+			Headers: config.Headers,
+		}
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONFIG_MARSHAL", "failed to marshal MCP configuration")
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CONFIG_WRITE", "failed to write MCP configuration file")
+	}
+
+	return nil
+}
+
+// LoadMCPConfigFile reads server definitions from a file in the CLI's
+// .mcp.json format. ${VAR} and ${VAR:-default} references are expanded from
+// the environment, as the CLI does. All servers are returned enabled.
+//
+// Example usage:
+//
+//	servers, err := client.LoadMCPConfigFile(".mcp.json")
+//	for name, server := range servers {
+//		err = claude.AddMCPServer(ctx, name, server)
+//	}
+func LoadMCPConfigFile(path string) (map[string]*types.MCPServerConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path)) // #nosec G304 - loading a caller-chosen config file is the purpose of this function
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "FILE_READ", "failed to read MCP configuration file")
+	}
+
+	var file mcpConfigFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "CONFIG_PARSE", "failed to parse MCP configuration file")
+	}
+
+	servers := make(map[string]*types.MCPServerConfig, len(file.MCPServers))
+	for name, entry := range file.MCPServers {
+		var missing []string
+		expand := func(value string) string {
+			return mcpEnvPattern.ReplaceAllStringFunc(value, func(ref string) string {
+				match := mcpEnvPattern.FindStringSubmatch(ref)
+				if env, ok := os.LookupEnv(match[1]); ok {
+					return env
+				}
+				if strings.Contains(ref, ":-") {
+					return match[2]
+				}
+				missing = append(missing, match[1])
+				return ref
+			})
+		}
+		expandMap := func(values map[string]string) map[string]string {
+			if values == nil {
+				return nil
+			}
+			expanded := make(map[string]string, len(values))
+			for key, value := range values {
+				expanded[key] = expand(value)
+			}
+			return expanded
+		}
+
+		config := &types.MCPServerConfig{
+			Type:             entry.Type,
+			Command:          expand(entry.Command),
+			Environment:      expandMap(entry.Env),
+			WorkingDirectory: expand(entry.Cwd),
+			URL:              expand(entry.URL),
+			Headers:          expandMap(entry.Headers),
+			Enabled:          true,
+		}
+		for _, arg := range entry.Args {
+			config.Args = append(config.Args, expand(arg))
+		}
+
+		if len(missing) > 0 {
+			return nil, sdkerrors.NewValidationError("mcpServers."+name, strings.Join(missing, ", "), "set", "missing environment variable "+strings.Join(missing, ", "))
+		}
+		if err := validateMCPServerConfig(config); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "SERVER_CONFIG", fmt.Sprintf("invalid configuration for server '%s'", name))
+		}
+
+		servers[name] = config
+	}
+
+	return servers, nil
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestMCPManager_WriteConfigFile(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	manager := client.mcpManager
+
+	servers := map[string]*types.MCPServerConfig{
+		"filesystem": {Command: "npx", Args: []string{"-y", "server-filesystem", "."}, Environment: map[string]string{"LOG": "debug"}, Enabled: true},
+		"github":     {Type: "http", URL: "https://api.example.com/mcp", Headers: map[string]string{"Authorization": "Bearer x"}, Enabled: true},
+		"disabled":   {Command: "echo"},
+	}
+	for name, server := range servers {
+		if err := manager.AddServer(name, server); err != nil {
+			t.Fatalf("AddServer(%s) failed: %v", name, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), ".mcp.json")
+	if err := manager.WriteConfigFile(path); err != nil {
+		t.Fatalf("WriteConfigFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	var raw map[string]map[string]map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(raw["mcpServers"]) != 2 || raw["mcpServers"]["filesystem"]["env"] == nil || raw["mcpServers"]["github"]["url"] == nil {
+		t.Errorf("Unexpected .mcp.json: %s", data)
+	}
+	if _, ok := raw["mcpServers"]["github"]["command"]; ok {
+		t.Errorf("Expected no command for remote servers: %s", data)
+	}
+
+	loaded, err := LoadMCPConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadMCPConfigFile failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded["filesystem"], servers["filesystem"]) || !reflect.DeepEqual(loaded["github"], servers["github"]) {
+		t.Errorf("Round trip mismatch: %+v", loaded)
+	}
+}
+
+func TestLoadMCPConfigFile_EnvExpansion(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	path := filepath.Join(t.TempDir(), ".mcp.json")
+	content := `{
+  "mcpServers": {
+    "github": {
+      "type": "stdio",
+      "command": "${MCP_BIN:-npx}",
+      "args": ["server-github"],
+      "env": {"GITHUB_TOKEN": "${GITHUB_TOKEN}"}
+    }
+  }
+}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	servers, err := LoadMCPConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadMCPConfigFile failed: %v", err)
+	}
+	github := servers["github"]
+	if github.Command != "npx" || github.Environment["GITHUB_TOKEN"] != "secret" || !github.Enabled {
+		t.Errorf("Unexpected server: %+v", github)
+	}
+
+	content = `{"mcpServers": {"github": {"command": "npx", "env": {"TOKEN": "${UNSET_MCP_TOKEN}"}}}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadMCPConfigFile(path); err == nil {
+		t.Error("Expected error for a missing environment variable")
+	}
+
+	content = `{"mcpServers": {"remote": {"type": "sse"}}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadMCPConfigFile(path); err == nil {
+		t.Error("Expected error for a remote server without a URL")
+	}
+}
+
+func TestMCPManager_ImportConfigFile(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+
+	path := filepath.Join(t.TempDir(), ".mcp.json")
+	content := `{"mcpServers": {"memory": {"command": "npx", "args": ["server-memory"]}}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := client.mcpManager.ImportConfigFile(path); err != nil {
+		t.Fatalf("ImportConfigFile failed: %v", err)
+	}
+
+	server, err := client.GetMCPServer("memory")
+	if err != nil || server.Command != "npx" || !server.Enabled {
+		t.Errorf("Unexpected server: %+v (%v)", server, err)
+	}
+	if client.config.MCPServers["memory"] == nil {
+		t.Error("Expected client config to be updated")
+	}
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// mcpListPattern matches a server line of `claude mcp list` output, e.g.
+// "filesystem: npx -y @modelcontextprotocol/server-filesystem . - ✓ Connected".
+var mcpListPattern = regexp.MustCompile(`^([^\s:][^:]*):\s.*\s-\s+(.+)$`)
+
+// parseMCPList extracts server states from `claude mcp list` output.
+func parseMCPList(output string) map[string]types.MCPServerStatus {
+	statuses := make(map[string]types.MCPServerStatus)
+	for _, line := range strings.Split(output, "\n") {
+		match := mcpListPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		status := types.MCPServerStatus{Name: match[1]}
+		text := strings.TrimSpace(strings.TrimLeft(match[2], "✓✗⚠ "))
+		lower := strings.ToLower(text)
+		switch {
+		case strings.Contains(lower, "fail") || strings.Contains(lower, "error"):
+			status.State = types.MCPServerFailed
+			status.LastError = text
+		case strings.Contains(lower, "auth"):
+			status.State = types.MCPServerNeedsAuth
+			status.LastError = text
+		case strings.Contains(lower, "connected"):
+			status.State = types.MCPServerConnected
+		case strings.Contains(lower, "pending") || strings.Contains(lower, "connecting"):
+			status.State = types.MCPServerPending
+		default:
+			status.State = types.MCPServerUnknown
+		}
+		statuses[status.Name] = status
+	}
+	return statuses
+}
+
+// mcpStateFromInit maps a status string from the CLI's init message.
+func mcpStateFromInit(status string) types.MCPServerState {
+	switch strings.ToLower(status) {
+	case "connected":
+		return types.MCPServerConnected
+	case "failed":
+		return types.MCPServerFailed
+	case "needs-auth", "needs_auth":
+		return types.MCPServerNeedsAuth
+	case "pending":
+		return types.MCPServerPending
+	}
+	return types.MCPServerUnknown
+}
+
+// MCPServerStatus checks the MCP servers with `claude mcp list` and returns
+// the status of every known server, sorted by name.
+//
+// Servers the CLI does not list, such as those passed only through the SDK's
+// configuration, report the state and tool count from the most recent query's
+// init message, or MCPServerUnknown if none has been seen. Tool counts are
+// only known from init messages.
+func (c *ClaudeCodeClient) MCPServerStatus(ctx context.Context) ([]types.MCPServerStatus, error) {
+	start := time.Now()
+	output, err := c.probeCLI(ctx, "mcp", "list")
+	if err != nil {
+		return nil, err
+	}
+	latency := time.Since(start)
+
+	for _, status := range parseMCPList(output) {
+		status.Latency = latency
+		c.updateMCPStatus(status)
+	}
+
+	return c.mcpStatusSnapshot(), nil
+}
+
+// MonitorMCPServers calls MCPServerStatus every interval until ctx is
+// cancelled, so OnMCPServerUnhealthy fires even when no queries are running.
+func (c *ClaudeCodeClient) MonitorMCPServers(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_, _ = c.MCPServerStatus(ctx) // Ignore error, the next tick retries
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// observeMCPInit records MCP server statuses from a stream-json init line.
+func (c *ClaudeCodeClient) observeMCPInit(line []byte) {
+	init, ok := types.ParseSystemInit(line)
+	if !ok {
+		return
+	}
+
+	for _, server := range init.MCPServers {
+		prefix := "mcp__" + server.Name + "__"
+		count := 0
+		for _, tool := range init.Tools {
+			if strings.HasPrefix(tool, prefix) {
+				count++
+			}
+		}
+
+		status := types.MCPServerStatus{
+			Name:      server.Name,
+			State:     mcpStateFromInit(server.Status),
+			ToolCount: count,
+		}
+		if status.Unhealthy() {
+			status.LastError = "server status " + server.Status
+		}
+		c.updateMCPStatus(status)
+	}
+}
+
+// updateMCPStatus merges a new observation into the recorded status and
+// fires OnMCPServerUnhealthy when a server becomes unhealthy.
+func (c *ClaudeCodeClient) updateMCPStatus(status types.MCPServerStatus) {
+	c.mcpStatusMu.Lock()
+	if c.mcpStatuses == nil {
+		c.mcpStatuses = make(map[string]types.MCPServerStatus)
+	}
+
+	previous, seen := c.mcpStatuses[status.Name]
+	if status.LastError == "" {
+		status.LastError = previous.LastError
+	}
+	if status.ToolCount == 0 && status.State == previous.State {
+		status.ToolCount = previous.ToolCount
+	}
+	if status.Latency == 0 {
+		status.Latency = previous.Latency
+	}
+	status.CheckedAt = time.Now()
+	c.mcpStatuses[status.Name] = status
+	c.mcpStatusMu.Unlock()
+
+	if onUnhealthy := c.settings().OnMCPServerUnhealthy; onUnhealthy != nil && status.Unhealthy() && (!seen || !previous.Unhealthy()) {
+		onUnhealthy(status)
+	}
+}
+
+// mcpStatusSnapshot returns the recorded statuses plus configured servers
+// without one, sorted by name.
+func (c *ClaudeCodeClient) mcpStatusSnapshot() []types.MCPServerStatus {
+	c.mcpStatusMu.Lock()
+	statuses := make(map[string]types.MCPServerStatus, len(c.mcpStatuses))
+	for name, status := range c.mcpStatuses {
+		statuses[name] = status
+	}
+	c.mcpStatusMu.Unlock()
+
+	if c.mcpManager != nil {
+		for name, server := range c.mcpManager.ListServers() {
+			switch {
+			case !server.Enabled:
+				statuses[name] = types.MCPServerStatus{Name: name, State: types.MCPServerDisabled}
+			case statuses[name].Name == "":
+				statuses[name] = types.MCPServerStatus{Name: name, State: types.MCPServerUnknown}
+			}
+		}
+	}
+
+	result := make([]types.MCPServerStatus, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
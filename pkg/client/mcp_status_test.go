@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const mcpListScript = `
+if [ "$1" = "mcp" ]; then
+	echo "Checking MCP server health..."
+	echo ""
+	echo "filesystem: npx -y @modelcontextprotocol/server-filesystem . - ✓ Connected"
+	echo "broken: /usr/bin/missing-server - ✗ Failed to connect"
+	echo "github: https://api.githubcopilot.com/mcp/ (HTTP) - ⚠ Needs authentication"
+	exit 0
+fi
+echo "handled $prompt"`
+
+func TestParseMCPList(t *testing.T) {
+	statuses := parseMCPList("Checking MCP server health...\n\nfs: npx server - ✓ Connected\nbad: cmd --flag - ✗ Failed to connect\n")
+
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 servers, got %v", statuses)
+	}
+	if statuses["fs"].State != types.MCPServerConnected {
+		t.Errorf("fs = %+v", statuses["fs"])
+	}
+	if bad := statuses["bad"]; bad.State != types.MCPServerFailed || bad.LastError != "Failed to connect" {
+		t.Errorf("bad = %+v", bad)
+	}
+}
+
+func TestMCPServerStatus(t *testing.T) {
+	client := newFakeCLIClient(t, mcpListScript)
+
+	var unhealthy []types.MCPServerStatus
+	client.config.OnMCPServerUnhealthy = func(status types.MCPServerStatus) {
+		unhealthy = append(unhealthy, status)
+	}
+	if err := client.mcpManager.AddServer("sdk-only", &types.MCPServerConfig{Command: "echo", Enabled: true}); err != nil {
+		t.Fatalf("AddServer failed: %v", err)
+	}
+	if err := client.mcpManager.AddServer("off", &types.MCPServerConfig{Command: "echo"}); err != nil {
+		t.Fatalf("AddServer failed: %v", err)
+	}
+
+	statuses, err := client.MCPServerStatus(context.Background())
+	if err != nil {
+		t.Fatalf("MCPServerStatus failed: %v", err)
+	}
+
+	want := map[string]types.MCPServerState{
+		"broken":     types.MCPServerFailed,
+		"filesystem": types.MCPServerConnected,
+		"github":     types.MCPServerNeedsAuth,
+		"off":        types.MCPServerDisabled,
+		"sdk-only":   types.MCPServerUnknown,
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("Unexpected statuses: %+v", statuses)
+	}
+	for i, status := range statuses {
+		if want[status.Name] != status.State {
+			t.Errorf("%s: state %q, want %q", status.Name, status.State, want[status.Name])
+		}
+		if i > 0 && statuses[i-1].Name > status.Name {
+			t.Error("Expected statuses sorted by name")
+		}
+	}
+	if statuses[1].Latency <= 0 {
+		t.Errorf("Expected latency to be recorded: %+v", statuses[1])
+	}
+
+	if len(unhealthy) != 2 {
+		t.Errorf("Expected 2 unhealthy events, got %+v", unhealthy)
+	}
+
+	// Repeated checks do not repeat the event
+	if _, err := client.MCPServerStatus(context.Background()); err != nil {
+		t.Fatalf("MCPServerStatus failed: %v", err)
+	}
+	if len(unhealthy) != 2 {
+		t.Errorf("Expected no new unhealthy events, got %+v", unhealthy)
+	}
+}
+
+func TestObserveMCPInit(t *testing.T) {
+	client := &ClaudeCodeClient{config: &types.ClaudeCodeConfig{}}
+
+	var unhealthy []string
+	client.config.OnMCPServerUnhealthy = func(status types.MCPServerStatus) {
+		unhealthy = append(unhealthy, status.Name)
+	}
+
+	client.observeMCPInit([]byte(`{"type":"system","subtype":"init","session_id":"s1","tools":["Read","mcp__fs__read_file","mcp__fs__list_directory"],"mcp_servers":[{"name":"fs","status":"connected"},{"name":"db","status":"failed"}]}`))
+	client.observeMCPInit([]byte(`{"type":"assistant","message":{"content":[]}}`))
+
+	statuses := client.mcpStatusSnapshot()
+	if len(statuses) != 2 {
+		t.Fatalf("Unexpected statuses: %+v", statuses)
+	}
+	if db := statuses[0]; db.Name != "db" || db.State != types.MCPServerFailed || db.LastError == "" {
+		t.Errorf("db = %+v", db)
+	}
+	if fs := statuses[1]; fs.State != types.MCPServerConnected || fs.ToolCount != 2 {
+		t.Errorf("fs = %+v", fs)
+	}
+	if len(unhealthy) != 1 || unhealthy[0] != "db" {
+		t.Errorf("Unexpected unhealthy events: %v", unhealthy)
+	}
+
+	// A recovered server fires again when it next fails
+	client.observeMCPInit([]byte(`{"type":"system","subtype":"init","mcp_servers":[{"name":"db","status":"connected"}]}`))
+	client.observeMCPInit([]byte(`{"type":"system","subtype":"init","mcp_servers":[{"name":"db","status":"failed"}]}`))
+	if len(unhealthy) != 2 {
+		t.Errorf("Expected a second unhealthy event, got %v", unhealthy)
+	}
+}
@@ -0,0 +1,230 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// DefaultMCPDiscoveryTimeout bounds ListMCPTools when ctx has no deadline
+	DefaultMCPDiscoveryTimeout = 30 * time.Second
+
+	// mcpProtocolVersion is the MCP revision offered during initialization
+	mcpProtocolVersion = "2024-11-05"
+)
+
+// ListMCPTools starts a configured MCP server, asks it for its tools over the
+// MCP stdio protocol and returns each tool's name, description and JSON input
+// schema, sorted by name. The server is stopped before returning.
+//
+// Example usage:
+//
+//	tools, err := claude.ListMCPTools(ctx, "github")
+//	for _, tool := range tools {
+//		fmt.Println(tool.QualifiedName(), tool.Description)
+//	}
+func (c *ClaudeCodeClient) ListMCPTools(ctx context.Context, serverName string) ([]types.MCPTool, error) {
+	config, err := c.mcpManager.GetServer(serverName)
+	if err != nil {
+		return nil, err
+	}
+	if config.IsRemote() {
+		return nil, sdkerrors.NewValidationError("serverName", serverName, "stdio server", "tool discovery is only supported for stdio MCP servers")
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultMCPDiscoveryTimeout)
+		defer cancel()
+	}
+
+	session, err := c.startMCPServer(ctx, config)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MCP_START", "failed to start MCP server "+serverName)
+	}
+	defer session.close()
+
+	var initResult json.RawMessage
+	if err := session.call(ctx, "initialize", map[string]any{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "claude-code-go-sdk", "version": "1.0.0"},
+	}, &initResult); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MCP_INITIALIZE", "failed to initialize MCP server "+serverName)
+	}
+	if err := session.notify("notifications/initialized"); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MCP_INITIALIZE", "failed to initialize MCP server "+serverName)
+	}
+
+	var tools []types.MCPTool
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		var page struct {
+			Tools []struct {
+				Name        string          `json:"name"`
+				Description string          `json:"description"`
+				InputSchema json.RawMessage `json:"inputSchema"`
+			} `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := session.call(ctx, "tools/list", params, &page); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MCP_TOOLS_LIST", "failed to list tools of MCP server "+serverName)
+		}
+
+		for _, tool := range page.Tools {
+			tools = append(tools, types.MCPTool{
+				Server:      serverName,
+				Name:        tool.Name,
+				Description: tool.Description,
+				InputSchema: tool.InputSchema,
+			})
+		}
+
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools, nil
+}
+
+// mcpStdioSession is a minimal JSON-RPC client for an MCP server over stdio.
+type mcpStdioSession struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	lines  chan []byte
+	done   chan struct{}
+	nextID int
+	mu     sync.Mutex
+}
+
+// startMCPServer starts an MCP server process with the client's filtered
+// environment plus the server's own variables.
+func (c *ClaudeCodeClient) startMCPServer(ctx context.Context, config *types.MCPServerConfig) (*mcpStdioSession, error) {
+	env, err := c.subprocessEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range config.Environment {
+		env = append(env, key+"="+value)
+	}
+
+	cmd := exec.CommandContext(ctx, config.Command, config.Args...) // #nosec G204 - MCP server commands come from the client's configuration
+	cmd.Env = env
+	cmd.Dir = c.currentWorkingDir()
+	if config.WorkingDirectory != "" {
+		cmd.Dir = config.WorkingDirectory
+	}
+	cmd.WaitDelay = time.Second
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	session := &mcpStdioSession{cmd: cmd, stdin: stdin, lines: make(chan []byte, 16), done: make(chan struct{})}
+	go func() {
+		defer close(session.lines)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxCLILineSize)
+		for scanner.Scan() {
+			select {
+			case session.lines <- append([]byte(nil), scanner.Bytes()...):
+			case <-session.done:
+				return
+			}
+		}
+	}()
+
+	return session, nil
+}
+
+// call sends a request and decodes the matching response's result.
+func (s *mcpStdioSession) call(ctx context.Context, method string, params any, result any) error {
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.mu.Unlock()
+
+	if err := s.send(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-s.lines:
+			if !ok {
+				return fmt.Errorf("MCP server exited before responding to %s", method)
+			}
+
+			var response struct {
+				ID     *int            `json:"id"`
+				Method string          `json:"method"`
+				Result json.RawMessage `json:"result"`
+				Error  *struct {
+					Code    int    `json:"code"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(line, &response); err != nil || response.Method != "" || response.ID == nil || *response.ID != id {
+				continue // Logs, notifications and server requests are not responses
+			}
+			if response.Error != nil {
+				return fmt.Errorf("%s failed: %s (code %d)", method, response.Error.Message, response.Error.Code)
+			}
+			return json.Unmarshal(response.Result, result)
+		}
+	}
+}
+
+// notify sends a notification, which has no response.
+func (s *mcpStdioSession) notify(method string) error {
+	return s.send(map[string]any{"jsonrpc": "2.0", "method": method})
+}
+
+// send writes one JSON-RPC message as a line.
+func (s *mcpStdioSession) send(message map[string]any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// close ends the session and stops the server.
+func (s *mcpStdioSession) close() {
+	close(s.done)
+	_ = s.stdin.Close() // Ignore error, closing stdin asks the server to exit
+	_ = s.cmd.Process.Kill()
+	_ = s.cmd.Wait() // Ignore error, the server was killed
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeMCPServer answers initialize and two pages of tools/list over stdio.
+const fakeMCPServer = `#!/bin/sh
+while IFS= read -r line; do
+	case "$line" in
+	*'"method":"initialize"'*)
+		echo 'starting fake server' >&2
+		echo '{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info","data":"ready"}}'
+		echo '{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2024-11-05","capabilities":{"tools":{}},"serverInfo":{"name":"fake","version":"1"}}}'
+		;;
+	*'"method":"tools/list"'*'"cursor":"page2"'*)
+		echo '{"jsonrpc":"2.0","id":3,"result":{"tools":[{"name":"create_issue","description":"Create an issue","inputSchema":{"type":"object","properties":{"title":{"type":"string","description":"Issue title"},"labels":{"type":"array","items":{"type":"string"}}},"required":["title"]}}]}}'
+		;;
+	*'"method":"tools/list"'*)
+		echo '{"jsonrpc":"2.0","id":2,"result":{"tools":[{"name":"search","description":"Search issues","inputSchema":{"type":"object","properties":{"query":{"type":"string"}},"required":["query"]}}],"nextCursor":"page2"}}'
+		;;
+	esac
+done
+`
+
+func newFakeMCPClient(t *testing.T) *ClaudeCodeClient {
+	t.Helper()
+
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	server := filepath.Join(t.TempDir(), "fake-mcp")
+	if err := os.WriteFile(server, []byte(fakeMCPServer), 0700); err != nil {
+		t.Fatalf("Failed to write fake MCP server: %v", err)
+	}
+	if err := client.mcpManager.AddServer("github", &types.MCPServerConfig{Command: server, Enabled: true}); err != nil {
+		t.Fatalf("AddServer failed: %v", err)
+	}
+	return client
+}
+
+func TestListMCPTools(t *testing.T) {
+	client := newFakeMCPClient(t)
+
+	tools, err := client.ListMCPTools(context.Background(), "github")
+	if err != nil {
+		t.Fatalf("ListMCPTools failed: %v", err)
+	}
+
+	if len(tools) != 2 || tools[0].Name != "create_issue" || tools[1].Name != "search" {
+		t.Fatalf("Unexpected tools: %+v", tools)
+	}
+	if tools[0].QualifiedName() != "mcp__github__create_issue" || tools[0].Description != "Create an issue" {
+		t.Errorf("Unexpected tool: %+v", tools[0])
+	}
+
+	schema, err := tools[0].Schema()
+	if err != nil {
+		t.Fatalf("Schema failed: %v", err)
+	}
+	if schema.Type != "object" || schema.Properties["labels"].Items == nil || len(schema.Required) != 1 {
+		t.Errorf("Unexpected schema: %+v", schema)
+	}
+}
+
+func TestListMCPTools_Errors(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+
+	if _, err := client.ListMCPTools(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for an unknown server")
+	}
+
+	if err := client.mcpManager.AddServer("exits", &types.MCPServerConfig{Command: "true", Enabled: true}); err != nil {
+		t.Fatalf("AddServer failed: %v", err)
+	}
+	if _, err := client.ListMCPTools(context.Background(), "exits"); err == nil {
+		t.Error("Expected error for a server that exits")
+	}
+}
+
+func TestDiscoverToolsIncludesMCPTools(t *testing.T) {
+	client := newFakeMCPClient(t)
+
+	tools, err := client.DiscoverTools(context.Background())
+	if err != nil {
+		t.Fatalf("DiscoverTools failed: %v", err)
+	}
+
+	var found *ClaudeCodeToolDefinition
+	for _, tool := range tools {
+		if tool.Name == "create_issue" {
+			found = tool
+		}
+	}
+	if found == nil {
+		t.Fatal("Expected create_issue to be discovered")
+	}
+	if found.Source != "mcp:github" || found.Parameters["title"].Type != "string" || found.RequiredParameters[0] != "title" {
+		t.Errorf("Unexpected definition: %+v", found)
+	}
+}
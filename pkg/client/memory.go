@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// memoryPrompt recalls the configured Memory's facts relevant to the first
+// prompt of a new session and formats them for the system prompt. It returns
+// "" without a Memory, when nothing relevant is found, or when recall fails,
+// so that an unavailable memory store never fails a query.
+func (c *ClaudeCodeClient) memoryPrompt(ctx context.Context, prompt string) string {
+	config := c.settings()
+	if config.Memory == nil || strings.TrimSpace(prompt) == "" {
+		return ""
+	}
+
+	limit := config.MemoryRecallLimit
+	if limit <= 0 {
+		limit = types.DefaultMemoryRecallLimit
+	}
+	facts, err := config.Memory.Recall(ctx, prompt, limit)
+	if err != nil || len(facts) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("<memories>\nFacts remembered from earlier conversations:\n")
+	for _, fact := range facts {
+		b.WriteString("- " + strings.ReplaceAll(strings.TrimSpace(fact), "\n", " ") + "\n")
+	}
+	b.WriteString("</memories>")
+	return b.String()
+}
+
+// withMemories appends the memories recalled for prompt to a system prompt.
+func (c *ClaudeCodeClient) withMemories(ctx context.Context, system, prompt string) string {
+	memories := c.memoryPrompt(ctx, prompt)
+	switch {
+	case memories == "":
+		return system
+	case system == "":
+		return memories
+	}
+	return system + "\n\n" + memories
+}
+
+// lastUserMessage returns the content of the last user message of a request.
+func lastUserMessage(request *types.QueryRequest) string {
+	for i := len(request.Messages) - 1; i >= 0; i-- {
+		if request.Messages[i].Role == types.RoleUser {
+			return request.Messages[i].Content
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeRecaller records recall requests and returns fixed facts.
+type fakeRecaller struct {
+	facts   []string
+	err     error
+	prompts []string
+	limits  []int
+}
+
+func (r *fakeRecaller) Recall(ctx context.Context, text string, limit int) ([]string, error) {
+	r.prompts = append(r.prompts, text)
+	r.limits = append(r.limits, limit)
+	return r.facts, r.err
+}
+
+func TestMemory_QueryMessages(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: $*"`)
+	recaller := &fakeRecaller{facts: []string{"The staging database is Postgres 15", "Tests are\ntable-driven"}}
+	client.config.Memory = recaller
+
+	result, err := client.QueryMessagesSync(context.Background(), "migrate staging", &QueryOptions{SystemPrompt: "Be brief."})
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+	output := result.Messages[len(result.Messages)-1].Content
+	// The fake CLI's output loses the blank line between the parts
+	want := "--append-system-prompt Be brief.\n<memories>\nFacts remembered from earlier conversations:\n" +
+		"- The staging database is Postgres 15\n- Tests are table-driven\n</memories> migrate staging"
+	if !strings.Contains(output, want) {
+		t.Errorf("Expected memories in CLI args, got: %s", output)
+	}
+	if len(recaller.prompts) != 1 || recaller.prompts[0] != "migrate staging" || recaller.limits[0] != types.DefaultMemoryRecallLimit {
+		t.Errorf("Unexpected recall requests: %v %v", recaller.prompts, recaller.limits)
+	}
+
+	// Resumed sessions already have their context
+	if _, err := client.QueryMessagesSync(context.Background(), "continue", &QueryOptions{SessionID: "existing"}); err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+	if len(recaller.prompts) != 1 {
+		t.Errorf("Expected no recall for a resumed session, got %v", recaller.prompts)
+	}
+}
+
+func TestMemory_Session(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "$*"`)
+	recaller := &fakeRecaller{facts: []string{"Deploys run on Fridays"}}
+	client.config.Memory = recaller
+	client.config.MemoryRecallLimit = 2
+
+	session, err := client.CreateSession(context.Background(), "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	defer session.Close()
+
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "when do we deploy?"}}}
+	response, err := session.Query(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if !strings.Contains(response.Content[0].Text, "- Deploys run on Fridays") {
+		t.Errorf("Expected memories in the first query, got: %s", response.Content[0].Text)
+	}
+	if len(recaller.limits) != 1 || recaller.limits[0] != 2 || recaller.prompts[0] != "when do we deploy?" {
+		t.Errorf("Unexpected recall requests: %v %v", recaller.prompts, recaller.limits)
+	}
+
+	response, err = session.Query(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if strings.Contains(response.Content[0].Text, "<memories>") || len(recaller.prompts) != 1 {
+		t.Errorf("Expected memories only in the first query, got: %s", response.Content[0].Text)
+	}
+}
+
+func TestMemory_RecallFailure(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: $*"`)
+	client.config.Memory = &fakeRecaller{err: errors.New("store offline")}
+
+	result, err := client.QueryMessagesSync(context.Background(), "hello", nil)
+	if err != nil {
+		t.Fatalf("Expected the query to succeed without memories: %v", err)
+	}
+	if output := result.Messages[len(result.Messages)-1].Content; strings.Contains(output, "--append-system-prompt") {
+		t.Errorf("Expected no system prompt, got: %s", output)
+	}
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// policyTagsKey is the metadata key under which MessagePolicy tags are
+// recorded on requests, responses, messages and stream chunks.
+const policyTagsKey = "policy_tags"
+
+// applyPolicy runs the configured MessagePolicy over text. Without a policy,
+// text is returned unchanged.
+func (c *ClaudeCodeClient) applyPolicy(ctx context.Context, direction types.MessageDirection, text string) (string, []string, error) {
+	policy := c.settings().MessagePolicy
+	if policy == nil || text == "" {
+		return text, nil, nil
+	}
+	return policy.Apply(ctx, direction, text)
+}
+
+// policyRequest applies the MessagePolicy to a request's system prompt and
+// messages, returning a copy with the rewritten text and the policy's tags
+// in its metadata. The request is returned as is without a policy.
+func (c *ClaudeCodeClient) policyRequest(ctx context.Context, request *types.QueryRequest) (*types.QueryRequest, error) {
+	if c.settings().MessagePolicy == nil {
+		return request, nil
+	}
+
+	checked := *request
+	var tags []string
+	system, found, err := c.applyPolicy(ctx, types.MessageOutgoing, request.System)
+	if err != nil {
+		return nil, err
+	}
+	checked.System = system
+	tags = appendTags(tags, found)
+
+	checked.Messages = make([]types.Message, len(request.Messages))
+	for i, message := range request.Messages {
+		if message.Content, found, err = c.applyPolicy(ctx, types.MessageOutgoing, message.Content); err != nil {
+			return nil, err
+		}
+		tags = appendTags(tags, found)
+		checked.Messages[i] = message
+	}
+
+	if len(tags) > 0 {
+		checked.Metadata = withPolicyTags(request.Metadata, tags)
+	}
+	return &checked, nil
+}
+
+// policyResponse applies the MessagePolicy to the text blocks of a response
+// in place, recording the policy's tags in its metadata.
+func (c *ClaudeCodeClient) policyResponse(ctx context.Context, response *types.QueryResponse) error {
+	if c.settings().MessagePolicy == nil {
+		return nil
+	}
+
+	var tags []string
+	for i := range response.Content {
+		block := &response.Content[i]
+		if block.Type != "text" {
+			continue
+		}
+		text, found, err := c.applyPolicy(ctx, types.MessageIncoming, block.Text)
+		if err != nil {
+			return err
+		}
+		block.Text = text
+		tags = appendTags(tags, found)
+	}
+
+	if len(tags) > 0 {
+		response.Metadata = withPolicyTags(response.Metadata, tags)
+	}
+	return nil
+}
+
+// policyMessages forwards messages from in to out, applying the
+// MessagePolicy to assistant messages. A blocked message is replaced by an
+// error message. It returns when in is closed.
+func (c *ClaudeCodeClient) policyMessages(ctx context.Context, in <-chan *types.Message, out chan<- *types.Message) {
+	for message := range in {
+		if message != nil && message.Role == types.RoleAssistant {
+			text, tags, err := c.applyPolicy(ctx, types.MessageIncoming, message.Content)
+			if err != nil {
+				message = &types.Message{Role: types.RoleSystem, Content: fmt.Sprintf("Error: %v", err)}
+			} else {
+				message.Content = text
+				if len(tags) > 0 {
+					message.Metadata = withPolicyTags(message.Metadata, tags)
+				}
+			}
+		}
+		out <- message
+	}
+}
+
+// policyStream applies the MessagePolicy to the content chunks of a stream.
+// Each chunk is checked on its own, so text split across chunks may escape
+// pattern-based policies.
+type policyStream struct {
+	types.QueryStream
+	ctx    context.Context
+	client *ClaudeCodeClient
+}
+
+// Recv implements types.QueryStream.
+func (s *policyStream) Recv() (*types.StreamChunk, error) {
+	chunk, err := s.QueryStream.Recv()
+	if err != nil || chunk == nil || chunk.Type != types.ChunkTypeContent {
+		return chunk, err
+	}
+
+	text, tags, err := s.client.applyPolicy(s.ctx, types.MessageIncoming, chunk.Content)
+	if err != nil {
+		return nil, err
+	}
+	chunk.Content = text
+	if len(tags) > 0 {
+		chunk.Metadata = withPolicyTags(chunk.Metadata, tags)
+	}
+	return chunk, nil
+}
+
+// withPolicyTags returns a copy of metadata with tags added to its policy
+// tags.
+func withPolicyTags(metadata map[string]any, tags []string) map[string]any {
+	updated := make(map[string]any, len(metadata)+1)
+	for key, value := range metadata {
+		updated[key] = value
+	}
+	existing, _ := updated[policyTagsKey].([]string)
+	updated[policyTagsKey] = appendTags(append([]string(nil), existing...), tags)
+	return updated
+}
+
+// appendTags adds the tags not already in list.
+func appendTags(list, tags []string) []string {
+	for _, tag := range tags {
+		if !containsString(list, tag) {
+			list = append(list, tag)
+		}
+	}
+	return list
+}
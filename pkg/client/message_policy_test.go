@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// wordPolicy redacts outgoing "alice", tags incoming "bob" and blocks
+// "mallory" in either direction.
+type wordPolicy struct{}
+
+var errMallory = errors.New("mallory is not allowed")
+
+func (wordPolicy) Apply(ctx context.Context, direction types.MessageDirection, text string) (string, []string, error) {
+	if strings.Contains(text, "mallory") {
+		return "", nil, errMallory
+	}
+	var tags []string
+	if direction == types.MessageOutgoing && strings.Contains(text, "alice") {
+		text = strings.ReplaceAll(text, "alice", "[name]")
+		tags = append(tags, "name")
+	}
+	if direction == types.MessageIncoming && strings.Contains(text, "bob") {
+		tags = append(tags, "bob")
+	}
+	return text, tags, nil
+}
+
+func TestMessagePolicy_Query(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "bob says $prompt"`)
+	client.config.MessagePolicy = wordPolicy{}
+
+	request := &types.QueryRequest{
+		System:   "Be kind to alice.",
+		Messages: []types.Message{{Role: types.RoleUser, Content: "greet alice"}},
+	}
+	response, err := client.Query(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if text := response.Content[0].Text; !strings.Contains(text, "bob says greet [name]") {
+		t.Errorf("Expected the prompt redacted before it was sent, got: %s", text)
+	}
+	if tags, _ := response.Metadata[policyTagsKey].([]string); !reflect.DeepEqual(tags, []string{"bob"}) {
+		t.Errorf("Expected the response tagged, got %v", response.Metadata)
+	}
+	if request.Messages[0].Content != "greet alice" || request.System != "Be kind to alice." {
+		t.Errorf("Expected the caller's request unchanged, got %+v", request)
+	}
+
+	request.Messages[0].Content = "greet mallory"
+	if _, err := client.Query(context.Background(), request); !errors.Is(err, errMallory) {
+		t.Errorf("Expected the outgoing message blocked, got %v", err)
+	}
+}
+
+func TestMessagePolicy_QueryMessages(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: mallory and $prompt"`)
+	client.config.MessagePolicy = wordPolicy{}
+
+	result, err := client.QueryMessagesSync(context.Background(), "hello alice", nil)
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+	if result.Messages[0].Content != "hello [name]" {
+		t.Errorf("Expected the prompt redacted, got %q", result.Messages[0].Content)
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "mallory is not allowed") {
+		t.Errorf("Expected the response blocked, got %+v", result.Messages)
+	}
+	for _, message := range result.Messages {
+		if message.Role == types.RoleAssistant && strings.Contains(message.Content, "mallory") {
+			t.Errorf("Expected the blocked response withheld, got %q", message.Content)
+		}
+	}
+
+	if _, err := client.QueryMessages(context.Background(), "ask mallory", nil); !errors.Is(err, errMallory) {
+		t.Errorf("Expected the outgoing prompt blocked, got %v", err)
+	}
+}
+
+func TestMessagePolicy_Stream(t *testing.T) {
+	stream := &policyStream{
+		QueryStream: &chunkStream{chunks: []*types.StreamChunk{
+			{Type: types.ChunkTypeContent, Content: "hi bob"},
+			{Type: types.ChunkTypeContent, Content: "mallory"},
+		}},
+		ctx:    context.Background(),
+		client: newFakeCLIClient(t, `exit 0`),
+	}
+	stream.client.config.MessagePolicy = wordPolicy{}
+
+	chunk, err := stream.Recv()
+	if err != nil || chunk.Content != "hi bob" || !reflect.DeepEqual(chunk.Metadata[policyTagsKey], []string{"bob"}) {
+		t.Errorf("Expected a tagged chunk, got %+v %v", chunk, err)
+	}
+	if _, err := stream.Recv(); !errors.Is(err, errMallory) {
+		t.Errorf("Expected the chunk blocked, got %v", err)
+	}
+}
+
+// chunkStream replays fixed chunks.
+type chunkStream struct {
+	chunks []*types.StreamChunk
+}
+
+func (s *chunkStream) Recv() (*types.StreamChunk, error) {
+	if len(s.chunks) == 0 {
+		return &types.StreamChunk{Done: true}, nil
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *chunkStream) Close() error {
+	return nil
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// PinContext pins text into the system prompt of every query the client
+// starts from now on, replacing the snippet with the same name. The snippets
+// are sent in pinning order after the query's own system prompt, and their
+// estimated size counts against PinnedContextMaxTokens; a snippet that would
+// exceed the budget is rejected with a ValidationError.
+func (c *ClaudeCodeClient) PinContext(name, content string) error {
+	return c.pin(types.PinnedSnippet{Name: name, Content: content})
+}
+
+// PinFile pins the contents of a file, named by its path relative to the
+// working directory, as PinContext does. The file is read once; pin it again
+// to pick up later edits.
+func (c *ClaudeCodeClient) PinFile(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return sdkerrors.NewValidationError("path", path, "required", "file path cannot be empty")
+	}
+
+	workingDir := c.currentWorkingDir()
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workingDir, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return sdkerrors.NewValidationError("path", path, "exists", "file does not exist")
+	}
+	if info.IsDir() {
+		return sdkerrors.NewValidationError("path", path, "file", "path is a directory, not a file")
+	}
+
+	content, err := os.ReadFile(path) // #nosec G304 - the caller chose the file to pin
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PIN_FILE", "failed to read pinned file")
+	}
+
+	name := displayManifestPath(workingDir, path)
+	return c.pin(types.PinnedSnippet{Name: name, Content: string(content), Source: name})
+}
+
+// pin checks a snippet against the budget and stores it.
+func (c *ClaudeCodeClient) pin(snippet types.PinnedSnippet) error {
+	if strings.TrimSpace(snippet.Name) == "" {
+		return sdkerrors.NewValidationError("name", snippet.Name, "required", "pinned context name cannot be empty")
+	}
+	if strings.TrimSpace(snippet.Content) == "" {
+		return sdkerrors.NewValidationError("content", snippet.Name, "required", "pinned context cannot be empty")
+	}
+
+	config := c.settings()
+	snippet.Tokens = CountTokens(config.Model, renderPinnedSnippet(snippet))
+	snippet.PinnedAt = time.Now()
+	limit := pinnedContextLimit(config)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	used := snippet.Tokens
+	pins := make([]types.PinnedSnippet, 0, len(c.pins)+1)
+	replaced := false
+	for _, pinned := range c.pins {
+		if pinned.Name == snippet.Name {
+			pins = append(pins, snippet)
+			replaced = true
+			continue
+		}
+		used += pinned.Tokens
+		pins = append(pins, pinned)
+	}
+	if !replaced {
+		pins = append(pins, snippet)
+	}
+
+	if used > limit {
+		return sdkerrors.NewValidationError("content", snippet.Name, fmt.Sprintf("max %d tokens", limit),
+			fmt.Sprintf("pinned context would use about %d tokens, over the budget of %d; unpin something or raise PinnedContextMaxTokens", used, limit))
+	}
+	c.pins = pins
+	return nil
+}
+
+// Unpin removes the pinned snippet with the given name and reports whether
+// there was one.
+func (c *ClaudeCodeClient) Unpin(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, pinned := range c.pins {
+		if pinned.Name == name {
+			c.pins = append(append([]types.PinnedSnippet(nil), c.pins[:i]...), c.pins[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// PinnedContext returns the pinned snippets in the order they are sent.
+func (c *ClaudeCodeClient) PinnedContext() []types.PinnedSnippet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]types.PinnedSnippet(nil), c.pins...)
+}
+
+// PinnedContextTokens returns the estimated tokens used by the pinned
+// snippets and the budget they count against.
+func (c *ClaudeCodeClient) PinnedContextTokens() (used, limit int) {
+	limit = pinnedContextLimit(c.settings())
+	for _, pinned := range c.PinnedContext() {
+		used += pinned.Tokens
+	}
+	return used, limit
+}
+
+// pinnedContextLimit returns the configured pinned context budget.
+func pinnedContextLimit(config *types.ClaudeCodeConfig) int {
+	if config.PinnedContextMaxTokens > 0 {
+		return config.PinnedContextMaxTokens
+	}
+	return types.DefaultPinnedContextMaxTokens
+}
+
+// renderPinnedSnippet formats a snippet for the system prompt.
+func renderPinnedSnippet(snippet types.PinnedSnippet) string {
+	attributes := fmt.Sprintf("name=%q", snippet.Name)
+	if snippet.Source != "" {
+		attributes += fmt.Sprintf(" source=%q", snippet.Source)
+	}
+	return "<pinned_context " + attributes + ">\n" + strings.TrimRight(snippet.Content, "\n") + "\n</pinned_context>"
+}
+
+// systemPrompt appends the pinned snippets to a query's system prompt.
+func (c *ClaudeCodeClient) systemPrompt(system string) string {
+	parts := make([]string, 0, 1)
+	if system != "" {
+		parts = append(parts, system)
+	}
+	for _, pinned := range c.PinnedContext() {
+		parts = append(parts, renderPinnedSnippet(pinned))
+	}
+	return strings.Join(parts, "\n\n")
+}
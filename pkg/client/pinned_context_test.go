@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestPinContext(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: $*"`)
+	if err := os.WriteFile(filepath.Join(client.currentWorkingDir(), "STYLE.md"), []byte("Wrap errors with %w.\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if err := client.PinContext("glossary", "A widget is a unit of work."); err != nil {
+		t.Fatalf("PinContext failed: %v", err)
+	}
+	if err := client.PinFile("STYLE.md"); err != nil {
+		t.Fatalf("PinFile failed: %v", err)
+	}
+
+	result, err := client.QueryMessagesSync(context.Background(), "hello", &QueryOptions{SystemPrompt: "Be brief."})
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+	output := result.Messages[len(result.Messages)-1].Content
+	// The fake CLI's output loses the blank lines between the parts
+	want := "--append-system-prompt Be brief.\n<pinned_context name=\"glossary\">\nA widget is a unit of work.\n</pinned_context>\n" +
+		"<pinned_context name=\"STYLE.md\" source=\"STYLE.md\">\nWrap errors with %w.\n</pinned_context> hello"
+	if !strings.Contains(output, want) {
+		t.Errorf("Expected pinned context in CLI args, got: %s", output)
+	}
+
+	// Re-pinning replaces in place; unpinning removes
+	if err := client.PinContext("glossary", "A gadget is a widget."); err != nil {
+		t.Fatalf("PinContext failed: %v", err)
+	}
+	pins := client.PinnedContext()
+	if len(pins) != 2 || pins[0].Content != "A gadget is a widget." || pins[1].Source != "STYLE.md" {
+		t.Errorf("Expected the glossary replaced in place, got %+v", pins)
+	}
+	if !client.Unpin("STYLE.md") || client.Unpin("STYLE.md") {
+		t.Error("Expected Unpin to remove the file once")
+	}
+
+	used, limit := client.PinnedContextTokens()
+	if used != pins[0].Tokens || used == 0 || limit != types.DefaultPinnedContextMaxTokens {
+		t.Errorf("Expected %d of %d tokens used, got %d of %d", pins[0].Tokens, types.DefaultPinnedContextMaxTokens, used, limit)
+	}
+
+	count, err := client.CountTokens(context.Background(), &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("CountTokens failed: %v", err)
+	}
+	if count.InputTokens < used {
+		t.Errorf("Expected the token count to include %d pinned tokens, got %d", used, count.InputTokens)
+	}
+}
+
+func TestPinContext_Budget(t *testing.T) {
+	client := newFakeCLIClient(t, `echo`)
+	client.config.PinnedContextMaxTokens = 40
+
+	if err := client.PinContext("small", "short note"); err != nil {
+		t.Fatalf("PinContext failed: %v", err)
+	}
+	if err := client.PinContext("large", strings.Repeat("word ", 50)); err == nil {
+		t.Error("Expected a snippet over the budget to be rejected")
+	}
+	if pins := client.PinnedContext(); len(pins) != 1 {
+		t.Errorf("Expected the rejected snippet not to be pinned, got %+v", pins)
+	}
+
+	for _, tt := range []struct{ name, content string }{{"", "text"}, {"empty", " "}} {
+		if err := client.PinContext(tt.name, tt.content); err == nil {
+			t.Errorf("Expected PinContext(%q, %q) to fail", tt.name, tt.content)
+		}
+	}
+	if err := client.PinFile("missing.md"); err == nil {
+		t.Error("Expected a missing file to be rejected")
+	}
+	if err := client.PinFile("."); err == nil {
+		t.Error("Expected a directory to be rejected")
+	}
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// RegisterProfile adds or replaces a named profile at runtime.
+func (c *ClaudeCodeClient) RegisterProfile(name string, profile *types.Profile) error {
+	if name == "" {
+		return sdkerrors.NewValidationError("name", name, "non-empty", "profile name cannot be empty")
+	}
+	if err := profile.Validate(name); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "INVALID_PROFILE", "invalid profile "+name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Replace the map rather than adding to it, since snapshots of the
+	// configuration may still be reading it
+	profiles := make(map[string]*types.Profile, len(c.config.Profiles)+1)
+	for existing, p := range c.config.Profiles {
+		profiles[existing] = p
+	}
+	profiles[name] = profile
+
+	c.updateSettings(func(config *types.ClaudeCodeConfig) {
+		config.Profiles = profiles
+	})
+	return nil
+}
+
+// SetProfile switches the profile applied to queries that do not select one.
+// An empty name clears the default.
+func (c *ClaudeCodeClient) SetProfile(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name != "" && c.config.Profiles[name] == nil {
+		return sdkerrors.NewValidationError("profile", name, "registered profile", "unknown profile")
+	}
+	c.updateSettings(func(config *types.ClaudeCodeConfig) {
+		config.DefaultProfile = name
+	})
+	return nil
+}
+
+// ActiveProfile returns the name of the default profile, or "" if none is set.
+func (c *ClaudeCodeClient) ActiveProfile() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.config.DefaultProfile
+}
+
+// Profiles returns the names of the registered profiles, sorted.
+func (c *ClaudeCodeClient) Profiles() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.config.Profiles))
+	for name := range c.config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile resolves the profile selected by options (or the client's
+// default) into a copy of options. Settings made explicitly on options win
+// over the profile; when options is nil the profile replaces the defaults.
+func (c *ClaudeCodeClient) applyProfile(options *QueryOptions) (*QueryOptions, error) {
+	c.mu.RLock()
+	name := c.config.DefaultProfile
+	if options != nil && options.Profile != "" {
+		name = options.Profile
+	}
+	profile := c.config.Profiles[name]
+	c.mu.RUnlock()
+
+	if name == "" {
+		return options, nil
+	}
+	if profile == nil {
+		return nil, sdkerrors.NewValidationError("profile", name, "registered profile", "unknown profile")
+	}
+
+	resolved := QueryOptions{Stream: true}
+	if options != nil {
+		resolved = *options
+	}
+	resolved.Profile = name
+
+	if resolved.Model == "" {
+		resolved.Model = profile.Model
+	}
+	if resolved.PermissionMode == "" {
+		resolved.PermissionMode = PermissionMode(profile.PermissionMode)
+	}
+	if len(resolved.AllowedTools) == 0 {
+		resolved.AllowedTools = profile.AllowedTools
+	}
+	if resolved.SystemPrompt == "" {
+		resolved.SystemPrompt = profile.SystemPrompt
+	}
+	if resolved.MaxTurns == 0 {
+		resolved.MaxTurns = profile.MaxTurns
+	}
+
+	if len(profile.ToolTimeouts) > 0 {
+		timeouts := make(map[string]time.Duration, len(profile.ToolTimeouts)+len(resolved.ToolTimeouts))
+		for tool, timeout := range profile.ToolTimeouts {
+			timeouts[tool] = timeout
+		}
+		for tool, timeout := range resolved.ToolTimeouts {
+			timeouts[tool] = timeout
+		}
+		resolved.ToolTimeouts = timeouts
+	}
+
+	if len(profile.ExtraArgs) > 0 {
+		extra := make(map[string]*string, len(profile.ExtraArgs)+len(resolved.ExtraArgs))
+		for flag, value := range profile.ExtraArgs {
+			extra[strings.TrimLeft(flag, "-")] = value
+		}
+		for flag, value := range resolved.ExtraArgs {
+			extra[strings.TrimLeft(flag, "-")] = value
+		}
+		resolved.ExtraArgs = extra
+	}
+
+	if options == nil {
+		if resolved.MaxTurns == 0 {
+			resolved.MaxTurns = 10
+		}
+		if resolved.PermissionMode == "" {
+			resolved.PermissionMode = PermissionModeAsk
+		}
+	}
+
+	return &resolved, nil
+}
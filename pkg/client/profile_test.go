@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestApplyProfile(t *testing.T) {
+	client := &ClaudeCodeClient{config: &types.ClaudeCodeConfig{
+		Profiles: map[string]*types.Profile{
+			"ci": {
+				Model:          "claude-3-5-haiku-20241022",
+				PermissionMode: "rejectEdits",
+				AllowedTools:   []string{"Read"},
+				ToolTimeouts:   map[string]time.Duration{"Bash": time.Second, "Read": time.Second},
+			},
+			"deep-review": {Model: "claude-opus-4", MaxTurns: 40},
+		},
+	}}
+
+	// No profile leaves options untouched
+	if options, err := client.applyProfile(nil); err != nil || options != nil {
+		t.Fatalf("applyProfile(nil) = %+v, %v", options, err)
+	}
+
+	if err := client.SetProfile("ci"); err != nil {
+		t.Fatalf("SetProfile failed: %v", err)
+	}
+
+	options, err := client.applyProfile(nil)
+	if err != nil {
+		t.Fatalf("applyProfile failed: %v", err)
+	}
+	if options.Model != "claude-3-5-haiku-20241022" || options.PermissionMode != PermissionModeRejectEdits || options.MaxTurns != 10 || !options.Stream {
+		t.Errorf("Unexpected default profile options: %+v", options)
+	}
+
+	// Explicit options win over the profile
+	explicit := &QueryOptions{Model: "claude-sonnet-4", ToolTimeouts: map[string]time.Duration{"Bash": time.Minute}}
+	options, err = client.applyProfile(explicit)
+	if err != nil {
+		t.Fatalf("applyProfile failed: %v", err)
+	}
+	if options.Model != "claude-sonnet-4" || !reflect.DeepEqual(options.AllowedTools, []string{"Read"}) {
+		t.Errorf("Unexpected merged options: %+v", options)
+	}
+	if options.ToolTimeouts["Bash"] != time.Minute || options.ToolTimeouts["Read"] != time.Second {
+		t.Errorf("Unexpected tool timeouts: %v", options.ToolTimeouts)
+	}
+	if explicit.Profile != "" || len(explicit.AllowedTools) != 0 {
+		t.Error("Expected caller options not to be modified")
+	}
+
+	// Per-query selection overrides the default
+	options, _ = client.applyProfile(&QueryOptions{Profile: "deep-review"})
+	if options.Model != "claude-opus-4" || options.MaxTurns != 40 {
+		t.Errorf("Unexpected per-query profile: %+v", options)
+	}
+
+	if _, err := client.applyProfile(&QueryOptions{Profile: "missing"}); err == nil {
+		t.Error("Expected unknown profile error")
+	}
+	if err := client.SetProfile("missing"); err == nil {
+		t.Error("Expected SetProfile to reject unknown profiles")
+	}
+}
+
+func TestRegisterProfile(t *testing.T) {
+	client := &ClaudeCodeClient{config: &types.ClaudeCodeConfig{}}
+
+	if err := client.RegisterProfile("fast-cheap", &types.Profile{Model: "claude-3-5-haiku-20241022"}); err != nil {
+		t.Fatalf("RegisterProfile failed: %v", err)
+	}
+	if err := client.RegisterProfile("bad", &types.Profile{PermissionMode: "yolo"}); err == nil {
+		t.Error("Expected invalid profile to be rejected")
+	}
+	if err := client.RegisterProfile("", &types.Profile{}); err == nil {
+		t.Error("Expected empty profile name to be rejected")
+	}
+
+	if names := client.Profiles(); !reflect.DeepEqual(names, []string{"fast-cheap"}) {
+		t.Errorf("Profiles() = %v", names)
+	}
+	if err := client.SetProfile("fast-cheap"); err != nil || client.ActiveProfile() != "fast-cheap" {
+		t.Errorf("SetProfile failed: %v (active %q)", err, client.ActiveProfile())
+	}
+}
+
+func TestQueryMessagesWithProfile(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: $*"`)
+	if err := client.RegisterProfile("ci", &types.Profile{
+		Model:          "claude-3-5-haiku-20241022",
+		PermissionMode: "acceptEdits",
+		AllowedTools:   []string{"Read", "Grep"},
+	}); err != nil {
+		t.Fatalf("RegisterProfile failed: %v", err)
+	}
+
+	result, err := client.QueryMessagesSync(context.Background(), "hi", &QueryOptions{Profile: "ci"})
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+
+	output := result.Messages[len(result.Messages)-1].Content
+	for _, want := range []string{"--model claude-3-5-haiku-20241022", "--permission-mode acceptEdits", "--allowedTools Read,Grep"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in CLI args: %s", want, output)
+		}
+	}
+}
@@ -10,41 +10,52 @@ import (
 	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
 )
 
-// ProjectContextManager provides basic project context management for Claude Code integration.
-// Simplified to match official SDK scope - only provides working directory information.
+// ProjectContextManager provides project context management for Claude Code integration.
+// It enriches the client's project context with dependency, git, workspace, and
+// (optionally) Go symbol information and caches the result until a file it was built from
+// changes.
 type ProjectContextManager struct {
 	client          *ClaudeCodeClient
 	cachedContext   *types.ProjectContext
+	cachedDir       string
+	watch           *projectWatch
 	lastCacheUpdate time.Time
 	cacheDuration   time.Duration
+	symbolIndexing  bool
+	detectors       []Detector
+	focus           string
+	focusRoot       string
 	mu              sync.RWMutex
 }
 
 // NewProjectContextManager creates a new project context manager.
 func NewProjectContextManager(client *ClaudeCodeClient) *ProjectContextManager {
-	return &ProjectContextManager{
-		client:        client,
-		cacheDuration: 5 * time.Minute, // Cache for 5 minutes
-	}
+	return &ProjectContextManager{client: client}
 }
 
-// GetEnhancedProjectContext returns basic project context.
-// Simplified to match official SDK scope - no complex analysis.
+// GetEnhancedProjectContext returns the project context enriched with the
+// dependency graph, git metadata, detected technologies, monorepo workspace,
+// and the Go symbol index if enabled. When a package is focused with
+// FocusPackage, the analysis covers that package only. The result is cached
+// until one of the manifests, key directories, git state or indexed sources
+// it was built from changes, which a file watcher reports, or until the
+// cache duration, if set, expires.
 func (pm *ProjectContextManager) GetEnhancedProjectContext(ctx context.Context) (*types.ProjectContext, error) {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
-	// Check if cached context is still valid
-	if pm.cachedContext != nil && time.Since(pm.lastCacheUpdate) < pm.cacheDuration {
-		return pm.cachedContext, nil
-	}
-
 	// Get base context from client
 	baseContext, err := pm.client.GetProjectContext(ctx)
 	if err != nil {
 		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "BASE_CONTEXT", "failed to get base project context")
 	}
 
+	// Check if cached context is still valid and describes the same directory
+	if pm.cacheValid() && pm.cachedDir == baseContext.WorkingDirectory {
+		return pm.cachedContext, nil
+	}
+	cachedDir := baseContext.WorkingDirectory
+
 	// Ensure working directory is absolute
 	if baseContext.WorkingDirectory != "" {
 		if absPath, err := filepath.Abs(baseContext.WorkingDirectory); err == nil {
@@ -52,14 +63,132 @@ func (pm *ProjectContextManager) GetEnhancedProjectContext(ctx context.Context)
 		}
 	}
 
+	if baseContext.WorkingDirectory != "" {
+		root := baseContext.WorkingDirectory
+		baseContext.Workspace = detectWorkspace(root)
+
+		// A focus set for another working directory no longer applies
+		if pm.focus != "" && pm.focusRoot == root {
+			dir, rel, err := resolveWorkspacePackage(root, baseContext.Workspace, pm.focus)
+			if err != nil {
+				return nil, err
+			}
+			baseContext.WorkingDirectory, baseContext.Focus = dir, rel
+		}
+
+		if err := pm.analyze(ctx, baseContext); err != nil {
+			return nil, err
+		}
+	}
+
 	// Cache the context
 	pm.cachedContext = baseContext
+	pm.cachedDir = cachedDir
+	if pm.watch != nil {
+		pm.watch.stop()
+	}
+	pm.watch = watchProject(baseContext)
 	pm.lastCacheUpdate = time.Now()
 
 	return baseContext, nil
 }
 
-// InvalidateCache invalidates the cached project context.
+// PackageContext returns the enhanced context of one package of the
+// workspace, given its name or its path relative to the working directory,
+// as GetEnhancedProjectContext does while the package is focused. The result
+// is not cached.
+func (pm *ProjectContextManager) PackageContext(ctx context.Context, nameOrPath string) (*types.ProjectContext, error) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	baseContext, err := pm.client.GetProjectContext(ctx)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "BASE_CONTEXT", "failed to get base project context")
+	}
+	root, err := filepath.Abs(baseContext.WorkingDirectory)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "PATH_ABS", "failed to convert to absolute path")
+	}
+
+	baseContext.Workspace = detectWorkspace(root)
+	dir, rel, err := resolveWorkspacePackage(root, baseContext.Workspace, nameOrPath)
+	if err != nil {
+		return nil, err
+	}
+	baseContext.WorkingDirectory, baseContext.Focus = dir, rel
+
+	if err := pm.analyze(ctx, baseContext); err != nil {
+		return nil, err
+	}
+	return baseContext, nil
+}
+
+// analyze fills in the analysis fields of a context whose WorkingDirectory
+// is set. The caller holds pm.mu.
+func (pm *ProjectContextManager) analyze(ctx context.Context, projectCtx *types.ProjectContext) error {
+	// Extract the dependency graph from manifests in all workspace roots
+	roots := append([]string{projectCtx.WorkingDirectory}, projectCtx.AdditionalDirectories...)
+	projectCtx.Dependencies = analyzeDependencies(projectCtx.WorkingDirectory, roots)
+	projectCtx.Git = collectGitInfo(ctx, projectCtx.WorkingDirectory)
+
+	if pm.symbolIndexing {
+		index, err := BuildSymbolIndex(projectCtx.WorkingDirectory)
+		if err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SYMBOL_INDEX", "failed to build symbol index")
+		}
+		projectCtx.Symbols = index
+	}
+
+	projectCtx.Stack = detectStack(ctx, projectCtx, pm.allDetectors())
+	return nil
+}
+
+// FocusPackage narrows the enhanced project context to one package of a
+// monorepo, given its workspace package name or its path relative to the
+// working directory, so that its dependencies, symbols and stack are not
+// mixed with the rest of the repository. The Workspace field still lists
+// every package. Pass "" to analyze the whole working directory again. The
+// focus only applies while the working directory is the one it was set in.
+func (pm *ProjectContextManager) FocusPackage(nameOrPath string) error {
+	root := ""
+	if nameOrPath != "" {
+		var err error
+		if root, err = filepath.Abs(pm.client.currentWorkingDir()); err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "PATH_ABS", "failed to convert to absolute path")
+		}
+		if _, _, err := resolveWorkspacePackage(root, detectWorkspace(root), nameOrPath); err != nil {
+			return err
+		}
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.focus, pm.focusRoot = nameOrPath, root
+	pm.cachedContext = nil
+	pm.lastCacheUpdate = time.Time{}
+	return nil
+}
+
+// Focus returns the package set with FocusPackage, or "".
+func (pm *ProjectContextManager) Focus() string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.focus
+}
+
+// cacheValid reports whether the cached context is still fresh. The caller
+// holds pm.mu.
+func (pm *ProjectContextManager) cacheValid() bool {
+	if pm.cachedContext == nil || pm.watch.changed() {
+		return false
+	}
+	return pm.cacheDuration <= 0 || time.Since(pm.lastCacheUpdate) < pm.cacheDuration
+}
+
+// InvalidateCache invalidates the cached project context. It is only needed
+// for changes the cache does not watch, such as edits to files that are not
+// manifests or indexed Go sources.
 func (pm *ProjectContextManager) InvalidateCache() {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
@@ -68,7 +197,18 @@ func (pm *ProjectContextManager) InvalidateCache() {
 	pm.lastCacheUpdate = time.Time{}
 }
 
-// SetCacheDuration sets the cache duration for project context.
+// stopWatching stops watching the files of the cached context.
+func (pm *ProjectContextManager) stopWatching() {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if pm.watch != nil {
+		pm.watch.stop()
+	}
+}
+
+// SetCacheDuration caps how long the project context is cached even when no
+// watched file changes. Zero, the default, caches it until a change.
 func (pm *ProjectContextManager) SetCacheDuration(duration time.Duration) {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
@@ -76,6 +216,47 @@ func (pm *ProjectContextManager) SetCacheDuration(duration time.Duration) {
 	pm.cacheDuration = duration
 }
 
+// SetSymbolIndexing enables or disables building the Go symbol index for the
+// enhanced project context. Indexing is disabled by default because it parses
+// every Go file in the working directory.
+func (pm *ProjectContextManager) SetSymbolIndexing(enabled bool) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if pm.symbolIndexing != enabled {
+		pm.symbolIndexing = enabled
+		pm.cachedContext = nil
+		pm.lastCacheUpdate = time.Time{}
+	}
+}
+
+// RegisterDetector adds a detector to this manager only, replacing a detector
+// with the same name, whether registered globally or here. The cached context
+// is dropped so that the next call runs it.
+func (pm *ProjectContextManager) RegisterDetector(detector Detector) error {
+	if err := validateDetector(detector); err != nil {
+		return err
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.detectors = withDetector(pm.detectors, detector)
+	pm.cachedContext = nil
+	pm.lastCacheUpdate = time.Time{}
+	return nil
+}
+
+// allDetectors returns the global detectors followed by the manager's own,
+// which replace global detectors of the same name. The caller holds pm.mu.
+func (pm *ProjectContextManager) allDetectors() []Detector {
+	all := Detectors()
+	for _, detector := range pm.detectors {
+		all = withDetector(all, detector)
+	}
+	return all
+}
+
 // GetCacheInfo returns information about the cache status.
 func (pm *ProjectContextManager) GetCacheInfo() map[string]any {
 	pm.mu.RLock()
@@ -89,7 +270,8 @@ func (pm *ProjectContextManager) GetCacheInfo() map[string]any {
 
 	if pm.cachedContext != nil {
 		info["last_update"] = pm.lastCacheUpdate.Format(time.RFC3339)
-		info["cache_valid"] = time.Since(pm.lastCacheUpdate) < pm.cacheDuration
+		info["cache_valid"] = pm.cacheValid()
+		info["watched_files"] = pm.watch.size()
 	}
 
 	return info
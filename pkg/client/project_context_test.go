@@ -206,3 +206,76 @@ func TestProjectContextManager_AbsolutePath(t *testing.T) {
 		t.Errorf("Expected absolute directory %s, got %s", absDir, projectContext.WorkingDirectory)
 	}
 }
+
+func TestProjectContextManager_WatchedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	writeFile("go.mod", "module example.com/app\n\ngo 1.20\n")
+	writeFile("main.go", "package main\n\nfunc main() {}\n")
+
+	config := types.NewClaudeCodeConfig()
+	config.WorkingDirectory = dir
+	config.TestMode = true
+	client, err := NewClaudeCodeClient(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	manager := NewProjectContextManager(client)
+	defer manager.stopWatching()
+	manager.SetSymbolIndexing(true)
+	ctx := context.Background()
+
+	first, err := manager.GetEnhancedProjectContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get enhanced project context: %v", err)
+	}
+	if again, _ := manager.GetEnhancedProjectContext(ctx); again != first {
+		t.Error("Expected an unchanged project to be served from the cache")
+	}
+	if info := manager.GetCacheInfo(); info["watched_files"].(int) == 0 || !info["cache_valid"].(bool) {
+		t.Errorf("Expected a valid cache with watched files, got %v", info)
+	}
+
+	tests := []struct {
+		name   string
+		change func()
+	}{
+		{"edit go.mod", func() {
+			writeFile("go.mod", "module example.com/app\n\ngo 1.20\n\nrequire github.com/google/uuid v1.6.0\n")
+		}},
+		{"add package.json", func() { writeFile("package.json", `{"name": "app"}`) }},
+		{"edit Go source", func() { writeFile("main.go", "package main\n\nfunc Run() {}\n\nfunc main() {}\n") }},
+	}
+
+	previous := first
+	for _, tt := range tests {
+		tt.change()
+		// The watcher reports the change asynchronously
+		deadline := time.Now().Add(5 * time.Second)
+		for manager.GetCacheInfo()["cache_valid"].(bool) && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if info := manager.GetCacheInfo(); info["cache_valid"].(bool) {
+			t.Errorf("%s: expected the cache to be stale", tt.name)
+		}
+		next, err := manager.GetEnhancedProjectContext(ctx)
+		if err != nil {
+			t.Fatalf("%s: failed to get enhanced project context: %v", tt.name, err)
+		}
+		if next == previous {
+			t.Errorf("%s: expected the project context to be rebuilt", tt.name)
+		}
+		previous = next
+	}
+
+	if previous.Symbols == nil || len(previous.Symbols.Symbols) == 0 || previous.Symbols.Symbols[0].Name != "Run" {
+		t.Errorf("Expected the rebuilt index to contain Run, got %+v", previous.Symbols)
+	}
+}
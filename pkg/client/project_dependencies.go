@@ -0,0 +1,608 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Manifest and lock file names recognized by the dependency analyzer.
+const (
+	goModFile         = "go.mod"
+	packageJSONFile   = "package.json"
+	packageLockFile   = "package-lock.json"
+	pyprojectFile     = "pyproject.toml"
+	poetryLockFile    = "poetry.lock"
+	uvLockFile        = "uv.lock"
+	maxLicenseFileLen = 16 * 1024
+)
+
+// analyzeDependencies parses the manifests found in each root and builds a
+// dependency graph. Manifests that cannot be parsed are recorded in the graph's
+// Errors instead of failing the whole analysis. It returns nil if no manifest
+// was found.
+func analyzeDependencies(workingDir string, roots []string) *types.DependencyGraph {
+	graph := &types.DependencyGraph{}
+
+	for _, root := range roots {
+		parsers := []struct {
+			file  string
+			parse func(path string) (*types.DependencyManifest, []types.Dependency, error)
+		}{
+			{goModFile, parseGoModDependencies},
+			{packageJSONFile, parseNPMDependencies},
+			{pyprojectFile, parsePythonDependencies},
+		}
+
+		for _, parser := range parsers {
+			path := filepath.Join(root, parser.file)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+
+			manifest, deps, err := parser.parse(path)
+			displayPath := displayManifestPath(workingDir, path)
+			if err != nil {
+				graph.Errors = append(graph.Errors, fmt.Sprintf("%s: %v", displayPath, err))
+				continue
+			}
+
+			manifest.Path = displayPath
+			graph.Manifests = append(graph.Manifests, *manifest)
+			for i := range deps {
+				deps[i].Manifest = displayPath
+			}
+			graph.Dependencies = append(graph.Dependencies, deps...)
+		}
+	}
+
+	if len(graph.Manifests) == 0 && len(graph.Errors) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(graph.Dependencies, func(i, j int) bool {
+		a, b := graph.Dependencies[i], graph.Dependencies[j]
+		if a.Manifest != b.Manifest {
+			return a.Manifest < b.Manifest
+		}
+		if a.IsDirect() != b.IsDirect() {
+			return a.IsDirect()
+		}
+		return a.Name < b.Name
+	})
+
+	return graph
+}
+
+// displayManifestPath returns the manifest path relative to the working directory when possible.
+func displayManifestPath(workingDir, path string) string {
+	if rel, err := filepath.Rel(workingDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// parseGoModDependencies parses a go.mod file. Licenses are looked up in the
+// local module cache when the module has been downloaded.
+func parseGoModDependencies(path string) (*types.DependencyManifest, []types.Dependency, error) {
+	file, err := os.Open(path) // #nosec G304 - path is a manifest inside a configured workspace root
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	manifest := &types.DependencyManifest{Ecosystem: types.EcosystemGo}
+	var deps []types.Dependency
+
+	addRequire := func(line string) {
+		indirect := false
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			indirect = strings.Contains(line[idx:], "indirect")
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return
+		}
+		fields[0] = strings.Trim(fields[0], `"`)
+		kind := types.DependencyDirect
+		if indirect {
+			kind = types.DependencyIndirect
+		}
+		deps = append(deps, types.Dependency{
+			Name:       fields[0],
+			Version:    fields[1],
+			Constraint: fields[1],
+			Ecosystem:  types.EcosystemGo,
+			Kind:       kind,
+			License:    goModuleLicense(fields[0], fields[1]),
+		})
+	}
+
+	// go.mod is not TOML: it is a line-based format whose comments, such as
+	// "// indirect", carry meaning
+	inRequire := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		directive := line
+		if idx := strings.Index(directive, "//"); idx >= 0 {
+			directive = strings.TrimSpace(directive[:idx])
+		}
+
+		switch {
+		case inRequire && directive == ")":
+			inRequire = false
+		case inRequire:
+			if directive != "" {
+				addRequire(line)
+			}
+		case strings.HasPrefix(directive, "module "):
+			manifest.Project = strings.Trim(strings.TrimSpace(strings.TrimPrefix(directive, "module ")), `"`)
+		case strings.HasPrefix(directive, "require") && strings.TrimSpace(strings.TrimPrefix(directive, "require")) == "(":
+			inRequire = true
+		case strings.HasPrefix(directive, "require "):
+			addRequire(strings.TrimPrefix(line, "require "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if manifest.Project == "" {
+		return nil, nil, fmt.Errorf("missing module directive")
+	}
+
+	manifest.License = detectLicenseInDir(filepath.Dir(path))
+	return manifest, deps, nil
+}
+
+// goModuleLicense looks up the license of a downloaded module in the module cache.
+func goModuleLicense(modulePath, version string) string {
+	modCache := os.Getenv("GOMODCACHE")
+	if modCache == "" {
+		gopath := os.Getenv("GOPATH")
+		if gopath == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return ""
+			}
+			gopath = filepath.Join(home, "go")
+		}
+		modCache = filepath.Join(strings.Split(gopath, string(os.PathListSeparator))[0], "pkg", "mod")
+	}
+
+	dir := filepath.Join(modCache, filepath.FromSlash(escapeModulePath(modulePath))+"@"+version)
+	return detectLicenseInDir(dir)
+}
+
+// escapeModulePath applies the module cache case encoding ("A" becomes "!a").
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('!')
+			b.WriteRune(r + ('a' - 'A'))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// npmPackageJSON is the subset of package.json used for dependency analysis.
+type npmPackageJSON struct {
+	Name                 string            `json:"name"`
+	License              json.RawMessage   `json:"license"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// npmLockPackage is a package entry in package-lock.json.
+type npmLockPackage struct {
+	Version      string            `json:"version"`
+	License      json.RawMessage   `json:"license"`
+	Dependencies map[string]string `json:"dependencies"`
+	Requires     map[string]string `json:"requires"`
+	Dev          bool              `json:"dev"`
+	Optional     bool              `json:"optional"`
+}
+
+// npmLockFile is the subset of package-lock.json used for dependency analysis.
+type npmLockFile struct {
+	Packages     map[string]npmLockPackage `json:"packages"`
+	Dependencies map[string]npmLockPackage `json:"dependencies"`
+}
+
+// parseNPMDependencies parses package.json and, when present, package-lock.json
+// for resolved versions, licenses, and transitive dependencies.
+func parseNPMDependencies(path string) (*types.DependencyManifest, []types.Dependency, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is a manifest inside a configured workspace root
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pkg npmPackageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, nil, err
+	}
+
+	manifest := &types.DependencyManifest{
+		Ecosystem: types.EcosystemNPM,
+		Project:   pkg.Name,
+		License:   npmLicense(pkg.License),
+	}
+
+	// Resolved packages from the lock file, keyed by package name
+	resolved := make(map[string]npmLockPackage)
+	if lockData, err := os.ReadFile(filepath.Join(filepath.Dir(path), packageLockFile)); err == nil { // #nosec G304 - lock file next to the manifest
+		var lock npmLockFile
+		if err := json.Unmarshal(lockData, &lock); err == nil {
+			for key, entry := range lock.Packages {
+				if key == "" {
+					continue
+				}
+				name := key[strings.LastIndex(key, "node_modules/")+len("node_modules/"):]
+				// Prefer top-level installs over nested copies
+				if _, exists := resolved[name]; !exists || !strings.Contains(strings.TrimPrefix(key, "node_modules/"), "node_modules/") {
+					resolved[name] = entry
+				}
+			}
+			// Lockfile v1 layout
+			for name, entry := range lock.Dependencies {
+				if _, exists := resolved[name]; !exists {
+					if entry.Dependencies == nil {
+						entry.Dependencies = entry.Requires
+					}
+					resolved[name] = entry
+				}
+			}
+		}
+	}
+
+	var deps []types.Dependency
+	declared := make(map[string]bool)
+	addDeclared := func(entries map[string]string, kind types.DependencyKind) {
+		for name, constraint := range entries {
+			if declared[name] {
+				continue
+			}
+			declared[name] = true
+			dep := types.Dependency{
+				Name:       name,
+				Version:    constraint,
+				Constraint: constraint,
+				Ecosystem:  types.EcosystemNPM,
+				Kind:       kind,
+			}
+			if entry, ok := resolved[name]; ok {
+				dep.Version = entry.Version
+				dep.License = npmLicense(entry.License)
+				dep.Requires = sortedKeys(entry.Dependencies)
+			}
+			deps = append(deps, dep)
+		}
+	}
+	addDeclared(pkg.Dependencies, types.DependencyDirect)
+	addDeclared(pkg.PeerDependencies, types.DependencyDirect)
+	addDeclared(pkg.OptionalDependencies, types.DependencyOptional)
+	addDeclared(pkg.DevDependencies, types.DependencyDev)
+
+	// Everything else in the lock file is transitive
+	for _, name := range sortedKeys(resolved) {
+		if declared[name] {
+			continue
+		}
+		entry := resolved[name]
+		deps = append(deps, types.Dependency{
+			Name:      name,
+			Version:   entry.Version,
+			Ecosystem: types.EcosystemNPM,
+			Kind:      types.DependencyIndirect,
+			License:   npmLicense(entry.License),
+			Requires:  sortedKeys(entry.Dependencies),
+		})
+	}
+
+	return manifest, deps, nil
+}
+
+// npmLicense extracts a license name from the string or object forms used by npm.
+func npmLicense(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var license string
+	if err := json.Unmarshal(raw, &license); err == nil {
+		return license
+	}
+
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &object); err == nil {
+		return object.Type
+	}
+
+	return ""
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var pep508NamePattern = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*(.*)$`)
+
+// pyprojectTOML is the subset of pyproject.toml used for dependency analysis.
+// Poetry dependency values are a version string, a table with a version, or
+// an array of such tables.
+type pyprojectTOML struct {
+	Project struct {
+		Name                 string              `toml:"name"`
+		License              any                 `toml:"license"`
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+	} `toml:"project"`
+	DependencyGroups map[string][]any `toml:"dependency-groups"`
+	Tool             struct {
+		Poetry struct {
+			Name            string         `toml:"name"`
+			License         string         `toml:"license"`
+			Dependencies    map[string]any `toml:"dependencies"`
+			DevDependencies map[string]any `toml:"dev-dependencies"`
+			Group           map[string]struct {
+				Dependencies map[string]any `toml:"dependencies"`
+			} `toml:"group"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+// pythonLockTOML is the subset of poetry.lock and uv.lock used for resolved
+// versions.
+type pythonLockTOML struct {
+	Package []struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+	} `toml:"package"`
+}
+
+// parsePythonDependencies parses PEP 621 and Poetry dependency declarations in
+// pyproject.toml, using poetry.lock or uv.lock for resolved versions.
+func parsePythonDependencies(path string) (*types.DependencyManifest, []types.Dependency, error) {
+	var pyproject pyprojectTOML
+	if _, err := toml.DecodeFile(path, &pyproject); err != nil {
+		return nil, nil, err
+	}
+	project, poetry := pyproject.Project, pyproject.Tool.Poetry
+
+	manifest := &types.DependencyManifest{Ecosystem: types.EcosystemPython, Project: project.Name}
+	if manifest.Project == "" {
+		manifest.Project = poetry.Name
+	}
+	manifest.License = pep621License(project.License)
+	if manifest.License == "" {
+		manifest.License = poetry.License
+	}
+
+	var deps []types.Dependency
+	declared := make(map[string]bool)
+
+	addDep := func(name, constraint string, kind types.DependencyKind) {
+		key := normalizePythonName(name)
+		if key == "" || key == "python" || declared[key] {
+			return
+		}
+		declared[key] = true
+		deps = append(deps, types.Dependency{
+			Name:       name,
+			Version:    constraint,
+			Constraint: constraint,
+			Ecosystem:  types.EcosystemPython,
+			Kind:       kind,
+		})
+	}
+
+	addRequirement := func(requirement string, kind types.DependencyKind) {
+		if match := pep508NamePattern.FindStringSubmatch(requirement); match != nil {
+			constraint := strings.TrimSpace(strings.SplitN(match[3], ";", 2)[0])
+			addDep(match[1], constraint, kind)
+		}
+	}
+
+	addPoetry := func(entries map[string]any, kind types.DependencyKind) {
+		for _, name := range sortedKeys(entries) {
+			addDep(name, poetryConstraint(entries[name]), kind)
+		}
+	}
+
+	// Earlier declarations win when a package is declared more than once
+	for _, requirement := range project.Dependencies {
+		addRequirement(requirement, types.DependencyDirect)
+	}
+	for _, extra := range sortedKeys(project.OptionalDependencies) {
+		for _, requirement := range project.OptionalDependencies[extra] {
+			addRequirement(requirement, types.DependencyOptional)
+		}
+	}
+	for _, group := range sortedKeys(pyproject.DependencyGroups) {
+		// Groups may also hold {include-group = "..."} tables
+		for _, entry := range pyproject.DependencyGroups[group] {
+			if requirement, ok := entry.(string); ok {
+				addRequirement(requirement, types.DependencyDev)
+			}
+		}
+	}
+	addPoetry(poetry.Dependencies, types.DependencyDirect)
+	addPoetry(poetry.DevDependencies, types.DependencyDev)
+	for _, group := range sortedKeys(poetry.Group) {
+		addPoetry(poetry.Group[group].Dependencies, types.DependencyDev)
+	}
+
+	// Resolve versions and transitive dependencies from a lock file
+	locked := parsePythonLock(filepath.Dir(path))
+	for i := range deps {
+		if version, ok := locked[normalizePythonName(deps[i].Name)]; ok {
+			deps[i].Version = version
+		}
+	}
+	for _, name := range sortedKeys(locked) {
+		if !declared[name] && name != normalizePythonName(manifest.Project) {
+			deps = append(deps, types.Dependency{
+				Name:      name,
+				Version:   locked[name],
+				Ecosystem: types.EcosystemPython,
+				Kind:      types.DependencyIndirect,
+			})
+		}
+	}
+
+	if manifest.License == "" {
+		manifest.License = detectLicenseInDir(filepath.Dir(path))
+	}
+
+	return manifest, deps, nil
+}
+
+// parsePythonLock reads package versions from poetry.lock or uv.lock.
+func parsePythonLock(dir string) map[string]string {
+	locked := make(map[string]string)
+
+	for _, name := range []string{poetryLockFile, uvLockFile} {
+		var lock pythonLockTOML
+		_, err := toml.DecodeFile(filepath.Join(dir, name), &lock)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err == nil {
+			for _, pkg := range lock.Package {
+				if pkg.Name != "" && pkg.Version != "" {
+					locked[normalizePythonName(pkg.Name)] = pkg.Version
+				}
+			}
+		}
+		break
+	}
+
+	return locked
+}
+
+// pep621License returns the license of a PEP 621 project: an SPDX expression
+// string or a {text = "..."} table. License files are left to license
+// detection.
+func pep621License(value any) string {
+	switch license := value.(type) {
+	case string:
+		return license
+	case map[string]any:
+		if text, ok := license["text"].(string); ok {
+			return text
+		}
+	}
+	return ""
+}
+
+// poetryConstraint extracts the version constraint from a Poetry dependency
+// value. Of multiple constraints, the first is used.
+func poetryConstraint(value any) string {
+	switch dep := value.(type) {
+	case string:
+		return dep
+	case map[string]any:
+		if version, ok := dep["version"].(string); ok {
+			return version
+		}
+	case []map[string]any:
+		if len(dep) > 0 {
+			return poetryConstraint(dep[0])
+		}
+	case []any:
+		if len(dep) > 0 {
+			return poetryConstraint(dep[0])
+		}
+	}
+	return ""
+}
+
+// normalizePythonName normalizes a Python package name per PEP 503.
+func normalizePythonName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
+
+// licenseFileNames are the file names checked when detecting a license.
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING", "license", "license.md"}
+
+// detectLicenseInDir identifies the license of the license file in dir, if any.
+func detectLicenseInDir(dir string) string {
+	for _, name := range licenseFileNames {
+		file, err := os.Open(filepath.Join(dir, name)) // #nosec G304 - fixed license file names
+		if err != nil {
+			continue
+		}
+		buf := make([]byte, maxLicenseFileLen)
+		n, _ := file.Read(buf)
+		file.Close()
+		if license := detectLicense(string(buf[:n])); license != "" {
+			return license
+		}
+	}
+	return ""
+}
+
+// detectLicense identifies common licenses from their text and returns an SPDX identifier.
+func detectLicense(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+
+	switch {
+	case strings.Contains(normalized, "apache license") && strings.Contains(normalized, "version 2.0"):
+		return "Apache-2.0"
+	case strings.Contains(normalized, "mozilla public license version 2.0"):
+		return "MPL-2.0"
+	case strings.Contains(normalized, "gnu lesser general public license"):
+		if strings.Contains(normalized, "version 3") {
+			return "LGPL-3.0"
+		}
+		return "LGPL-2.1"
+	case strings.Contains(normalized, "gnu affero general public license"):
+		return "AGPL-3.0"
+	case strings.Contains(normalized, "gnu general public license"):
+		if strings.Contains(normalized, "version 3") {
+			return "GPL-3.0"
+		}
+		return "GPL-2.0"
+	case strings.Contains(normalized, "permission is hereby granted, free of charge"):
+		return "MIT"
+	case strings.Contains(normalized, "permission to use, copy, modify, and/or distribute this software"),
+		strings.Contains(normalized, "isc license"):
+		return "ISC"
+	case strings.Contains(normalized, "redistribution and use in source and binary forms"):
+		if strings.Contains(normalized, "neither the name") {
+			return "BSD-3-Clause"
+		}
+		return "BSD-2-Clause"
+	case strings.Contains(normalized, "this is free and unencumbered software released into the public domain"):
+		return "Unlicense"
+	}
+
+	return ""
+}
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// writeTestFile writes content to name inside dir.
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+func TestParseGoModDependencies(t *testing.T) {
+	dir := t.TempDir()
+	modCache := t.TempDir()
+	t.Setenv("GOMODCACHE", modCache)
+
+	writeTestFile(t, dir, goModFile, `module example.com/app
+
+go 1.20
+
+require github.com/stretchr/testify v1.8.4
+
+require ( // build dependencies
+	// comment line
+	github.com/BurntSushi/toml v1.3.2
+	golang.org/x/sys v0.10.0 // indirect
+)
+`)
+	writeTestFile(t, modCache, "github.com/!burnt!sushi/toml@v1.3.2/COPYING",
+		"The MIT License (MIT)\n\nPermission is hereby granted, free of charge, to any person")
+
+	manifest, deps, err := parseGoModDependencies(filepath.Join(dir, goModFile))
+	if err != nil {
+		t.Fatalf("Failed to parse go.mod: %v", err)
+	}
+
+	if manifest.Project != "example.com/app" {
+		t.Errorf("Expected module example.com/app, got %s", manifest.Project)
+	}
+	if len(deps) != 3 {
+		t.Fatalf("Expected 3 dependencies, got %d: %+v", len(deps), deps)
+	}
+
+	graph := &types.DependencyGraph{Dependencies: deps}
+	toml := graph.Find("github.com/BurntSushi/toml")
+	if toml == nil || toml.Version != "v1.3.2" || toml.Kind != types.DependencyDirect {
+		t.Errorf("Unexpected toml dependency: %+v", toml)
+	} else if toml.License != "MIT" {
+		t.Errorf("Expected MIT license from module cache, got %q", toml.License)
+	}
+	if sys := graph.Find("golang.org/x/sys"); sys == nil || sys.Kind != types.DependencyIndirect {
+		t.Errorf("Expected golang.org/x/sys to be indirect, got %+v", sys)
+	}
+}
+
+func TestParseNPMDependencies(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTestFile(t, dir, packageJSONFile, `{
+  "name": "web",
+  "license": "ISC",
+  "dependencies": {"react": "^18.0.0"},
+  "devDependencies": {"jest": "^29.0.0"}
+}`)
+	writeTestFile(t, dir, packageLockFile, `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "web"},
+    "node_modules/react": {"version": "18.2.0", "license": "MIT", "dependencies": {"loose-envify": "^1.1.0"}},
+    "node_modules/loose-envify": {"version": "1.4.0", "license": "MIT"},
+    "node_modules/jest": {"version": "29.7.0", "license": "MIT", "dev": true}
+  }
+}`)
+
+	manifest, deps, err := parseNPMDependencies(filepath.Join(dir, packageJSONFile))
+	if err != nil {
+		t.Fatalf("Failed to parse package.json: %v", err)
+	}
+
+	if manifest.Project != "web" || manifest.License != "ISC" {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+
+	graph := &types.DependencyGraph{Dependencies: deps}
+	react := graph.Find("react")
+	if react == nil || react.Version != "18.2.0" || react.Constraint != "^18.0.0" || react.License != "MIT" {
+		t.Fatalf("Unexpected react dependency: %+v", react)
+	}
+	if len(react.Requires) != 1 || react.Requires[0] != "loose-envify" {
+		t.Errorf("Expected react to require loose-envify, got %v", react.Requires)
+	}
+	if jest := graph.Find("jest"); jest == nil || jest.Kind != types.DependencyDev {
+		t.Errorf("Expected jest to be a dev dependency, got %+v", jest)
+	}
+	if envify := graph.Find("loose-envify"); envify == nil || envify.Kind != types.DependencyIndirect {
+		t.Errorf("Expected loose-envify to be indirect, got %+v", envify)
+	}
+}
+
+func TestParsePythonDependencies(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTestFile(t, dir, pyprojectFile, `[project]
+name = "svc"
+license = {text = "Apache-2.0"}
+dependencies = [
+    "requests>=2.31", # HTTP
+    "pydantic[email]~=2.0; python_version >= '3.8'",
+]
+
+[project.optional-dependencies]
+docs = ["mkdocs"]
+
+[dependency-groups]
+test = ["pytest>=7"]
+`)
+	writeTestFile(t, dir, uvLockFile, `version = 1
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+
+[[package]]
+name = "urllib3"
+version = "2.0.7"
+`)
+
+	manifest, deps, err := parsePythonDependencies(filepath.Join(dir, pyprojectFile))
+	if err != nil {
+		t.Fatalf("Failed to parse pyproject.toml: %v", err)
+	}
+
+	if manifest.Project != "svc" || manifest.License != "Apache-2.0" {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+
+	graph := &types.DependencyGraph{Dependencies: deps}
+	if requests := graph.Find("requests"); requests == nil || requests.Version != "2.31.0" || requests.Constraint != ">=2.31" {
+		t.Errorf("Unexpected requests dependency: %+v", requests)
+	}
+	if pydantic := graph.Find("pydantic"); pydantic == nil || pydantic.Constraint != "~=2.0" {
+		t.Errorf("Unexpected pydantic dependency: %+v", pydantic)
+	}
+	if mkdocs := graph.Find("mkdocs"); mkdocs == nil || mkdocs.Kind != types.DependencyOptional {
+		t.Errorf("Expected mkdocs to be optional, got %+v", mkdocs)
+	}
+	if pytest := graph.Find("pytest"); pytest == nil || pytest.Kind != types.DependencyDev {
+		t.Errorf("Expected pytest to be a dev dependency, got %+v", pytest)
+	}
+	if urllib3 := graph.Find("urllib3"); urllib3 == nil || urllib3.Kind != types.DependencyIndirect {
+		t.Errorf("Expected urllib3 to be indirect, got %+v", urllib3)
+	}
+}
+
+func TestParsePythonDependencies_Poetry(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTestFile(t, dir, pyprojectFile, `[tool.poetry]
+name = "app"
+license = "MIT"
+
+[tool.poetry.dependencies]
+python = "^3.11"
+django = { extras = ["version"], version = "^5.0" }
+numpy = [
+    { version = "<1.25", python = "<3.9" },
+    { version = "^1.25", python = ">=3.9" },
+]
+
+[tool.poetry.group.test.dependencies]
+pytest = "^8.0"
+`)
+	writeTestFile(t, dir, poetryLockFile, `[[package]]
+name = "Django"
+version = "5.0.1"
+
+[package.extras]
+argon2 = ["argon2-cffi (>=19.1.0)"]
+
+[[package]]
+name = "sqlparse"
+version = "0.4.4"
+`)
+
+	manifest, deps, err := parsePythonDependencies(filepath.Join(dir, pyprojectFile))
+	if err != nil {
+		t.Fatalf("Failed to parse pyproject.toml: %v", err)
+	}
+	if manifest.Project != "app" || manifest.License != "MIT" {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+
+	graph := &types.DependencyGraph{Dependencies: deps}
+	if graph.Find("python") != nil {
+		t.Error("Expected the python constraint not to be a dependency")
+	}
+	if django := graph.Find("django"); django == nil || django.Constraint != "^5.0" || django.Version != "5.0.1" {
+		t.Errorf("Unexpected django dependency: %+v", django)
+	}
+	if numpy := graph.Find("numpy"); numpy == nil || numpy.Constraint != "<1.25" {
+		t.Errorf("Unexpected numpy dependency: %+v", numpy)
+	}
+	if pytest := graph.Find("pytest"); pytest == nil || pytest.Kind != types.DependencyDev {
+		t.Errorf("Expected pytest to be a dev dependency, got %+v", pytest)
+	}
+	if sqlparse := graph.Find("sqlparse"); sqlparse == nil || sqlparse.Kind != types.DependencyIndirect {
+		t.Errorf("Expected sqlparse to be indirect, got %+v", sqlparse)
+	}
+
+	writeTestFile(t, dir, pyprojectFile, "[project\nname = \"broken\"\n")
+	if _, _, err := parsePythonDependencies(filepath.Join(dir, pyprojectFile)); err == nil {
+		t.Error("Expected invalid TOML to fail")
+	}
+}
+
+func TestProjectContextManager_Dependencies(t *testing.T) {
+	dir := t.TempDir()
+	extra := t.TempDir()
+
+	writeTestFile(t, dir, goModFile, "module example.com/app\n\nrequire github.com/google/uuid v1.6.0\n")
+	writeTestFile(t, dir, packageJSONFile, "{not json")
+	writeTestFile(t, extra, packageJSONFile, `{"name": "ui", "dependencies": {"vue": "^3.0.0"}}`)
+
+	config := &types.ClaudeCodeConfig{
+		WorkingDirectory: dir,
+		AddDirs:          []string{extra},
+		TestMode:         true,
+	}
+	client, err := NewClaudeCodeClient(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	projectContext, err := NewProjectContextManager(client).GetEnhancedProjectContext(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get enhanced project context: %v", err)
+	}
+
+	graph := projectContext.Dependencies
+	if graph == nil {
+		t.Fatal("Expected dependency graph")
+	}
+	if len(graph.Manifests) != 2 {
+		t.Errorf("Expected 2 parsed manifests, got %+v", graph.Manifests)
+	}
+	if len(graph.Errors) != 1 {
+		t.Errorf("Expected malformed package.json to be reported, got %v", graph.Errors)
+	}
+	if dep := graph.Find("github.com/google/uuid"); dep == nil || dep.Manifest != goModFile {
+		t.Errorf("Unexpected uuid dependency: %+v", dep)
+	}
+	if deps := graph.ByEcosystem(types.EcosystemNPM); len(deps) != 1 || deps[0].Name != "vue" {
+		t.Errorf("Expected vue from additional directory, got %+v", deps)
+	}
+}
+
+func TestDetectLicense(t *testing.T) {
+	tests := map[string]string{
+		"Apache License\nVersion 2.0, January 2004":                                        "Apache-2.0",
+		"Redistribution and use in source and binary forms ... Neither the name of Google": "BSD-3-Clause",
+		"GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007":                              "GPL-3.0",
+		"some custom terms": "",
+	}
+
+	for text, expected := range tests {
+		if got := detectLicense(text); got != expected {
+			t.Errorf("detectLicense(%q) = %q, want %q", text, got, expected)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Detector finds languages, frameworks and tools in a project for the
+// enhanced project context. Detect is given the context built so far, with
+// the working directory, additional directories, dependency graph and git
+// state filled in, and must not modify it. Register detectors with
+// RegisterDetector, or with ProjectContextManager.RegisterDetector for a
+// single client.
+type Detector interface {
+	// Name identifies the detector; registering a detector replaces any
+	// detector with the same name
+	Name() string
+
+	// Detect returns the technologies found in the project, or none
+	Detect(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error)
+}
+
+// detectorFunc adapts a function to the Detector interface.
+type detectorFunc struct {
+	name   string
+	detect func(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error)
+}
+
+// NewDetector creates a detector that runs detect.
+func NewDetector(name string, detect func(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error)) Detector {
+	return &detectorFunc{name: name, detect: detect}
+}
+
+// Name returns the detector name.
+func (d *detectorFunc) Name() string {
+	return d.name
+}
+
+// Detect runs the detector function.
+func (d *detectorFunc) Detect(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error) {
+	return d.detect(ctx, project)
+}
+
+// NewFileDetector creates a detector that reports technology when a file in
+// a workspace root matches one of the glob patterns, such as "*.tf" for
+// Terraform or "MODULE.bazel" for Bazel. Patterns may name files in
+// subdirectories, as in "ProjectSettings/ProjectVersion.txt".
+func NewFileDetector(technology types.Technology, patterns ...string) Detector {
+	return NewDetector(strings.ToLower(technology.Name), func(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error) {
+		evidence := findProjectFiles(project, patterns...)
+		if len(evidence) == 0 {
+			return nil, nil
+		}
+		found := technology
+		found.Evidence = evidence
+		return []types.Technology{found}, nil
+	})
+}
+
+// findProjectFiles returns the files in the project's workspace roots that
+// match the glob patterns.
+func findProjectFiles(project *types.ProjectContext, patterns ...string) []string {
+	if project.WorkingDirectory == "" {
+		return nil
+	}
+
+	var files []string
+	roots := append([]string{project.WorkingDirectory}, project.AdditionalDirectories...)
+	for _, root := range roots {
+		for _, pattern := range patterns {
+			matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern))) // Only fails for malformed patterns
+			for _, match := range matches {
+				files = append(files, displayManifestPath(project.WorkingDirectory, match))
+			}
+		}
+	}
+	return files
+}
+
+var (
+	detectorsMu sync.RWMutex
+
+	// detectors are run by every ProjectContextManager, built-in ones first
+	detectors = []Detector{
+		NewDetector("go", detectGo),
+		NewDetector("node", detectNode),
+		NewFileDetector(types.Technology{Name: "Python", Kind: types.TechLanguage}, pyprojectFile, "requirements.txt", "setup.py"),
+		NewDetector("frameworks", detectFrameworks),
+		NewFileDetector(types.Technology{Name: "Docker", Kind: types.TechInfrastructure}, "Dockerfile", "docker-compose.yml", "docker-compose.yaml", "compose.yaml"),
+		NewFileDetector(types.Technology{Name: "Make", Kind: types.TechBuildSystem}, "Makefile"),
+	}
+)
+
+// RegisterDetector adds a detector to every ProjectContextManager, replacing
+// the detector with the same name if there is one. Built-in detectors, named
+// "go", "node", "python", "frameworks", "docker" and "make", can be replaced
+// the same way.
+func RegisterDetector(detector Detector) error {
+	if err := validateDetector(detector); err != nil {
+		return err
+	}
+
+	detectorsMu.Lock()
+	defer detectorsMu.Unlock()
+	detectors = withDetector(detectors, detector)
+	return nil
+}
+
+// Detectors returns the detectors registered with RegisterDetector, in the
+// order they run.
+func Detectors() []Detector {
+	detectorsMu.RLock()
+	defer detectorsMu.RUnlock()
+	return append([]Detector(nil), detectors...)
+}
+
+// validateDetector rejects detectors that cannot be registered.
+func validateDetector(detector Detector) error {
+	if detector == nil {
+		return sdkerrors.NewValidationError("detector", "", "required", "detector cannot be nil")
+	}
+	if detector.Name() == "" {
+		return sdkerrors.NewValidationError("name", "", "required", "detector name cannot be empty")
+	}
+	return nil
+}
+
+// withDetector returns list with detector appended, or in place of the
+// detector with the same name. The list is copied rather than modified.
+func withDetector(list []Detector, detector Detector) []Detector {
+	updated := append([]Detector(nil), list...)
+	for i, existing := range updated {
+		if existing.Name() == detector.Name() {
+			updated[i] = detector
+			return updated
+		}
+	}
+	return append(updated, detector)
+}
+
+// detectStack runs the detectors over the project and merges their
+// findings. A detector that fails or panics is recorded in the stack's
+// Errors. It returns nil if nothing was found.
+func detectStack(ctx context.Context, project *types.ProjectContext, detectors []Detector) *types.TechStack {
+	stack := &types.TechStack{}
+
+	for _, detector := range detectors {
+		technologies, err := runDetector(ctx, project, detector)
+		if err != nil {
+			stack.Errors = append(stack.Errors, fmt.Sprintf("%s: %v", detector.Name(), err))
+			continue
+		}
+
+		for _, technology := range technologies {
+			if technology.Detector == "" {
+				technology.Detector = detector.Name()
+			}
+			mergeTechnology(stack, technology)
+		}
+	}
+
+	if len(stack.Technologies) == 0 && len(stack.Errors) == 0 {
+		return nil
+	}
+	return stack
+}
+
+// runDetector runs one detector, turning a panic into an error so that a
+// faulty plugin cannot break project analysis.
+func runDetector(ctx context.Context, project *types.ProjectContext, detector Detector) (technologies []types.Technology, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("detector panicked: %v", r)
+		}
+	}()
+	return detector.Detect(ctx, project)
+}
+
+// mergeTechnology adds a technology to the stack, combining it with an
+// earlier finding of the same name and kind.
+func mergeTechnology(stack *types.TechStack, technology types.Technology) {
+	for i := range stack.Technologies {
+		existing := &stack.Technologies[i]
+		if !strings.EqualFold(existing.Name, technology.Name) || existing.Kind != technology.Kind {
+			continue
+		}
+		if existing.Version == "" {
+			existing.Version = technology.Version
+		}
+		for _, file := range technology.Evidence {
+			if !containsString(existing.Evidence, file) {
+				existing.Evidence = append(existing.Evidence, file)
+			}
+		}
+		return
+	}
+	technology.Evidence = append([]string(nil), technology.Evidence...)
+	stack.Technologies = append(stack.Technologies, technology)
+}
+
+// detectGo reports Go, with the language version from the go directive of
+// the first go.mod found.
+func detectGo(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error) {
+	evidence := findProjectFiles(project, goModFile, goWorkFile)
+	if len(evidence) == 0 {
+		return nil, nil
+	}
+
+	technology := types.Technology{Name: "Go", Kind: types.TechLanguage, Evidence: evidence}
+	for _, file := range evidence {
+		if filepath.Base(file) == goModFile {
+			technology.Version = goDirective(resolveProjectPath(project, file))
+			break
+		}
+	}
+	return []types.Technology{technology}, nil
+}
+
+// goDirective returns the version in a go.mod file's go directive, or "".
+func goDirective(path string) string {
+	file, err := os.Open(path) // #nosec G304 - path is a manifest inside a configured workspace root
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 && fields[0] == "go" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// detectNode reports JavaScript for package.json, and TypeScript when the
+// project has a tsconfig.json or depends on typescript.
+func detectNode(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error) {
+	var technologies []types.Technology
+	if evidence := findProjectFiles(project, packageJSONFile); len(evidence) > 0 {
+		technologies = append(technologies, types.Technology{Name: "JavaScript", Kind: types.TechLanguage, Evidence: evidence})
+	}
+
+	evidence := findProjectFiles(project, "tsconfig.json")
+	version := ""
+	if project.Dependencies != nil {
+		if dep := project.Dependencies.Find("typescript"); dep != nil && dep.Ecosystem == types.EcosystemNPM {
+			version = dep.Version
+			if !containsString(evidence, dep.Manifest) {
+				evidence = append(evidence, dep.Manifest)
+			}
+		}
+	}
+	if len(evidence) > 0 {
+		technologies = append(technologies, types.Technology{Name: "TypeScript", Kind: types.TechLanguage, Version: version, Evidence: evidence})
+	}
+	return technologies, nil
+}
+
+// frameworkDependencies maps dependency names to the frameworks they provide.
+// Go module paths are matched without their major version suffix.
+var frameworkDependencies = map[string]string{
+	"github.com/gin-gonic/gin": "gin",
+	"github.com/labstack/echo": "Echo",
+	"github.com/gofiber/fiber": "Fiber",
+	"github.com/go-chi/chi":    "chi",
+	"google.golang.org/grpc":   "gRPC",
+	"github.com/spf13/cobra":   "Cobra",
+	"react":                    "React",
+	"next":                     "Next.js",
+	"vue":                      "Vue",
+	"@angular/core":            "Angular",
+	"svelte":                   "Svelte",
+	"express":                  "Express",
+	"@nestjs/core":             "NestJS",
+	"django":                   "Django",
+	"flask":                    "Flask",
+	"fastapi":                  "FastAPI",
+}
+
+// goMajorVersionSuffix matches the major version suffix of a Go module path.
+var goMajorVersionSuffix = regexp.MustCompile(`/v[0-9]+$`)
+
+// detectFrameworks reports frameworks the project depends on directly.
+func detectFrameworks(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error) {
+	if project.Dependencies == nil {
+		return nil, nil
+	}
+
+	var technologies []types.Technology
+	for _, dep := range project.Dependencies.Direct() {
+		name := dep.Name
+		if dep.Ecosystem == types.EcosystemGo {
+			name = goMajorVersionSuffix.ReplaceAllString(name, "")
+		}
+		if framework, ok := frameworkDependencies[name]; ok {
+			technologies = append(technologies, types.Technology{
+				Name:     framework,
+				Kind:     types.TechFramework,
+				Version:  dep.Version,
+				Evidence: []string{dep.Manifest},
+			})
+		}
+	}
+	return technologies, nil
+}
+
+// resolveProjectPath returns the absolute path of a file reported relative to
+// the project's working directory.
+func resolveProjectPath(project *types.ProjectContext, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(project.WorkingDirectory, filepath.FromSlash(file))
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// newProjectTestManager returns a ProjectContextManager for a client working
+// in dir.
+func newProjectTestManager(t *testing.T, dir string) *ProjectContextManager {
+	t.Helper()
+
+	config := types.NewClaudeCodeConfig()
+	config.WorkingDirectory = dir
+	config.TestMode = true
+	client, err := NewClaudeCodeClient(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewProjectContextManager(client)
+}
+
+func TestProjectContextManager_BuiltinDetectors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, goModFile, "module example.com/app\n\ngo 1.21\n\nrequire github.com/labstack/echo/v4 v4.11.1\n")
+	writeTestFile(t, dir, "Dockerfile", "FROM golang:1.21\n")
+	writeTestFile(t, dir, packageJSONFile, `{"name": "web", "devDependencies": {"typescript": "5.2.2"}, "dependencies": {"react": "18.2.0"}}`)
+
+	projectCtx, err := newProjectTestManager(t, dir).GetEnhancedProjectContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetEnhancedProjectContext failed: %v", err)
+	}
+	stack := projectCtx.Stack
+	if stack == nil {
+		t.Fatal("Expected a detected stack")
+	}
+
+	var languages []string
+	for _, language := range stack.Languages() {
+		languages = append(languages, language.Name)
+	}
+	if want := []string{"Go", "JavaScript", "TypeScript"}; !reflect.DeepEqual(languages, want) {
+		t.Errorf("Expected languages %v, got %v", want, languages)
+	}
+	if golang := stack.Find("go"); golang.Version != "1.21" || golang.Detector != "go" {
+		t.Errorf("Expected Go 1.21 from the go detector, got %+v", golang)
+	}
+	if ts := stack.Find("TypeScript"); ts.Version != "5.2.2" {
+		t.Errorf("Expected TypeScript version from package.json, got %+v", ts)
+	}
+
+	var frameworks []string
+	for _, framework := range stack.Frameworks() {
+		frameworks = append(frameworks, framework.Name)
+	}
+	if want := []string{"Echo", "React"}; !reflect.DeepEqual(frameworks, want) {
+		t.Errorf("Expected frameworks %v, got %v", want, frameworks)
+	}
+	if docker := stack.Find("Docker"); docker == nil || !reflect.DeepEqual(docker.Evidence, []string{"Dockerfile"}) {
+		t.Errorf("Expected Docker found by its Dockerfile, got %+v", docker)
+	}
+}
+
+func TestProjectContextManager_RegisterDetector(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "infra/main.tf", "terraform {}\n")
+	manager := newProjectTestManager(t, dir)
+	ctx := context.Background()
+
+	if projectCtx, _ := manager.GetEnhancedProjectContext(ctx); projectCtx.Stack != nil {
+		t.Fatalf("Expected no stack for an empty project, got %+v", projectCtx.Stack)
+	}
+
+	terraform := NewFileDetector(types.Technology{Name: "Terraform", Kind: types.TechInfrastructure}, "*.tf", "*/*.tf")
+	if err := manager.RegisterDetector(terraform); err != nil {
+		t.Fatalf("RegisterDetector failed: %v", err)
+	}
+	failing := NewDetector("bazel", func(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error) {
+		return nil, errors.New("bazel query failed")
+	})
+	panicking := NewDetector("unity", func(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error) {
+		panic("boom")
+	})
+	for _, detector := range []Detector{failing, panicking} {
+		if err := manager.RegisterDetector(detector); err != nil {
+			t.Fatalf("RegisterDetector failed: %v", err)
+		}
+	}
+
+	projectCtx, err := manager.GetEnhancedProjectContext(ctx)
+	if err != nil {
+		t.Fatalf("GetEnhancedProjectContext failed: %v", err)
+	}
+	found := projectCtx.Stack.Find("terraform")
+	if found == nil || found.Detector != "terraform" || !reflect.DeepEqual(found.Evidence, []string{"infra/main.tf"}) {
+		t.Errorf("Expected Terraform found in infra/main.tf, got %+v", found)
+	}
+	if errs := projectCtx.Stack.Errors; len(errs) != 2 || errs[0] != "bazel: bazel query failed" || !strings.Contains(errs[1], "unity: detector panicked") {
+		t.Errorf("Expected the failing detectors to be recorded, got %v", errs)
+	}
+
+	// Replacing a built-in detector by name only affects this manager
+	if err := manager.RegisterDetector(NewDetector("go", func(ctx context.Context, project *types.ProjectContext) ([]types.Technology, error) {
+		return []types.Technology{{Name: "Go", Kind: types.TechLanguage, Version: "custom"}}, nil
+	})); err != nil {
+		t.Fatalf("RegisterDetector failed: %v", err)
+	}
+	projectCtx, _ = manager.GetEnhancedProjectContext(ctx)
+	if golang := projectCtx.Stack.Find("Go"); golang == nil || golang.Version != "custom" {
+		t.Errorf("Expected the replacement go detector to run, got %+v", golang)
+	}
+	if names := detectorNames(Detectors()); !reflect.DeepEqual(names, []string{"go", "node", "python", "frameworks", "docker", "make"}) {
+		t.Errorf("Expected the global detectors to be unchanged, got %v", names)
+	}
+
+	if err := manager.RegisterDetector(nil); err == nil {
+		t.Error("Expected a nil detector to be rejected")
+	}
+	if err := RegisterDetector(NewDetector("", nil)); err == nil {
+		t.Error("Expected an unnamed detector to be rejected")
+	}
+}
+
+func TestRegisterDetector(t *testing.T) {
+	original := Detectors()
+	t.Cleanup(func() {
+		detectorsMu.Lock()
+		detectors = original
+		detectorsMu.Unlock()
+	})
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "MODULE.bazel", "module(name = \"app\")\n")
+	bazel := NewFileDetector(types.Technology{Name: "Bazel", Kind: types.TechBuildSystem}, "MODULE.bazel", "WORKSPACE")
+	if err := RegisterDetector(bazel); err != nil {
+		t.Fatalf("RegisterDetector failed: %v", err)
+	}
+
+	projectCtx, err := newProjectTestManager(t, dir).GetEnhancedProjectContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetEnhancedProjectContext failed: %v", err)
+	}
+	if found := projectCtx.Stack.ByKind(types.TechBuildSystem); len(found) != 1 || found[0].Name != "Bazel" {
+		t.Errorf("Expected Bazel to be detected, got %+v", found)
+	}
+}
+
+// detectorNames returns the names of detectors.
+func detectorNames(detectors []Detector) []string {
+	names := make([]string, 0, len(detectors))
+	for _, detector := range detectors {
+		names = append(names, detector.Name())
+	}
+	return names
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// gitCommandTimeout bounds each git invocation used for project context
+	gitCommandTimeout = 5 * time.Second
+
+	// gitRecentCommits is the number of recent commits included in project context
+	gitRecentCommits = 10
+)
+
+// collectGitInfo gathers repository metadata for dir by running git. It
+// returns nil if git is not installed or dir is not inside a repository.
+func collectGitInfo(ctx context.Context, dir string) *types.GitInfo {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil
+	}
+
+	root, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil || root == "" {
+		return nil
+	}
+
+	info := &types.GitInfo{Root: root}
+
+	// symbolic-ref fails with a detached HEAD, leaving Branch empty
+	if branch, err := runGit(ctx, dir, "symbolic-ref", "--short", "-q", "HEAD"); err == nil {
+		info.Branch = branch
+	}
+
+	// git log fails in a repository without commits
+	if output, err := runGit(ctx, dir, "log", "-n", strconv.Itoa(gitRecentCommits), "--format=%H%x1f%an%x1f%aI%x1f%s"); err == nil {
+		info.RecentCommits = parseGitLog(output)
+		if len(info.RecentCommits) > 0 {
+			head := info.RecentCommits[0]
+			info.Head = &head
+		}
+	}
+
+	if output, err := runGit(ctx, dir, "status", "--porcelain"); err == nil {
+		info.ChangedFiles = parseGitStatus(output)
+		info.Dirty = len(info.ChangedFiles) > 0
+	}
+
+	if url, err := runGit(ctx, dir, "remote", "get-url", "origin"); err == nil {
+		info.RemoteURL = url
+	} else if remotes, err := runGit(ctx, dir, "remote"); err == nil && remotes != "" {
+		if url, err := runGit(ctx, dir, "remote", "get-url", strings.Fields(remotes)[0]); err == nil {
+			info.RemoteURL = url
+		}
+	}
+
+	return info
+}
+
+// runGit runs a git command in dir and returns its output without the trailing newline.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - fixed git subcommands
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// parseGitLog parses `git log` output formatted as unit-separated fields.
+func parseGitLog(output string) []types.GitCommit {
+	var commits []types.GitCommit
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+
+		commit := types.GitCommit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Subject: fields[3],
+		}
+		if date, err := time.Parse(time.RFC3339, fields[2]); err == nil {
+			commit.Date = date
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// parseGitStatus extracts the paths from `git status --porcelain` output.
+func parseGitStatus(output string) []string {
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+
+		path := line[3:]
+		// Renames are reported as "old -> new"
+		if idx := strings.Index(path, " -> "); idx >= 0 {
+			path = path[idx+len(" -> "):]
+		}
+		files = append(files, strings.Trim(path, `"`))
+	}
+	return files
+}
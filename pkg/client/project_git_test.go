@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initTestRepo creates a git repository with a single commit in a temporary directory.
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "Test Author")
+	t.Setenv("GIT_AUTHOR_EMAIL", "author@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "author@example.com")
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	git("init", "-q")
+	git("checkout", "-q", "-b", "main")
+	writeTestFile(t, dir, "README.md", "hello\n")
+	git("add", "README.md")
+	git("commit", "-q", "-m", "Initial commit")
+	git("remote", "add", "origin", "https://example.com/repo.git")
+
+	return dir
+}
+
+func TestCollectGitInfo(t *testing.T) {
+	dir := initTestRepo(t)
+
+	info := collectGitInfo(context.Background(), dir)
+	if info == nil {
+		t.Fatal("Expected git info for repository")
+	}
+
+	if info.Branch != "main" {
+		t.Errorf("Expected branch main, got %q", info.Branch)
+	}
+	if info.Head == nil || info.Head.Subject != "Initial commit" || info.Head.Author != "Test Author" {
+		t.Errorf("Unexpected HEAD commit: %+v", info.Head)
+	}
+	if info.Head != nil && info.Head.Date.IsZero() {
+		t.Error("Expected HEAD commit date to be parsed")
+	}
+	if info.Dirty {
+		t.Errorf("Expected clean working tree, got changes %v", info.ChangedFiles)
+	}
+	if info.RemoteURL != "https://example.com/repo.git" {
+		t.Errorf("Unexpected remote URL %q", info.RemoteURL)
+	}
+
+	// Modify a tracked file and add an untracked one
+	writeTestFile(t, dir, "README.md", "changed\n")
+	writeTestFile(t, dir, "new.txt", "new\n")
+
+	info = collectGitInfo(context.Background(), dir)
+	if !info.Dirty || len(info.ChangedFiles) != 2 || info.ChangedFiles[0] != "README.md" {
+		t.Errorf("Expected two changed files, got %v", info.ChangedFiles)
+	}
+
+	summary := info.Summary()
+	for _, expected := range []string{"Branch: main", "Initial commit", "2 changed file(s)"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", expected, summary)
+		}
+	}
+}
+
+func TestCollectGitInfo_NotARepository(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	if info := collectGitInfo(context.Background(), dir); info != nil {
+		t.Errorf("Expected nil git info outside a repository, got %+v", info)
+	}
+}
+
+func TestParseGitStatus(t *testing.T) {
+	output := " M modified.go\nR  old.go -> new.go\n?? \"with space.txt\"\n"
+
+	files := parseGitStatus(output)
+	expected := []string{"modified.go", "new.go", "with space.txt"}
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], files[i])
+		}
+	}
+}
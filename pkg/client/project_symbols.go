@@ -0,0 +1,258 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// BuildSymbolIndex parses the Go sources under root and indexes their exported
+// declarations. Test files, the vendor, testdata, and hidden directories, and
+// paths excluded by the root's .gitignore and .claudeignore are skipped. Files
+// that fail to parse are recorded in the index's Errors.
+//
+// The sources are parsed directly rather than loaded with go/packages, which
+// would run the go command and type-check every package. Like the go command,
+// the index keeps only the files whose build constraints and GOOS and GOARCH
+// suffixes match the build.Default context, so platform variants of a
+// declaration and files excluded with //go:build ignore are not indexed.
+func BuildSymbolIndex(root string) (*types.SymbolIndex, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	index := &types.SymbolIndex{Files: make(map[string][]string)}
+	if manifest, _, err := parseGoModDependencies(filepath.Join(root, goModFile)); err == nil {
+		index.Module = manifest.Project
+	}
+
+	ignore, err := LoadIgnoreMatcher(root)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string]*types.GoPackage)
+	fset := token.NewFileSet()
+
+	err = ignore.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			name := entry.Name()
+			if filePath != root && (name == "vendor" || name == "testdata" || name == "node_modules" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(filePath, ".go") || strings.HasSuffix(filePath, "_test.go") {
+			return nil
+		}
+
+		relFile, _ := filepath.Rel(root, filePath)
+		relFile = filepath.ToSlash(relFile)
+
+		if match, err := build.Default.MatchFile(filepath.Dir(filePath), entry.Name()); err != nil {
+			index.Errors = append(index.Errors, err.Error())
+			return nil
+		} else if !match {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			index.Errors = append(index.Errors, err.Error())
+			return nil
+		}
+
+		relDir := path.Dir(relFile)
+		pkg, ok := packages[relDir]
+		if !ok {
+			pkg = &types.GoPackage{
+				Name:       file.Name.Name,
+				ImportPath: goImportPath(index.Module, relDir),
+				Dir:        relDir,
+			}
+			packages[relDir] = pkg
+		}
+		pkg.Files = append(pkg.Files, relFile)
+
+		symbols := fileSymbols(fset, file, pkg.ImportPath, relFile)
+		names := make([]string, 0, len(symbols))
+		for _, symbol := range symbols {
+			names = append(names, symbol.Name)
+		}
+		index.Files[relFile] = names
+		index.Symbols = append(index.Symbols, symbols...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range packages {
+		index.Packages = append(index.Packages, *pkg)
+	}
+	sort.Slice(index.Packages, func(i, j int) bool {
+		return index.Packages[i].ImportPath < index.Packages[j].ImportPath
+	})
+
+	return index, nil
+}
+
+// goImportPath returns the import path of the package in relDir.
+func goImportPath(module, relDir string) string {
+	switch {
+	case module == "":
+		return relDir
+	case relDir == ".":
+		return module
+	default:
+		return module + "/" + relDir
+	}
+}
+
+// fileSymbols returns the exported declarations of a parsed file.
+func fileSymbols(fset *token.FileSet, file *ast.File, importPath, relFile string) []types.Symbol {
+	var symbols []types.Symbol
+
+	newSymbol := func(name string, kind types.SymbolKind, pos token.Pos, docs *ast.CommentGroup) types.Symbol {
+		return types.Symbol{
+			Name:    name,
+			Kind:    kind,
+			Package: importPath,
+			File:    relFile,
+			Line:    fset.Position(pos).Line,
+			Doc:     firstSentence(docs),
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if !decl.Name.IsExported() {
+				continue
+			}
+
+			kind := types.SymbolFunc
+			receiver := ""
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				receiver = receiverTypeName(decl.Recv.List[0].Type)
+				if !ast.IsExported(receiver) {
+					continue
+				}
+				kind = types.SymbolMethod
+			}
+
+			symbol := newSymbol(decl.Name.Name, kind, decl.Pos(), decl.Doc)
+			symbol.Receiver = receiver
+			symbol.Signature = nodeString(fset, &ast.FuncDecl{Recv: decl.Recv, Name: decl.Name, Type: decl.Type})
+			symbols = append(symbols, symbol)
+
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if !spec.Name.IsExported() {
+						continue
+					}
+
+					kind := types.SymbolType
+					if _, ok := spec.Type.(*ast.InterfaceType); ok {
+						kind = types.SymbolInterface
+					}
+
+					docs := spec.Doc
+					if docs == nil {
+						docs = decl.Doc
+					}
+					symbol := newSymbol(spec.Name.Name, kind, spec.Pos(), docs)
+					symbol.Signature = "type " + spec.Name.Name + " " + typeSummary(fset, spec.Type)
+					symbols = append(symbols, symbol)
+
+				case *ast.ValueSpec:
+					kind := types.SymbolVar
+					if decl.Tok == token.CONST {
+						kind = types.SymbolConst
+					}
+
+					docs := spec.Doc
+					if docs == nil {
+						docs = decl.Doc
+					}
+					for _, name := range spec.Names {
+						if name.IsExported() {
+							symbols = append(symbols, newSymbol(name.Name, kind, name.Pos(), docs))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return symbols
+}
+
+// receiverTypeName returns the base type name of a method receiver.
+func receiverTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// typeSummary renders a type expression, abbreviating struct and interface bodies.
+func typeSummary(fset *token.FileSet, expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.StructType:
+		return "struct{...}"
+	case *ast.InterfaceType:
+		return "interface{...}"
+	}
+	return nodeString(fset, expr)
+}
+
+// nodeString renders an AST node as Go source.
+func nodeString(fset *token.FileSet, node any) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// firstSentence returns the first sentence of a doc comment.
+func firstSentence(docs *ast.CommentGroup) string {
+	if docs == nil {
+		return ""
+	}
+	return doc.Synopsis(docs.Text())
+}
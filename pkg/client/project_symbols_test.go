@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestBuildSymbolIndex(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTestFile(t, dir, goModFile, "module example.com/app\n")
+	writeTestFile(t, dir, "app.go", `package app
+
+// Version is the application version.
+const Version = "1.0"
+
+// Server serves requests. It is safe for concurrent use.
+type Server struct{ addr string }
+
+// Handler handles a request.
+type Handler interface{ Handle() }
+
+// NewServer creates a server.
+func NewServer(addr string) *Server { return &Server{addr: addr} }
+
+// Start starts the server.
+func (s *Server) Start() error { return nil }
+
+func helper() {}
+`)
+	writeTestFile(t, dir, "internal/store/store.go", "package store\n\nvar Default = 1\n")
+	writeTestFile(t, dir, "app_test.go", "package app\n\nfunc TestX() {}\n")
+	writeTestFile(t, dir, "testdata/skip.go", "package skip\n\nfunc Skipped() {}\n")
+	writeTestFile(t, dir, "broken.go", "package app\n\nfunc {")
+	// Files the go command would not build for this platform are skipped
+	writeTestFile(t, dir, "tools.go", "//go:build ignore\n\npackage app\n\nfunc Tool() {}\n")
+	writeTestFile(t, dir, "server_plan9.go", "package app\n\nfunc NewServer() {}\n")
+	writeTestFile(t, dir, "generated/gen.go", "package generated\n\nfunc Generated() {}\n")
+	writeTestFile(t, dir, ClaudeIgnoreFile, "generated/\n")
+
+	index, err := BuildSymbolIndex(dir)
+	if err != nil {
+		t.Fatalf("Failed to build symbol index: %v", err)
+	}
+
+	if index.Module != "example.com/app" {
+		t.Errorf("Expected module example.com/app, got %q", index.Module)
+	}
+	// generated/ is excluded by .claudeignore
+	if len(index.Packages) != 2 || index.Packages[1].ImportPath != "example.com/app/internal/store" {
+		t.Errorf("Unexpected packages: %+v", index.Packages)
+	}
+	if len(index.Errors) != 1 {
+		t.Errorf("Expected broken.go to be reported, got %v", index.Errors)
+	}
+
+	servers := index.FindSymbol("Server")
+	if len(servers) != 1 || servers[0].Kind != types.SymbolType || servers[0].Doc != "Server serves requests." {
+		t.Errorf("Unexpected Server symbol: %+v", servers)
+	}
+	if handler := index.FindSymbol("Handler"); len(handler) != 1 || handler[0].Kind != types.SymbolInterface {
+		t.Errorf("Unexpected Handler symbol: %+v", handler)
+	}
+
+	start := index.FindSymbol("Server.Start")
+	if len(start) != 1 || start[0].Kind != types.SymbolMethod || start[0].Signature != "func (s *Server) Start() error" {
+		t.Errorf("Unexpected Server.Start symbol: %+v", start)
+	}
+	if newServer := index.FindSymbol("NewServer"); len(newServer) != 1 || newServer[0].Line != 13 {
+		t.Errorf("Unexpected NewServer symbol: %+v", newServer)
+	}
+
+	for _, name := range []string{"helper", "TestX", "Skipped", "Tool"} {
+		if found := index.FindSymbol(name); len(found) != 0 {
+			t.Errorf("Expected %s not to be indexed, got %+v", name, found)
+		}
+	}
+
+	if names := index.Files["internal/store/store.go"]; len(names) != 1 || names[0] != "Default" {
+		t.Errorf("Unexpected file map entry: %v", names)
+	}
+	if summary := index.Summary(); !strings.Contains(summary, "package app (example.com/app)") {
+		t.Errorf("Unexpected summary:\n%s", summary)
+	}
+}
+
+func TestProjectContextManager_SymbolIndexing(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "main.go", "package main\n\ntype ClaudeCodeClient struct{}\n")
+
+	config := &types.ClaudeCodeConfig{WorkingDirectory: dir, TestMode: true}
+	client, err := NewClaudeCodeClient(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	manager := NewProjectContextManager(client)
+	projectContext, err := manager.GetEnhancedProjectContext(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get enhanced project context: %v", err)
+	}
+	if projectContext.Symbols != nil {
+		t.Error("Expected symbol indexing to be disabled by default")
+	}
+
+	manager.SetSymbolIndexing(true)
+	projectContext, err = manager.GetEnhancedProjectContext(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get enhanced project context: %v", err)
+	}
+	if found := projectContext.FindSymbol("ClaudeCodeClient"); len(found) != 1 || found[0].File != "main.go" {
+		t.Errorf("Expected ClaudeCodeClient in main.go, got %+v", found)
+	}
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// watchedProjectFiles are the files in each workspace root whose changes
+// invalidate the cached project context.
+var watchedProjectFiles = []string{
+	goModFile, "go.sum", goWorkFile, packageJSONFile, packageLockFile,
+	pnpmWorkspaceFile, cargoFile, pyprojectFile, poetryLockFile, uvLockFile,
+	".gitignore", ".claudeignore",
+}
+
+// projectWatch marks a cached project context stale when a file it was built
+// from changes. It watches with fsnotify the directories holding those files,
+// since editors and git replace files by renaming over them, and ignores
+// events for other files in the same directories.
+type projectWatch struct {
+	watcher *fsnotify.Watcher
+	// files are the paths whose creation, removal or modification matters
+	files map[string]bool
+	// dirs are the directories whose entries being added, removed or
+	// renamed matters
+	dirs  map[string]bool
+	stale atomic.Bool
+
+	stopOnce sync.Once
+}
+
+// watchProject starts watching the files the project context was built from:
+// the manifests and key directories of each workspace root, the monorepo root
+// and the directories holding its packages, the git HEAD, index and changed
+// files, the indexed Go sources, and the files detectors cited as evidence.
+// If the watch cannot be set up, for example because the inotify watch limit
+// is reached, the context is reported stale at once so that it is rebuilt
+// rather than served out of date.
+func watchProject(projectCtx *types.ProjectContext) *projectWatch {
+	w := &projectWatch{files: map[string]bool{}, dirs: map[string]bool{}}
+	if projectCtx.WorkingDirectory == "" {
+		return w
+	}
+
+	roots := append([]string{projectCtx.WorkingDirectory}, projectCtx.AdditionalDirectories...)
+	if workspace := projectCtx.Workspace; workspace != nil {
+		roots = append(roots, workspace.Root)
+		for _, pkg := range workspace.Packages {
+			w.dirs[filepath.Dir(filepath.Join(workspace.Root, filepath.FromSlash(pkg.Path)))] = true
+		}
+	}
+	for _, root := range roots {
+		w.dirs[root] = true
+		for _, name := range watchedProjectFiles {
+			w.files[filepath.Join(root, name)] = true
+		}
+	}
+
+	if git := projectCtx.Git; git != nil {
+		gitDir := filepath.Join(git.Root, ".git")
+		w.files[filepath.Join(gitDir, "HEAD")] = true
+		w.files[filepath.Join(gitDir, "index")] = true
+		if git.Branch != "" {
+			w.files[filepath.Join(gitDir, "refs", "heads", filepath.FromSlash(git.Branch))] = true
+		}
+		for _, file := range git.ChangedFiles {
+			w.files[filepath.Join(git.Root, filepath.FromSlash(file))] = true
+		}
+	}
+
+	if symbols := projectCtx.Symbols; symbols != nil {
+		for _, pkg := range symbols.Packages {
+			w.dirs[filepath.Join(projectCtx.WorkingDirectory, filepath.FromSlash(pkg.Dir))] = true
+			for _, file := range pkg.Files {
+				w.files[filepath.Join(projectCtx.WorkingDirectory, filepath.FromSlash(file))] = true
+			}
+		}
+	}
+
+	if stack := projectCtx.Stack; stack != nil {
+		for _, technology := range stack.Technologies {
+			for _, file := range technology.Evidence {
+				w.files[resolveProjectPath(projectCtx, file)] = true
+			}
+		}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		w.stale.Store(true)
+		return w
+	}
+	w.watcher = watcher
+
+	watched := map[string]bool{}
+	for dir := range w.dirs {
+		watched[dir] = true
+	}
+	for file := range w.files {
+		watched[filepath.Dir(file)] = true
+	}
+	for dir := range watched {
+		// A directory that does not exist yet, such as a git branch
+		// namespace, cannot be watched; its parent notices it being created
+		if err := watcher.Add(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			w.stale.Store(true)
+		}
+	}
+
+	go w.run()
+	return w
+}
+
+// run marks the context stale on the first event for a watched path, or on a
+// watcher error, which may mean events were lost.
+func (w *projectWatch) run() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if w.relevant(event) {
+				w.stale.Store(true)
+			}
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.stale.Store(true)
+		}
+	}
+}
+
+// relevant reports whether an event changes a file the context was built from.
+func (w *projectWatch) relevant(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	if w.files[event.Name] || w.dirs[event.Name] {
+		return true
+	}
+	return event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 && w.dirs[filepath.Dir(event.Name)]
+}
+
+// changed reports whether any watched path was created, removed or modified
+// since the watch started.
+func (w *projectWatch) changed() bool {
+	return w.stale.Load()
+}
+
+// size returns the number of watched paths.
+func (w *projectWatch) size() int {
+	return len(w.files) + len(w.dirs)
+}
+
+// stop closes the watcher.
+func (w *projectWatch) stop() {
+	w.stopOnce.Do(func() {
+		if w.watcher != nil {
+			_ = w.watcher.Close() // Ignore error during cleanup
+		}
+	})
+}
@@ -0,0 +1,301 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Workspace manifest file names recognized by detectWorkspace.
+const (
+	goWorkFile        = "go.work"
+	pnpmWorkspaceFile = "pnpm-workspace.yaml"
+	cargoFile         = "Cargo.toml"
+)
+
+// detectWorkspace looks for go.work, pnpm-workspace.yaml, package.json
+// workspaces and a Cargo workspace in root and lists their packages.
+// Manifests that cannot be parsed are recorded in the workspace's Errors. It
+// returns nil if root is not a workspace root.
+func detectWorkspace(root string) *types.Workspace {
+	workspace := &types.Workspace{Root: root}
+
+	parsers := []struct {
+		file  string
+		kind  types.WorkspaceKind
+		parse func(path string) (patterns []string, ok bool, err error)
+	}{
+		{goWorkFile, types.WorkspaceGo, parseGoWork},
+		{pnpmWorkspaceFile, types.WorkspacePNPM, parsePNPMWorkspace},
+		{packageJSONFile, types.WorkspaceNPM, parseNPMWorkspaces},
+		{cargoFile, types.WorkspaceCargo, parseCargoWorkspace},
+	}
+
+	for _, parser := range parsers {
+		path := filepath.Join(root, parser.file)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		patterns, ok, err := parser.parse(path)
+		if err != nil {
+			workspace.Errors = append(workspace.Errors, fmt.Sprintf("%s: %v", parser.file, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		workspace.Kinds = append(workspace.Kinds, parser.kind)
+		for _, dir := range expandWorkspacePatterns(root, parser.kind, patterns) {
+			rel, _ := filepath.Rel(root, dir)
+			workspace.Packages = append(workspace.Packages, types.WorkspacePackage{
+				Name: workspacePackageName(dir, parser.kind),
+				Path: filepath.ToSlash(rel),
+				Kind: parser.kind,
+			})
+		}
+	}
+
+	if len(workspace.Kinds) == 0 && len(workspace.Errors) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(workspace.Packages, func(i, j int) bool {
+		return workspace.Packages[i].Path < workspace.Packages[j].Path
+	})
+	return workspace
+}
+
+// parseGoWork returns the directories named by a go.work file's use
+// directives.
+func parseGoWork(path string) ([]string, bool, error) {
+	file, err := os.Open(path) // #nosec G304 - path is a manifest inside a configured workspace root
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	var dirs []string
+	inUse := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inUse && fields[0] == ")":
+			inUse = false
+		case inUse:
+			dirs = append(dirs, strings.Trim(fields[0], `"`))
+		case fields[0] == "use" && len(fields) > 1 && fields[1] == "(":
+			inUse = true
+		case fields[0] == "use" && len(fields) > 1:
+			dirs = append(dirs, strings.Trim(fields[1], `"`))
+		}
+	}
+	return dirs, true, scanner.Err()
+}
+
+// parsePNPMWorkspace returns the package patterns of pnpm-workspace.yaml.
+func parsePNPMWorkspace(path string) ([]string, bool, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is a manifest inside a configured workspace root
+	if err != nil {
+		return nil, false, err
+	}
+
+	var manifest struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, false, err
+	}
+	return manifest.Packages, true, nil
+}
+
+// parseNPMWorkspaces returns the workspaces of a package.json, given either
+// as a list or, as Yarn allows, as an object with a packages list. A
+// package.json without workspaces is not a workspace root.
+func parseNPMWorkspaces(path string) ([]string, bool, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is a manifest inside a configured workspace root
+	if err != nil {
+		return nil, false, err
+	}
+
+	var manifest struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, false, err
+	}
+	if len(manifest.Workspaces) == 0 {
+		return nil, false, nil
+	}
+
+	var patterns []string
+	if err := json.Unmarshal(manifest.Workspaces, &patterns); err == nil {
+		return patterns, true, nil
+	}
+	var yarn struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(manifest.Workspaces, &yarn); err != nil {
+		return nil, false, fmt.Errorf("workspaces must be a list or an object with packages")
+	}
+	return yarn.Packages, true, nil
+}
+
+// parseCargoWorkspace returns the members of a Cargo.toml [workspace], with
+// its excludes as negated patterns. A Cargo.toml without a [workspace] table
+// is a single crate, not a workspace root.
+func parseCargoWorkspace(path string) ([]string, bool, error) {
+	var manifest struct {
+		Workspace *struct {
+			Members []string `toml:"members"`
+			Exclude []string `toml:"exclude"`
+		} `toml:"workspace"`
+	}
+	if _, err := toml.DecodeFile(path, &manifest); err != nil {
+		return nil, false, err
+	}
+	if manifest.Workspace == nil {
+		return nil, false, nil
+	}
+
+	patterns := manifest.Workspace.Members
+	for _, exclude := range manifest.Workspace.Exclude {
+		patterns = append(patterns, "!"+exclude)
+	}
+	return patterns, true, nil
+}
+
+// workspaceManifests are the files that make a directory a package of each
+// kind of workspace.
+var workspaceManifests = map[types.WorkspaceKind]string{
+	types.WorkspaceGo:    goModFile,
+	types.WorkspacePNPM:  packageJSONFile,
+	types.WorkspaceNPM:   packageJSONFile,
+	types.WorkspaceCargo: cargoFile,
+}
+
+// expandWorkspacePatterns returns the package directories matched by the
+// glob patterns, skipping those matched by a "!" pattern and those without
+// the kind's manifest. A "**" segment matches one directory level.
+func expandWorkspacePatterns(root string, kind types.WorkspaceKind, patterns []string) []string {
+	var excludes []string
+	for _, pattern := range patterns {
+		if exclude, ok := strings.CutPrefix(pattern, "!"); ok {
+			excludes = append(excludes, workspaceGlob(root, exclude))
+		}
+	}
+
+	var dirs []string
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		matches, _ := filepath.Glob(workspaceGlob(root, pattern)) // Only fails for malformed patterns
+		for _, dir := range matches {
+			if containsString(dirs, dir) || matchesAny(excludes, dir) {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(dir, workspaceManifests[kind])); err != nil {
+				continue
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// workspaceGlob turns a workspace pattern into a filepath.Glob pattern
+// under root.
+func workspaceGlob(root, pattern string) string {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+	pattern = strings.ReplaceAll(pattern, "**", "*")
+	return filepath.Join(root, filepath.FromSlash(pattern))
+}
+
+// matchesAny reports whether path matches one of the glob patterns.
+func matchesAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// workspacePackageName returns the name a package's manifest declares, or
+// its directory name.
+func workspacePackageName(dir string, kind types.WorkspaceKind) string {
+	manifest := filepath.Join(dir, workspaceManifests[kind])
+	name := ""
+	switch kind {
+	case types.WorkspaceGo:
+		if parsed, _, err := parseGoModDependencies(manifest); err == nil {
+			name = parsed.Project
+		}
+	case types.WorkspacePNPM, types.WorkspaceNPM:
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		if data, err := os.ReadFile(manifest); err == nil && json.Unmarshal(data, &pkg) == nil { // #nosec G304 - manifest inside a workspace root
+			name = pkg.Name
+		}
+	case types.WorkspaceCargo:
+		var crate struct {
+			Package struct {
+				Name string `toml:"name"`
+			} `toml:"package"`
+		}
+		if _, err := toml.DecodeFile(manifest, &crate); err == nil {
+			name = crate.Package.Name
+		}
+	}
+
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	return name
+}
+
+// resolveWorkspacePackage finds the directory of a workspace package given
+// its name, or of any directory given a path relative to root or absolute.
+// It returns the absolute directory and its slash-separated path relative to
+// root.
+func resolveWorkspacePackage(root string, workspace *types.Workspace, nameOrPath string) (string, string, error) {
+	path := nameOrPath
+	if workspace != nil {
+		if pkg := workspace.Package(nameOrPath); pkg != nil {
+			path = pkg.Path
+		} else if pkg := workspace.Package(filepath.ToSlash(filepath.Clean(nameOrPath))); pkg != nil {
+			path = pkg.Path
+		}
+	}
+
+	dir := path
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, filepath.FromSlash(dir))
+	}
+	dir = filepath.Clean(dir)
+
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", "", sdkerrors.NewValidationError("package", nameOrPath, "workspace", "package must be inside the working directory")
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", "", sdkerrors.NewValidationError("package", nameOrPath, "exists", "package is not a workspace package or directory")
+	}
+	return dir, filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestDetectWorkspace(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		kinds []types.WorkspaceKind
+		want  []types.WorkspacePackage
+	}{
+		{
+			name: "go.work",
+			files: map[string]string{
+				goWorkFile:               "go 1.21\n\nuse ./tools // generators\n\nuse (\n\t./services/api\n\t./services/worker\n)\n",
+				"tools/go.mod":           "module example.com/tools\n",
+				"services/api/go.mod":    "module example.com/api\n",
+				"services/worker/go.mod": "module example.com/worker\n",
+			},
+			kinds: []types.WorkspaceKind{types.WorkspaceGo},
+			want: []types.WorkspacePackage{
+				{Name: "example.com/api", Path: "services/api", Kind: types.WorkspaceGo},
+				{Name: "example.com/worker", Path: "services/worker", Kind: types.WorkspaceGo},
+				{Name: "example.com/tools", Path: "tools", Kind: types.WorkspaceGo},
+			},
+		},
+		{
+			name: "pnpm",
+			files: map[string]string{
+				pnpmWorkspaceFile:              "packages:\n  - 'apps/*'\n  - 'packages/**'\n  - '!packages/legacy'\n",
+				"apps/web/package.json":        `{"name": "@acme/web"}`,
+				"apps/notes/README.md":         "not a package",
+				"packages/ui/package.json":     `{"name": "@acme/ui"}`,
+				"packages/legacy/package.json": `{"name": "@acme/legacy"}`,
+			},
+			kinds: []types.WorkspaceKind{types.WorkspacePNPM},
+			want: []types.WorkspacePackage{
+				{Name: "@acme/web", Path: "apps/web", Kind: types.WorkspacePNPM},
+				{Name: "@acme/ui", Path: "packages/ui", Kind: types.WorkspacePNPM},
+			},
+		},
+		{
+			name: "yarn",
+			files: map[string]string{
+				packageJSONFile:          `{"name": "root", "private": true, "workspaces": {"packages": ["libs/*"]}}`,
+				"libs/core/package.json": `{}`,
+			},
+			kinds: []types.WorkspaceKind{types.WorkspaceNPM},
+			want:  []types.WorkspacePackage{{Name: "core", Path: "libs/core", Kind: types.WorkspaceNPM}},
+		},
+		{
+			name: "cargo",
+			files: map[string]string{
+				cargoFile:                   "[workspace]\nmembers = [\"crates/*\"]\nexclude = [\"crates/scratch\"]\n",
+				"crates/parser/Cargo.toml":  "[package]\nname = \"acme-parser\"\n",
+				"crates/scratch/Cargo.toml": "[package]\nname = \"scratch\"\n",
+			},
+			kinds: []types.WorkspaceKind{types.WorkspaceCargo},
+			want:  []types.WorkspacePackage{{Name: "acme-parser", Path: "crates/parser", Kind: types.WorkspaceCargo}},
+		},
+		{
+			name:  "single package",
+			files: map[string]string{packageJSONFile: `{"name": "app"}`, cargoFile: "[package]\nname = \"app\"\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeTestFile(t, dir, name, content)
+			}
+
+			workspace := detectWorkspace(dir)
+			if tt.kinds == nil {
+				if workspace != nil {
+					t.Fatalf("Expected no workspace, got %+v", workspace)
+				}
+				return
+			}
+			if workspace == nil {
+				t.Fatal("Expected a workspace")
+			}
+			if !reflect.DeepEqual(workspace.Kinds, tt.kinds) || len(workspace.Errors) > 0 {
+				t.Errorf("Expected kinds %v without errors, got %v %v", tt.kinds, workspace.Kinds, workspace.Errors)
+			}
+			if !reflect.DeepEqual(workspace.Packages, tt.want) {
+				t.Errorf("Expected packages %+v, got %+v", tt.want, workspace.Packages)
+			}
+		})
+	}
+}
+
+func TestProjectContextManager_FocusPackage(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, goWorkFile, "go 1.21\n\nuse (\n\t./services/api\n\t./services/worker\n)\n")
+	writeTestFile(t, dir, "services/api/go.mod", "module example.com/api\n\ngo 1.21\n\nrequire github.com/gin-gonic/gin v1.9.1\n")
+	writeTestFile(t, dir, "services/worker/go.mod", "module example.com/worker\n\ngo 1.21\n\nrequire github.com/google/uuid v1.6.0\n")
+	manager := newProjectTestManager(t, dir)
+	ctx := context.Background()
+
+	whole, err := manager.GetEnhancedProjectContext(ctx)
+	if err != nil {
+		t.Fatalf("GetEnhancedProjectContext failed: %v", err)
+	}
+	if whole.Workspace == nil || len(whole.Workspace.Packages) != 2 || whole.Focus != "" {
+		t.Fatalf("Expected an unfocused workspace of two packages, got %+v focus %q", whole.Workspace, whole.Focus)
+	}
+
+	if err := manager.FocusPackage("example.com/api"); err != nil {
+		t.Fatalf("FocusPackage failed: %v", err)
+	}
+	focused, err := manager.GetEnhancedProjectContext(ctx)
+	if err != nil {
+		t.Fatalf("GetEnhancedProjectContext failed: %v", err)
+	}
+	if focused.Focus != "services/api" || focused.WorkingDirectory != filepath.Join(whole.WorkingDirectory, "services", "api") {
+		t.Errorf("Expected the context focused on services/api, got %q in %s", focused.Focus, focused.WorkingDirectory)
+	}
+	if focused.Dependencies.Find("github.com/google/uuid") != nil || focused.Dependencies.Find("github.com/gin-gonic/gin") == nil {
+		t.Errorf("Expected only the api dependencies, got %+v", focused.Dependencies.Dependencies)
+	}
+	if focused.Workspace == nil || len(focused.Workspace.Packages) != 2 {
+		t.Errorf("Expected the workspace to still list every package, got %+v", focused.Workspace)
+	}
+	if cached, _ := manager.GetEnhancedProjectContext(ctx); cached != focused {
+		t.Error("Expected the focused context to be cached")
+	}
+
+	worker, err := manager.PackageContext(ctx, "services/worker")
+	if err != nil {
+		t.Fatalf("PackageContext failed: %v", err)
+	}
+	if worker.Focus != "services/worker" || worker.Dependencies.Find("github.com/google/uuid") == nil {
+		t.Errorf("Expected the worker context, got focus %q deps %+v", worker.Focus, worker.Dependencies)
+	}
+
+	for _, bad := range []string{"services/missing", "../outside"} {
+		if err := manager.FocusPackage(bad); err == nil {
+			t.Errorf("Expected FocusPackage(%q) to fail", bad)
+		}
+	}
+
+	if err := manager.FocusPackage(""); err != nil {
+		t.Fatalf("FocusPackage(\"\") failed: %v", err)
+	}
+	if unfocused, _ := manager.GetEnhancedProjectContext(ctx); unfocused.Focus != "" || unfocused.WorkingDirectory != whole.WorkingDirectory {
+		t.Errorf("Expected the whole repository again, got focus %q", unfocused.Focus)
+	}
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// PurgeSession deletes everything kept about a session, given its session ID
+// or an external ID known to the session registry, to satisfy data-deletion
+// requests. It closes the session, deletes the job checkpoints of jobs run in
+// it from the session store, removes the registry mapping, and deletes the
+// CLI's transcript, todo list and file history for the session, since the
+// CLI has no command to forget a session. Transcripts already copied
+// elsewhere, such as by the archive package, are not removed.
+//
+// Every kind of data is attempted even if one fails; the report lists what
+// was deleted. Purging a session with no data is not an error.
+func (c *ClaudeCodeClient) PurgeSession(ctx context.Context, sessionID string) (*types.SessionPurge, error) {
+	id, externalID, err := c.sessionManager.lookupSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, sdkerrors.NewValidationError("sessionID", sessionID, "existing session", "session not found")
+	}
+
+	purge := &types.SessionPurge{SessionID: id, ExternalID: externalID}
+	var errs []error
+
+	_ = c.sessionManager.CloseSession(id) // Only fails for registry lookups, which succeeded above
+
+	c.mu.RLock()
+	store := c.sessionStore
+	c.mu.RUnlock()
+	if store != nil {
+		deleted, err := purgeCheckpoints(ctx, store, id)
+		purge.Checkpoints = deleted
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	files, err := c.purgeCLISession(ctx, id)
+	purge.Files = files
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if registry := c.sessionManager.registry(); registry != nil && externalID != "" {
+		if err := registry.Unregister(ctx, externalID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return purge, sdkerrors.WrapError(errors.Join(errs...), sdkerrors.CategoryInternal, "SESSION_PURGE", "failed to purge all session data")
+	}
+	return purge, nil
+}
+
+// purgeCheckpoints deletes the checkpoints of jobs run in a session and
+// returns their job IDs.
+func purgeCheckpoints(ctx context.Context, store SessionStore, sessionID string) ([]string, error) {
+	checkpoints, err := store.ListCheckpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, checkpoint := range checkpoints {
+		if checkpoint.SessionID != sessionID {
+			continue
+		}
+		if err := store.DeleteCheckpoint(ctx, checkpoint.JobID); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, checkpoint.JobID)
+	}
+	return deleted, nil
+}
+
+// purgeCLISession deletes the files the CLI keeps for a session in its
+// config directory and returns their paths.
+func (c *ClaudeCodeClient) purgeCLISession(ctx context.Context, sessionID string) ([]string, error) {
+	dir, err := c.claudeConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := []string{
+		filepath.Join(dir, "projects", "*", sessionID+".jsonl"),
+		filepath.Join(dir, "todos", sessionID+"-*.json"),
+		filepath.Join(dir, "file-history", sessionID),
+	}
+	var removed []string
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return removed, err
+		}
+		for _, path := range paths {
+			if err := ctx.Err(); err != nil {
+				return removed, err
+			}
+			if err := os.RemoveAll(path); err != nil {
+				return removed, err
+			}
+			removed = append(removed, path)
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPurgeSession(t *testing.T) {
+	ctx := context.Background()
+	client, configDir := newStoredSessionsClient(t)
+
+	registry := NewMemorySessionRegistry()
+	client.Sessions().SetRegistry(registry)
+	session, err := client.CreateSession(ctx, "customer-42")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	other := GenerateSessionID()
+
+	transcript := writeTranscript(t, configDir, "-src-api", session.ID, `{"type":"user"}`)
+	kept := writeTranscript(t, configDir, "-src-api", other, `{"type":"user"}`)
+	todos := filepath.Join(configDir, "todos", session.ID+"-agent-"+session.ID+".json")
+	history := filepath.Join(configDir, "file-history", session.ID)
+	for _, path := range []string{todos, filepath.Join(history, "main.go@v1")} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := NewMemorySessionStore()
+	client.SetSessionStore(store)
+	for _, checkpoint := range []*JobCheckpoint{
+		{JobID: "job-1", SessionID: session.ID},
+		{JobID: "job-2", SessionID: other},
+	} {
+		if err := store.SaveCheckpoint(ctx, checkpoint); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	purge, err := client.PurgeSession(ctx, "customer-42")
+	if err != nil {
+		t.Fatalf("PurgeSession failed: %v", err)
+	}
+	if purge.SessionID != session.ID || purge.ExternalID != "customer-42" {
+		t.Errorf("Purge IDs = %q, %q", purge.SessionID, purge.ExternalID)
+	}
+	if len(purge.Checkpoints) != 1 || purge.Checkpoints[0] != "job-1" {
+		t.Errorf("Purged checkpoints = %v, want [job-1]", purge.Checkpoints)
+	}
+	if len(purge.Files) != 3 {
+		t.Errorf("Purged files = %v, want 3", purge.Files)
+	}
+
+	for _, path := range []string{transcript, todos, history} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be deleted", path)
+		}
+	}
+	if _, err := os.Stat(kept); err != nil {
+		t.Errorf("Other sessions' transcripts should be kept: %v", err)
+	}
+	if _, err := store.LoadCheckpoint(ctx, "job-2"); err != nil {
+		t.Errorf("Other sessions' checkpoints should be kept: %v", err)
+	}
+	if _, err := client.GetSession(session.ID); err == nil {
+		t.Error("Purged session should be closed")
+	}
+	if _, ok, _ := registry.SessionID(ctx, "customer-42"); ok {
+		t.Error("Purged external ID should be unregistered")
+	}
+}
+
+func TestPurgeSessionWithoutData(t *testing.T) {
+	client, _ := newStoredSessionsClient(t)
+	sessionID := GenerateSessionID()
+
+	purge, err := client.PurgeSession(context.Background(), sessionID)
+	if err != nil {
+		t.Fatalf("PurgeSession failed: %v", err)
+	}
+	if purge.SessionID != sessionID || len(purge.Files) != 0 || len(purge.Checkpoints) != 0 {
+		t.Errorf("Unexpected purge report: %+v", purge)
+	}
+}
+
+func TestPurgeSessionUnknownExternalID(t *testing.T) {
+	client, _ := newStoredSessionsClient(t)
+	client.Sessions().SetRegistry(NewMemorySessionRegistry())
+
+	if _, err := client.PurgeSession(context.Background(), "unknown-ticket"); err == nil {
+		t.Error("Expected an error for an unregistered external ID")
+	}
+}
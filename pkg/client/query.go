@@ -1,12 +1,11 @@
 package client
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
 )
@@ -36,6 +35,9 @@ type QueryOptions struct {
 	// AllowedTools specifies which tools Claude can use
 	AllowedTools []string
 
+	// DisallowedTools specifies tools Claude must not use
+	DisallowedTools []string
+
 	// PermissionMode controls how file edits are handled
 	PermissionMode PermissionMode
 
@@ -59,6 +61,25 @@ type QueryOptions struct {
 
 	// Environment variables to pass to Claude Code
 	Env map[string]string
+
+	// ToolTimeouts overrides the client's per-tool execution deadlines for this query
+	ToolTimeouts map[string]time.Duration
+
+	// ExtraArgs appends arbitrary CLI flags, overriding the client's ExtraArgs.
+	// A nil value passes a boolean flag.
+	ExtraArgs map[string]*string
+
+	// Profile selects a named profile from the client's configuration,
+	// overriding its default profile
+	Profile string
+
+	// APIKey authenticates this query instead of the client's credentials,
+	// as WithAPIKey does for the context. It is never serialized.
+	APIKey string `json:"-"`
+
+	// Metadata is attached to the query as WithQueryMetadata does for the
+	// context, and added to every message under types.QueryMetadataKey
+	Metadata map[string]string
 }
 
 // QueryResult represents the result of a query execution
@@ -73,6 +94,12 @@ type QueryResult struct {
 func (c *ClaudeCodeClient) QueryMessages(ctx context.Context, prompt string, options *QueryOptions) (<-chan *types.Message, error) {
 	messageChan := make(chan *types.Message, 100)
 
+	options, err := c.applyProfile(options)
+	if err != nil {
+		close(messageChan)
+		return messageChan, err
+	}
+
 	// Set defaults
 	if options == nil {
 		options = &QueryOptions{
@@ -82,6 +109,39 @@ func (c *ClaudeCodeClient) QueryMessages(ctx context.Context, prompt string, opt
 		}
 	}
 
+	// Report every invalid option now rather than as an obscure CLI failure
+	if err := c.validateQuery(options); err != nil {
+		close(messageChan)
+		return messageChan, err
+	}
+
+	// Start a new conversation with what was remembered from earlier ones
+	if options.SessionID == "" {
+		withMemories := *options
+		withMemories.SystemPrompt = c.withMemories(ctx, options.SystemPrompt, prompt)
+		options = &withMemories
+	}
+
+	// Check the prompt and system prompt against the message policy
+	if c.settings().MessagePolicy != nil {
+		checked := *options
+		if prompt, _, err = c.applyPolicy(ctx, types.MessageOutgoing, prompt); err != nil {
+			close(messageChan)
+			return messageChan, err
+		}
+		if checked.SystemPrompt, _, err = c.applyPolicy(ctx, types.MessageOutgoing, options.SystemPrompt); err != nil {
+			close(messageChan)
+			return messageChan, err
+		}
+		options = &checked
+	}
+
+	// Authenticate as the caller the query is made for
+	if options.APIKey != "" {
+		ctx = WithAPIKey(ctx, options.APIKey)
+	}
+	ctx = WithQueryMetadata(ctx, options.Metadata)
+
 	// Create session using session manager
 	session, err := c.sessionManager.CreateSession(ctx, options.SessionID)
 	if err != nil {
@@ -94,12 +154,22 @@ func (c *ClaudeCodeClient) QueryMessages(ctx context.Context, prompt string, opt
 		session.model = options.Model
 	}
 	if options.CWD != "" {
-		session.projectDir = options.CWD
+		if err := session.SetProjectDirectory(options.CWD); err != nil {
+			close(messageChan)
+			return messageChan, err
+		}
+	}
+
+	// Tag every message with the query's metadata on its way out
+	out := messageChan
+	if QueryMetadata(ctx) != nil {
+		out = make(chan *types.Message, 100)
+		go tagMessages(ctx, out, messageChan)
 	}
 
 	// Start processing in goroutine
 	go func() {
-		defer close(messageChan)
+		defer close(out)
 		defer func() {
 			if !session.closed {
 				_ = session.Close() // Ignore error during cleanup
@@ -111,7 +181,7 @@ func (c *ClaudeCodeClient) QueryMessages(ctx context.Context, prompt string, opt
 			Role:    types.RoleUser,
 			Content: prompt,
 		}
-		messageChan <- userMsg
+		out <- userMsg
 
 		// Build command for chat
 		cmd := &types.Command{
@@ -120,8 +190,27 @@ func (c *ClaudeCodeClient) QueryMessages(ctx context.Context, prompt string, opt
 			Options: c.convertQueryOptionsToCommandOptions(options),
 		}
 
-		// Execute with streaming
-		c.executeQueryWithStreaming(ctx, session, cmd, messageChan, options)
+		// Execute with streaming, forwarding progress from local tools and
+		// cancelling those still running when the query ends or its context
+		// is cancelled
+		stopProgress := c.streamToolProgress(session.ID, out)
+		defer stopProgress()
+		defer c.watchToolCalls(ctx, session.ID)()
+		if c.settings().MessagePolicy == nil {
+			c.executeQueryWithStreaming(ctx, session, cmd, out, options)
+			return
+		}
+
+		// Check responses against the message policy on their way out
+		unchecked := make(chan *types.Message, 100)
+		checked := make(chan struct{})
+		go func() {
+			defer close(checked)
+			c.policyMessages(ctx, unchecked, out)
+		}()
+		c.executeQueryWithStreaming(ctx, session, cmd, unchecked, options)
+		close(unchecked)
+		<-checked
 	}()
 
 	return messageChan, nil
@@ -150,13 +239,19 @@ func (c *ClaudeCodeClient) QueryMessagesSync(ctx context.Context, prompt string,
 		}
 	}
 
-	return &QueryResult{
+	result := &QueryResult{
 		Messages: messages,
 		Error:    queryErr,
 		Metadata: map[string]any{
 			"turn_count": len(messages) / 2, // Approximate turn count
 		},
-	}, nil
+	}
+	if len(messages) > 0 {
+		if metadata, ok := messages[len(messages)-1].Metadata[types.QueryMetadataKey]; ok {
+			result.Metadata[types.QueryMetadataKey] = metadata
+		}
+	}
+	return result, nil
 }
 
 // executeQueryWithStreaming handles the streaming execution of a query
@@ -168,24 +263,18 @@ func (c *ClaudeCodeClient) executeQueryWithStreaming(
 	options *QueryOptions,
 ) {
 	// Build command
-	cmdArgs := c.buildQueryCommand(session, cmd, options)
-
-	// Create and start claude process
-	process := exec.CommandContext(ctx, c.claudeCodeCmd, cmdArgs...) // #nosec G204 - claudeCodeCmd is validated during initialization
-	process.Dir = c.workingDir
-
-	// Create pipes for stdout
-	stdout, err := process.StdoutPipe()
+	cmdArgs, err := c.buildQueryCommand(session, cmd, options)
 	if err != nil {
 		messageChan <- &types.Message{
 			Role:    types.RoleSystem,
-			Content: fmt.Sprintf("Error creating stdout pipe: %v", err),
+			Content: fmt.Sprintf("Error starting Claude Code: %v", err),
 		}
 		return
 	}
 
-	// Start the process
-	if err := process.Start(); err != nil {
+	// Create and start claude process in the session's directory
+	process, err := c.startCLI(withQueryScope(ctx, c.sessionScope(session)), cmdArgs, nil, cmd, false)
+	if err != nil {
 		messageChan <- &types.Message{
 			Role:    types.RoleSystem,
 			Content: fmt.Sprintf("Error starting Claude Code: %v", err),
@@ -195,19 +284,39 @@ func (c *ClaudeCodeClient) executeQueryWithStreaming(
 
 	// Track the process
 	processID := fmt.Sprintf("query_%s", session.ID)
-	c.processMu.Lock()
-	c.activeProcesses[processID] = process
-	c.processMu.Unlock()
+	if err := c.trackProcess(processID, process); err != nil {
+		_ = process.stdout.Close() // Ignore error during cleanup
+		_ = process.Wait()         // Ignore error, the process was killed
+		messageChan <- &types.Message{
+			Role:    types.RoleSystem,
+			Content: fmt.Sprintf("Error starting Claude Code: %v", err),
+		}
+		return
+	}
 
 	defer func() {
-		c.processMu.Lock()
-		delete(c.activeProcesses, processID)
-		c.processMu.Unlock()
-		_ = process.Process.Kill() // Ignore error, best effort cleanup
+		c.untrackProcess(processID)
+		_ = process.Kill()         // Ignore error, best effort cleanup
+		_ = process.stdout.Close() // Ignore error during cleanup
+		_ = process.Wait()         // Ignore error, the process was killed
 	}()
 
+	// Interrupt the query when a tool overruns its deadline
+	deadlines := newToolDeadlines(c.mergeToolTimeouts(options.ToolTimeouts), func() {
+		_ = process.Kill()         // Ignore error, best effort interrupt
+		_ = process.stdout.Close() // Ignore error, the reader is being released
+	})
+	defer deadlines.stop()
+
 	// Parse streaming output
-	c.parseStreamingOutput(stdout, messageChan, options)
+	c.parseStreamingOutput(process.stdout, messageChan, options, deadlines)
+
+	if err := deadlines.err(); err != nil {
+		messageChan <- &types.Message{
+			Role:    types.RoleSystem,
+			Content: fmt.Sprintf("Error: %v", err),
+		}
+	}
 }
 
 // parseStreamingOutput parses the streaming output from Claude Code
@@ -215,16 +324,23 @@ func (c *ClaudeCodeClient) parseStreamingOutput(
 	stdout any,
 	messageChan chan<- *types.Message,
 	options *QueryOptions,
+	deadlines *toolDeadlines,
 ) {
-	scanner := bufio.NewScanner(stdout.(interface{ Read([]byte) (int, error) }))
+	lines := c.cliLineReader(stdout.(interface{ Read([]byte) (int, error) }))
 
 	var currentMessage *types.Message
 	var contentBuffer strings.Builder
 	inAssistantMessage := false
 	turnCount := 0
+	lastToolID := ""
 
-	for scanner.Scan() {
-		line := scanner.Text()
+	for {
+		raw, err := lines.next()
+		if err != nil {
+			break
+		}
+		c.publishFrame(raw)
+		line := strings.TrimSuffix(string(raw), "\n")
 
 		// Parse different output patterns
 		if strings.HasPrefix(line, "Claude:") || strings.HasPrefix(line, "Assistant:") {
@@ -256,6 +372,8 @@ func (c *ClaudeCodeClient) parseStreamingOutput(
 			// Parse tool information
 			toolInfo := c.parseToolUsage(line)
 			if toolInfo != nil {
+				lastToolID = toolInfo.ID
+				deadlines.start(toolInfo.ID, toolInfo.Name)
 				currentMessage = &types.Message{
 					Role: types.RoleAssistant,
 					ToolCalls: []types.ToolCall{
@@ -274,6 +392,7 @@ func (c *ClaudeCodeClient) parseStreamingOutput(
 
 		} else if strings.HasPrefix(line, "Result:") {
 			// Tool result
+			deadlines.finish(lastToolID)
 			result := strings.TrimPrefix(line, "Result:")
 			resultMsg := &types.Message{
 				Role:    types.RoleTool,
@@ -365,8 +484,9 @@ func (c *ClaudeCodeClient) buildQueryCommand(
 	session *ClaudeCodeSession,
 	cmd *types.Command,
 	options *QueryOptions,
-) []string {
+) ([]string, error) {
 	args := []string{c.claudeCodeCmd}
+	scope := c.sessionScope(session)
 
 	// Add session ID
 	if session.ID != "" {
@@ -378,10 +498,22 @@ func (c *ClaudeCodeClient) buildQueryCommand(
 		args = append(args, "--model", options.Model)
 	}
 
+	// Add additional workspace roots
+	for _, dir := range scope.addDirs {
+		args = append(args, "--add-dir", dir)
+	}
+
+	// Offer the ToolRegistry's tools over MCP
+	localTools, err := c.localToolArgs(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, localTools...)
+
 	// Add system prompt
 	// Claude CLI uses --append-system-prompt
-	if options.SystemPrompt != "" {
-		args = append(args, "--append-system-prompt", options.SystemPrompt)
+	if system := c.systemPrompt(options.SystemPrompt); system != "" {
+		args = append(args, "--append-system-prompt", c.redact(system))
 	}
 
 	// Note: Claude CLI does not support --max-turns flag
@@ -397,11 +529,13 @@ func (c *ClaudeCodeClient) buildQueryCommand(
 		args = append(args, "--permission-mode", "default")
 	}
 
-	// Add allowed tools
+	// Add allowed tools, and the web domain and .claudeignore permissions
 	// Claude CLI uses --allowedTools (not --tools)
-	if len(options.AllowedTools) > 0 {
-		args = append(args, "--allowedTools", strings.Join(options.AllowedTools, ","))
+	permissions, err := c.permissionArgs(scope.workingDir, options.AllowedTools, options.DisallowedTools)
+	if err != nil {
+		return nil, err
 	}
+	args = append(args, permissions...)
 
 	// Note: Claude CLI does not support --timeout flag
 	// Timeout would need to be handled at the process level
@@ -411,10 +545,28 @@ func (c *ClaudeCodeClient) buildQueryCommand(
 		args = append(args, "--format", options.ResponseFormat)
 	}
 
+	// Install SDK hooks such as the Bash sandbox
+	hooks, err := c.hookArgs(scope.workingDir)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, hooks...)
+
+	// Add passthrough flags, letting the query override the client's
+	defaults := c.settings().ExtraArgs
+	extra := make(map[string]*string, len(defaults)+len(options.ExtraArgs))
+	for flag, value := range defaults {
+		extra[strings.TrimLeft(flag, "-")] = value
+	}
+	for flag, value := range options.ExtraArgs {
+		extra[strings.TrimLeft(flag, "-")] = value
+	}
+	args = append(args, extraArgs(extra)...)
+
 	// Add the prompt
-	args = append(args, cmd.Args[0])
+	args = append(args, c.redact(cmd.Args[0]))
 
-	return args
+	return args, nil
 }
 
 // convertQueryOptionsToCommandOptions converts QueryOptions to command options map
@@ -430,6 +582,9 @@ func (c *ClaudeCodeClient) convertQueryOptionsToCommandOptions(options *QueryOpt
 	if len(options.AllowedTools) > 0 {
 		opts["allowed_tools"] = options.AllowedTools
 	}
+	if len(options.DisallowedTools) > 0 {
+		opts["disallowed_tools"] = options.DisallowedTools
+	}
 	if options.PermissionMode != "" {
 		opts["permission_mode"] = string(options.PermissionMode)
 	}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+type queryMetadataKey struct{}
+
+// WithQueryMetadata returns a context whose queries carry metadata, such as
+// the feature, user or experiment a query is made for. The metadata is
+// added to responses, messages and stream chunks under
+// types.QueryMetadataKey, to webhook and file access events, to job
+// checkpoints, and as labels to the usage tracker, so usage can be sliced
+// by any key. It is merged with metadata already attached to ctx, with
+// metadata's values taking precedence. QueryOptions.Metadata does the same
+// for QueryMessages.
+func WithQueryMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	if len(metadata) == 0 {
+		return ctx
+	}
+	merged := QueryMetadata(ctx)
+	if merged == nil {
+		merged = make(map[string]string, len(metadata))
+	}
+	for key, value := range metadata {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, queryMetadataKey{}, merged)
+}
+
+// QueryMetadata returns a copy of the metadata attached to ctx with
+// WithQueryMetadata, or nil.
+func QueryMetadata(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(queryMetadataKey{}).(map[string]string)
+	if len(metadata) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
+
+// withMetadataValue returns a response or message metadata map with the
+// query metadata of ctx added. It returns metadata unchanged when ctx has
+// none.
+func withMetadataValue(ctx context.Context, metadata map[string]any) map[string]any {
+	queryMetadata := QueryMetadata(ctx)
+	if queryMetadata == nil {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]any, 1)
+	}
+	metadata[types.QueryMetadataKey] = queryMetadata
+	return metadata
+}
+
+// tagMessages forwards messages from in to out, adding the query metadata
+// of ctx to each, and closes out once in is closed.
+func tagMessages(ctx context.Context, in <-chan *types.Message, out chan<- *types.Message) {
+	defer close(out)
+	for message := range in {
+		if message != nil {
+			message.Metadata = withMetadataValue(ctx, message.Metadata)
+		}
+		out <- message
+	}
+}
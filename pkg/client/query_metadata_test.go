@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestWithQueryMetadata(t *testing.T) {
+	ctx := context.Background()
+	if QueryMetadata(ctx) != nil || WithQueryMetadata(ctx, nil) != ctx {
+		t.Error("Expected no metadata on a plain context")
+	}
+
+	ctx = WithQueryMetadata(ctx, map[string]string{"feature": "search", "user": "u1"})
+	ctx = WithQueryMetadata(ctx, map[string]string{"feature": "chat", "experiment": "b"})
+	want := map[string]string{"feature": "chat", "user": "u1", "experiment": "b"}
+	if got := QueryMetadata(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryMetadata = %v, want %v", got, want)
+	}
+
+	// Callers get a copy
+	QueryMetadata(ctx)["feature"] = "changed"
+	if QueryMetadata(ctx)["feature"] != "chat" {
+		t.Error("Modifying the returned metadata should not affect the context")
+	}
+}
+
+func TestQuery_Metadata(t *testing.T) {
+	client := newFakeCLIClient(t, `echo '{"model":"claude-sonnet-4-20250514","content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":100,"output_tokens":10}}'`)
+	metadata := map[string]string{"feature": "search", "user": "u1"}
+
+	response, err := client.Query(WithQueryMetadata(context.Background(), metadata), userRequest("hi"))
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if got := response.Metadata[types.QueryMetadataKey]; !reflect.DeepEqual(got, metadata) {
+		t.Errorf("Response metadata = %v, want %v", got, metadata)
+	}
+
+	if _, err := client.Query(context.Background(), userRequest("hi")); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	bySearch := client.UsageTracker().UsageByLabel("feature")["search"]
+	if len(bySearch) != 1 || bySearch[0].Requests != 1 || bySearch[0].InputTokens != 100 {
+		t.Errorf("Expected the labeled query in the feature usage, got %+v", bySearch)
+	}
+	if usage := client.UsageTracker().Usage(); len(usage) != 1 || usage[0].Requests != 2 {
+		t.Errorf("Expected both queries in the model usage, got %+v", usage)
+	}
+}
+
+func TestQueryMessages_Metadata(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: done"`)
+	metadata := map[string]string{"feature": "review"}
+
+	result, err := client.QueryMessagesSync(context.Background(), "hi", &QueryOptions{Metadata: metadata})
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+	if len(result.Messages) < 2 {
+		t.Fatalf("Expected the prompt and a response, got %+v", result.Messages)
+	}
+	for _, message := range result.Messages {
+		if got := message.Metadata[types.QueryMetadataKey]; !reflect.DeepEqual(got, metadata) {
+			t.Errorf("Message %q metadata = %v, want %v", message.Content, got, metadata)
+		}
+	}
+	if got := result.Metadata[types.QueryMetadataKey]; !reflect.DeepEqual(got, metadata) {
+		t.Errorf("Result metadata = %v, want %v", got, metadata)
+	}
+
+	// Without metadata, messages are untouched
+	result, err = client.QueryMessagesSync(context.Background(), "hi", nil)
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+	if _, ok := result.Messages[0].Metadata[types.QueryMetadataKey]; ok {
+		t.Error("Expected no query metadata")
+	}
+}
+
+func TestQueryStream_MetadataEvents(t *testing.T) {
+	receiver := &webhookReceiver{t: t}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	client := newFakeCLIClient(t, `
+echo '{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Write","input":{"file_path":"out.txt","content":"x"}}]}}'
+echo '{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}'
+echo '{"type":"result","subtype":"success","result":"done"}'`)
+	enableWebhooks(t, client, &types.WebhookConfig{URL: server.URL})
+
+	var (
+		mu    sync.Mutex
+		files []types.FileAccessEvent
+	)
+	client.config.OnFileAccess = func(event types.FileAccessEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		files = append(files, event)
+	}
+
+	metadata := map[string]string{"experiment": "exp-7"}
+	stream, err := client.QueryStream(WithQueryMetadata(context.Background(), metadata), userRequest("write it"))
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if chunk.Done {
+			if got := chunk.Metadata[types.QueryMetadataKey]; !reflect.DeepEqual(got, metadata) {
+				t.Errorf("Final chunk metadata = %v, want %v", got, metadata)
+			}
+			break
+		}
+	}
+	stream.Close()
+	client.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(files) != 1 || !reflect.DeepEqual(files[0].Metadata, metadata) {
+		t.Errorf("Expected file events with the query metadata, got %+v", files)
+	}
+	if len(receiver.events) == 0 {
+		t.Fatal("Expected webhook events")
+	}
+	for _, event := range receiver.events {
+		if !reflect.DeepEqual(event.Metadata, metadata) {
+			t.Errorf("Webhook %s metadata = %v, want %v", event.Type, event.Metadata, metadata)
+		}
+	}
+}
+
+func TestRunJob_Metadata(t *testing.T) {
+	client := newFakeCLIClient(t, `echo '{"type":"result","subtype":"success","result":"done","num_turns":1}'`)
+	store := NewMemorySessionStore()
+	client.SetSessionStore(store)
+
+	ctx := WithQueryMetadata(context.Background(), map[string]string{"feature": "nightly"})
+	response, err := client.RunJob(ctx, "job-1", userRequest("run"))
+	if err != nil {
+		t.Fatalf("RunJob failed: %v", err)
+	}
+	if got := response.Metadata[types.QueryMetadataKey]; !reflect.DeepEqual(got, map[string]string{"feature": "nightly"}) {
+		t.Errorf("Response metadata = %v", got)
+	}
+
+	checkpoint, err := store.LoadCheckpoint(context.Background(), "job-1")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	if checkpoint.Metadata["feature"] != "nightly" {
+		t.Errorf("Checkpoint metadata = %v", checkpoint.Metadata)
+	}
+}
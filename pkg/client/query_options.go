@@ -0,0 +1,161 @@
+package client
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// BuiltinTools are the tools built into the claude CLI. Rules in
+// AllowedTools and DisallowedTools must name one of them or an MCP tool
+// ("mcp__server" or "mcp__server__tool"), optionally followed by a
+// parenthesized specifier such as "Bash(git diff:*)". Names may use "*"
+// wildcards; see ResolveTools.
+var BuiltinTools = []string{
+	"Bash", "BashOutput", "Edit", "ExitPlanMode", "Glob", "Grep", "KillShell", "LS",
+	"MultiEdit", "NotebookEdit", "NotebookRead", "Read", "SlashCommand", "Task",
+	"TodoWrite", "WebFetch", "WebSearch", "Write",
+}
+
+// Validate checks the options before a query starts the CLI and returns a
+// ValidationError listing every problem, or nil.
+func (o *QueryOptions) Validate() error {
+	if violations := o.violations(); len(violations) > 0 {
+		return sdkerrors.NewValidationErrorWithViolations(violations)
+	}
+	return nil
+}
+
+// violations returns the problems with the options.
+func (o *QueryOptions) violations() []sdkerrors.ValidationViolation {
+	if o == nil {
+		return nil
+	}
+
+	var violations []sdkerrors.ValidationViolation
+	add := func(field, code, message string, value any) {
+		violations = append(violations, sdkerrors.ValidationViolation{Field: field, Code: code, Message: message, Value: value})
+	}
+
+	if o.MaxTurns < 0 {
+		add("max_turns", "min", "max_turns cannot be negative; use 0 for no limit", o.MaxTurns)
+	}
+	if o.Timeout < 0 {
+		add("timeout", "min", "timeout cannot be negative; use 0 for no limit", o.Timeout)
+	}
+
+	switch o.PermissionMode {
+	case "", PermissionModeAsk, PermissionModeAcceptEdits, PermissionModeRejectEdits:
+	default:
+		add("permission_mode", "enum", `permission_mode must be "ask", "acceptEdits" or "rejectEdits"`, o.PermissionMode)
+	}
+
+	for i, rule := range o.AllowedTools {
+		if message := checkToolRule(rule); message != "" {
+			add(fmt.Sprintf("allowed_tools[%d]", i), "tool", message, rule)
+		}
+	}
+	for i, rule := range o.DisallowedTools {
+		if message := checkToolRule(rule); message != "" {
+			add(fmt.Sprintf("disallowed_tools[%d]", i), "tool", message, rule)
+		}
+		if containsString(o.AllowedTools, rule) {
+			add(fmt.Sprintf("disallowed_tools[%d]", i), "conflict",
+				fmt.Sprintf("%q is also in allowed_tools; remove it from one of the lists", rule), rule)
+		}
+	}
+
+	tools := make([]string, 0, len(o.ToolTimeouts))
+	for tool := range o.ToolTimeouts {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		if o.ToolTimeouts[tool] <= 0 {
+			add("tool_timeouts."+tool, "min", "tool timeouts must be positive", o.ToolTimeouts[tool])
+		}
+	}
+
+	return violations
+}
+
+// checkToolRule describes what is wrong with a permission rule, or returns
+// "" for a valid rule.
+func checkToolRule(rule string) string {
+	name := strings.TrimSpace(rule)
+	if open := strings.IndexByte(name, '('); open >= 0 {
+		if !strings.HasSuffix(name, ")") {
+			return fmt.Sprintf("tool rule %q is missing a closing parenthesis", rule)
+		}
+		name = name[:open]
+	}
+
+	switch {
+	case name == "":
+		return "tool name cannot be empty"
+	case strings.HasPrefix(name, "mcp__") && strings.Contains(name, "*"):
+		server := strings.TrimSuffix(strings.TrimPrefix(name, "mcp__"), "__*")
+		if server == "" || strings.ContainsAny(server, "*") || strings.Contains(server, "__") {
+			return fmt.Sprintf("MCP wildcard %q must cover a whole server, as in \"mcp__server__*\"", name)
+		}
+		return ""
+	case strings.Contains(name, "*"):
+		for _, tool := range BuiltinTools {
+			if matched, err := path.Match(name, tool); err == nil && matched {
+				return ""
+			}
+		}
+		return fmt.Sprintf("tool pattern %q matches no built-in tool", name)
+	case strings.HasPrefix(name, "mcp__"):
+		if strings.TrimPrefix(name, "mcp__") == "" {
+			return `MCP tool rules must name a server, as in "mcp__server" or "mcp__server__tool"`
+		}
+		return ""
+	case containsString(BuiltinTools, name):
+		return ""
+	}
+
+	for _, tool := range BuiltinTools {
+		if strings.EqualFold(tool, name) {
+			return fmt.Sprintf("unknown tool %q; did you mean %q?", name, tool)
+		}
+	}
+	return fmt.Sprintf("unknown tool %q; expected a built-in tool (see BuiltinTools) or an MCP tool such as \"mcp__server__tool\"", name)
+}
+
+// mcpViolations returns the problems with the enabled MCP servers, which
+// would otherwise only surface as CLI startup failures.
+func (c *ClaudeCodeClient) mcpViolations() []sdkerrors.ValidationViolation {
+	servers := c.mcpManager.GetEnabledServers()
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []sdkerrors.ValidationViolation
+	for _, name := range names {
+		server := servers[name]
+		field := "mcp_servers." + name
+		switch {
+		case server.IsRemote() && server.URL == "":
+			violations = append(violations, sdkerrors.ValidationViolation{Field: field + ".url", Code: "required", Message: "url is required for " + server.Type + " servers"})
+		case !server.IsRemote() && server.Command == "":
+			violations = append(violations, sdkerrors.ValidationViolation{Field: field + ".command", Code: "required", Message: "command is required for stdio servers; set Command or disable the server"})
+		}
+	}
+	return violations
+}
+
+// validateQuery checks query options and the client's MCP servers before
+// the CLI is started.
+func (c *ClaudeCodeClient) validateQuery(options *QueryOptions) error {
+	violations := append(options.violations(), c.mcpViolations()...)
+	if len(violations) > 0 {
+		return sdkerrors.NewValidationErrorWithViolations(violations)
+	}
+	return nil
+}
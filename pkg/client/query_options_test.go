@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestQueryOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *QueryOptions
+		fields  []string
+		message string
+	}{
+		{
+			name:    "valid",
+			options: &QueryOptions{MaxTurns: 3, AllowedTools: []string{"Read", "Bash(git diff:*)", "mcp__github__create_issue"}, DisallowedTools: []string{"Write"}},
+		},
+		{
+			name: "nil",
+		},
+		{
+			name:    "negative max turns",
+			options: &QueryOptions{MaxTurns: -1},
+			fields:  []string{"max_turns"},
+		},
+		{
+			name:    "unknown tool with suggestion",
+			options: &QueryOptions{AllowedTools: []string{"bash"}},
+			fields:  []string{"allowed_tools[0]"},
+			message: `did you mean "Bash"?`,
+		},
+		{
+			name:    "unknown tool",
+			options: &QueryOptions{DisallowedTools: []string{"Teleport"}},
+			fields:  []string{"disallowed_tools[0]"},
+			message: `unknown tool "Teleport"`,
+		},
+		{
+			name:    "malformed rule",
+			options: &QueryOptions{AllowedTools: []string{"Bash(npm test"}},
+			fields:  []string{"allowed_tools[0]"},
+			message: "closing parenthesis",
+		},
+		{
+			name:    "conflicting rules",
+			options: &QueryOptions{AllowedTools: []string{"Read", "Edit"}, DisallowedTools: []string{"Edit"}},
+			fields:  []string{"disallowed_tools[0]"},
+			message: `"Edit" is also in allowed_tools`,
+		},
+		{
+			name: "every problem",
+			options: &QueryOptions{
+				MaxTurns:       -2,
+				PermissionMode: "yolo",
+				AllowedTools:   []string{"Grepp"},
+				ToolTimeouts:   map[string]time.Duration{"Bash": 0},
+			},
+			fields: []string{"max_turns", "permission_mode", "allowed_tools[0]", "tool_timeouts.Bash"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("Expected valid options, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *sdkerrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+			var fields []string
+			for _, violation := range validationErr.Violations {
+				fields = append(fields, violation.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Expected violations for %v, got %v", tt.fields, fields)
+			}
+			if tt.message != "" && !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Expected error to contain %q, got %q", tt.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestQueryMessages_ValidatesBeforeStart(t *testing.T) {
+	client := newFakeCLIClient(t, `touch started; echo "handled $prompt"`)
+	client.mcpManager.servers["broken"] = &types.MCPServerConfig{Enabled: true}
+
+	_, err := client.QueryMessages(context.Background(), "hi", &QueryOptions{MaxTurns: -1})
+
+	var validationErr *sdkerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+	if len(validationErr.Violations) != 2 {
+		t.Errorf("Expected option and MCP violations, got %v", validationErr.Violations)
+	}
+	if !strings.Contains(err.Error(), "mcp_servers.broken.command") {
+		t.Errorf("Expected error to name the MCP server, got %q", err.Error())
+	}
+	if _, statErr := os.Stat(filepath.Join(client.currentWorkingDir(), "started")); statErr == nil {
+		t.Error("Expected the CLI not to be started")
+	}
+}
@@ -0,0 +1,46 @@
+package client
+
+import "context"
+
+// queryScope is where a query runs: the CLI session it continues, the
+// directories it may access and the tools it may use there. Sessions with
+// their own directories attach a scope to the query context, so concurrent
+// queries in different checkouts never depend on the client's defaults.
+type queryScope struct {
+	sessionID  string
+	workingDir string
+	addDirs    []string
+
+	// Tool rules of the query's options, added to the client's
+	allowedTools    []string
+	disallowedTools []string
+}
+
+type queryScopeKey struct{}
+
+// withQueryScope returns a context whose queries run in scope.
+func withQueryScope(ctx context.Context, scope queryScope) context.Context {
+	return context.WithValue(ctx, queryScopeKey{}, scope)
+}
+
+// defaultScope returns the client's session and directories.
+func (c *ClaudeCodeClient) defaultScope() queryScope {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return queryScope{sessionID: c.sessionID, workingDir: c.workingDir, addDirs: c.addDirs}
+}
+
+// scopeFrom returns the scope attached to ctx, or the client's defaults.
+func (c *ClaudeCodeClient) scopeFrom(ctx context.Context) queryScope {
+	if scope, ok := ctx.Value(queryScopeKey{}).(queryScope); ok {
+		return scope
+	}
+	return c.defaultScope()
+}
+
+// sessionScope returns the scope of a session's queries.
+func (c *ClaudeCodeClient) sessionScope(session *ClaudeCodeSession) queryScope {
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	return session.scopeLocked(c.defaultScope())
+}
@@ -0,0 +1,37 @@
+package client
+
+import "github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+
+// redact scrubs secrets from text with the configured redactor, if any.
+func (c *ClaudeCodeClient) redact(text string) string {
+	redactor := c.settings().Redactor
+	if redactor == nil || text == "" {
+		return text
+	}
+	return redactor.Redact(text)
+}
+
+// redactValue scrubs the strings of a decoded JSON value, returning a copy.
+func redactValue(redactor types.Redactor, value any) any {
+	if redactor == nil {
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		return redactor.Redact(v)
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			redacted[key] = redactValue(redactor, item)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(redactor, item)
+		}
+		return redacted
+	}
+	return value
+}
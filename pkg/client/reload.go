@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// DefaultConfigWatchInterval is how often WatchConfig checks the config file
+// for changes when no interval is given.
+const DefaultConfigWatchInterval = 2 * time.Second
+
+// ReloadConfig applies a new configuration to a running client. The change
+// takes effect for queries and sessions started afterwards; in-flight queries
+// keep their settings.
+//
+// Reloaded settings are the model, system prompt, token and temperature
+// limits, timeouts, tool timeouts, environment, environment policy, Bash
+// sandbox, web domain policy, extra CLI flags, CLI feature check, profiles
+// and the MCP server set. Settings tied to the client's identity (working
+// directory, session ID, CLI path and authentication) are left unchanged.
+func (c *ClaudeCodeClient) ReloadConfig(ctx context.Context, config *types.ClaudeCodeConfig) error {
+	if config == nil {
+		return sdkerrors.NewValidationError("config", "", "required", "configuration cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "CONFIG_RELOAD", "invalid configuration")
+	}
+
+	c.mu.RLock()
+	closed := c.closed
+	c.mu.RUnlock()
+	if closed {
+		return sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
+	}
+
+	// Swap the MCP servers first so an invalid server leaves everything unchanged
+	changed, err := c.mcpManager.replaceServers(config.MCPServers)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.updateSettings(func(current *types.ClaudeCodeConfig) {
+		if config.Model != "" {
+			current.Model = config.Model
+		}
+		current.System = config.System
+		current.MaxTokens = config.MaxTokens
+		current.Temperature = config.Temperature
+		current.Timeout = config.Timeout
+		current.ToolTimeouts = config.ToolTimeouts
+		current.Environment = config.Environment
+		current.EnvPolicy = config.EnvPolicy
+		current.BashSandbox = config.BashSandbox
+		current.WebDomains = config.WebDomains
+		current.ExtraArgs = config.ExtraArgs
+		current.CLIFeatureCheck = config.CLIFeatureCheck
+		current.Profiles = config.Profiles
+		current.DefaultProfile = config.DefaultProfile
+	})
+	c.mu.Unlock()
+
+	if changed {
+		return c.mcpManager.ApplyConfiguration(ctx)
+	}
+	return nil
+}
+
+// Reload re-reads the config file passed to WatchConfig and applies it with
+// ReloadConfig. Environment variables override the file, as with
+// types.LoadConfigFromEnvAndFile.
+func (c *ClaudeCodeClient) Reload(ctx context.Context) error {
+	c.mu.RLock()
+	watcher := c.configWatcher
+	c.mu.RUnlock()
+
+	if watcher == nil {
+		return sdkerrors.NewConfigurationError("config_path", "no config file is being watched; call WatchConfig first")
+	}
+	return c.reloadFile(ctx, watcher.path)
+}
+
+// WatchConfig watches a config file and reloads the client whenever it
+// changes, until ctx is cancelled or the client is closed. The file is polled
+// every interval (DefaultConfigWatchInterval when zero). onReload, if not
+// nil, is called after each reload attempt with its error; a failed reload
+// leaves the previous configuration in place.
+//
+// Example usage:
+//
+//	err := claude.WatchConfig(ctx, "/etc/myapp/claude.yaml", 0, func(err error) {
+//		if err != nil {
+//			log.Printf("claude config reload failed: %v", err)
+//		}
+//	})
+func (c *ClaudeCodeClient) WatchConfig(ctx context.Context, path string, interval time.Duration, onReload func(err error)) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "CONFIG_WATCH", "failed to watch config file")
+	}
+
+	if interval <= 0 {
+		interval = DefaultConfigWatchInterval
+	}
+
+	watcher := &configWatcher{
+		path:     path,
+		modTime:  info.ModTime(),
+		size:     info.Size(),
+		onReload: onReload,
+		stopCh:   make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return sdkerrors.NewInternalError("CLIENT_CLOSED", "client has been closed")
+	}
+	if c.configWatcher != nil {
+		c.configWatcher.stop()
+	}
+	c.configWatcher = watcher
+	c.mu.Unlock()
+
+	go watcher.run(ctx, c, interval)
+	return nil
+}
+
+// reloadFile loads the config file at path and applies it.
+func (c *ClaudeCodeClient) reloadFile(ctx context.Context, path string) error {
+	config, err := types.LoadConfigFromEnvAndFile(path)
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "CONFIG_RELOAD", "failed to load config file")
+	}
+	return c.ReloadConfig(ctx, config)
+}
+
+// configWatcher polls a config file for changes.
+type configWatcher struct {
+	path     string
+	modTime  time.Time
+	size     int64
+	onReload func(err error)
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// stop ends the polling loop without waiting for an in-progress reload.
+func (w *configWatcher) stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+}
+
+// run polls the file every interval and reloads the client when it changes.
+func (w *configWatcher) run(ctx context.Context, c *ClaudeCodeClient, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			info, err := os.Stat(w.path)
+			if err != nil || (info.ModTime().Equal(w.modTime) && info.Size() == w.size) {
+				continue // Missing files are often mid-rename; keep the current config
+			}
+			w.modTime, w.size = info.ModTime(), info.Size()
+
+			err = c.reloadFile(ctx, w.path)
+			if w.onReload != nil {
+				w.onReload(err)
+			}
+		case <-w.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
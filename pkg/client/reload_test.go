@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestReloadConfig(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	workingDir := client.workingDir
+
+	config := types.NewClaudeCodeConfig()
+	config.Model = "claude-opus-4"
+	config.WorkingDirectory = t.TempDir()
+	config.ToolTimeouts = map[string]time.Duration{"Bash": time.Second}
+	config.Profiles = map[string]*types.Profile{"ci": {AllowedTools: []string{"Read"}}}
+	config.MCPServers = map[string]*types.MCPServerConfig{
+		"fs": {Command: "npx", Args: []string{"server-filesystem"}, Enabled: true},
+	}
+
+	if err := client.ReloadConfig(context.Background(), config); err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+
+	if client.config.Model != "claude-opus-4" || client.config.ToolTimeouts["Bash"] != time.Second {
+		t.Errorf("Settings not reloaded: %+v", client.config)
+	}
+	if client.workingDir != workingDir || client.config.WorkingDirectory != workingDir {
+		t.Error("Expected the working directory to be left unchanged")
+	}
+	if names := client.Profiles(); len(names) != 1 || names[0] != "ci" {
+		t.Errorf("Profiles not reloaded: %v", names)
+	}
+	if servers := client.ListMCPServers(); len(servers) != 1 || servers["fs"] == nil {
+		t.Errorf("MCP servers not reloaded: %v", servers)
+	}
+	data, err := os.ReadFile(filepath.Join(workingDir, ".claude", "mcp.json"))
+	if err != nil || !strings.Contains(string(data), "server-filesystem") {
+		t.Errorf("MCP configuration not applied: %s (%v)", data, err)
+	}
+
+	// Invalid configs leave the current settings in place
+	config.MCPServers = map[string]*types.MCPServerConfig{"bad": {Enabled: true}}
+	config.Model = "claude-sonnet-4"
+	if err := client.ReloadConfig(context.Background(), config); err == nil {
+		t.Error("Expected invalid MCP server to fail the reload")
+	}
+	if servers := client.ListMCPServers(); servers["fs"] == nil || client.config.Model != "claude-opus-4" {
+		t.Errorf("Expected previous settings to be kept, got %v and model %q", servers, client.config.Model)
+	}
+
+	config.Temperature = 5
+	if err := client.ReloadConfig(context.Background(), config); err == nil {
+		t.Error("Expected invalid config to be rejected")
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+
+	path := filepath.Join(t.TempDir(), "claude.yaml")
+	if err := os.WriteFile(path, []byte("model: claude-opus-4\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := client.Reload(context.Background()); err == nil {
+		t.Error("Expected Reload to fail before WatchConfig")
+	}
+
+	reloads := make(chan error, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := client.WatchConfig(ctx, path, 10*time.Millisecond, func(err error) { reloads <- err }); err != nil {
+		t.Fatalf("WatchConfig failed: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("model: claude-sonnet-4\nextra_args:\n  verbose: null\n"), 0600); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+
+	select {
+	case err := <-reloads:
+		if err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the config to be reloaded")
+	}
+
+	client.mu.RLock()
+	model, extra := client.config.Model, client.config.ExtraArgs
+	client.mu.RUnlock()
+	if model != "claude-sonnet-4" || len(extra) != 1 {
+		t.Errorf("Config not reloaded: model %q, extra args %v", model, extra)
+	}
+
+	// A broken file is reported and ignored
+	if err := os.WriteFile(path, []byte("model: [\n"), 0600); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+	select {
+	case err := <-reloads:
+		if err == nil {
+			t.Error("Expected an error for the broken config file")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the reload attempt")
+	}
+
+	if err := client.Reload(context.Background()); err == nil {
+		t.Error("Expected Reload of the broken file to fail")
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// hookBashSandbox names the Bash sandbox hook.
+const hookBashSandbox = "bash-sandbox"
+
+// bashSandboxHook is the configuration passed to the Bash sandbox hook.
+type bashSandboxHook struct {
+	Sandbox *types.BashSandbox `json:"sandbox"`
+	HostDir string             `json:"host_dir"`
+}
+
+// runBashSandboxHook reads a PreToolUse hook event and writes a response that
+// replaces the Bash command with its sandboxed equivalent.
+func runBashSandboxHook(config []byte, input io.Reader, output io.Writer) error {
+	var hook bashSandboxHook
+	if err := json.Unmarshal(config, &hook); err != nil {
+		return fmt.Errorf("invalid hook configuration: %w", err)
+	}
+	if hook.Sandbox == nil {
+		return fmt.Errorf("missing sandbox configuration")
+	}
+	if err := hook.Sandbox.Validate(); err != nil {
+		return err
+	}
+
+	var event preToolUseEvent
+	if err := json.NewDecoder(input).Decode(&event); err != nil {
+		return fmt.Errorf("invalid hook input: %w", err)
+	}
+	command, ok := event.ToolInput["command"].(string)
+	if event.ToolName != "Bash" || !ok {
+		return fmt.Errorf("expected a Bash tool call, got %s", event.ToolName)
+	}
+
+	// Keep the other inputs, such as timeout and description
+	updated := make(map[string]any, len(event.ToolInput))
+	for key, value := range event.ToolInput {
+		updated[key] = value
+	}
+	updated["command"] = shellJoin(hook.Sandbox.Command(command, hook.HostDir))
+
+	return json.NewEncoder(output).Encode(map[string]any{
+		"hookSpecificOutput": map[string]any{
+			"hookEventName": "PreToolUse",
+			"updatedInput":  updated,
+		},
+	})
+}
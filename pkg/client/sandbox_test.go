@@ -0,0 +1,116 @@
+package client
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestRunBashSandboxHook(t *testing.T) {
+	config, _ := json.Marshal(bashSandboxHook{
+		Sandbox: &types.BashSandbox{Image: "alpine", Network: "none"},
+		HostDir: "/home/me/my project",
+	})
+	input := `{"hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"echo 'hi' && ls","timeout":5000}}`
+
+	var output strings.Builder
+	if err := runBashSandboxHook(config, strings.NewReader(input), &output); err != nil {
+		t.Fatalf("runBashSandboxHook failed: %v", err)
+	}
+
+	var response struct {
+		HookSpecificOutput struct {
+			HookEventName string         `json:"hookEventName"`
+			UpdatedInput  map[string]any `json:"updatedInput"`
+		} `json:"hookSpecificOutput"`
+	}
+	if err := json.Unmarshal([]byte(output.String()), &response); err != nil {
+		t.Fatalf("Invalid hook output %q: %v", output.String(), err)
+	}
+
+	want := `docker run --rm -i -v '/home/me/my project:/workspace' -w /workspace --network none alpine sh -c 'echo '\''hi'\'' && ls'`
+	updated := response.HookSpecificOutput.UpdatedInput
+	if response.HookSpecificOutput.HookEventName != "PreToolUse" || updated["command"] != want {
+		t.Errorf("Unexpected hook output: %s", output.String())
+	}
+	if updated["timeout"] != float64(5000) {
+		t.Errorf("Expected other tool inputs to be kept, got %v", updated)
+	}
+
+	for _, bad := range []struct{ config, input string }{
+		{string(config), `{"tool_name":"Write","tool_input":{"file_path":"x"}}`},
+		{string(config), `not json`},
+		{`{"host_dir":"/tmp"}`, input},
+		{`{"sandbox":{"image":"alpine","container":"dev"}}`, input},
+	} {
+		if err := runBashSandboxHook([]byte(bad.config), strings.NewReader(bad.input), &output); err == nil {
+			t.Errorf("Expected error for config %s and input %s", bad.config, bad.input)
+		}
+	}
+}
+
+func TestSandboxArgs(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+
+	args, err := client.buildClaudeArgs(&types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}, false)
+	if err != nil {
+		t.Fatalf("buildClaudeArgs failed: %v", err)
+	}
+	if strings.Contains(strings.Join(args, " "), "--settings") {
+		t.Errorf("Expected no settings without a sandbox: %v", args)
+	}
+
+	client.config.BashSandbox = &types.BashSandbox{Container: "dev"}
+	args, err = client.buildClaudeArgs(&types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}, false)
+	if err != nil {
+		t.Fatalf("buildClaudeArgs failed: %v", err)
+	}
+
+	var settings string
+	for i, arg := range args {
+		if arg == "--settings" && i+1 < len(args) {
+			settings = args[i+1]
+		}
+	}
+	var parsed struct {
+		Hooks struct {
+			PreToolUse []struct {
+				Matcher string `json:"matcher"`
+				Hooks   []struct {
+					Type    string `json:"type"`
+					Command string `json:"command"`
+				} `json:"hooks"`
+			} `json:"PreToolUse"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal([]byte(settings), &parsed); err != nil || len(parsed.Hooks.PreToolUse) != 1 {
+		t.Fatalf("Unexpected settings %q: %v", settings, err)
+	}
+	matcher := parsed.Hooks.PreToolUse[0]
+	if matcher.Matcher != "Bash" || len(matcher.Hooks) != 1 || matcher.Hooks[0].Type != "command" {
+		t.Fatalf("Unexpected hook settings: %s", settings)
+	}
+
+	// The CLI runs the hook command through a shell, re-executing this binary
+	hook := exec.Command("sh", "-c", matcher.Hooks[0].Command) // #nosec G204 - hook command built by the test
+	hook.Stdin = strings.NewReader(`{"tool_name":"Bash","tool_input":{"command":"go test ./..."}}`)
+	output, err := hook.Output()
+	if err != nil {
+		t.Fatalf("Hook command failed: %v", err)
+	}
+	if !strings.Contains(string(output), `"command":"docker exec -i dev sh -c 'go test ./...'"`) {
+		t.Errorf("Unexpected hook output: %s", output)
+	}
+
+	// A failing hook exits with code 2 so the CLI blocks the command
+	hook = exec.Command("sh", "-c", matcher.Hooks[0].Command) // #nosec G204 - hook command built by the test
+	hook.Stdin = strings.NewReader(`garbage`)
+	if err := hook.Run(); err == nil {
+		t.Error("Expected hook to fail on invalid input")
+	} else if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 2 {
+		t.Errorf("Expected exit code 2, got %v", err)
+	}
+}
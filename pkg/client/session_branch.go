@@ -0,0 +1,179 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// Branch forks the conversation after its first fromTurn turns into a new
+// session, leaving this session unchanged. The branch's next query takes
+// the place of turn fromTurn+1, so applications can regenerate a reply or
+// try a different follow-up while keeping the original. A turn is a prompt
+// and everything Claude did to answer it; fromTurn 0 branches before the
+// first prompt.
+//
+// The branch is made by replaying the CLI's transcript of this session,
+// cut before turn fromTurn+1, into a transcript for a new session ID. The
+// branch keeps this session's model and directories, and its metadata
+// records "branched_from" and "branched_at_turn".
+func (s *ClaudeCodeSession) Branch(ctx context.Context, fromTurn int) (*ClaudeCodeSession, error) {
+	s.mu.RLock()
+	closed := s.closed
+	projectDir, addDirs, model := s.projectDir, s.addDirs, s.model
+	s.mu.RUnlock()
+
+	if closed {
+		return nil, sdkerrors.NewInternalError("SESSION_CLOSED", "session has been closed")
+	}
+	if fromTurn < 0 {
+		return nil, sdkerrors.NewValidationError("fromTurn", fmt.Sprint(fromTurn), "0 or more", "turn cannot be negative")
+	}
+
+	var lines [][]byte
+	var dir string
+	if fromTurn > 0 {
+		path, err := s.transcriptPath(ctx)
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Dir(path)
+		if lines, err = transcriptTurns(path, fromTurn); err != nil {
+			return nil, err
+		}
+	}
+
+	branch, err := s.manager.CreateSession(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) > 0 {
+		if err := writeBranchTranscript(filepath.Join(dir, branch.ID+".jsonl"), branch.ID, lines); err != nil {
+			_ = s.manager.CloseSession(branch.ID) // Ignore error, the branch is unusable
+			return nil, err
+		}
+	}
+
+	branch.mu.Lock()
+	branch.projectDir = projectDir
+	branch.addDirs = addDirs
+	branch.model = model
+	branch.started = len(lines) > 0
+	branch.metadata["model"] = model
+	branch.metadata["branched_from"] = s.ID
+	branch.metadata["branched_at_turn"] = fromTurn
+	if projectDir != "" {
+		branch.metadata["project_dir"] = projectDir
+	}
+	branch.mu.Unlock()
+
+	return branch, nil
+}
+
+// transcriptPath returns the file of this session's transcript in the CLI's
+// session history.
+func (s *ClaudeCodeSession) transcriptPath(ctx context.Context) (string, error) {
+	paths, err := s.client.storedSessionFiles(ctx, s.ID)
+	if err != nil {
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", sdkerrors.NewValidationError("sessionID", s.ID, "stored session", "session not found in session history")
+	}
+	return paths[0], nil
+}
+
+// isPromptEntry reports whether a transcript line is a prompt the user
+// typed, which starts a turn, rather than a tool result, command output or
+// a subagent's message.
+func isPromptEntry(entry *transcriptEntry) bool {
+	return entry.Type == "user" && !entry.IsMeta && !entry.IsSidechain && promptText(entry.Message) != ""
+}
+
+// transcriptTurns returns the lines of a CLI session transcript up to the
+// start of turn fromTurn+1. It fails if the transcript has fewer than
+// fromTurn turns.
+func transcriptTurns(path string, fromTurn int) ([][]byte, error) {
+	var lines [][]byte
+	turns := 0
+	err := walkTranscript(path, func(line []byte, entry *transcriptEntry) bool {
+		if isPromptEntry(entry) {
+			if turns == fromTurn {
+				return false
+			}
+			turns++
+		}
+		lines = append(lines, line)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if turns < fromTurn {
+		return nil, sdkerrors.NewValidationError("fromTurn", fmt.Sprint(fromTurn), fmt.Sprintf("at most %d", turns), "session does not have that many turns")
+	}
+	return lines, nil
+}
+
+// walkTranscript calls fn for each line of a CLI session transcript until
+// fn returns false. Lines that cannot be parsed are skipped, since the CLI
+// may be appending to the file.
+func walkTranscript(path string, fn func(line []byte, entry *transcriptEntry) bool) error {
+	file, err := os.Open(path) // #nosec G304 - path is in the CLI's session history
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_HISTORY", "failed to read session history")
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var entry transcriptEntry
+			if json.Unmarshal(trimmed, &entry) == nil && !fn(trimmed, &entry) {
+				return nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_HISTORY", "failed to read session history")
+		}
+	}
+}
+
+// writeBranchTranscript writes transcript lines as the transcript of the
+// session sessionID.
+func writeBranchTranscript(path, sessionID string, lines [][]byte) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(line, &fields); err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_BRANCH", "failed to copy session history")
+		}
+		if _, ok := fields["sessionId"]; ok {
+			fields["sessionId"], _ = json.Marshal(sessionID)
+			var err error
+			if line, err = json.Marshal(fields); err != nil {
+				return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_BRANCH", "failed to copy session history")
+			}
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_BRANCH", "failed to write branch session history")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+func TestClaudeCodeSession_Branch(t *testing.T) {
+	client, configDir := newStoredSessionsClient(t)
+	ctx := context.Background()
+
+	session, err := client.CreateSession(ctx, "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	project := t.TempDir()
+	if err := session.SetProjectDirectory(project); err != nil {
+		t.Fatal(err)
+	}
+	writeTranscript(t, configDir, "-src-api", session.ID,
+		`{"type":"user","sessionId":"`+session.ID+`","isMeta":true,"message":{"role":"user","content":"<command-name>/init</command-name>"}}`,
+		`{"type":"user","sessionId":"`+session.ID+`","uuid":"u1","message":{"role":"user","content":"Write a haiku"}}`,
+		`{"type":"assistant","sessionId":"`+session.ID+`","uuid":"a1","message":{"role":"assistant","content":[{"type":"text","text":"An old silent pond"}]}}`,
+		`{"type":"user","sessionId":"`+session.ID+`","uuid":"r1","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+		`{"type":"user","sessionId":"`+session.ID+`","uuid":"u2","message":{"role":"user","content":[{"type":"text","text":"Make it rhyme"}]}}`,
+		`{"type":"assistant","sessionId":"`+session.ID+`","uuid":"a2","message":{"role":"assistant","content":[{"type":"text","text":"A frog jumps in"}]}}`,
+	)
+
+	branch, err := session.Branch(ctx, 1)
+	if err != nil {
+		t.Fatalf("Branch failed: %v", err)
+	}
+	if branch.ID == session.ID || branch.GetProjectDirectory() != project {
+		t.Errorf("Expected a new session in %s, got %s in %s", project, branch.ID, branch.GetProjectDirectory())
+	}
+	if metadata := branch.GetMetadata(); metadata["branched_from"] != session.ID || metadata["branched_at_turn"] != 1 {
+		t.Errorf("Unexpected branch metadata: %v", metadata)
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "projects", "-src-api", branch.ID+".jsonl"))
+	if err != nil {
+		t.Fatalf("Expected a branch transcript: %v", err)
+	}
+	transcript := string(data)
+	if strings.Count(transcript, "\n") != 4 || strings.Contains(transcript, "Make it rhyme") || strings.Contains(transcript, session.ID) {
+		t.Errorf("Expected the first turn under the branch's ID, got:\n%s", transcript)
+	}
+	if !strings.Contains(transcript, `"sessionId":"`+branch.ID+`"`) || !strings.Contains(transcript, `"uuid":"r1"`) {
+		t.Errorf("Expected every line of the first turn, got:\n%s", transcript)
+	}
+
+	stored, err := client.GetStoredSession(ctx, branch.ID)
+	if err != nil || stored.Messages != 3 || stored.FirstPrompt != "Write a haiku" {
+		t.Errorf("Expected the branch in the session history, got %+v, %v", stored, err)
+	}
+
+	// The whole conversation, or none of it, can be branched too
+	if whole, err := session.Branch(ctx, 2); err != nil || !whole.started {
+		t.Errorf("Branch(2) = %v, %v", whole, err)
+	}
+	fresh, err := session.Branch(ctx, 0)
+	if err != nil || fresh.started {
+		t.Errorf("Branch(0) = %v, %v", fresh, err)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "projects", "-src-api", fresh.ID+".jsonl")); !os.IsNotExist(err) {
+		t.Errorf("Expected no transcript for a branch before the first turn, got %v", err)
+	}
+}
+
+func TestClaudeCodeSession_BranchErrors(t *testing.T) {
+	client, configDir := newStoredSessionsClient(t)
+	ctx := context.Background()
+
+	session, err := client.CreateSession(ctx, "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	var validation *sdkerrors.ValidationError
+	if _, err := session.Branch(ctx, 1); !errors.As(err, &validation) {
+		t.Errorf("Expected a validation error without history, got %v", err)
+	}
+
+	writeTranscript(t, configDir, "-src-api", session.ID,
+		`{"type":"user","sessionId":"`+session.ID+`","message":{"role":"user","content":"hello"}}`,
+	)
+	if _, err := session.Branch(ctx, 2); !errors.As(err, &validation) {
+		t.Errorf("Expected a validation error past the last turn, got %v", err)
+	}
+	if _, err := session.Branch(ctx, -1); !errors.As(err, &validation) {
+		t.Errorf("Expected a validation error for a negative turn, got %v", err)
+	}
+
+	_ = session.Close()
+	if _, err := session.Branch(ctx, 0); err == nil {
+		t.Error("Expected an error for a closed session")
+	}
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// RegenerateOptions overrides the parameters of a regenerated turn. Empty
+// fields keep the session's settings.
+type RegenerateOptions struct {
+	// Model answers the turn instead of the session's model, and remains
+	// the model of the branch holding the alternative
+	Model string
+
+	// Temperature is sent with the regenerated request when set
+	Temperature *float64
+
+	// SystemPrompt is appended to the system prompt of the regenerated turn
+	SystemPrompt string
+}
+
+// Regeneration is an alternative response to a session's last turn, tagged
+// with the turn and the original reply for comparison.
+type Regeneration struct {
+	// Turn is the regenerated turn, counting from 1, and Prompt its prompt
+	Turn   int
+	Prompt string
+
+	// OriginalSessionID is the session whose turn was regenerated, and
+	// Original the text of its reply
+	OriginalSessionID string
+	Original          string
+
+	// Response is the alternative response. Its Metadata records
+	// "regenerated_from" and "regenerated_turn".
+	Response *types.QueryResponse
+
+	// Session is the branch holding the alternative; continue it to adopt
+	// the alternative, or close it to discard it
+	Session *ClaudeCodeSession
+
+	// Overrides are the parameters the turn was regenerated with
+	Overrides RegenerateOptions
+}
+
+// Regenerate answers the session's last prompt again with overrides, in a
+// branch made with Branch, and returns the alternative alongside the
+// original reply. The session itself is unchanged.
+func (s *ClaudeCodeSession) Regenerate(ctx context.Context, overrides RegenerateOptions) (*Regeneration, error) {
+	path, err := s.transcriptPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	turn, prompt, original, err := lastTranscriptTurn(path)
+	if err != nil {
+		return nil, err
+	}
+	if turn == 0 {
+		return nil, sdkerrors.NewValidationError("sessionID", s.ID, "session with a prompt", "session has no turn to regenerate")
+	}
+
+	branch, err := s.Branch(ctx, turn-1)
+	if err != nil {
+		return nil, err
+	}
+	if overrides.Model != "" {
+		branch.mu.Lock()
+		branch.model = overrides.Model
+		branch.metadata["model"] = overrides.Model
+		branch.mu.Unlock()
+	}
+
+	request := &types.QueryRequest{
+		System:   overrides.SystemPrompt,
+		Messages: []types.Message{{Role: types.RoleUser, Content: prompt}},
+	}
+	if overrides.Temperature != nil {
+		request.Temperature = *overrides.Temperature
+	}
+	response, err := branch.Query(ctx, request)
+	if err != nil {
+		_ = s.manager.CloseSession(branch.ID) // Ignore error, the branch is discarded
+		return nil, err
+	}
+
+	if response.Metadata == nil {
+		response.Metadata = make(map[string]any)
+	}
+	response.Metadata["regenerated_from"] = s.ID
+	response.Metadata["regenerated_turn"] = turn
+
+	return &Regeneration{
+		Turn:              turn,
+		Prompt:            prompt,
+		OriginalSessionID: s.ID,
+		Original:          original,
+		Response:          response,
+		Session:           branch,
+		Overrides:         overrides,
+	}, nil
+}
+
+// lastTranscriptTurn returns the number of turns in a CLI session
+// transcript, with the prompt of the last turn and the text of its final
+// reply.
+func lastTranscriptTurn(path string) (int, string, string, error) {
+	turns := 0
+	var prompt, reply string
+	err := walkTranscript(path, func(line []byte, entry *transcriptEntry) bool {
+		switch {
+		case isPromptEntry(entry):
+			turns++
+			prompt, reply = transcriptText(entry.Message), ""
+		case entry.Type == "assistant" && !entry.IsSidechain:
+			if text := transcriptText(entry.Message); text != "" {
+				reply = text
+			}
+		}
+		return true
+	})
+	return turns, prompt, reply, err
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+func TestClaudeCodeSession_Regenerate(t *testing.T) {
+	client := newFakeCLIClient(t, `
+model=default
+while [ $# -gt 0 ]; do
+	case "$1" in --model) model="$2" ;; esac
+	shift
+done
+echo "[$model] $prompt"`)
+	configDir := t.TempDir()
+	client.config.Environment = map[string]string{"CLAUDE_CONFIG_DIR": configDir}
+	ctx := context.Background()
+
+	session, err := client.CreateSession(ctx, "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	writeTranscript(t, configDir, "-src-api", session.ID,
+		`{"type":"user","sessionId":"`+session.ID+`","message":{"role":"user","content":"Name a color"}}`,
+		`{"type":"assistant","sessionId":"`+session.ID+`","message":{"role":"assistant","content":[{"type":"text","text":"Blue"}]}}`,
+		`{"type":"user","sessionId":"`+session.ID+`","message":{"role":"user","content":[{"type":"text","text":"Name a fruit"}]}}`,
+		`{"type":"assistant","sessionId":"`+session.ID+`","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Read","input":{}}]}}`,
+		`{"type":"user","sessionId":"`+session.ID+`","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"fruits.txt"}]}}`,
+		`{"type":"assistant","sessionId":"`+session.ID+`","message":{"role":"assistant","content":[{"type":"text","text":"Apple"}]}}`,
+	)
+
+	regeneration, err := session.Regenerate(ctx, RegenerateOptions{Model: "claude-haiku"})
+	if err != nil {
+		t.Fatalf("Regenerate failed: %v", err)
+	}
+	if regeneration.Turn != 2 || regeneration.Prompt != "Name a fruit" || regeneration.Original != "Apple" || regeneration.OriginalSessionID != session.ID {
+		t.Errorf("Unexpected regeneration: %+v", regeneration)
+	}
+	if text := regeneration.Response.GetTextContent(); text != "[claude-haiku] Name a fruit" {
+		t.Errorf("Expected the prompt answered by the override model, got %q", text)
+	}
+	if metadata := regeneration.Response.Metadata; metadata["regenerated_from"] != session.ID || metadata["regenerated_turn"] != 2 {
+		t.Errorf("Unexpected response metadata: %v", metadata)
+	}
+
+	branch := regeneration.Session
+	if branch.ID == session.ID || branch.GetMetadata()["branched_at_turn"] != 1 || branch.GetMetadata()["model"] != "claude-haiku" {
+		t.Errorf("Expected a branch after the first turn, got %s %v", branch.ID, branch.GetMetadata())
+	}
+}
+
+func TestClaudeCodeSession_RegenerateWithoutTurns(t *testing.T) {
+	client, configDir := newStoredSessionsClient(t)
+	ctx := context.Background()
+
+	session, err := client.CreateSession(ctx, "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	writeTranscript(t, configDir, "-src-api", session.ID, `{"type":"summary","summary":"Empty"}`)
+
+	var validation *sdkerrors.ValidationError
+	if _, err := session.Regenerate(ctx, RegenerateOptions{}); !errors.As(err, &validation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
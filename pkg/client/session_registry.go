@@ -0,0 +1,310 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// SessionRegistry maps external conversation IDs, such as a ticket number or
+// a chat thread ID, to the UUID session IDs the Claude Code CLI requires. A
+// session manager with a registry accepts either kind of ID; each new
+// external ID is given a random session ID that the registry remembers, so
+// the same conversation is resumed whenever the external ID is used again.
+// Implementations must be safe for concurrent use.
+type SessionRegistry interface {
+	// Register maps an external ID to a session ID, replacing any earlier
+	// mapping of either ID
+	Register(ctx context.Context, externalID, sessionID string) error
+
+	// SessionID returns the session ID mapped to an external ID
+	SessionID(ctx context.Context, externalID string) (string, bool, error)
+
+	// ExternalID returns the external ID mapped to a session ID
+	ExternalID(ctx context.Context, sessionID string) (string, bool, error)
+
+	// Unregister removes the mapping of an external ID, if there is one
+	Unregister(ctx context.Context, externalID string) error
+}
+
+// MemorySessionRegistry keeps mappings in memory. Mappings are lost when the
+// process exits; use FileSessionRegistry to keep them across restarts.
+type MemorySessionRegistry struct {
+	sessions  map[string]string // external ID to session ID
+	externals map[string]string // session ID to external ID
+	mu        sync.RWMutex
+}
+
+// NewMemorySessionRegistry creates an empty in-memory registry.
+func NewMemorySessionRegistry() *MemorySessionRegistry {
+	return &MemorySessionRegistry{
+		sessions:  make(map[string]string),
+		externals: make(map[string]string),
+	}
+}
+
+// Register implements SessionRegistry.
+func (r *MemorySessionRegistry) Register(ctx context.Context, externalID, sessionID string) error {
+	if err := validateRegistration(externalID, sessionID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registerLocked(externalID, sessionID)
+	return nil
+}
+
+// SessionID implements SessionRegistry.
+func (r *MemorySessionRegistry) SessionID(ctx context.Context, externalID string) (string, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessionID, ok := r.sessions[externalID]
+	return sessionID, ok, nil
+}
+
+// ExternalID implements SessionRegistry.
+func (r *MemorySessionRegistry) ExternalID(ctx context.Context, sessionID string) (string, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	externalID, ok := r.externals[sessionID]
+	return externalID, ok, nil
+}
+
+// Unregister implements SessionRegistry.
+func (r *MemorySessionRegistry) Unregister(ctx context.Context, externalID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unregisterLocked(externalID)
+	return nil
+}
+
+// registerLocked records a mapping, dropping any earlier mapping of either ID
+// so that the two maps stay inverses of each other.
+func (r *MemorySessionRegistry) registerLocked(externalID, sessionID string) {
+	r.unregisterLocked(externalID)
+	if previous, ok := r.externals[sessionID]; ok {
+		delete(r.sessions, previous)
+	}
+	r.sessions[externalID] = sessionID
+	r.externals[sessionID] = externalID
+}
+
+// unregisterLocked removes the mapping of an external ID.
+func (r *MemorySessionRegistry) unregisterLocked(externalID string) {
+	if sessionID, ok := r.sessions[externalID]; ok {
+		delete(r.externals, sessionID)
+		delete(r.sessions, externalID)
+	}
+}
+
+// FileSessionRegistry keeps mappings in memory and in a JSON file, so that
+// external IDs resume the same conversations after the host process
+// restarts. The file is replaced atomically on every change.
+type FileSessionRegistry struct {
+	path   string
+	memory *MemorySessionRegistry
+}
+
+// sessionRegistryFile is the on-disk form of a FileSessionRegistry.
+type sessionRegistryFile struct {
+	Sessions map[string]string `json:"sessions"`
+}
+
+// NewFileSessionRegistry creates a registry stored at path, loading the
+// mappings already there. The file and its directory are created on the
+// first change.
+func NewFileSessionRegistry(path string) (*FileSessionRegistry, error) {
+	r := &FileSessionRegistry{path: path, memory: NewMemorySessionRegistry()}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is chosen by the caller
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "SESSION_REGISTRY_LOAD", "failed to read session registry")
+	}
+
+	var file sessionRegistryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "SESSION_REGISTRY_LOAD", "failed to decode session registry")
+	}
+	for externalID, sessionID := range file.Sessions {
+		r.memory.registerLocked(externalID, sessionID)
+	}
+	return r, nil
+}
+
+// Register implements SessionRegistry.
+func (r *FileSessionRegistry) Register(ctx context.Context, externalID, sessionID string) error {
+	if err := validateRegistration(externalID, sessionID); err != nil {
+		return err
+	}
+
+	r.memory.mu.Lock()
+	defer r.memory.mu.Unlock()
+	r.memory.registerLocked(externalID, sessionID)
+	return r.saveLocked()
+}
+
+// SessionID implements SessionRegistry.
+func (r *FileSessionRegistry) SessionID(ctx context.Context, externalID string) (string, bool, error) {
+	return r.memory.SessionID(ctx, externalID)
+}
+
+// ExternalID implements SessionRegistry.
+func (r *FileSessionRegistry) ExternalID(ctx context.Context, sessionID string) (string, bool, error) {
+	return r.memory.ExternalID(ctx, sessionID)
+}
+
+// Unregister implements SessionRegistry.
+func (r *FileSessionRegistry) Unregister(ctx context.Context, externalID string) error {
+	r.memory.mu.Lock()
+	defer r.memory.mu.Unlock()
+
+	if _, ok := r.memory.sessions[externalID]; !ok {
+		return nil
+	}
+	r.memory.unregisterLocked(externalID)
+	return r.saveLocked()
+}
+
+// saveLocked writes the mappings to the registry file.
+func (r *FileSessionRegistry) saveLocked() error {
+	data, err := json.MarshalIndent(sessionRegistryFile{Sessions: r.memory.sessions}, "", "  ")
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_REGISTRY_ENCODE", "failed to encode session registry")
+	}
+
+	dir := filepath.Dir(r.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "SESSION_REGISTRY_DIR", "failed to create session registry directory")
+	}
+	tmp, err := os.CreateTemp(dir, ".sessions-*")
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_REGISTRY_SAVE", "failed to save session registry")
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_REGISTRY_SAVE", "failed to save session registry")
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_REGISTRY_SAVE", "failed to save session registry")
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_REGISTRY_SAVE", "failed to save session registry")
+	}
+	return nil
+}
+
+// validateRegistration rejects mappings a registry cannot hold.
+func validateRegistration(externalID, sessionID string) error {
+	if externalID == "" {
+		return sdkerrors.NewValidationError("externalID", "", "required", "external session ID cannot be empty")
+	}
+	if !IsValidUUID(sessionID) {
+		return sdkerrors.NewValidationError("sessionID", sessionID, "uuid", "session ID must be a valid UUID")
+	}
+	return nil
+}
+
+// resolveSessionID returns the CLI session ID and external ID for an ID
+// given to the session manager. Without a registry, non-UUID IDs are mapped
+// to deterministic UUIDs. With one, they are looked up in the registry and,
+// when create is set, assigned a new random session ID if they have none;
+// otherwise the returned session ID is empty.
+// Session IDs read back from the registry are validated here, when they are
+// used, rather than when they were stored.
+func (sm *ClaudeCodeSessionManager) resolveSessionID(ctx context.Context, id string, create bool) (sessionID, externalID string, err error) {
+	registry := sm.config.Registry
+
+	if id == "" {
+		if !create {
+			return "", "", nil
+		}
+		return GenerateSessionID(), "", nil
+	}
+
+	if IsValidUUID(id) {
+		if registry != nil {
+			externalID, _, err = registry.ExternalID(ctx, id)
+			if err != nil {
+				return "", "", sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_REGISTRY_LOOKUP", "failed to look up session ID")
+			}
+		}
+		return id, externalID, nil
+	}
+
+	if registry == nil {
+		sessionID, err := NormalizeSessionID(id)
+		if err != nil {
+			return "", "", FormatSessionIDError(id)
+		}
+		return sessionID, id, nil
+	}
+
+	sessionID, ok, err := registry.SessionID(ctx, id)
+	if err != nil {
+		return "", "", sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_REGISTRY_LOOKUP", "failed to look up external session ID")
+	}
+	if ok {
+		if !IsValidUUID(sessionID) {
+			return "", "", sdkerrors.NewValidationError("sessionID", sessionID, "uuid",
+				"session ID registered for "+id+" is not a valid UUID")
+		}
+		return sessionID, id, nil
+	}
+	if !create {
+		return "", id, nil
+	}
+
+	sessionID = GenerateSessionID()
+	if err := registry.Register(ctx, id, sessionID); err != nil {
+		return "", "", err
+	}
+	return sessionID, id, nil
+}
+
+// SetRegistry sets the registry that maps external IDs to session IDs. Pass
+// nil to map non-UUID IDs to deterministic UUIDs instead. Sessions already
+// created keep their IDs.
+func (sm *ClaudeCodeSessionManager) SetRegistry(registry SessionRegistry) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.config.Registry = registry
+}
+
+// ExternalID returns the external ID the session was created with, or "" if
+// it was created with a UUID that has no registered external ID.
+func (s *ClaudeCodeSession) ExternalID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.externalID
+}
+
+// lookupSession returns the session ID and external ID for a session ID or
+// registered external ID. The session ID is "" for an external ID with no
+// session.
+func (sm *ClaudeCodeSessionManager) lookupSession(ctx context.Context, id string) (sessionID, externalID string, err error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.resolveSessionID(ctx, id, false)
+}
+
+// registry returns the manager's session registry, or nil.
+func (sm *ClaudeCodeSessionManager) registry() SessionRegistry {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.config.Registry
+}
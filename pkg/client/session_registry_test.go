@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func newRegistryTestClient(t *testing.T, registry SessionRegistry) *ClaudeCodeClient {
+	t.Helper()
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	client.Sessions().SetRegistry(registry)
+	return client
+}
+
+func TestMemorySessionRegistry(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMemorySessionRegistry()
+	first, second := GenerateSessionID(), GenerateSessionID()
+
+	if err := registry.Register(ctx, "ticket-1", first); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if id, ok, _ := registry.SessionID(ctx, "ticket-1"); !ok || id != first {
+		t.Errorf("SessionID = %q, %v; want %q", id, ok, first)
+	}
+	if ext, ok, _ := registry.ExternalID(ctx, first); !ok || ext != "ticket-1" {
+		t.Errorf("ExternalID = %q, %v; want ticket-1", ext, ok)
+	}
+
+	// Remapping the external ID drops the old session ID's reverse mapping
+	if err := registry.Register(ctx, "ticket-1", second); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if _, ok, _ := registry.ExternalID(ctx, first); ok {
+		t.Error("Old session ID should no longer map to ticket-1")
+	}
+
+	// Remapping the session ID drops the old external ID
+	if err := registry.Register(ctx, "ticket-2", second); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if _, ok, _ := registry.SessionID(ctx, "ticket-1"); ok {
+		t.Error("ticket-1 should no longer be mapped")
+	}
+
+	if err := registry.Unregister(ctx, "ticket-2"); err != nil {
+		t.Fatalf("Unregister failed: %v", err)
+	}
+	if _, ok, _ := registry.ExternalID(ctx, second); ok {
+		t.Error("Unregister should remove the reverse mapping")
+	}
+}
+
+func TestMemorySessionRegistryValidation(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMemorySessionRegistry()
+
+	if err := registry.Register(ctx, "", GenerateSessionID()); err == nil {
+		t.Error("Expected an error for an empty external ID")
+	}
+	if err := registry.Register(ctx, "ticket-1", "not-a-uuid"); err == nil {
+		t.Error("Expected an error for a non-UUID session ID")
+	}
+}
+
+func TestFileSessionRegistryPersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "registry", "sessions.json")
+	sessionID := GenerateSessionID()
+
+	registry, err := NewFileSessionRegistry(path)
+	if err != nil {
+		t.Fatalf("NewFileSessionRegistry failed: %v", err)
+	}
+	if err := registry.Register(ctx, "thread-42", sessionID); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	reloaded, err := NewFileSessionRegistry(path)
+	if err != nil {
+		t.Fatalf("Reloading registry failed: %v", err)
+	}
+	if id, ok, _ := reloaded.SessionID(ctx, "thread-42"); !ok || id != sessionID {
+		t.Errorf("SessionID after reload = %q, %v; want %q", id, ok, sessionID)
+	}
+	if ext, ok, _ := reloaded.ExternalID(ctx, sessionID); !ok || ext != "thread-42" {
+		t.Errorf("ExternalID after reload = %q, %v; want thread-42", ext, ok)
+	}
+
+	if err := reloaded.Unregister(ctx, "thread-42"); err != nil {
+		t.Fatalf("Unregister failed: %v", err)
+	}
+	again, err := NewFileSessionRegistry(path)
+	if err != nil {
+		t.Fatalf("Reloading registry failed: %v", err)
+	}
+	if _, ok, _ := again.SessionID(ctx, "thread-42"); ok {
+		t.Error("Unregistered mapping should not survive a reload")
+	}
+}
+
+func TestSessionManagerExternalIDs(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMemorySessionRegistry()
+	client := newRegistryTestClient(t, registry)
+
+	session, err := client.CreateSession(ctx, "support-ticket-1234")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if !IsValidUUID(session.ID) {
+		t.Errorf("Session ID %q should be a UUID", session.ID)
+	}
+	if session.ExternalID() != "support-ticket-1234" {
+		t.Errorf("ExternalID = %q", session.ExternalID())
+	}
+	if session.ID == deterministicSessionID(t, "support-ticket-1234") {
+		t.Error("Registry should assign a random session ID, not a derived one")
+	}
+	if id, ok, _ := registry.SessionID(ctx, "support-ticket-1234"); !ok || id != session.ID {
+		t.Errorf("Registry maps external ID to %q, %v; want %q", id, ok, session.ID)
+	}
+
+	// Both IDs find the session
+	for _, id := range []string{"support-ticket-1234", session.ID} {
+		found, err := client.GetSession(id)
+		if err != nil {
+			t.Fatalf("GetSession(%q) failed: %v", id, err)
+		}
+		if found != session {
+			t.Errorf("GetSession(%q) returned a different session", id)
+		}
+	}
+
+	// Closing keeps the mapping, so the conversation resumes
+	if err := client.Sessions().CloseSession("support-ticket-1234"); err != nil {
+		t.Fatalf("CloseSession failed: %v", err)
+	}
+	if _, err := client.GetSession("support-ticket-1234"); err == nil {
+		t.Error("Closed session should not be found")
+	}
+	resumed, err := client.CreateSession(ctx, "support-ticket-1234")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if resumed.ID != session.ID {
+		t.Errorf("Resumed session ID = %q, want %q", resumed.ID, session.ID)
+	}
+
+	// A UUID registered by another process reports its external ID
+	sessionID := GenerateSessionID()
+	if err := registry.Register(ctx, "slack-C123", sessionID); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	byUUID, err := client.CreateSession(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if byUUID.ExternalID() != "slack-C123" {
+		t.Errorf("ExternalID = %q, want slack-C123", byUUID.ExternalID())
+	}
+
+	if _, err := client.GetSession("unknown-ticket"); err == nil {
+		t.Error("Expected an error for an unregistered external ID")
+	}
+	if err := client.Sessions().CloseSession("unknown-ticket"); err != nil {
+		t.Errorf("Closing an unknown session should succeed, got %v", err)
+	}
+}
+
+func TestSessionManagerInvalidRegisteredID(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMemorySessionRegistry()
+	client := newRegistryTestClient(t, registry)
+
+	// Corrupt the mapping behind the registry's validation
+	registry.sessions["ticket-9"] = "not-a-uuid"
+
+	if _, err := client.CreateSession(ctx, "ticket-9"); err == nil {
+		t.Error("Expected an error for an invalid registered session ID")
+	}
+}
+
+func TestSessionManagerExternalIDsWithoutRegistry(t *testing.T) {
+	ctx := context.Background()
+	client := newRegistryTestClient(t, nil)
+
+	session, err := client.CreateSession(ctx, "my-conversation")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if want := deterministicSessionID(t, "my-conversation"); session.ID != want {
+		t.Errorf("Session ID = %q, want deterministic %q", session.ID, want)
+	}
+	if session.ExternalID() != "my-conversation" {
+		t.Errorf("ExternalID = %q", session.ExternalID())
+	}
+	if found, err := client.GetSession("my-conversation"); err != nil || found != session {
+		t.Errorf("GetSession by name = %v, %v", found, err)
+	}
+}
+
+// deterministicSessionID returns the deterministic UUID for input.
+func deterministicSessionID(t *testing.T, input string) string {
+	t.Helper()
+	id, err := GenerateUUIDFromString(input)
+	if err != nil {
+		t.Fatalf("GenerateUUIDFromString failed: %v", err)
+	}
+	return id
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// stateMachine tracks the client's lifecycle state. The counts of running
+// and interrupted queries come from activeProcesses; the rest is recorded
+// here as queries start and the client closes.
+type stateMachine struct {
+	mu    sync.Mutex
+	state types.ClientState
+
+	// starting counts CLI processes started but not yet tracked
+	starting int
+
+	// failed is set when the last CLI start failed
+	failed bool
+
+	closed bool
+
+	// Transitions waiting to be delivered to onChange, in order
+	onChange   func(types.ClientStateChange)
+	pending    []types.ClientStateChange
+	delivering bool
+}
+
+// State returns the client's current lifecycle state.
+func (c *ClaudeCodeClient) State() types.ClientState {
+	c.lifecycle.mu.Lock()
+	defer c.lifecycle.mu.Unlock()
+	return c.lifecycle.state
+}
+
+// transition applies update to the state machine, recomputes the state and
+// reports a change to OnStateChange. It must not be called with c.mu or
+// c.processMu held.
+func (c *ClaudeCodeClient) transition(update func(m *stateMachine)) {
+	m := &c.lifecycle
+	m.mu.Lock()
+
+	if update != nil {
+		update(m)
+	}
+
+	c.processMu.Lock()
+	active, interrupted := len(c.activeProcesses), 0
+	for _, process := range c.activeProcesses {
+		if process.interrupted.Load() {
+			interrupted++
+		}
+	}
+	c.processMu.Unlock()
+
+	next := types.ClientReady
+	switch {
+	case m.closed:
+		next = types.ClientClosed
+	case interrupted > 0:
+		next = types.ClientInterrupting
+	case active > 0:
+		next = types.ClientBusy
+	case m.starting > 0:
+		next = types.ClientConnecting
+	case m.failed:
+		next = types.ClientDisconnected
+	}
+
+	if next == m.state {
+		m.mu.Unlock()
+		return
+	}
+	if m.onChange != nil {
+		m.pending = append(m.pending, types.ClientStateChange{From: m.state, To: next, Time: time.Now()})
+	}
+	m.state = next
+
+	// One goroutine delivers at a time so changes arrive in order; a callback
+	// that queries the client only queues further changes
+	if m.delivering || len(m.pending) == 0 {
+		m.mu.Unlock()
+		return
+	}
+	m.delivering = true
+	for len(m.pending) > 0 {
+		change := m.pending[0]
+		m.pending = m.pending[1:]
+		m.mu.Unlock()
+		m.onChange(change)
+		m.mu.Lock()
+	}
+	m.delivering = false
+	m.mu.Unlock()
+}
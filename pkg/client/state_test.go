@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// recordStates records the client's state changes.
+func recordStates(client *ClaudeCodeClient) func() []types.ClientState {
+	var mu sync.Mutex
+	var states []types.ClientState
+	client.lifecycle.onChange = func(change types.ClientStateChange) {
+		// Reading the state from the callback must not deadlock
+		_ = client.State()
+
+		mu.Lock()
+		states = append(states, change.To)
+		mu.Unlock()
+	}
+	return func() []types.ClientState {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]types.ClientState(nil), states...)
+	}
+}
+
+func TestClientState_Query(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	states := recordStates(client)
+
+	if client.State() != types.ClientReady {
+		t.Fatalf("Expected new client to be ready, got %s", client.State())
+	}
+
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}
+	if _, err := client.Query(context.Background(), request); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	want := []types.ClientState{types.ClientConnecting, types.ClientBusy, types.ClientReady}
+	if got := states(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected states %v, got %v", want, got)
+	}
+}
+
+func TestClientState_StartFailure(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	cliPath := client.claudeCodeCmd
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}
+
+	client.claudeCodeCmd = filepath.Join(t.TempDir(), "missing")
+	if _, err := client.Query(context.Background(), request); err == nil {
+		t.Fatal("Expected Query to fail without a CLI")
+	}
+	if client.State() != types.ClientDisconnected {
+		t.Errorf("Expected disconnected after a failed start, got %s", client.State())
+	}
+
+	client.claudeCodeCmd = cliPath
+	if _, err := client.Query(context.Background(), request); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if client.State() != types.ClientReady {
+		t.Errorf("Expected ready after a successful query, got %s", client.State())
+	}
+}
+
+func TestClientState_InterruptAndClose(t *testing.T) {
+	client := newFakeCLIClient(t, `sleep 30`)
+	states := recordStates(client)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = client.Query(context.Background(), &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}})
+	}()
+	waitForProcesses(t, client, 1)
+
+	if client.State() != types.ClientBusy {
+		t.Errorf("Expected busy while a query runs, got %s", client.State())
+	}
+	if err := client.Interrupt(); err != nil {
+		t.Fatalf("Interrupt failed: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Query did not return after Interrupt")
+	}
+	if client.State() != types.ClientReady {
+		t.Errorf("Expected ready after the interrupted query returned, got %s", client.State())
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if client.State() != types.ClientClosed || client.State().CanQuery() {
+		t.Errorf("Expected closed state that rejects queries, got %s", client.State())
+	}
+
+	want := []types.ClientState{
+		types.ClientConnecting, types.ClientBusy, types.ClientInterrupting, types.ClientReady, types.ClientClosed,
+	}
+	if got := states(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected states %v, got %v", want, got)
+	}
+}
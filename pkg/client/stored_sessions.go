@@ -0,0 +1,256 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// maxFirstPrompt is the length at which StoredSession.FirstPrompt is cut.
+const maxFirstPrompt = 200
+
+// ListStoredSessions returns the conversations in the CLI's session history,
+// most recently used first, for every project the CLI has run in. Filter on
+// WorkingDirectory to offer only the current project's sessions. The history
+// is read from CLAUDE_CONFIG_DIR in the client's environment, or ~/.claude.
+func (c *ClaudeCodeClient) ListStoredSessions(ctx context.Context) ([]*types.StoredSession, error) {
+	dir, err := c.claudeConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "projects", "*", "*.jsonl"))
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_HISTORY", "failed to list session history")
+	}
+
+	sessions := make([]*types.StoredSession, 0, len(paths))
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !IsValidUUID(strings.TrimSuffix(filepath.Base(path), ".jsonl")) {
+			continue
+		}
+		session, err := readStoredSession(path)
+		if err != nil {
+			continue // Sessions being written or removed are skipped
+		}
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].UpdatedAt.Equal(sessions[j].UpdatedAt) {
+			return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+	return sessions, nil
+}
+
+// GetStoredSession returns a conversation in the CLI's session history, given
+// its session ID or an external ID known to the session registry.
+func (c *ClaudeCodeClient) GetStoredSession(ctx context.Context, sessionID string) (*types.StoredSession, error) {
+	paths, err := c.storedSessionFiles(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, sdkerrors.NewValidationError("sessionID", sessionID, "stored session", "session not found in session history")
+	}
+	session, err := readStoredSession(paths[0])
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_HISTORY", "failed to read session history")
+	}
+	return session, nil
+}
+
+// storedSessionFiles returns the transcript files of a session in the CLI's
+// session history. A session resumed from another directory has one file in
+// each project.
+func (c *ClaudeCodeClient) storedSessionFiles(ctx context.Context, sessionID string) ([]string, error) {
+	id, _, err := c.sessionManager.lookupSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsValidUUID(id) {
+		return nil, nil
+	}
+
+	dir, err := c.claudeConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "projects", "*", id+".jsonl"))
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SESSION_HISTORY", "failed to list session history")
+	}
+	return paths, nil
+}
+
+// claudeConfigDir returns the directory the CLI keeps its settings and
+// session history in: CLAUDE_CONFIG_DIR in the subprocess environment, or
+// ~/.claude.
+func (c *ClaudeCodeClient) claudeConfigDir() (string, error) {
+	env, err := c.subprocessEnvironment()
+	if err != nil {
+		return "", err
+	}
+	for i := len(env) - 1; i >= 0; i-- {
+		if dir, ok := strings.CutPrefix(env[i], "CLAUDE_CONFIG_DIR="); ok && dir != "" {
+			return dir, nil
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "SESSION_HISTORY", "cannot locate the Claude Code config directory")
+	}
+	return filepath.Join(home, ".claude"), nil
+}
+
+// transcriptEntry is the part of a CLI session transcript line that
+// describes a session.
+type transcriptEntry struct {
+	Type        string          `json:"type"`
+	Cwd         string          `json:"cwd"`
+	GitBranch   string          `json:"gitBranch"`
+	Timestamp   time.Time       `json:"timestamp"`
+	IsMeta      bool            `json:"isMeta"`
+	IsSidechain bool            `json:"isSidechain"`
+	Summary     string          `json:"summary"`
+	Message     json.RawMessage `json:"message"`
+}
+
+// readStoredSession summarizes a CLI session transcript. Lines that cannot
+// be parsed are skipped, since the CLI may be appending to the file.
+func readStoredSession(path string) (*types.StoredSession, error) {
+	file, err := os.Open(path) // #nosec G304 - path is in the CLI's session history
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	session := &types.StoredSession{
+		ID:   strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+		Path: path,
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry transcriptEntry
+			if json.Unmarshal(line, &entry) == nil {
+				addTranscriptEntry(session, &entry)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if session.UpdatedAt.IsZero() {
+		if info, err := file.Stat(); err == nil {
+			session.UpdatedAt = info.ModTime()
+		}
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = session.UpdatedAt
+	}
+	return session, nil
+}
+
+// addTranscriptEntry adds a transcript line to a session's summary.
+func addTranscriptEntry(session *types.StoredSession, entry *transcriptEntry) {
+	if entry.Type == "summary" {
+		if session.Summary == "" {
+			session.Summary = entry.Summary
+		}
+		return
+	}
+
+	if !entry.Timestamp.IsZero() {
+		if session.CreatedAt.IsZero() || entry.Timestamp.Before(session.CreatedAt) {
+			session.CreatedAt = entry.Timestamp
+		}
+		if entry.Timestamp.After(session.UpdatedAt) {
+			session.UpdatedAt = entry.Timestamp
+		}
+	}
+	if entry.Cwd != "" {
+		session.WorkingDirectory = entry.Cwd
+	}
+	if entry.GitBranch != "" {
+		session.GitBranch = entry.GitBranch
+	}
+
+	if (entry.Type != "user" && entry.Type != "assistant") || entry.IsSidechain || entry.IsMeta {
+		return
+	}
+	session.Messages++
+	if entry.Type == "user" && session.FirstPrompt == "" {
+		session.FirstPrompt = promptText(entry.Message)
+	}
+}
+
+// transcriptText returns the text of a message in a transcript: its content
+// if that is a string, or else its text blocks joined by newlines.
+func transcriptText(raw json.RawMessage) string {
+	var message struct {
+		Content json.RawMessage `json:"content"`
+	}
+	if json.Unmarshal(raw, &message) != nil {
+		return ""
+	}
+
+	var text string
+	if json.Unmarshal(message.Content, &text) == nil {
+		return text
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(message.Content, &blocks) != nil {
+		return ""
+	}
+	var texts []string
+	for _, block := range blocks {
+		if block.Type == "text" {
+			texts = append(texts, block.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// promptText returns the shortened text of a user message in a transcript,
+// or "" for tool results and slash command output.
+func promptText(raw json.RawMessage) string {
+	text := strings.Join(strings.Fields(transcriptText(raw)), " ")
+	if strings.HasPrefix(text, "<command-") || strings.HasPrefix(text, "<local-command-") {
+		return ""
+	}
+	if len(text) <= maxFirstPrompt {
+		return text
+	}
+	cut := maxFirstPrompt
+	for cut > 0 && text[cut]&0xC0 == 0x80 {
+		cut-- // Don't split a UTF-8 sequence
+	}
+	return text[:cut] + "…"
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// writeTranscript writes a CLI session transcript into configDir's history.
+func writeTranscript(t *testing.T, configDir, project, sessionID string, lines ...string) string {
+	t.Helper()
+	dir := filepath.Join(configDir, "projects", project)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, sessionID+".jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newStoredSessionsClient(t *testing.T) (*ClaudeCodeClient, string) {
+	t.Helper()
+	configDir := t.TempDir()
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+		Environment:      map[string]string{"CLAUDE_CONFIG_DIR": configDir},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, configDir
+}
+
+func TestListStoredSessions(t *testing.T) {
+	client, configDir := newStoredSessionsClient(t)
+	older, newer := GenerateSessionID(), GenerateSessionID()
+
+	path := writeTranscript(t, configDir, "-src-api", older,
+		`{"type":"summary","summary":"Fix login redirect","leafUuid":"x"}`,
+		`{"type":"user","sessionId":"`+older+`","cwd":"/src/api","gitBranch":"main","isMeta":true,"timestamp":"2025-06-01T09:59:00Z","message":{"role":"user","content":"<command-name>/init</command-name>"}}`,
+		`{"type":"user","sessionId":"`+older+`","cwd":"/src/api","gitBranch":"main","timestamp":"2025-06-01T10:00:00Z","message":{"role":"user","content":"Why does   login\nredirect twice?"}}`,
+		`{"type":"assistant","sessionId":"`+older+`","cwd":"/src/api","timestamp":"2025-06-01T10:00:05Z","message":{"role":"assistant","content":[{"type":"text","text":"Let me look."}]}}`,
+		`{"type":"user","sessionId":"`+older+`","cwd":"/src/api","timestamp":"2025-06-01T10:00:06Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+		`{"type":"assistant","sessionId":"`+older+`","isSidechain":true,"timestamp":"2025-06-01T10:00:07Z","message":{"role":"assistant","content":[]}}`,
+		`{"type":"user","sessionId":"`+older+`","cwd":"/src/api","gitBranch":"fix-login","timestamp":"2025-06-01T10:02:00Z","message":{"role":"user","content":[{"type":"text","text":"Thanks"}]}}`,
+		`{"truncated`,
+	)
+	writeTranscript(t, configDir, "-src-web", newer,
+		`{"type":"user","sessionId":"`+newer+`","cwd":"/src/web","timestamp":"2025-06-02T08:00:00Z","message":{"role":"user","content":[{"type":"text","text":"`+strings.Repeat("é", 150)+`"}]}}`,
+	)
+	writeTranscript(t, configDir, "-src-web", "not-a-session", `{"type":"user"}`)
+
+	sessions, err := client.ListStoredSessions(context.Background())
+	if err != nil {
+		t.Fatalf("ListStoredSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].ID != newer || sessions[1].ID != older {
+		t.Errorf("Sessions should be newest first, got %s, %s", sessions[0].ID, sessions[1].ID)
+	}
+
+	got := sessions[1]
+	want := &types.StoredSession{
+		ID:               older,
+		WorkingDirectory: "/src/api",
+		Path:             path,
+		CreatedAt:        time.Date(2025, 6, 1, 9, 59, 0, 0, time.UTC),
+		UpdatedAt:        time.Date(2025, 6, 1, 10, 2, 0, 0, time.UTC),
+		FirstPrompt:      "Why does login redirect twice?",
+		Summary:          "Fix login redirect",
+		Messages:         4,
+		GitBranch:        "fix-login",
+	}
+	if got.ID != want.ID || got.WorkingDirectory != want.WorkingDirectory || got.Path != want.Path ||
+		!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) ||
+		got.FirstPrompt != want.FirstPrompt || got.Summary != want.Summary ||
+		got.Messages != want.Messages || got.GitBranch != want.GitBranch {
+		t.Errorf("Stored session = %+v, want %+v", got, want)
+	}
+
+	prompt := sessions[0].FirstPrompt
+	if !strings.HasSuffix(prompt, "…") || len(prompt) > maxFirstPrompt+len("…") {
+		t.Errorf("Long prompt should be shortened, got %d bytes", len(prompt))
+	}
+}
+
+func TestListStoredSessionsEmpty(t *testing.T) {
+	client, _ := newStoredSessionsClient(t)
+
+	sessions, err := client.ListStoredSessions(context.Background())
+	if err != nil {
+		t.Fatalf("ListStoredSessions failed: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("Expected no sessions, got %d", len(sessions))
+	}
+}
+
+func TestGetStoredSession(t *testing.T) {
+	client, configDir := newStoredSessionsClient(t)
+	sessionID := GenerateSessionID()
+	writeTranscript(t, configDir, "-src-api", sessionID,
+		`{"type":"user","sessionId":"`+sessionID+`","cwd":"/src/api","timestamp":"2025-06-01T10:00:00Z","message":{"role":"user","content":"hello"}}`,
+	)
+
+	registry := NewMemorySessionRegistry()
+	if err := registry.Register(context.Background(), "ticket-7", sessionID); err != nil {
+		t.Fatal(err)
+	}
+	client.Sessions().SetRegistry(registry)
+
+	for _, id := range []string{sessionID, "ticket-7"} {
+		session, err := client.GetStoredSession(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetStoredSession(%q) failed: %v", id, err)
+		}
+		if session.ID != sessionID || session.FirstPrompt != "hello" {
+			t.Errorf("GetStoredSession(%q) = %+v", id, session)
+		}
+	}
+
+	if _, err := client.GetStoredSession(context.Background(), GenerateSessionID()); err == nil {
+		t.Error("Expected an error for a session not in the history")
+	}
+	if _, err := client.GetStoredSession(context.Background(), "unknown-ticket"); err == nil {
+		t.Error("Expected an error for an unregistered external ID")
+	}
+}
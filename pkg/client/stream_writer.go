@@ -0,0 +1,253 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// StreamTo runs prompt and writes the text of Claude's reply to w as it
+// arrives, flushing after each write when w is an http.Flusher or has a
+// Flush() error method, such as a bufio.Writer. Options select the model,
+// system prompt, session, working directory, allowed and disallowed tools,
+// credentials and metadata of the query.
+//
+// StreamTo returns the result the CLI reported, which is nil for CLIs that
+// do not write stream-json output. A query that ends with an error result
+// returns the result together with an error.
+func (c *ClaudeCodeClient) StreamTo(ctx context.Context, prompt string, options *QueryOptions, w io.Writer) (*types.ResultMessage, error) {
+	return c.streamText(ctx, prompt, options, func(text string) error {
+		if _, err := io.WriteString(w, text); err != nil {
+			return err
+		}
+		return flushWriter(w)
+	})
+}
+
+// SSEHandler returns an http.Handler that runs the query built from each
+// request and streams the reply as server-sent events: a "delta" event for
+// each piece of text, then a "result" event holding the ResultMessage as
+// JSON, or an "error" event if the query fails. The query is cancelled when
+// the request's client disconnects. Requests for which query returns an
+// error are answered with 400 Bad Request.
+func (c *ClaudeCodeClient) SSEHandler(query func(r *http.Request) (prompt string, options *QueryOptions, err error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+		prompt, options, err := query(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		header := w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		result, err := c.streamText(r.Context(), prompt, options, func(text string) error {
+			if err := writeSSE(w, "delta", text); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+		if err != nil {
+			_ = writeSSE(w, "error", err.Error()) // Ignore error, the client may be gone
+			flusher.Flush()
+			return
+		}
+		if result == nil {
+			result = &types.ResultMessage{Type: "result", Subtype: "success"}
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return
+		}
+		_ = writeSSE(w, "result", string(data)) // Ignore error, the client may be gone
+		flusher.Flush()
+	})
+}
+
+// streamText runs prompt through QueryStream, passing each piece of reply
+// text to emit. An error from emit stops the query.
+func (c *ClaudeCodeClient) streamText(ctx context.Context, prompt string, options *QueryOptions, emit func(text string) error) (*types.ResultMessage, error) {
+	options, err := c.applyProfile(options)
+	if err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = &QueryOptions{}
+	}
+	if err := c.validateQuery(options); err != nil {
+		return nil, err
+	}
+
+	if options.APIKey != "" {
+		ctx = WithAPIKey(ctx, options.APIKey)
+	}
+	ctx = WithQueryMetadata(ctx, options.Metadata)
+	if options.SessionID != "" || options.CWD != "" || len(options.AllowedTools) > 0 || len(options.DisallowedTools) > 0 {
+		scope := c.scopeFrom(ctx)
+		if options.SessionID != "" {
+			scope.sessionID = options.SessionID
+		}
+		if options.CWD != "" {
+			scope.workingDir = options.CWD
+		}
+		scope.allowedTools = options.AllowedTools
+		scope.disallowedTools = options.DisallowedTools
+		ctx = withQueryScope(ctx, scope)
+	}
+
+	stream, err := c.QueryStream(ctx, &types.QueryRequest{
+		Model:    options.Model,
+		System:   options.SystemPrompt,
+		Messages: []types.Message{{Role: types.RoleUser, Content: prompt}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var lines textLines
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return lines.result, err
+		}
+		if chunk.Done {
+			break
+		}
+		if text := lines.text([]byte(chunk.Content)); text != "" {
+			if err := emit(text); err != nil {
+				return lines.result, err
+			}
+		}
+	}
+
+	if lines.result != nil && !lines.result.Succeeded() {
+		return lines.result, sdkerrors.NewInternalError("CLAUDE_EXECUTION", "query ended with an error result: "+lines.result.Result)
+	}
+	return lines.result, nil
+}
+
+// textLines extracts reply text from lines of CLI output. Stream-json lines
+// contribute the text of assistant messages, or their text deltas when the
+// CLI includes partial messages; other lines are text output.
+type textLines struct {
+	// partial is set once a text delta is seen, after which the complete
+	// assistant messages repeat text already emitted
+	partial bool
+
+	// result is the result line, once seen
+	result *types.ResultMessage
+}
+
+// textStreamLine is the subset of a stream-json line holding reply text.
+type textStreamLine struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"message"`
+
+	// Delta is the delta of a content_block_delta line
+	Delta *textDelta `json:"delta"`
+
+	// Event wraps a raw API event in partial message output
+	Event *textStreamEvent `json:"event"`
+}
+
+type textStreamEvent struct {
+	Type  string     `json:"type"`
+	Delta *textDelta `json:"delta"`
+}
+
+type textDelta struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// text returns the reply text in line.
+func (l *textLines) text(line []byte) string {
+	var parsed textStreamLine
+	if !bytes.HasPrefix(bytes.TrimSpace(line), []byte("{")) || json.Unmarshal(line, &parsed) != nil || parsed.Type == "" {
+		return string(line)
+	}
+
+	switch parsed.Type {
+	case "assistant":
+		if l.partial || parsed.Message == nil {
+			return ""
+		}
+		var text strings.Builder
+		for _, block := range parsed.Message.Content {
+			if block.Type == "text" {
+				text.WriteString(block.Text)
+			}
+		}
+		return text.String()
+
+	case "stream_event":
+		if parsed.Event != nil {
+			return l.delta(parsed.Event)
+		}
+
+	case "content_block_delta":
+		return l.delta(&textStreamEvent{Type: parsed.Type, Delta: parsed.Delta})
+
+	case "result":
+		l.result, _ = types.ParseResultMessage(line)
+	}
+	return ""
+}
+
+// delta returns the text of a content_block_delta event.
+func (l *textLines) delta(event *textStreamEvent) string {
+	if event.Type != "content_block_delta" || event.Delta == nil || event.Delta.Type != "text_delta" {
+		return ""
+	}
+	l.partial = true
+	return event.Delta.Text
+}
+
+// flushWriter flushes w if it buffers output.
+func flushWriter(w io.Writer) error {
+	switch f := w.(type) {
+	case http.Flusher:
+		f.Flush()
+	case interface{ Flush() error }:
+		return f.Flush()
+	}
+	return nil
+}
+
+// writeSSE writes a server-sent event, splitting data across data fields
+// at its newlines.
+func writeSSE(w io.Writer, event, data string) error {
+	var b strings.Builder
+	b.WriteString("event: " + event + "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
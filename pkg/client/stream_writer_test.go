@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClaudeCodeClient_StreamTo(t *testing.T) {
+	client := newFakeCLIClient(t, `
+echo '{"type":"system","subtype":"init","session_id":"sess_1"}'
+echo '{"type":"assistant","message":{"content":[{"type":"text","text":"Hello, "}]}}'
+echo '{"type":"assistant","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Read","input":{}},{"type":"text","text":"world."}]}}'
+echo '{"type":"result","subtype":"success","is_error":false,"result":"Hello, world.","duration_ms":250,"total_cost_usd":0.01}'`)
+
+	var out bytes.Buffer
+	buffered := bufio.NewWriter(&out)
+	result, err := client.StreamTo(context.Background(), "greet me", nil, buffered)
+	if err != nil {
+		t.Fatalf("StreamTo failed: %v", err)
+	}
+	if out.String() != "Hello, world." {
+		t.Errorf("Expected the reply text to be flushed, got %q", out.String())
+	}
+	if !result.Succeeded() || result.Cost() != 0.01 || result.Duration().Milliseconds() != 250 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestClaudeCodeClient_StreamToPartialMessages(t *testing.T) {
+	client := newFakeCLIClient(t, `
+echo '{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}}'
+echo '{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}}'
+echo '{"type":"assistant","message":{"content":[{"type":"text","text":"Hello"}]}}'
+echo '{"type":"result","subtype":"error_max_turns","is_error":true,"result":"turn limit"}'`)
+
+	var out strings.Builder
+	result, err := client.StreamTo(context.Background(), "greet me", nil, &out)
+	if err == nil || result == nil || result.Subtype != "error_max_turns" {
+		t.Fatalf("Expected the error result, got %+v, %v", result, err)
+	}
+	if out.String() != "Hello" {
+		t.Errorf("Expected each delta written once, got %q", out.String())
+	}
+}
+
+func TestClaudeCodeClient_StreamToTextOutput(t *testing.T) {
+	client := newFakeCLIClient(t, `printf 'line one\nline two\n'`)
+
+	var out strings.Builder
+	result, err := client.StreamTo(context.Background(), "hi", nil, &out)
+	if err != nil || result != nil {
+		t.Fatalf("StreamTo = %+v, %v", result, err)
+	}
+	if out.String() != "line one\nline two\n" {
+		t.Errorf("Expected text output passed through, got %q", out.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestClaudeCodeClient_StreamToWriteError(t *testing.T) {
+	client := newFakeCLIClient(t, `echo 'partial'; sleep 5`)
+
+	if _, err := client.StreamTo(context.Background(), "hi", nil, failingWriter{}); err == nil || err.Error() != "connection reset" {
+		t.Errorf("Expected the write error, got %v", err)
+	}
+}
+
+func TestClaudeCodeClient_SSEHandler(t *testing.T) {
+	client := newFakeCLIClient(t, `
+printf '%s\n' '{"type":"assistant","message":{"content":[{"type":"text","text":"two\nlines"}]}}'
+printf '%s\n' '{"type":"result","subtype":"success","is_error":false,"result":"two\nlines","num_turns":1}'`)
+
+	handler := client.SSEHandler(func(r *http.Request) (string, *QueryOptions, error) {
+		prompt := r.URL.Query().Get("prompt")
+		if prompt == "" {
+			return "", nil, errors.New("prompt is required")
+		}
+		return prompt, nil, nil
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/chat?prompt=hi", nil))
+	if got := recorder.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	want := "event: delta\ndata: two\ndata: lines\n\n" +
+		`event: result` + "\n" + `data: {"type":"result","subtype":"success","is_error":false,"result":"two\nlines","num_turns":1}` + "\n\n"
+	if body := recorder.Body.String(); body != want {
+		t.Errorf("Unexpected events:\n%s\nwant:\n%s", body, want)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/chat", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad request, got %d", recorder.Code)
+	}
+}
@@ -1,14 +1,10 @@
 package client
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
-	"os"
-	"os/exec"
 	"strings"
 	"time"
 
@@ -37,40 +33,30 @@ func (c *ClaudeCodeClient) StreamQuery(ctx context.Context, request *types.Query
 	request.Stream = true
 
 	// Build claude command arguments for streaming
-	args, err := c.buildClaudeArgs(request, true)
+	scope := c.scopeFrom(ctx)
+	args, err := c.buildScopedClaudeArgs(request, true, scope)
 	if err != nil {
 		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "ARGS_BUILD", "failed to build claude streaming arguments")
 	}
-
-	// Create and start claude process
-	cmd := exec.CommandContext(ctx, c.claudeCodeCmd, args...) // #nosec G204 - claudeCodeCmd is validated during initialization
-	cmd.Dir = c.workingDir
-	cmd.Env = append(os.Environ(), c.buildEnvironment()...)
-
-	// Create pipes for stdout and stderr
-	stdout, err := cmd.StdoutPipe()
+	input, err := c.stdinInput(request, true, scope.workingDir)
 	if err != nil {
-		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PIPE_CREATION", "failed to create stdout pipe")
+		return nil, err
 	}
 
-	stderr, err := cmd.StderrPipe()
+	// Create and start claude process
+	process, err := c.startCLI(ctx, args, input, request, true)
 	if err != nil {
-		_ = stdout.Close() // Ignore error during cleanup
-		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PIPE_CREATION", "failed to create stderr pipe")
-	}
-
-	// Start the process
-	if err := cmd.Start(); err != nil {
-		_ = stdout.Close() // Ignore error during cleanup
-		_ = stderr.Close() // Ignore error during cleanup
-		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "PROCESS_START", "failed to start claude process")
+		return nil, err
 	}
 
 	// Track the process
-	processID := fmt.Sprintf("stream-%d", cmd.Process.Pid)
-	c.processMu.Lock()
-	c.activeProcesses[processID] = cmd
-	c.processMu.Unlock()
+	processID := c.newProcessID("stream")
+	if err := c.trackProcess(processID, process); err != nil {
+		_ = process.stdout.Close() // Ignore error during cleanup
+		_ = process.stderr.Close() // Ignore error during cleanup
+		_ = process.Wait()         // Ignore error, the process was killed
+		return nil, err
+	}
 
 	// Create channels for streaming
 	eventChan := make(chan *types.StreamEvent, opts.BufferSize)
@@ -82,9 +68,9 @@ func (c *ClaudeCodeClient) StreamQuery(ctx context.Context, request *types.Query
 
 	// Create reader for parsing stream
 	reader := &advancedStreamReader{
-		cmd:       cmd,
-		stdout:    stdout,
-		stderr:    stderr,
+		process:   process,
+		stdout:    process.stdout,
+		stderr:    process.stderr,
 		processID: processID,
 		client:    c,
 		opts:      opts,
@@ -103,7 +89,7 @@ func (c *ClaudeCodeClient) StreamQuery(ctx context.Context, request *types.Query
 
 // advancedStreamReader handles the actual stream processing
 type advancedStreamReader struct {
-	cmd       *exec.Cmd
+	process   *cliProcess
 	stdout    io.ReadCloser
 	stderr    io.ReadCloser
 	processID string
@@ -118,10 +104,8 @@ func (r *advancedStreamReader) processStream(ctx context.Context, eventChan chan
 	defer close(doneChan)
 	defer r.cleanup()
 
-	// Create scanner for stdout
-	scanner := bufio.NewScanner(r.stdout)
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
+	// Create line reader for stdout
+	lines := r.client.cliLineReader(r.stdout)
 
 	// Read stderr in separate goroutine
 	errChan := make(chan error, 1)
@@ -140,14 +124,23 @@ func (r *advancedStreamReader) processStream(ctx context.Context, eventChan chan
 	var currentMessage *types.StreamMessage
 	contentBlocks := make([]types.ContentBlock, 0)
 
-	for scanner.Scan() {
+	var readErr error
+	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
 		}
 
-		line := scanner.Text()
+		raw, err := lines.next()
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			break
+		}
+		r.client.publishFrame(raw)
+		line := strings.TrimSuffix(string(raw), "\n")
 
 		// Parse streaming event
 		event, err := r.parseStreamEvent(line)
@@ -205,6 +198,14 @@ func (r *advancedStreamReader) processStream(ctx context.Context, eventChan chan
 				}
 			}
 
+		case types.StreamEventCompactBoundary:
+			if event.CompactBoundary != nil && r.opts.OnCompactBoundary != nil {
+				if callbackErr := r.opts.OnCompactBoundary(event.CompactBoundary); callbackErr != nil {
+					// Log callback error or handle it appropriately
+					_ = callbackErr
+				}
+			}
+
 		case types.StreamEventMessageStop:
 			if currentMessage != nil && r.opts.OnComplete != nil {
 				currentMessage.Content = contentBlocks
@@ -226,10 +227,10 @@ func (r *advancedStreamReader) processStream(ctx context.Context, eventChan chan
 		}
 	}
 
-	// Check for scanner error
-	if err := scanner.Err(); err != nil {
+	// Check for read error
+	if readErr != nil {
 		select {
-		case errorChan <- err:
+		case errorChan <- readErr:
 		case <-ctx.Done():
 		}
 	}
@@ -298,8 +299,9 @@ func (r *advancedStreamReader) parseStreamEvent(line string) (*types.StreamEvent
 			var block streamContentBlock
 			if err := json.Unmarshal(blockData, &block); err == nil {
 				event.ContentBlock = &types.ContentBlock{
-					Type: block.Type,
-					Text: block.Text,
+					Type:   block.Type,
+					Text:   block.Text,
+					Source: block.Source,
 				}
 			}
 		}
@@ -336,6 +338,12 @@ func (r *advancedStreamReader) parseStreamEvent(line string) (*types.StreamEvent
 			}
 		}
 
+	case "system":
+		if boundary, ok := types.ParseCompactBoundary([]byte(line)); ok {
+			event.Type = types.StreamEventCompactBoundary
+			event.CompactBoundary = boundary
+		}
+
 	case "error":
 		if errorData, ok := raw["error"]; ok {
 			var errorInfo map[string]any
@@ -365,18 +373,16 @@ func (r *advancedStreamReader) cleanup() {
 	if r.stderr != nil {
 		_ = r.stderr.Close() // Ignore error during cleanup
 	}
-	if r.cmd != nil && r.cmd.Process != nil {
-		_ = r.cmd.Process.Kill() // Ignore error, best effort cleanup
-		if err := r.cmd.Wait(); err != nil {
+	if r.process != nil {
+		_ = r.process.Kill() // Ignore error, best effort cleanup
+		if err := r.process.Wait(); err != nil {
 			// Process already killed, ignore error
 			_ = err
 		}
 	}
 
 	// Remove from active processes
-	r.client.processMu.Lock()
-	delete(r.client.activeProcesses, r.processID)
-	r.client.processMu.Unlock()
+	r.client.untrackProcess(r.processID)
 }
 
 // streamMessage represents message data in stream events
@@ -390,9 +396,10 @@ type streamMessage struct {
 
 // streamContentBlock represents content block data in stream events
 type streamContentBlock struct {
-	Type string `json:"type"`
-	ID   string `json:"id,omitempty"`
-	Text string `json:"text,omitempty"`
+	Type   string               `json:"type"`
+	ID     string               `json:"id,omitempty"`
+	Text   string               `json:"text,omitempty"`
+	Source *types.ContentSource `json:"source,omitempty"`
 }
 
 // streamDelta represents delta updates in stream events
@@ -858,3 +858,23 @@ func BenchmarkStreamingResponse_Collect(b *testing.B) {
 		}
 	}
 }
+
+func TestAdvancedStreamReader_CompactBoundary(t *testing.T) {
+	reader := &advancedStreamReader{opts: types.DefaultStreamOptions()}
+
+	line := `{"type":"system","subtype":"compact_boundary","session_id":"abc","compact_metadata":{"trigger":"auto","pre_tokens":150000}}`
+	event, err := reader.parseStreamEvent(line)
+	require.NoError(t, err)
+
+	assert.Equal(t, types.StreamEventCompactBoundary, event.Type)
+	require.NotNil(t, event.CompactBoundary)
+	assert.Equal(t, types.CompactTriggerAuto, event.CompactBoundary.Trigger)
+	assert.Equal(t, 150000, event.CompactBoundary.PreTokens)
+	assert.Equal(t, "abc", event.CompactBoundary.SessionID)
+
+	// Other system messages are passed through unchanged
+	event, err = reader.parseStreamEvent(`{"type":"system","subtype":"init"}`)
+	require.NoError(t, err)
+	assert.Equal(t, types.StreamEventType("system"), event.Type)
+	assert.Nil(t, event.CompactBoundary)
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/prompts"
+)
+
+// SetPromptLibrary sets the templates used by QueryTemplate. Pass nil to use
+// prompts.Default.
+func (c *ClaudeCodeClient) SetPromptLibrary(library *prompts.Library) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prompts = library
+}
+
+// PromptLibrary returns the templates used by QueryTemplate.
+func (c *ClaudeCodeClient) PromptLibrary() *prompts.Library {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.prompts == nil {
+		return prompts.Default
+	}
+	return c.prompts
+}
+
+// QueryTemplate renders the named prompt template with vars and runs it like
+// QueryMessagesSync. The template's system prompt is used unless options
+// set one.
+//
+// Example usage:
+//
+//	result, err := client.QueryTemplate(ctx, prompts.CodeReview, map[string]any{
+//		"code":  string(source),
+//		"focus": "error handling",
+//	}, nil)
+func (c *ClaudeCodeClient) QueryTemplate(ctx context.Context, name string, vars map[string]any, options *QueryOptions) (*QueryResult, error) {
+	prompt, err := c.PromptLibrary().Render(name, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if prompt.SystemPrompt != "" && (options == nil || options.SystemPrompt == "") {
+		merged := QueryOptions{}
+		if options != nil {
+			merged = *options
+		}
+		merged.SystemPrompt = prompt.SystemPrompt
+		options = &merged
+	}
+
+	return c.QueryMessagesSync(ctx, prompt.Text, options)
+}
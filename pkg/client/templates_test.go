@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/prompts"
+)
+
+func TestQueryTemplate(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: $*"`)
+
+	result, err := client.QueryTemplate(context.Background(), prompts.CodeReview, map[string]any{
+		"code":  "func main() {}",
+		"focus": "naming",
+	}, nil)
+	if err != nil {
+		t.Fatalf("QueryTemplate failed: %v", err)
+	}
+
+	output := result.Messages[len(result.Messages)-1].Content
+	for _, want := range []string{"--append-system-prompt You are an experienced Go reviewer", "focusing on naming", "func main() {}"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in CLI args: %s", want, output)
+		}
+	}
+
+	// Options take precedence over the template's system prompt
+	result, err = client.QueryTemplate(context.Background(), prompts.CodeReview, map[string]any{"code": "x"}, &QueryOptions{SystemPrompt: "Be brief."})
+	if err != nil {
+		t.Fatalf("QueryTemplate failed: %v", err)
+	}
+	if output := result.Messages[len(result.Messages)-1].Content; !strings.Contains(output, "--append-system-prompt Be brief.") {
+		t.Errorf("Expected the caller's system prompt: %s", output)
+	}
+}
+
+func TestQueryTemplate_CustomLibrary(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "Claude: $*"`)
+
+	library := prompts.NewLibrary()
+	library.MustRegister(prompts.Template{Name: "greet", Text: "Say hello to {{.name}}", Required: []string{"name"}})
+	client.SetPromptLibrary(library)
+
+	if _, err := client.QueryTemplate(context.Background(), prompts.CodeReview, nil, nil); !errors.Is(err, prompts.ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound, got %v", err)
+	}
+	if _, err := client.QueryTemplate(context.Background(), "greet", nil, nil); err == nil {
+		t.Error("Expected error for a missing required variable")
+	}
+
+	result, err := client.QueryTemplate(context.Background(), "greet", map[string]any{"name": "Gophers"}, nil)
+	if err != nil {
+		t.Fatalf("QueryTemplate failed: %v", err)
+	}
+	if output := result.Messages[len(result.Messages)-1].Content; !strings.Contains(output, "Say hello to Gophers") {
+		t.Errorf("Unexpected output: %s", output)
+	}
+
+	client.SetPromptLibrary(nil)
+	if client.PromptLibrary() != prompts.Default {
+		t.Error("Expected the default library after SetPromptLibrary(nil)")
+	}
+}
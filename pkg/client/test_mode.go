@@ -0,0 +1,317 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// scriptedCLIVersion is the version the scripted CLI reports to --version.
+const scriptedCLIVersion = "0.0.0-test (Claude Code)"
+
+// scriptedWords are the words replies generated from the seed are made of.
+var scriptedWords = []string{
+	"the", "code", "function", "test", "file", "change", "value", "error",
+	"returns", "updated", "handles", "request", "config", "module", "now",
+	"checks", "input", "output", "and", "with", "for", "each", "case",
+}
+
+// scriptedOutput is the output format a CLI invocation expects.
+type scriptedOutput int
+
+const (
+	// scriptedJSON is a single QueryResponse, for --print
+	scriptedJSON scriptedOutput = iota
+
+	// scriptedStreamJSON is one stream-json message per line
+	scriptedStreamJSON
+
+	// scriptedTranscript is the "Claude:", "Tool:" and "Result:" lines
+	// parsed by QueryMessages
+	scriptedTranscript
+)
+
+// scriptedCLI answers CLI invocations from a TestScript in place of the CLI.
+type scriptedCLI struct {
+	script *types.TestScript
+
+	mu      sync.Mutex
+	used    []bool
+	queries int
+}
+
+func newScriptedCLI(script *types.TestScript) *scriptedCLI {
+	return &scriptedCLI{
+		script: script,
+		used:   make([]bool, len(script.Responses)),
+	}
+}
+
+// next returns the response for a prompt and the query's sequence number,
+// which makes its IDs deterministic.
+func (s *scriptedCLI) next(prompt string) (types.TestResponse, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queries++
+	for i, response := range s.script.Responses {
+		if !s.used[i] && strings.Contains(prompt, response.Match) {
+			s.used[i] = true
+			return response, s.queries
+		}
+	}
+	return types.TestResponse{Text: s.generate(prompt)}, s.queries
+}
+
+// generate returns a reply derived only from the seed and the prompt.
+func (s *scriptedCLI) generate(prompt string) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(prompt)) // Hash writes never fail
+
+	// #nosec G404 - the output must be reproducible, not unpredictable
+	rng := rand.New(rand.NewSource(s.script.Seed ^ int64(hash.Sum64())))
+
+	words := make([]string, 6+rng.Intn(10))
+	for i := range words {
+		words[i] = scriptedWords[rng.Intn(len(scriptedWords))]
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ") + "."
+}
+
+// probe answers an informational invocation such as --version. Other
+// commands, such as mcp list, report nothing.
+func (s *scriptedCLI) probe(args []string) string {
+	if len(args) == 1 && args[0] == "--version" {
+		return scriptedCLIVersion + "\n"
+	}
+	return ""
+}
+
+// start serves an invocation as a process writing the scripted response.
+func (s *scriptedCLI) start(ctx context.Context, args []string, input []byte, options any, captureStderr bool, defaultModel string) *cliProcess {
+	prompt := string(input)
+	if input == nil && len(args) > 0 {
+		prompt = args[len(args)-1]
+	}
+	response, query := s.next(prompt)
+
+	model := flagValue(args, "--model")
+	if model == "" {
+		model = defaultModel
+	}
+	sessionID := flagValue(args, "--session-id")
+	if sessionID == "" {
+		sessionID = flagValue(args, "--resume")
+	}
+	if sessionID == "" {
+		sessionID = flagValue(args, "--session")
+	}
+
+	usage := response.Usage
+	if usage == nil {
+		usage = &types.TokenUsage{
+			InputTokens:  CountTokens(model, prompt),
+			OutputTokens: CountTokens(model, response.Text),
+		}
+		usage.TotalTokens = usage.InputTokens + usage.OutputTokens
+	}
+
+	var stdout, stderr string
+	if response.Error != "" {
+		stderr = response.Error + "\n"
+	} else {
+		format := scriptedJSON
+		if _, ok := options.(*types.Command); ok {
+			format = scriptedTranscript
+		} else if flagValue(args, "--output-format") == "stream-json" || !containsString(args, "--print") {
+			format = scriptedStreamJSON
+		}
+		stdout = renderScripted(format, response, query, model, sessionID, usage)
+	}
+
+	return newScriptedProcess(ctx, response, stdout, stderr, captureStderr)
+}
+
+// renderScripted formats a response as the CLI would print it.
+func renderScripted(format scriptedOutput, response types.TestResponse, query int, model, sessionID string, usage *types.TokenUsage) string {
+	var out strings.Builder
+	line := func(prefix string, value any) {
+		data, _ := json.Marshal(value) // Only maps and SDK types, which always marshal
+		out.WriteString(prefix)
+		out.Write(data)
+		out.WriteByte('\n')
+	}
+	toolID := func(i int) string {
+		return fmt.Sprintf("toolu_test_%d_%d", query, i+1)
+	}
+
+	switch format {
+	case scriptedJSON:
+		line("", &types.QueryResponse{
+			ID:         fmt.Sprintf("msg_test_%d", query),
+			Type:       "message",
+			Role:       types.RoleAssistant,
+			Content:    []types.ContentBlock{types.NewTextBlock(response.Text)},
+			Model:      model,
+			StopReason: "end_turn",
+			Usage:      usage,
+		})
+
+	case scriptedTranscript:
+		for i, tool := range response.ToolUses {
+			line("Tool: ", map[string]any{"id": toolID(i), "name": tool.Name, "input": tool.Input})
+			out.WriteString("Result: " + strings.ReplaceAll(tool.Result, "\n", " ") + "\n")
+		}
+		out.WriteString("Claude: " + response.Text + "\n")
+
+	case scriptedStreamJSON:
+		line("", map[string]any{"type": "system", "subtype": "init", "session_id": sessionID, "model": model})
+		for i, tool := range response.ToolUses {
+			line("", map[string]any{"type": "assistant", "session_id": sessionID, "message": map[string]any{
+				"model": model, "role": "assistant",
+				"content": []any{map[string]any{"type": "tool_use", "id": toolID(i), "name": tool.Name, "input": tool.Input}},
+			}})
+			line("", map[string]any{"type": "user", "session_id": sessionID, "message": map[string]any{
+				"role":    "user",
+				"content": []any{map[string]any{"type": "tool_result", "tool_use_id": toolID(i), "content": tool.Result, "is_error": tool.IsError}},
+			}})
+		}
+		line("", map[string]any{"type": "assistant", "session_id": sessionID, "message": map[string]any{
+			"model": model, "role": "assistant",
+			"content": []any{map[string]any{"type": "text", "text": response.Text}},
+		}})
+		line("", map[string]any{
+			"type": "result", "subtype": "success", "is_error": false, "result": response.Text,
+			"num_turns": len(response.ToolUses) + 1, "session_id": sessionID, "usage": usage,
+		})
+	}
+	return out.String()
+}
+
+// errScriptedKilled is the exit status of a killed scripted process.
+var errScriptedKilled = errors.New("signal: killed")
+
+// scriptedExitError is the exit status of a scripted response with an Error.
+type scriptedExitError struct{}
+
+// Error implements the error interface.
+func (scriptedExitError) Error() string {
+	return "exit status 1"
+}
+
+// ExitCode returns the scripted exit code.
+func (scriptedExitError) ExitCode() int {
+	return 1
+}
+
+// newScriptedProcess returns a process that writes stdout and stderr after
+// the response's delay and exits, or with Timeout waits to be killed.
+// Cancelling ctx kills it, as it would a subprocess.
+func newScriptedProcess(ctx context.Context, response types.TestResponse, stdout, stderr string, captureStderr bool) *cliProcess {
+	type stream struct {
+		reader *io.PipeReader
+		writer *io.PipeWriter
+		data   string
+	}
+	streams := []*stream{{data: stdout}}
+	if captureStderr {
+		streams = append(streams, &stream{data: stderr})
+	}
+	for _, s := range streams {
+		s.reader, s.writer = io.Pipe()
+	}
+
+	killed := make(chan struct{})
+	var killOnce sync.Once
+	kill := func() error {
+		killOnce.Do(func() {
+			close(killed)
+			for _, s := range streams {
+				_ = s.reader.CloseWithError(io.ErrClosedPipe) // Ignore error, unblocks pending writes
+			}
+		})
+		return nil
+	}
+
+	exited := make(chan struct{})
+	var exitErr error
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = kill()
+		case <-exited:
+		}
+	}()
+	go func() {
+		defer close(exited)
+		exitErr = func() error {
+			if response.Delay > 0 {
+				select {
+				case <-time.After(response.Delay):
+				case <-killed:
+					return errScriptedKilled
+				}
+			}
+
+			// Write each stream concurrently so an unread one cannot block the other
+			var wg sync.WaitGroup
+			for _, s := range streams {
+				wg.Add(1)
+				go func(s *stream) {
+					defer wg.Done()
+					_, _ = io.WriteString(s.writer, s.data) // Fails once the reader is closed
+					if !response.Timeout {
+						_ = s.writer.Close() // Ignore error, signals EOF to the reader
+					}
+				}(s)
+			}
+			wg.Wait()
+
+			if response.Timeout {
+				<-killed
+			}
+			select {
+			case <-killed:
+				return errScriptedKilled
+			default:
+			}
+			if response.Error != "" {
+				return scriptedExitError{}
+			}
+			return nil
+		}()
+	}()
+
+	process := &cliProcess{
+		stdout: streams[0].reader,
+		wait: func() error {
+			<-exited
+			return exitErr
+		},
+		kill: kill,
+	}
+	if captureStderr {
+		process.stderr = streams[1].reader
+	}
+	return process
+}
+
+// flagValue returns the value following flag in args, or "" if it is absent.
+func flagValue(args []string, flag string) string {
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == flag {
+			return args[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,252 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func newScriptedClient(t *testing.T, script *types.TestScript) *ClaudeCodeClient {
+	t.Helper()
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		TestScript:       script,
+		WorkingDirectory: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	// Any attempt to start a subprocess fails
+	client.claudeCodeCmd = "/nonexistent/claude"
+	return client
+}
+
+func TestTestScript_Query(t *testing.T) {
+	client := newScriptedClient(t, &types.TestScript{
+		Responses: []types.TestResponse{
+			{Match: "weather", Text: "Sunny.", Usage: &types.TokenUsage{InputTokens: 7, OutputTokens: 2, TotalTokens: 9}},
+			{Text: "First."},
+		},
+	})
+
+	tests := []struct {
+		prompt string
+		want   string
+	}{
+		{"what's the weather?", "Sunny."},
+		{"what's the weather?", "First."}, // The weather response was used
+	}
+	for _, tt := range tests {
+		response, err := client.Query(context.Background(), userRequest(tt.prompt))
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if got := response.Content[0].Text; got != tt.want {
+			t.Errorf("Query(%q) = %q, want %q", tt.prompt, got, tt.want)
+		}
+	}
+
+	usage := client.UsageTracker().Usage()
+	if len(usage) != 1 || usage[0].InputTokens < 7 || usage[0].Requests != 2 {
+		t.Errorf("Expected scripted usage to be tracked, got %+v", usage)
+	}
+}
+
+func TestTestScript_SeededResponses(t *testing.T) {
+	reply := func(seed int64, prompt string) string {
+		client := newScriptedClient(t, &types.TestScript{Seed: seed})
+		response, err := client.Query(context.Background(), userRequest(prompt))
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		return response.Content[0].Text
+	}
+
+	first := reply(42, "refactor the parser")
+	if first == "" || !strings.HasSuffix(first, ".") {
+		t.Errorf("Unexpected generated reply %q", first)
+	}
+	if again := reply(42, "refactor the parser"); again != first {
+		t.Errorf("Same seed and prompt should give the same reply: %q, %q", first, again)
+	}
+	if other := reply(7, "refactor the parser"); other == first {
+		t.Errorf("Different seeds should give different replies, both %q", first)
+	}
+}
+
+func TestTestScript_StreamToolUses(t *testing.T) {
+	client := newScriptedClient(t, &types.TestScript{
+		Responses: []types.TestResponse{{
+			Text: "Fixed.",
+			ToolUses: []types.TestToolUse{
+				{Name: "Read", Input: map[string]any{"file_path": "main.go"}, Result: "package main"},
+				{Name: "Edit", Input: map[string]any{"file_path": "main.go", "new_string": "x"}, Result: "ok"},
+			},
+		}},
+	})
+
+	var (
+		mu     sync.Mutex
+		events []types.FileAccessEvent
+	)
+	client.config.OnFileAccess = func(event types.FileAccessEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	stream, err := client.QueryStream(context.Background(), userRequest("fix main.go"))
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	var lines []string
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if chunk.Done {
+			break
+		}
+		lines = append(lines, chunk.Content)
+	}
+
+	// init, two tool calls with results, the reply and the result
+	if len(lines) != 7 {
+		t.Fatalf("Expected 7 lines, got %d: %v", len(lines), lines)
+	}
+	if !strings.Contains(lines[len(lines)-1], `"result":"Fixed."`) {
+		t.Errorf("Expected the result last, got %s", lines[len(lines)-1])
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 || events[0].Op != types.FileOpRead || events[1].Op != types.FileOpEdit {
+		t.Errorf("Expected a read and an edit, got %+v", events)
+	}
+}
+
+func TestTestScript_QueryMessages(t *testing.T) {
+	client := newScriptedClient(t, &types.TestScript{
+		Responses: []types.TestResponse{{
+			Text:     "There are 3 files.",
+			ToolUses: []types.TestToolUse{{Name: "Bash", Input: map[string]any{"command": "ls"}, Result: "a\nb\nc"}},
+		}},
+	})
+
+	result, err := client.QueryMessagesSync(context.Background(), "count the files", nil)
+	if err != nil {
+		t.Fatalf("QueryMessagesSync failed: %v", err)
+	}
+
+	var tools []string
+	var reply string
+	for _, message := range result.Messages {
+		for _, call := range message.ToolCalls {
+			tools = append(tools, call.Function.Name)
+		}
+		if message.Role == types.RoleAssistant && message.Content != "" {
+			reply = message.Content
+		}
+	}
+	if len(tools) != 1 || tools[0] != "Bash" {
+		t.Errorf("Expected a Bash tool call, got %v", tools)
+	}
+	if reply != "There are 3 files." {
+		t.Errorf("Reply = %q", reply)
+	}
+}
+
+func TestTestScript_Error(t *testing.T) {
+	client := newScriptedClient(t, &types.TestScript{
+		Responses: []types.TestResponse{{Error: "rate limited"}},
+	})
+
+	_, err := client.Query(context.Background(), userRequest("hi"))
+	if err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("Expected the scripted error, got %v", err)
+	}
+}
+
+func TestTestScript_Timeout(t *testing.T) {
+	client := newScriptedClient(t, &types.TestScript{
+		Responses: []types.TestResponse{
+			{Match: "hang", Timeout: true},
+			{Match: "slow", Delay: time.Hour},
+		},
+	})
+
+	for _, prompt := range []string{"hang", "slow"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		_, err := client.Query(ctx, userRequest(prompt))
+		cancel()
+		if err == nil {
+			t.Errorf("Query(%q) should fail once its context is done", prompt)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("Query(%q) took %v", prompt, elapsed)
+		}
+	}
+}
+
+func TestTestScript_RunJob(t *testing.T) {
+	client := newScriptedClient(t, &types.TestScript{
+		Responses: []types.TestResponse{{
+			Text:     "Migrated.",
+			ToolUses: []types.TestToolUse{{Name: "Bash", Input: map[string]any{"command": "make migrate"}, Result: "ok"}},
+		}},
+	})
+	store := NewMemorySessionStore()
+	client.SetSessionStore(store)
+
+	response, err := client.RunJob(context.Background(), "job-1", userRequest("migrate"))
+	if err != nil {
+		t.Fatalf("RunJob failed: %v", err)
+	}
+	if response.Content[0].Text != "Migrated." {
+		t.Errorf("Response = %q", response.Content[0].Text)
+	}
+
+	checkpoint, err := store.LoadCheckpoint(context.Background(), "job-1")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	if !checkpoint.Completed || checkpoint.Turns != 2 {
+		t.Errorf("Checkpoint = %+v", checkpoint)
+	}
+}
+
+func TestTestScript_Probes(t *testing.T) {
+	client := newScriptedClient(t, &types.TestScript{})
+
+	features, err := client.SupportedFeatures(context.Background())
+	if err != nil {
+		t.Fatalf("SupportedFeatures failed: %v", err)
+	}
+	if features.Version != "0.0.0-test" {
+		t.Errorf("Version = %q", features.Version)
+	}
+	if _, err := client.MCPServerStatus(context.Background()); err != nil {
+		t.Errorf("MCPServerStatus failed: %v", err)
+	}
+}
+
+func TestScriptedProcess_Kill(t *testing.T) {
+	process := newScriptedProcess(context.Background(), types.TestResponse{Timeout: true}, "partial\n", "", true)
+	if err := process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Wait(); !errors.Is(err, errScriptedKilled) {
+		t.Errorf("Wait() = %v, want errScriptedKilled", err)
+	}
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// anthropicAPIBaseURL is the default base URL of the Anthropic API
+	anthropicAPIBaseURL = "https://api.anthropic.com"
+
+	// countTokensPath is the path of the count-tokens endpoint
+	countTokensPath = "/v1/messages/count_tokens"
+
+	// messageTokenOverhead estimates the tokens used by role markers per message
+	messageTokenOverhead = 4
+
+	// requestTokenOverhead estimates the fixed tokens added to every request
+	requestTokenOverhead = 3
+)
+
+// CountTokens estimates the number of tokens in text for the given model.
+//
+// The estimate is computed locally without network access by
+// types.EstimateTokens. It is intended for budgeting and typically lands
+// within 10-15% of the real count. All current Claude models share the same
+// estimator; the model parameter keeps call sites stable should that change.
+// Use ClaudeCodeClient.CountTokens for exact counts via the API.
+func CountTokens(model, text string) int {
+	return types.EstimateTokens(text)
+}
+
+// CountMessageTokens estimates the number of input tokens used by a list of
+// messages, including per-message overhead and tool calls.
+func CountMessageTokens(model string, messages []types.Message) int {
+	tokens := requestTokenOverhead
+	for _, msg := range messages {
+		tokens += messageTokenOverhead + CountTokens(model, msg.Content)
+		for _, call := range msg.ToolCalls {
+			tokens += CountTokens(model, call.Function.Name) + CountTokens(model, call.Function.Arguments)
+		}
+	}
+	return tokens
+}
+
+// estimateRequestTokens estimates the input tokens of a query request.
+func estimateRequestTokens(model string, request *types.QueryRequest) int {
+	tokens := CountMessageTokens(model, request.Messages) + CountTokens(model, request.System)
+	for _, tool := range request.Tools {
+		tokens += CountTokens(model, tool.Name) + CountTokens(model, tool.Description)
+		if schema, err := json.Marshal(tool.InputSchema); err == nil {
+			tokens += CountTokens(model, string(schema))
+		}
+	}
+	return tokens
+}
+
+// CountTokens counts the input tokens a request would consume. When the client
+// is configured with an API key or a gateway token, the API's count-tokens
+// endpoint is used for an exact count, reached through the configured gateway,
+// proxy and certificate trust as the CLI would; otherwise, or if the endpoint
+// cannot be reached, the local estimator is used and the result is marked as
+// estimated.
+func (c *ClaudeCodeClient) CountTokens(ctx context.Context, request *types.QueryRequest) (*types.TokenCount, error) {
+	if request == nil {
+		return nil, sdkerrors.NewValidationError("request", "", "required", "request cannot be nil")
+	}
+
+	model := request.Model
+	if model == "" {
+		model = c.settings().Model
+	}
+	if model == "" {
+		model = types.DefaultModel
+	}
+
+	// Count the pinned context the query would be sent with
+	if pinned := c.systemPrompt(request.System); pinned != request.System {
+		withPins := *request
+		withPins.System = pinned
+		request = &withPins
+	}
+
+	if c.hasAPICredentials(ctx) {
+		if tokens, err := c.countTokensAPI(ctx, model, request); err == nil {
+			return &types.TokenCount{Model: model, InputTokens: tokens}, nil
+		} else if ctx.Err() != nil {
+			return nil, sdkerrors.WrapError(ctx.Err(), sdkerrors.CategoryNetwork, "CONTEXT_CANCELED", "request context canceled")
+		}
+	}
+
+	return &types.TokenCount{
+		Model:       model,
+		InputTokens: estimateRequestTokens(model, request),
+		Estimated:   true,
+	}, nil
+}
+
+// countTokensAPI calls the API's count-tokens endpoint.
+func (c *ClaudeCodeClient) countTokensAPI(ctx context.Context, model string, request *types.QueryRequest) (int, error) {
+	type apiMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	body := struct {
+		Model    string       `json:"model"`
+		System   string       `json:"system,omitempty"`
+		Messages []apiMessage `json:"messages"`
+	}{Model: model, System: request.System}
+
+	for _, msg := range request.Messages {
+		switch msg.Role {
+		case types.RoleSystem:
+			if body.System != "" {
+				body.System += "\n\n"
+			}
+			body.System += msg.Content
+		case types.RoleAssistant:
+			body.Messages = append(body.Messages, apiMessage{Role: "assistant", Content: msg.Content})
+		default:
+			body.Messages = append(body.Messages, apiMessage{Role: "user", Content: msg.Content})
+		}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiBaseURL()+countTokensPath, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.setAPIHeaders(ctx, httpReq)
+
+	httpClient, err := c.apiHTTPClient()
+	if err != nil {
+		return 0, err
+	}
+	defer httpClient.CloseIdleConnections()
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("count tokens request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var result struct {
+		InputTokens int `json:"input_tokens"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return 0, err
+	}
+
+	return result.InputTokens, nil
+}
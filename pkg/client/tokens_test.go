@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestCountTokens(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"hello", 2},
+		{"hello world", 4},
+		{"func main() {}", 6},
+		{"12345", 2},
+		{"日本", 2},
+	}
+
+	for _, tt := range tests {
+		if got := CountTokens(types.DefaultModel, tt.text); got != tt.expected {
+			t.Errorf("CountTokens(%q) = %d, want %d", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestCountMessageTokens(t *testing.T) {
+	messages := []types.Message{
+		{Role: types.RoleUser, Content: "hello"},
+		{Role: types.RoleAssistant, Content: "hello world"},
+	}
+
+	expected := requestTokenOverhead + 2*messageTokenOverhead + 2 + 4
+	if got := CountMessageTokens(types.DefaultModel, messages); got != expected {
+		t.Errorf("Expected %d tokens, got %d", expected, got)
+	}
+}
+
+func TestClaudeCodeClient_CountTokens(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != countTokensPath || r.Header.Get("x-api-key") != "sk-test" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte(`{"input_tokens": 42}`))
+	}))
+	defer server.Close()
+
+	request := &types.QueryRequest{
+		Model:    "claude-test",
+		System:   "be brief",
+		Messages: []types.Message{{Role: types.RoleUser, Content: "hello"}},
+	}
+
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+		APIKey:           "sk-test",
+		Environment:      map[string]string{"ANTHROPIC_BASE_URL": server.URL},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	count, err := client.CountTokens(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CountTokens failed: %v", err)
+	}
+	if count.InputTokens != 42 || count.Estimated || count.Model != "claude-test" {
+		t.Errorf("Unexpected count: %+v", count)
+	}
+	if received["system"] != "be brief" {
+		t.Errorf("Expected system prompt to be sent, got %v", received)
+	}
+
+	// Endpoint failures fall back to the estimator
+	server.Close()
+	count, err = client.CountTokens(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CountTokens failed: %v", err)
+	}
+	if !count.Estimated || count.InputTokens == 0 {
+		t.Errorf("Expected estimated fallback count, got %+v", count)
+	}
+}
+
+func TestClaudeCodeClient_CountTokensWithoutAPIKey(t *testing.T) {
+	// Keep the estimator path offline regardless of the host environment
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CountTokens(context.Background(), nil); err == nil {
+		t.Error("Expected error for nil request")
+	}
+
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hello"}}}
+	count, err := client.CountTokens(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CountTokens failed: %v", err)
+	}
+	if !count.Estimated || count.InputTokens != CountMessageTokens(count.Model, request.Messages) {
+		t.Errorf("Unexpected estimated count: %+v", count)
+	}
+}
+
+func TestClaudeCodeClient_CountTokensThroughGateway(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	gateway := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/anthropic"+countTokensPath || r.Header.Get("Authorization") != "Bearer gw-token" || r.Header.Get("X-Team") != "platform" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"input_tokens": 42}`))
+	}))
+	defer gateway.Close()
+
+	// Pin the gateway's self-signed certificate
+	pinned := filepath.Join(t.TempDir(), "gateway.pem")
+	if err := os.WriteFile(pinned, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: gateway.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hello"}}}
+	gatewayConfig := &types.GatewayConfig{
+		BaseURL:   gateway.URL + "/anthropic/",
+		AuthToken: "gw-token",
+		Headers:   map[string]string{"X-Team": "platform"},
+	}
+	tests := []struct {
+		name      string
+		trust     *types.CertificateTrust
+		estimated bool
+	}{
+		{"pinned", &types.CertificateTrust{PinnedCertsFile: pinned}, false},
+		{"ca file", &types.CertificateTrust{CAFile: pinned}, false},
+		{"untrusted", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+				TestMode:         true,
+				WorkingDirectory: t.TempDir(),
+				Gateway:          gatewayConfig,
+				CertificateTrust: tt.trust,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			defer client.Close()
+
+			count, err := client.CountTokens(context.Background(), request)
+			if err != nil {
+				t.Fatalf("CountTokens failed: %v", err)
+			}
+			if count.Estimated != tt.estimated || (!tt.estimated && count.InputTokens != 42) {
+				t.Errorf("Unexpected count: %+v", count)
+			}
+		})
+	}
+}
+
+func TestClaudeCodeClient_EstimateCostThroughProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute URL of the gateway
+		proxied = r.URL.String()
+		_, _ = w.Write([]byte(`{"input_tokens": 1000}`))
+	}))
+	defer proxy.Close()
+
+	client, err := NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+		APIKey:           "sk-test",
+		Proxy:            &types.ProxyConfig{URL: proxy.URL},
+		Gateway:          &types.GatewayConfig{BaseURL: "http://llm-gateway.example"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	estimate, err := client.EstimateCost(context.Background(), &types.QueryRequest{
+		Model:    types.DefaultModel,
+		Messages: []types.Message{{Role: types.RoleUser, Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("EstimateCost failed: %v", err)
+	}
+	if proxied != "http://llm-gateway.example"+countTokensPath {
+		t.Errorf("Expected the request to go through the proxy to the gateway, got %q", proxied)
+	}
+	if estimate.MinInputTokens != 1000 || estimate.MaxInputTokens != 1000 {
+		t.Errorf("Expected the exact count from the gateway, got %+v", estimate)
+	}
+}
@@ -0,0 +1,393 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/mcpserver"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// LocalToolServer is the MCP server name under which ToolRegistry tools are
+// advertised, so the model sees a registered tool "lookup_order" as
+// "mcp__sdk__lookup_order".
+const LocalToolServer = "sdk"
+
+// toolNamePattern matches the tool names the API accepts.
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ToolRegistry holds Go functions the model can call as tools. The client
+// serves them to the CLI over an in-process MCP server, so tool calls run in
+// the calling application. A registry is safe for concurrent use and may be
+// shared by several clients.
+type ToolRegistry struct {
+	server *mcpserver.Server
+}
+
+// NewToolRegistry creates an empty tool registry.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{server: mcpserver.New(LocalToolServer, "1.0.0")}
+}
+
+// Register adds a tool, replacing any tool with the same name. Use
+// mcpserver.NewTool or RegisterTool to derive the input schema from a struct.
+func (r *ToolRegistry) Register(tool mcpserver.Tool) error {
+	if !toolNamePattern.MatchString(tool.Name) {
+		return sdkerrors.NewValidationError("name", tool.Name, "^[a-zA-Z0-9_-]{1,64}$", "tool names must be 1-64 letters, digits, underscores or hyphens")
+	}
+	if tool.Handler == nil {
+		return sdkerrors.NewValidationError("handler", tool.Name, "required", "tool handler cannot be nil")
+	}
+	if len(tool.InputSchema) > 0 && !json.Valid(tool.InputSchema) {
+		return sdkerrors.NewValidationError("input_schema", tool.Name, "json", "tool input schema must be valid JSON")
+	}
+
+	r.server.AddTool(tool)
+	return nil
+}
+
+// RegisterTool adds a tool whose arguments are decoded into the struct In.
+// The input schema is derived from In's `json` and `description` tags:
+//
+//	type lookupArguments struct {
+//		OrderID string `json:"order_id" description:"Order to look up"`
+//	}
+//
+//	err := client.RegisterTool(registry, "lookup_order", "Look up an order",
+//		func(ctx context.Context, args lookupArguments) (string, error) {
+//			return orders.Describe(ctx, args.OrderID)
+//		})
+func RegisterTool[In any](r *ToolRegistry, name, description string, handler func(ctx context.Context, arguments In) (string, error)) error {
+	tool, err := mcpserver.NewTool(name, description, handler)
+	if err != nil {
+		return sdkerrors.NewValidationError("arguments", name, "struct", err.Error())
+	}
+	return r.Register(tool)
+}
+
+// Tools returns the registered tools sorted by name.
+func (r *ToolRegistry) Tools() []mcpserver.Tool {
+	return r.server.Tools()
+}
+
+// Call runs a registered tool in-process, as the model would.
+func (r *ToolRegistry) Call(ctx context.Context, name string, arguments json.RawMessage) (*mcpserver.ToolResult, error) {
+	return r.server.CallTool(ctx, name, arguments)
+}
+
+// SetToolRegistry makes the registry's tools available to queries started
+// after the call, and allows them without prompting. Pass nil to remove them.
+// Tools registered later are picked up by the next query.
+func (c *ClaudeCodeClient) SetToolRegistry(registry *ToolRegistry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.toolRegistry = registry
+}
+
+// currentToolRegistry returns the registry set with SetToolRegistry, or nil.
+func (c *ClaudeCodeClient) currentToolRegistry() *ToolRegistry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.toolRegistry
+}
+
+// hasLocalTools reports whether queries should be given the local tools.
+func (c *ClaudeCodeClient) hasLocalTools() bool {
+	registry := c.currentToolRegistry()
+	return registry != nil && len(registry.Tools()) > 0
+}
+
+// localToolServer serves the client's ToolRegistry to the CLI over MCP's
+// HTTP transport on loopback.
+type localToolServer struct {
+	server *http.Server
+	url    string
+	token  string
+
+	// dir holds the --mcp-config files, which carry the token and so are
+	// not passed on the command line where other users could read them
+	dir     string
+	configs map[string]bool
+}
+
+// localToolArgs starts the local tool server on first use and returns the
+// --mcp-config flag pointing the CLI at it, or nil when no tools are
+// registered. Tool calls made through the flag are attributed to route, which
+// identifies the query so that tool progress reaches its message stream.
+//
+// The flag names a config file readable only by the current user; pass the
+// arguments to releaseLocalToolArgs once the CLI has exited.
+func (c *ClaudeCodeClient) localToolArgs(route string) ([]string, error) {
+	if !c.hasLocalTools() {
+		return nil, nil
+	}
+
+	c.localToolsMu.Lock()
+	defer c.localToolsMu.Unlock()
+
+	if c.localTools == nil {
+		token := make([]byte, 32)
+		if _, err := rand.Read(token); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "LOCAL_TOOL_SERVER", "failed to generate local tool token")
+		}
+		dir, err := os.MkdirTemp("", "claude-tools-")
+		if err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "LOCAL_TOOL_SERVER", "failed to create local tool config directory")
+		}
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			_ = os.RemoveAll(dir) // Ignore error during cleanup
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "LOCAL_TOOL_SERVER", "failed to start local tool server")
+		}
+
+		tools := &localToolServer{
+			url:     "http://" + listener.Addr().String() + "/mcp/",
+			token:   hex.EncodeToString(token),
+			dir:     dir,
+			configs: make(map[string]bool),
+		}
+		tools.server = &http.Server{
+			Handler:           http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { c.serveLocalTools(tools.token, w, r) }),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() { _ = tools.server.Serve(listener) }() // Serve returns when the server is closed
+		c.localTools = tools
+	}
+
+	config, err := json.Marshal(map[string]any{
+		"mcpServers": map[string]any{
+			LocalToolServer: map[string]any{
+				"type":    "http",
+				"url":     c.localTools.url + url.PathEscape(route),
+				"headers": map[string]string{"Authorization": "Bearer " + c.localTools.token},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// CreateTemp makes the file readable by the current user only
+	file, err := os.CreateTemp(c.localTools.dir, "mcp-*.json")
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "LOCAL_TOOL_SERVER", "failed to write local tool config")
+	}
+	_, err = file.Write(config)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name()) // Ignore error during cleanup
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "LOCAL_TOOL_SERVER", "failed to write local tool config")
+	}
+	c.localTools.configs[file.Name()] = true
+	return []string{"--mcp-config", file.Name()}, nil
+}
+
+// releaseLocalToolArgs removes the local tool config files named in the
+// arguments of a CLI invocation that has exited.
+func (c *ClaudeCodeClient) releaseLocalToolArgs(args []string) {
+	c.localToolsMu.Lock()
+	defer c.localToolsMu.Unlock()
+
+	if c.localTools == nil {
+		return
+	}
+	for i, arg := range args {
+		if arg == "--mcp-config" && i+1 < len(args) && c.localTools.configs[args[i+1]] {
+			_ = os.Remove(args[i+1]) // Ignore error, the file is only a leftover
+			delete(c.localTools.configs, args[i+1])
+		}
+	}
+}
+
+// serveLocalTools answers one MCP request from the CLI with the client's
+// current ToolRegistry.
+func (c *ClaudeCodeClient) serveLocalTools(token string, w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	registry := c.currentToolRegistry()
+	if registry == nil {
+		registry = NewToolRegistry()
+	}
+
+	route := strings.TrimPrefix(r.URL.Path, "/mcp/")
+	ctx, done := c.startToolCall(r.Context(), route)
+	defer done()
+
+	ctx = mcpserver.WithProgress(ctx, func(progress mcpserver.Progress) {
+		c.reportToolProgress(route, types.ToolProgress{
+			Tool:    progress.Tool,
+			Percent: progress.Percent,
+			Message: progress.Message,
+			Time:    time.Now(),
+		})
+	})
+	registry.server.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// startToolCall tracks a local tool call on route until done is called, so
+// that Interrupt, Close and the end of its query can cancel the handler. The
+// request context is also cancelled when the CLI disconnects, as it does when
+// its query is cancelled.
+func (c *ClaudeCodeClient) startToolCall(ctx context.Context, route string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	call := &localToolCall{route: route, cancel: cancel}
+
+	c.localToolsMu.Lock()
+	if c.toolCalls == nil {
+		c.toolCalls = make(map[*localToolCall]struct{})
+	}
+	c.toolCalls[call] = struct{}{}
+	c.localToolsMu.Unlock()
+
+	return ctx, func() {
+		c.localToolsMu.Lock()
+		delete(c.toolCalls, call)
+		c.localToolsMu.Unlock()
+		cancel()
+	}
+}
+
+// watchToolCalls cancels the local tool calls on route when ctx is done or
+// the returned function is called at the end of the query.
+func (c *ClaudeCodeClient) watchToolCalls(ctx context.Context, route string) func() {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.cancelToolCalls(route, false)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		close(done)
+		c.cancelToolCalls(route, false)
+	}
+}
+
+// localToolCall is a local tool call in flight.
+type localToolCall struct {
+	route  string
+	cancel context.CancelFunc
+}
+
+// cancelToolCalls cancels the contexts of the local tool calls in flight on
+// route, or of all calls when all is set.
+func (c *ClaudeCodeClient) cancelToolCalls(route string, all bool) {
+	c.localToolsMu.Lock()
+	defer c.localToolsMu.Unlock()
+
+	for call := range c.toolCalls {
+		if all || call.route == route {
+			call.cancel()
+		}
+	}
+}
+
+// reportToolProgress delivers a tool's progress to OnToolProgress and to the
+// message stream of the query on route, if it has one.
+func (c *ClaudeCodeClient) reportToolProgress(route string, progress types.ToolProgress) {
+	if onProgress := c.settings().OnToolProgress; onProgress != nil {
+		onProgress(progress)
+	}
+
+	c.localToolsMu.Lock()
+	stream := c.progressStreams[route]
+	c.localToolsMu.Unlock()
+	if stream != nil {
+		stream.send(&types.Message{
+			Role:    types.RoleSystem,
+			Content: progress.String(),
+			Metadata: map[string]any{
+				"subtype": "tool_progress",
+				"tool":    progress.Tool,
+				"percent": progress.Percent,
+				"message": progress.Message,
+			},
+			Timestamp: progress.Time,
+		})
+	}
+}
+
+// progressStream forwards tool progress into a query's message channel
+// until the query finishes.
+type progressStream struct {
+	messages chan<- *types.Message
+	done     chan struct{}
+	closed   bool
+	mu       sync.Mutex
+}
+
+// send delivers a message unless the query has finished.
+func (s *progressStream) send(message *types.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.messages <- message:
+	case <-s.done:
+	}
+}
+
+// streamToolProgress sends progress from tool calls on route to messages as
+// system messages. The returned function stops forwarding and must be called
+// before messages is closed.
+func (c *ClaudeCodeClient) streamToolProgress(route string, messages chan<- *types.Message) func() {
+	stream := &progressStream{messages: messages, done: make(chan struct{})}
+
+	c.localToolsMu.Lock()
+	if c.progressStreams == nil {
+		c.progressStreams = make(map[string]*progressStream)
+	}
+	c.progressStreams[route] = stream
+	c.localToolsMu.Unlock()
+
+	return func() {
+		c.localToolsMu.Lock()
+		if c.progressStreams[route] == stream {
+			delete(c.progressStreams, route)
+		}
+		c.localToolsMu.Unlock()
+
+		// Release a blocked send, then wait for it to return
+		close(stream.done)
+		stream.mu.Lock()
+		stream.closed = true
+		stream.mu.Unlock()
+	}
+}
+
+// stopLocalTools shuts down the local tool server, if it was started, and
+// cancels the calls in flight.
+func (c *ClaudeCodeClient) stopLocalTools() {
+	c.localToolsMu.Lock()
+	defer c.localToolsMu.Unlock()
+
+	if c.localTools != nil {
+		_ = c.localTools.server.Close()    // Ignore error, the CLI sees failed tool calls
+		_ = os.RemoveAll(c.localTools.dir) // Ignore error during cleanup
+		c.localTools = nil
+	}
+	for call := range c.toolCalls {
+		call.cancel()
+	}
+}
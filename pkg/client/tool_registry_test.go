@@ -0,0 +1,342 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/mcpserver"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+type lookupArguments struct {
+	OrderID string `json:"order_id" description:"Order to look up"`
+}
+
+func newOrderRegistry(t *testing.T) *ToolRegistry {
+	t.Helper()
+	registry := NewToolRegistry()
+	err := RegisterTool(registry, "lookup_order", "Look up an order", func(_ context.Context, args lookupArguments) (string, error) {
+		return "order " + args.OrderID + " shipped", nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterTool failed: %v", err)
+	}
+	return registry
+}
+
+func TestToolRegistry_Register(t *testing.T) {
+	registry := newOrderRegistry(t)
+
+	result, err := registry.Call(context.Background(), "lookup_order", json.RawMessage(`{"order_id":"A-1"}`))
+	if err != nil || result.IsError || result.Text() != "order A-1 shipped" {
+		t.Errorf("Unexpected result %+v (%v)", result, err)
+	}
+	if tools := registry.Tools(); len(tools) != 1 || !strings.Contains(string(tools[0].InputSchema), `"required":["order_id"]`) {
+		t.Errorf("Unexpected tools: %+v", tools)
+	}
+
+	handler := func(context.Context, json.RawMessage) (string, error) { return "", nil }
+	for _, tool := range []mcpserver.Tool{
+		{Name: "bad name", Handler: handler},
+		{Name: "", Handler: handler},
+		{Name: "no_handler"},
+		{Name: "bad_schema", InputSchema: json.RawMessage(`{`), Handler: handler},
+	} {
+		if err := registry.Register(tool); err == nil {
+			t.Errorf("Expected %+v to be rejected", tool)
+		}
+	}
+	if err := RegisterTool(registry, "scalar", "", func(context.Context, string) (string, error) { return "", nil }); err == nil {
+		t.Error("Expected non-struct arguments to be rejected")
+	}
+}
+
+func TestClient_LocalTools(t *testing.T) {
+	client := newFakeCLIClient(t, `echo`)
+	request := &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}
+
+	args, err := client.buildClaudeArgs(request, false)
+	if err != nil {
+		t.Fatalf("buildClaudeArgs failed: %v", err)
+	}
+	if containsString(args, "--mcp-config") {
+		t.Errorf("Expected no local tool server without a registry, got %v", args)
+	}
+
+	client.SetToolRegistry(newOrderRegistry(t))
+	args, err = client.buildClaudeArgs(request, false)
+	if err != nil {
+		t.Fatalf("buildClaudeArgs failed: %v", err)
+	}
+
+	for i, arg := range args {
+		if arg == "--allowedTools" && !strings.Contains(args[i+1], "mcp__sdk") {
+			t.Errorf("Expected local tools to be allowed, got %q", args[i+1])
+		}
+	}
+	server := parseLocalToolServer(t, args)
+
+	// The token stays off the command line, in a file only the user can read
+	var configPath string
+	for i, arg := range args {
+		if arg == "--mcp-config" {
+			configPath = args[i+1]
+		}
+	}
+	if strings.Contains(strings.Join(args, " "), server.Headers["Authorization"]) {
+		t.Error("Expected the local tool token not to be passed as an argument")
+	}
+	if info, err := os.Stat(configPath); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected a 0600 config file, got %v, %v", info, err)
+	}
+	client.releaseLocalToolArgs(args)
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the config file to be removed once released, got %v", err)
+	}
+
+	call := func(authorization string) (*http.Response, string) {
+		return callLocalTool(t, server.URL, authorization, "lookup_order", `{"order_id":"B-2"}`)
+	}
+
+	if resp, _ := call("Bearer wrong"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a bad token to be rejected, got %d", resp.StatusCode)
+	}
+	if resp, body := call(server.Headers["Authorization"]); resp.StatusCode != http.StatusOK || !strings.Contains(body, "order B-2 shipped") {
+		t.Errorf("Unexpected tool call response %d: %s", resp.StatusCode, body)
+	}
+
+	resolution, err := client.ResolveTools(nil)
+	if err != nil {
+		t.Fatalf("ResolveTools failed: %v", err)
+	}
+	if tool := resolution.Tool("mcp__sdk"); tool == nil || tool.Decision != ToolAllowed {
+		t.Errorf("Expected local tools to resolve as allowed, got %+v", tool)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := http.Post(server.URL, "application/json", strings.NewReader(`{}`)); err == nil {
+		t.Error("Expected the local tool server to stop on Close")
+	}
+}
+
+// localToolConfig is the local tool server entry of --mcp-config.
+type localToolConfig struct {
+	Type    string            `json:"type"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+}
+
+// parseLocalToolServer returns the local tool server passed to the CLI.
+func parseLocalToolServer(t *testing.T, args []string) localToolConfig {
+	t.Helper()
+	for i, arg := range args {
+		if arg != "--mcp-config" || i+1 >= len(args) {
+			continue
+		}
+		data, err := os.ReadFile(args[i+1])
+		if err != nil {
+			t.Fatalf("Failed to read --mcp-config: %v", err)
+		}
+		var config struct {
+			MCPServers map[string]localToolConfig `json:"mcpServers"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			t.Fatalf("Invalid --mcp-config %q: %v", data, err)
+		}
+		if server, ok := config.MCPServers[LocalToolServer]; ok && server.Type == "http" {
+			return server
+		}
+	}
+	t.Fatalf("Expected an http %s server in %v", LocalToolServer, args)
+	return localToolConfig{}
+}
+
+// callLocalTool calls a tool on the local tool server as the CLI would.
+func callLocalTool(t *testing.T, url, authorization, name, arguments string) (*http.Response, string) {
+	t.Helper()
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"` + name + `","arguments":` + arguments + `}}`
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Authorization", authorization)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Tool call failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp, string(data)
+}
+
+// waitForFile waits for the fake CLI to write a file and returns its content.
+func waitForFile(t *testing.T, path string) string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+			return string(data)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %s", path)
+	return ""
+}
+
+func TestQueryMessages_ToolProgress(t *testing.T) {
+	client := newFakeCLIClient(t, `for arg in "$@"; do
+	[ -n "$next" ] && cp "$arg" mcp.json && next=
+	[ "$arg" = --mcp-config ] && next=1
+done
+while [ ! -f release ]; do sleep 0.05; done
+echo "Claude: indexed"`)
+
+	registry := NewToolRegistry()
+	err := registry.Register(mcpserver.Tool{
+		Name: "index",
+		Handler: func(ctx context.Context, _ json.RawMessage) (string, error) {
+			mcpserver.ReportProgress(ctx, 40, "scanning")
+			mcpserver.ReportLog(ctx, "found 3 files")
+			return "indexed", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	client.SetToolRegistry(registry)
+
+	var mu sync.Mutex
+	var reported []types.ToolProgress
+	client.config.OnToolProgress = func(progress types.ToolProgress) {
+		mu.Lock()
+		reported = append(reported, progress)
+		mu.Unlock()
+	}
+
+	messages, err := client.QueryMessages(context.Background(), "index the repo", nil)
+	if err != nil {
+		t.Fatalf("QueryMessages failed: %v", err)
+	}
+
+	// Call the tool as the CLI would, through the server it was given
+	dir := client.currentWorkingDir()
+	waitForFile(t, filepath.Join(dir, "mcp.json"))
+	server := parseLocalToolServer(t, []string{"--mcp-config", filepath.Join(dir, "mcp.json")})
+	if resp, body := callLocalTool(t, server.URL, server.Headers["Authorization"], "index", `{}`); resp.StatusCode != http.StatusOK || !strings.Contains(body, "indexed") {
+		t.Fatalf("Unexpected tool call response %d: %s", resp.StatusCode, body)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "release"), []byte("1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var progress []string
+	for message := range messages {
+		if message.Role == types.RoleSystem && message.Metadata["subtype"] == "tool_progress" {
+			progress = append(progress, message.Content)
+		}
+	}
+	if want := []string{"index: 40% scanning", "index: found 3 files"}; !reflect.DeepEqual(progress, want) {
+		t.Errorf("Expected progress messages %v, got %v", want, progress)
+	}
+	if entries, err := os.ReadDir(client.localTools.dir); err != nil || len(entries) != 0 {
+		t.Errorf("Expected the config file to be removed when the CLI exited, got %v, %v", entries, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 2 || reported[0].Tool != "index" || reported[0].Percent != 40 || reported[1].Percent != -1 {
+		t.Errorf("Unexpected OnToolProgress updates: %+v", reported)
+	}
+}
+
+func TestLocalTools_Interrupt(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(client *ClaudeCodeClient, cancel context.CancelFunc)
+	}{
+		{"interrupt", func(client *ClaudeCodeClient, _ context.CancelFunc) { _ = client.Interrupt() }},
+		{"context cancelled", func(_ *ClaudeCodeClient, cancel context.CancelFunc) { cancel() }},
+		{"close", func(client *ClaudeCodeClient, _ context.CancelFunc) { _ = client.Close() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeCLIClient(t, `for arg in "$@"; do
+	[ -n "$next" ] && cp "$arg" mcp.json && next=
+	[ "$arg" = --mcp-config ] && next=1
+done
+exec sleep 30`)
+
+			cancelled := make(chan struct{})
+			registry := NewToolRegistry()
+			_ = registry.Register(mcpserver.Tool{
+				Name: "migrate",
+				Handler: func(ctx context.Context, _ json.RawMessage) (string, error) {
+					<-ctx.Done()
+					close(cancelled)
+					return "", ctx.Err()
+				},
+			})
+			client.SetToolRegistry(registry)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			messages, err := client.QueryMessages(ctx, "migrate the database", nil)
+			if err != nil {
+				t.Fatalf("QueryMessages failed: %v", err)
+			}
+
+			waitForFile(t, filepath.Join(client.currentWorkingDir(), "mcp.json"))
+			server := parseLocalToolServer(t, []string{"--mcp-config", filepath.Join(client.currentWorkingDir(), "mcp.json")})
+			response := make(chan string, 1)
+			go func() {
+				body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"migrate","arguments":{}}}`
+				req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(body))
+				req.Header.Set("Authorization", server.Headers["Authorization"])
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					response <- err.Error()
+					return
+				}
+				defer resp.Body.Close()
+				data, _ := io.ReadAll(resp.Body)
+				response <- string(data)
+			}()
+			for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+				client.localToolsMu.Lock()
+				calls := len(client.toolCalls)
+				client.localToolsMu.Unlock()
+				if calls == 1 {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("Tool call did not start")
+				}
+			}
+
+			tt.stop(client, cancel)
+
+			select {
+			case <-cancelled:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Expected the tool handler's context to be cancelled")
+			}
+			if tt.name != "close" {
+				if body := <-response; !strings.Contains(body, "tool call interrupted") {
+					t.Errorf("Expected an interrupted tool result, got %s", body)
+				}
+			}
+			for range messages {
+				// Drain until the query ends
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// ToolDecision is how a tool is treated by a query's permission rules.
+type ToolDecision string
+
+const (
+	// ToolAllowed means the tool runs without asking for permission
+	ToolAllowed ToolDecision = "allow"
+
+	// ToolDenied means the tool cannot be used
+	ToolDenied ToolDecision = "deny"
+
+	// ToolDefault means no rule covers the whole tool, so the permission
+	// mode decides
+	ToolDefault ToolDecision = "default"
+)
+
+// ResolvedTool reports the effective permission of one tool.
+type ResolvedTool struct {
+	// Name is the tool name, such as "Bash" or "mcp__github"
+	Name string
+
+	// Decision applies to every use of the tool not covered by a scoped rule
+	Decision ToolDecision
+
+	// Rule is the rule that made the decision ("" for ToolDefault)
+	Rule string
+
+	// AllowedWhen and DeniedWhen list scoped rules, such as
+	// "Bash(npm run *)", that override the decision for matching uses
+	AllowedWhen []string
+	DeniedWhen  []string
+}
+
+// ToolResolution is the effective tool set of a query, as reported by
+// ResolveTools.
+type ToolResolution struct {
+	// Allowed and Disallowed are the rules passed to --allowedTools and
+	// --disallowedTools after wildcards are expanded
+	Allowed    []string
+	Disallowed []string
+
+	// Tools reports each built-in tool, each enabled MCP server, the
+	// ToolRegistry server and any extra tools passed to ResolveTools
+	Tools []ResolvedTool
+}
+
+// Tool returns the resolution of a tool, or nil if it was not resolved.
+func (r *ToolResolution) Tool(name string) *ResolvedTool {
+	for i := range r.Tools {
+		if r.Tools[i].Name == name {
+			return &r.Tools[i]
+		}
+	}
+	return nil
+}
+
+// String formats the resolution as a table for debugging.
+func (r *ToolResolution) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOOL\tDECISION\tRULE\tSCOPED RULES")
+	for _, tool := range r.Tools {
+		var scoped []string
+		for _, rule := range tool.AllowedWhen {
+			scoped = append(scoped, "allow "+rule)
+		}
+		for _, rule := range tool.DeniedWhen {
+			scoped = append(scoped, "deny "+rule)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", tool.Name, tool.Decision, tool.Rule, strings.Join(scoped, ", "))
+	}
+	_ = w.Flush() // Ignore error, writes to a strings.Builder
+	return b.String()
+}
+
+// ResolveTools reports the effective tool set of a query with the given
+// options (nil uses the defaults and the client's profile), for debugging
+// permission rules. Extra tool names, such as MCP tools returned by
+// ListMCPTools, are resolved along with the built-in tools.
+//
+// Rules are applied in this order:
+//  1. A DisallowedTools rule naming the whole tool denies it, even if it is
+//     also allowed.
+//  2. Otherwise an AllowedTools rule naming the whole tool allows it.
+//  3. Otherwise the permission mode decides.
+//
+// Scoped rules such as "Bash(npm run *)" only cover matching uses of a tool,
+// and a scoped deny rule also wins over an allow rule. Wildcards may appear in
+// tool names: "mcp__fs__*" covers every tool of the fs MCP server and
+// "Notebook*" every built-in tool starting with Notebook.
+func (c *ClaudeCodeClient) ResolveTools(options *QueryOptions, tools ...string) (*ToolResolution, error) {
+	options, err := c.applyProfile(options)
+	if err != nil {
+		return nil, err
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = &QueryOptions{}
+	}
+
+	allowed, disallowed, err := c.permissionRules(c.currentWorkingDir(), options.AllowedTools, options.DisallowedTools)
+	if err != nil {
+		return nil, err
+	}
+
+	names := append([]string(nil), BuiltinTools...)
+	servers := make([]string, 0)
+	for name := range c.mcpManager.GetEnabledServers() {
+		servers = append(servers, "mcp__"+name)
+	}
+	if c.hasLocalTools() {
+		servers = append(servers, "mcp__"+LocalToolServer)
+	}
+	sort.Strings(servers)
+	names = append(names, servers...)
+	for _, tool := range tools {
+		if !containsString(names, tool) {
+			names = append(names, tool)
+		}
+	}
+
+	resolution := &ToolResolution{Allowed: allowed, Disallowed: disallowed}
+	for _, name := range names {
+		resolution.Tools = append(resolution.Tools, resolveTool(name, allowed, disallowed))
+	}
+	return resolution, nil
+}
+
+// resolveTool applies the expanded allow and deny rules to a tool.
+func resolveTool(name string, allowed, disallowed []string) ResolvedTool {
+	tool := ResolvedTool{Name: name, Decision: ToolDefault}
+
+	for _, rule := range disallowed {
+		ruleName, scoped := splitToolRule(rule)
+		if !matchToolName(ruleName, name) {
+			continue
+		}
+		if scoped {
+			tool.DeniedWhen = append(tool.DeniedWhen, rule)
+		} else if tool.Decision != ToolDenied {
+			tool.Decision, tool.Rule = ToolDenied, rule
+		}
+	}
+
+	for _, rule := range allowed {
+		ruleName, scoped := splitToolRule(rule)
+		if !matchToolName(ruleName, name) || tool.Decision == ToolDenied {
+			continue
+		}
+		if scoped {
+			tool.AllowedWhen = append(tool.AllowedWhen, rule)
+		} else if tool.Decision == ToolDefault {
+			tool.Decision, tool.Rule = ToolAllowed, rule
+		}
+	}
+
+	return tool
+}
+
+// expandToolRules rewrites wildcard tool names into rules the CLI accepts:
+// "mcp__server__*" becomes the server rule "mcp__server", and a built-in
+// name pattern becomes one rule per matching tool. Scoped rules keep their
+// specifier, which the CLI matches itself.
+func expandToolRules(rules []string) []string {
+	var expanded []string
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		name, _ := splitToolRule(rule)
+		specifier := rule[len(name):]
+
+		switch {
+		case !strings.Contains(name, "*"):
+			expanded = append(expanded, rule)
+		case strings.HasPrefix(name, "mcp__"):
+			expanded = append(expanded, strings.TrimSuffix(name, "__*"))
+		default:
+			for _, tool := range BuiltinTools {
+				if matched, _ := path.Match(name, tool); matched {
+					expanded = append(expanded, tool+specifier)
+				}
+			}
+		}
+	}
+
+	// Drop duplicates introduced by overlapping patterns
+	unique := expanded[:0]
+	for i, rule := range expanded {
+		if !containsString(expanded[:i], rule) {
+			unique = append(unique, rule)
+		}
+	}
+	return unique
+}
+
+// splitToolRule returns the tool name of a rule and whether it has a
+// parenthesized specifier.
+func splitToolRule(rule string) (string, bool) {
+	if open := strings.IndexByte(rule, '('); open >= 0 {
+		return rule[:open], true
+	}
+	return rule, false
+}
+
+// matchToolName reports whether a rule's tool name covers a tool. A server
+// rule such as "mcp__github" covers all of the server's tools.
+func matchToolName(ruleName, tool string) bool {
+	if ruleName == tool {
+		return true
+	}
+	if matched, _ := path.Match(ruleName, tool); matched {
+		return true
+	}
+	return strings.HasPrefix(ruleName, "mcp__") && !strings.Contains(strings.TrimPrefix(ruleName, "mcp__"), "__") &&
+		strings.HasPrefix(tool, ruleName+"__")
+}
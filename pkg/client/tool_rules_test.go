@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestExpandToolRules(t *testing.T) {
+	tests := []struct {
+		rules []string
+		want  []string
+	}{
+		{[]string{"Read", "Bash(git diff:*)"}, []string{"Read", "Bash(git diff:*)"}},
+		{[]string{"mcp__fs__*"}, []string{"mcp__fs"}},
+		{[]string{"Notebook*"}, []string{"NotebookEdit", "NotebookRead"}},
+		{[]string{"Web*(domain:example.com)"}, []string{"WebFetch(domain:example.com)", "WebSearch(domain:example.com)"}},
+		{[]string{"Bash(npm run *)"}, []string{"Bash(npm run *)"}},
+		{[]string{"*Edit", "MultiEdit"}, []string{"Edit", "MultiEdit", "NotebookEdit"}},
+	}
+
+	for _, tt := range tests {
+		if got := expandToolRules(tt.rules); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandToolRules(%v) = %v, want %v", tt.rules, got, tt.want)
+		}
+	}
+}
+
+func TestResolveTools(t *testing.T) {
+	client := newFakeCLIClient(t, `echo`)
+	if err := client.AddMCPServer(context.Background(), "fs", &types.MCPServerConfig{Command: "fs-server", Enabled: true}); err != nil {
+		t.Fatalf("AddMCPServer failed: %v", err)
+	}
+
+	resolution, err := client.ResolveTools(&QueryOptions{
+		AllowedTools:    []string{"Read", "Notebook*", "mcp__fs__*", "Bash(npm run *)", "Write"},
+		DisallowedTools: []string{"NotebookEdit", "Bash(rm *)", "mcp__fs__delete_file"},
+	}, "mcp__fs__read_file", "mcp__fs__delete_file")
+	if err != nil {
+		t.Fatalf("ResolveTools failed: %v", err)
+	}
+
+	if want := []string{"Read", "NotebookEdit", "NotebookRead", "mcp__fs", "Bash(npm run *)", "Write"}; !reflect.DeepEqual(resolution.Allowed, want) {
+		t.Errorf("Expected allowed rules %v, got %v", want, resolution.Allowed)
+	}
+
+	tests := []struct {
+		tool     string
+		decision ToolDecision
+		rule     string
+	}{
+		{"Read", ToolAllowed, "Read"},
+		{"NotebookRead", ToolAllowed, "NotebookRead"},
+		{"NotebookEdit", ToolDenied, "NotebookEdit"}, // Deny wins over the wildcard allow
+		{"Grep", ToolDefault, ""},
+		{"Bash", ToolDefault, ""},
+		{"mcp__fs", ToolAllowed, "mcp__fs"},
+		{"mcp__fs__read_file", ToolAllowed, "mcp__fs"},
+		{"mcp__fs__delete_file", ToolDenied, "mcp__fs__delete_file"},
+	}
+	for _, tt := range tests {
+		tool := resolution.Tool(tt.tool)
+		if tool == nil {
+			t.Errorf("Expected %s to be resolved", tt.tool)
+			continue
+		}
+		if tool.Decision != tt.decision || tool.Rule != tt.rule {
+			t.Errorf("%s: expected %s by %q, got %s by %q", tt.tool, tt.decision, tt.rule, tool.Decision, tool.Rule)
+		}
+	}
+
+	bash := resolution.Tool("Bash")
+	if !reflect.DeepEqual(bash.AllowedWhen, []string{"Bash(npm run *)"}) || !reflect.DeepEqual(bash.DeniedWhen, []string{"Bash(rm *)"}) {
+		t.Errorf("Expected scoped Bash rules, got allow %v deny %v", bash.AllowedWhen, bash.DeniedWhen)
+	}
+	if output := resolution.String(); !strings.Contains(output, "deny Bash(rm *)") {
+		t.Errorf("Expected table to list scoped rules, got:\n%s", output)
+	}
+}
+
+func TestResolveTools_InvalidWildcards(t *testing.T) {
+	client := newFakeCLIClient(t, `echo`)
+
+	for _, rule := range []string{"mcp__fs__read_*", "mcp__*", "Teleport*"} {
+		if _, err := client.ResolveTools(&QueryOptions{AllowedTools: []string{rule}}); err == nil {
+			t.Errorf("Expected %q to be rejected", rule)
+		}
+	}
+}
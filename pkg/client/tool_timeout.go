@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// ToolTimeoutWildcard is the ToolTimeouts key applied to tools without their own entry.
+const ToolTimeoutWildcard = "*"
+
+// toolTimeout returns the deadline for a tool, preferring an exact entry over the wildcard.
+func toolTimeout(timeouts map[string]time.Duration, name string) (time.Duration, bool) {
+	if timeout, ok := timeouts[name]; ok && timeout > 0 {
+		return timeout, true
+	}
+	if timeout, ok := timeouts[ToolTimeoutWildcard]; ok && timeout > 0 {
+		return timeout, true
+	}
+	return 0, false
+}
+
+// mergeToolTimeouts returns the client timeouts with per-query overrides applied.
+func (c *ClaudeCodeClient) mergeToolTimeouts(overrides map[string]time.Duration) map[string]time.Duration {
+	defaults := c.settings().ToolTimeouts
+	if len(overrides) == 0 {
+		return defaults
+	}
+
+	merged := make(map[string]time.Duration, len(defaults)+len(overrides))
+	for name, timeout := range defaults {
+		merged[name] = timeout
+	}
+	for name, timeout := range overrides {
+		merged[name] = timeout
+	}
+	return merged
+}
+
+// toolDeadlines enforces per-tool timeouts on a running query. A timer starts
+// when a tool is invoked and stops when its result arrives; if it fires first,
+// the query is interrupted. A nil *toolDeadlines enforces nothing.
+type toolDeadlines struct {
+	timeouts  map[string]time.Duration
+	interrupt func()
+
+	mu      sync.Mutex
+	timers  map[string]*time.Timer
+	expired error
+	stopped bool
+}
+
+// newToolDeadlines returns deadlines that call interrupt when a tool overruns,
+// or nil when no timeouts are configured.
+func newToolDeadlines(timeouts map[string]time.Duration, interrupt func()) *toolDeadlines {
+	if len(timeouts) == 0 {
+		return nil
+	}
+	return &toolDeadlines{
+		timeouts:  timeouts,
+		interrupt: interrupt,
+		timers:    make(map[string]*time.Timer),
+	}
+}
+
+// start begins the deadline of a tool invocation.
+func (d *toolDeadlines) start(id, name string) {
+	if d == nil {
+		return
+	}
+	timeout, ok := toolTimeout(d.timeouts, name)
+	if !ok {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopped {
+		return
+	}
+	if timer, ok := d.timers[id]; ok {
+		timer.Stop()
+	}
+	started := time.Now()
+	d.timers[id] = time.AfterFunc(timeout, func() {
+		d.expire(id, name, timeout, time.Since(started))
+	})
+}
+
+// finish stops the deadline of a completed tool invocation.
+func (d *toolDeadlines) finish(id string) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if timer, ok := d.timers[id]; ok {
+		timer.Stop()
+		delete(d.timers, id)
+	}
+}
+
+// expire records the timeout and interrupts the query once.
+func (d *toolDeadlines) expire(id, name string, timeout, elapsed time.Duration) {
+	d.mu.Lock()
+	if d.stopped || d.expired != nil {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.timers, id)
+	timeoutErr := sdkerrors.NewTimeoutError("tool "+name, timeout, elapsed)
+	timeoutErr.WithDetail("tool_name", name).WithDetail("tool_use_id", id)
+	d.expired = timeoutErr
+	d.mu.Unlock()
+
+	d.interrupt()
+}
+
+// stop cancels all pending deadlines.
+func (d *toolDeadlines) stop() {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stopped = true
+	for id, timer := range d.timers {
+		timer.Stop()
+		delete(d.timers, id)
+	}
+}
+
+// err returns the timeout error if a tool overran its deadline.
+func (d *toolDeadlines) err() error {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.expired
+}
+
+// toolDeadlineLine is the subset of the CLI's stream-json output used to track tools.
+type toolDeadlineLine struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Content []struct {
+			Type      string `json:"type"`
+			ID        string `json:"id"`
+			Name      string `json:"name"`
+			ToolUseID string `json:"tool_use_id"`
+		} `json:"content"`
+	} `json:"message"`
+}
+
+// observeLine starts and finishes deadlines from a stream-json line.
+func (d *toolDeadlines) observeLine(line []byte) {
+	if d == nil || !bytes.HasPrefix(bytes.TrimSpace(line), []byte("{")) {
+		return
+	}
+
+	var msg toolDeadlineLine
+	if err := json.Unmarshal(line, &msg); err != nil || msg.Message == nil {
+		return
+	}
+
+	for _, block := range msg.Message.Content {
+		switch block.Type {
+		case "tool_use":
+			d.start(block.ID, block.Name)
+		case "tool_result":
+			d.finish(block.ToolUseID)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestToolTimeout_Lookup(t *testing.T) {
+	timeouts := map[string]time.Duration{"Bash": 30 * time.Second, ToolTimeoutWildcard: time.Minute}
+
+	if timeout, ok := toolTimeout(timeouts, "Bash"); !ok || timeout != 30*time.Second {
+		t.Errorf("Expected Bash timeout, got %v %v", timeout, ok)
+	}
+	if timeout, ok := toolTimeout(timeouts, "WebFetch"); !ok || timeout != time.Minute {
+		t.Errorf("Expected wildcard timeout, got %v %v", timeout, ok)
+	}
+	if _, ok := toolTimeout(map[string]time.Duration{"Bash": time.Second}, "Read"); ok {
+		t.Error("Expected no timeout for an unlisted tool")
+	}
+}
+
+func TestToolTimeout_InterruptsStream(t *testing.T) {
+	client := newFakeCLIClient(t, `
+echo '{"type":"assistant","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Read","input":{}}]}}'
+echo '{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"ok"}]}}'
+echo '{"type":"assistant","message":{"content":[{"type":"tool_use","id":"toolu_2","name":"Bash","input":{"command":"sleep 60"}}]}}'
+sleep 5`)
+	client.config.ToolTimeouts = map[string]time.Duration{"Bash": 100 * time.Millisecond, "Read": 50 * time.Millisecond}
+
+	stream, err := client.QueryStream(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "run it"}},
+	})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	defer stream.Close()
+
+	start := time.Now()
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			var timeoutErr *sdkerrors.TimeoutError
+			if !errors.As(err, &timeoutErr) || timeoutErr.Operation != "tool Bash" {
+				t.Fatalf("Expected Bash timeout, got %v", err)
+			}
+			break
+		}
+		if chunk.Done {
+			t.Fatal("Expected the stream to be interrupted")
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Stream was not interrupted promptly: %v", elapsed)
+	}
+}
+
+func TestToolTimeout_ExecuteTool(t *testing.T) {
+	client := newFakeCLIClient(t, `exit 0`)
+	client.config.ToolTimeouts = map[string]time.Duration{"run_command": 100 * time.Millisecond}
+
+	result, err := client.ExecuteTool(context.Background(), &ClaudeCodeTool{
+		Name:       "run_command",
+		Parameters: map[string]any{"command": "sleep 5"},
+	})
+
+	var timeoutErr *sdkerrors.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+	if result == nil || result.Success {
+		t.Errorf("Expected failed result, got %+v", result)
+	}
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// hookWebDomains names the web domain policy hook.
+const hookWebDomains = "web-domains"
+
+// runWebDomainsHook reads a PreToolUse hook event for WebFetch or WebSearch
+// and enforces the policy: fetches of other domains are denied, and searches
+// are limited with the tool's allowed_domains or blocked_domains input.
+func runWebDomainsHook(config []byte, input io.Reader, output io.Writer) error {
+	var policy types.WebDomainPolicy
+	if err := json.Unmarshal(config, &policy); err != nil {
+		return fmt.Errorf("invalid hook configuration: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	var event preToolUseEvent
+	if err := json.NewDecoder(input).Decode(&event); err != nil {
+		return fmt.Errorf("invalid hook input: %w", err)
+	}
+
+	switch event.ToolName {
+	case "WebFetch":
+		raw, _ := event.ToolInput["url"].(string)
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			return denyToolUse(output, "invalid URL "+raw)
+		}
+		if !policy.Allows(u.Hostname()) {
+			return denyToolUse(output, "domain "+u.Hostname()+" is not allowed by the web domain policy")
+		}
+		return nil
+
+	case "WebSearch":
+		updated := make(map[string]any, len(event.ToolInput)+1)
+		for key, value := range event.ToolInput {
+			updated[key] = value
+		}
+
+		// The search tool takes either an allowlist or a blocklist, not both
+		if len(policy.AllowedDomains) > 0 {
+			var allowed []string
+			for _, domain := range stringList(event.ToolInput["allowed_domains"]) {
+				if policy.Allows(domain) {
+					allowed = append(allowed, domain)
+				}
+			}
+			if len(allowed) == 0 {
+				for _, domain := range policy.AllowedDomains {
+					if policy.Allows(domain) {
+						allowed = append(allowed, domain)
+					}
+				}
+			}
+			if len(allowed) == 0 {
+				return denyToolUse(output, "the web domain policy allows no search domains")
+			}
+			updated["allowed_domains"] = allowed
+			delete(updated, "blocked_domains")
+		} else {
+			updated["blocked_domains"] = append(stringList(event.ToolInput["blocked_domains"]), policy.BlockedDomains...)
+		}
+
+		return json.NewEncoder(output).Encode(map[string]any{
+			"hookSpecificOutput": map[string]any{
+				"hookEventName": "PreToolUse",
+				"updatedInput":  updated,
+			},
+		})
+	}
+
+	return fmt.Errorf("expected a WebFetch or WebSearch tool call, got %s", event.ToolName)
+}
+
+// denyToolUse writes a PreToolUse response that denies the call.
+func denyToolUse(output io.Writer, reason string) error {
+	return json.NewEncoder(output).Encode(map[string]any{
+		"hookSpecificOutput": map[string]any{
+			"hookEventName":            "PreToolUse",
+			"permissionDecision":       "deny",
+			"permissionDecisionReason": reason,
+		},
+	})
+}
+
+// stringList converts a decoded JSON array of strings.
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestWebDomainArgs(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	client.config.WebDomains = &types.WebDomainPolicy{
+		AllowedDomains: []string{"go.dev", "GitHub.com"},
+		BlockedDomains: []string{"gist.github.com"},
+	}
+
+	args, err := client.buildClaudeArgs(&types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}}}, false)
+	if err != nil {
+		t.Fatalf("buildClaudeArgs failed: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "--allowedTools WebFetch(domain:go.dev),WebFetch(domain:github.com)") ||
+		!strings.Contains(joined, "--disallowedTools WebFetch(domain:gist.github.com)") ||
+		!strings.Contains(joined, "--settings") {
+		t.Errorf("Unexpected args: %v", args)
+	}
+
+	options := &QueryOptions{AllowedTools: []string{"Read"}}
+	command, err := client.buildQueryCommand(&ClaudeCodeSession{ID: "s"}, &types.Command{Args: []string{"hi"}}, options)
+	if err != nil {
+		t.Fatalf("buildQueryCommand failed: %v", err)
+	}
+	if !strings.Contains(strings.Join(command, " "), "--allowedTools Read,WebFetch(domain:go.dev),WebFetch(domain:github.com)") {
+		t.Errorf("Expected web rules merged with allowed tools: %v", command)
+	}
+}
+
+func TestRunWebDomainsHook(t *testing.T) {
+	allowlist, _ := json.Marshal(types.WebDomainPolicy{
+		AllowedDomains: []string{"go.dev", "github.com"},
+		BlockedDomains: []string{"gist.github.com"},
+	})
+	blocklist, _ := json.Marshal(types.WebDomainPolicy{BlockedDomains: []string{"example.com"}})
+
+	tests := []struct {
+		name   string
+		config []byte
+		input  string
+		want   string
+	}{
+		{"allowed fetch", allowlist, `{"tool_name":"WebFetch","tool_input":{"url":"https://pkg.go.dev/net/http"}}`, ``},
+		{"blocked subdomain", allowlist, `{"tool_name":"WebFetch","tool_input":{"url":"https://gist.github.com/x"}}`, `"permissionDecision":"deny"`},
+		{"unlisted fetch", allowlist, `{"tool_name":"WebFetch","tool_input":{"url":"https://evil.com/"}}`, `"permissionDecision":"deny"`},
+		{"search allowlist", allowlist, `{"tool_name":"WebSearch","tool_input":{"query":"go"}}`, `"allowed_domains":["go.dev","github.com"]`},
+		{"search narrowed", allowlist, `{"tool_name":"WebSearch","tool_input":{"query":"go","allowed_domains":["go.dev","evil.com"]}}`, `"allowed_domains":["go.dev"]`},
+		{"search blocklist", blocklist, `{"tool_name":"WebSearch","tool_input":{"query":"go","blocked_domains":["ads.net"]}}`, `"blocked_domains":["ads.net","example.com"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output strings.Builder
+			if err := runWebDomainsHook(tt.config, strings.NewReader(tt.input), &output); err != nil {
+				t.Fatalf("runWebDomainsHook failed: %v", err)
+			}
+			if tt.want == "" && output.Len() != 0 || !strings.Contains(output.String(), tt.want) {
+				t.Errorf("Output %q does not match %q", output.String(), tt.want)
+			}
+		})
+	}
+
+	if err := runWebDomainsHook(allowlist, strings.NewReader(`{"tool_name":"Bash","tool_input":{}}`), &strings.Builder{}); err == nil {
+		t.Error("Expected error for a non-web tool")
+	}
+}
+
+func TestRunWebDomainsHook_Subcommand(t *testing.T) {
+	client := newFakeCLIClient(t, `echo "handled $prompt"`)
+	client.config.WebDomains = &types.WebDomainPolicy{AllowedDomains: []string{"go.dev"}}
+
+	args, err := client.hookArgs(client.currentWorkingDir())
+	if err != nil {
+		t.Fatalf("hookArgs failed: %v", err)
+	}
+	var settings struct {
+		Hooks struct {
+			PreToolUse []struct {
+				Matcher string `json:"matcher"`
+				Hooks   []struct {
+					Command string `json:"command"`
+				} `json:"hooks"`
+			} `json:"PreToolUse"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal([]byte(args[1]), &settings); err != nil || len(settings.Hooks.PreToolUse) != 1 {
+		t.Fatalf("Unexpected settings %q: %v", args[1], err)
+	}
+	if settings.Hooks.PreToolUse[0].Matcher != "WebFetch|WebSearch" {
+		t.Errorf("Unexpected matcher %q", settings.Hooks.PreToolUse[0].Matcher)
+	}
+
+	hook := exec.Command("sh", "-c", settings.Hooks.PreToolUse[0].Hooks[0].Command) // #nosec G204 - hook command built by the test
+	hook.Stdin = strings.NewReader(`{"tool_name":"WebFetch","tool_input":{"url":"https://example.com/"}}`)
+	output, err := hook.Output()
+	if err != nil || !strings.Contains(string(output), `"permissionDecision":"deny"`) {
+		t.Errorf("Unexpected hook output %s (%v)", output, err)
+	}
+}
@@ -0,0 +1,451 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// WebhookSignatureHeader carries the HMAC-SHA256 signature of a webhook request
+	WebhookSignatureHeader = "X-Claude-Signature"
+
+	// WebhookTimestampHeader carries the Unix timestamp included in the signature
+	WebhookTimestampHeader = "X-Claude-Timestamp"
+
+	// WebhookEventHeader carries the event type
+	WebhookEventHeader = "X-Claude-Event"
+
+	// defaultWebhookTimeout is used when WebhookConfig.Timeout is unset
+	defaultWebhookTimeout = 10 * time.Second
+
+	// defaultWebhookMaxRetries is used when WebhookConfig.MaxRetries is unset
+	defaultWebhookMaxRetries = 3
+
+	// webhookQueueSize bounds the events waiting for delivery
+	webhookQueueSize = 256
+)
+
+// SignWebhookPayload returns the signature header value for a webhook body:
+// "sha256=" followed by the hex HMAC-SHA256 of timestamp + "." + body.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a webhook signature in constant time.
+// Receivers should also reject timestamps outside their tolerance window to
+// prevent replays.
+func VerifyWebhookSignature(secret, timestamp string, body []byte, signature string) bool {
+	expected := SignWebhookPayload(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// webhookNotifier delivers webhook events in the background, in order, so
+// queries never block on the receiving endpoint.
+type webhookNotifier struct {
+	config     *types.WebhookConfig
+	httpClient *http.Client
+	backoff    time.Duration
+
+	queue  chan *types.WebhookEvent
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// newWebhookNotifier validates the configuration and creates a notifier.
+func newWebhookNotifier(config *types.WebhookConfig) (*webhookNotifier, error) {
+	if u, err := url.Parse(config.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, sdkerrors.NewConfigurationError("webhook.url", "webhook URL must be an absolute http or https URL: "+config.URL)
+	}
+	switch config.Format {
+	case "", types.WebhookFormatJSON, types.WebhookFormatSlack, types.WebhookFormatTeams:
+	default:
+		return nil, sdkerrors.NewConfigurationError("webhook.format", "unsupported webhook format: "+string(config.Format))
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	return &webhookNotifier{
+		config:     config,
+		httpClient: httpClient,
+		backoff:    500 * time.Millisecond,
+		queue:      make(chan *types.WebhookEvent, webhookQueueSize),
+	}, nil
+}
+
+// start launches the delivery goroutine.
+func (n *webhookNotifier) start() {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		for event := range n.queue {
+			if err := n.deliver(event); err != nil && n.config.OnError != nil {
+				n.config.OnError(event, err)
+			}
+		}
+	}()
+}
+
+// stop delivers the queued events and stops the delivery goroutine.
+func (n *webhookNotifier) stop() {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	n.closed = true
+	close(n.queue)
+	n.mu.Unlock()
+
+	n.wg.Wait()
+}
+
+// send queues an event for delivery. Events are dropped, and reported through
+// OnError, when the queue is full or the notifier has stopped.
+func (n *webhookNotifier) send(event *types.WebhookEvent) {
+	if !n.config.Accepts(event.Type) {
+		return
+	}
+
+	event.ID = uuid.New().String()
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var err error
+	if n.closed {
+		err = sdkerrors.NewInternalError("WEBHOOK_STOPPED", "webhook notifier has been stopped")
+	} else {
+		select {
+		case n.queue <- event:
+			return
+		default:
+			err = sdkerrors.NewInternalError("WEBHOOK_QUEUE_FULL", "webhook queue is full, event dropped")
+		}
+	}
+	if n.config.OnError != nil {
+		n.config.OnError(event, err)
+	}
+}
+
+// deliver POSTs an event, retrying network errors, 429 and 5xx responses.
+func (n *webhookNotifier) deliver(event *types.WebhookEvent) error {
+	var payload any = event
+	switch n.config.Format {
+	case types.WebhookFormatSlack:
+		payload = slackPayload(event)
+	case types.WebhookFormatTeams:
+		payload = teamsPayload(event)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "WEBHOOK_ENCODE", "failed to encode webhook event")
+	}
+
+	maxRetries := n.config.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultWebhookMaxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(n.backoff * time.Duration(1<<(attempt-1)))
+		}
+
+		retry, err := n.post(event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// post makes a single delivery attempt and reports whether a failure is retryable.
+func (n *webhookNotifier) post(event *types.WebhookEvent, body []byte) (bool, error) {
+	timeout := n.config.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "WEBHOOK_REQUEST", "failed to create webhook request")
+	}
+	for key, value := range n.config.Headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(event.Type))
+
+	if n.config.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(n.config.Secret, timestamp, body))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return true, sdkerrors.NewNetworkError("webhook delivery", req.URL.Host, err)
+	}
+	_ = resp.Body.Close() // Ignore error, the body is not used
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, sdkerrors.NewInternalError("WEBHOOK_STATUS", fmt.Sprintf("webhook endpoint returned %s", resp.Status))
+}
+
+// webhookTracker follows a single query and emits its webhook events. A nil
+// tracker ignores all calls, so callers need not check whether webhooks are
+// enabled.
+type webhookTracker struct {
+	client    *ClaudeCodeClient
+	notifier  *webhookNotifier
+	queryID   string
+	sessionID string
+	model     string
+	metadata  map[string]string
+	started   time.Time
+
+	mu    sync.Mutex
+	turn  int
+	tools map[string]*types.WebhookToolEvent
+	done  bool
+}
+
+// trackWebhooks returns a tracker for a query, or nil when webhooks are disabled.
+func (c *ClaudeCodeClient) trackWebhooks(ctx context.Context, request *types.QueryRequest, sessionID string) *webhookTracker {
+	if c.webhooks == nil {
+		return nil
+	}
+
+	model := request.Model
+	if model == "" {
+		model = c.settings().Model
+	}
+	return &webhookTracker{
+		client:    c,
+		notifier:  c.webhooks,
+		queryID:   uuid.New().String(),
+		sessionID: sessionID,
+		model:     model,
+		metadata:  QueryMetadata(ctx),
+		started:   time.Now(),
+		tools:     make(map[string]*types.WebhookToolEvent),
+	}
+}
+
+// webhookStreamLine is the subset of the CLI's stream-json output used for webhooks.
+type webhookStreamLine struct {
+	Type      string `json:"type"`
+	SessionID string `json:"session_id"`
+	Message   *struct {
+		Model   string `json:"model"`
+		Content []struct {
+			Type      string         `json:"type"`
+			ID        string         `json:"id"`
+			Name      string         `json:"name"`
+			Input     map[string]any `json:"input"`
+			ToolUseID string         `json:"tool_use_id"`
+			IsError   bool           `json:"is_error"`
+		} `json:"content"`
+	} `json:"message"`
+
+	// Result fields
+	Result       string            `json:"result"`
+	IsError      bool              `json:"is_error"`
+	NumTurns     int               `json:"num_turns"`
+	TotalCostUSD float64           `json:"total_cost_usd"`
+	Usage        *types.TokenUsage `json:"usage"`
+}
+
+// observeLine inspects a stream-json line, emitting turn, tool and result events.
+func (t *webhookTracker) observeLine(line []byte) {
+	if t == nil || !bytes.HasPrefix(bytes.TrimSpace(line), []byte("{")) {
+		return
+	}
+
+	var msg webhookStreamLine
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	if msg.SessionID != "" {
+		t.sessionID = msg.SessionID
+	}
+	if msg.Message != nil && msg.Message.Model != "" {
+		t.model = msg.Message.Model
+	}
+	t.mu.Unlock()
+
+	switch msg.Type {
+	case "assistant":
+		if msg.Message == nil {
+			return
+		}
+		t.mu.Lock()
+		for _, block := range msg.Message.Content {
+			if block.Type == "tool_use" {
+				input := block.Input
+				if redacted, ok := redactValue(t.client.settings().Redactor, input).(map[string]any); ok {
+					input = redacted
+				}
+				t.tools[block.ID] = &types.WebhookToolEvent{ID: block.ID, Name: block.Name, Input: input}
+			}
+		}
+		t.turn++
+		t.mu.Unlock()
+		t.emit(&types.WebhookEvent{Type: types.WebhookEventTurnCompleted})
+
+	case "user":
+		if msg.Message == nil {
+			return
+		}
+		for _, block := range msg.Message.Content {
+			if block.Type != "tool_result" {
+				continue
+			}
+			t.mu.Lock()
+			tool, ok := t.tools[block.ToolUseID]
+			if ok {
+				delete(t.tools, block.ToolUseID)
+			} else {
+				tool = &types.WebhookToolEvent{ID: block.ToolUseID}
+			}
+			tool.IsError = block.IsError
+			t.mu.Unlock()
+			t.emit(&types.WebhookEvent{Type: types.WebhookEventToolExecuted, Tool: tool})
+		}
+
+	case "result":
+		if msg.IsError {
+			t.fail(sdkerrors.NewInternalError("CLAUDE_EXECUTION", "query ended with an error result: "+msg.Result))
+			return
+		}
+		t.complete(&types.WebhookResult{
+			Text:     msg.Result,
+			NumTurns: msg.NumTurns,
+			Usage:    msg.Usage,
+			CostUSD:  msg.TotalCostUSD,
+		})
+	}
+}
+
+// observeResponse emits the events of a synchronous query.
+func (t *webhookTracker) observeResponse(response *types.QueryResponse) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	if response.Model != "" {
+		t.model = response.Model
+	}
+	t.turn++
+	t.mu.Unlock()
+	t.emit(&types.WebhookEvent{Type: types.WebhookEventTurnCompleted})
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	t.complete(&types.WebhookResult{
+		Text:       text.String(),
+		StopReason: response.StopReason,
+		Usage:      response.Usage,
+	})
+}
+
+// complete emits query.completed once, estimating the cost when it is not reported.
+func (t *webhookTracker) complete(result *types.WebhookResult) {
+	if t == nil || !t.finish() {
+		return
+	}
+
+	t.mu.Lock()
+	if result.NumTurns == 0 {
+		result.NumTurns = t.turn
+	}
+	model := t.model
+	t.mu.Unlock()
+
+	if result.CostUSD == 0 && result.Usage != nil {
+		if price, ok := t.client.lookupPrice(model); ok {
+			result.CostUSD = price.Cost(*result.Usage)
+		}
+	}
+	result.Duration = time.Since(t.started)
+
+	t.emit(&types.WebhookEvent{Type: types.WebhookEventQueryCompleted, Result: result})
+}
+
+// fail emits query.failed once.
+func (t *webhookTracker) fail(err error) {
+	if t == nil || err == nil || !t.finish() {
+		return
+	}
+	t.emit(&types.WebhookEvent{Type: types.WebhookEventQueryFailed, Error: err.Error()})
+}
+
+// finish marks the query as finished, reporting whether it was still running.
+func (t *webhookTracker) finish() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return false
+	}
+	t.done = true
+	return true
+}
+
+// emit fills in the query fields and queues the event.
+func (t *webhookTracker) emit(event *types.WebhookEvent) {
+	t.mu.Lock()
+	event.QueryID = t.queryID
+	event.SessionID = t.sessionID
+	event.Model = t.model
+	event.Turn = t.turn
+	event.Metadata = t.metadata
+	t.mu.Unlock()
+
+	t.notifier.send(event)
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// maxChatSummary bounds the response text quoted in chat notifications.
+const maxChatSummary = 1500
+
+// chatMessage is a webhook event rendered for a human reader.
+type chatMessage struct {
+	title  string
+	text   string
+	facts  [][2]string
+	failed bool
+}
+
+// newChatMessage renders an event as a title, summary text and facts.
+func newChatMessage(event *types.WebhookEvent) chatMessage {
+	msg := chatMessage{}
+	switch event.Type {
+	case types.WebhookEventQueryCompleted:
+		msg.title = "Claude query completed"
+	case types.WebhookEventQueryFailed:
+		msg.title = "Claude query failed"
+		msg.text = event.Error
+		msg.failed = true
+	case types.WebhookEventTurnCompleted:
+		msg.title = fmt.Sprintf("Claude finished turn %d", event.Turn)
+	case types.WebhookEventToolExecuted:
+		msg.title = "Claude used a tool"
+		if event.Tool != nil {
+			msg.title = "Claude used " + event.Tool.Name
+			msg.failed = event.Tool.IsError
+		}
+	default:
+		msg.title = "Claude event " + string(event.Type)
+	}
+
+	if result := event.Result; result != nil {
+		msg.text = truncateSummary(result.Text)
+		msg.facts = append(msg.facts,
+			[2]string{"Turns", fmt.Sprint(result.NumTurns)},
+			[2]string{"Cost", fmt.Sprintf("$%.4f", result.CostUSD)},
+			[2]string{"Duration", result.Duration.Round(time.Second).String()},
+		)
+		if result.Usage != nil {
+			msg.facts = append(msg.facts, [2]string{"Tokens", fmt.Sprintf("%d in / %d out", result.Usage.InputTokens, result.Usage.OutputTokens)})
+		}
+	}
+	if event.Model != "" {
+		msg.facts = append(msg.facts, [2]string{"Model", event.Model})
+	}
+	if event.SessionID != "" {
+		msg.facts = append(msg.facts, [2]string{"Session", event.SessionID})
+	}
+	return msg
+}
+
+// truncateSummary shortens long response text for chat messages.
+func truncateSummary(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= maxChatSummary {
+		return text
+	}
+	cut := maxChatSummary
+	for cut > 0 && text[cut]&0xC0 == 0x80 {
+		cut-- // Don't split a UTF-8 sequence
+	}
+	return text[:cut] + "…"
+}
+
+// slackPayload encodes an event as a Slack incoming webhook message.
+func slackPayload(event *types.WebhookEvent) map[string]any {
+	msg := newChatMessage(event)
+
+	icon := ":white_check_mark:"
+	if msg.failed {
+		icon = ":x:"
+	}
+	blocks := []map[string]any{{
+		"type": "header",
+		"text": map[string]any{"type": "plain_text", "text": msg.title},
+	}}
+	if msg.text != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": msg.text},
+		})
+	}
+	if len(msg.facts) > 0 {
+		fields := make([]map[string]any, 0, len(msg.facts))
+		for _, fact := range msg.facts {
+			fields = append(fields, map[string]any{"type": "mrkdwn", "text": "*" + fact[0] + "*\n" + fact[1]})
+		}
+		blocks = append(blocks, map[string]any{"type": "section", "fields": fields})
+	}
+
+	return map[string]any{
+		"text":   icon + " " + msg.title,
+		"blocks": blocks,
+	}
+}
+
+// teamsPayload encodes an event as a Microsoft Teams incoming webhook
+// message card.
+func teamsPayload(event *types.WebhookEvent) map[string]any {
+	msg := newChatMessage(event)
+
+	color := "2EB67D"
+	if msg.failed {
+		color = "E01E5A"
+	}
+	facts := make([]map[string]any, 0, len(msg.facts))
+	for _, fact := range msg.facts {
+		facts = append(facts, map[string]any{"name": fact[0], "value": fact[1]})
+	}
+
+	card := map[string]any{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.title,
+		"themeColor": color,
+		"title":      msg.title,
+	}
+	if msg.text != "" {
+		card["text"] = msg.text
+	}
+	if len(facts) > 0 {
+		card["sections"] = []map[string]any{{"facts": facts}}
+	}
+	return card
+}
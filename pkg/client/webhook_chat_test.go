@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestWebhook_ChatFormats(t *testing.T) {
+	script := `
+echo '{"type":"system","subtype":"init","session_id":"sess-1"}'
+echo '{"type":"assistant","message":{"model":"claude-sonnet-4","content":[{"type":"text","text":"All tests pass"}]}}'
+echo '{"type":"result","subtype":"success","result":"All tests pass","num_turns":1,"total_cost_usd":0.0125,"usage":{"input_tokens":10,"output_tokens":5}}'`
+
+	tests := []struct {
+		format types.WebhookFormat
+		check  func(t *testing.T, payload map[string]any)
+	}{
+		{types.WebhookFormatSlack, func(t *testing.T, payload map[string]any) {
+			blocks, _ := payload["blocks"].([]any)
+			body, _ := json.Marshal(blocks)
+			if payload["text"] != ":white_check_mark: Claude query completed" || len(blocks) != 3 {
+				t.Errorf("Unexpected Slack message: %s", body)
+			}
+			for _, want := range []string{"All tests pass", "$0.0125", "sess-1", "10 in / 5 out"} {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("Slack message missing %q: %s", want, body)
+				}
+			}
+		}},
+		{types.WebhookFormatTeams, func(t *testing.T, payload map[string]any) {
+			body, _ := json.Marshal(payload)
+			if payload["@type"] != "MessageCard" || payload["title"] != "Claude query completed" || payload["text"] != "All tests pass" {
+				t.Errorf("Unexpected Teams card: %s", body)
+			}
+			if !strings.Contains(string(body), `{"name":"Cost","value":"$0.0125"}`) {
+				t.Errorf("Teams card missing cost: %s", body)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var mu sync.Mutex
+			var payloads []map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				var payload map[string]any
+				if err := json.Unmarshal(body, &payload); err != nil {
+					t.Errorf("Invalid body: %s", body)
+				}
+				mu.Lock()
+				payloads = append(payloads, payload)
+				mu.Unlock()
+			}))
+			defer server.Close()
+
+			client := newFakeCLIClient(t, script)
+			enableWebhooks(t, client, &types.WebhookConfig{URL: server.URL, Format: tt.format})
+
+			stream, err := client.QueryStream(context.Background(), &types.QueryRequest{
+				Messages: []types.Message{{Role: types.RoleUser, Content: "run the tests"}},
+			})
+			if err != nil {
+				t.Fatalf("QueryStream failed: %v", err)
+			}
+			for {
+				chunk, err := stream.Recv()
+				if err != nil {
+					t.Fatalf("Recv failed: %v", err)
+				}
+				if chunk.Done {
+					break
+				}
+			}
+			stream.Close()
+			client.Close()
+
+			// Chat formats skip turn events unless they are requested
+			if len(payloads) != 1 {
+				t.Fatalf("Expected only the completion message, got %d", len(payloads))
+			}
+			tt.check(t, payloads[0])
+		})
+	}
+}
+
+func TestChatMessage_Failure(t *testing.T) {
+	event := &types.WebhookEvent{Type: types.WebhookEventQueryFailed, Error: "CLI exited with status 1"}
+
+	slack := slackPayload(event)
+	if slack["text"] != ":x: Claude query failed" {
+		t.Errorf("Unexpected Slack text %q", slack["text"])
+	}
+	teams := teamsPayload(event)
+	if teams["themeColor"] != "E01E5A" || teams["text"] != "CLI exited with status 1" {
+		t.Errorf("Unexpected Teams card %+v", teams)
+	}
+
+	long := &types.WebhookEvent{Type: types.WebhookEventQueryCompleted, Result: &types.WebhookResult{
+		Text:     strings.Repeat("é", maxChatSummary),
+		Duration: 90 * time.Second,
+	}}
+	msg := newChatMessage(long)
+	if len(msg.text) > maxChatSummary+len("…") || !strings.HasSuffix(msg.text, "é…") {
+		t.Errorf("Expected truncated summary, got %d bytes", len(msg.text))
+	}
+}
+
+func TestWebhookConfig_AcceptsChatDefaults(t *testing.T) {
+	config := &types.WebhookConfig{Format: types.WebhookFormatTeams}
+	if config.Accepts(types.WebhookEventTurnCompleted) || !config.Accepts(types.WebhookEventQueryFailed) {
+		t.Error("Chat formats should default to completion and failure events")
+	}
+	config.Events = []types.WebhookEventType{types.WebhookEventTurnCompleted}
+	if !config.Accepts(types.WebhookEventTurnCompleted) {
+		t.Error("Explicit events should override the chat default")
+	}
+}
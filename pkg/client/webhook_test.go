@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// webhookReceiver collects webhook events and verifies their signatures.
+type webhookReceiver struct {
+	t      *testing.T
+	secret string
+	mu     sync.Mutex
+	events []types.WebhookEvent
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	if r.secret != "" {
+		timestamp := req.Header.Get(WebhookTimestampHeader)
+		if !VerifyWebhookSignature(r.secret, timestamp, body, req.Header.Get(WebhookSignatureHeader)) {
+			r.t.Errorf("Invalid signature for %s", body)
+		}
+	}
+
+	var event types.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		r.t.Errorf("Invalid event body: %v", err)
+	}
+	if req.Header.Get(WebhookEventHeader) != string(event.Type) {
+		r.t.Errorf("Event header %q does not match %q", req.Header.Get(WebhookEventHeader), event.Type)
+	}
+
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *webhookReceiver) types() []types.WebhookEventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var eventTypes []types.WebhookEventType
+	for _, event := range r.events {
+		eventTypes = append(eventTypes, event.Type)
+	}
+	return eventTypes
+}
+
+func enableWebhooks(t *testing.T, client *ClaudeCodeClient, config *types.WebhookConfig) {
+	t.Helper()
+
+	notifier, err := newWebhookNotifier(config)
+	if err != nil {
+		t.Fatalf("newWebhookNotifier failed: %v", err)
+	}
+	notifier.backoff = time.Millisecond
+	notifier.start()
+	client.webhooks = notifier
+}
+
+func TestWebhookSignature(t *testing.T) {
+	body := []byte(`{"type":"query.completed"}`)
+	signature := SignWebhookPayload("secret", "1700000000", body)
+
+	if !VerifyWebhookSignature("secret", "1700000000", body, signature) {
+		t.Error("Expected signature to verify")
+	}
+	if VerifyWebhookSignature("other", "1700000000", body, signature) {
+		t.Error("Expected signature with a different secret to fail")
+	}
+	if VerifyWebhookSignature("secret", "1700000001", body, signature) {
+		t.Error("Expected signature with a different timestamp to fail")
+	}
+}
+
+func TestWebhook_StreamEvents(t *testing.T) {
+	receiver := &webhookReceiver{t: t, secret: "s3cret"}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	client := newFakeCLIClient(t, `
+echo '{"type":"system","subtype":"init","session_id":"sess-1"}'
+echo '{"type":"assistant","message":{"model":"claude-sonnet-4","content":[{"type":"tool_use","id":"toolu_1","name":"Read","input":{"file_path":"a.go"}}]}}'
+echo '{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"package a"}]}}'
+echo '{"type":"assistant","message":{"content":[{"type":"text","text":"done"}]}}'
+echo '{"type":"result","subtype":"success","result":"done","num_turns":2,"total_cost_usd":0.25,"usage":{"input_tokens":10,"output_tokens":5}}'`)
+	enableWebhooks(t, client, &types.WebhookConfig{URL: server.URL, Secret: "s3cret"})
+
+	stream, err := client.QueryStream(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "read a.go"}},
+	})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	stream.Close()
+	client.Close()
+
+	want := []types.WebhookEventType{
+		types.WebhookEventTurnCompleted,
+		types.WebhookEventToolExecuted,
+		types.WebhookEventTurnCompleted,
+		types.WebhookEventQueryCompleted,
+	}
+	got := receiver.types()
+	if len(got) != len(want) {
+		t.Fatalf("Events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Events = %v, want %v", got, want)
+		}
+	}
+
+	tool := receiver.events[1].Tool
+	if tool == nil || tool.Name != "Read" || tool.Input["file_path"] != "a.go" {
+		t.Errorf("Unexpected tool event: %+v", tool)
+	}
+
+	completed := receiver.events[3]
+	if completed.SessionID != "sess-1" || completed.Model != "claude-sonnet-4" || completed.Turn != 2 {
+		t.Errorf("Unexpected completion metadata: %+v", completed)
+	}
+	if completed.Result.CostUSD != 0.25 || completed.Result.Text != "done" || completed.Result.Usage.OutputTokens != 5 {
+		t.Errorf("Unexpected result: %+v", completed.Result)
+	}
+	if completed.QueryID == "" || completed.QueryID != receiver.events[0].QueryID {
+		t.Errorf("Events should share a query ID: %q %q", completed.QueryID, receiver.events[0].QueryID)
+	}
+}
+
+func TestWebhook_QueryFailedAndFiltered(t *testing.T) {
+	receiver := &webhookReceiver{t: t}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	client := newFakeCLIClient(t, `echo "boom" >&2; exit 3`)
+	enableWebhooks(t, client, &types.WebhookConfig{
+		URL:    server.URL,
+		Events: []types.WebhookEventType{types.WebhookEventQueryFailed},
+	})
+
+	_, err := client.Query(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}},
+	})
+	if err == nil {
+		t.Fatal("Expected query to fail")
+	}
+	client.Close()
+
+	got := receiver.types()
+	if len(got) != 1 || got[0] != types.WebhookEventQueryFailed || receiver.events[0].Error == "" {
+		t.Errorf("Unexpected events: %+v", receiver.events)
+	}
+}
+
+func TestWebhook_QueryCompletedEstimatesCost(t *testing.T) {
+	receiver := &webhookReceiver{t: t}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	client := newFakeCLIClient(t, `echo '{"model":"claude-sonnet-4","content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":1000000,"output_tokens":0}}'`)
+	enableWebhooks(t, client, &types.WebhookConfig{URL: server.URL})
+
+	if _, err := client.Query(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "hi"}},
+	}); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	client.Close()
+
+	got := receiver.types()
+	if len(got) != 2 || got[1] != types.WebhookEventQueryCompleted {
+		t.Fatalf("Unexpected events: %v", got)
+	}
+	if result := receiver.events[1].Result; result.CostUSD <= 0 || result.Text != "hi" {
+		t.Errorf("Expected estimated cost, got %+v", result)
+	}
+}
+
+func TestWebhookNotifier_Retries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var failed []error
+	notifier, err := newWebhookNotifier(&types.WebhookConfig{
+		URL:        server.URL,
+		MaxRetries: 2,
+		OnError:    func(event *types.WebhookEvent, err error) { failed = append(failed, err) },
+	})
+	if err != nil {
+		t.Fatalf("newWebhookNotifier failed: %v", err)
+	}
+	notifier.backoff = time.Millisecond
+	notifier.start()
+	notifier.send(&types.WebhookEvent{Type: types.WebhookEventTurnCompleted})
+	notifier.stop()
+
+	if atomic.LoadInt32(&attempts) != 3 || len(failed) != 0 {
+		t.Errorf("Expected success on third attempt, got %d attempts and errors %v", attempts, failed)
+	}
+
+	notifier.send(&types.WebhookEvent{Type: types.WebhookEventTurnCompleted})
+	if len(failed) != 1 {
+		t.Errorf("Expected send after stop to report an error, got %v", failed)
+	}
+
+	if _, err := newWebhookNotifier(&types.WebhookConfig{URL: "ftp://example.com"}); err == nil {
+		t.Error("Expected invalid URL to be rejected")
+	}
+}
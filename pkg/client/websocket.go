@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// WebSocketConn is the part of a WebSocket connection ServeWebSocket uses.
+// *websocket.Conn from github.com/gorilla/websocket implements it; wrap
+// connections from other libraries to send and receive JSON messages.
+type WebSocketConn interface {
+	// ReadJSON reads the next message and decodes it into v
+	ReadJSON(v any) error
+
+	// WriteJSON sends v as a JSON message
+	WriteJSON(v any) error
+}
+
+// ServeWebSocket answers the prompts a browser sends over conn in this
+// session, streaming each reply as types.WebSocketEvent messages, so a web
+// chat UI needs no protocol of its own:
+//
+//	upgrader := websocket.Upgrader{}
+//	http.HandleFunc("/chat", func(w http.ResponseWriter, r *http.Request) {
+//		conn, err := upgrader.Upgrade(w, r, nil)
+//		if err != nil {
+//			return
+//		}
+//		defer conn.Close()
+//		_ = session.ServeWebSocket(r.Context(), conn)
+//	})
+//
+// Prompts, sent as types.WebSocketRequest, are answered one at a time in
+// order. ServeWebSocket returns when ctx is done or conn fails, such as when
+// the browser closes it, returning that error.
+func (s *ClaudeCodeSession) ServeWebSocket(ctx context.Context, conn WebSocketConn) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var request types.WebSocketRequest
+		if err := conn.ReadJSON(&request); err != nil {
+			return err
+		}
+		if strings.TrimSpace(request.Prompt) == "" {
+			invalid := sdkerrors.NewValidationError("prompt", "", "required", "prompt cannot be empty")
+			if err := conn.WriteJSON(webSocketError(invalid)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := s.streamWebSocketReply(ctx, conn, request.Prompt); err != nil {
+			return err
+		}
+	}
+}
+
+// streamWebSocketReply sends the events of the reply to prompt, returning an
+// error only if conn fails.
+func (s *ClaudeCodeSession) streamWebSocketReply(ctx context.Context, conn WebSocketConn, prompt string) error {
+	stream, err := s.QueryStream(ctx, &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: prompt}},
+	})
+	if err != nil {
+		return conn.WriteJSON(webSocketError(err))
+	}
+	defer stream.Close()
+
+	var lines textLines
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return conn.WriteJSON(webSocketError(err))
+		}
+		if chunk.Done {
+			break
+		}
+		for _, event := range lines.webSocketEvents([]byte(chunk.Content)) {
+			if err := conn.WriteJSON(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	result := lines.result
+	if result == nil {
+		result = &types.ResultMessage{Type: "result", Subtype: "success"}
+	}
+	return conn.WriteJSON(&types.WebSocketEvent{Type: types.WebSocketEventResult, Result: result})
+}
+
+// webSocketMessageLine is the subset of a stream-json line holding a
+// complete message.
+type webSocketMessageLine struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Role    types.Role           `json:"role"`
+		Content []types.ContentBlock `json:"content"`
+	} `json:"message"`
+}
+
+// webSocketEvents returns the events for a line of CLI output: its reply
+// text as a delta, then a tool_use event for each tool call and a message
+// event for a complete message.
+func (l *textLines) webSocketEvents(line []byte) []*types.WebSocketEvent {
+	var events []*types.WebSocketEvent
+	if text := l.text(line); text != "" {
+		events = append(events, &types.WebSocketEvent{Type: types.WebSocketEventDelta, Text: text})
+	}
+
+	var parsed webSocketMessageLine
+	if json.Unmarshal(line, &parsed) != nil || parsed.Message == nil || (parsed.Type != "assistant" && parsed.Type != "user") {
+		return events
+	}
+	for _, block := range parsed.Message.Content {
+		if block.Type == "tool_use" {
+			use := types.ToolUseFromBlock(block)
+			events = append(events, &types.WebSocketEvent{Type: types.WebSocketEventToolUse, ToolUse: &use})
+		}
+	}
+
+	role := parsed.Message.Role
+	if role == "" {
+		role = types.Role(parsed.Type)
+	}
+	return append(events, &types.WebSocketEvent{Type: types.WebSocketEventMessage, Role: role, Content: parsed.Message.Content})
+}
+
+// webSocketError returns the error event for err.
+func webSocketError(err error) *types.WebSocketEvent {
+	event := &types.WebSocketEvent{Type: types.WebSocketEventError, Error: err.Error()}
+	var sdkErr sdkerrors.SDKError
+	if errors.As(err, &sdkErr) {
+		event.Code = sdkErr.Code()
+	}
+	return event
+}
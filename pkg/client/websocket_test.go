@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeWebSocket replays requests and records the events written.
+type fakeWebSocket struct {
+	requests []string
+	events   []types.WebSocketEvent
+}
+
+func (c *fakeWebSocket) ReadJSON(v any) error {
+	if len(c.requests) == 0 {
+		return io.EOF
+	}
+	request := c.requests[0]
+	c.requests = c.requests[1:]
+	return json.Unmarshal([]byte(request), v)
+}
+
+func (c *fakeWebSocket) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var event types.WebSocketEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+	c.events = append(c.events, event)
+	return nil
+}
+
+func TestClaudeCodeSession_ServeWebSocket(t *testing.T) {
+	client := newFakeCLIClient(t, `
+echo '{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Reading."},{"type":"tool_use","id":"toolu_1","name":"Read","input":{"file_path":"main.go"}}]}}'
+echo '{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"package main"}]}}'
+echo '{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"It is a main package."}]}}'
+echo '{"type":"result","subtype":"success","is_error":false,"result":"It is a main package.","total_cost_usd":0.02}'`)
+
+	session, err := client.CreateSession(context.Background(), "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	conn := &fakeWebSocket{requests: []string{`{"prompt":"   "}`, `{"prompt":"What is main.go?"}`}}
+	if err := session.ServeWebSocket(context.Background(), conn); err != io.EOF {
+		t.Fatalf("Expected the connection error, got %v", err)
+	}
+
+	var got []types.WebSocketEventType
+	for _, event := range conn.events {
+		got = append(got, event.Type)
+	}
+	want := []types.WebSocketEventType{
+		types.WebSocketEventError,
+		types.WebSocketEventDelta, types.WebSocketEventToolUse, types.WebSocketEventMessage,
+		types.WebSocketEventMessage,
+		types.WebSocketEventDelta, types.WebSocketEventMessage,
+		types.WebSocketEventResult,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected events %v, got %v", want, got)
+		}
+	}
+
+	events := conn.events
+	if events[0].Code != "VALIDATION_ERROR" {
+		t.Errorf("Unexpected error event: %+v", events[0])
+	}
+	if events[1].Text != "Reading." || events[5].Text != "It is a main package." {
+		t.Errorf("Unexpected deltas: %+v, %+v", events[1], events[5])
+	}
+	if use := events[2].ToolUse; use == nil || use.Name != "Read" || use.Input["file_path"] != "main.go" {
+		t.Errorf("Unexpected tool use: %+v", events[2])
+	}
+	if toolResult := events[4]; toolResult.Role != types.RoleUser || len(toolResult.Content) != 1 || toolResult.Content[0].Content[0].Text != "package main" {
+		t.Errorf("Unexpected tool result message: %+v", toolResult)
+	}
+	if result := events[7].Result; !result.Succeeded() || result.Cost() != 0.02 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestClaudeCodeSession_ServeWebSocketError(t *testing.T) {
+	client := newFakeCLIClient(t, `echo 'partial'; exit 3`)
+
+	session, err := client.CreateSession(context.Background(), "")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	conn := &fakeWebSocket{requests: []string{`{"prompt":"hi"}`}}
+	if err := session.ServeWebSocket(context.Background(), conn); err != io.EOF {
+		t.Fatalf("Expected the connection error, got %v", err)
+	}
+	if len(conn.events) != 2 || conn.events[0].Text != "partial\n" || conn.events[1].Type != types.WebSocketEventError || conn.events[1].Code == "" {
+		t.Errorf("Expected a delta and an error event, got %+v", conn.events)
+	}
+}
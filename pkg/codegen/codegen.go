@@ -0,0 +1,263 @@
+package codegen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// DefaultMaxIterations bounds the repair rounds after the first attempt
+	DefaultMaxIterations = 3
+
+	// DefaultTestTimeout bounds each go test run
+	DefaultTestTimeout = 2 * time.Minute
+
+	// maxFeedbackOutput bounds the go test output sent back to Claude
+	maxFeedbackOutput = 8000
+)
+
+// ErrTestsFailed is returned, wrapped, when the generated tests still fail
+// after the last repair iteration.
+var ErrTestsFailed = errors.New("generated tests do not pass")
+
+// Options configures GenerateTests.
+type Options struct {
+	// Executor asks Claude for the tests (required)
+	Executor types.QueryExecutor
+
+	// Model overrides the executor's model
+	Model string
+
+	// Focus narrows the tests, e.g. "the Parse function" or "error paths"
+	Focus string
+
+	// MaxIterations is the number of repair rounds after the first attempt
+	// (defaults to DefaultMaxIterations; negative disables repairs)
+	MaxIterations int
+
+	// TestTimeout bounds each go test run (defaults to DefaultTestTimeout)
+	TestTimeout time.Duration
+
+	// TestArgs are extra go test flags, such as "-race"
+	TestArgs []string
+}
+
+// Result is the outcome of GenerateTests.
+type Result struct {
+	// Files maps test file names to their contents
+	Files map[string]string
+
+	// Passed reports whether go test passed with the files
+	Passed bool
+
+	// Iterations is the number of go test runs
+	Iterations int
+
+	// Output is the output of the last go test run
+	Output string
+}
+
+// Write saves the generated files into dir, normally the package directory.
+func (r *Result) Write(dir string) error {
+	for name, content := range r.Files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "FILE_WRITE", "failed to write "+name)
+		}
+	}
+	return nil
+}
+
+// GenerateTests asks Claude for tests of the Go package in pkgPath, runs them
+// with go test in a copy of the module, and feeds failures back to Claude
+// until they pass or MaxIterations repairs were tried. The package itself is
+// never modified; use Result.Write to keep the files.
+//
+// When the tests never pass, the last attempt is returned together with an
+// error wrapping ErrTestsFailed.
+func GenerateTests(ctx context.Context, pkgPath string, opts Options) (*Result, error) {
+	if opts.Executor == nil {
+		return nil, sdkerrors.NewConfigurationError("executor", "test generation needs an executor")
+	}
+	maxIterations := opts.MaxIterations
+	if maxIterations == 0 {
+		maxIterations = DefaultMaxIterations
+	} else if maxIterations < 0 {
+		maxIterations = 0
+	}
+
+	pkgDir, err := filepath.Abs(pkgPath)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "CODEGEN", "invalid package path")
+	}
+	source, err := readPackage(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	root, err := moduleRoot(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(root, pkgDir)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CODEGEN", "failed to locate the package in its module")
+	}
+
+	sandbox, err := os.MkdirTemp("", "codegen-*")
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CODEGEN", "failed to create the sandbox")
+	}
+	defer func() { _ = os.RemoveAll(sandbox) }() // Best-effort cleanup
+	if err := copyModule(root, sandbox); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CODEGEN", "failed to copy the module into the sandbox")
+	}
+	sandboxPkg := filepath.Join(sandbox, rel)
+
+	result := &Result{}
+	prompt := generatePrompt(source, opts.Focus)
+	for iteration := 0; iteration <= maxIterations; iteration++ {
+		response, err := opts.Executor.Query(ctx, &types.QueryRequest{
+			Model:    opts.Model,
+			System:   systemPrompt,
+			Messages: []types.Message{{Role: types.RoleUser, Content: prompt}},
+		})
+		if err != nil {
+			return result, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CODEGEN", "test generation query failed")
+		}
+
+		files, parseErr := parseFiles(response.GetTextContent(), source.files)
+		if parseErr != nil {
+			result.Output = parseErr.Error()
+			prompt = repairPrompt(source, opts.Focus, result.Files, result.Output)
+			continue
+		}
+
+		// Replace the previous attempt's files in the sandbox
+		for name := range result.Files {
+			_ = os.Remove(filepath.Join(sandboxPkg, name)) // Ignore error, rewritten below if still used
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(sandboxPkg, name), []byte(content), 0o600); err != nil {
+				return result, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CODEGEN", "failed to write "+name)
+			}
+		}
+		result.Files = files
+		result.Iterations++
+
+		result.Output, result.Passed, err = runTests(ctx, sandbox, "./"+filepath.ToSlash(rel), opts)
+		if err != nil {
+			return result, err
+		}
+		if result.Passed {
+			return result, nil
+		}
+		prompt = repairPrompt(source, opts.Focus, files, result.Output)
+	}
+
+	return result, fmt.Errorf("%w after %d runs:\n%s", ErrTestsFailed, result.Iterations, truncateOutput(result.Output))
+}
+
+// runTests runs go test for the package inside the sandbox. A failing test
+// run is reported through passed; err is set only when go could not run.
+func runTests(ctx context.Context, dir, pkg string, opts Options) (output string, passed bool, err error) {
+	timeout := opts.TestTimeout
+	if timeout <= 0 {
+		timeout = DefaultTestTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	args := append([]string{"test", "-count=1"}, opts.TestArgs...)
+	cmd := exec.CommandContext(ctx, "go", append(args, pkg)...) // #nosec G204 - go test with caller-provided flags
+	cmd.Dir = dir
+	data, runErr := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	switch {
+	case runErr == nil:
+		return string(data), true, nil
+	case ctx.Err() != nil:
+		return string(data), false, sdkerrors.WrapError(ctx.Err(), sdkerrors.CategoryInternal, "CODEGEN_TIMEOUT", "go test timed out")
+	case errors.As(runErr, &exitErr):
+		return string(data), false, nil
+	default:
+		return string(data), false, sdkerrors.WrapError(runErr, sdkerrors.CategoryInternal, "CODEGEN", "failed to run go test")
+	}
+}
+
+// packageSource holds the files of the package under test.
+type packageSource struct {
+	name      string
+	files     map[string]string
+	testFiles []string
+}
+
+// readPackage reads the Go files of a package directory.
+func readPackage(dir string) (*packageSource, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "CODEGEN", "failed to read package "+dir)
+	}
+
+	source := &packageSource{files: make(map[string]string)}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if strings.HasSuffix(name, "_test.go") {
+			source.testFiles = append(source.testFiles, name)
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name)) // #nosec G304 - package files
+		if err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "FILE_READ", "failed to read "+name)
+		}
+		source.files[name] = string(data)
+		if source.name == "" {
+			source.name = packageName(string(data))
+		}
+	}
+	if len(source.files) == 0 {
+		return nil, sdkerrors.NewValidationError("pkgPath", dir, "Go package", "no Go source files in "+dir)
+	}
+	return source, nil
+}
+
+// packageName returns the name in a file's package clause.
+func packageName(source string) string {
+	for _, line := range strings.Split(source, "\n") {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "package "); ok {
+			return strings.TrimSpace(strings.SplitN(name, "//", 2)[0])
+		}
+	}
+	return ""
+}
+
+// sortedNames returns the keys of a file map in order.
+func sortedNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// truncateOutput keeps the end of long go test output, where the failures
+// and the summary are.
+func truncateOutput(output string) string {
+	if len(output) <= maxFeedbackOutput {
+		return output
+	}
+	return "...\n" + output[len(output)-maxFeedbackOutput:]
+}
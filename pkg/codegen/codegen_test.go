@@ -0,0 +1,136 @@
+package codegen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// scriptedExecutor replies with the given responses in order.
+type scriptedExecutor struct {
+	responses []string
+	prompts   []string
+}
+
+func (e *scriptedExecutor) Query(_ context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	e.prompts = append(e.prompts, request.Messages[0].GetText())
+	if len(e.responses) == 0 {
+		return nil, errors.New("no more responses")
+	}
+	text := e.responses[0]
+	e.responses = e.responses[1:]
+	return &types.QueryResponse{Content: []types.ContentBlock{{Type: "text", Text: text}}}, nil
+}
+
+// newModule writes a module with a small calc package and returns the
+// package directory.
+func newModule(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	root := t.TempDir()
+	pkg := filepath.Join(root, "calc")
+	if err := os.MkdirAll(pkg, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		filepath.Join(root, "go.mod"):  "module example.com/calc\n\ngo 1.20\n",
+		filepath.Join(pkg, "calc.go"):  "package calc\n\n// Add returns a + b.\nfunc Add(a, b int) int { return a + b }\n",
+		filepath.Join(pkg, "other.go"): "package calc\n\nconst Zero = 0\n",
+	} {
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return pkg
+}
+
+const failingTests = "```go\n// file: calc_test.go\npackage calc\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif got := Add(1, 2); got != 4 {\n\t\tt.Errorf(\"Add(1, 2) = %d, want 4\", got)\n\t}\n}\n```"
+
+const passingTests = "Fixed the expectation:\n\n```go\n// file: calc_test.go\npackage calc\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif got := Add(1, 2); got != 3 {\n\t\tt.Errorf(\"Add(1, 2) = %d, want 3\", got)\n\t}\n}\n```"
+
+func TestGenerateTests_RepairsFailures(t *testing.T) {
+	pkg := newModule(t)
+	executor := &scriptedExecutor{responses: []string{failingTests, passingTests}}
+
+	result, err := GenerateTests(context.Background(), pkg, Options{Executor: executor, Focus: "Add"})
+	if err != nil {
+		t.Fatalf("GenerateTests failed: %v", err)
+	}
+	if !result.Passed || result.Iterations != 2 || !strings.Contains(result.Files["calc_test.go"], "!= 3") {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	if len(executor.prompts) != 2 {
+		t.Fatalf("Expected 2 prompts, got %d", len(executor.prompts))
+	}
+	if first := executor.prompts[0]; !strings.Contains(first, "focusing on Add") || !strings.Contains(first, "func Add(a, b int) int") {
+		t.Errorf("Expected the package source in the prompt, got %q", first)
+	}
+	if repair := executor.prompts[1]; !strings.Contains(repair, "Add(1, 2) = 3, want 4") || !strings.Contains(repair, "// file: calc_test.go") {
+		t.Errorf("Expected the failure and previous tests in the repair prompt, got %q", repair)
+	}
+
+	// The package is untouched until the result is written
+	if _, err := os.Stat(filepath.Join(pkg, "calc_test.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected no test file in the package yet, got %v", err)
+	}
+	if err := result.Write(pkg); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(pkg, "calc_test.go")); err != nil {
+		t.Errorf("Expected the test file to be written: %v", err)
+	}
+}
+
+func TestGenerateTests_GivesUp(t *testing.T) {
+	pkg := newModule(t)
+	executor := &scriptedExecutor{responses: []string{failingTests, "I cannot help with that.", failingTests}}
+
+	result, err := GenerateTests(context.Background(), pkg, Options{Executor: executor, MaxIterations: 2})
+	if !errors.Is(err, ErrTestsFailed) {
+		t.Fatalf("Expected ErrTestsFailed, got %v", err)
+	}
+	if result.Passed || result.Iterations != 2 || len(executor.prompts) != 3 {
+		t.Errorf("Unexpected result %+v after %d prompts", result, len(executor.prompts))
+	}
+	if !strings.Contains(executor.prompts[2], "no test files") {
+		t.Errorf("Expected the parse error in the next prompt, got %q", executor.prompts[2])
+	}
+}
+
+func TestGenerateTests_Errors(t *testing.T) {
+	if _, err := GenerateTests(context.Background(), ".", Options{}); err == nil {
+		t.Error("Expected an error without an executor")
+	}
+	if _, err := GenerateTests(context.Background(), t.TempDir(), Options{Executor: &scriptedExecutor{}}); err == nil {
+		t.Error("Expected an error for a directory without Go files")
+	}
+}
+
+func TestParseFiles(t *testing.T) {
+	existing := map[string]string{"calc.go": ""}
+	files, err := parseFiles("```go\n// file: a_test.go\npackage calc\n```\ntext\n```go\n// file: b_test.go\npackage calc_test\n```\n```\nno name\n```", existing)
+	if err != nil || len(files) != 2 || files["a_test.go"] != "package calc\n" || files["b_test.go"] != "package calc_test\n" {
+		t.Errorf("parseFiles() = %q, %v", files, err)
+	}
+
+	for _, text := range []string{
+		"```go\n// file: ../escape_test.go\npackage calc\n```",
+		"```go\n// file: calc.go\npackage calc\n```",
+		"```go\n// file: helpers.go\npackage calc\n```",
+		"```go\npackage calc\n```",
+	} {
+		if _, err := parseFiles(text, existing); err == nil {
+			t.Errorf("Expected parseFiles(%q) to fail", text)
+		}
+	}
+}
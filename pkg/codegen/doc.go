@@ -0,0 +1,28 @@
+/*
+Package codegen generates Go code with Claude and verifies it with the Go
+toolchain before handing it back.
+
+GenerateTests asks Claude for tests of a package, runs them with go test in
+a throwaway copy of the module, and sends compile errors and failures back
+to Claude until the tests pass or the repair budget is spent. The package
+directory is never modified; Result.Write saves the passing files.
+
+# Basic Usage
+
+	result, err := codegen.GenerateTests(ctx, "./internal/parser", codegen.Options{
+		Executor:      claudeClient,
+		Focus:         "malformed input",
+		MaxIterations: 3,
+		TestArgs:      []string{"-race"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := result.Write("./internal/parser"); err != nil {
+		log.Fatal(err)
+	}
+
+When the tests still fail, the last attempt is returned with an error
+wrapping ErrTestsFailed, so callers can inspect Result.Output.
+*/
+package codegen
@@ -0,0 +1,108 @@
+package codegen
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// systemPrompt describes the reply format parseFiles understands.
+const systemPrompt = `You write idiomatic, table-driven Go tests using only the standard library ` +
+	`testing package and the module's existing dependencies. Reply with one ` + "```go" + ` code block per ` +
+	`test file. The first line of each block must be a comment naming the file, like ` +
+	"`// file: parser_test.go`" + `. Always reply with complete files.`
+
+// fileComment matches the file name comment opening a code block.
+var fileComment = regexp.MustCompile(`^//\s*file:\s*(\S+)\s*$`)
+
+// generatePrompt asks for the first version of the tests.
+func generatePrompt(source *packageSource, focus string) string {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Write tests for Go package %s", source.name)
+	if focus != "" {
+		prompt.WriteString(", focusing on " + focus)
+	}
+	prompt.WriteString(".")
+	if len(source.testFiles) > 0 {
+		fmt.Fprintf(&prompt, " The package already has %s; use new file names and do not redeclare their helpers.",
+			strings.Join(source.testFiles, ", "))
+	}
+	prompt.WriteString("\n")
+
+	for _, name := range sortedNames(source.files) {
+		fmt.Fprintf(&prompt, "\n%s:\n```go\n%s\n```\n", name, strings.TrimRight(source.files[name], "\n"))
+	}
+	return prompt.String()
+}
+
+// repairPrompt sends the failing tests and go test output back to Claude.
+func repairPrompt(source *packageSource, focus string, files map[string]string, output string) string {
+	var prompt strings.Builder
+	prompt.WriteString(generatePrompt(source, focus))
+	prompt.WriteString("\nYour previous tests did not pass.\n")
+	for _, name := range sortedNames(files) {
+		fmt.Fprintf(&prompt, "\n```go\n// file: %s\n%s\n```\n", name, strings.TrimRight(files[name], "\n"))
+	}
+	fmt.Fprintf(&prompt, "\nOutput:\n```\n%s\n```\n\n", strings.TrimRight(truncateOutput(output), "\n"))
+	prompt.WriteString("Fix the tests, not the package, and reply with the complete corrected files. " +
+		"If a test exposes a real bug in the package, remove that case instead.")
+	return prompt.String()
+}
+
+// parseFiles extracts the test files from a reply. Names must be plain
+// *_test.go file names that do not replace the package's own files.
+func parseFiles(text string, existing map[string]string) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, block := range codeBlocks(text) {
+		lines := strings.SplitN(block, "\n", 2)
+		match := fileComment.FindStringSubmatch(strings.TrimSpace(lines[0]))
+		if match == nil {
+			continue
+		}
+
+		name := match[1]
+		if filepath.Base(name) != name || !strings.HasSuffix(name, "_test.go") {
+			return nil, sdkerrors.NewValidationError("file", name, "*_test.go", "test files must be *_test.go names in the package directory")
+		}
+		if _, ok := existing[name]; ok {
+			return nil, sdkerrors.NewValidationError("file", name, "new file", name+" is a package source file")
+		}
+		content := ""
+		if len(lines) > 1 {
+			content = strings.TrimLeft(lines[1], "\n")
+		}
+		files[name] = strings.TrimRight(content, "\n") + "\n"
+	}
+
+	if len(files) == 0 {
+		return nil, sdkerrors.NewValidationError("reply", "", "go code blocks", "the reply contains no test files starting with a // file: comment")
+	}
+	return files, nil
+}
+
+// codeBlocks returns the contents of the fenced code blocks in text.
+func codeBlocks(text string) []string {
+	var blocks []string
+	for {
+		start := strings.Index(text, "```")
+		if start < 0 {
+			return blocks
+		}
+		rest := text[start+3:]
+		newline := strings.Index(rest, "\n")
+		if newline < 0 {
+			return blocks
+		}
+		rest = rest[newline+1:]
+
+		end := strings.Index(rest, "\n```")
+		if end < 0 {
+			return append(blocks, rest)
+		}
+		blocks = append(blocks, rest[:end])
+		text = rest[end+4:]
+	}
+}
@@ -0,0 +1,70 @@
+package codegen
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// moduleRoot returns the directory of the go.mod enclosing dir.
+func moduleRoot(dir string) (string, error) {
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+			return current, nil
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", sdkerrors.NewValidationError("pkgPath", dir, "inside a Go module", "no go.mod found above "+dir)
+		}
+		current = parent
+	}
+}
+
+// copyModule copies the regular files of a module into a sandbox directory,
+// skipping version control metadata.
+func copyModule(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case entry.IsDir():
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0o750)
+		case entry.Type().IsRegular():
+			return copyFile(path, target)
+		default:
+			return nil // Symlinks and special files are not needed to build
+		}
+	})
+}
+
+// copyFile copies a single file.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src) // #nosec G304 - files of the module being tested
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }() // Ignore error, read-only file
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) // #nosec G304 - sandbox path
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
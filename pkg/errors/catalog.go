@@ -0,0 +1,227 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale of the SDK's built-in messages.
+const DefaultLocale = "en"
+
+// MessageID identifies a user-facing message. IDs are stable across
+// releases, so translations keyed by them keep working when the English
+// text changes.
+//
+// Each Reason has a summary, a detail and numbered suggestions:
+//
+//	cli_not_found.summary
+//	cli_not_found.detail
+//	cli_not_found.suggestion.1
+type MessageID string
+
+// Messages used when rendering an Explanation as text.
+const (
+	// MessageRetryAfter introduces the wait before retrying; {duration} is
+	// replaced with it
+	MessageRetryAfter MessageID = "explain.retry_after"
+
+	// MessageTry heads the list of suggestions
+	MessageTry MessageID = "explain.try"
+)
+
+// SummaryID returns the ID of the summary for reason.
+func SummaryID(reason Reason) MessageID {
+	return MessageID(string(reason) + ".summary")
+}
+
+// DetailID returns the ID of the detail for reason.
+func DetailID(reason Reason) MessageID {
+	return MessageID(string(reason) + ".detail")
+}
+
+// SuggestionID returns the ID of the n-th suggestion for reason, counting
+// from 1.
+func SuggestionID(reason Reason, n int) MessageID {
+	return MessageID(string(reason) + ".suggestion." + strconv.Itoa(n))
+}
+
+// englishMessages returns the built-in messages by ID.
+func englishMessages() map[MessageID]string {
+	messages := map[MessageID]string{
+		MessageRetryAfter: "Retry after {duration}.",
+		MessageTry:        "Try:",
+	}
+	for reason, text := range explanationTexts {
+		messages[SummaryID(reason)] = text.summary
+		messages[DetailID(reason)] = text.detail
+		for i, suggestion := range text.suggestions {
+			messages[SuggestionID(reason, i+1)] = suggestion
+		}
+	}
+	return messages
+}
+
+// MessageIDs returns the IDs of every user-facing message, sorted, for
+// building a translation template.
+func MessageIDs() []MessageID {
+	messages := englishMessages()
+	ids := make([]MessageID, 0, len(messages))
+	for id := range messages {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Catalog holds the user-facing messages of the SDK by locale. It starts
+// with the English messages; applications register translations for other
+// locales. Messages missing from a locale fall back to its base language
+// ("pt" for "pt-BR") and then to English.
+//
+// A Catalog is safe for concurrent use.
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[MessageID]string
+}
+
+// NewCatalog creates a catalog holding the English messages.
+func NewCatalog() *Catalog {
+	return &Catalog{messages: map[string]map[MessageID]string{DefaultLocale: englishMessages()}}
+}
+
+var defaultCatalog = NewCatalog()
+
+// DefaultCatalog returns the catalog used by Explain. Translations
+// registered with it apply process-wide.
+func DefaultCatalog() *Catalog {
+	return defaultCatalog
+}
+
+// normalizeLocale lower-cases a locale and uses "-" as its separator, so
+// "pt_BR" and "pt-br" name the same locale.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// Register adds messages for locale, replacing earlier ones with the same
+// IDs. It fails without registering anything if an ID is not one of
+// MessageIDs.
+func (c *Catalog) Register(locale string, messages map[MessageID]string) error {
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		return NewValidationError("locale", "", "required", "locale cannot be empty")
+	}
+	known := englishMessages()
+	for id := range messages {
+		if _, ok := known[id]; !ok {
+			return NewValidationError("messages", string(id), "known message ID", "unknown message ID "+strconv.Quote(string(id)))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[MessageID]string, len(messages))
+	}
+	for id, text := range messages {
+		c.messages[locale][id] = text
+	}
+	return nil
+}
+
+// RegisterJSON adds messages for locale from a JSON object mapping message
+// IDs to text, as kept in a translation file.
+func (c *Catalog) RegisterJSON(locale string, data []byte) error {
+	var messages map[MessageID]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return WrapError(err, CategoryValidation, "CATALOG_PARSE", "failed to parse message catalog")
+	}
+	return c.Register(locale, messages)
+}
+
+// Message returns the text of a message in locale, falling back to the base
+// language and then English. It returns the ID itself for unknown IDs.
+func (c *Catalog) Message(locale string, id MessageID) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, candidate := range fallbackLocales(locale) {
+		if text, ok := c.messages[candidate][id]; ok {
+			return text
+		}
+	}
+	return string(id)
+}
+
+// Missing returns the IDs without a translation for locale or its base
+// language, sorted.
+func (c *Catalog) Missing(locale string) []MessageID {
+	locales := fallbackLocales(locale)
+	locales = locales[:len(locales)-1] // Not English
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var missing []MessageID
+	for _, id := range MessageIDs() {
+		found := false
+		for _, candidate := range locales {
+			if _, ok := c.messages[candidate][id]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// fallbackLocales returns the locales to look a message up in: locale, its
+// base language, and English.
+func fallbackLocales(locale string) []string {
+	locale = normalizeLocale(locale)
+	var locales []string
+	if locale != "" && locale != DefaultLocale {
+		locales = append(locales, locale)
+		if base, _, ok := strings.Cut(locale, "-"); ok && base != DefaultLocale {
+			locales = append(locales, base)
+		}
+	}
+	return append(locales, DefaultLocale)
+}
+
+// Explain works like the package's Explain function, with the text in
+// locale. It returns nil for a nil error.
+func (c *Catalog) Explain(err error, locale string) *Explanation {
+	if err == nil {
+		return nil
+	}
+
+	reason, retryAfter := classify(err)
+	explanation := &Explanation{
+		Reason:     reason,
+		Summary:    c.Message(locale, SummaryID(reason)),
+		Detail:     c.Message(locale, DetailID(reason)),
+		Retryable:  IsRetryable(err),
+		RetryAfter: retryAfter,
+		Cause:      err.Error(),
+		Locale:     locale,
+		catalog:    c,
+	}
+	if explanation.Locale == "" {
+		explanation.Locale = DefaultLocale
+	}
+	for n := 1; n <= len(explanationTexts[reason].suggestions); n++ {
+		explanation.Suggestions = append(explanation.Suggestions, c.Message(locale, SuggestionID(reason, n)))
+	}
+
+	var sdkErr SDKError
+	if errors.As(err, &sdkErr) {
+		explanation.Code = sdkErr.Code()
+	}
+	return explanation
+}
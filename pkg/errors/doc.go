@@ -111,6 +111,50 @@ Subprocess execution errors with exit codes:
 		Stderr   string // Captured stderr output
 	}
 
+# Circuit Breaker Errors
+
+A client whose circuit breaker is open fails queries without running the CLI:
+
+	type CircuitOpenError struct {
+		*BaseError
+		Failures   int           // Consecutive failures that opened the circuit
+		RetryAfter time.Duration // Time until a probe query is let through
+	}
+
+# Explaining Errors
+
+Explain turns any error from the SDK into text for a user-facing error
+screen: a summary, the likely cause and suggested fixes. It recognizes typed
+errors anywhere in the chain, as well as the CLI's own error output, such as
+an invalid API key or an overloaded API:
+
+	if err != nil {
+		explanation := errors.Explain(err)
+		fmt.Println(explanation) // Summary, detail and "Try:" suggestions
+		if explanation.Reason == errors.ReasonCLINotFound {
+			showInstallGuide()
+		}
+	}
+
+Reasons are stable across releases, so applications can attach their own
+help links to them.
+
+# Localized Messages
+
+The text of explanations comes from a Catalog of messages keyed by stable
+MessageIDs such as "cli_not_found.summary". Applications register
+translations, for example from JSON files, and explain errors in the user's
+locale. Missing messages fall back to the base language, then to English:
+
+	catalog := errors.DefaultCatalog()
+	if err := catalog.RegisterJSON("de", germanMessages); err != nil {
+		log.Fatal(err)
+	}
+	explanation := catalog.Explain(err, "de-AT")
+
+MessageIDs lists every ID for building a translation template, and Missing
+reports the ones a locale has not translated yet.
+
 # Error Context
 
 Add context to errors for better debugging:
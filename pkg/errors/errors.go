@@ -281,6 +281,31 @@ func NewConfigurationError(field, message string) *ConfigurationError {
 	}
 }
 
+// CircuitOpenError is returned instead of running the CLI while a client's
+// circuit breaker is open after repeated failures.
+type CircuitOpenError struct {
+	*BaseError
+	Failures   int           // Consecutive failures that opened the circuit
+	RetryAfter time.Duration // Time until the circuit lets a probe through
+}
+
+// NewCircuitOpenError creates a new circuit open error.
+func NewCircuitOpenError(failures int, retryAfter time.Duration) *CircuitOpenError {
+	message := fmt.Sprintf("circuit breaker open after %d consecutive CLI failures; retry in %v", failures, retryAfter)
+
+	err := &CircuitOpenError{
+		BaseError: NewBaseError(CategoryInternal, SeverityHigh, "CIRCUIT_OPEN", message).
+			WithRetryable(true),
+		Failures:   failures,
+		RetryAfter: retryAfter,
+	}
+
+	err.WithDetail("failures", failures).
+		WithDetail("retry_after_seconds", retryAfter.Seconds())
+
+	return err
+}
+
 // Utility functions for error handling
 
 // IsRetryable checks if an error is retryable by examining the error chain.
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"os/exec"
 	"strings"
 	"testing"
 	"time"
@@ -417,6 +419,10 @@ func TestValidationErrors(t *testing.T) {
 		if err.Violations[0].Field != "username" {
 			t.Errorf("Expected first violation field 'username', got %s", err.Violations[0].Field)
 		}
+		expected := "Validation failed for 2 fields: username: Username is required; email: Invalid email format"
+		if err.Error() != expected {
+			t.Errorf("Expected message to list every violation, got %q", err.Error())
+		}
 	})
 
 	t.Run("request validation error", func(t *testing.T) {
@@ -609,6 +615,17 @@ func TestConfigurationErrors(t *testing.T) {
 			t.Errorf("Expected category %s, got %s", CategoryInternal, err.Category())
 		}
 	})
+
+	t.Run("circuit open error", func(t *testing.T) {
+		err := NewCircuitOpenError(5, 30*time.Second)
+
+		if err.Code() != "CIRCUIT_OPEN" || err.Failures != 5 || err.RetryAfter != 30*time.Second {
+			t.Errorf("Unexpected circuit open error: %+v", err)
+		}
+		if !IsRetryable(err) {
+			t.Error("Expected circuit open error to be retryable")
+		}
+	})
 }
 
 // Test error serialization and formatting
@@ -719,6 +736,101 @@ func TestSecurityFeatures(t *testing.T) {
 	})
 }
 
+func TestExplain(t *testing.T) {
+	if Explain(nil) != nil {
+		t.Error("Expected nil explanation for nil error")
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		reason Reason
+	}{
+		{"missing CLI", WrapError(errors.New("claude code executable not found"), CategoryConfiguration, "CLAUDE_CODE_PATH", "failed to locate claude code executable"), ReasonCLINotFound},
+		{"exec not found", fmt.Errorf("start: %w", &exec.Error{Name: "claude", Err: exec.ErrNotFound}), ReasonCLINotFound},
+		{"rate limit", WrapError(NewRateLimitError(30*time.Second, 0, 0, time.Time{}), CategoryAPI, "QUERY", "query failed"), ReasonRateLimited},
+		{"missing credentials", NewMissingCredentialsError([]string{"api_key"}, nil), ReasonAuthMissing},
+		{"invalid key from CLI", NewInternalError("CLAUDE_EXECUTION", "claude command failed: Invalid API key · Please run /login"), ReasonAuthInvalid},
+		{"not logged in", NewInternalError("CLAUDE_EXECUTION", "claude command failed: Not logged in"), ReasonAuthMissing},
+		{"overloaded", NewInternalError("CLAUDE_EXECUTION", "claude command failed: API Error: 529 overloaded_error"), ReasonAPIUnavailable},
+		{"CLI failure", NewInternalError("CLAUDE_EXECUTION", "claude command failed: boom"), ReasonCLIFailed},
+		{"permission", fmt.Errorf("open: %w", os.ErrPermission), ReasonPermissionDenied},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), ReasonTimeout},
+		{"canceled", NewInternalError("INTERRUPTED", "query was interrupted"), ReasonCanceled},
+		{"circuit", NewCircuitOpenError(3, time.Minute), ReasonCircuitOpen},
+		{"validation", WrapError(NewValidationError("max_turns", "-1", "non-negative", "bad"), CategoryValidation, "ARGS_BUILD", "failed"), ReasonInvalidRequest},
+		{"unknown", errors.New("something odd"), ReasonUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation := Explain(tt.err)
+			if explanation.Reason != tt.reason {
+				t.Errorf("Reason = %s, want %s", explanation.Reason, tt.reason)
+			}
+			if explanation.Summary == "" || explanation.Cause != tt.err.Error() {
+				t.Errorf("Unexpected explanation: %+v", explanation)
+			}
+		})
+	}
+
+	explanation := Explain(WrapError(NewRateLimitError(30*time.Second, 0, 0, time.Time{}), CategoryAPI, "QUERY", "query failed"))
+	if explanation.Code != "QUERY" || !explanation.Retryable || explanation.RetryAfter != 30*time.Second {
+		t.Errorf("Unexpected rate limit explanation: %+v", explanation)
+	}
+	text := explanation.String()
+	if !strings.HasPrefix(text, "Rate limited\n") || !strings.Contains(text, "Retry after 30s.") || !strings.Contains(text, "\n  - Wait and retry with backoff") {
+		t.Errorf("Unexpected text:\n%s", text)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	catalog := NewCatalog()
+
+	err := catalog.RegisterJSON("de", []byte(`{
+		"cli_not_found.summary": "Claude Code CLI nicht gefunden",
+		"cli_not_found.suggestion.1": "Installieren mit: npm install -g @anthropic-ai/claude-code",
+		"explain.try": "Versuchen Sie:"
+	}`))
+	if err != nil {
+		t.Fatalf("RegisterJSON failed: %v", err)
+	}
+	if err := catalog.Register("de", map[MessageID]string{"cli_not_found.sumary": "Tippfehler"}); err == nil {
+		t.Error("Expected an unknown message ID to be rejected")
+	}
+
+	// Regional locales fall back to the base language, then to English
+	cliErr := WrapError(errors.New("not found"), CategoryConfiguration, "CLAUDE_CODE_PATH", "failed to locate claude code executable")
+	explanation := catalog.Explain(cliErr, "de_AT")
+	if explanation.Summary != "Claude Code CLI nicht gefunden" || explanation.Locale != "de_AT" {
+		t.Errorf("Unexpected summary: %+v", explanation)
+	}
+	if explanation.Detail != explanationTexts[ReasonCLINotFound].detail {
+		t.Errorf("Expected untranslated detail to fall back to English, got %q", explanation.Detail)
+	}
+	text := explanation.String()
+	if !strings.Contains(text, "\n\nVersuchen Sie:\n  - Installieren mit") {
+		t.Errorf("Unexpected text:\n%s", text)
+	}
+
+	// The default catalog is unaffected
+	if summary := Explain(cliErr).Summary; summary != "Claude Code CLI not found" {
+		t.Errorf("Default summary = %q", summary)
+	}
+
+	missing := catalog.Missing("de")
+	if len(missing) != len(MessageIDs())-3 {
+		t.Errorf("Missing returned %d IDs, want %d", len(missing), len(MessageIDs())-3)
+	}
+	for _, id := range missing {
+		if id == SummaryID(ReasonCLINotFound) {
+			t.Errorf("Translated message %s reported missing", id)
+		}
+	}
+	if got := catalog.Message("fr", "no.such.id"); got != "no.such.id" {
+		t.Errorf("Message for an unknown ID = %q", got)
+	}
+}
+
 // Benchmark tests
 func BenchmarkErrorCreation(b *testing.B) {
 	b.Run("BaseError", func(b *testing.B) {
@@ -0,0 +1,439 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Reason identifies what went wrong in terms a user can act on. Reasons are
+// stable across releases, so applications can key their own help text or
+// telemetry on them.
+type Reason string
+
+const (
+	ReasonCLINotFound      Reason = "cli_not_found"
+	ReasonCLIFailed        Reason = "cli_failed"
+	ReasonAuthMissing      Reason = "auth_missing"
+	ReasonAuthInvalid      Reason = "auth_invalid"
+	ReasonAuthExpired      Reason = "auth_expired"
+	ReasonPermissionDenied Reason = "permission_denied"
+	ReasonRateLimited      Reason = "rate_limited"
+	ReasonQuotaExceeded    Reason = "quota_exceeded"
+	ReasonAPIUnavailable   Reason = "api_unavailable"
+	ReasonModelUnavailable Reason = "model_unavailable"
+	ReasonContentPolicy    Reason = "content_policy"
+	ReasonNetwork          Reason = "network"
+	ReasonProxy            Reason = "proxy"
+	ReasonTLS              Reason = "tls"
+	ReasonTimeout          Reason = "timeout"
+	ReasonCanceled         Reason = "canceled"
+	ReasonCircuitOpen      Reason = "circuit_open"
+	ReasonInvalidRequest   Reason = "invalid_request"
+	ReasonConfiguration    Reason = "configuration"
+	ReasonClientClosed     Reason = "client_closed"
+	ReasonUnknown          Reason = "unknown"
+)
+
+// Explanation describes an error for the people who have to fix it: what
+// went wrong, why, and what to try.
+type Explanation struct {
+	// Reason identifies the kind of failure
+	Reason Reason `json:"reason"`
+
+	// Summary is a one-line description, e.g. "Claude Code CLI not found"
+	Summary string `json:"summary"`
+
+	// Detail explains the likely cause
+	Detail string `json:"detail"`
+
+	// Suggestions are fixes to try, most likely first
+	Suggestions []string `json:"suggestions,omitempty"`
+
+	// Code is the SDK error code, if err is an SDK error
+	Code string `json:"code,omitempty"`
+
+	// Retryable reports whether retrying unchanged may succeed
+	Retryable bool `json:"retryable"`
+
+	// RetryAfter is how long to wait before retrying, when known
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+
+	// Cause is the error's own message
+	Cause string `json:"cause"`
+
+	// Locale is the language of the text, e.g. "en"
+	Locale string `json:"locale"`
+
+	// catalog rendered the text and renders String's labels
+	catalog *Catalog
+}
+
+// String renders the explanation as plain text for an error screen or log,
+// in the explanation's locale.
+func (e *Explanation) String() string {
+	catalog := e.catalog
+	if catalog == nil {
+		catalog = DefaultCatalog()
+	}
+
+	var b strings.Builder
+	b.WriteString(e.Summary)
+	if e.Detail != "" {
+		b.WriteString("\n")
+		b.WriteString(e.Detail)
+	}
+	if e.RetryAfter > 0 {
+		b.WriteString("\n")
+		b.WriteString(strings.ReplaceAll(catalog.Message(e.Locale, MessageRetryAfter), "{duration}", e.RetryAfter.String()))
+	}
+	if len(e.Suggestions) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(catalog.Message(e.Locale, MessageTry))
+		for _, suggestion := range e.Suggestions {
+			b.WriteString("\n  - ")
+			b.WriteString(suggestion)
+		}
+	}
+	return b.String()
+}
+
+// explanationText is the English text for a Reason, the source of the
+// default catalog's messages.
+type explanationText struct {
+	summary     string
+	detail      string
+	suggestions []string
+}
+
+var explanationTexts = map[Reason]explanationText{
+	ReasonCLINotFound: {
+		summary: "Claude Code CLI not found",
+		detail:  "The SDK runs the claude command-line tool, which is not installed or not on the PATH.",
+		suggestions: []string{
+			"Install it with: npm install -g @anthropic-ai/claude-code",
+			"Check that `claude --version` works in the same environment",
+			"Set ClaudeCodePath to the full path of the claude executable",
+		},
+	},
+	ReasonCLIFailed: {
+		summary: "Claude Code CLI failed",
+		detail:  "The claude process exited with an error.",
+		suggestions: []string{
+			"Read the CLI's error output in the cause below",
+			"Run the same prompt with `claude -p` to reproduce it",
+			"Update the CLI with: npm update -g @anthropic-ai/claude-code",
+		},
+	},
+	ReasonAuthMissing: {
+		summary: "Not signed in",
+		detail:  "No API key or login was found for Claude Code.",
+		suggestions: []string{
+			"Set the ANTHROPIC_API_KEY environment variable or the APIKey config field",
+			"Or sign in once with `claude login`",
+		},
+	},
+	ReasonAuthInvalid: {
+		summary: "Authentication failed",
+		detail:  "The API key or token was rejected.",
+		suggestions: []string{
+			"Check that the API key is copied correctly and has not been revoked",
+			"Make sure the key belongs to the workspace you expect",
+		},
+	},
+	ReasonAuthExpired: {
+		summary: "Credentials expired",
+		detail:  "The login or token used by Claude Code has expired.",
+		suggestions: []string{
+			"Sign in again with `claude login`",
+			"Refresh the token and retry",
+		},
+	},
+	ReasonPermissionDenied: {
+		summary: "Permission denied",
+		detail:  "The request was not allowed to access a file, tool or resource.",
+		suggestions: []string{
+			"Check the file permissions of the working directory",
+			"Allow the tool with AllowedTools or a permission mode that grants it",
+			"Ask an administrator for access to the resource",
+		},
+	},
+	ReasonRateLimited: {
+		summary: "Rate limited",
+		detail:  "Too many requests were sent in a short time.",
+		suggestions: []string{
+			"Wait and retry with backoff",
+			"Lower the number of concurrent queries",
+			"Request a higher rate limit for your organization",
+		},
+	},
+	ReasonQuotaExceeded: {
+		summary: "Usage limit reached",
+		detail:  "The account's credit balance or usage quota is used up.",
+		suggestions: []string{
+			"Check billing and usage in the Anthropic Console",
+			"Wait for the quota to reset",
+		},
+	},
+	ReasonAPIUnavailable: {
+		summary: "Claude API unavailable",
+		detail:  "The API is overloaded or having an outage.",
+		suggestions: []string{
+			"Retry in a few moments",
+			"Check https://status.anthropic.com for incidents",
+		},
+	},
+	ReasonModelUnavailable: {
+		summary: "Model unavailable",
+		detail:  "The requested model does not exist or is not available to this account.",
+		suggestions: []string{
+			"Check the model name for typos",
+			"Use a model listed as available for your account",
+		},
+	},
+	ReasonContentPolicy: {
+		summary: "Request blocked by content policy",
+		detail:  "The prompt or response was flagged by a usage policy.",
+		suggestions: []string{
+			"Rephrase the request",
+		},
+	},
+	ReasonNetwork: {
+		summary: "Network error",
+		detail:  "The Claude API could not be reached.",
+		suggestions: []string{
+			"Check the internet connection and DNS",
+			"Check that a firewall allows connections to api.anthropic.com",
+		},
+	},
+	ReasonProxy: {
+		summary: "Proxy error",
+		detail:  "The connection through the configured proxy failed.",
+		suggestions: []string{
+			"Check the proxy URL and credentials",
+			"Add internal hosts to NoProxy",
+		},
+	},
+	ReasonTLS: {
+		summary: "Secure connection failed",
+		detail:  "The server's certificate could not be verified.",
+		suggestions: []string{
+			"Behind a TLS-inspecting proxy, add its CA with CertificateTrust.CAFile",
+			"Check that the system clock is correct",
+		},
+	},
+	ReasonTimeout: {
+		summary: "Request timed out",
+		detail:  "The operation did not finish in time.",
+		suggestions: []string{
+			"Retry the request",
+			"Allow a longer timeout, or split the task into smaller prompts",
+		},
+	},
+	ReasonCanceled: {
+		summary: "Request canceled",
+		detail:  "The request was canceled or interrupted before it finished.",
+	},
+	ReasonCircuitOpen: {
+		summary: "Claude Code temporarily unavailable",
+		detail:  "Requests are paused after repeated failures of the CLI or API.",
+		suggestions: []string{
+			"Wait for the cool-down and retry",
+			"Look at the earlier failures to find the underlying problem",
+		},
+	},
+	ReasonInvalidRequest: {
+		summary: "Invalid request",
+		detail:  "The request has invalid or missing values.",
+		suggestions: []string{
+			"Correct the fields named in the cause below",
+		},
+	},
+	ReasonConfiguration: {
+		summary: "Configuration error",
+		detail:  "The SDK configuration is invalid.",
+		suggestions: []string{
+			"Correct the setting named in the cause below",
+		},
+	},
+	ReasonClientClosed: {
+		summary: "Client closed",
+		detail:  "The request was made after the client was closed.",
+		suggestions: []string{
+			"Create a new client, or keep the client open until all requests finish",
+		},
+	},
+	ReasonUnknown: {
+		summary: "Unexpected error",
+		detail:  "Something went wrong that the SDK does not recognize.",
+		suggestions: []string{
+			"Retry the request",
+			"If it keeps failing, report it with the cause below",
+		},
+	},
+}
+
+// Explain walks err's chain and describes the failure in terms a user can
+// act on, with suggested fixes, for embedding in user-facing error screens.
+// The text is in English; use Catalog.Explain for other languages. It
+// returns nil for a nil error.
+func Explain(err error) *Explanation {
+	return DefaultCatalog().Explain(err, DefaultLocale)
+}
+
+// classify finds the reason for err: typed SDK errors first, then standard
+// library sentinels, then the CLI's error output, then error codes and
+// categories.
+func classify(err error) (Reason, time.Duration) {
+	var (
+		rateLimit   *RateLimitError
+		circuitOpen *CircuitOpenError
+		expired     *TokenExpiredError
+		missing     *MissingCredentialsError
+		apiKey      *APIKeyError
+		bearer      *BearerTokenError
+		invalid     *InvalidCredentialsError
+		authn       *AuthenticationError
+		authz       *AuthorizationError
+		quota       *QuotaExceededError
+		model       *ModelUnavailableError
+		policy      *ContentPolicyError
+		proxy       *ProxyError
+		tlsErr      *TLSError
+		timeout     *TimeoutError
+		network     *NetworkError
+		connection  *ConnectionError
+		dns         *DNSError
+		validation  *ValidationError
+		request     *InvalidRequestError
+		config      *ConfigurationError
+		apiErr      *APIError
+	)
+
+	switch {
+	case errors.As(err, &rateLimit):
+		return ReasonRateLimited, rateLimit.RetryAfter
+	case errors.As(err, &circuitOpen):
+		return ReasonCircuitOpen, circuitOpen.RetryAfter
+	case errors.As(err, &expired):
+		return ReasonAuthExpired, 0
+	case errors.As(err, &missing):
+		return ReasonAuthMissing, 0
+	case errors.As(err, &apiKey), errors.As(err, &bearer), errors.As(err, &invalid), errors.As(err, &authn):
+		return ReasonAuthInvalid, 0
+	case errors.As(err, &authz):
+		return ReasonPermissionDenied, 0
+	case errors.As(err, &quota):
+		return ReasonQuotaExceeded, 0
+	case errors.As(err, &model):
+		return ReasonModelUnavailable, 0
+	case errors.As(err, &policy):
+		return ReasonContentPolicy, 0
+	case errors.As(err, &proxy):
+		return ReasonProxy, 0
+	case errors.As(err, &tlsErr):
+		return ReasonTLS, 0
+	case errors.As(err, &timeout):
+		return ReasonTimeout, 0
+	case errors.As(err, &network), errors.As(err, &connection), errors.As(err, &dns):
+		return ReasonNetwork, 0
+	case errors.As(err, &validation), errors.As(err, &request):
+		return ReasonInvalidRequest, 0
+	case errors.As(err, &config):
+		return ReasonConfiguration, 0
+	case errors.As(err, &apiErr) && apiErr.HTTPStatusCode() >= 500:
+		return ReasonAPIUnavailable, 0
+
+	case errors.Is(err, exec.ErrNotFound):
+		return ReasonCLINotFound, 0
+	case errors.Is(err, context.DeadlineExceeded):
+		return ReasonTimeout, 0
+	case errors.Is(err, context.Canceled):
+		return ReasonCanceled, 0
+	case errors.Is(err, fs.ErrPermission):
+		return ReasonPermissionDenied, 0
+	}
+
+	if reason, ok := classifyMessage(err.Error()); ok {
+		return reason, 0
+	}
+
+	switch {
+	case hasCode(err, "CLAUDE_CODE_PATH"):
+		return ReasonCLINotFound, 0
+	case hasCode(err, "CLIENT_CLOSED"):
+		return ReasonClientClosed, 0
+	case hasCode(err, "INTERRUPTED", "CONTEXT_CANCELED"):
+		return ReasonCanceled, 0
+	case hasCode(err, "CLAUDE_EXECUTION", "PROCESS_START"):
+		return ReasonCLIFailed, 0
+	}
+
+	switch GetCategory(err) {
+	case CategoryValidation:
+		return ReasonInvalidRequest, 0
+	case CategoryConfiguration:
+		return ReasonConfiguration, 0
+	case CategoryAuth:
+		return ReasonAuthInvalid, 0
+	case CategoryNetwork:
+		return ReasonNetwork, 0
+	}
+	return ReasonUnknown, 0
+}
+
+// messagePatterns recognize failures reported only as text, such as the
+// CLI's error output carried in a CLAUDE_EXECUTION error. Patterns are
+// lower case and checked in order.
+var messagePatterns = []struct {
+	pattern string
+	reason  Reason
+}{
+	{"executable not found", ReasonCLINotFound},
+	{"command not found", ReasonCLINotFound},
+	{"rate limit", ReasonRateLimited},
+	{"rate_limit", ReasonRateLimited},
+	{"credit balance", ReasonQuotaExceeded},
+	{"overloaded", ReasonAPIUnavailable},
+	{"oauth token has expired", ReasonAuthExpired},
+	{"token expired", ReasonAuthExpired},
+	{"invalid api key", ReasonAuthInvalid},
+	{"invalid x-api-key", ReasonAuthInvalid},
+	{"authentication_error", ReasonAuthInvalid},
+	{"not logged in", ReasonAuthMissing},
+	{"please run /login", ReasonAuthMissing},
+	{"permission denied", ReasonPermissionDenied},
+	{"certificate", ReasonTLS},
+	{"econnrefused", ReasonNetwork},
+	{"enotfound", ReasonNetwork},
+	{"etimedout", ReasonTimeout},
+}
+
+// classifyMessage matches an error message against messagePatterns.
+func classifyMessage(message string) (Reason, bool) {
+	message = strings.ToLower(message)
+	for _, p := range messagePatterns {
+		if strings.Contains(message, p.pattern) {
+			return p.reason, true
+		}
+	}
+	return "", false
+}
+
+// hasCode reports whether any SDK error in err's chain has one of codes.
+func hasCode(err error, codes ...string) bool {
+	for err != nil {
+		if sdkErr, ok := err.(SDKError); ok {
+			for _, code := range codes {
+				if sdkErr.Code() == code {
+					return true
+				}
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
@@ -53,16 +53,8 @@ func NewValidationError(field, value, constraint, message string) *ValidationErr
 
 // NewValidationErrorWithViolations creates a validation error with multiple violations.
 func NewValidationErrorWithViolations(violations []ValidationViolation) *ValidationError {
-	message := "Request validation failed"
-	if len(violations) == 1 {
-		message = fmt.Sprintf("Validation failed for field '%s': %s",
-			violations[0].Field, violations[0].Message)
-	} else if len(violations) > 1 {
-		message = fmt.Sprintf("Validation failed for %d fields", len(violations))
-	}
-
 	err := &ValidationError{
-		BaseError: NewBaseError(CategoryValidation, SeverityMedium, "VALIDATION_ERROR", message).
+		BaseError: NewBaseError(CategoryValidation, SeverityMedium, "VALIDATION_ERROR", violationsMessage(violations)).
 			WithRetryable(false),
 		Field:      "", // Multiple fields
 		Value:      "", // Multiple values
@@ -99,8 +91,25 @@ func (e *ValidationError) AddViolation(field, code, message string, value, const
 
 	// Update the error message for multiple violations
 	if len(e.Violations) > 1 {
-		e.message = fmt.Sprintf("Validation failed for %d fields", len(e.Violations))
+		e.message = violationsMessage(e.Violations)
+	}
+}
+
+// violationsMessage summarizes violations, listing each one so a single
+// error reports every problem.
+func violationsMessage(violations []ValidationViolation) string {
+	switch len(violations) {
+	case 0:
+		return "Request validation failed"
+	case 1:
+		return fmt.Sprintf("Validation failed for field '%s': %s", violations[0].Field, violations[0].Message)
+	}
+
+	problems := make([]string, len(violations))
+	for i, v := range violations {
+		problems[i] = v.Field + ": " + v.Message
 	}
+	return fmt.Sprintf("Validation failed for %d fields: %s", len(violations), strings.Join(problems, "; "))
 }
 
 // RequestValidationError represents validation errors for API request parameters.
@@ -0,0 +1,67 @@
+/*
+Package eval regression-tests prompt and agent behavior. A Scenario pairs a
+prompt with what the agent is expected to do: the tools it calls or must
+not call, the files it changes, and matchers over its reply. A Runner sends
+each prompt and reports which expectations held.
+
+# Defining Scenarios
+
+	scenarios := []eval.Scenario{{
+		Name:           "fix-typo",
+		Prompt:         "Fix the typo in README.md",
+		Tools:          []string{"Read", "Edit"},
+		ForbiddenTools: []string{"Bash"},
+		FileChanges:    []eval.FileChange{{Path: "README.md", Contains: "Hello"}},
+		Response:       []eval.Matcher{eval.Contains("typo"), eval.NotContains("sorry")},
+	}}
+
+File changes are read from the Write, Edit, MultiEdit and NotebookEdit calls
+the agent made, so they can be checked for recorded runs as well as live
+ones.
+
+# Running
+
+The runner takes any types.QueryStreamer; a ClaudeCodeClient runs the scenarios live:
+
+	runner := &eval.Runner{Executor: claudeClient, Timeout: 5 * time.Minute}
+	report, err := runner.Run(ctx, scenarios...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(report)
+	if !report.Passed() {
+		os.Exit(1)
+	}
+
+With a recorder in replay mode attached to the client, the same scenarios
+run against recorded sessions without the CLI, deterministically:
+
+	rec, err := recorder.New("testdata/evals.json", recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claudeClient.SetRecorder(rec)
+	report, err := runner.Run(ctx, scenarios...)
+
+A scenario's Budget limits the cost, turns and time of its run; a run over
+budget fails the scenario.
+
+Scenarios run one at a time in order. A failed query fails its scenario
+rather than the run, and the report keeps each scenario's Run, with its
+reply and tool calls, for investigating failures. Runs collected elsewhere
+with Collect can be checked directly with Evaluate.
+
+# YAML Suites
+
+Scenarios can be written in YAML by people who do not write Go, and run by
+a Go service or test. LoadSuite validates the file, rejecting unknown keys,
+and the suite's model, system prompt and timeout configure the runner (see
+Suite for the format):
+
+	suite, err := eval.LoadSuite("evals/docs-agent.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	report, err := suite.Run(ctx, claudeClient)
+*/
+package eval
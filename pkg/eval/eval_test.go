@@ -0,0 +1,269 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeStream serves canned stream-json lines.
+type fakeStream struct {
+	lines []string
+}
+
+func (s *fakeStream) Recv() (*types.StreamChunk, error) {
+	if len(s.lines) == 0 {
+		return &types.StreamChunk{Done: true}, nil
+	}
+	line := s.lines[0]
+	s.lines = s.lines[1:]
+	return &types.StreamChunk{Type: types.ChunkTypeContent, Content: line + "\n"}, nil
+}
+
+func (s *fakeStream) Close() error { return nil }
+
+// fakeExecutor answers each prompt with canned lines and records requests.
+type fakeExecutor struct {
+	replies  map[string][]string
+	requests []*types.QueryRequest
+}
+
+func (e *fakeExecutor) QueryStream(ctx context.Context, request *types.QueryRequest) (types.QueryStream, error) {
+	e.requests = append(e.requests, request)
+	lines, ok := e.replies[request.Messages[0].Content]
+	if !ok {
+		return nil, errors.New("CLI not available")
+	}
+	return &fakeStream{lines: lines}, nil
+}
+
+func TestRunner_Run(t *testing.T) {
+	executor := &fakeExecutor{replies: map[string][]string{
+		"Fix the typo in README.md": {
+			`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/src/app/README.md"}}]}}`,
+			`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"# Helo"}]}}`,
+			`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"/src/app/README.md","old_string":"Helo","new_string":"Hello"}}]}}`,
+			`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":"ok"}]}}`,
+			`{"type":"assistant","message":{"content":[{"type":"text","text":"Fixed the typo: Helo is now Hello."}]}}`,
+			`{"type":"result","subtype":"success","is_error":false,"total_cost_usd":0.01}`,
+		},
+		"Add a health endpoint": {
+			`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Write","input":{"file_path":"/src/app/internal/health.go","content":"package internal"}}]}}`,
+			`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"permission denied","is_error":true}]}}`,
+			`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"rm -rf internal"}}]}}`,
+			`{"type":"assistant","message":{"content":[{"type":"text","text":"I could not write the file."}]}}`,
+			`{"type":"result","subtype":"error_max_turns","is_error":true,"total_cost_usd":0.02}`,
+		},
+	}}
+
+	runner := &Runner{Executor: executor, Model: "claude-sonnet-4"}
+	report, err := runner.Run(context.Background(),
+		Scenario{
+			Name:           "fix-typo",
+			Prompt:         "Fix the typo in README.md",
+			Tools:          []string{"Read", "Edit"},
+			ForbiddenTools: []string{"Bash"},
+			FileChanges:    []FileChange{{Path: "README.md", Contains: "Hello"}},
+			Response:       []Matcher{Contains("Fixed"), MatchRegexp(`Hel+o`), NotContains("sorry")},
+		},
+		Scenario{
+			Name:           "add-endpoint",
+			Prompt:         "Add a health endpoint",
+			Model:          "claude-opus-4",
+			ForbiddenTools: []string{"Bash"},
+			FileChanges:    []FileChange{{Path: "internal/*.go"}},
+		},
+		Scenario{Prompt: "Unreachable"},
+	)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(report.Results) != 3 || report.Passed() || len(report.Failed()) != 2 {
+		t.Fatalf("Unexpected report:\n%s", report)
+	}
+	if executor.requests[0].Model != "claude-sonnet-4" || executor.requests[1].Model != "claude-opus-4" {
+		t.Errorf("Expected scenario models to override the runner's, got %q and %q", executor.requests[0].Model, executor.requests[1].Model)
+	}
+
+	fixed := report.Results[0]
+	if !fixed.Passed || len(fixed.Checks) != 8 || fixed.CostUSD != 0.01 {
+		t.Errorf("Expected fix-typo to pass all checks, got %+v", fixed)
+	}
+	if fixed.Run.Text != "Fixed the typo: Helo is now Hello." {
+		t.Errorf("Unexpected reply %q", fixed.Run.Text)
+	}
+
+	var failures []string
+	for _, check := range report.Results[1].Failures() {
+		failures = append(failures, check.Name+": "+check.Message)
+	}
+	want := []string{
+		"completed: the CLI reported error_max_turns",
+		"tool Bash not called: called",
+		"internal/*.go changed: no matching file was changed",
+	}
+	if strings.Join(failures, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected add-endpoint failures:\n%s", strings.Join(failures, "\n"))
+	}
+
+	unreachable := report.Results[2]
+	if unreachable.Scenario != "scenario 3" || unreachable.Passed || unreachable.Checks[0].Name != "run" {
+		t.Errorf("Expected the failed query to fail its scenario, got %+v", unreachable)
+	}
+
+	output := report.String()
+	for _, want := range []string{"PASS  fix-typo", "FAIL  add-endpoint", "      tool Bash not called: called", "3 scenarios, 1 passed, 2 failed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRunner_Validation(t *testing.T) {
+	var configErr *sdkerrors.ConfigurationError
+	if _, err := (&Runner{}).Run(context.Background(), Scenario{Prompt: "hi"}); !errors.As(err, &configErr) {
+		t.Errorf("Expected a configuration error without an executor, got %v", err)
+	}
+
+	var validationErr *sdkerrors.ValidationError
+	runner := &Runner{Executor: &fakeExecutor{}}
+	if _, err := runner.Run(context.Background(), Scenario{Name: "empty"}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected a validation error for a scenario without a prompt, got %v", err)
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		matcher Matcher
+		text    string
+		wantErr string
+	}{
+		{Contains("ok"), "all ok", ""},
+		{Contains("ok"), "fine", `reply does not contain "ok"`},
+		{NotContains("TODO"), "// TODO", `reply contains "TODO"`},
+		{MatchRegexp(`^\d+ tests? passed$`), "12 tests passed", ""},
+		{MatchRegexp(`(`), "anything", "invalid pattern"},
+		{MatchFunc("short", func(text string) error {
+			if len(text) > 5 {
+				return errors.New("reply is too long")
+			}
+			return nil
+		}), "a long reply", "reply is too long"},
+	}
+	for _, tt := range tests {
+		err := tt.matcher.Match(tt.text)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.matcher, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: expected error %q, got %v", tt.matcher, tt.wantErr, err)
+		}
+	}
+
+	if got := (Matcher{Contains: "a", Regexp: "b"}).String(); got != `contains "a" and matches /b/` {
+		t.Errorf("Unexpected description %q", got)
+	}
+}
+
+func TestRun_Changed(t *testing.T) {
+	run := &Run{ToolCalls: []ToolCall{
+		{ToolUse: types.ToolUse{Name: "MultiEdit", Input: map[string]any{
+			"file_path": "/src/app/internal/server.go",
+			"edits":     []any{map[string]any{"new_string": "func health()"}, map[string]any{"new_string": "mux.HandleFunc"}},
+		}}},
+		{ToolUse: types.ToolUse{Name: "Read", Input: map[string]any{"file_path": "/src/app/go.mod"}}},
+	}}
+
+	if written, ok := run.Changed("internal/*.go"); !ok || written != "func health()\nmux.HandleFunc" {
+		t.Errorf("Changed(internal/*.go) = %q, %v", written, ok)
+	}
+	if _, ok := run.Changed("/src/app/internal/server.go"); !ok {
+		t.Error("Expected an absolute pattern to match")
+	}
+	if _, ok := run.Changed("go.mod"); ok {
+		t.Error("Expected a read not to count as a change")
+	}
+	if _, ok := run.Changed("server.go/extra"); ok {
+		t.Error("Expected a longer pattern not to match")
+	}
+}
+
+func TestParseSuite(t *testing.T) {
+	suite, err := ParseSuite([]byte(`
+name: docs-agent
+model: claude-sonnet-4
+timeout: 5m
+scenarios:
+  - name: fix-typo
+    prompt: Fix the typo in README.md
+    tools: [Read, Edit]
+    forbidden_tools: [Bash]
+    file_changes:
+      - path: README.md
+        contains: Hello
+    response:
+      - contains: Fixed
+      - regexp: 'Hel+o'
+    budget:
+      max_cost_usd: 0.005
+      max_turns: 3
+      max_duration: 1m
+`))
+	if err != nil {
+		t.Fatalf("ParseSuite failed: %v", err)
+	}
+	scenario := suite.Scenarios[0]
+	if suite.Timeout != 5*time.Minute || scenario.Budget.MaxDuration != time.Minute || scenario.FileChanges[0].Contains != "Hello" {
+		t.Fatalf("Unexpected suite %+v", suite)
+	}
+
+	executor := &fakeExecutor{replies: map[string][]string{
+		"Fix the typo in README.md": {
+			`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"README.md"}}]}}`,
+			`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"README.md","new_string":"Hello"}}]}}`,
+			`{"type":"assistant","message":{"content":[{"type":"text","text":"Fixed: Hello"}]}}`,
+			`{"type":"result","subtype":"success","is_error":false,"num_turns":5,"total_cost_usd":0.01}`,
+		},
+	}}
+	report, err := suite.Run(context.Background(), executor)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if executor.requests[0].Model != "claude-sonnet-4" {
+		t.Errorf("Expected the suite's model, got %q", executor.requests[0].Model)
+	}
+
+	var failures []string
+	for _, check := range report.Results[0].Failures() {
+		failures = append(failures, check.Name+": "+check.Message)
+	}
+	want := "cost within $0.0050: cost $0.0100\nat most 3 turns: took 5 turns"
+	if strings.Join(failures, "\n") != want {
+		t.Errorf("Expected only the budget to fail, got:\n%s", strings.Join(failures, "\n"))
+	}
+}
+
+func TestParseSuite_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":     "scenarios:\n  - prompt: hi\n    tool: [Read]\n",
+		"no scenarios":    "name: empty\n",
+		"no prompt":       "scenarios:\n  - name: a\n",
+		"duplicate name":  "scenarios:\n  - {name: a, prompt: hi}\n  - {name: a, prompt: ho}\n",
+		"bad regexp":      "scenarios:\n  - prompt: hi\n    response: [{regexp: '('}]\n",
+		"empty matcher":   "scenarios:\n  - prompt: hi\n    response: [{}]\n",
+		"bad path":        "scenarios:\n  - prompt: hi\n    file_changes: [{path: '['}]\n",
+		"negative budget": "scenarios:\n  - prompt: hi\n    budget: {max_turns: -1}\n",
+		"bad duration":    "scenarios:\n  - prompt: hi\n    budget: {max_duration: soon}\n",
+	}
+	for name, yaml := range tests {
+		if _, err := ParseSuite([]byte(yaml)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
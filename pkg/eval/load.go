@@ -0,0 +1,102 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Suite is a set of scenarios with the runner settings they share, as
+// declared in a YAML file:
+//
+//	name: docs-agent
+//	model: claude-sonnet-4
+//	timeout: 5m
+//	scenarios:
+//	  - name: fix-typo
+//	    prompt: Fix the typo in README.md
+//	    tools: [Read, Edit]
+//	    forbidden_tools: [Bash]
+//	    file_changes:
+//	      - path: README.md
+//	        contains: Hello
+//	    response:
+//	      - contains: typo
+//	      - regexp: '(?i)fixed'
+//	    budget:
+//	      max_cost_usd: 0.25
+//	      max_turns: 8
+//	      max_duration: 2m
+type Suite struct {
+	// Name identifies the suite
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Model, System and Timeout configure the runner (see Runner)
+	Model   string        `json:"model,omitempty" yaml:"model,omitempty"`
+	System  string        `json:"system,omitempty" yaml:"system,omitempty"`
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// Scenarios are run in order
+	Scenarios []Scenario `json:"scenarios" yaml:"scenarios"`
+}
+
+// LoadSuite reads and validates a suite from a YAML file.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - suite path is provided by the caller
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "EVAL_SUITE", "failed to read eval suite "+path)
+	}
+	return ParseSuite(data)
+}
+
+// ParseSuite decodes and validates a suite from YAML. Unknown keys are
+// rejected so that typos do not silently drop expectations. Scenario names
+// must be unique.
+func ParseSuite(data []byte) (*Suite, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var suite Suite
+	if err := decoder.Decode(&suite); err != nil && !errors.Is(err, io.EOF) {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "EVAL_SUITE", "invalid eval suite")
+	}
+	if suite.Timeout < 0 {
+		return nil, sdkerrors.NewValidationError("timeout", suite.Timeout.String(), "0 or more", "suite timeout cannot be negative")
+	}
+	if len(suite.Scenarios) == 0 {
+		return nil, sdkerrors.NewValidationError("scenarios", suite.Name, "at least one", "eval suite has no scenarios")
+	}
+
+	names := make(map[string]bool, len(suite.Scenarios))
+	for i := range suite.Scenarios {
+		scenario := &suite.Scenarios[i]
+		scenario.Name = scenarioName(*scenario, i)
+		if names[scenario.Name] {
+			return nil, sdkerrors.NewValidationError("scenarios", scenario.Name, "unique name", "duplicate scenario "+scenario.Name)
+		}
+		names[scenario.Name] = true
+		if err := scenario.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &suite, nil
+}
+
+// Runner returns a runner with the suite's settings that runs prompts with
+// executor.
+func (s *Suite) Runner(executor types.QueryStreamer) *Runner {
+	return &Runner{Executor: executor, Model: s.Model, System: s.System, Timeout: s.Timeout}
+}
+
+// Run runs the suite's scenarios with executor.
+func (s *Suite) Run(ctx context.Context, executor types.QueryStreamer) (*Report, error) {
+	return s.Runner(executor).Run(ctx, s.Scenarios...)
+}
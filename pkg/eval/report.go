@@ -0,0 +1,106 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Check is the outcome of one expectation of a scenario.
+type Check struct {
+	// Name describes the expectation, e.g. "tool Edit called"
+	Name string `json:"name"`
+
+	// Passed reports whether the expectation was met
+	Passed bool `json:"passed"`
+
+	// Message explains a failed check
+	Message string `json:"message,omitempty"`
+}
+
+// Result is the outcome of a scenario.
+type Result struct {
+	// Scenario is the scenario's name
+	Scenario string `json:"scenario"`
+
+	// Passed reports whether every check passed
+	Passed bool `json:"passed"`
+
+	// Checks are the scenario's expectations and their outcomes
+	Checks []Check `json:"checks"`
+
+	// Duration and CostUSD are the wall time and reported cost of the run
+	Duration time.Duration `json:"duration_ns"`
+	CostUSD  float64       `json:"cost_usd,omitempty"`
+
+	// Run is what the agent did, for investigating failures
+	Run *Run `json:"run,omitempty"`
+}
+
+// pass records a check that passed.
+func (r *Result) pass(name string) {
+	r.Checks = append(r.Checks, Check{Name: name, Passed: true})
+}
+
+// fail records a check that failed, failing the result.
+func (r *Result) fail(name, message string) {
+	r.Checks = append(r.Checks, Check{Name: name, Message: message})
+	r.Passed = false
+}
+
+// Failures returns the checks that failed.
+func (r Result) Failures() []Check {
+	var failures []Check
+	for _, check := range r.Checks {
+		if !check.Passed {
+			failures = append(failures, check)
+		}
+	}
+	return failures
+}
+
+// Report is the outcome of a set of scenarios.
+type Report struct {
+	// Results are the scenarios' outcomes, in the order they ran
+	Results []Result `json:"results"`
+}
+
+// Passed reports whether every scenario passed.
+func (r *Report) Passed() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the results of the scenarios that failed.
+func (r *Report) Failed() []Result {
+	var failed []Result
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// String renders the report in the style of go test output, listing the
+// failed checks of each failed scenario:
+//
+//	PASS  fix-typo (1.2s, $0.0100)
+//	FAIL  add-endpoint (3.4s, $0.0200)
+//	      tool Edit called: not called
+//	2 scenarios, 1 passed, 1 failed
+func (r *Report) String() string {
+	var out strings.Builder
+	for _, result := range r.Results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&out, "%s  %s (%s, $%.4f)\n", status, result.Scenario, result.Duration.Round(100*time.Millisecond), result.CostUSD)
+		for _, check := range result.Failures() {
+			fmt.Fprintf(&out, "      %s: %s\n", check.Name, check.Message)
+		}
+	}
+	failed := len(r.Failed())
+	fmt.Fprintf(&out, "%d scenarios, %d passed, %d failed\n", len(r.Results), len(r.Results)-failed, failed)
+	return out.String()
+}
@@ -0,0 +1,346 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// ToolCall is a tool Claude called during a run.
+type ToolCall struct {
+	types.ToolUse
+
+	// Failed reports whether the tool returned an error
+	Failed bool `json:"failed,omitempty"`
+}
+
+// Run is what the agent did to answer a prompt.
+type Run struct {
+	// Text is the reply: the text of Claude's messages, one per line
+	Text string `json:"text"`
+
+	// ToolCalls are the tools Claude called, in order, including those of
+	// subagents
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+
+	// Result is the result the CLI reported, if any
+	Result *types.ResultMessage `json:"result,omitempty"`
+
+	// Duration is the wall time of the run
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// streamLine is the subset of a stream-json line a run is built from.
+type streamLine struct {
+	Type            string  `json:"type"`
+	ParentToolUseID *string `json:"parent_tool_use_id"`
+	Message         *struct {
+		Content []types.ContentBlock `json:"content"`
+	} `json:"message"`
+}
+
+// Collect reads stream to its end and returns the run it describes. Plain
+// text output is taken as reply text. The stream is not closed.
+func Collect(stream types.QueryStream) (*Run, error) {
+	run := &Run{}
+	var text []string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return run, err
+		}
+		if chunk.Done {
+			break
+		}
+
+		trimmed := strings.TrimSpace(chunk.Content)
+		var line streamLine
+		if !strings.HasPrefix(trimmed, "{") || json.Unmarshal([]byte(trimmed), &line) != nil || line.Type == "" {
+			if trimmed != "" {
+				text = append(text, trimmed)
+			}
+			continue
+		}
+
+		switch line.Type {
+		case "assistant", "user":
+			if line.Message == nil {
+				continue
+			}
+			subagent := line.ParentToolUseID != nil && *line.ParentToolUseID != ""
+			for _, block := range line.Message.Content {
+				switch block.Type {
+				case "text":
+					if line.Type == "assistant" && !subagent && block.Text != "" {
+						text = append(text, block.Text)
+					}
+				case "tool_use":
+					run.ToolCalls = append(run.ToolCalls, ToolCall{ToolUse: types.ToolUseFromBlock(block)})
+				case "tool_result":
+					for i := range run.ToolCalls {
+						if run.ToolCalls[i].ID == block.ToolUseID {
+							run.ToolCalls[i].Failed = block.IsError
+						}
+					}
+				}
+			}
+
+		case "result":
+			if result, err := types.ParseResultMessage([]byte(trimmed)); err == nil {
+				run.Result = result
+			}
+		}
+	}
+
+	run.Text = strings.Join(text, "\n")
+	if run.Text == "" && run.Result != nil {
+		run.Text = run.Result.Result
+	}
+	return run, nil
+}
+
+// Called reports whether the named tool was called.
+func (r *Run) Called(name string) bool {
+	for _, call := range r.ToolCalls {
+		if call.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fileEditTools maps the tools that change files to the input naming the
+// file.
+var fileEditTools = map[string]string{
+	"Write":        "file_path",
+	"Edit":         "file_path",
+	"MultiEdit":    "file_path",
+	"NotebookEdit": "notebook_path",
+}
+
+// Changed returns the text written to files matching pattern by successful
+// Write, Edit, MultiEdit and NotebookEdit calls, joined by newlines, and
+// whether any such call was made. See FileChange.Path for the pattern.
+func (r *Run) Changed(pattern string) (string, bool) {
+	var written []string
+	changed := false
+	for _, call := range r.ToolCalls {
+		key, ok := fileEditTools[call.Name]
+		if !ok || call.Failed {
+			continue
+		}
+		file, _ := call.Input[key].(string)
+		if file == "" || !matchPath(pattern, file) {
+			continue
+		}
+		changed = true
+		written = append(written, writtenText(call.Input)...)
+	}
+	return strings.Join(written, "\n"), changed
+}
+
+// writtenText returns the text a file editing call writes.
+func writtenText(input map[string]any) []string {
+	var written []string
+	for _, key := range []string{"content", "new_string", "new_source"} {
+		if text, ok := input[key].(string); ok {
+			written = append(written, text)
+		}
+	}
+	if edits, ok := input["edits"].([]any); ok {
+		for _, edit := range edits {
+			if edit, ok := edit.(map[string]any); ok {
+				written = append(written, writtenText(edit)...)
+			}
+		}
+	}
+	return written
+}
+
+// Runner runs scenarios and evaluates the runs.
+type Runner struct {
+	// Executor runs the prompts (required)
+	Executor types.QueryStreamer
+
+	// Model and System are used by scenarios that do not set their own
+	Model  string
+	System string
+
+	// Timeout bounds each scenario's run (no limit when zero)
+	Timeout time.Duration
+}
+
+// Run runs the scenarios one at a time, in order, so a replayed cassette
+// serves them in the order they were recorded, and reports the outcome of
+// each. Failed queries fail their scenario; the error is only for invalid
+// scenarios (see Scenario.Validate) or a missing executor.
+func (r *Runner) Run(ctx context.Context, scenarios ...Scenario) (*Report, error) {
+	if r.Executor == nil {
+		return nil, sdkerrors.NewConfigurationError("executor", "an eval runner needs an executor")
+	}
+	for i := range scenarios {
+		scenario := scenarios[i]
+		scenario.Name = scenarioName(scenario, i)
+		if err := scenario.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &Report{}
+	for i, scenario := range scenarios {
+		scenario.Name = scenarioName(scenario, i)
+		run, err := r.run(ctx, scenario)
+		report.Results = append(report.Results, evaluate(scenario, run, err))
+	}
+	return report, nil
+}
+
+// run runs a scenario's prompt and collects what the agent did.
+func (r *Runner) run(ctx context.Context, scenario Scenario) (*Run, error) {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	request := &types.QueryRequest{
+		Model:    firstNonEmpty(scenario.Model, r.Model),
+		System:   firstNonEmpty(scenario.System, r.System),
+		Messages: []types.Message{{Role: types.RoleUser, Content: scenario.Prompt}},
+	}
+
+	started := time.Now()
+	stream, err := r.Executor.QueryStream(ctx, request)
+	if err != nil {
+		return &Run{Duration: time.Since(started)}, err
+	}
+	defer stream.Close()
+
+	run, err := Collect(stream)
+	run.Duration = time.Since(started)
+	return run, err
+}
+
+// scenarioName returns the scenario's name, or its position if unnamed.
+func scenarioName(scenario Scenario, i int) string {
+	if scenario.Name != "" {
+		return scenario.Name
+	}
+	return fmt.Sprintf("scenario %d", i+1)
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Evaluate checks a run against the scenario's expectations. Runs gathered
+// elsewhere, for example with Collect, can be evaluated without a Runner.
+func Evaluate(scenario Scenario, run *Run) Result {
+	return evaluate(scenario, run, nil)
+}
+
+// evaluate checks a run that ended with err, which fails the scenario.
+func evaluate(scenario Scenario, run *Run, err error) Result {
+	if run == nil {
+		run = &Run{}
+	}
+	result := Result{Scenario: scenario.Name, Passed: true, Duration: run.Duration, Run: run}
+	if err != nil {
+		result.fail("run", err.Error())
+	}
+
+	if run.Result != nil {
+		result.CostUSD = run.Result.Cost()
+		if !run.Result.Succeeded() {
+			result.fail("completed", "the CLI reported "+firstNonEmpty(run.Result.Subtype, "an error"))
+		} else {
+			result.pass("completed")
+		}
+	}
+
+	for _, tool := range scenario.Tools {
+		name := fmt.Sprintf("tool %s called", tool)
+		if run.Called(tool) {
+			result.pass(name)
+		} else {
+			result.fail(name, "not called")
+		}
+	}
+	for _, tool := range scenario.ForbiddenTools {
+		name := fmt.Sprintf("tool %s not called", tool)
+		if run.Called(tool) {
+			result.fail(name, "called")
+		} else {
+			result.pass(name)
+		}
+	}
+
+	for _, change := range scenario.FileChanges {
+		written, changed := run.Changed(change.Path)
+		switch {
+		case !changed:
+			result.fail(change.String(), "no matching file was changed")
+		case change.Contains != "" && !strings.Contains(written, change.Contains):
+			result.fail(change.String(), fmt.Sprintf("the text written does not contain %q", change.Contains))
+		default:
+			result.pass(change.String())
+		}
+	}
+
+	for _, matcher := range scenario.Response {
+		name := "reply " + matcher.String()
+		if err := matcher.Match(run.Text); err != nil {
+			result.fail(name, err.Error())
+		} else {
+			result.pass(name)
+		}
+	}
+
+	budget := scenario.Budget
+	if budget.MaxCostUSD > 0 {
+		name := fmt.Sprintf("cost within $%.4f", budget.MaxCostUSD)
+		switch {
+		case run.Result == nil:
+			result.fail(name, "no cost was reported")
+		case result.CostUSD > budget.MaxCostUSD:
+			result.fail(name, fmt.Sprintf("cost $%.4f", result.CostUSD))
+		default:
+			result.pass(name)
+		}
+	}
+	if budget.MaxTurns > 0 {
+		name := fmt.Sprintf("at most %d turns", budget.MaxTurns)
+		switch {
+		case run.Result == nil:
+			result.fail(name, "no turns were reported")
+		case run.Result.NumTurns > budget.MaxTurns:
+			result.fail(name, fmt.Sprintf("took %d turns", run.Result.NumTurns))
+		default:
+			result.pass(name)
+		}
+	}
+	if budget.MaxDuration > 0 {
+		name := fmt.Sprintf("finished within %s", budget.MaxDuration)
+		if run.Duration > budget.MaxDuration {
+			result.fail(name, fmt.Sprintf("took %s", run.Duration.Round(time.Millisecond)))
+		} else {
+			result.pass(name)
+		}
+	}
+	return result
+}
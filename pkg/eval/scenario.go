@@ -0,0 +1,206 @@
+package eval
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// Scenario is a prompt and the behavior expected of the agent answering it.
+// Every expectation that is set becomes a check in the scenario's Result.
+type Scenario struct {
+	// Name identifies the scenario in reports
+	Name string `json:"name" yaml:"name"`
+
+	// Prompt is sent to Claude
+	Prompt string `json:"prompt" yaml:"prompt"`
+
+	// Model and System override the runner's model and system prompt
+	Model  string `json:"model,omitempty" yaml:"model,omitempty"`
+	System string `json:"system,omitempty" yaml:"system,omitempty"`
+
+	// Tools must each be called at least once, e.g. "Read" or
+	// "mcp__github__create_issue"
+	Tools []string `json:"tools,omitempty" yaml:"tools,omitempty"`
+
+	// ForbiddenTools must not be called
+	ForbiddenTools []string `json:"forbidden_tools,omitempty" yaml:"forbidden_tools,omitempty"`
+
+	// FileChanges must each be made by a successful Write, Edit, MultiEdit
+	// or NotebookEdit call
+	FileChanges []FileChange `json:"file_changes,omitempty" yaml:"file_changes,omitempty"`
+
+	// Response must all match the reply text
+	Response []Matcher `json:"response,omitempty" yaml:"response,omitempty"`
+
+	// Budget limits what the run may spend
+	Budget Budget `json:"budget,omitempty" yaml:"budget,omitempty"`
+}
+
+// Budget limits the cost, turns and time of a scenario's run. Zero fields
+// are unlimited. A run over budget fails its scenario but is not stopped;
+// use Runner.Timeout to cut runs short.
+type Budget struct {
+	// MaxCostUSD is the most the run may cost, as reported by the CLI
+	MaxCostUSD float64 `json:"max_cost_usd,omitempty" yaml:"max_cost_usd,omitempty"`
+
+	// MaxTurns is the most agent turns the run may take
+	MaxTurns int `json:"max_turns,omitempty" yaml:"max_turns,omitempty"`
+
+	// MaxDuration is the longest the run may take
+	MaxDuration time.Duration `json:"max_duration,omitempty" yaml:"max_duration,omitempty"`
+}
+
+// Validate checks that the scenario can run: it needs a prompt, and its
+// patterns and budget must be valid.
+func (s Scenario) Validate() error {
+	if strings.TrimSpace(s.Prompt) == "" {
+		return sdkerrors.NewValidationError("prompt", s.Name, "required", "scenario has no prompt")
+	}
+	for _, change := range s.FileChanges {
+		if _, err := path.Match(change.Path, ""); change.Path == "" || err != nil {
+			return sdkerrors.NewValidationError("file_changes", change.Path, "path pattern", "scenario "+s.Name+" has an invalid file change path")
+		}
+	}
+	for _, matcher := range s.Response {
+		if matcher.Contains == "" && matcher.NotContains == "" && matcher.Regexp == "" && matcher.Func == nil {
+			return sdkerrors.NewValidationError("response", s.Name, "contains, not_contains or regexp", "scenario "+s.Name+" has an empty response matcher")
+		}
+		if _, err := regexp.Compile(matcher.Regexp); err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "EVAL_SCENARIO", "scenario "+s.Name+" has an invalid response pattern")
+		}
+	}
+	if s.Budget.MaxCostUSD < 0 || s.Budget.MaxTurns < 0 || s.Budget.MaxDuration < 0 {
+		return sdkerrors.NewValidationError("budget", s.Name, "0 or more", "scenario "+s.Name+" has a negative budget")
+	}
+	return nil
+}
+
+// FileChange is a file the agent is expected to write or edit.
+type FileChange struct {
+	// Path is matched with path.Match against the path the tool
+	// changed. Relative patterns match the trailing components of the path,
+	// so "internal/*.go" matches "/src/app/internal/server.go".
+	Path string `json:"path" yaml:"path"`
+
+	// Contains, when set, must appear in the text written to the file: the
+	// content of a Write or the new text of an edit
+	Contains string `json:"contains,omitempty" yaml:"contains,omitempty"`
+}
+
+// String describes the change for reports.
+func (f FileChange) String() string {
+	if f.Contains != "" {
+		return fmt.Sprintf("%s changed to contain %q", f.Path, f.Contains)
+	}
+	return f.Path + " changed"
+}
+
+// Matcher is an expectation of the reply text. Every field that is set must
+// match; the Contains, NotContains and Regexp functions build matchers with
+// one field set.
+type Matcher struct {
+	// Contains must appear in the text
+	Contains string `json:"contains,omitempty" yaml:"contains,omitempty"`
+
+	// NotContains must not appear in the text
+	NotContains string `json:"not_contains,omitempty" yaml:"not_contains,omitempty"`
+
+	// Regexp must match the text
+	Regexp string `json:"regexp,omitempty" yaml:"regexp,omitempty"`
+
+	// Func returns an error describing why the text does not match
+	Func func(text string) error `json:"-" yaml:"-"`
+
+	// Name describes Func in reports
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// Contains returns a Matcher requiring the reply to contain s.
+func Contains(s string) Matcher {
+	return Matcher{Contains: s}
+}
+
+// NotContains returns a Matcher requiring the reply not to contain s.
+func NotContains(s string) Matcher {
+	return Matcher{NotContains: s}
+}
+
+// MatchRegexp returns a Matcher requiring pattern to match the reply. An
+// invalid pattern fails Scenario.Validate, and the check if not validated.
+func MatchRegexp(pattern string) Matcher {
+	return Matcher{Regexp: pattern}
+}
+
+// MatchFunc returns a Matcher that checks the reply with fn, named name in
+// reports.
+func MatchFunc(name string, fn func(text string) error) Matcher {
+	return Matcher{Name: name, Func: fn}
+}
+
+// String describes the matcher for reports.
+func (m Matcher) String() string {
+	var parts []string
+	if m.Contains != "" {
+		parts = append(parts, fmt.Sprintf("contains %q", m.Contains))
+	}
+	if m.NotContains != "" {
+		parts = append(parts, fmt.Sprintf("does not contain %q", m.NotContains))
+	}
+	if m.Regexp != "" {
+		parts = append(parts, fmt.Sprintf("matches /%s/", m.Regexp))
+	}
+	if m.Func != nil {
+		name := m.Name
+		if name == "" {
+			name = "custom check"
+		}
+		parts = append(parts, name)
+	}
+	if len(parts) == 0 {
+		return "anything"
+	}
+	return strings.Join(parts, " and ")
+}
+
+// Match returns an error describing why text does not match, or nil.
+func (m Matcher) Match(text string) error {
+	if m.Contains != "" && !strings.Contains(text, m.Contains) {
+		return fmt.Errorf("reply does not contain %q", m.Contains)
+	}
+	if m.NotContains != "" && strings.Contains(text, m.NotContains) {
+		return fmt.Errorf("reply contains %q", m.NotContains)
+	}
+	if m.Regexp != "" {
+		re, err := regexp.Compile(m.Regexp)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		if !re.MatchString(text) {
+			return fmt.Errorf("reply does not match /%s/", m.Regexp)
+		}
+	}
+	if m.Func != nil {
+		return m.Func(text)
+	}
+	return nil
+}
+
+// matchPath reports whether pattern matches file, comparing a relative
+// pattern with the same number of trailing components of file.
+func matchPath(pattern, file string) bool {
+	pattern, file = filepath.ToSlash(filepath.Clean(pattern)), filepath.ToSlash(filepath.Clean(file))
+	if !strings.HasPrefix(pattern, "/") {
+		parts := strings.Split(file, "/")
+		if n := strings.Count(pattern, "/") + 1; n < len(parts) {
+			file = strings.Join(parts[len(parts)-n:], "/")
+		}
+	}
+	matched, err := path.Match(pattern, file)
+	return err == nil && matched
+}
@@ -0,0 +1,114 @@
+package githubflow
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// FileChange is a changed file in the workspace.
+type FileChange struct {
+	// Path is relative to the repository root
+	Path string `json:"path"`
+
+	// Status is "added", "modified", "deleted" or "renamed"
+	Status string `json:"status"`
+
+	// OldPath is the previous path of a renamed file
+	OldPath string `json:"old_path,omitempty"`
+}
+
+// ChangeSet is the set of uncommitted changes in a git workspace, typically
+// the edits Claude made during a query.
+type ChangeSet struct {
+	// Dir is the repository root
+	Dir string `json:"dir"`
+
+	// Files are the changed files, in git status order
+	Files []FileChange `json:"files"`
+}
+
+// Collect returns the uncommitted changes of the repository containing dir,
+// including untracked files that are not ignored.
+func Collect(ctx context.Context, dir string) (*ChangeSet, error) {
+	root, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := git(ctx, root, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	return &ChangeSet{Dir: root, Files: parseStatus(status)}, nil
+}
+
+// Empty reports whether there is nothing to commit.
+func (c *ChangeSet) Empty() bool {
+	return c == nil || len(c.Files) == 0
+}
+
+// Paths returns the paths to stage: every changed path, including the old
+// path of renamed files.
+func (c *ChangeSet) Paths() []string {
+	var paths []string
+	for _, file := range c.Files {
+		if file.OldPath != "" {
+			paths = append(paths, file.OldPath)
+		}
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+// parseStatus parses `git status --porcelain -z` output.
+func parseStatus(output string) []FileChange {
+	entries := strings.Split(output, "\x00")
+
+	var files []FileChange
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		code, path := entry[:2], entry[3:]
+		change := FileChange{Path: path, Status: "modified"}
+		switch {
+		case code == "??" || strings.Contains(code, "A"):
+			change.Status = "added"
+		case strings.Contains(code, "D"):
+			change.Status = "deleted"
+		case strings.Contains(code, "R"):
+			// The original path follows a rename as a separate entry
+			change.Status = "renamed"
+			if i+1 < len(entries) {
+				i++
+				change.OldPath = entries[i]
+			}
+		}
+		files = append(files, change)
+	}
+	return files
+}
+
+// git runs a git command in dir and returns its trimmed output. Failures
+// include git's error output.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - fixed git subcommands
+	cmd.Dir = dir
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		message := "git " + args[0] + " failed"
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			message += ": " + detail
+		}
+		return "", sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "GIT_COMMAND", message)
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
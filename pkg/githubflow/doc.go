@@ -0,0 +1,33 @@
+/*
+Package githubflow turns Claude's edits into a GitHub pull request, making
+code review and documentation agents usable end to end.
+
+Collect reads the uncommitted changes of a git workspace into a ChangeSet.
+Flow.Open then creates a branch, commits the changes with a message Claude
+writes from the diff, pushes the branch and opens a pull request through the
+GitHub REST API.
+
+# Basic Usage
+
+	session, _ := claudeClient.CreateSession(ctx, "")
+	if _, err := session.Query(ctx, request); err != nil {
+		log.Fatal(err)
+	}
+
+	changes, err := githubflow.Collect(ctx, config.WorkingDirectory)
+	if err != nil || changes.Empty() {
+		return
+	}
+
+	flow := &githubflow.Flow{Executor: session, Draft: true}
+	pr, err := flow.Open(ctx, changes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Opened", pr.URL)
+
+The token defaults to $GITHUB_TOKEN and the repository to the owner and
+name in the origin remote's URL. Passing the session that made the changes
+as the Executor lets Claude explain why, not just what, in the description.
+*/
+package githubflow
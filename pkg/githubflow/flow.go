@@ -0,0 +1,204 @@
+package githubflow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// DefaultBranchPrefix starts the names of generated branches
+	DefaultBranchPrefix = "claude/"
+
+	// maxMessageDiff bounds the diff sent to Claude to write the commit message
+	maxMessageDiff = 60000
+)
+
+// Flow turns a ChangeSet into a pull request: it creates a branch, commits
+// the changes with a message written by Claude, pushes the branch and opens
+// a pull request through the GitHub API.
+//
+// The branch is left checked out afterwards, and is not cleaned up if a
+// later step fails, so the changes are never lost.
+type Flow struct {
+	// Executor writes the commit message and pull request description
+	// (nil uses Title and a list of the changed files). A session that made
+	// the changes writes better messages because it knows why.
+	Executor types.QueryExecutor
+
+	// Token authenticates GitHub API requests (defaults to $GITHUB_TOKEN)
+	Token string
+
+	// Owner and Repo identify the repository (defaults to the remote's URL)
+	Owner string
+	Repo  string
+
+	// Remote is the git remote pushed to (defaults to "origin")
+	Remote string
+
+	// Base is the branch the pull request targets (defaults to the branch
+	// checked out when Open is called)
+	Base string
+
+	// Branch names the new branch (defaults to BranchPrefix plus a timestamp)
+	Branch string
+
+	// BranchPrefix starts generated branch names (defaults to DefaultBranchPrefix)
+	BranchPrefix string
+
+	// Title is used as the commit subject when there is no Executor
+	Title string
+
+	// Draft opens the pull request as a draft
+	Draft bool
+
+	// APIURL is the GitHub API endpoint (defaults to DefaultAPIURL), for
+	// GitHub Enterprise Server
+	APIURL string
+
+	// HTTPClient sends API requests (default http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// Open commits changes to a new branch, pushes it and opens a pull request.
+func (f *Flow) Open(ctx context.Context, changes *ChangeSet) (*PullRequest, error) {
+	if changes.Empty() {
+		return nil, sdkerrors.NewValidationError("changes", "", "non-empty", "there are no changes to commit")
+	}
+	dir := changes.Dir
+
+	token := f.Token
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		return nil, sdkerrors.NewConfigurationError("token", "a GitHub token is required; set Token or GITHUB_TOKEN")
+	}
+
+	remote := f.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+	owner, repo := f.Owner, f.Repo
+	if owner == "" || repo == "" {
+		remoteURL, err := git(ctx, dir, "remote", "get-url", remote)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if owner, repo, ok = parseRemote(remoteURL); !ok {
+			return nil, sdkerrors.NewConfigurationError("repo", "cannot infer the GitHub repository from remote "+remoteURL)
+		}
+	}
+
+	pr := &PullRequest{Base: f.Base, Branch: f.Branch}
+	if pr.Base == "" {
+		base, err := git(ctx, dir, "symbolic-ref", "--short", "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		pr.Base = base
+	}
+	if pr.Branch == "" {
+		prefix := f.BranchPrefix
+		if prefix == "" {
+			prefix = DefaultBranchPrefix
+		}
+		pr.Branch = prefix + time.Now().UTC().Format("20060102-150405")
+	}
+
+	if _, err := git(ctx, dir, "checkout", "-b", pr.Branch); err != nil {
+		return nil, err
+	}
+	if _, err := git(ctx, dir, append([]string{"add", "-A", "--"}, changes.Paths()...)...); err != nil {
+		return nil, err
+	}
+
+	diff, err := git(ctx, dir, "diff", "--cached")
+	if err != nil {
+		return nil, err
+	}
+	pr.Title, pr.Body, err = f.message(ctx, changes, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	message := pr.Title
+	if pr.Body != "" {
+		message += "\n\n" + pr.Body
+	}
+	if _, err := git(ctx, dir, "commit", "-m", message); err != nil {
+		return nil, err
+	}
+	if pr.Commit, err = git(ctx, dir, "rev-parse", "HEAD"); err != nil {
+		return nil, err
+	}
+	if _, err := git(ctx, dir, "push", "-u", remote, pr.Branch); err != nil {
+		return nil, err
+	}
+
+	if err := f.createPullRequest(ctx, token, owner, repo, pr); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// message returns the commit subject and body for the staged diff.
+func (f *Flow) message(ctx context.Context, changes *ChangeSet, diff string) (string, string, error) {
+	if f.Executor == nil {
+		title := f.Title
+		if title == "" {
+			title = "Update " + changes.Files[0].Path
+			if len(changes.Files) > 1 {
+				title = fmt.Sprintf("Update %s and %d more files", changes.Files[0].Path, len(changes.Files)-1)
+			}
+		}
+		var body strings.Builder
+		for _, file := range changes.Files {
+			fmt.Fprintf(&body, "- %s %s\n", file.Status, file.Path)
+		}
+		return title, strings.TrimSpace(body.String()), nil
+	}
+
+	if len(diff) > maxMessageDiff {
+		diff = diff[:maxMessageDiff] + "\n[diff truncated]"
+	}
+	prompt := "Write a git commit message for the following diff. Start with a summary line of at most 72 characters in the imperative mood, then a blank line, then a short body explaining what changed and why. Reply with the commit message only, without code fences.\n\n" + diff
+
+	response, err := f.Executor.Query(ctx, &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: prompt}},
+	})
+	if err != nil {
+		return "", "", sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "GITHUB_FLOW", "failed to generate the commit message")
+	}
+
+	title, body := splitMessage(response.GetTextContent())
+	if title == "" {
+		return "", "", sdkerrors.NewValidationError("message", "", "non-empty", "Claude returned an empty commit message")
+	}
+	return title, body, nil
+}
+
+// splitMessage splits a commit message into its subject and body, dropping
+// code fences Claude may have added anyway.
+func splitMessage(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimSuffix(text, "```")
+		if newline := strings.Index(text, "\n"); newline >= 0 {
+			text = text[newline+1:]
+		} else {
+			text = ""
+		}
+		text = strings.TrimSpace(text)
+	}
+
+	title, body, _ := strings.Cut(text, "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(body)
+}
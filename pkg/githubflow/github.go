@@ -0,0 +1,121 @@
+package githubflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// DefaultAPIURL is the GitHub REST API endpoint.
+const DefaultAPIURL = "https://api.github.com"
+
+// PullRequest is a pull request opened by a Flow.
+type PullRequest struct {
+	// Number is the pull request number
+	Number int `json:"number"`
+
+	// URL is the pull request's web page
+	URL string `json:"html_url"`
+
+	// Title and Body describe the change
+	Title string `json:"title"`
+	Body  string `json:"body"`
+
+	// Branch is the pushed head branch and Base the branch it targets
+	Branch string `json:"-"`
+	Base   string `json:"-"`
+
+	// Commit is the SHA of the commit on Branch
+	Commit string `json:"-"`
+}
+
+// createPullRequest opens a pull request through the GitHub REST API.
+func (f *Flow) createPullRequest(ctx context.Context, token, owner, repo string, pr *PullRequest) error {
+	body, err := json.Marshal(map[string]any{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Branch,
+		"base":  pr.Base,
+		"draft": f.Draft,
+	})
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "GITHUB_ENCODE", "failed to encode pull request")
+	}
+
+	apiURL := f.APIURL
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/pulls", strings.TrimRight(apiURL, "/"), owner, repo)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "GITHUB_REQUEST", "failed to create GitHub request")
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	httpClient := f.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return sdkerrors.NewNetworkError("create pull request", req.URL.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }() // Ignore error, body fully read
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return sdkerrors.NewNetworkError("create pull request", req.URL.Host, err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr) // Fall back to the status alone
+		message := "GitHub returned " + resp.Status
+		if apiErr.Message != "" {
+			message += ": " + apiErr.Message
+		}
+		return sdkerrors.NewInternalError("GITHUB_STATUS", message)
+	}
+
+	if err := json.Unmarshal(data, pr); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "GITHUB_DECODE", "invalid pull request response")
+	}
+	return nil
+}
+
+// parseRemote extracts the owner and repository name from a GitHub remote
+// URL such as git@github.com:owner/repo.git or https://github.com/owner/repo.
+func parseRemote(remote string) (owner, repo string, ok bool) {
+	path := remote
+	switch {
+	case strings.Contains(remote, "://"):
+		path = remote[strings.Index(remote, "://")+3:]
+		if slash := strings.Index(path, "/"); slash >= 0 {
+			path = path[slash+1:]
+		} else {
+			return "", "", false
+		}
+	case strings.Contains(remote, ":"):
+		path = remote[strings.Index(remote, ":")+1:]
+	default:
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(path, "/"), ".git"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
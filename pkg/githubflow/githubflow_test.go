@@ -0,0 +1,202 @@
+package githubflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeExecutor answers every query with a fixed response and records prompts.
+type fakeExecutor struct {
+	response string
+	prompts  []string
+}
+
+func (e *fakeExecutor) Query(_ context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	e.prompts = append(e.prompts, request.Messages[0].GetText())
+	return &types.QueryResponse{Content: []types.ContentBlock{{Type: "text", Text: e.response}}}, nil
+}
+
+// newRepo creates a repository with one commit and a bare "origin" remote.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	base := t.TempDir()
+	dir := filepath.Join(base, "work")
+	remote := filepath.Join(base, "remote.git")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	run(base, "init", "-q", "--bare", remote)
+	run(base, "init", "-q", "-b", "main", dir)
+	run(dir, "config", "user.name", "Test")
+	run(dir, "config", "user.email", "test@example.com")
+	writeFile(t, dir, "main.go", "package main\n")
+	writeFile(t, dir, "old.go", "package main\n")
+	run(dir, "add", "-A")
+	run(dir, "commit", "-q", "-m", "Initial commit")
+	run(dir, "remote", "add", "origin", remote)
+	return dir
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	dir := newRepo(t)
+	writeFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	writeFile(t, dir, "new.go", "package main\n")
+	if err := os.Remove(filepath.Join(dir, "old.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := Collect(context.Background(), filepath.Join(dir, "."))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, file := range changes.Files {
+		got[file.Path] = file.Status
+	}
+	want := map[string]string{"main.go": "modified", "new.go": "added", "old.go": "deleted"}
+	if len(got) != len(want) {
+		t.Fatalf("Files = %v, want %v", got, want)
+	}
+	for path, status := range want {
+		if got[path] != status {
+			t.Errorf("%s: status %q, want %q", path, got[path], status)
+		}
+	}
+}
+
+func TestParseStatus_Rename(t *testing.T) {
+	files := parseStatus("R  new.go\x00old.go\x00?? notes.md\x00")
+	if len(files) != 2 || files[0].Status != "renamed" || files[0].OldPath != "old.go" || files[1].Path != "notes.md" {
+		t.Errorf("Unexpected files %+v", files)
+	}
+}
+
+func TestFlow_Open(t *testing.T) {
+	dir := newRepo(t)
+	writeFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/pulls" || r.Header.Get("Authorization") != "Bearer gh-token" {
+			t.Errorf("Unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number":42,"html_url":"https://github.com/acme/widgets/pull/42","title":"Add main function","body":"The binary needs an entry point."}`))
+	}))
+	defer server.Close()
+
+	executor := &fakeExecutor{response: "```\nAdd main function\n\nThe binary needs an entry point.\n```"}
+	flow := &Flow{
+		Executor: executor,
+		Token:    "gh-token",
+		Owner:    "acme",
+		Repo:     "widgets",
+		Branch:   "claude/main-func",
+		APIURL:   server.URL,
+	}
+
+	changes, err := Collect(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	pr, err := flow.Open(context.Background(), changes)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if pr.Number != 42 || pr.URL != "https://github.com/acme/widgets/pull/42" || pr.Base != "main" || pr.Commit == "" {
+		t.Errorf("Unexpected pull request %+v", pr)
+	}
+	if request["head"] != "claude/main-func" || request["base"] != "main" || request["title"] != "Add main function" {
+		t.Errorf("Unexpected API request %v", request)
+	}
+	if len(executor.prompts) != 1 || !strings.Contains(executor.prompts[0], "+func main() {}") {
+		t.Errorf("Expected the diff in the prompt, got %q", executor.prompts)
+	}
+
+	message, err := git(context.Background(), dir, "log", "-1", "--format=%B")
+	if err != nil || message != "Add main function\n\nThe binary needs an entry point." {
+		t.Errorf("Unexpected commit message %q: %v", message, err)
+	}
+	if remote, err := git(context.Background(), dir, "ls-remote", "origin", "claude/main-func"); err != nil || !strings.HasPrefix(remote, pr.Commit) {
+		t.Errorf("Branch was not pushed: %q %v", remote, err)
+	}
+}
+
+func TestFlow_OpenErrors(t *testing.T) {
+	flow := &Flow{Token: "t"}
+	if _, err := flow.Open(context.Background(), &ChangeSet{}); err == nil {
+		t.Error("Expected an error for an empty change set")
+	}
+
+	dir := newRepo(t)
+	writeFile(t, dir, "main.go", "package main // changed\n")
+	changes, err := Collect(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	// The local remote is not a GitHub URL
+	if _, err := flow.Open(context.Background(), changes); err == nil || !strings.Contains(err.Error(), "cannot infer") {
+		t.Errorf("Expected a repository inference error, got %v", err)
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote      string
+		owner, repo string
+		ok          bool
+	}{
+		{"git@github.com:acme/widgets.git", "acme", "widgets", true},
+		{"https://github.com/acme/widgets", "acme", "widgets", true},
+		{"ssh://git@github.com/acme/widgets.git", "acme", "widgets", true},
+		{"/tmp/remote.git", "", "", false},
+		{"https://github.com/acme", "", "", false},
+	}
+	for _, tt := range tests {
+		owner, repo, ok := parseRemote(tt.remote)
+		if owner != tt.owner || repo != tt.repo || ok != tt.ok {
+			t.Errorf("parseRemote(%q) = %q, %q, %v", tt.remote, owner, repo, ok)
+		}
+	}
+}
+
+func TestFlow_MessageWithoutExecutor(t *testing.T) {
+	flow := &Flow{}
+	title, body, err := flow.message(context.Background(), &ChangeSet{Files: []FileChange{{Path: "a.go", Status: "added"}}}, "")
+	if err != nil || title != "Update a.go" || body != "- added a.go" {
+		t.Errorf("message() = %q, %q, %v", title, body, err)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Package golden locks down prompt and response handling with golden files: a
+received message stream is serialized to normalized JSON and compared with
+a file checked into testdata.
+
+Normalization makes the output stable across runs. Timestamps are replaced
+with "<time>", and IDs, such as message, session and tool use IDs, with
+numbered placeholders like "<id-1>" in order of first appearance, so the
+links between a tool call and its result are still checked. JSON carried in
+strings, such as the stream-json lines of QueryStream chunks, is normalized
+too.
+
+# Asserting
+
+	messages, err := claude.QueryMessages(ctx, "List the TODOs", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.Assert(t, "testdata/todos.golden.json", golden.Collect(messages))
+
+Streams are collected with CollectStream:
+
+	stream, err := claude.QueryStream(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := golden.CollectStream(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.Assert(t, "testdata/stream.golden.json", chunks)
+
+# Updating
+
+Run the tests with UPDATE_GOLDEN=1 in the environment to write the golden
+files instead of comparing, then review the diff before committing:
+
+	UPDATE_GOLDEN=1 go test ./...
+
+Combined with the client's TestScript or a recorder cassette, golden tests
+run without the CLI.
+*/
+package golden
@@ -0,0 +1,303 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// UpdateEnv is the environment variable that makes Assert write golden files
+// instead of comparing with them.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// Update reports whether Assert writes golden files. It defaults to whether
+// UpdateEnv is set.
+var Update = os.Getenv(UpdateEnv) != ""
+
+// DefaultIDFields are the fields whose values are replaced with ID placeholders.
+var DefaultIDFields = []string{
+	"id", "uuid", "parentUuid", "session_id", "sessionId", "tool_use_id",
+	"tool_call_id", "message_id", "request_id", "leafUuid",
+}
+
+// DefaultTimeFields are the fields whose values are replaced with "<time>".
+var DefaultTimeFields = []string{
+	"timestamp", "created_at", "updated_at", "time", "createdAt", "updatedAt",
+	"duration_ms", "duration_api_ms",
+}
+
+var (
+	uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	timePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+)
+
+// Option customizes normalization.
+type Option func(*normalizer)
+
+// IgnoreFields removes the named fields, such as costs that vary between
+// runs, from the output.
+func IgnoreFields(names ...string) Option {
+	return func(n *normalizer) {
+		for _, name := range names {
+			n.ignore[name] = true
+		}
+	}
+}
+
+// IDFields adds fields whose values are replaced with ID placeholders.
+func IDFields(names ...string) Option {
+	return func(n *normalizer) {
+		for _, name := range names {
+			n.ids[name] = true
+		}
+	}
+}
+
+// TimeFields adds fields whose values are replaced with "<time>".
+func TimeFields(names ...string) Option {
+	return func(n *normalizer) {
+		for _, name := range names {
+			n.times[name] = true
+		}
+	}
+}
+
+// normalizer rewrites decoded JSON into its stable form.
+type normalizer struct {
+	ids    map[string]bool
+	times  map[string]bool
+	ignore map[string]bool
+
+	// placeholders maps each ID seen to its placeholder
+	placeholders map[string]string
+}
+
+func newNormalizer(opts []Option) *normalizer {
+	n := &normalizer{
+		ids:          make(map[string]bool),
+		times:        make(map[string]bool),
+		ignore:       make(map[string]bool),
+		placeholders: make(map[string]string),
+	}
+	for _, name := range DefaultIDFields {
+		n.ids[name] = true
+	}
+	for _, name := range DefaultTimeFields {
+		n.times[name] = true
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Normalize serializes v, typically a slice of messages or stream chunks, to
+// indented JSON with timestamps and IDs replaced by placeholders.
+func Normalize(v any, opts ...Option) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("golden: failed to marshal value: %w", err)
+	}
+
+	var decoded any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("golden: failed to decode value: %w", err)
+	}
+
+	normalized, err := marshal(newNormalizer(opts).value("", decoded), "  ")
+	if err != nil {
+		return nil, fmt.Errorf("golden: failed to marshal normalized value: %w", err)
+	}
+	return normalized, nil
+}
+
+// marshal encodes a value with a trailing newline, without escaping the
+// placeholders' angle brackets, indenting when indent is set.
+func marshal(value any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// value returns the normalized form of a decoded value found under key.
+func (n *normalizer) value(key string, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		// Visit fields in output order so placeholders are numbered stably
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if n.ignore[k] {
+				delete(v, k)
+				continue
+			}
+			v[k] = n.value(k, v[k])
+		}
+		return v
+
+	case []any:
+		for i, item := range v {
+			v[i] = n.value(key, item)
+		}
+		return v
+
+	case string:
+		switch {
+		case v == "":
+			return v
+		case n.times[key]:
+			return "<time>"
+		case n.ids[key]:
+			return n.placeholder(v)
+		}
+		return n.text(v)
+
+	case json.Number:
+		if n.times[key] {
+			return "<time>"
+		}
+		return v
+	}
+	return value
+}
+
+// text normalizes a string value. Strings holding JSON, such as stream-json
+// lines, are normalized as JSON; other strings have UUIDs and timestamps
+// replaced.
+func (n *normalizer) text(s string) string {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		var decoded any
+		if err := decoder.Decode(&decoded); err == nil && !decoder.More() {
+			if data, err := marshal(n.value("", decoded), ""); err == nil {
+				// Keep the surrounding whitespace, such as a line's newline
+				start := strings.Index(s, trimmed)
+				return s[:start] + strings.TrimSuffix(string(data), "\n") + s[start+len(trimmed):]
+			}
+		}
+	}
+
+	s = timePattern.ReplaceAllString(s, "<time>")
+	return uuidPattern.ReplaceAllStringFunc(s, n.placeholder)
+}
+
+// placeholder returns the stable placeholder for an ID.
+func (n *normalizer) placeholder(id string) string {
+	if placeholder, ok := n.placeholders[id]; ok {
+		return placeholder
+	}
+	placeholder := fmt.Sprintf("<id-%d>", len(n.placeholders)+1)
+	n.placeholders[id] = placeholder
+	return placeholder
+}
+
+// Assert compares the normalized form of v with the golden file at path,
+// failing t with the first difference. When Update is set, the file is
+// written instead.
+func Assert(t testing.TB, path string, v any, opts ...Option) {
+	t.Helper()
+
+	got, err := Normalize(v, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden: failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil { // #nosec G306 - golden files are checked in
+			t.Fatalf("golden: failed to write %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path) // #nosec G304 - path is chosen by the test
+	if os.IsNotExist(err) {
+		t.Fatalf("golden: %s does not exist; run with %s=1 to create it", path, UpdateEnv)
+	}
+	if err != nil {
+		t.Fatalf("golden: failed to read %s: %v", path, err)
+	}
+
+	if diff := Diff(string(want), string(got)); diff != "" {
+		t.Errorf("golden: output does not match %s (run with %s=1 to update):\n%s", path, UpdateEnv, diff)
+	}
+}
+
+// Diff describes the first line where got differs from want, with a line of
+// context before it, or returns "" if they are equal.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	line := 0
+	for line < len(wantLines) && line < len(gotLines) && wantLines[line] == gotLines[line] {
+		line++
+	}
+
+	var b strings.Builder
+	if line > 0 {
+		fmt.Fprintf(&b, "  %d: %s\n", line, wantLines[line-1])
+	}
+	if line < len(wantLines) {
+		fmt.Fprintf(&b, "- %d: %s\n", line+1, wantLines[line])
+	}
+	if line < len(gotLines) {
+		fmt.Fprintf(&b, "+ %d: %s\n", line+1, gotLines[line])
+	}
+	return b.String()
+}
+
+// Collect drains a message channel, such as the one returned by
+// QueryMessages, skipping nil messages.
+func Collect(messages <-chan *types.Message) []types.Message {
+	var collected []types.Message
+	for message := range messages {
+		if message != nil {
+			collected = append(collected, *message)
+		}
+	}
+	return collected
+}
+
+// CollectStream receives every chunk of a stream up to and including the
+// final one, then closes the stream.
+func CollectStream(stream types.QueryStream) ([]*types.StreamChunk, error) {
+	defer stream.Close()
+
+	var chunks []*types.StreamChunk
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return chunks, err
+		}
+		chunks = append(chunks, chunk)
+		if chunk.Done {
+			return chunks, nil
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package golden
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestNormalize(t *testing.T) {
+	sessionID := "3f2b8c1e-9d4a-4e6b-8f7c-1a2b3c4d5e6f"
+	messages := []types.Message{
+		{
+			ID:        "msg_01",
+			Role:      types.RoleAssistant,
+			Content:   "Checking session " + sessionID + " at 2025-06-01T10:00:00Z",
+			ToolCalls: []types.ToolCall{{ID: "toolu_01", Type: "function", Function: types.FunctionCall{Name: "Read"}}},
+			Timestamp: time.Now(),
+			Metadata:  map[string]any{"cost_usd": 0.0123},
+		},
+		{
+			Role:       types.RoleTool,
+			Content:    `{"type":"user","session_id":"` + sessionID + `"}` + "\n",
+			ToolCallID: "toolu_01",
+			Timestamp:  time.Now(),
+		},
+	}
+
+	data, err := Normalize(messages, IgnoreFields("cost_usd"))
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	got := string(data)
+
+	for _, want := range []string{
+		`"Checking session <id-1> at <time>"`, // UUIDs and times in text
+		`"id": "<id-2>"`,                      // The message ID
+		`"tool_call_id": "<id-3>"`,            // Same ID as the tool call
+		`"timestamp": "<time>"`,
+		`{\"session_id\":\"<id-1>\",\"type\":\"user\"}\n`, // JSON in strings
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %s in:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{sessionID, "msg_01", "toolu_01", "cost_usd"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("Expected %s to be normalized away in:\n%s", unwanted, got)
+		}
+	}
+
+	again, err := Normalize(messages, IgnoreFields("cost_usd"))
+	if err != nil || string(again) != got {
+		t.Error("Normalize should be deterministic")
+	}
+}
+
+func TestNormalizeOptions(t *testing.T) {
+	data, err := Normalize(map[string]any{"run": "r-42", "started": "yesterday"}, IDFields("run"), TimeFields("started"))
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if want := "{\n  \"run\": \"<id-1>\",\n  \"started\": \"<time>\"\n}\n"; string(data) != want {
+		t.Errorf("Normalize = %q, want %q", data, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if diff := Diff("a\nb\n", "a\nb\n"); diff != "" {
+		t.Errorf("Expected no diff, got %q", diff)
+	}
+	if diff, want := Diff("a\nb\nc\n", "a\nx\nc\n"), "  1: a\n- 2: b\n+ 2: x\n"; diff != want {
+		t.Errorf("Diff = %q, want %q", diff, want)
+	}
+}
+
+// recordingTB captures the errors reported to it.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "messages.golden.json")
+	messages := []types.Message{{ID: "msg_1", Role: types.RoleUser, Content: "hi"}}
+
+	defer func(update bool) { Update = update }(Update)
+	Update = true
+	Assert(t, path, messages)
+	Update = false
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected the golden file to be written: %v", err)
+	}
+
+	// IDs differ between runs but normalize the same
+	Assert(t, path, []types.Message{{ID: "msg_2", Role: types.RoleUser, Content: "hi"}})
+
+	recorder := &recordingTB{TB: t}
+	Assert(recorder, path, []types.Message{{Role: types.RoleUser, Content: "hello"}})
+	if len(recorder.errors) != 1 || !strings.Contains(recorder.errors[0], `+ 3:     "content": "hello",`) {
+		t.Errorf("Expected a mismatch with the differing line, got %v", recorder.errors)
+	}
+}
+
+func TestAssertStream(t *testing.T) {
+	claude, err := client.NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{
+		TestMode:         true,
+		WorkingDirectory: t.TempDir(),
+		TestScript: &types.TestScript{Responses: []types.TestResponse{{
+			Text:     "It prints a greeting.",
+			ToolUses: []types.TestToolUse{{Name: "Read", Input: map[string]any{"file_path": "main.go"}, Result: "package main"}},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer claude.Close()
+
+	stream, err := claude.QueryStream(context.Background(), &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "What does main.go do?"}},
+	})
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	chunks, err := CollectStream(stream)
+	if err != nil {
+		t.Fatalf("CollectStream failed: %v", err)
+	}
+
+	Assert(t, filepath.Join("testdata", "stream.golden.json"), chunks)
+}
@@ -0,0 +1,379 @@
+// Protocol definitions for remote access to the Claude Code Go SDK.
+//
+// Regenerate the Go code with:
+//
+//	protoc --go_out=paths=source_relative:../claudecodepb \
+//		--go-grpc_out=paths=source_relative:../claudecodepb claudecode.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: claudecode.proto
+
+package claudecodepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ClaudeCode_Query_FullMethodName         = "/claudecode.v1.ClaudeCode/Query"
+	ClaudeCode_QueryStream_FullMethodName   = "/claudecode.v1.ClaudeCode/QueryStream"
+	ClaudeCode_Converse_FullMethodName      = "/claudecode.v1.ClaudeCode/Converse"
+	ClaudeCode_CreateSession_FullMethodName = "/claudecode.v1.ClaudeCode/CreateSession"
+	ClaudeCode_ListSessions_FullMethodName  = "/claudecode.v1.ClaudeCode/ListSessions"
+	ClaudeCode_CloseSession_FullMethodName  = "/claudecode.v1.ClaudeCode/CloseSession"
+)
+
+// ClaudeCodeClient is the client API for ClaudeCode service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ClaudeCodeClient interface {
+	// Query runs a request and returns the complete response.
+	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// QueryStream runs a request and streams response chunks.
+	QueryStream(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (ClaudeCode_QueryStreamClient, error)
+	// Converse runs an interactive session. The first message selects the
+	// session; every message with a query starts a turn whose chunks are
+	// streamed back, followed by a turn_complete event. Sending interrupt
+	// cancels the turn in progress.
+	Converse(ctx context.Context, opts ...grpc.CallOption) (ClaudeCode_ConverseClient, error)
+	// CreateSession creates a conversation session.
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error)
+	// ListSessions lists active sessions.
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// CloseSession closes a session.
+	CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*CloseSessionResponse, error)
+}
+
+type claudeCodeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClaudeCodeClient(cc grpc.ClientConnInterface) ClaudeCodeClient {
+	return &claudeCodeClient{cc}
+}
+
+func (c *claudeCodeClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, ClaudeCode_Query_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *claudeCodeClient) QueryStream(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (ClaudeCode_QueryStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ClaudeCode_ServiceDesc.Streams[0], ClaudeCode_QueryStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &claudeCodeQueryStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ClaudeCode_QueryStreamClient interface {
+	Recv() (*StreamChunk, error)
+	grpc.ClientStream
+}
+
+type claudeCodeQueryStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *claudeCodeQueryStreamClient) Recv() (*StreamChunk, error) {
+	m := new(StreamChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *claudeCodeClient) Converse(ctx context.Context, opts ...grpc.CallOption) (ClaudeCode_ConverseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ClaudeCode_ServiceDesc.Streams[1], ClaudeCode_Converse_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &claudeCodeConverseClient{stream}
+	return x, nil
+}
+
+type ClaudeCode_ConverseClient interface {
+	Send(*ConverseRequest) error
+	Recv() (*ConverseEvent, error)
+	grpc.ClientStream
+}
+
+type claudeCodeConverseClient struct {
+	grpc.ClientStream
+}
+
+func (x *claudeCodeConverseClient) Send(m *ConverseRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *claudeCodeConverseClient) Recv() (*ConverseEvent, error) {
+	m := new(ConverseEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *claudeCodeClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error) {
+	out := new(CreateSessionResponse)
+	err := c.cc.Invoke(ctx, ClaudeCode_CreateSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *claudeCodeClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, ClaudeCode_ListSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *claudeCodeClient) CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*CloseSessionResponse, error) {
+	out := new(CloseSessionResponse)
+	err := c.cc.Invoke(ctx, ClaudeCode_CloseSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClaudeCodeServer is the server API for ClaudeCode service.
+// All implementations must embed UnimplementedClaudeCodeServer
+// for forward compatibility
+type ClaudeCodeServer interface {
+	// Query runs a request and returns the complete response.
+	Query(context.Context, *QueryRequest) (*QueryResponse, error)
+	// QueryStream runs a request and streams response chunks.
+	QueryStream(*QueryRequest, ClaudeCode_QueryStreamServer) error
+	// Converse runs an interactive session. The first message selects the
+	// session; every message with a query starts a turn whose chunks are
+	// streamed back, followed by a turn_complete event. Sending interrupt
+	// cancels the turn in progress.
+	Converse(ClaudeCode_ConverseServer) error
+	// CreateSession creates a conversation session.
+	CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error)
+	// ListSessions lists active sessions.
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// CloseSession closes a session.
+	CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error)
+	mustEmbedUnimplementedClaudeCodeServer()
+}
+
+// UnimplementedClaudeCodeServer must be embedded to have forward compatible implementations.
+type UnimplementedClaudeCodeServer struct {
+}
+
+func (UnimplementedClaudeCodeServer) Query(context.Context, *QueryRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+func (UnimplementedClaudeCodeServer) QueryStream(*QueryRequest, ClaudeCode_QueryStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method QueryStream not implemented")
+}
+func (UnimplementedClaudeCodeServer) Converse(ClaudeCode_ConverseServer) error {
+	return status.Errorf(codes.Unimplemented, "method Converse not implemented")
+}
+func (UnimplementedClaudeCodeServer) CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedClaudeCodeServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedClaudeCodeServer) CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseSession not implemented")
+}
+func (UnimplementedClaudeCodeServer) mustEmbedUnimplementedClaudeCodeServer() {}
+
+// UnsafeClaudeCodeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ClaudeCodeServer will
+// result in compilation errors.
+type UnsafeClaudeCodeServer interface {
+	mustEmbedUnimplementedClaudeCodeServer()
+}
+
+func RegisterClaudeCodeServer(s grpc.ServiceRegistrar, srv ClaudeCodeServer) {
+	s.RegisterService(&ClaudeCode_ServiceDesc, srv)
+}
+
+func _ClaudeCode_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClaudeCodeServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClaudeCode_Query_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClaudeCodeServer).Query(ctx, req.(*QueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClaudeCode_QueryStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClaudeCodeServer).QueryStream(m, &claudeCodeQueryStreamServer{stream})
+}
+
+type ClaudeCode_QueryStreamServer interface {
+	Send(*StreamChunk) error
+	grpc.ServerStream
+}
+
+type claudeCodeQueryStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *claudeCodeQueryStreamServer) Send(m *StreamChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ClaudeCode_Converse_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ClaudeCodeServer).Converse(&claudeCodeConverseServer{stream})
+}
+
+type ClaudeCode_ConverseServer interface {
+	Send(*ConverseEvent) error
+	Recv() (*ConverseRequest, error)
+	grpc.ServerStream
+}
+
+type claudeCodeConverseServer struct {
+	grpc.ServerStream
+}
+
+func (x *claudeCodeConverseServer) Send(m *ConverseEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *claudeCodeConverseServer) Recv() (*ConverseRequest, error) {
+	m := new(ConverseRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _ClaudeCode_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClaudeCodeServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClaudeCode_CreateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClaudeCodeServer).CreateSession(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClaudeCode_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClaudeCodeServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClaudeCode_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClaudeCodeServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClaudeCode_CloseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClaudeCodeServer).CloseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClaudeCode_CloseSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClaudeCodeServer).CloseSession(ctx, req.(*CloseSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ClaudeCode_ServiceDesc is the grpc.ServiceDesc for ClaudeCode service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ClaudeCode_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "claudecode.v1.ClaudeCode",
+	HandlerType: (*ClaudeCodeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Query",
+			Handler:    _ClaudeCode_Query_Handler,
+		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _ClaudeCode_CreateSession_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _ClaudeCode_ListSessions_Handler,
+		},
+		{
+			MethodName: "CloseSession",
+			Handler:    _ClaudeCode_CloseSession_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QueryStream",
+			Handler:       _ClaudeCode_QueryStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Converse",
+			Handler:       _ClaudeCode_Converse_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "claudecode.proto",
+}
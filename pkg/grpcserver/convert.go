@@ -0,0 +1,205 @@
+package grpcserver
+
+import (
+	"encoding/json"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/grpcserver/claudecodepb"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// RequestFromProto converts a protobuf query request to the SDK type.
+func RequestFromProto(req *claudecodepb.QueryRequest) (*types.QueryRequest, error) {
+	request := &types.QueryRequest{
+		Model:         req.GetModel(),
+		MaxTokens:     int(req.GetMaxTokens()),
+		Temperature:   req.GetTemperature(),
+		System:        req.GetSystem(),
+		StopSequences: req.GetStopSequences(),
+	}
+
+	for _, msg := range req.GetMessages() {
+		message := types.Message{
+			ID:         msg.GetId(),
+			Role:       types.Role(msg.GetRole()),
+			Content:    msg.GetContent(),
+			ToolCallID: msg.GetToolCallId(),
+		}
+		for _, call := range msg.GetToolCalls() {
+			message.ToolCalls = append(message.ToolCalls, types.ToolCall{
+				ID:   call.GetId(),
+				Type: call.GetType(),
+				Function: types.FunctionCall{
+					Name:      call.GetName(),
+					Arguments: call.GetArguments(),
+				},
+			})
+		}
+		request.Messages = append(request.Messages, message)
+	}
+
+	for _, tool := range req.GetTools() {
+		converted := types.Tool{Name: tool.GetName(), Description: tool.GetDescription()}
+		if schema := tool.GetInputSchemaJson(); schema != "" {
+			if err := json.Unmarshal([]byte(schema), &converted.InputSchema); err != nil {
+				return nil, err
+			}
+		}
+		request.Tools = append(request.Tools, converted)
+	}
+
+	return request, nil
+}
+
+// RequestToProto converts an SDK query request to protobuf.
+func RequestToProto(request *types.QueryRequest) (*claudecodepb.QueryRequest, error) {
+	req := &claudecodepb.QueryRequest{
+		Model:         request.Model,
+		MaxTokens:     int32(request.MaxTokens),
+		Temperature:   request.Temperature,
+		System:        request.System,
+		StopSequences: request.StopSequences,
+	}
+
+	for _, msg := range request.Messages {
+		message := &claudecodepb.Message{
+			Id:         msg.ID,
+			Role:       string(msg.Role),
+			Content:    msg.Content,
+			ToolCallId: msg.ToolCallID,
+		}
+		for _, call := range msg.ToolCalls {
+			message.ToolCalls = append(message.ToolCalls, &claudecodepb.ToolCall{
+				Id:        call.ID,
+				Type:      call.Type,
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			})
+		}
+		req.Messages = append(req.Messages, message)
+	}
+
+	for _, tool := range request.Tools {
+		schema, err := json.Marshal(tool.InputSchema)
+		if err != nil {
+			return nil, err
+		}
+		req.Tools = append(req.Tools, &claudecodepb.Tool{
+			Name:            tool.Name,
+			Description:     tool.Description,
+			InputSchemaJson: string(schema),
+		})
+	}
+
+	return req, nil
+}
+
+// ResponseToProto converts an SDK query response to protobuf.
+func ResponseToProto(response *types.QueryResponse) *claudecodepb.QueryResponse {
+	resp := &claudecodepb.QueryResponse{
+		Id:           response.ID,
+		Type:         response.Type,
+		Role:         string(response.Role),
+		Content:      contentToProto(response.Content),
+		Model:        response.Model,
+		StopReason:   response.StopReason,
+		StopSequence: response.StopSequence,
+	}
+	if response.Usage != nil {
+		resp.Usage = &claudecodepb.Usage{
+			InputTokens:  int32(response.Usage.InputTokens),
+			OutputTokens: int32(response.Usage.OutputTokens),
+			TotalTokens:  int32(response.Usage.TotalTokens),
+		}
+	}
+	return resp
+}
+
+// ResponseFromProto converts a protobuf query response to the SDK type.
+func ResponseFromProto(resp *claudecodepb.QueryResponse) *types.QueryResponse {
+	response := &types.QueryResponse{
+		ID:           resp.GetId(),
+		Type:         resp.GetType(),
+		Role:         types.Role(resp.GetRole()),
+		Content:      contentFromProto(resp.GetContent()),
+		Model:        resp.GetModel(),
+		StopReason:   resp.GetStopReason(),
+		StopSequence: resp.GetStopSequence(),
+	}
+	if usage := resp.GetUsage(); usage != nil {
+		response.Usage = &types.TokenUsage{
+			InputTokens:  int(usage.GetInputTokens()),
+			OutputTokens: int(usage.GetOutputTokens()),
+			TotalTokens:  int(usage.GetTotalTokens()),
+		}
+	}
+	return response
+}
+
+// ChunkToProto converts an SDK stream chunk to protobuf.
+func ChunkToProto(chunk *types.StreamChunk) *claudecodepb.StreamChunk {
+	pbChunk := &claudecodepb.StreamChunk{
+		Type:    string(chunk.Type),
+		Content: chunk.Content,
+		Done:    chunk.Done,
+	}
+	if boundary := chunk.CompactBoundary; boundary != nil {
+		pbChunk.CompactBoundary = &claudecodepb.CompactBoundary{
+			SessionId: boundary.SessionID,
+			Trigger:   string(boundary.Trigger),
+			PreTokens: int32(boundary.PreTokens),
+		}
+	}
+	return pbChunk
+}
+
+// contentToProto converts content blocks, encoding tool input as JSON.
+func contentToProto(blocks []types.ContentBlock) []*claudecodepb.ContentBlock {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	converted := make([]*claudecodepb.ContentBlock, 0, len(blocks))
+	for _, block := range blocks {
+		pbBlock := &claudecodepb.ContentBlock{
+			Type:      block.Type,
+			Text:      block.Text,
+			Id:        block.ID,
+			Name:      block.Name,
+			ToolUseId: block.ToolUseID,
+			Content:   contentToProto(block.Content),
+			IsError:   block.IsError,
+		}
+		if block.Input != nil {
+			if input, err := json.Marshal(block.Input); err == nil {
+				pbBlock.InputJson = string(input)
+			}
+		}
+		converted = append(converted, pbBlock)
+	}
+	return converted
+}
+
+// contentFromProto converts protobuf content blocks back to SDK blocks.
+func contentFromProto(blocks []*claudecodepb.ContentBlock) []types.ContentBlock {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	converted := make([]types.ContentBlock, 0, len(blocks))
+	for _, pbBlock := range blocks {
+		block := types.ContentBlock{
+			Type:      pbBlock.GetType(),
+			Text:      pbBlock.GetText(),
+			ID:        pbBlock.GetId(),
+			Name:      pbBlock.GetName(),
+			ToolUseID: pbBlock.GetToolUseId(),
+			Content:   contentFromProto(pbBlock.GetContent()),
+			IsError:   pbBlock.GetIsError(),
+		}
+		if input := pbBlock.GetInputJson(); input != "" {
+			_ = json.Unmarshal([]byte(input), &block.Input) // Ignore error, input stays nil
+		}
+		converted = append(converted, block)
+	}
+	return converted
+}
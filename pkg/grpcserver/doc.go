@@ -0,0 +1,41 @@
+/*
+Package grpcserver exposes the Claude Code Go SDK as a gRPC service so
+services written in any language can query Claude with strongly typed
+messages.
+
+The service is defined in proto/claudecode.proto, and the generated Go code
+lives in the claudecodepb package. Besides unary and server-streaming queries,
+the Converse RPC runs an interactive session over a bidirectional stream: the
+first message selects (or creates) a session, each query message starts a
+turn whose chunks are streamed back as events, and a message with interrupt
+set cancels the turn in progress.
+
+# Basic Usage
+
+	claudeClient, err := client.NewClaudeCodeClient(ctx, types.NewClaudeCodeConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer claudeClient.Close()
+
+	unary, stream := grpcserver.APIKeyInterceptors(os.Getenv("GATEWAY_API_KEY"))
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(unary), grpc.ChainStreamInterceptor(stream))
+	grpcserver.New(server.NewClientBackend(claudeClient)).Register(s)
+
+	lis, err := net.Listen("tcp", ":9090")
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Fatal(s.Serve(lis))
+
+# Generating Clients
+
+Clients for other languages are generated from proto/claudecode.proto with
+the usual protoc plugins. The Go code is regenerated from this directory with:
+
+	protoc -I proto \
+		--go_out=. --go_opt=module=github.com/jonwraymond/go-claude-code-sdk/pkg/grpcserver \
+		--go-grpc_out=. --go-grpc_opt=module=github.com/jonwraymond/go-claude-code-sdk/pkg/grpcserver \
+		claudecode.proto
+*/
+package grpcserver
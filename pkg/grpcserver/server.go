@@ -0,0 +1,331 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/grpcserver/claudecodepb"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/server"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// APIKeyMetadata is the metadata key carrying the API key. An
+// "authorization: Bearer <key>" entry is also accepted.
+const APIKeyMetadata = "x-api-key"
+
+// Server implements the ClaudeCode gRPC service over a server.Backend.
+type Server struct {
+	claudecodepb.UnimplementedClaudeCodeServer
+
+	backend server.Backend
+}
+
+// New creates a gRPC service for backend. Use server.NewClientBackend to
+// serve a ClaudeCodeClient.
+func New(backend server.Backend) *Server {
+	return &Server{backend: backend}
+}
+
+// Register registers the service with a gRPC server.
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	claudecodepb.RegisterClaudeCodeServer(registrar, s)
+}
+
+// Query runs a request and returns the complete response.
+func (s *Server) Query(ctx context.Context, req *claudecodepb.QueryRequest) (*claudecodepb.QueryResponse, error) {
+	conv, request, err := s.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := conv.Query(ctx, request)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return ResponseToProto(response), nil
+}
+
+// QueryStream runs a request and streams response chunks.
+func (s *Server) QueryStream(req *claudecodepb.QueryRequest, stream claudecodepb.ClaudeCode_QueryStreamServer) error {
+	conv, request, err := s.prepare(req)
+	if err != nil {
+		return err
+	}
+
+	return streamTurn(stream.Context(), conv, request, func(chunk *types.StreamChunk) error {
+		return stream.Send(ChunkToProto(chunk))
+	})
+}
+
+// Converse runs an interactive session over a bidirectional stream.
+func (s *Server) Converse(stream claudecodepb.ClaudeCode_ConverseServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	sessionID := first.GetSessionId()
+	if sessionID == "" {
+		if sessionID, err = s.backend.CreateSession(stream.Context(), ""); err != nil {
+			return toStatus(err)
+		}
+	}
+	conv, err := s.backend.Session(sessionID)
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	// gRPC streams do not support concurrent sends
+	var sendMu sync.Mutex
+	send := func(event *claudecodepb.ConverseEvent) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		event.SessionId = sessionID
+		return stream.Send(event)
+	}
+
+	if err := send(&claudecodepb.ConverseEvent{}); err != nil {
+		return err
+	}
+
+	// Receive in the background so interrupts arrive while a turn streams
+	incoming := make(chan *claudecodepb.ConverseRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		defer close(incoming)
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case incoming <- msg:
+			case <-stream.Context().Done():
+				recvErr <- stream.Context().Err()
+				return
+			}
+		}
+	}()
+
+	var (
+		cancelTurn context.CancelFunc
+		turnDone   chan struct{}
+	)
+
+	handle := func(msg *claudecodepb.ConverseRequest) error {
+		if msg.GetInterrupt() && cancelTurn != nil {
+			cancelTurn()
+		}
+		if msg.GetQuery() == nil {
+			return nil
+		}
+
+		if turnDone != nil {
+			select {
+			case <-turnDone:
+			default:
+				return send(&claudecodepb.ConverseEvent{Error: "a turn is already in progress"})
+			}
+		}
+
+		request, err := RequestFromProto(msg.GetQuery())
+		if err != nil {
+			return send(&claudecodepb.ConverseEvent{Error: "invalid request: " + err.Error(), TurnComplete: true})
+		}
+
+		ctx, cancel := context.WithCancel(stream.Context())
+		done := make(chan struct{})
+		cancelTurn, turnDone = cancel, done
+
+		go func() {
+			defer close(done)
+			defer cancel()
+
+			err := streamTurn(ctx, conv, request, func(chunk *types.StreamChunk) error {
+				return send(&claudecodepb.ConverseEvent{Chunk: ChunkToProto(chunk)})
+			})
+
+			event := &claudecodepb.ConverseEvent{TurnComplete: true}
+			if ctx.Err() != nil && stream.Context().Err() == nil {
+				event.Interrupted = true
+			} else if err != nil {
+				event.Error = err.Error()
+			}
+			_ = send(event) // Ignore error, the client may have gone away
+		}()
+		return nil
+	}
+
+	if err := handle(first); err != nil {
+		return err
+	}
+
+	for msg := range incoming {
+		if err := handle(msg); err != nil {
+			return err
+		}
+	}
+
+	// Let the last turn finish before closing the stream
+	if turnDone != nil {
+		<-turnDone
+	}
+
+	if err := <-recvErr; err != io.EOF {
+		if cancelTurn != nil {
+			cancelTurn()
+		}
+		return err
+	}
+	return nil
+}
+
+// CreateSession creates a conversation session.
+func (s *Server) CreateSession(ctx context.Context, req *claudecodepb.CreateSessionRequest) (*claudecodepb.CreateSessionResponse, error) {
+	sessionID, err := s.backend.CreateSession(ctx, req.GetSessionId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &claudecodepb.CreateSessionResponse{SessionId: sessionID}, nil
+}
+
+// ListSessions lists active sessions.
+func (s *Server) ListSessions(ctx context.Context, req *claudecodepb.ListSessionsRequest) (*claudecodepb.ListSessionsResponse, error) {
+	sessions := s.backend.ListSessions()
+	sort.Strings(sessions)
+	return &claudecodepb.ListSessionsResponse{SessionIds: sessions}, nil
+}
+
+// CloseSession closes a session.
+func (s *Server) CloseSession(ctx context.Context, req *claudecodepb.CloseSessionRequest) (*claudecodepb.CloseSessionResponse, error) {
+	if err := s.backend.CloseSession(req.GetSessionId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &claudecodepb.CloseSessionResponse{}, nil
+}
+
+// prepare converts the request and selects the conversation it runs in.
+func (s *Server) prepare(req *claudecodepb.QueryRequest) (server.Conversation, *types.QueryRequest, error) {
+	request, err := RequestFromProto(req)
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if req.GetSessionId() == "" {
+		return s.backend, request, nil
+	}
+
+	conv, err := s.backend.Session(req.GetSessionId())
+	if err != nil {
+		return nil, nil, status.Error(codes.NotFound, err.Error())
+	}
+	return conv, request, nil
+}
+
+// streamTurn streams one query, passing each chunk to send until the stream completes.
+func streamTurn(ctx context.Context, conv server.Conversation, request *types.QueryRequest, send func(*types.StreamChunk) error) error {
+	request.Stream = true
+
+	stream, err := conv.QueryStream(ctx, request)
+	if err != nil {
+		return toStatus(err)
+	}
+	defer stream.Close()
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF || (err == nil && (chunk == nil || chunk.Done)) {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return toStatus(err)
+		}
+		if err := send(chunk); err != nil {
+			return err
+		}
+	}
+}
+
+// toStatus converts an SDK error to a gRPC status error.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	code := codes.Internal
+	switch sdkerrors.GetCategory(err) {
+	case sdkerrors.CategoryValidation:
+		code = codes.InvalidArgument
+	case sdkerrors.CategoryAuth:
+		code = codes.Unauthenticated
+	case sdkerrors.CategoryNetwork, sdkerrors.CategoryAPI:
+		code = codes.Unavailable
+	case sdkerrors.CategoryConfiguration:
+		code = codes.FailedPrecondition
+	}
+	return status.Error(code, err.Error())
+}
+
+// APIKeyInterceptors returns unary and stream interceptors that require one of
+// the given API keys in the request metadata.
+func APIKeyInterceptors(keys ...string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	authorize := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		var key string
+		if values := md.Get(APIKeyMetadata); len(values) > 0 {
+			key = values[0]
+		} else if values := md.Get("authorization"); len(values) > 0 && strings.HasPrefix(values[0], "Bearer ") {
+			key = strings.TrimPrefix(values[0], "Bearer ")
+		}
+
+		authorized := 0
+		if key != "" {
+			for _, candidate := range keys {
+				authorized |= subtle.ConstantTimeCompare([]byte(key), []byte(candidate))
+			}
+		}
+		if authorized != 1 {
+			return status.Error(codes.Unauthenticated, "missing or invalid API key")
+		}
+		return nil
+	}
+
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authorize(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+	return unary, stream
+}
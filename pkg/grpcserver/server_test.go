@@ -0,0 +1,319 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/grpcserver/claudecodepb"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/server"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeStream returns chunks, then blocks until canceled if block is set.
+type fakeStream struct {
+	ctx    context.Context
+	chunks []string
+	block  bool
+}
+
+func (s *fakeStream) Recv() (*types.StreamChunk, error) {
+	if len(s.chunks) > 0 {
+		content := s.chunks[0]
+		s.chunks = s.chunks[1:]
+		return &types.StreamChunk{Type: types.ChunkTypeContent, Content: content}, nil
+	}
+	if s.block {
+		<-s.ctx.Done()
+		return nil, s.ctx.Err()
+	}
+	return &types.StreamChunk{Done: true}, nil
+}
+
+func (s *fakeStream) Close() error { return nil }
+
+// fakeConversation echoes prompts; the prompt "block" streams until canceled.
+type fakeConversation struct {
+	name string
+}
+
+func (c fakeConversation) Query(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	if len(request.Messages) == 0 {
+		return nil, sdkerrors.NewValidationError("messages", "", "non-empty", "messages are required")
+	}
+	return &types.QueryResponse{
+		Model:   request.Model,
+		Content: []types.ContentBlock{types.NewTextBlock(c.name + ": " + request.Messages[0].Content)},
+		Usage:   &types.TokenUsage{InputTokens: 3, OutputTokens: 5, TotalTokens: 8},
+	}, nil
+}
+
+func (c fakeConversation) QueryStream(ctx context.Context, request *types.QueryRequest) (types.QueryStream, error) {
+	prompt := request.Messages[0].Content
+	return &fakeStream{ctx: ctx, chunks: []string{c.name + " " + prompt}, block: prompt == "block"}, nil
+}
+
+type fakeBackend struct {
+	fakeConversation
+	sessions map[string]bool
+	mu       sync.Mutex
+}
+
+func (b *fakeBackend) CreateSession(ctx context.Context, sessionID string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sessionID == "" {
+		sessionID = "generated"
+	}
+	b.sessions[sessionID] = true
+	return sessionID, nil
+}
+
+func (b *fakeBackend) Session(sessionID string) (server.Conversation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.sessions[sessionID] {
+		return nil, errors.New("session not found")
+	}
+	return fakeConversation{name: sessionID}, nil
+}
+
+func (b *fakeBackend) ListSessions() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ids := make([]string, 0, len(b.sessions))
+	for id := range b.sessions {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (b *fakeBackend) CloseSession(sessionID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sessions, sessionID)
+	return nil
+}
+
+func newTestClient(t *testing.T, opts ...grpc.ServerOption) claudecodepb.ClaudeCodeClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(opts...)
+	New(&fakeBackend{fakeConversation: fakeConversation{name: "client"}, sessions: make(map[string]bool)}).Register(grpcServer)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return claudecodepb.NewClaudeCodeClient(conn)
+}
+
+func userQuery(prompt string) *claudecodepb.QueryRequest {
+	return &claudecodepb.QueryRequest{
+		Model:    "claude-sonnet-4",
+		Messages: []*claudecodepb.Message{{Role: "user", Content: prompt}},
+	}
+}
+
+func TestServer_Query(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	resp, err := client.Query(ctx, userQuery("hello"))
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	response := ResponseFromProto(resp)
+	if response.Content[0].Text != "client: hello" || response.Usage.TotalTokens != 8 {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	_, err = client.Query(ctx, &claudecodepb.QueryRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+
+	_, err = client.Query(ctx, &claudecodepb.QueryRequest{SessionId: "missing", Messages: userQuery("hi").Messages})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestServer_QueryStreamAndSessions(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateSession(ctx, &claudecodepb.CreateSessionRequest{SessionId: "abc"})
+	if err != nil || created.GetSessionId() != "abc" {
+		t.Fatalf("CreateSession failed: %v %v", created, err)
+	}
+
+	req := userQuery("stream me")
+	req.SessionId = "abc"
+	stream, err := client.QueryStream(ctx, req)
+	if err != nil {
+		t.Fatalf("QueryStream failed: %v", err)
+	}
+	var contents []string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		contents = append(contents, chunk.GetContent())
+	}
+	if len(contents) != 1 || contents[0] != "abc stream me" {
+		t.Errorf("Unexpected chunks: %v", contents)
+	}
+
+	list, err := client.ListSessions(ctx, &claudecodepb.ListSessionsRequest{})
+	if err != nil || len(list.GetSessionIds()) != 1 {
+		t.Errorf("Unexpected sessions: %v %v", list, err)
+	}
+	if _, err := client.CloseSession(ctx, &claudecodepb.CloseSessionRequest{SessionId: "abc"}); err != nil {
+		t.Errorf("CloseSession failed: %v", err)
+	}
+}
+
+func TestServer_Converse(t *testing.T) {
+	client := newTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.Converse(ctx)
+	if err != nil {
+		t.Fatalf("Converse failed: %v", err)
+	}
+
+	// The first message opens a new session and starts a turn
+	if err := stream.Send(&claudecodepb.ConverseRequest{Query: userQuery("hello")}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	opened := recvEvent(t, stream)
+	if opened.GetSessionId() != "generated" {
+		t.Fatalf("Expected session to be opened, got %v", opened)
+	}
+	if event := recvEvent(t, stream); event.GetChunk().GetContent() != "generated hello" {
+		t.Errorf("Unexpected chunk event: %v", event)
+	}
+	if event := recvEvent(t, stream); !event.GetTurnComplete() || event.GetInterrupted() {
+		t.Errorf("Expected completed turn, got %v", event)
+	}
+
+	// A blocking turn is interrupted on request
+	if err := stream.Send(&claudecodepb.ConverseRequest{Query: userQuery("block")}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if event := recvEvent(t, stream); event.GetChunk() == nil {
+		t.Fatalf("Expected chunk event, got %v", event)
+	}
+	if err := stream.Send(&claudecodepb.ConverseRequest{Interrupt: true}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if event := recvEvent(t, stream); !event.GetTurnComplete() || !event.GetInterrupted() {
+		t.Errorf("Expected interrupted turn, got %v", event)
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend failed: %v", err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Expected EOF after CloseSend, got %v", err)
+	}
+}
+
+func TestAPIKeyInterceptors(t *testing.T) {
+	unary, streamInterceptor := APIKeyInterceptors("secret")
+	client := newTestClient(t, grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(streamInterceptor))
+
+	if _, err := client.Query(context.Background(), userQuery("hi")); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	if _, err := client.Query(ctx, userQuery("hi")); err != nil {
+		t.Errorf("Expected bearer token to be accepted, got %v", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadata, "wrong")
+	stream, err := client.QueryStream(ctx, userQuery("hi"))
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated stream, got %v", err)
+	}
+}
+
+func TestRequestConversionRoundTrip(t *testing.T) {
+	request := &types.QueryRequest{
+		Model:  "claude-sonnet-4",
+		System: "be brief",
+		Messages: []types.Message{{
+			Role:    types.RoleAssistant,
+			Content: "calling",
+			ToolCalls: []types.ToolCall{{
+				ID: "call_1", Type: "function",
+				Function: types.FunctionCall{Name: "read", Arguments: `{"path":"a.go"}`},
+			}},
+		}},
+		Tools: []types.Tool{{
+			Name:        "read",
+			InputSchema: types.ToolInputSchema{Type: "object", Required: []string{"path"}},
+		}},
+	}
+
+	pb, err := RequestToProto(request)
+	if err != nil {
+		t.Fatalf("RequestToProto failed: %v", err)
+	}
+	converted, err := RequestFromProto(pb)
+	if err != nil {
+		t.Fatalf("RequestFromProto failed: %v", err)
+	}
+
+	if converted.Messages[0].ToolCalls[0].Function.Arguments != `{"path":"a.go"}` {
+		t.Errorf("Tool call not preserved: %+v", converted.Messages[0])
+	}
+	if converted.Tools[0].InputSchema.Type != "object" || converted.Tools[0].InputSchema.Required[0] != "path" {
+		t.Errorf("Tool schema not preserved: %+v", converted.Tools[0])
+	}
+	if converted.System != "be brief" || converted.Model != "claude-sonnet-4" {
+		t.Errorf("Request fields not preserved: %+v", converted)
+	}
+}
+
+func recvEvent(t *testing.T, stream claudecodepb.ClaudeCode_ConverseClient) *claudecodepb.ConverseEvent {
+	t.Helper()
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	return event
+}
@@ -0,0 +1,59 @@
+/*
+Package jobs runs Claude Code queries as fire-and-forget background jobs.
+
+A Queue persists each submitted job to a pluggable Store, executes it with a
+pool of workers, and retries failures according to a RetryPolicy. Callers can
+poll a job with Get, block on it with Wait, or Subscribe to every state
+change. With a durable store such as FileStore, jobs that were pending or
+running when the process stopped are picked up again on the next Start.
+
+# Basic Usage
+
+	claudeClient, err := client.NewClaudeCodeClient(ctx, types.NewClaudeCodeConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer claudeClient.Close()
+
+	store, err := jobs.NewFileStore("/var/lib/myapp/jobs")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	queue := jobs.New(claudeClient, jobs.WithStore(store), jobs.WithWorkers(4))
+	if err := queue.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+	defer queue.Stop()
+
+	job, err := queue.Submit(ctx, &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "Fix the failing tests"}},
+	}, jobs.WithMetadata(map[string]string{"ticket": "ENG-123"}))
+
+	// Later, possibly from another request handler
+	job, err = queue.Wait(ctx, job.ID)
+	fmt.Println(job.Status, job.Response)
+
+# Job Lifecycle
+
+Jobs move from pending to running and end as succeeded, failed or canceled.
+A retryable failure returns the job to pending until its next attempt. Custom
+stores (for example backed by a database) implement the Store interface.
+
+# Priorities and Preemption
+
+Ready jobs run highest priority first, in submission order within a
+priority. WithPreemption lets an urgent job interrupt the lowest-priority
+running job when every worker is busy; the interrupted job goes back to
+pending without using up an attempt:
+
+	queue := jobs.New(claudeClient, jobs.WithWorkers(4), jobs.WithPreemption(),
+		jobs.WithBudget(jobs.Budget{MaxCostUSD: 50}))
+
+	job, err := queue.Submit(ctx, request, jobs.WithPriority(10))
+
+WithBudget prices each completed job's usage. Once the budget is spent, jobs
+at or below Budget.ExemptPriority are preempted and fail, and Wait returns
+a *PreemptedError for them.
+*/
+package jobs
@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Status is the lifecycle state of a job.
+type Status string
+
+const (
+	// StatusPending jobs are waiting to run, either for the first time or for a retry
+	StatusPending Status = "pending"
+
+	// StatusRunning jobs are being executed
+	StatusRunning Status = "running"
+
+	// StatusSucceeded jobs completed with a response
+	StatusSucceeded Status = "succeeded"
+
+	// StatusFailed jobs exhausted their attempts or failed with a permanent error
+	StatusFailed Status = "failed"
+
+	// StatusCanceled jobs were canceled before completing
+	StatusCanceled Status = "canceled"
+)
+
+// Terminal reports whether the status is final.
+func (s Status) Terminal() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+// Job is a query submitted for asynchronous execution.
+type Job struct {
+	// ID uniquely identifies the job
+	ID string `json:"id"`
+
+	// Request is the query to run
+	Request *types.QueryRequest `json:"request"`
+
+	// Metadata holds caller-defined labels, such as a tenant or ticket ID
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Status is the current lifecycle state
+	Status Status `json:"status"`
+
+	// Attempts is the number of times the job has started
+	Attempts int `json:"attempts"`
+
+	// MaxAttempts is the attempt limit for this job
+	MaxAttempts int `json:"max_attempts"`
+
+	// Priority orders ready jobs, highest first
+	Priority int `json:"priority,omitempty"`
+
+	// Response is the query result once the job succeeds
+	Response *types.QueryResponse `json:"response,omitempty"`
+
+	// Error is the last error, for failed jobs and pending retries
+	Error string `json:"error,omitempty"`
+
+	// Preempted describes the job's latest preemption, if any
+	Preempted *PreemptedError `json:"preempted,omitempty"`
+
+	// CreatedAt is when the job was submitted
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the job last changed
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// StartedAt is when the latest attempt started
+	StartedAt time.Time `json:"started_at,omitempty"`
+
+	// FinishedAt is when the job reached a terminal status
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+
+	// NextAttemptAt is when a pending retry becomes eligible to run
+	NextAttemptAt time.Time `json:"next_attempt_at,omitempty"`
+}
+
+// Clone returns a deep copy of the job, so snapshots handed to callers are
+// never mutated by the queue.
+func (j *Job) Clone() *Job {
+	data, err := json.Marshal(j)
+	if err != nil {
+		copied := *j
+		return &copied
+	}
+
+	var clone Job
+	if err := json.Unmarshal(data, &clone); err != nil {
+		copied := *j
+		return &copied
+	}
+	return &clone
+}
+
+// preemptedError returns the job's PreemptedError if preemption failed it.
+func (j *Job) preemptedError() error {
+	if j.Status == StatusFailed && j.Preempted != nil {
+		return j.Preempted
+	}
+	return nil
+}
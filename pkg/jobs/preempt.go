@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"fmt"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/pricing"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Budget caps the cost of the jobs a queue runs. Once the jobs it has
+// completed cost MaxCostUSD, running jobs with a priority of ExemptPriority
+// or less are preempted and fail, as do such jobs when they come up to run.
+type Budget struct {
+	// MaxCostUSD is the spend at which the budget is exhausted
+	MaxCostUSD float64
+
+	// ExemptPriority is the priority jobs must exceed to keep running once
+	// the budget is exhausted
+	ExemptPriority int
+
+	// Pricing prices responses by model (nil uses pricing.Default)
+	Pricing pricing.Provider
+}
+
+// cost returns the cost of a response, or 0 if it has no usage or its model
+// has no price.
+func (b *Budget) cost(response *types.QueryResponse) float64 {
+	if response == nil || response.Usage == nil {
+		return 0
+	}
+	provider := b.Pricing
+	if provider == nil {
+		provider = pricing.Default()
+	}
+	cost, err := pricing.Cost(provider, response.Model, *response.Usage)
+	if err != nil {
+		return 0
+	}
+	return cost
+}
+
+// WithBudget caps the cost of the queue's jobs, preempting lower-priority
+// jobs once it is spent. Spend is counted from Start and is not persisted.
+func WithBudget(budget Budget) Option {
+	return func(q *Queue) {
+		q.budget = &budget
+	}
+}
+
+// WithPreemption lets a job that is ready to run interrupt the
+// lowest-priority running job when every worker is busy, if that job has a
+// lower priority. The preempted job returns to pending, without counting
+// the attempt, and runs again when a worker is free.
+func WithPreemption() Option {
+	return func(q *Queue) {
+		q.preemption = true
+	}
+}
+
+// WithPriority sets the job's priority. Ready jobs run in priority order,
+// highest first, and jobs submitted with the same priority run in order.
+func WithPriority(priority int) SubmitOption {
+	return func(j *Job) {
+		j.Priority = priority
+	}
+}
+
+// PreemptedError describes why a job was interrupted to make way for more
+// important work.
+type PreemptedError struct {
+	// JobID and Priority identify the preempted job
+	JobID    string `json:"job_id"`
+	Priority int    `json:"priority"`
+
+	// By is the higher-priority job given the worker, and ByPriority its
+	// priority; By is empty when the budget was exhausted
+	By         string `json:"by,omitempty"`
+	ByPriority int    `json:"by_priority,omitempty"`
+}
+
+func (e *PreemptedError) Error() string {
+	if e.By == "" {
+		return fmt.Sprintf("job %s (priority %d) preempted: cost budget exhausted", e.JobID, e.Priority)
+	}
+	return fmt.Sprintf("job %s (priority %d) preempted by job %s (priority %d)", e.JobID, e.Priority, e.By, e.ByPriority)
+}
+
+// preemptForLocked interrupts the lowest-priority running job if every
+// worker is busy and it ranks below the ready job. Among equals, the job
+// started last is chosen, losing the least work. The caller must hold q.mu.
+func (q *Queue) preemptForLocked(id string, priority int) {
+	if !q.preemption {
+		return
+	}
+
+	busy := 0
+	var victimID string
+	var victim *runningJob
+	for runningID, run := range q.running {
+		if run.preempted != nil {
+			continue
+		}
+		busy++
+		if run.priority >= priority {
+			continue
+		}
+		if victim == nil || run.priority < victim.priority ||
+			(run.priority == victim.priority && run.startedAt.After(victim.startedAt)) {
+			victimID, victim = runningID, run
+		}
+	}
+	if victim == nil || busy < q.workers {
+		return
+	}
+
+	victim.preempted = &PreemptedError{JobID: victimID, Priority: victim.priority, By: id, ByPriority: priority}
+	victim.cancel()
+}
+
+// budgetExhaustedLocked reports whether the budget is spent. The caller must
+// hold q.mu.
+func (q *Queue) budgetExhaustedLocked() bool {
+	return q.budget != nil && q.spent >= q.budget.MaxCostUSD
+}
+
+// chargeLocked adds the cost of a response to the spend and, once the
+// budget is exhausted, preempts the running jobs it no longer allows. The
+// caller must hold q.mu.
+func (q *Queue) chargeLocked(response *types.QueryResponse) {
+	if q.budget == nil {
+		return
+	}
+	q.spent += q.budget.cost(response)
+	if !q.budgetExhaustedLocked() {
+		return
+	}
+
+	for id, run := range q.running {
+		if run.preempted == nil && run.priority <= q.budget.ExemptPriority {
+			run.preempted = &PreemptedError{JobID: id, Priority: run.priority}
+			run.cancel()
+		}
+	}
+}
+
+// Spent returns the cost of the jobs completed since Start, as priced by the
+// budget. It is 0 without a budget.
+func (q *Queue) Spent() float64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.spent
+}
@@ -0,0 +1,167 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/pricing"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestQueue_PriorityOrder(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+	executor := &fakeExecutor{fn: func(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error) {
+		mu.Lock()
+		order = append(order, request.Messages[0].Content)
+		mu.Unlock()
+		if call == 1 {
+			close(started)
+			<-release
+		}
+		return echo(ctx, call, request)
+	}}
+	q := startQueue(t, executor)
+
+	q.Submit(context.Background(), prompt("gate"))
+	<-started
+	q.Submit(context.Background(), prompt("low"))
+	q.Submit(context.Background(), prompt("high"), WithPriority(2))
+	q.Submit(context.Background(), prompt("mid"), WithPriority(1))
+	q.Submit(context.Background(), prompt("high again"), WithPriority(2))
+	close(release)
+
+	jobs, _ := q.List(context.Background())
+	for _, job := range jobs {
+		waitJob(t, q, job.ID)
+	}
+
+	want := []string{"gate", "high", "high again", "mid", "low"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != len(want) {
+		t.Fatalf("Run order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Run order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestQueue_PreemptsForCapacity(t *testing.T) {
+	started := make(chan struct{})
+	executor := &fakeExecutor{fn: func(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error) {
+		if call == 1 {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return echo(ctx, call, request)
+	}}
+	q := startQueue(t, executor, WithPreemption())
+
+	low, _ := q.Submit(context.Background(), prompt("low"))
+	<-started
+	high, _ := q.Submit(context.Background(), prompt("high"), WithPriority(1))
+
+	highDone := waitJob(t, q, high.ID)
+	lowDone := waitJob(t, q, low.ID)
+	if highDone.Status != StatusSucceeded || lowDone.Status != StatusSucceeded {
+		t.Fatalf("Unexpected statuses: high %s, low %s", highDone.Status, lowDone.Status)
+	}
+	if lowDone.FinishedAt.Before(highDone.FinishedAt) {
+		t.Error("Expected the preempted job to finish after the job that preempted it")
+	}
+	if lowDone.Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1 since preemption is not counted", lowDone.Attempts)
+	}
+	if p := lowDone.Preempted; p == nil || p.JobID != low.ID || p.By != high.ID || p.ByPriority != 1 {
+		t.Errorf("Unexpected preemption record: %+v", p)
+	}
+}
+
+func TestQueue_NoPreemptionWithoutOption(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	executor := &fakeExecutor{fn: func(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error) {
+		if call == 1 {
+			close(started)
+			<-release
+		}
+		return echo(ctx, call, request)
+	}}
+	q := startQueue(t, executor)
+
+	low, _ := q.Submit(context.Background(), prompt("low"))
+	<-started
+	high, _ := q.Submit(context.Background(), prompt("high"), WithPriority(1))
+	close(release)
+
+	if job := waitJob(t, q, low.ID); job.Status != StatusSucceeded || job.Preempted != nil {
+		t.Errorf("Expected the running job to finish undisturbed, got %+v", job)
+	}
+	waitJob(t, q, high.ID)
+	if calls := atomic.LoadInt32(&executor.calls); calls != 2 {
+		t.Errorf("Executor calls = %d, want 2", calls)
+	}
+}
+
+func TestQueue_BudgetPreemption(t *testing.T) {
+	started := make(chan struct{})
+	executor := &fakeExecutor{fn: func(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error) {
+		switch request.Messages[0].Content {
+		case "background":
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		case "spend":
+			return &types.QueryResponse{Model: "test-model", Usage: &types.TokenUsage{InputTokens: 2}}, nil
+		}
+		return echo(ctx, call, request)
+	}}
+	q := startQueue(t, executor, WithWorkers(2), WithBudget(Budget{
+		MaxCostUSD: 1.5,
+		Pricing:    pricing.Table{"test-model": {InputPerMTok: 1e6, Currency: "USD"}},
+	}))
+
+	background, _ := q.Submit(context.Background(), prompt("background"))
+	<-started
+	spend, _ := q.Submit(context.Background(), prompt("spend"))
+	if job := waitJob(t, q, spend.ID); job.Status != StatusSucceeded {
+		t.Fatalf("Spend job status = %s", job.Status)
+	}
+	if spent := q.Spent(); spent != 2 {
+		t.Errorf("Spent = %v, want 2", spent)
+	}
+
+	ctx := context.Background()
+	job, err := q.Wait(ctx, background.ID)
+	var preempted *PreemptedError
+	if !errors.As(err, &preempted) || preempted.JobID != background.ID || preempted.By != "" {
+		t.Fatalf("Expected budget PreemptedError, got %v", err)
+	}
+	if job.Status != StatusFailed || job.Preempted == nil {
+		t.Errorf("Unexpected preempted job: %+v", job)
+	}
+
+	// Once the budget is spent, only jobs above the exempt priority run
+	calls := atomic.LoadInt32(&executor.calls)
+	late, _ := q.Submit(ctx, prompt("late"))
+	if _, err := q.Wait(ctx, late.ID); !errors.As(err, &preempted) {
+		t.Errorf("Expected late job to be preempted, got %v", err)
+	}
+	if got := atomic.LoadInt32(&executor.calls); got != calls {
+		t.Errorf("Executor ran a job the budget no longer allows")
+	}
+
+	urgent, _ := q.Submit(ctx, prompt("urgent"), WithPriority(1))
+	if job := waitJob(t, q, urgent.ID); job.Status != StatusSucceeded {
+		t.Errorf("Urgent job status = %s, want succeeded", job.Status)
+	}
+}
@@ -0,0 +1,556 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// ErrQueueStopped is returned when waiting on a queue that has stopped.
+var ErrQueueStopped = errors.New("job queue stopped")
+
+// Option configures a Queue.
+type Option func(*Queue)
+
+// WithStore sets the job store (defaults to a MemoryStore).
+func WithStore(store Store) Option {
+	return func(q *Queue) {
+		q.store = store
+	}
+}
+
+// WithWorkers sets the number of jobs run concurrently (defaults to 1).
+func WithWorkers(workers int) Option {
+	return func(q *Queue) {
+		if workers > 0 {
+			q.workers = workers
+		}
+	}
+}
+
+// WithRetryPolicy sets the retry policy (defaults to DefaultRetryPolicy).
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(q *Queue) {
+		q.retry = policy
+	}
+}
+
+// WithJobTimeout bounds each attempt (zero means no timeout).
+func WithJobTimeout(timeout time.Duration) Option {
+	return func(q *Queue) {
+		q.timeout = timeout
+	}
+}
+
+// SubmitOption configures a submitted job.
+type SubmitOption func(*Job)
+
+// WithJobID sets the job ID instead of generating one.
+func WithJobID(id string) SubmitOption {
+	return func(j *Job) {
+		j.ID = id
+	}
+}
+
+// WithMetadata attaches labels to the job.
+func WithMetadata(metadata map[string]string) SubmitOption {
+	return func(j *Job) {
+		j.Metadata = metadata
+	}
+}
+
+// WithMaxAttempts overrides the retry policy's attempt limit for the job.
+func WithMaxAttempts(attempts int) SubmitOption {
+	return func(j *Job) {
+		if attempts > 0 {
+			j.MaxAttempts = attempts
+		}
+	}
+}
+
+// Queue runs jobs in the background with a pool of workers, persisting every
+// state change to its Store. Jobs left pending or running by a previous
+// process are picked up again when the queue starts.
+type Queue struct {
+	executor types.QueryExecutor
+	store    Store
+	workers  int
+	retry    RetryPolicy
+	timeout  time.Duration
+	now      func() time.Time
+
+	preemption bool
+	budget     *Budget
+
+	mu          sync.Mutex
+	ready       []readyJob
+	signal      chan struct{}
+	running     map[string]*runningJob
+	spent       float64
+	canceled    map[string]bool
+	subscribers map[string][]chan *Job
+	timers      map[string]*time.Timer
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+	stopped bool
+}
+
+// New creates a queue that runs jobs with executor. Call Start to begin
+// processing.
+func New(executor types.QueryExecutor, opts ...Option) *Queue {
+	q := &Queue{
+		executor:    executor,
+		store:       NewMemoryStore(),
+		workers:     1,
+		retry:       DefaultRetryPolicy(),
+		now:         time.Now,
+		signal:      make(chan struct{}, 1),
+		running:     make(map[string]*runningJob),
+		canceled:    make(map[string]bool),
+		subscribers: make(map[string][]chan *Job),
+		timers:      make(map[string]*time.Timer),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if q.retry.MaxAttempts < 1 {
+		q.retry.MaxAttempts = 1
+	}
+	return q
+}
+
+// Start recovers unfinished jobs from the store and starts the workers.
+// Jobs found running are assumed interrupted and run again.
+func (q *Queue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	if q.started {
+		q.mu.Unlock()
+		return sdkerrors.NewInternalError("QUEUE_STARTED", "job queue already started")
+	}
+	q.started = true
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	q.mu.Unlock()
+
+	jobs, err := q.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Status == StatusRunning {
+			job.Status = StatusPending
+			job.UpdatedAt = q.now()
+			if err := q.store.Save(ctx, job); err != nil {
+				return err
+			}
+		}
+		if job.Status == StatusPending {
+			q.schedule(job, job.NextAttemptAt.Sub(q.now()))
+		}
+	}
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return nil
+}
+
+// Stop stops the workers and waits for them to exit. Interrupted jobs are
+// returned to pending so a later Start resumes them.
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	if !q.started || q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	q.stopped = true
+	for id, timer := range q.timers {
+		timer.Stop()
+		delete(q.timers, id)
+	}
+	q.mu.Unlock()
+
+	q.cancel()
+	q.wg.Wait()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for id, subs := range q.subscribers {
+		for _, ch := range subs {
+			close(ch)
+		}
+		delete(q.subscribers, id)
+	}
+}
+
+// Submit persists a job for request and queues it for execution.
+func (q *Queue) Submit(ctx context.Context, request *types.QueryRequest, opts ...SubmitOption) (*Job, error) {
+	if request == nil {
+		return nil, sdkerrors.NewValidationError("request", "", "required", "request cannot be nil")
+	}
+
+	now := q.now()
+	job := &Job{
+		ID:          uuid.New().String(),
+		Request:     request,
+		Status:      StatusPending,
+		MaxAttempts: q.retry.MaxAttempts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	if _, err := q.store.Get(ctx, job.ID); err == nil {
+		return nil, sdkerrors.NewValidationError("id", job.ID, "unique job ID", "job already exists")
+	} else if !errors.Is(err, ErrJobNotFound) {
+		return nil, err
+	}
+
+	if err := q.store.Save(ctx, job); err != nil {
+		return nil, err
+	}
+	q.schedule(job, 0)
+	return job.Clone(), nil
+}
+
+// Get returns the current state of a job.
+func (q *Queue) Get(ctx context.Context, id string) (*Job, error) {
+	return q.store.Get(ctx, id)
+}
+
+// List returns all jobs in the store.
+func (q *Queue) List(ctx context.Context) ([]*Job, error) {
+	return q.store.List(ctx)
+}
+
+// Cancel cancels a pending or running job. Canceling a finished job has no effect.
+func (q *Queue) Cancel(ctx context.Context, id string) error {
+	job, err := q.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status.Terminal() {
+		return nil
+	}
+
+	q.mu.Lock()
+	q.canceled[id] = true
+	run, running := q.running[id]
+	if timer, ok := q.timers[id]; ok {
+		timer.Stop()
+		delete(q.timers, id)
+	}
+	q.mu.Unlock()
+
+	if running {
+		// The worker records the cancellation when the query returns
+		run.cancel()
+		return nil
+	}
+
+	job.Status = StatusCanceled
+	job.FinishedAt = q.now()
+	job.UpdatedAt = job.FinishedAt
+	return q.update(ctx, job)
+}
+
+// Subscribe returns a channel that receives a snapshot of the job after each
+// state change. The channel is closed once the job reaches a terminal status
+// or the queue stops; call the returned function to unsubscribe early.
+func (q *Queue) Subscribe(id string) (<-chan *Job, func()) {
+	ch := make(chan *Job, 16)
+
+	q.mu.Lock()
+	q.subscribers[id] = append(q.subscribers[id], ch)
+	q.mu.Unlock()
+
+	unsubscribe := func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		subs := q.subscribers[id]
+		for i, sub := range subs {
+			if sub == ch {
+				q.subscribers[id] = append(subs[:i], subs[i+1:]...)
+				close(ch)
+				break
+			}
+		}
+		if len(q.subscribers[id]) == 0 {
+			delete(q.subscribers, id)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// Wait blocks until a job reaches a terminal status and returns it. A job
+// that failed because it was preempted is returned with its PreemptedError.
+func (q *Queue) Wait(ctx context.Context, id string) (*Job, error) {
+	updates, unsubscribe := q.Subscribe(id)
+	defer unsubscribe()
+
+	job, err := q.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for !job.Status.Terminal() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case update, ok := <-updates:
+			if !ok {
+				if job, err = q.store.Get(ctx, id); err != nil {
+					return nil, err
+				}
+				if !job.Status.Terminal() {
+					return job, ErrQueueStopped
+				}
+				return job, job.preemptedError()
+			}
+			job = update
+		}
+	}
+	return job, job.preemptedError()
+}
+
+// readyJob is a job waiting for a worker.
+type readyJob struct {
+	id       string
+	priority int
+}
+
+// runningJob tracks a job a worker is executing.
+type runningJob struct {
+	cancel    context.CancelFunc
+	priority  int
+	startedAt time.Time
+
+	// preempted is set when the job is interrupted for another
+	preempted *PreemptedError
+}
+
+// schedule queues a job to run after delay.
+func (q *Queue) schedule(job *Job, delay time.Duration) {
+	id, priority := job.ID, job.Priority
+	if delay <= 0 {
+		q.enqueue(id, priority)
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped {
+		return
+	}
+	q.timers[id] = time.AfterFunc(delay, func() {
+		q.mu.Lock()
+		delete(q.timers, id)
+		q.mu.Unlock()
+		q.enqueue(id, priority)
+	})
+}
+
+// enqueue makes a job available to the workers, behind ready jobs of the
+// same or higher priority.
+func (q *Queue) enqueue(id string, priority int) {
+	q.mu.Lock()
+	i := len(q.ready)
+	for i > 0 && q.ready[i-1].priority < priority {
+		i--
+	}
+	q.ready = append(q.ready, readyJob{})
+	copy(q.ready[i+1:], q.ready[i:])
+	q.ready[i] = readyJob{id: id, priority: priority}
+	q.preemptForLocked(id, priority)
+	q.mu.Unlock()
+	q.wake()
+}
+
+// wake signals one idle worker without blocking.
+func (q *Queue) wake() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the next ready job ID, or false when the queue stops.
+func (q *Queue) next() (string, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.ready) > 0 {
+			id := q.ready[0].id
+			q.ready = q.ready[1:]
+			more := len(q.ready) > 0
+			q.mu.Unlock()
+			if more {
+				q.wake()
+			}
+			return id, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.signal:
+		case <-q.ctx.Done():
+			return "", false
+		}
+	}
+}
+
+// work runs jobs until the queue stops.
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	for {
+		id, ok := q.next()
+		if !ok {
+			return
+		}
+		q.run(id)
+	}
+}
+
+// run executes one attempt of a job and records the outcome.
+func (q *Queue) run(id string) {
+	ctx := context.Background()
+
+	job, err := q.store.Get(ctx, id)
+	if err != nil || job.Status != StatusPending {
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(q.ctx)
+	if q.timeout > 0 {
+		runCtx, cancel = context.WithTimeout(q.ctx, q.timeout)
+	}
+	defer cancel()
+
+	q.mu.Lock()
+	if q.canceled[id] {
+		delete(q.canceled, id)
+		q.mu.Unlock()
+		return
+	}
+	if q.budgetExhaustedLocked() && job.Priority <= q.budget.ExemptPriority {
+		q.mu.Unlock()
+		preempted := &PreemptedError{JobID: id, Priority: job.Priority}
+		job.Status = StatusFailed
+		job.Error = preempted.Error()
+		job.Preempted = preempted
+		job.FinishedAt = q.now()
+		job.UpdatedAt = job.FinishedAt
+		_ = q.update(ctx, job) // Ignore error, the job state is also broadcast to subscribers
+		return
+	}
+	q.running[id] = &runningJob{cancel: cancel, priority: job.Priority, startedAt: q.now()}
+	q.mu.Unlock()
+
+	job.Status = StatusRunning
+	job.Attempts++
+	job.StartedAt = q.now()
+	job.UpdatedAt = job.StartedAt
+	_ = q.update(ctx, job) // Ignore error, the outcome is saved below
+
+	response, err := q.executor.Query(runCtx, job.Request)
+
+	q.mu.Lock()
+	preempted := q.running[id].preempted
+	delete(q.running, id)
+	canceled := q.canceled[id]
+	delete(q.canceled, id)
+	if err == nil {
+		q.chargeLocked(response)
+	}
+	q.mu.Unlock()
+
+	now := q.now()
+	job.UpdatedAt = now
+
+	switch {
+	case err == nil:
+		job.Status = StatusSucceeded
+		job.Response = response
+		job.Error = ""
+		job.FinishedAt = now
+
+	case canceled:
+		job.Status = StatusCanceled
+		job.Error = err.Error()
+		job.FinishedAt = now
+
+	case q.ctx.Err() != nil:
+		// The queue is stopping; leave the job for the next Start
+		job.Status = StatusPending
+		job.Attempts--
+		job.Error = ""
+
+	case preempted != nil && preempted.By != "":
+		// A higher-priority job took the worker; run again without counting
+		// the attempt
+		job.Status = StatusPending
+		job.Attempts--
+		job.Error = preempted.Error()
+		job.Preempted = preempted
+		_ = q.update(ctx, job) // Ignore error, the job still runs again
+		q.schedule(job, 0)
+		return
+
+	case preempted != nil:
+		job.Status = StatusFailed
+		job.Error = preempted.Error()
+		job.Preempted = preempted
+		job.FinishedAt = now
+
+	case q.retry.shouldRetry(err, job.Attempts, job.MaxAttempts):
+		delay := q.retry.Backoff(job.Attempts)
+		job.Status = StatusPending
+		job.Error = err.Error()
+		job.NextAttemptAt = now.Add(delay)
+		_ = q.update(ctx, job) // Ignore error, the retry still runs
+		q.schedule(job, delay)
+		return
+
+	default:
+		job.Status = StatusFailed
+		job.Error = err.Error()
+		job.FinishedAt = now
+	}
+
+	_ = q.update(ctx, job) // Ignore error, the job state is also broadcast to subscribers
+}
+
+// update saves a job and notifies its subscribers.
+func (q *Queue) update(ctx context.Context, job *Job) error {
+	err := q.store.Save(ctx, job)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	subs := q.subscribers[job.ID]
+	for _, ch := range subs {
+		select {
+		case ch <- job.Clone():
+		default:
+			// Slow subscribers miss intermediate updates but still see the channel close
+		}
+		if job.Status.Terminal() {
+			close(ch)
+		}
+	}
+	if job.Status.Terminal() {
+		delete(q.subscribers, job.ID)
+	}
+	return err
+}
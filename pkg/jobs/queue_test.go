@@ -0,0 +1,213 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeExecutor runs queries with a configurable function and counts calls.
+type fakeExecutor struct {
+	calls int32
+	fn    func(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error)
+}
+
+func (e *fakeExecutor) Query(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	call := int(atomic.AddInt32(&e.calls, 1))
+	return e.fn(ctx, call, request)
+}
+
+func echo(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error) {
+	return &types.QueryResponse{Content: []types.ContentBlock{types.NewTextBlock("done: " + request.Messages[0].Content)}}, nil
+}
+
+func prompt(text string) *types.QueryRequest {
+	return &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: text}}}
+}
+
+func startQueue(t *testing.T, executor types.QueryExecutor, opts ...Option) *Queue {
+	t.Helper()
+
+	q := New(executor, opts...)
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(q.Stop)
+	return q
+}
+
+func waitJob(t *testing.T, q *Queue, id string) *Job {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := q.Wait(ctx, id)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	return job
+}
+
+func TestQueue_SubmitAndWait(t *testing.T) {
+	q := startQueue(t, &fakeExecutor{fn: echo}, WithWorkers(2))
+
+	job, err := q.Submit(context.Background(), prompt("task"), WithMetadata(map[string]string{"ticket": "42"}))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if job.Status != StatusPending || job.ID == "" {
+		t.Errorf("Unexpected submitted job: %+v", job)
+	}
+
+	done := waitJob(t, q, job.ID)
+	if done.Status != StatusSucceeded || done.Response.Content[0].Text != "done: task" {
+		t.Errorf("Unexpected result: %+v", done)
+	}
+	if done.Attempts != 1 || done.Metadata["ticket"] != "42" || done.FinishedAt.IsZero() {
+		t.Errorf("Unexpected job fields: %+v", done)
+	}
+
+	if _, err := q.Submit(context.Background(), prompt("again"), WithJobID(job.ID)); err == nil {
+		t.Error("Expected duplicate job ID to be rejected")
+	}
+	if _, err := q.Submit(context.Background(), nil); err == nil {
+		t.Error("Expected nil request to be rejected")
+	}
+}
+
+func TestQueue_Retries(t *testing.T) {
+	executor := &fakeExecutor{fn: func(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error) {
+		if call < 3 {
+			return nil, sdkerrors.NewNetworkError("query", "", errors.New("connection reset"))
+		}
+		return echo(ctx, call, request)
+	}}
+	q := startQueue(t, executor, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Retryable:      func(error) bool { return true },
+	}))
+
+	updates, unsubscribe := q.Subscribe("retry")
+	defer unsubscribe()
+
+	if _, err := q.Submit(context.Background(), prompt("flaky"), WithJobID("retry")); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	var statuses []Status
+	for job := range updates {
+		statuses = append(statuses, job.Status)
+	}
+	if len(statuses) == 0 || statuses[len(statuses)-1] != StatusSucceeded {
+		t.Fatalf("Unexpected status updates: %v", statuses)
+	}
+
+	job, _ := q.Get(context.Background(), "retry")
+	if job.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", job.Attempts)
+	}
+}
+
+func TestQueue_PermanentFailure(t *testing.T) {
+	executor := &fakeExecutor{fn: func(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error) {
+		return nil, sdkerrors.NewValidationError("messages", "", "non-empty", "bad request")
+	}}
+	q := startQueue(t, executor)
+
+	job, _ := q.Submit(context.Background(), prompt("bad"))
+	done := waitJob(t, q, job.ID)
+	if done.Status != StatusFailed || done.Attempts != 1 || done.Error == "" {
+		t.Errorf("Expected permanent failure after one attempt, got %+v", done)
+	}
+}
+
+func TestQueue_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	executor := &fakeExecutor{fn: func(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	q := startQueue(t, executor)
+
+	running, _ := q.Submit(context.Background(), prompt("long"))
+	queued, _ := q.Submit(context.Background(), prompt("queued"))
+	<-started
+
+	if err := q.Cancel(context.Background(), queued.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if err := q.Cancel(context.Background(), running.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+
+	if job := waitJob(t, q, running.ID); job.Status != StatusCanceled {
+		t.Errorf("Running job status = %s, want canceled", job.Status)
+	}
+	if job := waitJob(t, q, queued.ID); job.Status != StatusCanceled {
+		t.Errorf("Queued job status = %s, want canceled", job.Status)
+	}
+	if calls := atomic.LoadInt32(&executor.calls); calls != 1 {
+		t.Errorf("Executor calls = %d, want 1", calls)
+	}
+}
+
+func TestQueue_RecoversAfterRestart(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+
+	// A stopped queue leaves its interrupted job pending
+	var once sync.Once
+	started := make(chan struct{})
+	blocking := &fakeExecutor{fn: func(ctx context.Context, call int, request *types.QueryRequest) (*types.QueryResponse, error) {
+		once.Do(func() { close(started) })
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	first := New(blocking, WithStore(store))
+	if err := first.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	job, _ := first.Submit(context.Background(), prompt("survive"))
+	<-started
+	first.Stop()
+
+	if saved, _ := store.Get(context.Background(), job.ID); saved.Status != StatusPending || saved.Attempts != 0 {
+		t.Fatalf("Expected interrupted job to be pending, got %+v", saved)
+	}
+
+	// A running job left behind by a crash is also recovered
+	crashed := &Job{ID: "crashed", Request: prompt("crashed"), Status: StatusRunning, MaxAttempts: 1, Attempts: 1}
+	if err := store.Save(context.Background(), crashed); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	second := startQueue(t, &fakeExecutor{fn: echo}, WithStore(store))
+	if done := waitJob(t, second, job.ID); done.Status != StatusSucceeded {
+		t.Errorf("Recovered job status = %s", done.Status)
+	}
+	if done := waitJob(t, second, "crashed"); done.Status != StatusSucceeded {
+		t.Errorf("Crashed job status = %s", done.Status)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		if got := policy.Backoff(i + 1); got != expected {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, expected)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// RetryPolicy controls how failed jobs are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first (minimum 1)
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+
+	// Multiplier grows the delay after each retry
+	Multiplier float64
+
+	// Retryable decides whether an error is worth retrying (defaults to sdkerrors.IsRetryable)
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy retries retryable errors up to three attempts with
+// exponential backoff.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Retryable:      sdkerrors.IsRetryable,
+	}
+}
+
+// NoRetry runs each job once.
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// Backoff returns the delay before the given retry (1 for the first retry).
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	for i := 1; i < retry; i++ {
+		delay *= multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(delay)
+}
+
+// shouldRetry reports whether a job that failed with err on the given attempt
+// should run again.
+func (p RetryPolicy) shouldRetry(err error, attempt, maxAttempts int) bool {
+	if attempt >= maxAttempts {
+		return false
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = sdkerrors.IsRetryable
+	}
+	return retryable(err)
+}
@@ -0,0 +1,221 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// ErrJobNotFound is returned when a job does not exist.
+var ErrJobNotFound = errors.New("job not found")
+
+// Store persists jobs. Implementations must be safe for concurrent use.
+type Store interface {
+	// Save creates or replaces a job
+	Save(ctx context.Context, job *Job) error
+
+	// Get returns a job, or ErrJobNotFound
+	Get(ctx context.Context, id string) (*Job, error)
+
+	// List returns all jobs ordered by creation time
+	List(ctx context.Context) ([]*Job, error)
+
+	// Delete removes a job. Deleting a missing job is not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore keeps jobs in memory. Jobs do not survive a restart.
+type MemoryStore struct {
+	jobs map[string]*Job
+	mu   sync.RWMutex
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: make(map[string]*Job)}
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(ctx context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = job.Clone()
+	return nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return job.Clone(), nil
+}
+
+// List implements Store.
+func (s *MemoryStore) List(ctx context.Context) ([]*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job.Clone())
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	return nil
+}
+
+// FileStore keeps each job as a JSON file in a directory, so queued work
+// survives process restarts.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore creates a store in dir, creating the directory if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "JOB_STORE_DIR", "failed to create job store directory")
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Save implements Store. Files are replaced atomically.
+func (s *FileStore) Save(ctx context.Context, job *Job) error {
+	path, err := s.path(job.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JOB_ENCODE", "failed to encode job")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, ".job-*")
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JOB_SAVE", "failed to save job")
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JOB_SAVE", "failed to save job")
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JOB_SAVE", "failed to save job")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JOB_SAVE", "failed to save job")
+	}
+	return nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(ctx context.Context, id string) (*Job, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return readJob(path)
+}
+
+// List implements Store.
+func (s *FileStore) List(ctx context.Context) ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JOB_LIST", "failed to list jobs")
+	}
+
+	jobs := make([]*Job, 0, len(paths))
+	for _, path := range paths {
+		job, err := readJob(path)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JOB_DELETE", "failed to delete job")
+	}
+	return nil
+}
+
+// path returns the file for a job, rejecting IDs that could escape the directory.
+func (s *FileStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", sdkerrors.NewValidationError("id", id, "file-safe job ID", "invalid job ID")
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+func readJob(path string) (*Job, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JOB_READ", "failed to read job")
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "JOB_DECODE", "failed to decode job "+filepath.Base(path))
+	}
+	return &job, nil
+}
+
+// sortJobs orders jobs by creation time, then ID.
+func sortJobs(jobs []*Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+}
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now()
+
+	second := &Job{ID: "b", Request: prompt("second"), Status: StatusPending, CreatedAt: now.Add(time.Second)}
+	first := &Job{ID: "a", Request: prompt("first"), Status: StatusSucceeded, CreatedAt: now}
+	for _, job := range []*Job{second, first} {
+		if err := store.Save(ctx, job); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	// Stored jobs are copies
+	first.Status = StatusFailed
+	got, err := store.Get(ctx, "a")
+	if err != nil || got.Status != StatusSucceeded || got.Request.Messages[0].Content != "first" {
+		t.Errorf("Unexpected job: %+v (%v)", got, err)
+	}
+
+	jobs, err := store.List(ctx)
+	if err != nil || len(jobs) != 2 || jobs[0].ID != "a" || jobs[1].ID != "b" {
+		t.Errorf("Unexpected list: %v (%v)", jobs, err)
+	}
+
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Get(ctx, "a"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Expected ErrJobNotFound, got %v", err)
+	}
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Errorf("Deleting a missing job should succeed: %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	testStore(t, store)
+
+	if err := store.Save(context.Background(), &Job{ID: "../escape"}); err == nil {
+		t.Error("Expected path-like job ID to be rejected")
+	}
+}
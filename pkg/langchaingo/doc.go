@@ -0,0 +1,29 @@
+/*
+Package langchaingo adapts the Claude Code Go SDK to the interfaces used by
+the LangChainGo framework (github.com/tmc/langchaingo), so the Claude Code
+agentic backend can plug into existing chains and agents.
+
+LLM implements llms.Model: messages are converted to an SDK query, function
+tools map to SDK tools, and tool_use blocks are returned as tool calls. When a
+streaming function is set the response is streamed through it. Tool
+implements tools.Tool so an agent built on another model can delegate
+software engineering tasks to Claude Code.
+
+This package is a separate module so the core SDK does not depend on
+LangChainGo or its minimum Go version.
+
+# Basic Usage
+
+	claudeClient, err := client.NewClaudeCodeClient(ctx, types.NewClaudeCodeConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer claudeClient.Close()
+
+	llm := langchaingo.New(claudeClient, langchaingo.WithModel("claude-sonnet-4"))
+	answer, err := llms.GenerateFromSinglePrompt(ctx, llm, "Summarize the README")
+
+	// Give another agent access to Claude Code
+	agentTools := []tools.Tool{langchaingo.NewTool(claudeClient)}
+*/
+package langchaingo
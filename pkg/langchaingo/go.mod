@@ -0,0 +1,23 @@
+module github.com/jonwraymond/go-claude-code-sdk/pkg/langchaingo
+
+go 1.24.4
+
+require (
+	github.com/jonwraymond/go-claude-code-sdk v0.0.0
+	github.com/tmc/langchaingo v0.1.14
+)
+
+require (
+	github.com/BurntSushi/toml v1.3.2 // indirect
+	github.com/dlclark/regexp2 v1.10.0 // indirect
+	github.com/fsnotify/fsnotify v1.7.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/pkoukk/tiktoken-go v0.1.6 // indirect
+	go.starlark.net v0.0.0-20230302034142-4b1e35fe2254 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/jonwraymond/go-claude-code-sdk => ../..
@@ -0,0 +1,236 @@
+package langchaingo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/server"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// LLM implements llms.Model on top of an SDK conversation, typically a
+// ClaudeCodeClient or one of its sessions.
+type LLM struct {
+	conv  server.Conversation
+	model string
+}
+
+var _ llms.Model = (*LLM)(nil)
+
+// Option configures an LLM.
+type Option func(*LLM)
+
+// WithModel sets the default model, used when a call does not set llms.WithModel.
+func WithModel(model string) Option {
+	return func(l *LLM) {
+		l.model = model
+	}
+}
+
+// New creates an LLM backed by conv.
+func New(conv server.Conversation, opts ...Option) *LLM {
+	l := &LLM{conv: conv}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Call implements llms.Model for single text prompts.
+func (l *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+// GenerateContent implements llms.Model. When a streaming function is set the
+// response is streamed through it as it is generated.
+func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	opts := llms.CallOptions{Model: l.model}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	request, err := toQueryRequest(messages, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.StreamingFunc != nil {
+		return l.stream(ctx, request, opts.StreamingFunc)
+	}
+
+	response, err := l.conv.Query(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return toContentResponse(response), nil
+}
+
+// stream runs a streaming query, forwarding content chunks to fn.
+func (l *LLM) stream(ctx context.Context, request *types.QueryRequest, fn func(context.Context, []byte) error) (*llms.ContentResponse, error) {
+	request.Stream = true
+
+	stream, err := l.conv.QueryStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF || (err == nil && (chunk == nil || chunk.Done)) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if chunk.Type != types.ChunkTypeContent || chunk.Content == "" {
+			continue
+		}
+
+		content.WriteString(chunk.Content)
+		if err := fn(ctx, []byte(chunk.Content)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		Content:    content.String(),
+		StopReason: "end_turn",
+	}}}, nil
+}
+
+// toQueryRequest converts langchaingo messages and options to an SDK request.
+// System messages are combined into the system prompt.
+func toQueryRequest(messages []llms.MessageContent, opts llms.CallOptions) (*types.QueryRequest, error) {
+	request := &types.QueryRequest{
+		Model:         opts.Model,
+		MaxTokens:     opts.MaxTokens,
+		Temperature:   opts.Temperature,
+		TopP:          opts.TopP,
+		TopK:          opts.TopK,
+		StopSequences: opts.StopWords,
+		ToolChoice:    opts.ToolChoice,
+		Metadata:      opts.Metadata,
+	}
+
+	var system []string
+	for i, msg := range messages {
+		message := types.Message{}
+		switch msg.Role {
+		case llms.ChatMessageTypeSystem:
+			system = append(system, textOf(msg.Parts))
+			continue
+		case llms.ChatMessageTypeHuman, llms.ChatMessageTypeGeneric:
+			message.Role = types.RoleUser
+		case llms.ChatMessageTypeAI:
+			message.Role = types.RoleAssistant
+		case llms.ChatMessageTypeTool, llms.ChatMessageTypeFunction:
+			message.Role = types.RoleTool
+		default:
+			return nil, sdkerrors.NewValidationError("messages.role", string(msg.Role), "system, human, generic, ai, tool or function",
+				fmt.Sprintf("unsupported role in message %d", i))
+		}
+
+		var text []string
+		for _, part := range msg.Parts {
+			switch p := part.(type) {
+			case llms.TextContent:
+				text = append(text, p.Text)
+			case llms.ToolCall:
+				call := types.ToolCall{ID: p.ID, Type: p.Type}
+				if p.FunctionCall != nil {
+					call.Function = types.FunctionCall{Name: p.FunctionCall.Name, Arguments: p.FunctionCall.Arguments}
+				}
+				message.ToolCalls = append(message.ToolCalls, call)
+			case llms.ToolCallResponse:
+				message.ToolCallID = p.ToolCallID
+				text = append(text, p.Content)
+			default:
+				return nil, sdkerrors.NewValidationError("messages.parts", fmt.Sprintf("%T", part), "text, tool call or tool response",
+					fmt.Sprintf("unsupported content part in message %d", i))
+			}
+		}
+		message.Content = strings.Join(text, "\n")
+		request.Messages = append(request.Messages, message)
+	}
+	request.System = strings.Join(system, "\n\n")
+
+	for _, tool := range opts.Tools {
+		if tool.Function == nil {
+			continue
+		}
+		converted := types.Tool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: types.ToolInputSchema{Type: "object"},
+		}
+		if tool.Function.Parameters != nil {
+			schema, err := json.Marshal(tool.Function.Parameters)
+			if err == nil {
+				err = json.Unmarshal(schema, &converted.InputSchema)
+			}
+			if err != nil {
+				return nil, sdkerrors.NewValidationError("tools.function.parameters", tool.Function.Name, "JSON schema",
+					"invalid parameters schema: "+err.Error())
+			}
+		}
+		request.Tools = append(request.Tools, converted)
+	}
+
+	return request, nil
+}
+
+// toContentResponse converts an SDK response to a single-choice content response.
+func toContentResponse(response *types.QueryResponse) *llms.ContentResponse {
+	choice := &llms.ContentChoice{StopReason: response.StopReason}
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			arguments, err := json.Marshal(block.Input)
+			if err != nil || block.Input == nil {
+				arguments = []byte("{}")
+			}
+			choice.ToolCalls = append(choice.ToolCalls, llms.ToolCall{
+				ID:           block.ID,
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: block.Name, Arguments: string(arguments)},
+			})
+		}
+	}
+	choice.Content = text.String()
+	if len(choice.ToolCalls) > 0 {
+		choice.FuncCall = choice.ToolCalls[0].FunctionCall
+	}
+
+	if usage := response.Usage; usage != nil {
+		choice.GenerationInfo = map[string]any{
+			"InputTokens":  usage.InputTokens,
+			"OutputTokens": usage.OutputTokens,
+			"TotalTokens":  usage.InputTokens + usage.OutputTokens,
+		}
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}
+}
+
+// textOf joins the text parts of a message.
+func textOf(parts []llms.ContentPart) string {
+	var text []string
+	for _, part := range parts {
+		if p, ok := part.(llms.TextContent); ok {
+			text = append(text, p.Text)
+		}
+	}
+	return strings.Join(text, "\n")
+}
@@ -0,0 +1,145 @@
+package langchaingo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeStream returns the configured chunks and then a done chunk.
+type fakeStream struct {
+	chunks []types.StreamChunk
+}
+
+func (s *fakeStream) Recv() (*types.StreamChunk, error) {
+	if len(s.chunks) == 0 {
+		return &types.StreamChunk{Done: true}, nil
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return &chunk, nil
+}
+
+func (s *fakeStream) Close() error { return nil }
+
+// fakeConversation records the last request and replies with a fixed response.
+type fakeConversation struct {
+	lastRequest *types.QueryRequest
+	response    *types.QueryResponse
+}
+
+func (c *fakeConversation) Query(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	c.lastRequest = request
+	if c.response != nil {
+		return c.response, nil
+	}
+	prompt := request.Messages[len(request.Messages)-1].Content
+	return &types.QueryResponse{
+		Content:    []types.ContentBlock{types.NewTextBlock("echo: " + prompt)},
+		StopReason: "end_turn",
+		Usage:      &types.TokenUsage{InputTokens: 2, OutputTokens: 3},
+	}, nil
+}
+
+func (c *fakeConversation) QueryStream(ctx context.Context, request *types.QueryRequest) (types.QueryStream, error) {
+	c.lastRequest = request
+	return &fakeStream{chunks: []types.StreamChunk{
+		{Type: types.ChunkTypeContent, Content: "Hel"},
+		{Type: types.ChunkTypeMetadata},
+		{Type: types.ChunkTypeContent, Content: "lo"},
+	}}, nil
+}
+
+func TestLLM_Call(t *testing.T) {
+	conv := &fakeConversation{}
+	llm := New(conv, WithModel("claude-sonnet-4"))
+
+	out, err := llm.Call(context.Background(), "hi", llms.WithMaxTokens(50))
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if out != "echo: hi" {
+		t.Errorf("Call = %q", out)
+	}
+	if conv.lastRequest.Model != "claude-sonnet-4" || conv.lastRequest.MaxTokens != 50 {
+		t.Errorf("Options not applied: %+v", conv.lastRequest)
+	}
+
+	if _, err := llm.Call(context.Background(), "hi", llms.WithModel("claude-opus-4")); err != nil || conv.lastRequest.Model != "claude-opus-4" {
+		t.Errorf("Per-call model not applied: %q (%v)", conv.lastRequest.Model, err)
+	}
+}
+
+func TestLLM_GenerateContent(t *testing.T) {
+	conv := &fakeConversation{response: &types.QueryResponse{
+		Content: []types.ContentBlock{
+			types.NewTextBlock("Reading"),
+			{Type: "tool_use", ID: "toolu_1", Name: "read", Input: map[string]any{"path": "a.go"}},
+		},
+		StopReason: "tool_use",
+	}}
+	llm := New(conv)
+
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "Be brief."),
+		llms.TextParts(llms.ChatMessageTypeHuman, "Read a.go"),
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{llms.ToolCall{ID: "call_0", Type: "function", FunctionCall: &llms.FunctionCall{Name: "read", Arguments: `{}`}}}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{llms.ToolCallResponse{ToolCallID: "call_0", Content: "missing"}}},
+	}
+	tools := []llms.Tool{{Type: "function", Function: &llms.FunctionDefinition{
+		Name:       "read",
+		Parameters: map[string]any{"type": "object", "required": []string{"path"}},
+	}}}
+
+	resp, err := llm.GenerateContent(context.Background(), messages, llms.WithTools(tools))
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+
+	request := conv.lastRequest
+	if request.System != "Be brief." || len(request.Messages) != 3 {
+		t.Errorf("Unexpected request: %+v", request)
+	}
+	if request.Messages[1].ToolCalls[0].ID != "call_0" || request.Messages[2].ToolCallID != "call_0" {
+		t.Errorf("Tool history not converted: %+v", request.Messages)
+	}
+	if request.Tools[0].InputSchema.Required[0] != "path" {
+		t.Errorf("Tool schema not converted: %+v", request.Tools)
+	}
+
+	choice := resp.Choices[0]
+	if choice.Content != "Reading" || choice.StopReason != "tool_use" {
+		t.Errorf("Unexpected choice: %+v", choice)
+	}
+	if len(choice.ToolCalls) != 1 || choice.ToolCalls[0].FunctionCall.Arguments != `{"path":"a.go"}` {
+		t.Errorf("Unexpected tool calls: %+v", choice.ToolCalls)
+	}
+
+	_, err = llm.GenerateContent(context.Background(), []llms.MessageContent{{Role: "unknown"}})
+	if sdkerrors.GetCategory(err) != sdkerrors.CategoryValidation {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestLLM_Streaming(t *testing.T) {
+	llm := New(&fakeConversation{})
+
+	var streamed []string
+	resp, err := llm.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")},
+		llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			streamed = append(streamed, string(chunk))
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+	if len(streamed) != 2 || resp.Choices[0].Content != "Hello" {
+		t.Errorf("Unexpected stream: %v %q", streamed, resp.Choices[0].Content)
+	}
+}
@@ -0,0 +1,82 @@
+package langchaingo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/server"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// DefaultToolName is the name reported by a Tool without an explicit name.
+const DefaultToolName = "claude_code"
+
+// DefaultToolDescription describes the Claude Code agent to the calling agent.
+const DefaultToolDescription = "Delegates a software engineering task to the Claude Code agent, " +
+	"which can read, search and edit files in the project. Input is the task in plain language; " +
+	"the output is Claude Code's final answer."
+
+// Tool implements tools.Tool, letting agents in other frameworks delegate
+// tasks to Claude Code.
+type Tool struct {
+	// Conversation runs the delegated tasks, typically a ClaudeCodeClient or session
+	Conversation server.Conversation
+
+	// ToolName overrides DefaultToolName
+	ToolName string
+
+	// ToolDescription overrides DefaultToolDescription
+	ToolDescription string
+
+	// Model selects the model for delegated tasks (empty uses the client default)
+	Model string
+
+	// System is an optional system prompt added to each task
+	System string
+}
+
+var _ tools.Tool = (*Tool)(nil)
+
+// NewTool creates a tool backed by conv with the default name and description.
+func NewTool(conv server.Conversation) *Tool {
+	return &Tool{Conversation: conv}
+}
+
+// Name implements tools.Tool.
+func (t *Tool) Name() string {
+	if t.ToolName != "" {
+		return t.ToolName
+	}
+	return DefaultToolName
+}
+
+// Description implements tools.Tool.
+func (t *Tool) Description() string {
+	if t.ToolDescription != "" {
+		return t.ToolDescription
+	}
+	return DefaultToolDescription
+}
+
+// Call implements tools.Tool by running input as a query and returning the
+// response text.
+func (t *Tool) Call(ctx context.Context, input string) (string, error) {
+	response, err := t.Conversation.Query(ctx, &types.QueryRequest{
+		Model:    t.Model,
+		System:   t.System,
+		Messages: []types.Message{{Role: types.RoleUser, Content: input}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), nil
+}
@@ -0,0 +1,29 @@
+package langchaingo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTool(t *testing.T) {
+	conv := &fakeConversation{}
+	tool := NewTool(conv)
+	tool.System = "Work in the repo."
+
+	if tool.Name() != DefaultToolName || tool.Description() != DefaultToolDescription {
+		t.Errorf("Unexpected defaults: %q %q", tool.Name(), tool.Description())
+	}
+
+	out, err := tool.Call(context.Background(), "fix the build")
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if out != "echo: fix the build" || conv.lastRequest.System != "Work in the repo." {
+		t.Errorf("Unexpected result %q for request %+v", out, conv.lastRequest)
+	}
+
+	tool.ToolName = "coder"
+	if tool.Name() != "coder" {
+		t.Errorf("Name override ignored: %q", tool.Name())
+	}
+}
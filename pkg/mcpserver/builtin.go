@@ -0,0 +1,91 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// SubcommandArg is the first argument with which Command re-executes the
+// current program to run a built-in server.
+const SubcommandArg = "__mcp-server"
+
+// Built-in server names accepted by Builtin and Command.
+const (
+	BuiltinFilesystem = "filesystem"
+	BuiltinFetch      = "fetch"
+	BuiltinMemory     = "memory"
+)
+
+// Builtin creates a built-in server by name. The filesystem server takes its
+// root directory as the first argument (default "."); the fetch server takes
+// its allowed hosts; the memory server takes no arguments.
+func Builtin(name string, args []string) (*Server, error) {
+	switch name {
+	case BuiltinFilesystem:
+		root := "."
+		if len(args) > 0 {
+			root = args[0]
+		}
+		return NewFilesystemServer(root)
+
+	case BuiltinFetch:
+		return NewFetchServer(FetchConfig{AllowedHosts: args})
+
+	case BuiltinMemory:
+		return NewMemoryServer(), nil
+	}
+
+	return nil, fmt.Errorf("unknown built-in MCP server %q", name)
+}
+
+// Command returns an MCP server configuration that runs a built-in server as
+// a subcommand of the current program, so it can be registered with the
+// client without Node or npx:
+//
+//	fs, err := mcpserver.Command(mcpserver.BuiltinFilesystem, "/path/to/project")
+//	err = claude.AddMCPServer(ctx, "filesystem", fs)
+//
+// The program must call RunSubcommand at the start of main.
+func Command(name string, args ...string) (*types.MCPServerConfig, error) {
+	if _, err := Builtin(name, args); err != nil {
+		return nil, err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	return &types.MCPServerConfig{
+		Command: executable,
+		Args:    append([]string{SubcommandArg, name}, args...),
+		Enabled: true,
+	}, nil
+}
+
+// RunSubcommand serves a built-in server on stdin and stdout and exits when
+// the program was started by a configuration from Command. Otherwise it
+// returns immediately. Call it first thing in main:
+//
+//	func main() {
+//		mcpserver.RunSubcommand()
+//		// ...
+//	}
+func RunSubcommand() {
+	if len(os.Args) < 3 || os.Args[1] != SubcommandArg {
+		return
+	}
+
+	server, err := Builtin(os.Args[2], os.Args[3:])
+	if err == nil {
+		err = server.Serve(context.Background(), os.Stdin, os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mcp server %s: %v\n", os.Args[2], err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,70 @@
+package mcpserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Serve built-in servers when re-executed by TestCommand
+	RunSubcommand()
+	os.Exit(m.Run())
+}
+
+func TestBuiltin(t *testing.T) {
+	for _, name := range []string{BuiltinFilesystem, BuiltinMemory} {
+		if server, err := Builtin(name, nil); err != nil || server.Name() != name {
+			t.Errorf("Builtin(%s) = %v, %v", name, server, err)
+		}
+	}
+	if _, err := Builtin(BuiltinFetch, []string{"example.com"}); err != nil {
+		t.Errorf("Builtin(fetch) failed: %v", err)
+	}
+	if _, err := Builtin(BuiltinFetch, nil); err == nil {
+		t.Error("Expected error for fetch without hosts")
+	}
+	if _, err := Builtin("unknown", nil); err == nil {
+		t.Error("Expected error for an unknown server")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	config, err := Command(BuiltinMemory)
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if len(config.Args) != 2 || config.Args[0] != SubcommandArg || !config.Enabled {
+		t.Fatalf("Unexpected config: %+v", config)
+	}
+
+	cmd := exec.Command(config.Command, config.Args...) // #nosec G204 - re-executing the test binary
+	cmd.Stdin = strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"set","arguments":{"key":"k","value":"v"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get","arguments":{"key":"k"}}}`,
+	}, "\n"))
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Subcommand failed: %v", err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	var last struct {
+		Result ToolResult `json:"result"`
+	}
+	for scanner.Scan() {
+		if err := json.Unmarshal(scanner.Bytes(), &last); err != nil {
+			t.Fatalf("Invalid response %q: %v", scanner.Text(), err)
+		}
+	}
+	if last.Result.Text() != "v" {
+		t.Errorf("Unexpected subcommand output: %s", output)
+	}
+
+	if _, err := Command("unknown"); err == nil {
+		t.Error("Expected error for an unknown server")
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Package mcpserver provides MCP servers written in Go, so common tool servers
+do not need Node or npx.
+
+Three built-in servers are included:
+  - Filesystem: read-only file access confined to a root directory
+  - Fetch: HTTP GET limited to an allowlist of hosts
+  - Memory: a key-value store the model can use for notes
+
+Custom servers are built with New and AddTool. NewTool derives a tool's
+input schema from a struct's `json` and `description` tags and decodes the
+arguments for the handler:
+
+	type greetArguments struct {
+		Name string `json:"name" description:"Who to greet"`
+	}
+
+	tool, err := mcpserver.NewTool("greet", "Greet someone",
+		func(ctx context.Context, args greetArguments) (string, error) {
+			return "Hello, " + args.Name, nil
+		})
+	server.AddTool(tool)
+
+Handlers report progress with ReportProgress and ReportLog; callers collect
+it by passing a context from WithProgress to CallTool or Serve, or as the
+context of a request handled by ServeHTTP.
+
+# Running as a Subcommand
+
+The CLI starts MCP servers as subprocesses. Command returns a server
+configuration that re-executes the current program with SubcommandArg, and
+RunSubcommand serves the requested server when the program starts that way:
+
+	func main() {
+		mcpserver.RunSubcommand()
+
+		claude, err := client.NewClaudeCodeClient(ctx, types.NewClaudeCodeConfig())
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer claude.Close()
+
+		fs, err := mcpserver.Command(mcpserver.BuiltinFilesystem, "/path/to/project")
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = claude.AddMCPServer(ctx, "filesystem", fs)
+	}
+
+# In-Process Use
+
+Servers can also be called directly, served over any reader and writer, or
+mounted as an http.Handler for MCP's HTTP transport:
+
+	memory := mcpserver.NewMemoryServer()
+	result, err := memory.CallTool(ctx, "set", json.RawMessage(`{"key":"plan","value":"..."}`))
+
+	err = memory.Serve(ctx, conn, conn)
+	http.Handle("/mcp", memory)
+*/
+package mcpserver
@@ -0,0 +1,144 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultFetchMaxBytes is the default response size limit of the fetch server.
+const DefaultFetchMaxBytes = 1 << 20
+
+// FetchConfig configures the fetch server.
+type FetchConfig struct {
+	// AllowedHosts lists the hosts that may be fetched, either exact names
+	// ("example.com") or subdomain wildcards ("*.example.com"). It is required;
+	// the fetch server never reaches arbitrary hosts.
+	AllowedHosts []string
+
+	// MaxBytes limits the response body returned to the model (default DefaultFetchMaxBytes)
+	MaxBytes int64
+
+	// Client performs the requests (default: a client with a 30s timeout)
+	Client *http.Client
+}
+
+// NewFetchServer returns a server with a fetch tool that GETs http and https
+// URLs on the allowed hosts and returns the response body as text.
+func NewFetchServer(config FetchConfig) (*Server, error) {
+	if len(config.AllowedHosts) == 0 {
+		return nil, fmt.Errorf("fetch server requires at least one allowed host")
+	}
+
+	fetcher := &fetchTool{
+		allowed:  make([]string, 0, len(config.AllowedHosts)),
+		maxBytes: config.MaxBytes,
+		client:   config.Client,
+	}
+	for _, host := range config.AllowedHosts {
+		fetcher.allowed = append(fetcher.allowed, strings.ToLower(strings.TrimSpace(host)))
+	}
+	if fetcher.maxBytes <= 0 {
+		fetcher.maxBytes = DefaultFetchMaxBytes
+	}
+	if fetcher.client == nil {
+		fetcher.client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	// Redirects must stay on allowed hosts too
+	client := *fetcher.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return fetcher.check(req.URL)
+	}
+	fetcher.client = &client
+
+	server := New("fetch", "1.0.0")
+	server.AddTool(Tool{
+		Name:        "fetch",
+		Description: "Fetch a URL and return its content. Only allowed hosts can be fetched: " + strings.Join(fetcher.allowed, ", "),
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"url":{"type":"string","description":"http or https URL to fetch"}},"required":["url"]}`),
+		Handler:     fetcher.fetch,
+	})
+
+	return server, nil
+}
+
+// fetchTool implements the fetch server's tool.
+type fetchTool struct {
+	allowed  []string
+	maxBytes int64
+	client   *http.Client
+}
+
+// check rejects URLs that are not http(s) or not on an allowed host.
+func (f *fetchTool) check(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range f.allowed {
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return nil
+			}
+		} else if host == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("host %s is not allowed", host)
+}
+
+func (f *fetchTool) fetch(ctx context.Context, arguments json.RawMessage) (string, error) {
+	var args struct {
+		URL string `json:"url"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(args.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := f.check(u); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }() // Ignore error, response body close
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return "", err
+	}
+	truncated := int64(len(body)) > f.maxBytes
+	if truncated {
+		body = body[:f.maxBytes]
+	}
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("%s returned %s: %s", u.Redacted(), resp.Status, body)
+	}
+
+	text := string(body)
+	if truncated {
+		text += fmt.Sprintf("\n\n[content truncated at %d bytes]", f.maxBytes)
+	}
+	return text, nil
+}
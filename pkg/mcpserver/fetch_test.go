@@ -0,0 +1,91 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFetchServer(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			fmt.Fprint(w, strings.Repeat("x", 100))
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			fmt.Fprint(w, "hello from "+r.URL.Path)
+		}
+	}))
+	defer target.Close()
+
+	// Redirects to a host outside the allowlist must be refused
+	redirector := httptest.NewServer(http.RedirectHandler("http://blocked.invalid/", http.StatusFound))
+	defer redirector.Close()
+
+	host := func(raw string) string {
+		u, _ := url.Parse(raw)
+		return u.Hostname()
+	}
+	if host(target.URL) != host(redirector.URL) {
+		t.Fatal("Expected test servers on the same host")
+	}
+
+	server, err := NewFetchServer(FetchConfig{AllowedHosts: []string{host(target.URL)}, MaxBytes: 50})
+	if err != nil {
+		t.Fatalf("NewFetchServer failed: %v", err)
+	}
+
+	fetch := func(u string) *ToolResult {
+		t.Helper()
+		arguments, _ := json.Marshal(map[string]string{"url": u})
+		result, err := server.CallTool(context.Background(), "fetch", arguments)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		return result
+	}
+
+	if result := fetch(target.URL + "/page"); result.IsError || result.Text() != "hello from /page" {
+		t.Errorf("Unexpected fetch result: %+v", result)
+	}
+
+	if result := fetch(target.URL + "/large"); result.IsError || !strings.HasPrefix(result.Text(), strings.Repeat("x", 50)+"\n\n[content truncated") {
+		t.Errorf("Expected truncated content, got %+v", result)
+	}
+
+	for _, u := range []string{target.URL + "/missing", "https://example.com/", "file:///etc/passwd", redirector.URL} {
+		if result := fetch(u); !result.IsError {
+			t.Errorf("Expected fetch(%s) to fail, got %+v", u, result)
+		}
+	}
+
+	if _, err := NewFetchServer(FetchConfig{}); err == nil {
+		t.Error("Expected error without allowed hosts")
+	}
+}
+
+func TestFetchTool_Check(t *testing.T) {
+	fetcher := &fetchTool{allowed: []string{"example.com", "*.github.com"}}
+
+	tests := map[string]bool{
+		"https://example.com/a":         true,
+		"http://EXAMPLE.com:8080/":      true,
+		"https://api.github.com/repos":  true,
+		"https://github.com/":           false,
+		"https://evilexample.com/":      false,
+		"https://example.com.evil.net/": false,
+		"ftp://example.com/":            false,
+	}
+	for raw, allowed := range tests {
+		u, _ := url.Parse(raw)
+		if err := fetcher.check(u); (err == nil) != allowed {
+			t.Errorf("check(%s) = %v, expected allowed=%v", raw, err, allowed)
+		}
+	}
+}
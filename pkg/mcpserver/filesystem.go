@@ -0,0 +1,241 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MaxReadFileSize is the largest file the filesystem server will return
+	MaxReadFileSize = 1 << 20
+
+	// maxSearchResults bounds search_files output
+	maxSearchResults = 1000
+)
+
+// NewFilesystemServer returns a read-only filesystem server confined to root.
+// It provides read_file, list_directory, search_files and get_file_info;
+// paths are relative to root, and paths (or symlinks) that escape it are
+// rejected.
+func NewFilesystemServer(root string) (*Server, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	absRoot, err = filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("filesystem root %s is not a directory", root)
+	}
+
+	files := &filesystemTools{root: absRoot}
+	server := New("filesystem", "1.0.0")
+
+	server.AddTool(Tool{
+		Name:        "read_file",
+		Description: "Read the contents of a text file",
+		InputSchema: pathSchema("File to read, relative to the root"),
+		Handler:     files.readFile,
+	})
+	server.AddTool(Tool{
+		Name:        "list_directory",
+		Description: "List the entries of a directory",
+		InputSchema: pathSchema("Directory to list, relative to the root"),
+		Handler:     files.listDirectory,
+	})
+	server.AddTool(Tool{
+		Name:        "get_file_info",
+		Description: "Get the size, type and modification time of a file or directory",
+		InputSchema: pathSchema("File or directory, relative to the root"),
+		Handler:     files.fileInfo,
+	})
+	server.AddTool(Tool{
+		Name:        "search_files",
+		Description: "Recursively find files whose name matches a glob pattern",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"path":{"type":"string","description":"Directory to search, relative to the root"},` +
+			`"pattern":{"type":"string","description":"Glob pattern matched against file names, e.g. *.go"}},` +
+			`"required":["pattern"]}`),
+		Handler: files.searchFiles,
+	})
+
+	return server, nil
+}
+
+// pathSchema is the input schema of tools taking a single path.
+func pathSchema(description string) json.RawMessage {
+	return json.RawMessage(`{"type":"object","properties":{"path":{"type":"string","description":` +
+		mustQuote(description) + `}},"required":["path"]}`)
+}
+
+func mustQuote(s string) string {
+	quoted, _ := json.Marshal(s) // Ignore error, strings always marshal
+	return string(quoted)
+}
+
+// filesystemTools implements the filesystem server's tools.
+type filesystemTools struct {
+	root string
+}
+
+// pathArguments are the arguments of tools taking a path.
+type pathArguments struct {
+	Path    string `json:"path"`
+	Pattern string `json:"pattern"`
+}
+
+// resolve maps a path argument to an absolute path inside the root.
+func (f *filesystemTools) resolve(path string) (string, error) {
+	if path == "" {
+		path = "."
+	}
+
+	full := path
+	if !filepath.IsAbs(full) {
+		full = filepath.Join(f.root, path)
+	}
+	full = filepath.Clean(full)
+
+	resolved, err := filepath.EvalSymlinks(full)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%s does not exist", path)
+		}
+		return "", err
+	}
+
+	if resolved != f.root && !strings.HasPrefix(resolved, f.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("access denied: %s is outside the allowed directory", path)
+	}
+	return resolved, nil
+}
+
+func (f *filesystemTools) readFile(_ context.Context, arguments json.RawMessage) (string, error) {
+	var args pathArguments
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+
+	path, err := f.resolve(args.Path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", args.Path)
+	}
+	if info.Size() > MaxReadFileSize {
+		return "", fmt.Errorf("%s is too large (%d bytes, limit %d)", args.Path, info.Size(), MaxReadFileSize)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is confined to the server root
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (f *filesystemTools) listDirectory(_ context.Context, arguments json.RawMessage) (string, error) {
+	var args pathArguments
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+
+	path, err := f.resolve(args.Path)
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	for _, entry := range entries {
+		kind := "[FILE]"
+		if entry.IsDir() {
+			kind = "[DIR]"
+		}
+		fmt.Fprintf(&out, "%s %s\n", kind, entry.Name())
+	}
+	return out.String(), nil
+}
+
+func (f *filesystemTools) fileInfo(_ context.Context, arguments json.RawMessage) (string, error) {
+	var args pathArguments
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+
+	path, err := f.resolve(args.Path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	kind := "file"
+	if info.IsDir() {
+		kind = "directory"
+	}
+	return fmt.Sprintf("type: %s\nsize: %d\nmodified: %s\npermissions: %s\n",
+		kind, info.Size(), info.ModTime().UTC().Format("2006-01-02T15:04:05Z"), info.Mode().Perm()), nil
+}
+
+func (f *filesystemTools) searchFiles(ctx context.Context, arguments json.RawMessage) (string, error) {
+	var args pathArguments
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+	if _, err := filepath.Match(args.Pattern, ""); err != nil || args.Pattern == "" {
+		return "", fmt.Errorf("invalid pattern %q", args.Pattern)
+	}
+
+	start, err := f.resolve(args.Path)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []string
+	err = filepath.WalkDir(start, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if matched, _ := filepath.Match(args.Pattern, entry.Name()); matched && path != start {
+			rel, _ := filepath.Rel(f.root, path) // Ignore error, path is inside the root
+			matches = append(matches, filepath.ToSlash(rel))
+			if len(matches) >= maxSearchResults {
+				return fs.SkipAll
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(matches) == 0 {
+		return "No matches found", nil
+	}
+	return strings.Join(matches, "\n"), nil
+}
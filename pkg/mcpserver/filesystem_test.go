@@ -0,0 +1,82 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilesystemServer(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "pkg", "sub"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"README.md":         "# readme",
+		"pkg/main.go":       "package main",
+		"pkg/sub/helper.go": "package sub",
+		"pkg/sub/notes.txt": "notes",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret"), filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := NewFilesystemServer(root)
+	if err != nil {
+		t.Fatalf("NewFilesystemServer failed: %v", err)
+	}
+
+	call := func(tool, arguments string) *ToolResult {
+		t.Helper()
+		result, err := server.CallTool(context.Background(), tool, json.RawMessage(arguments))
+		if err != nil {
+			t.Fatalf("CallTool(%s) failed: %v", tool, err)
+		}
+		return result
+	}
+
+	if result := call("read_file", `{"path":"pkg/main.go"}`); result.IsError || result.Text() != "package main" {
+		t.Errorf("Unexpected read_file result: %+v", result)
+	}
+
+	if result := call("list_directory", `{"path":"pkg"}`); result.IsError ||
+		!strings.Contains(result.Text(), "[FILE] main.go") || !strings.Contains(result.Text(), "[DIR] sub") {
+		t.Errorf("Unexpected list_directory result: %+v", result)
+	}
+
+	if result := call("search_files", `{"pattern":"*.go"}`); result.IsError || result.Text() != "pkg/main.go\npkg/sub/helper.go" {
+		t.Errorf("Unexpected search_files result: %+v", result)
+	}
+
+	if result := call("get_file_info", `{"path":"README.md"}`); result.IsError || !strings.Contains(result.Text(), "size: 8") {
+		t.Errorf("Unexpected get_file_info result: %+v", result)
+	}
+
+	for _, arguments := range []string{
+		`{"path":"../` + filepath.Base(outside) + `/secret"}`,
+		`{"path":"` + filepath.Join(outside, "secret") + `"}`,
+		`{"path":"escape"}`,
+		`{"path":"pkg"}`,
+		`{"path":"missing.txt"}`,
+	} {
+		if result := call("read_file", arguments); !result.IsError || strings.Contains(result.Text(), "secret\n") || result.Text() == "secret" {
+			t.Errorf("Expected read_file(%s) to fail, got %+v", arguments, result)
+		}
+	}
+
+	if _, err := NewFilesystemServer(filepath.Join(root, "README.md")); err == nil {
+		t.Error("Expected error for a file root")
+	}
+}
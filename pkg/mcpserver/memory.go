@@ -0,0 +1,135 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NewMemoryServer returns a key-value memory server with set, get, delete and
+// list tools. Values live in process memory for the lifetime of the server,
+// letting the model keep notes across turns of a session.
+func NewMemoryServer() *Server {
+	memory := &memoryTools{values: make(map[string]string)}
+	server := New("memory", "1.0.0")
+
+	keySchema := json.RawMessage(`{"type":"object","properties":{"key":{"type":"string"}},"required":["key"]}`)
+
+	server.AddTool(Tool{
+		Name:        "set",
+		Description: "Store a value under a key, replacing any previous value",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"key":{"type":"string"},"value":{"type":"string"}},"required":["key","value"]}`),
+		Handler:     memory.set,
+	})
+	server.AddTool(Tool{
+		Name:        "get",
+		Description: "Retrieve the value stored under a key",
+		InputSchema: keySchema,
+		Handler:     memory.get,
+	})
+	server.AddTool(Tool{
+		Name:        "delete",
+		Description: "Delete the value stored under a key",
+		InputSchema: keySchema,
+		Handler:     memory.delete,
+	})
+	server.AddTool(Tool{
+		Name:        "list",
+		Description: "List the stored keys, optionally only those with a prefix",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"prefix":{"type":"string"}}}`),
+		Handler:     memory.list,
+	})
+
+	return server
+}
+
+// memoryTools implements the memory server's tools.
+type memoryTools struct {
+	values map[string]string
+	mu     sync.RWMutex
+}
+
+// memoryArguments are the arguments of the memory tools.
+type memoryArguments struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Prefix string `json:"prefix"`
+}
+
+func (m *memoryTools) arguments(raw json.RawMessage, needKey bool) (memoryArguments, error) {
+	var args memoryArguments
+	if err := decodeArguments(raw, &args); err != nil {
+		return args, err
+	}
+	if needKey && args.Key == "" {
+		return args, fmt.Errorf("key is required")
+	}
+	return args, nil
+}
+
+func (m *memoryTools) set(_ context.Context, raw json.RawMessage) (string, error) {
+	args, err := m.arguments(raw, true)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[args.Key] = args.Value
+	return "Stored " + args.Key, nil
+}
+
+func (m *memoryTools) get(_ context.Context, raw json.RawMessage) (string, error) {
+	args, err := m.arguments(raw, true)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, ok := m.values[args.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found", args.Key)
+	}
+	return value, nil
+}
+
+func (m *memoryTools) delete(_ context.Context, raw json.RawMessage) (string, error) {
+	args, err := m.arguments(raw, true)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.values[args.Key]; !ok {
+		return "", fmt.Errorf("key %s not found", args.Key)
+	}
+	delete(m.values, args.Key)
+	return "Deleted " + args.Key, nil
+}
+
+func (m *memoryTools) list(_ context.Context, raw json.RawMessage) (string, error) {
+	args, err := m.arguments(raw, false)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]string, 0, len(m.values))
+	for key := range m.values {
+		if strings.HasPrefix(key, args.Prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		return "No keys stored", nil
+	}
+	return strings.Join(keys, "\n"), nil
+}
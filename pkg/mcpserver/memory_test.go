@@ -0,0 +1,45 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestMemoryServer(t *testing.T) {
+	server := NewMemoryServer()
+
+	call := func(tool, arguments string) *ToolResult {
+		t.Helper()
+		result, err := server.CallTool(context.Background(), tool, json.RawMessage(arguments))
+		if err != nil {
+			t.Fatalf("CallTool(%s) failed: %v", tool, err)
+		}
+		return result
+	}
+
+	call("set", `{"key":"plan/step1","value":"write tests"}`)
+	call("set", `{"key":"plan/step2","value":"fix bugs"}`)
+	call("set", `{"key":"owner","value":"team"}`)
+
+	if result := call("get", `{"key":"plan/step1"}`); result.IsError || result.Text() != "write tests" {
+		t.Errorf("Unexpected get result: %+v", result)
+	}
+	if result := call("list", `{"prefix":"plan/"}`); result.Text() != "plan/step1\nplan/step2" {
+		t.Errorf("Unexpected list result: %+v", result)
+	}
+
+	call("delete", `{"key":"plan/step1"}`)
+	if result := call("get", `{"key":"plan/step1"}`); !result.IsError {
+		t.Errorf("Expected deleted key to be missing, got %+v", result)
+	}
+	if result := call("delete", `{"key":"plan/step1"}`); !result.IsError {
+		t.Errorf("Expected error deleting a missing key, got %+v", result)
+	}
+	if result := call("set", `{"value":"orphan"}`); !result.IsError {
+		t.Errorf("Expected error without a key, got %+v", result)
+	}
+	if result := call("list", `{}`); result.Text() != "owner\nplan/step2" {
+		t.Errorf("Unexpected list result: %+v", result)
+	}
+}
@@ -0,0 +1,57 @@
+package mcpserver
+
+import "context"
+
+// Progress is a progress update reported by a running tool.
+type Progress struct {
+	// Tool is the name of the reporting tool, filled in by the server
+	Tool string `json:"tool"`
+
+	// Percent is the completion percentage from 0 to 100, or -1 when the
+	// update is only a log line
+	Percent float64 `json:"percent"`
+
+	// Message describes the current step
+	Message string `json:"message,omitempty"`
+}
+
+// ProgressFunc receives progress updates from tool handlers.
+type ProgressFunc func(progress Progress)
+
+type progressKey struct{}
+
+// progressReporter is stored in a tool call's context.
+type progressReporter struct {
+	tool   string
+	report ProgressFunc
+}
+
+// WithProgress returns a context whose tool calls deliver progress updates to
+// report. Servers embedding tools use it to surface progress to their users.
+func WithProgress(ctx context.Context, report ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, &progressReporter{report: report})
+}
+
+// withTool names the tool that reports progress through ctx.
+func withTool(ctx context.Context, tool string) context.Context {
+	reporter, ok := ctx.Value(progressKey{}).(*progressReporter)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressReporter{tool: tool, report: reporter.report})
+}
+
+// ReportProgress reports a tool's completion percentage (0 to 100) and current
+// step from within its handler. It does nothing when the caller does not
+// collect progress.
+func ReportProgress(ctx context.Context, percent float64, message string) {
+	if reporter, ok := ctx.Value(progressKey{}).(*progressReporter); ok {
+		reporter.report(Progress{Tool: reporter.tool, Percent: percent, Message: message})
+	}
+}
+
+// ReportLog reports a log line from within a tool handler without a
+// completion percentage.
+func ReportLog(ctx context.Context, message string) {
+	ReportProgress(ctx, -1, message)
+}
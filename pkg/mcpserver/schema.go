@@ -0,0 +1,172 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema returns the JSON Schema of a struct's JSON encoding, for use as a
+// tool's InputSchema. Property names follow the `json` tag, fields tagged
+// omitempty are optional and all others are required, and a `description`
+// tag documents the property:
+//
+//	type lookupArguments struct {
+//		OrderID string `json:"order_id" description:"Order to look up"`
+//		Verbose bool   `json:"verbose,omitempty"`
+//	}
+func Schema(v any) (json.RawMessage, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("tool arguments must be a struct, got %v", t)
+	}
+
+	schema, err := typeSchema(t, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(schema)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeSchema builds the schema of a type. seen guards against recursive
+// struct types, which JSON Schema cannot describe without references.
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) (map[string]any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		// Custom decoding accepts whatever the type decides
+		return map[string]any{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Interface:
+		return map[string]any{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := typeSchema(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map %v must have string keys", t)
+		}
+		values, err := typeSchema(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		return structSchema(t, seen)
+	}
+
+	return nil, fmt.Errorf("unsupported argument type %v", t)
+}
+
+// structSchema builds the object schema of a struct's exported fields.
+func structSchema(t reflect.Type, seen map[reflect.Type]bool) (map[string]any, error) {
+	if seen[t] {
+		return nil, fmt.Errorf("recursive argument type %v", t)
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	properties := map[string]any{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property, err := typeSchema(field.Type, seen)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			property["description"] = description
+		}
+		properties[name] = property
+
+		if !strings.Contains(","+options+",", ",omitempty,") && field.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// NewTool creates a tool whose arguments are decoded into In, a struct whose
+// schema is derived with Schema. Missing required arguments are rejected
+// before handler runs.
+func NewTool[In any](name, description string, handler func(ctx context.Context, arguments In) (string, error)) (Tool, error) {
+	var zero In
+	schema, err := Schema(zero)
+	if err != nil {
+		return Tool{}, fmt.Errorf("tool %s: %w", name, err)
+	}
+
+	var required []string
+	var parsed struct {
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(schema, &parsed); err == nil {
+		required = parsed.Required
+	}
+
+	return Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: schema,
+		Handler: func(ctx context.Context, raw json.RawMessage) (string, error) {
+			var present map[string]json.RawMessage
+			if err := decodeArguments(raw, &present); err != nil {
+				return "", err
+			}
+			for _, field := range required {
+				if _, ok := present[field]; !ok {
+					return "", fmt.Errorf("%s is required", field)
+				}
+			}
+
+			var arguments In
+			if err := decodeArguments(raw, &arguments); err != nil {
+				return "", err
+			}
+			return handler(ctx, arguments)
+		},
+	}, nil
+}
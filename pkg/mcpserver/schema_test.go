@@ -0,0 +1,78 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type orderArguments struct {
+	OrderID  string            `json:"order_id" description:"Order to look up"`
+	Quantity int               `json:"quantity,omitempty"`
+	Price    float64           `json:"price"`
+	Rush     bool              `json:"rush"`
+	Tags     []string          `json:"tags,omitempty"`
+	Notes    map[string]string `json:"notes,omitempty"`
+	Due      *time.Time        `json:"due"`
+	Address  struct {
+		City string `json:"city"`
+	} `json:"address"`
+	Internal string `json:"-"`
+	secret   string
+}
+
+func TestSchema(t *testing.T) {
+	schema, err := Schema(orderArguments{})
+	if err != nil {
+		t.Fatalf("Schema failed: %v", err)
+	}
+
+	want := `{"properties":{"address":{"properties":{"city":{"type":"string"}},"required":["city"],"type":"object"},` +
+		`"due":{"format":"date-time","type":"string"},` +
+		`"notes":{"additionalProperties":{"type":"string"},"type":"object"},` +
+		`"order_id":{"description":"Order to look up","type":"string"},` +
+		`"price":{"type":"number"},"quantity":{"type":"integer"},"rush":{"type":"boolean"},` +
+		`"tags":{"items":{"type":"string"},"type":"array"}},` +
+		`"required":["order_id","price","rush","address"],"type":"object"}`
+	if string(schema) != want {
+		t.Errorf("Unexpected schema:\n got %s\nwant %s", schema, want)
+	}
+
+	type node struct {
+		Children []node `json:"children"`
+	}
+	for _, v := range []any{"not a struct", node{}, struct{ C chan int }{}} {
+		if _, err := Schema(v); err == nil {
+			t.Errorf("Expected Schema(%T) to fail", v)
+		}
+	}
+}
+
+func TestNewTool(t *testing.T) {
+	tool, err := NewTool("lookup_order", "Look up an order", func(_ context.Context, args orderArguments) (string, error) {
+		return fmt.Sprintf("%s x%d", args.OrderID, args.Quantity), nil
+	})
+	if err != nil {
+		t.Fatalf("NewTool failed: %v", err)
+	}
+
+	server := New("orders", "1.0.0")
+	server.AddTool(tool)
+
+	result, err := server.CallTool(context.Background(), "lookup_order",
+		json.RawMessage(`{"order_id":"A-1","quantity":2,"price":9.5,"rush":false,"address":{"city":"Oslo"}}`))
+	if err != nil || result.IsError || result.Text() != "A-1 x2" {
+		t.Errorf("Unexpected result %+v (%v)", result, err)
+	}
+
+	result, _ = server.CallTool(context.Background(), "lookup_order", json.RawMessage(`{"order_id":"A-1"}`))
+	if !result.IsError || result.Text() != "price is required" {
+		t.Errorf("Expected missing argument error, got %+v", result)
+	}
+
+	if _, err := NewTool("bad", "", func(context.Context, string) (string, error) { return "", nil }); err == nil {
+		t.Error("Expected NewTool to reject non-struct arguments")
+	}
+}
@@ -0,0 +1,277 @@
+package mcpserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ProtocolVersion is the MCP revision implemented by Server.
+const ProtocolVersion = "2024-11-05"
+
+// JSON-RPC error codes used by Server.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// ErrUnknownTool is returned by CallTool for tools the server does not provide.
+var ErrUnknownTool = errors.New("unknown tool")
+
+// ToolHandler executes a tool call with its raw JSON arguments and returns the
+// text result. Returned errors are reported to the model as tool errors.
+type ToolHandler func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+// Tool is a tool served by a Server.
+type Tool struct {
+	// Name is the tool name
+	Name string `json:"name"`
+
+	// Description explains what the tool does
+	Description string `json:"description,omitempty"`
+
+	// InputSchema is the JSON Schema of the tool's arguments
+	InputSchema json.RawMessage `json:"inputSchema"`
+
+	// Handler executes the tool
+	Handler ToolHandler `json:"-"`
+}
+
+// ToolResult is the result of a tool call.
+type ToolResult struct {
+	// Content holds the result as MCP content blocks
+	Content []Content `json:"content"`
+
+	// IsError reports whether the tool failed
+	IsError bool `json:"isError,omitempty"`
+
+	// Interrupted reports whether the tool failed because its context was
+	// cancelled
+	Interrupted bool `json:"-"`
+}
+
+// Content is an MCP text content block.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Text returns the concatenated text of the result's content.
+func (r *ToolResult) Text() string {
+	var text string
+	for _, content := range r.Content {
+		text += content.Text
+	}
+	return text
+}
+
+// Server is a minimal MCP server offering tools over newline-delimited
+// JSON-RPC, the MCP stdio transport, or over HTTP as an http.Handler. Tools
+// can also be called in-process with CallTool.
+type Server struct {
+	name    string
+	version string
+
+	tools map[string]Tool
+	mu    sync.RWMutex
+}
+
+// New creates an empty server that identifies itself with name and version.
+func New(name, version string) *Server {
+	return &Server{
+		name:    name,
+		version: version,
+		tools:   make(map[string]Tool),
+	}
+}
+
+// Name returns the server name.
+func (s *Server) Name() string {
+	return s.name
+}
+
+// AddTool registers a tool, replacing any tool with the same name.
+func (s *Server) AddTool(tool Tool) {
+	if len(tool.InputSchema) == 0 {
+		tool.InputSchema = json.RawMessage(`{"type":"object"}`)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools[tool.Name] = tool
+}
+
+// Tools returns the registered tools sorted by name.
+func (s *Server) Tools() []Tool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(s.tools))
+	for _, tool := range s.tools {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// CallTool runs a tool in-process. Tool failures are reported in the result
+// with IsError set; only unknown tools return an error. A handler that fails
+// after ctx is cancelled is reported as interrupted.
+func (s *Server) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*ToolResult, error) {
+	s.mu.RLock()
+	tool, ok := s.tools[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTool, name)
+	}
+
+	if len(arguments) == 0 {
+		arguments = json.RawMessage(`{}`)
+	}
+
+	text, err := tool.Handler(withTool(ctx, name), arguments)
+	if err != nil && ctx.Err() != nil {
+		return &ToolResult{Content: []Content{{Type: "text", Text: "tool call interrupted: " + err.Error()}}, IsError: true, Interrupted: true}, nil
+	}
+	if err != nil {
+		return &ToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return &ToolResult{Content: []Content{{Type: "text", Text: text}}}, nil
+}
+
+// rpcMessage is an incoming JSON-RPC request or notification.
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcError is a JSON-RPC error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve answers MCP requests read from r, writing responses to w, until r is
+// exhausted or ctx is cancelled. Requests are handled one at a time.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		response := s.respond(ctx, line)
+		if response == nil {
+			continue
+		}
+		if err := encoder.Encode(response); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// respond handles one JSON-RPC message and returns the response, or nil for
+// notifications such as notifications/initialized, which need none.
+func (s *Server) respond(ctx context.Context, data []byte) map[string]any {
+	var message rpcMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return map[string]any{"jsonrpc": "2.0", "id": nil, "error": rpcError{Code: codeParseError, Message: "parse error"}}
+	}
+	if len(message.ID) == 0 {
+		return nil
+	}
+
+	response := map[string]any{"jsonrpc": "2.0", "id": message.ID}
+	if result, rpcErr := s.handle(ctx, message); rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	return response
+}
+
+// ServeHTTP answers MCP requests over the streamable HTTP transport: each POST
+// carries one JSON-RPC message and receives a JSON response. Tool calls run
+// with the request's context, so they are cancelled if the client goes away.
+// Server-initiated streams are not supported.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, 16*1024*1024))
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	response := s.respond(r.Context(), data)
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response) // Ignore error, the client sees a broken response
+}
+
+// handle dispatches a request to its method.
+func (s *Server) handle(ctx context.Context, message rpcMessage) (any, *rpcError) {
+	switch message.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": s.name, "version": s.version},
+		}, nil
+
+	case "ping":
+		return map[string]any{}, nil
+
+	case "tools/list":
+		return map[string]any{"tools": s.Tools()}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(message.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid tools/call params"}
+		}
+		result, err := s.CallTool(ctx, params.Name, params.Arguments)
+		if err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return result, nil
+	}
+
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + message.Method}
+}
+
+// decodeArguments unmarshals tool arguments, rejecting malformed input.
+func decodeArguments(arguments json.RawMessage, v any) error {
+	if err := json.Unmarshal(arguments, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
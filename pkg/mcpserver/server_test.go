@@ -0,0 +1,195 @@
+package mcpserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newEchoServer() *Server {
+	server := New("echo", "0.1.0")
+	server.AddTool(Tool{
+		Name:        "echo",
+		Description: "Echo the message",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"message":{"type":"string"}}}`),
+		Handler: func(_ context.Context, arguments json.RawMessage) (string, error) {
+			var args struct {
+				Message string `json:"message"`
+			}
+			if err := decodeArguments(arguments, &args); err != nil {
+				return "", err
+			}
+			if args.Message == "" {
+				return "", errors.New("message is required")
+			}
+			return args.Message, nil
+		},
+	})
+	return server
+}
+
+func TestServer_Serve(t *testing.T) {
+	server := newEchoServer()
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"message":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"missing"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"resources/list"}`,
+		`not json`,
+	}, "\n")
+
+	var output strings.Builder
+	if err := server.Serve(context.Background(), strings.NewReader(input), &output); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	var responses []map[string]json.RawMessage
+	scanner := bufio.NewScanner(strings.NewReader(output.String()))
+	for scanner.Scan() {
+		var response map[string]json.RawMessage
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			t.Fatalf("Invalid response %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, response)
+	}
+	if len(responses) != 7 {
+		t.Fatalf("Expected 7 responses (notification unanswered), got %d:\n%s", len(responses), output.String())
+	}
+
+	if !strings.Contains(string(responses[0]["result"]), `"protocolVersion":"2024-11-05"`) ||
+		!strings.Contains(string(responses[0]["result"]), `"name":"echo"`) {
+		t.Errorf("Unexpected initialize result: %s", responses[0]["result"])
+	}
+
+	var list struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := json.Unmarshal(responses[1]["result"], &list); err != nil || len(list.Tools) != 1 || list.Tools[0].Name != "echo" {
+		t.Errorf("Unexpected tools/list result: %s", responses[1]["result"])
+	}
+
+	var result ToolResult
+	if err := json.Unmarshal(responses[2]["result"], &result); err != nil || result.IsError || result.Text() != "hi" {
+		t.Errorf("Unexpected tools/call result: %s", responses[2]["result"])
+	}
+
+	result = ToolResult{}
+	if err := json.Unmarshal(responses[3]["result"], &result); err != nil || !result.IsError || result.Text() != "message is required" {
+		t.Errorf("Expected tool error result, got %s", responses[3]["result"])
+	}
+
+	for i, code := range map[int]string{4: "-32602", 5: "-32601", 6: "-32700"} {
+		if !strings.Contains(string(responses[i]["error"]), `"code":`+code) {
+			t.Errorf("Response %d: expected error code %s, got %s", i, code, responses[i]["error"])
+		}
+	}
+}
+
+func TestServer_CallTool(t *testing.T) {
+	server := newEchoServer()
+
+	result, err := server.CallTool(context.Background(), "echo", json.RawMessage(`{"message":"direct"}`))
+	if err != nil || result.Text() != "direct" {
+		t.Errorf("Unexpected result %+v (%v)", result, err)
+	}
+
+	if _, err := server.CallTool(context.Background(), "missing", nil); !errors.Is(err, ErrUnknownTool) {
+		t.Errorf("Expected ErrUnknownTool, got %v", err)
+	}
+
+	server.AddTool(Tool{Name: "noop", Handler: func(context.Context, json.RawMessage) (string, error) { return "", nil }})
+	tools := server.Tools()
+	if len(tools) != 2 || tools[0].Name != "echo" || string(tools[1].InputSchema) != `{"type":"object"}` {
+		t.Errorf("Unexpected tools: %+v", tools)
+	}
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	server := httptest.NewServer(newEchoServer())
+	defer server.Close()
+
+	post := func(body string) (*http.Response, string) {
+		response, err := http.Post(server.URL, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		defer response.Body.Close()
+		var text strings.Builder
+		_, _ = io.Copy(&text, response.Body)
+		return response, text.String()
+	}
+
+	response, body := post(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"message":"over http"}}}`)
+	if response.StatusCode != http.StatusOK || !strings.Contains(body, `"text":"over http"`) {
+		t.Errorf("Unexpected tools/call response %d: %s", response.StatusCode, body)
+	}
+
+	if response, _ := post(`{"jsonrpc":"2.0","method":"notifications/initialized"}`); response.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected notifications to be accepted, got %d", response.StatusCode)
+	}
+
+	get, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	get.Body.Close()
+	if get.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET to be rejected, got %d", get.StatusCode)
+	}
+}
+
+func TestServer_Progress(t *testing.T) {
+	server := New("slow", "1.0.0")
+	server.AddTool(Tool{
+		Name: "index",
+		Handler: func(ctx context.Context, _ json.RawMessage) (string, error) {
+			ReportProgress(ctx, 50, "half way")
+			ReportLog(ctx, "scanned 10 files")
+			return "done", nil
+		},
+	})
+
+	// Without a receiver progress is dropped
+	if _, err := server.CallTool(context.Background(), "index", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	var updates []Progress
+	ctx := WithProgress(context.Background(), func(progress Progress) { updates = append(updates, progress) })
+	if _, err := server.CallTool(ctx, "index", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	want := []Progress{{Tool: "index", Percent: 50, Message: "half way"}, {Tool: "index", Percent: -1, Message: "scanned 10 files"}}
+	if len(updates) != 2 || updates[0] != want[0] || updates[1] != want[1] {
+		t.Errorf("Expected %+v, got %+v", want, updates)
+	}
+}
+
+func TestServer_CallToolInterrupted(t *testing.T) {
+	server := New("slow", "1.0.0")
+	server.AddTool(Tool{
+		Name: "wait",
+		Handler: func(ctx context.Context, _ json.RawMessage) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := server.CallTool(ctx, "wait", nil)
+	if err != nil || !result.IsError || !result.Interrupted || !strings.HasPrefix(result.Text(), "tool call interrupted") {
+		t.Errorf("Expected an interrupted result, got %+v (%v)", result, err)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Package memory gives Claude Code clients a long-term memory of facts learned
+in earlier conversations.
+
+A Manager remembers facts in a pluggable Store and recalls those relevant to
+a prompt. Set it as a client's Memory and the facts relevant to the first
+prompt of each new session are added to that session's system prompt.
+
+# Basic Usage
+
+	store, err := memory.NewFileStore("/var/lib/myapp/memory")
+	if err != nil {
+		log.Fatal(err)
+	}
+	memories := memory.New(store)
+
+	_, err = memories.Remember(ctx, "The staging database is Postgres 15", "infra")
+
+	config := types.NewClaudeCodeConfig()
+	config.Memory = memories
+	claudeClient, err := client.NewClaudeCodeClient(ctx, config)
+
+# Stores
+
+MemoryStore keeps entries in memory and FileStore keeps one JSON file per
+entry. Both rank entries with Rank. Other backends, such as SQLite or an
+external vector database, implement Store; a vector database would typically
+answer Search with its own nearest-neighbour query on Query.Embedding.
+
+# Relevance
+
+Without an Embedder, an entry's score is the fraction of the query's words
+it contains. WithEmbedder embeds entries and queries, so that they are
+ranked by the cosine similarity of their vectors and match by meaning:
+
+	memories := memory.New(store, memory.WithEmbedder(memory.EmbedderFunc(
+		func(ctx context.Context, texts []string) ([][]float32, error) {
+			return embeddingClient.Embed(ctx, texts)
+		},
+	)))
+
+Recall leaves out memories scoring below DefaultMinScore, or the score set
+with WithMinScore.
+*/
+package memory
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// DefaultMinScore is the relevance below which Recall leaves memories out.
+const DefaultMinScore = 0.2
+
+// Embedder turns text into vectors for semantic search, typically by calling
+// an embedding model. Implementations must be safe for concurrent use.
+type Embedder interface {
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedderFunc adapts a function to the Embedder interface.
+type EmbedderFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// Embed calls f.
+func (f EmbedderFunc) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return f(ctx, texts)
+}
+
+// Manager remembers facts in a Store and recalls those relevant to a
+// prompt. It implements types.MemoryRecaller, so that a client configured
+// with it injects relevant memories into new sessions.
+type Manager struct {
+	store    Store
+	embedder Embedder
+	minScore float64
+}
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithEmbedder embeds entries and queries so that they are matched by
+// meaning rather than by shared words.
+func WithEmbedder(embedder Embedder) Option {
+	return func(m *Manager) {
+		m.embedder = embedder
+	}
+}
+
+// WithMinScore sets the relevance below which Recall leaves memories out
+// (default DefaultMinScore).
+func WithMinScore(score float64) Option {
+	return func(m *Manager) {
+		m.minScore = score
+	}
+}
+
+// New creates a manager over store. A nil store keeps memories in memory.
+func New(store Store, options ...Option) *Manager {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	m := &Manager{store: store, minScore: DefaultMinScore}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Store returns the manager's store.
+func (m *Manager) Store() Store {
+	return m.store
+}
+
+// Remember stores a fact with optional tags and returns its entry.
+func (m *Manager) Remember(ctx context.Context, content string, tags ...string) (*Entry, error) {
+	entry := &Entry{Content: content, Tags: tags}
+	if err := m.Add(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Add stores an entry, filling in its ID, creation time and, with an
+// Embedder, its embedding when they are not set.
+func (m *Manager) Add(ctx context.Context, entry *Entry) error {
+	entry.Content = strings.TrimSpace(entry.Content)
+	if entry.Content == "" {
+		return sdkerrors.NewValidationError("content", "", "required", "memory content cannot be empty")
+	}
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if m.embedder != nil && len(entry.Embedding) == 0 {
+		vectors, err := m.embedder.Embed(ctx, []string{entry.Content})
+		if err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_EMBED", "failed to embed memory")
+		}
+		if len(vectors) == 1 {
+			entry.Embedding = vectors[0]
+		}
+	}
+	return m.store.Put(ctx, entry)
+}
+
+// Forget deletes an entry.
+func (m *Manager) Forget(ctx context.Context, id string) error {
+	return m.store.Delete(ctx, id)
+}
+
+// Search returns the entries matching a query, best first. With an Embedder,
+// the query text is embedded unless the query already has an embedding.
+func (m *Manager) Search(ctx context.Context, query Query) ([]Result, error) {
+	if m.embedder != nil && len(query.Embedding) == 0 && strings.TrimSpace(query.Text) != "" {
+		vectors, err := m.embedder.Embed(ctx, []string{query.Text})
+		if err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_EMBED", "failed to embed query")
+		}
+		if len(vectors) == 1 {
+			query.Embedding = vectors[0]
+		}
+	}
+	return m.store.Search(ctx, query)
+}
+
+// Recall returns the content of up to limit memories relevant to text, best
+// first, leaving out those scoring below the manager's minimum.
+func (m *Manager) Recall(ctx context.Context, text string, limit int) ([]string, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	results, err := m.Search(ctx, Query{Text: text, Limit: limit, MinScore: m.minScore})
+	if err != nil {
+		return nil, err
+	}
+	memories := make([]string, 0, len(results))
+	for _, result := range results {
+		memories = append(memories, result.Entry.Content)
+	}
+	return memories, nil
+}
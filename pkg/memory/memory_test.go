@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now()
+
+	second := &Entry{ID: "b", Content: "The API uses gRPC", Tags: []string{"api"}, CreatedAt: now.Add(time.Second)}
+	first := &Entry{ID: "a", Content: "Deploys run on Fridays", CreatedAt: now}
+	for _, entry := range []*Entry{second, first} {
+		if err := store.Put(ctx, entry); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	// Stored entries are copies
+	first.Content = "changed"
+	got, err := store.Get(ctx, "a")
+	if err != nil || got.Content != "Deploys run on Fridays" {
+		t.Errorf("Unexpected entry: %+v (%v)", got, err)
+	}
+
+	entries, err := store.List(ctx)
+	if err != nil || len(entries) != 2 || entries[0].ID != "a" || entries[1].ID != "b" {
+		t.Errorf("Unexpected list: %v (%v)", entries, err)
+	}
+
+	results, err := store.Search(ctx, Query{Text: "which protocol does the API use?"})
+	if err != nil || len(results) != 1 || results[0].Entry.ID != "b" {
+		t.Errorf("Unexpected search results: %v (%v)", results, err)
+	}
+
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Errorf("Deleting a missing entry should succeed: %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	testStore(t, store)
+
+	if err := store.Put(context.Background(), &Entry{ID: "../escape"}); err == nil {
+		t.Error("Expected path-like entry ID to be rejected")
+	}
+
+	// Entries survive reopening the store
+	reopened, _ := NewFileStore(dir)
+	if _, err := reopened.Get(context.Background(), "b"); err != nil {
+		t.Errorf("Expected entry to persist: %v", err)
+	}
+}
+
+func TestRank(t *testing.T) {
+	now := time.Now()
+	entries := []*Entry{
+		{ID: "old", Content: "Use tabs for indentation", Tags: []string{"style"}, CreatedAt: now},
+		{ID: "new", Content: "Use spaces for indentation", Tags: []string{"Style"}, CreatedAt: now.Add(time.Second)},
+		{ID: "other", Content: "Releases are tagged on main", CreatedAt: now},
+	}
+
+	results := Rank(entries, Query{Text: "indentation"})
+	if len(results) != 2 || results[0].Entry.ID != "new" || results[0].Score != 1 {
+		t.Errorf("Expected newest match first, got %v", results)
+	}
+
+	if results := Rank(entries, Query{Tags: []string{"style"}, Limit: 1}); len(results) != 1 || results[0].Entry.ID != "new" {
+		t.Errorf("Expected tag filter and limit, got %v", results)
+	}
+
+	if results := Rank(entries, Query{Text: "indentation releases", MinScore: 0.6}); len(results) != 0 {
+		t.Errorf("Expected partial matches below MinScore to be left out, got %v", results)
+	}
+
+	vectors := []*Entry{
+		{ID: "x", Embedding: []float32{1, 0}},
+		{ID: "y", Embedding: []float32{0.6, 0.8}},
+		{ID: "z", Embedding: []float32{0, 1}},
+	}
+	results = Rank(vectors, Query{Embedding: []float32{0, 1}})
+	if len(results) != 2 || results[0].Entry.ID != "z" || results[1].Entry.ID != "y" {
+		t.Errorf("Expected cosine ranking, got %v", results)
+	}
+}
+
+func TestManagerRecall(t *testing.T) {
+	ctx := context.Background()
+	m := New(nil)
+
+	if _, err := m.Remember(ctx, "  "); err == nil {
+		t.Error("Expected empty memory to be rejected")
+	}
+	entry, err := m.Remember(ctx, "The staging database is Postgres 15", "infra")
+	if err != nil || entry.ID == "" || entry.CreatedAt.IsZero() {
+		t.Fatalf("Unexpected entry: %+v (%v)", entry, err)
+	}
+	_, _ = m.Remember(ctx, "The team prefers table-driven tests")
+
+	facts, err := m.Recall(ctx, "Migrate the staging database", 5)
+	if err != nil || len(facts) != 1 || facts[0] != "The staging database is Postgres 15" {
+		t.Errorf("Unexpected recall: %v (%v)", facts, err)
+	}
+	if facts, _ := m.Recall(ctx, "Write a haiku", 5); len(facts) != 0 {
+		t.Errorf("Expected nothing relevant, got %v", facts)
+	}
+
+	if err := m.Forget(ctx, entry.ID); err != nil {
+		t.Fatalf("Forget failed: %v", err)
+	}
+	if facts, _ := m.Recall(ctx, "staging database", 5); len(facts) != 0 {
+		t.Errorf("Expected forgotten memory to be left out, got %v", facts)
+	}
+}
+
+func TestManagerEmbedder(t *testing.T) {
+	ctx := context.Background()
+
+	// Embed texts mentioning cats and dogs as orthogonal vectors
+	calls := 0
+	embedder := EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		calls++
+		vectors := make([][]float32, len(texts))
+		for i, text := range texts {
+			vectors[i] = []float32{0, 1}
+			if text == "kittens" || text == "Cats need quiet" {
+				vectors[i] = []float32{1, 0}
+			}
+		}
+		return vectors, nil
+	})
+	m := New(NewMemoryStore(), WithEmbedder(embedder), WithMinScore(0.5))
+
+	_, _ = m.Remember(ctx, "Cats need quiet")
+	_, _ = m.Remember(ctx, "Dogs need walks")
+
+	facts, err := m.Recall(ctx, "kittens", 5)
+	if err != nil || len(facts) != 1 || facts[0] != "Cats need quiet" {
+		t.Errorf("Expected semantic match, got %v (%v)", facts, err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 embedding calls, got %d", calls)
+	}
+
+	failing := New(nil, WithEmbedder(EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		return nil, errors.New("offline")
+	})))
+	if _, err := failing.Remember(ctx, "anything"); err == nil {
+		t.Error("Expected embedding failure to be reported")
+	}
+}
@@ -0,0 +1,417 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// ErrNotFound is returned when a memory does not exist.
+var ErrNotFound = errors.New("memory not found")
+
+// Entry is one remembered fact.
+type Entry struct {
+	// ID identifies the entry within its store
+	ID string `json:"id"`
+
+	// Content is the fact in plain text
+	Content string `json:"content"`
+
+	// Tags group entries, such as "preference" or a project name
+	Tags []string `json:"tags,omitempty"`
+
+	// Metadata holds application-defined attributes
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// SessionID is the session the fact was learned in, if any
+	SessionID string `json:"session_id,omitempty"`
+
+	// Embedding is the vector of Content, set when the Manager has an Embedder
+	Embedding []float32 `json:"embedding,omitempty"`
+
+	// CreatedAt is when the entry was stored
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Clone returns a deep copy of the entry.
+func (e *Entry) Clone() *Entry {
+	clone := *e
+	clone.Tags = append([]string(nil), e.Tags...)
+	clone.Embedding = append([]float32(nil), e.Embedding...)
+	if e.Metadata != nil {
+		clone.Metadata = make(map[string]string, len(e.Metadata))
+		for key, value := range e.Metadata {
+			clone.Metadata[key] = value
+		}
+	}
+	return &clone
+}
+
+// Query selects and ranks entries.
+type Query struct {
+	// Text is matched against entry content by shared words
+	Text string
+
+	// Embedding is matched against entry embeddings by cosine similarity; it
+	// takes precedence over Text for entries that have an embedding
+	Embedding []float32
+
+	// Tags restricts the results to entries with all of these tags
+	Tags []string
+
+	// Limit caps the number of results (0 for no limit)
+	Limit int
+
+	// MinScore drops results scoring below it
+	MinScore float64
+}
+
+// Result is an entry matched by a query.
+type Result struct {
+	Entry *Entry
+
+	// Score is the relevance from 0 to 1
+	Score float64
+}
+
+// Store persists memory entries. Implementations must be safe for
+// concurrent use. A vector database is plugged in by implementing Store with
+// a native nearest-neighbour Search; stores without one can use Rank.
+type Store interface {
+	// Put creates or replaces an entry
+	Put(ctx context.Context, entry *Entry) error
+
+	// Get returns an entry, or ErrNotFound
+	Get(ctx context.Context, id string) (*Entry, error)
+
+	// Delete removes an entry. Deleting a missing entry is not an error.
+	Delete(ctx context.Context, id string) error
+
+	// List returns all entries ordered by creation time
+	List(ctx context.Context) ([]*Entry, error)
+
+	// Search returns the entries matching a query, best first
+	Search(ctx context.Context, query Query) ([]Result, error)
+}
+
+// Rank scores entries against a query and returns the matches best first,
+// newest first among equal scores. An entry matches when it has the query's
+// tags and a positive score, or when the query has neither text nor an
+// embedding.
+func Rank(entries []*Entry, query Query) []Result {
+	terms := words(query.Text)
+
+	var results []Result
+	for _, entry := range entries {
+		if !hasTags(entry, query.Tags) {
+			continue
+		}
+
+		score := 1.0
+		switch {
+		case len(query.Embedding) > 0 && len(entry.Embedding) > 0:
+			score = cosine(query.Embedding, entry.Embedding)
+		case len(terms) > 0:
+			score = overlap(terms, words(entry.Content))
+		}
+		if score <= 0 || score < query.MinScore {
+			continue
+		}
+		results = append(results, Result{Entry: entry, Score: score})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Entry.CreatedAt.After(results[j].Entry.CreatedAt)
+	})
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results
+}
+
+// hasTags reports whether an entry has all of the tags.
+func hasTags(entry *Entry, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, have := range entry.Tags {
+			if strings.EqualFold(have, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// stopWords are left out of text matching.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "do": true, "for": true, "from": true, "how": true, "i": true, "in": true,
+	"is": true, "it": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+	"we": true, "what": true, "with": true, "you": true,
+}
+
+// words returns the distinct lower-case words of text, without stop words.
+func words(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !stopWords[word] {
+			set[word] = true
+		}
+	}
+	return set
+}
+
+// overlap returns the fraction of the query terms found in the content.
+func overlap(terms, content map[string]bool) float64 {
+	found := 0
+	for term := range terms {
+		if content[term] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(terms))
+}
+
+// cosine returns the cosine similarity of two vectors, or 0 if their
+// lengths differ.
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// MemoryStore keeps entries in memory. Entries do not survive a restart.
+type MemoryStore struct {
+	entries map[string]*Entry
+	mu      sync.RWMutex
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*Entry)}
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(ctx context.Context, entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.ID] = entry.Clone()
+	return nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return entry.Clone(), nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, id)
+	return nil
+}
+
+// List implements Store.
+func (s *MemoryStore) List(ctx context.Context) ([]*Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]*Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry.Clone())
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// Search implements Store with Rank.
+func (s *MemoryStore) Search(ctx context.Context, query Query) ([]Result, error) {
+	entries, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Rank(entries, query), nil
+}
+
+// FileStore keeps each entry as a JSON file in a directory, so memories
+// survive process restarts.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore creates a store in dir, creating the directory if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "MEMORY_STORE_DIR", "failed to create memory store directory")
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put implements Store. Files are replaced atomically.
+func (s *FileStore) Put(ctx context.Context, entry *Entry) error {
+	path, err := s.path(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_ENCODE", "failed to encode memory")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, ".memory-*")
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_SAVE", "failed to save memory")
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_SAVE", "failed to save memory")
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_SAVE", "failed to save memory")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_SAVE", "failed to save memory")
+	}
+	return nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(ctx context.Context, id string) (*Entry, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return readEntry(path)
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_DELETE", "failed to delete memory")
+	}
+	return nil
+}
+
+// List implements Store.
+func (s *FileStore) List(ctx context.Context) ([]*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_LIST", "failed to list memories")
+	}
+
+	entries := make([]*Entry, 0, len(paths))
+	for _, path := range paths {
+		entry, err := readEntry(path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// Search implements Store with Rank.
+func (s *FileStore) Search(ctx context.Context, query Query) ([]Result, error) {
+	entries, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Rank(entries, query), nil
+}
+
+// path returns the file for an entry, rejecting IDs that could escape the directory.
+func (s *FileStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", sdkerrors.NewValidationError("id", id, "file-safe memory ID", "invalid memory ID")
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+func readEntry(path string) (*Entry, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is inside the store directory
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_READ", "failed to read memory")
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "MEMORY_DECODE", "failed to decode memory "+filepath.Base(path))
+	}
+	return &entry, nil
+}
+
+// sortEntries orders entries by creation time, then ID.
+func sortEntries(entries []*Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+}
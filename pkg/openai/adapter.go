@@ -0,0 +1,124 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/server"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Adapter serves chat completions from an SDK conversation.
+type Adapter struct {
+	conv server.Conversation
+	now  func() time.Time
+}
+
+// NewAdapter creates an adapter for conv, typically a ClaudeCodeClient or session.
+func NewAdapter(conv server.Conversation) *Adapter {
+	return &Adapter{conv: conv, now: time.Now}
+}
+
+// CreateChatCompletion runs a chat completion request.
+func (a *Adapter) CreateChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	request, err := ToQueryRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	request.Stream = false
+
+	response, err := a.conv.Query(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := FromQueryResponse(response, newCompletionID(), a.now().Unix())
+	if completion.Model == "" {
+		completion.Model = req.Model
+	}
+	return completion, nil
+}
+
+// CreateChatCompletionStream runs a chat completion request and streams deltas.
+func (a *Adapter) CreateChatCompletionStream(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionStream, error) {
+	request, err := ToQueryRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	request.Stream = true
+
+	stream, err := a.conv.QueryStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChatCompletionStream{
+		stream:  stream,
+		id:      newCompletionID(),
+		model:   req.Model,
+		created: a.now().Unix(),
+	}, nil
+}
+
+// ChatCompletionStream yields chat completion chunks from an SDK stream.
+type ChatCompletionStream struct {
+	stream   types.QueryStream
+	id       string
+	model    string
+	created  int64
+	sentRole bool
+	finished bool
+}
+
+// Recv returns the next chunk. The final chunk carries a finish reason, after
+// which Recv returns io.EOF.
+func (s *ChatCompletionStream) Recv() (*ChatCompletionChunk, error) {
+	if s.finished {
+		return nil, io.EOF
+	}
+
+	for {
+		chunk, err := s.stream.Recv()
+		if err == io.EOF || (err == nil && (chunk == nil || chunk.Done)) {
+			s.finished = true
+			reason := FinishReasonStop
+			return s.chunk(Delta{}, &reason), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if chunk.Type != types.ChunkTypeContent || chunk.Content == "" {
+			continue
+		}
+
+		delta := Delta{Content: chunk.Content}
+		if !s.sentRole {
+			delta.Role = "assistant"
+			s.sentRole = true
+		}
+		return s.chunk(delta, nil), nil
+	}
+}
+
+// Close releases the underlying stream.
+func (s *ChatCompletionStream) Close() error {
+	return s.stream.Close()
+}
+
+func (s *ChatCompletionStream) chunk(delta Delta, finishReason *string) *ChatCompletionChunk {
+	return &ChatCompletionChunk{
+		ID:      s.id,
+		Object:  ObjectChatCompletionChunk,
+		Created: s.created,
+		Model:   s.model,
+		Choices: []ChunkChoice{{Delta: delta, FinishReason: finishReason}},
+	}
+}
+
+func newCompletionID() string {
+	return "chatcmpl-" + uuid.NewString()
+}
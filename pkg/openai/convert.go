@@ -0,0 +1,143 @@
+package openai
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// ToQueryRequest converts a chat completions request to an SDK query request.
+// System and developer messages are combined into the system prompt.
+func ToQueryRequest(req *ChatCompletionRequest) (*types.QueryRequest, error) {
+	request := &types.QueryRequest{
+		Model:         req.Model,
+		MaxTokens:     req.MaxTokens,
+		StopSequences: req.Stop,
+		Stream:        req.Stream,
+		ToolChoice:    req.ToolChoice,
+	}
+	if req.MaxCompletionTokens > 0 {
+		request.MaxTokens = req.MaxCompletionTokens
+	}
+	if req.Temperature != nil {
+		request.Temperature = *req.Temperature
+	}
+	if req.TopP != nil {
+		request.TopP = *req.TopP
+	}
+	if req.User != "" {
+		request.Metadata = map[string]any{"user_id": req.User}
+	}
+
+	var system []string
+	for i, msg := range req.Messages {
+		switch msg.Role {
+		case "system", "developer":
+			system = append(system, msg.Content)
+			continue
+		case "user", "assistant", "tool":
+		default:
+			return nil, sdkerrors.NewValidationError("messages.role", msg.Role, "system, developer, user, assistant or tool",
+				"unsupported role in message "+strconv.Itoa(i))
+		}
+
+		message := types.Message{
+			Role:       types.Role(msg.Role),
+			Content:    msg.Content,
+			ToolCallID: msg.ToolCallID,
+		}
+		for _, call := range msg.ToolCalls {
+			message.ToolCalls = append(message.ToolCalls, types.ToolCall{
+				ID:   call.ID,
+				Type: call.Type,
+				Function: types.FunctionCall{
+					Name:      call.Function.Name,
+					Arguments: call.Function.Arguments,
+				},
+			})
+		}
+		request.Messages = append(request.Messages, message)
+	}
+	request.System = strings.Join(system, "\n\n")
+
+	for _, tool := range req.Tools {
+		if tool.Type != "" && tool.Type != "function" {
+			return nil, sdkerrors.NewValidationError("tools.type", tool.Type, "function", "only function tools are supported")
+		}
+
+		converted := types.Tool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: types.ToolInputSchema{Type: "object"},
+		}
+		if len(tool.Function.Parameters) > 0 {
+			if err := json.Unmarshal(tool.Function.Parameters, &converted.InputSchema); err != nil {
+				return nil, sdkerrors.NewValidationError("tools.function.parameters", tool.Function.Name, "JSON schema",
+					"invalid parameters schema: "+err.Error())
+			}
+		}
+		request.Tools = append(request.Tools, converted)
+	}
+
+	return request, nil
+}
+
+// FromQueryResponse converts an SDK query response to a chat completion.
+// Text blocks become the message content and tool_use blocks become tool calls.
+func FromQueryResponse(response *types.QueryResponse, id string, created int64) *ChatCompletionResponse {
+	message := ChatMessage{Role: "assistant"}
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			arguments, err := json.Marshal(block.Input)
+			if err != nil || block.Input == nil {
+				arguments = []byte("{}")
+			}
+			message.ToolCalls = append(message.ToolCalls, ToolCall{
+				ID:       block.ID,
+				Type:     "function",
+				Function: FunctionCall{Name: block.Name, Arguments: string(arguments)},
+			})
+		}
+	}
+	message.Content = text.String()
+
+	completion := &ChatCompletionResponse{
+		ID:      id,
+		Object:  ObjectChatCompletion,
+		Created: created,
+		Model:   response.Model,
+		Choices: []Choice{{Message: message, FinishReason: finishReason(response.StopReason, len(message.ToolCalls) > 0)}},
+	}
+	if usage := response.Usage; usage != nil {
+		total := usage.TotalTokens
+		if total == 0 {
+			total = usage.InputTokens + usage.OutputTokens
+		}
+		completion.Usage = &Usage{
+			PromptTokens:     usage.InputTokens,
+			CompletionTokens: usage.OutputTokens,
+			TotalTokens:      total,
+		}
+	}
+	return completion
+}
+
+// finishReason maps a Claude stop reason to an OpenAI finish reason.
+func finishReason(stopReason string, hasToolCalls bool) string {
+	switch {
+	case stopReason == "max_tokens":
+		return FinishReasonLength
+	case stopReason == "tool_use" || hasToolCalls:
+		return FinishReasonToolCalls
+	default:
+		return FinishReasonStop
+	}
+}
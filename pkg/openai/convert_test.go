@@ -0,0 +1,125 @@
+package openai
+
+import (
+	"encoding/json"
+	"testing"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestChatMessage_UnmarshalContent(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"string", `{"role":"user","content":"hello"}`, "hello"},
+		{"null", `{"role":"assistant","content":null}`, ""},
+		{"parts", `{"role":"user","content":[{"type":"text","text":"a"},{"type":"text","text":"b"}]}`, "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg ChatMessage
+			if err := json.Unmarshal([]byte(tt.json), &msg); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if msg.Content != tt.want {
+				t.Errorf("Content = %q, want %q", msg.Content, tt.want)
+			}
+		})
+	}
+
+	var msg ChatMessage
+	if err := json.Unmarshal([]byte(`{"role":"user","content":[{"type":"image_url"}]}`), &msg); err == nil {
+		t.Error("Expected error for unsupported content part")
+	}
+}
+
+func TestStop_Unmarshal(t *testing.T) {
+	var req ChatCompletionRequest
+	if err := json.Unmarshal([]byte(`{"stop":"END"}`), &req); err != nil || len(req.Stop) != 1 || req.Stop[0] != "END" {
+		t.Errorf("Unexpected single stop: %v (%v)", req.Stop, err)
+	}
+	if err := json.Unmarshal([]byte(`{"stop":["a","b"]}`), &req); err != nil || len(req.Stop) != 2 {
+		t.Errorf("Unexpected stop list: %v (%v)", req.Stop, err)
+	}
+}
+
+func TestToQueryRequest(t *testing.T) {
+	temperature := 0.2
+	req := &ChatCompletionRequest{
+		Model:               "claude-sonnet-4",
+		MaxTokens:           100,
+		MaxCompletionTokens: 200,
+		Temperature:         &temperature,
+		Messages: []ChatMessage{
+			{Role: "system", Content: "Be brief."},
+			{Role: "developer", Content: "Use Go."},
+			{Role: "user", Content: "Read a.go"},
+			{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Type: "function", Function: FunctionCall{Name: "read", Arguments: `{"path":"a.go"}`}}}},
+			{Role: "tool", ToolCallID: "call_1", Content: "package a"},
+		},
+		Tools: []ChatTool{{Type: "function", Function: FunctionSpec{
+			Name:       "read",
+			Parameters: json.RawMessage(`{"type":"object","required":["path"]}`),
+		}}},
+	}
+
+	request, err := ToQueryRequest(req)
+	if err != nil {
+		t.Fatalf("ToQueryRequest failed: %v", err)
+	}
+	if request.System != "Be brief.\n\nUse Go." {
+		t.Errorf("System = %q", request.System)
+	}
+	if len(request.Messages) != 3 || request.Messages[2].Role != types.RoleTool || request.Messages[2].ToolCallID != "call_1" {
+		t.Errorf("Unexpected messages: %+v", request.Messages)
+	}
+	if request.Messages[1].ToolCalls[0].Function.Arguments != `{"path":"a.go"}` {
+		t.Errorf("Tool call not converted: %+v", request.Messages[1])
+	}
+	if request.MaxTokens != 200 || request.Temperature != 0.2 {
+		t.Errorf("Unexpected sampling parameters: %d %v", request.MaxTokens, request.Temperature)
+	}
+	if request.Tools[0].InputSchema.Required[0] != "path" {
+		t.Errorf("Tool schema not converted: %+v", request.Tools[0])
+	}
+
+	_, err = ToQueryRequest(&ChatCompletionRequest{Messages: []ChatMessage{{Role: "function"}}})
+	if sdkerrors.GetCategory(err) != sdkerrors.CategoryValidation {
+		t.Errorf("Expected validation error for unknown role, got %v", err)
+	}
+}
+
+func TestFromQueryResponse(t *testing.T) {
+	response := &types.QueryResponse{
+		Model: "claude-sonnet-4",
+		Content: []types.ContentBlock{
+			types.NewTextBlock("Reading "),
+			types.NewTextBlock("now"),
+			{Type: "tool_use", ID: "toolu_1", Name: "read", Input: map[string]any{"path": "a.go"}},
+		},
+		StopReason: "tool_use",
+		Usage:      &types.TokenUsage{InputTokens: 10, OutputTokens: 4},
+	}
+
+	completion := FromQueryResponse(response, "chatcmpl-1", 42)
+	choice := completion.Choices[0]
+	if choice.Message.Content != "Reading now" || choice.FinishReason != FinishReasonToolCalls {
+		t.Errorf("Unexpected choice: %+v", choice)
+	}
+	if call := choice.Message.ToolCalls[0]; call.ID != "toolu_1" || call.Function.Arguments != `{"path":"a.go"}` {
+		t.Errorf("Unexpected tool call: %+v", call)
+	}
+	if completion.Usage.TotalTokens != 14 || completion.Object != ObjectChatCompletion || completion.Created != 42 {
+		t.Errorf("Unexpected completion: %+v", completion)
+	}
+
+	if reason := finishReason("max_tokens", false); reason != FinishReasonLength {
+		t.Errorf("finishReason(max_tokens) = %q", reason)
+	}
+	if reason := finishReason("end_turn", false); reason != FinishReasonStop {
+		t.Errorf("finishReason(end_turn) = %q", reason)
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Package openai exposes the Claude Code Go SDK behind an OpenAI chat
+completions interface, so tools that already speak the OpenAI schema can
+switch to Claude Code with minimal changes.
+
+The Adapter converts ChatCompletionRequest values to SDK query requests and
+SDK responses back to the OpenAI shapes: system and developer messages become
+the system prompt, function tools map to SDK tools, and tool_use blocks are
+returned as tool calls. Handler serves the adapter over HTTP with the
+/v1/chat/completions and /v1/models routes, streaming with the usual
+"data: ..." Server-Sent Events terminated by "data: [DONE]".
+
+# Basic Usage
+
+	claudeClient, err := client.NewClaudeCodeClient(ctx, types.NewClaudeCodeConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer claudeClient.Close()
+
+	adapter := openai.NewAdapter(claudeClient)
+
+	completion, err := adapter.CreateChatCompletion(ctx, &openai.ChatCompletionRequest{
+		Model:    "claude-sonnet-4",
+		Messages: []openai.ChatMessage{{Role: "user", Content: "Explain this repository"}},
+	})
+
+# HTTP Handler
+
+	handler, err := openai.NewHandler(adapter, openai.HandlerConfig{
+		APIKeys: []string{os.Getenv("GATEWAY_API_KEY")},
+		Models:  []string{"claude-sonnet-4"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Fatal(http.ListenAndServe(":8080", handler))
+
+Existing OpenAI clients then only need their base URL pointed at
+http://localhost:8080/v1.
+*/
+package openai
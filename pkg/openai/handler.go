@@ -0,0 +1,230 @@
+package openai
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// DefaultMaxRequestBytes limits the size of request bodies.
+const DefaultMaxRequestBytes = 1 << 20
+
+// Error types reported in error bodies.
+const (
+	ErrorTypeInvalidRequest = "invalid_request_error"
+	ErrorTypeAuthentication = "authentication_error"
+	ErrorTypeAPI            = "api_error"
+)
+
+// HandlerConfig configures a Handler.
+type HandlerConfig struct {
+	// APIKeys are the bearer tokens accepted from clients. At least one key is
+	// required unless AllowUnauthenticated is set.
+	APIKeys []string
+
+	// AllowUnauthenticated disables API-key checks, for use behind a trusted proxy
+	AllowUnauthenticated bool
+
+	// Models are the model IDs listed by GET /v1/models
+	Models []string
+
+	// MaxRequestBytes limits request bodies (defaults to DefaultMaxRequestBytes)
+	MaxRequestBytes int64
+}
+
+// Handler serves the OpenAI chat completions API over an Adapter.
+//
+// Routes:
+//
+//	POST /v1/chat/completions    chat completion, streamed as SSE when "stream" is true
+//	GET  /v1/models              configured models
+type Handler struct {
+	adapter *Adapter
+	config  HandlerConfig
+}
+
+// NewHandler creates an HTTP handler for adapter.
+func NewHandler(adapter *Adapter, config HandlerConfig) (*Handler, error) {
+	if adapter == nil {
+		return nil, sdkerrors.NewConfigurationError("adapter", "adapter is required")
+	}
+	if len(config.APIKeys) == 0 && !config.AllowUnauthenticated {
+		return nil, sdkerrors.NewConfigurationError("api_keys", "at least one API key is required unless AllowUnauthenticated is set")
+	}
+	if config.MaxRequestBytes <= 0 {
+		config.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	return &Handler{adapter: adapter, config: config}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		writeError(w, http.StatusUnauthorized, ErrorTypeAuthentication, "invalid_api_key", "missing or invalid API key")
+		return
+	}
+
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "/v1/chat/completions":
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, ErrorTypeInvalidRequest, "method_not_allowed", "method not allowed")
+			return
+		}
+		h.handleChatCompletions(w, r)
+
+	case "/v1/models":
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeError(w, http.StatusMethodNotAllowed, ErrorTypeInvalidRequest, "method_not_allowed", "method not allowed")
+			return
+		}
+		list := ModelList{Object: ObjectList, Data: []Model{}}
+		for _, id := range h.config.Models {
+			list.Data = append(list.Data, Model{ID: id, Object: ObjectModel, OwnedBy: "anthropic"})
+		}
+		writeJSON(w, http.StatusOK, list)
+
+	default:
+		writeError(w, http.StatusNotFound, ErrorTypeInvalidRequest, "not_found", "unknown endpoint")
+	}
+}
+
+// authorized checks the bearer token in constant time.
+func (h *Handler) authorized(r *http.Request) bool {
+	if h.config.AllowUnauthenticated {
+		return true
+	}
+
+	key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if key == "" {
+		return false
+	}
+
+	match := 0
+	for _, candidate := range h.config.APIKeys {
+		match |= subtle.ConstantTimeCompare([]byte(key), []byte(candidate))
+	}
+	return match == 1
+}
+
+func (h *Handler) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	var req ChatCompletionRequest
+	decoder := json.NewDecoder(io.LimitReader(r.Body, h.config.MaxRequestBytes))
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorTypeInvalidRequest, "invalid_json", fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+	if len(req.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, ErrorTypeInvalidRequest, "invalid_request", "messages are required")
+		return
+	}
+
+	if !req.Stream {
+		completion, err := h.adapter.CreateChatCompletion(r.Context(), &req)
+		if err != nil {
+			writeSDKError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, completion)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, ErrorTypeAPI, "streaming_unsupported", "response writer does not support streaming")
+		return
+	}
+
+	stream, err := h.adapter.CreateChatCompletionStream(r.Context(), &req)
+	if err != nil {
+		writeSDKError(w, err)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			_, _ = io.WriteString(w, "data: [DONE]\n\n")
+			flusher.Flush()
+			return
+		}
+		if err != nil {
+			writeData(w, errorBody(err))
+			flusher.Flush()
+			return
+		}
+		writeData(w, chunk)
+		flusher.Flush()
+	}
+}
+
+// errorBody converts an SDK error to an OpenAI error body.
+func errorBody(err error) ErrorResponse {
+	detail := ErrorDetail{Type: ErrorTypeAPI, Message: err.Error()}
+	switch sdkerrors.GetCategory(err) {
+	case sdkerrors.CategoryValidation:
+		detail.Type = ErrorTypeInvalidRequest
+	case sdkerrors.CategoryAuth:
+		detail.Type = ErrorTypeAuthentication
+	}
+
+	var sdkErr sdkerrors.SDKError
+	if errors.As(err, &sdkErr) {
+		code := sdkErr.Code()
+		detail.Code = &code
+	}
+	return ErrorResponse{Error: detail}
+}
+
+// writeSDKError writes an error response with a status derived from the error.
+func writeSDKError(w http.ResponseWriter, err error) {
+	status := sdkerrors.GetHTTPStatusCode(err)
+	if status == 0 {
+		switch sdkerrors.GetCategory(err) {
+		case sdkerrors.CategoryValidation:
+			status = http.StatusBadRequest
+		case sdkerrors.CategoryAuth:
+			status = http.StatusUnauthorized
+		case sdkerrors.CategoryNetwork, sdkerrors.CategoryAPI:
+			status = http.StatusBadGateway
+		default:
+			status = http.StatusInternalServerError
+		}
+	}
+	writeJSON(w, status, errorBody(err))
+}
+
+// writeError writes an error response with an explicit type and code.
+func writeError(w http.ResponseWriter, status int, errType, code, message string) {
+	writeJSON(w, status, ErrorResponse{Error: ErrorDetail{Type: errType, Code: &code, Message: message}})
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) // Ignore error, the client may have disconnected
+}
+
+// writeData writes an unnamed Server-Sent Event with a JSON payload.
+func writeData(w io.Writer, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
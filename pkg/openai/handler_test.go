@@ -0,0 +1,178 @@
+package openai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeStream returns the configured chunks and then a done chunk.
+type fakeStream struct {
+	chunks []types.StreamChunk
+}
+
+func (s *fakeStream) Recv() (*types.StreamChunk, error) {
+	if len(s.chunks) == 0 {
+		return &types.StreamChunk{Done: true}, nil
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return &chunk, nil
+}
+
+func (s *fakeStream) Close() error { return nil }
+
+// fakeConversation echoes the last prompt.
+type fakeConversation struct {
+	lastRequest *types.QueryRequest
+}
+
+func (c *fakeConversation) Query(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	c.lastRequest = request
+	if request.Model == "bad" {
+		return nil, sdkerrors.NewValidationError("model", request.Model, "known model", "unknown model")
+	}
+	prompt := request.Messages[len(request.Messages)-1].Content
+	return &types.QueryResponse{
+		Content:    []types.ContentBlock{types.NewTextBlock("echo: " + prompt)},
+		StopReason: "end_turn",
+		Usage:      &types.TokenUsage{InputTokens: 2, OutputTokens: 3, TotalTokens: 5},
+	}, nil
+}
+
+func (c *fakeConversation) QueryStream(ctx context.Context, request *types.QueryRequest) (types.QueryStream, error) {
+	c.lastRequest = request
+	return &fakeStream{chunks: []types.StreamChunk{
+		{Type: types.ChunkTypeContent, Content: "Hel"},
+		{Type: types.ChunkTypeMetadata},
+		{Type: types.ChunkTypeContent, Content: "lo"},
+	}}, nil
+}
+
+func newTestHandler(t *testing.T) (*httptest.Server, *fakeConversation) {
+	t.Helper()
+
+	conv := &fakeConversation{}
+	handler, err := NewHandler(NewAdapter(conv), HandlerConfig{APIKeys: []string{"secret"}, Models: []string{"claude-sonnet-4"}})
+	if err != nil {
+		t.Fatalf("NewHandler failed: %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts, conv
+}
+
+func post(t *testing.T, url, key, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestNewHandler_RequiresAPIKeys(t *testing.T) {
+	if _, err := NewHandler(NewAdapter(&fakeConversation{}), HandlerConfig{}); err == nil {
+		t.Error("Expected error without API keys")
+	}
+}
+
+func TestHandler_ChatCompletion(t *testing.T) {
+	ts, conv := newTestHandler(t)
+	body := `{"model":"claude-sonnet-4","messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"hi"}]}`
+
+	if resp := post(t, ts.URL+"/v1/chat/completions", "wrong", body); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", resp.StatusCode)
+	}
+
+	resp := post(t, ts.URL+"/v1/chat/completions", "secret", body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	var completion ChatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if completion.Choices[0].Message.Content != "echo: hi" || completion.Model != "claude-sonnet-4" {
+		t.Errorf("Unexpected completion: %+v", completion)
+	}
+	if !strings.HasPrefix(completion.ID, "chatcmpl-") || completion.Usage.TotalTokens != 5 {
+		t.Errorf("Unexpected completion metadata: %+v", completion)
+	}
+	if conv.lastRequest.System != "Be brief." {
+		t.Errorf("System prompt not forwarded: %q", conv.lastRequest.System)
+	}
+
+	resp = post(t, ts.URL+"/v1/chat/completions", "secret", `{"model":"bad","messages":[{"role":"user","content":"hi"}]}`)
+	var errResp ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || resp.StatusCode != http.StatusBadRequest || errResp.Error.Type != ErrorTypeInvalidRequest {
+		t.Errorf("Unexpected error response: %d %+v (%v)", resp.StatusCode, errResp, err)
+	}
+}
+
+func TestHandler_ChatCompletionStream(t *testing.T) {
+	ts, _ := newTestHandler(t)
+
+	resp := post(t, ts.URL+"/v1/chat/completions", "secret", `{"model":"claude-sonnet-4","stream":true,"messages":[{"role":"user","content":"hi"}]}`)
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+			data = append(data, strings.TrimPrefix(line, "data: "))
+		}
+	}
+	if len(data) != 4 || data[3] != "[DONE]" {
+		t.Fatalf("Unexpected events: %v", data)
+	}
+
+	var first, last ChatCompletionChunk
+	if err := json.Unmarshal([]byte(data[0]), &first); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(data[2]), &last); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if first.Choices[0].Delta.Role != "assistant" || first.Choices[0].Delta.Content != "Hel" || first.Object != ObjectChatCompletionChunk {
+		t.Errorf("Unexpected first chunk: %+v", first)
+	}
+	if last.Choices[0].FinishReason == nil || *last.Choices[0].FinishReason != FinishReasonStop || last.ID != first.ID {
+		t.Errorf("Unexpected final chunk: %+v", last)
+	}
+}
+
+func TestHandler_Models(t *testing.T) {
+	ts, _ := newTestHandler(t)
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/v1/models", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var list ModelList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil || len(list.Data) != 1 || list.Data[0].ID != "claude-sonnet-4" {
+		t.Errorf("Unexpected model list: %+v (%v)", list, err)
+	}
+}
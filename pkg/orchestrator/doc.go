@@ -0,0 +1,45 @@
+/*
+Package orchestrator coordinates several Claude Code sessions working on one
+goal: a Coordinator splits the goal into tasks, fans them out to Workers with
+specialized roles, and aggregates their results.
+
+Each Worker owns an executor, normally its own ClaudeCodeSession, and runs one
+task at a time; different workers run concurrently. The coordinator's own
+executor plans the tasks and writes the final answer.
+
+# Basic Usage
+
+	newSession := func() *client.ClaudeCodeSession {
+		session, err := claudeClient.CreateSession(ctx, "")
+		if err != nil {
+			log.Fatal(err)
+		}
+		return session
+	}
+
+	coordinator, err := orchestrator.NewCoordinator(newSession(), []*orchestrator.Worker{
+		{Name: "coder-1", Role: "coder", SystemPrompt: "You write Go code.", Executor: newSession()},
+		{Name: "coder-2", Role: "coder", SystemPrompt: "You write Go code.", Executor: newSession()},
+		{Name: "tester", Role: "tester", SystemPrompt: "You write Go tests.", Executor: newSession()},
+	}, orchestrator.WithMaxAttempts(2))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := coordinator.Run(ctx, "Add pagination to the list endpoints")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(result.Summary)
+
+Plan, Dispatch and Aggregate can also be called separately, for example to
+dispatch a task list built without the planner.
+
+# Failure Handling
+
+A failed task is retried on a different worker with the same role until
+WithMaxAttempts is reached. By default the other tasks keep running and the
+failures are reported to the aggregation step; WithFailFast stops dispatching
+after the first failure and marks the remaining tasks with ErrNotRun.
+*/
+package orchestrator
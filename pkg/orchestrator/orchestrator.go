@@ -0,0 +1,410 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// ErrNotRun is the error of tasks left pending when a fail-fast dispatch stopped.
+var ErrNotRun = errors.New("task was not run")
+
+// Task is a unit of work handed to a worker.
+type Task struct {
+	// ID identifies the task in results
+	ID string `json:"id"`
+
+	// Role restricts the task to workers with this role ("" for any worker)
+	Role string `json:"role,omitempty"`
+
+	// Prompt is sent to the worker
+	Prompt string `json:"prompt"`
+}
+
+// TaskResult is the outcome of a task.
+type TaskResult struct {
+	Task Task
+
+	// Worker is the name of the worker that produced the final outcome
+	Worker string
+
+	// Output is the text of the worker's response
+	Output string
+
+	// Response is the worker's full response
+	Response *types.QueryResponse
+
+	// Err is set when every attempt failed
+	Err error
+
+	// Attempts is the number of workers that tried the task
+	Attempts int
+
+	Duration time.Duration
+}
+
+// Worker runs tasks in its own session, specialized by a role and system prompt.
+type Worker struct {
+	// Name identifies the worker in results
+	Name string
+
+	// Role is matched against Task.Role, e.g. "tester"
+	Role string
+
+	// SystemPrompt specializes the worker for its role
+	SystemPrompt string
+
+	// Model overrides the model for the worker's queries
+	Model string
+
+	// Executor runs the worker's queries. Give each worker its own session so
+	// workers keep separate conversations.
+	Executor types.QueryExecutor
+}
+
+// Option configures a Coordinator.
+type Option func(*Coordinator)
+
+// WithMaxAttempts sets how many different workers may try a failed task
+// (defaults to 1).
+func WithMaxAttempts(attempts int) Option {
+	return func(c *Coordinator) {
+		if attempts > 0 {
+			c.maxAttempts = attempts
+		}
+	}
+}
+
+// WithFailFast stops dispatching new tasks after the first task fails.
+func WithFailFast() Option {
+	return func(c *Coordinator) {
+		c.failFast = true
+	}
+}
+
+// WithTaskTimeout bounds each task attempt (zero means no timeout).
+func WithTaskTimeout(timeout time.Duration) Option {
+	return func(c *Coordinator) {
+		c.taskTimeout = timeout
+	}
+}
+
+// Coordinator plans a goal into tasks with its own session, fans the tasks
+// out to workers, and aggregates their results. Each worker runs one task at
+// a time; different workers run concurrently.
+type Coordinator struct {
+	planner     types.QueryExecutor
+	workers     []*Worker
+	maxAttempts int
+	failFast    bool
+	taskTimeout time.Duration
+}
+
+// NewCoordinator creates a coordinator that plans and aggregates with planner
+// and distributes tasks over workers. Worker names must be unique. Planner
+// may be nil when only Dispatch is used.
+func NewCoordinator(planner types.QueryExecutor, workers []*Worker, opts ...Option) (*Coordinator, error) {
+	if len(workers) == 0 {
+		return nil, sdkerrors.NewValidationError("workers", "", "non-empty", "a coordinator needs at least one worker")
+	}
+	names := make(map[string]bool, len(workers))
+	for i, worker := range workers {
+		if worker == nil || worker.Executor == nil {
+			return nil, sdkerrors.NewValidationError("workers", fmt.Sprint(i), "executor", "every worker needs an executor")
+		}
+		if worker.Name == "" || names[worker.Name] {
+			return nil, sdkerrors.NewValidationError("workers", worker.Name, "unique name", "worker names must be unique and non-empty")
+		}
+		names[worker.Name] = true
+	}
+
+	c := &Coordinator{planner: planner, workers: workers, maxAttempts: 1}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Result is the outcome of Run.
+type Result struct {
+	Goal    string
+	Tasks   []TaskResult
+	Summary string
+}
+
+// Failed returns the results of tasks that failed.
+func (r *Result) Failed() []TaskResult {
+	var failed []TaskResult
+	for _, task := range r.Tasks {
+		if task.Err != nil {
+			failed = append(failed, task)
+		}
+	}
+	return failed
+}
+
+// Run plans the goal, dispatches the tasks and aggregates the results. It
+// fails if planning fails, if every task fails, or if a fail-fast dispatch
+// stopped early; the partial result is returned with the error.
+func (c *Coordinator) Run(ctx context.Context, goal string) (*Result, error) {
+	tasks, err := c.Plan(ctx, goal)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Goal: goal, Tasks: c.Dispatch(ctx, tasks)}
+	failed := result.Failed()
+	if len(failed) == len(result.Tasks) {
+		return result, sdkerrors.WrapError(failed[0].Err, sdkerrors.CategoryInternal, "ORCHESTRATION", "every task failed")
+	}
+	if c.failFast && len(failed) > 0 {
+		return result, sdkerrors.WrapError(failed[0].Err, sdkerrors.CategoryInternal, "ORCHESTRATION", "task "+failed[0].Task.ID+" failed")
+	}
+
+	result.Summary, err = c.Aggregate(ctx, goal, result.Tasks)
+	return result, err
+}
+
+// Plan asks the planner to split the goal into tasks for the workers' roles.
+func (c *Coordinator) Plan(ctx context.Context, goal string) ([]Task, error) {
+	if c.planner == nil {
+		return nil, sdkerrors.NewConfigurationError("planner", "planning requires a planner executor")
+	}
+	roles := c.roles()
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Split this goal into independent tasks that can run in parallel:\n\n%s\n\n", goal)
+	if len(roles) > 0 {
+		fmt.Fprintf(&prompt, "Assign each task one of these roles: %s.\n", strings.Join(roles, ", "))
+	}
+	prompt.WriteString(`Each task prompt must be self-contained. Reply with only a JSON array of ` +
+		`objects with "id", "role" and "prompt" fields.`)
+
+	response, err := c.planner.Query(ctx, &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: prompt.String()}},
+	})
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "ORCHESTRATION", "failed to plan tasks")
+	}
+
+	tasks, err := parseTasks(response.GetTextContent())
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		known[role] = true
+	}
+	for i := range tasks {
+		if tasks[i].ID == "" {
+			tasks[i].ID = fmt.Sprintf("task-%d", i+1)
+		}
+		if !known[tasks[i].Role] {
+			tasks[i].Role = ""
+		}
+	}
+	return tasks, nil
+}
+
+// parseTasks extracts the JSON task list from a planner response.
+func parseTasks(text string) ([]Task, error) {
+	start, end := strings.Index(text, "["), strings.LastIndex(text, "]")
+	if start < 0 || end < start {
+		return nil, sdkerrors.NewValidationError("plan", text, "JSON array", "planner did not return a task list")
+	}
+
+	var tasks []Task
+	if err := json.Unmarshal([]byte(text[start:end+1]), &tasks); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "ORCHESTRATION", "planner returned an invalid task list")
+	}
+	if len(tasks) == 0 {
+		return nil, sdkerrors.NewValidationError("plan", text, "non-empty", "planner returned no tasks")
+	}
+	return tasks, nil
+}
+
+// roles returns the distinct worker roles in worker order.
+func (c *Coordinator) roles() []string {
+	var roles []string
+	seen := make(map[string]bool)
+	for _, worker := range c.workers {
+		if worker.Role != "" && !seen[worker.Role] {
+			seen[worker.Role] = true
+			roles = append(roles, worker.Role)
+		}
+	}
+	return roles
+}
+
+// Aggregate asks the planner to combine the task results into one answer.
+func (c *Coordinator) Aggregate(ctx context.Context, goal string, results []TaskResult) (string, error) {
+	if c.planner == nil {
+		return "", sdkerrors.NewConfigurationError("planner", "aggregation requires a planner executor")
+	}
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Workers completed tasks toward this goal:\n\n%s\n\n", goal)
+	for _, result := range results {
+		fmt.Fprintf(&prompt, "## Task %s\n%s\n\n", result.Task.ID, result.Task.Prompt)
+		if result.Err != nil {
+			fmt.Fprintf(&prompt, "FAILED: %v\n\n", result.Err)
+		} else {
+			fmt.Fprintf(&prompt, "Result from %s:\n%s\n\n", result.Worker, result.Output)
+		}
+	}
+	prompt.WriteString("Combine the results into a single answer to the goal, noting any failed tasks.")
+
+	response, err := c.planner.Query(ctx, &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: prompt.String()}},
+	})
+	if err != nil {
+		return "", sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "ORCHESTRATION", "failed to aggregate results")
+	}
+	return response.GetTextContent(), nil
+}
+
+// Dispatch runs the tasks on the workers and returns their results in task
+// order. A failed task is retried on a different eligible worker until
+// WithMaxAttempts is reached; a task with a role no worker has fails at once.
+func (c *Coordinator) Dispatch(ctx context.Context, tasks []Task) []TaskResult {
+	d := &dispatch{coordinator: c, results: make([]TaskResult, len(tasks))}
+	d.cond = sync.NewCond(&d.mu)
+
+	for i, task := range tasks {
+		d.results[i].Task = task
+		if len(c.eligible(task, nil)) == 0 {
+			d.results[i].Err = sdkerrors.NewValidationError("role", task.Role, "existing worker role", "no worker has role "+task.Role)
+			continue
+		}
+		d.pending = append(d.pending, &attempt{index: i, tried: make(map[string]bool)})
+	}
+
+	var wg sync.WaitGroup
+	for _, worker := range c.workers {
+		wg.Add(1)
+		go func(worker *Worker) {
+			defer wg.Done()
+			d.work(ctx, worker)
+		}(worker)
+	}
+	wg.Wait()
+
+	for _, a := range d.pending {
+		d.results[a.index].Err = ErrNotRun
+	}
+	return d.results
+}
+
+// eligible returns the workers that may run a task, excluding those tried.
+func (c *Coordinator) eligible(task Task, tried map[string]bool) []*Worker {
+	var workers []*Worker
+	for _, worker := range c.workers {
+		if (task.Role == "" || task.Role == worker.Role) && !tried[worker.Name] {
+			workers = append(workers, worker)
+		}
+	}
+	return workers
+}
+
+// attempt tracks a pending task and the workers that already tried it.
+type attempt struct {
+	index int
+	tried map[string]bool
+}
+
+// dispatch is the shared state of a Dispatch call.
+type dispatch struct {
+	coordinator *Coordinator
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	pending  []*attempt
+	inFlight int
+	stopped  bool
+	results  []TaskResult
+}
+
+// work runs pending tasks on a worker until none are left that it can take.
+func (d *dispatch) work(ctx context.Context, worker *Worker) {
+	for {
+		d.mu.Lock()
+		var next *attempt
+		for {
+			if d.stopped || ctx.Err() != nil {
+				d.mu.Unlock()
+				return
+			}
+			if next = d.take(worker); next != nil {
+				break
+			}
+			// Only a failing task in flight can make more work available
+			if d.inFlight == 0 {
+				d.mu.Unlock()
+				return
+			}
+			d.cond.Wait()
+		}
+		d.inFlight++
+		task := d.results[next.index].Task
+		d.mu.Unlock()
+
+		started := time.Now()
+		response, err := d.run(ctx, worker, task)
+
+		d.mu.Lock()
+		d.inFlight--
+		result := &d.results[next.index]
+		result.Attempts++
+		result.Worker = worker.Name
+		result.Duration += time.Since(started)
+		result.Response, result.Err = response, err
+		if err == nil {
+			result.Output = response.GetTextContent()
+		} else {
+			next.tried[worker.Name] = true
+			retry := result.Attempts < d.coordinator.maxAttempts && len(d.coordinator.eligible(task, next.tried)) > 0
+			switch {
+			case retry && ctx.Err() == nil:
+				d.pending = append(d.pending, next)
+			case d.coordinator.failFast:
+				d.stopped = true
+			}
+		}
+		d.cond.Broadcast()
+		d.mu.Unlock()
+	}
+}
+
+// take removes and returns the first pending task the worker may run.
+func (d *dispatch) take(worker *Worker) *attempt {
+	for i, a := range d.pending {
+		task := d.results[a.index].Task
+		if (task.Role == "" || task.Role == worker.Role) && !a.tried[worker.Name] {
+			d.pending = append(d.pending[:i], d.pending[i+1:]...)
+			return a
+		}
+	}
+	return nil
+}
+
+// run sends a task to a worker.
+func (d *dispatch) run(ctx context.Context, worker *Worker, task Task) (*types.QueryResponse, error) {
+	if d.coordinator.taskTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.coordinator.taskTimeout)
+		defer cancel()
+	}
+
+	return worker.Executor.Query(ctx, &types.QueryRequest{
+		Model:    worker.Model,
+		System:   worker.SystemPrompt,
+		Messages: []types.Message{{Role: types.RoleUser, Content: task.Prompt}},
+	})
+}
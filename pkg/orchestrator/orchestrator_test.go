@@ -0,0 +1,160 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeExecutor answers queries with a function and records the requests.
+type fakeExecutor struct {
+	mu       sync.Mutex
+	requests []*types.QueryRequest
+	answer   func(request *types.QueryRequest) (string, error)
+}
+
+func (f *fakeExecutor) Query(_ context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, request)
+	f.mu.Unlock()
+
+	text, err := f.answer(request)
+	if err != nil {
+		return nil, err
+	}
+	return &types.QueryResponse{Content: []types.ContentBlock{types.NewTextBlock(text)}}, nil
+}
+
+// prompt returns the user prompt of a request.
+func prompt(request *types.QueryRequest) string {
+	return request.Messages[len(request.Messages)-1].Content
+}
+
+// echoWorker returns a worker that answers with its name and the task prompt.
+func echoWorker(name, role string) *Worker {
+	return &Worker{Name: name, Role: role, SystemPrompt: "You are a " + role, Executor: &fakeExecutor{
+		answer: func(request *types.QueryRequest) (string, error) {
+			return name + " did " + prompt(request), nil
+		},
+	}}
+}
+
+func TestCoordinator_Run(t *testing.T) {
+	planner := &fakeExecutor{answer: func(request *types.QueryRequest) (string, error) {
+		if strings.HasPrefix(prompt(request), "Split this goal") {
+			return "Here is the plan:\n```json\n" + `[
+				{"id": "impl", "role": "coder", "prompt": "write the parser"},
+				{"id": "tests", "role": "tester", "prompt": "test the parser"},
+				{"role": "designer", "prompt": "document the parser"}
+			]` + "\n```", nil
+		}
+		return "combined", nil
+	}}
+
+	coordinator, err := NewCoordinator(planner, []*Worker{echoWorker("alice", "coder"), echoWorker("bob", "tester")})
+	if err != nil {
+		t.Fatalf("NewCoordinator failed: %v", err)
+	}
+
+	result, err := coordinator.Run(context.Background(), "Build a parser")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Summary != "combined" || len(result.Tasks) != 3 || len(result.Failed()) != 0 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if task := result.Tasks[0]; task.Worker != "alice" || task.Output != "alice did write the parser" {
+		t.Errorf("Unexpected coder task: %+v", task)
+	}
+	if task := result.Tasks[1]; task.Worker != "bob" {
+		t.Errorf("Expected the tester task on bob: %+v", task)
+	}
+	if task := result.Tasks[2]; task.Task.ID != "task-3" || task.Task.Role != "" {
+		t.Errorf("Expected an unknown role to be cleared: %+v", task.Task)
+	}
+
+	planPrompt := prompt(planner.requests[0])
+	if !strings.Contains(planPrompt, "Build a parser") || !strings.Contains(planPrompt, "coder, tester") {
+		t.Errorf("Unexpected plan prompt: %s", planPrompt)
+	}
+	aggregatePrompt := prompt(planner.requests[1])
+	if !strings.Contains(aggregatePrompt, "alice did write the parser") {
+		t.Errorf("Unexpected aggregate prompt: %s", aggregatePrompt)
+	}
+
+	worker := coordinator.workers[0].Executor.(*fakeExecutor)
+	if worker.requests[0].System != "You are a coder" {
+		t.Errorf("Expected the worker system prompt, got %q", worker.requests[0].System)
+	}
+}
+
+func TestCoordinator_RetryOnAnotherWorker(t *testing.T) {
+	broken := &Worker{Name: "broken", Executor: &fakeExecutor{answer: func(*types.QueryRequest) (string, error) {
+		return "", errors.New("session crashed")
+	}}}
+	coordinator, err := NewCoordinator(nil, []*Worker{broken, echoWorker("ok", "")}, WithMaxAttempts(2))
+	if err != nil {
+		t.Fatalf("NewCoordinator failed: %v", err)
+	}
+
+	tasks := []Task{{ID: "a", Prompt: "one"}, {ID: "b", Prompt: "two"}, {ID: "c", Prompt: "three"}}
+	results := coordinator.Dispatch(context.Background(), tasks)
+	for _, result := range results {
+		if result.Err != nil || result.Worker != "ok" || result.Output != "ok did "+result.Task.Prompt {
+			t.Errorf("Unexpected result %+v", result)
+		}
+	}
+}
+
+func TestCoordinator_Failures(t *testing.T) {
+	failing := &Worker{Name: "failing", Role: "coder", Executor: &fakeExecutor{answer: func(*types.QueryRequest) (string, error) {
+		return "", errors.New("boom")
+	}}}
+
+	coordinator, _ := NewCoordinator(nil, []*Worker{failing}, WithMaxAttempts(3))
+	results := coordinator.Dispatch(context.Background(), []Task{
+		{ID: "a", Role: "coder", Prompt: "x"},
+		{ID: "b", Role: "reviewer", Prompt: "y"},
+	})
+	if results[0].Err == nil || results[0].Attempts != 1 {
+		t.Errorf("Expected one failed attempt without another eligible worker, got %+v", results[0])
+	}
+	if results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "no worker has role reviewer") {
+		t.Errorf("Expected a missing role error, got %v", results[1].Err)
+	}
+
+	// Fail fast leaves the remaining tasks unrun
+	coordinator, _ = NewCoordinator(nil, []*Worker{failing}, WithFailFast())
+	results = coordinator.Dispatch(context.Background(), []Task{{ID: "a", Prompt: "x"}, {ID: "b", Prompt: "y"}})
+	if results[0].Err == nil || !errors.Is(results[1].Err, ErrNotRun) {
+		t.Errorf("Expected fail fast to skip the second task, got %+v", results)
+	}
+
+	if _, err := coordinator.Plan(context.Background(), "goal"); err == nil {
+		t.Error("Expected Plan to require a planner")
+	}
+}
+
+func TestNewCoordinator_Validation(t *testing.T) {
+	if _, err := NewCoordinator(nil, nil); err == nil {
+		t.Error("Expected error without workers")
+	}
+	if _, err := NewCoordinator(nil, []*Worker{echoWorker("a", ""), echoWorker("a", "")}); err == nil {
+		t.Error("Expected error for duplicate worker names")
+	}
+	if _, err := NewCoordinator(nil, []*Worker{{Name: "a"}}); err == nil {
+		t.Error("Expected error for a worker without an executor")
+	}
+}
+
+func TestParseTasks(t *testing.T) {
+	for _, text := range []string{"no tasks here", "[]", "[{"} {
+		if _, err := parseTasks(text); err == nil {
+			t.Errorf("Expected parseTasks(%q) to fail", text)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package pii
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/redact"
+)
+
+// Detector names of the built-in detectors.
+const (
+	Email      = "email"
+	SSN        = "us_ssn"
+	CreditCard = "credit_card"
+	Phone      = "phone_number"
+	IPAddress  = "ip_address"
+)
+
+// patternDetector finds candidates with a regular expression and keeps those
+// passing a validity check, such as a checksum, to avoid flagging numbers
+// that merely look like personal data.
+type patternDetector struct {
+	name    string
+	pattern *regexp.Regexp
+	valid   func(match string) bool
+}
+
+// Name returns the detector name.
+func (d *patternDetector) Name() string {
+	return d.name
+}
+
+// Find returns the valid matches of the expression.
+func (d *patternDetector) Find(text string) []redact.Match {
+	var matches []redact.Match
+	for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+		if d.valid == nil || d.valid(text[loc[0]:loc[1]]) {
+			matches = append(matches, redact.Match{Start: loc[0], End: loc[1], Detector: d.name})
+		}
+	}
+	return matches
+}
+
+// EmailDetector finds email addresses.
+func EmailDetector() redact.Detector {
+	return &patternDetector{
+		name:    Email,
+		pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}\b`),
+	}
+}
+
+// SSNDetector finds US Social Security numbers written as 123-45-6789,
+// skipping numbers the Social Security Administration never issues.
+func SSNDetector() redact.Detector {
+	return &patternDetector{
+		name:    SSN,
+		pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		valid: func(match string) bool {
+			area, group, serial := match[:3], match[4:6], match[7:]
+			return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+		},
+	}
+}
+
+// CreditCardDetector finds payment card numbers of 13 to 19 digits, optionally
+// grouped with spaces or dashes, that pass the Luhn checksum.
+func CreditCardDetector() redact.Detector {
+	return &patternDetector{
+		name:    CreditCard,
+		pattern: regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`),
+		valid: func(match string) bool {
+			return luhn(strings.NewReplacer(" ", "", "-", "").Replace(match))
+		},
+	}
+}
+
+// PhoneDetector finds North American phone numbers such as (555) 123-4567
+// and +1 555.123.4567, and international numbers in E.164 form.
+func PhoneDetector() redact.Detector {
+	return &patternDetector{
+		name:    Phone,
+		pattern: regexp.MustCompile(`(?:\+1[ .\-]?)?(?:\(\d{3}\)\s?|\b\d{3}[ .\-])\d{3}[ .\-]\d{4}\b|\+[1-9]\d{7,14}\b`),
+	}
+}
+
+// IPAddressDetector finds IPv4 addresses.
+func IPAddressDetector() redact.Detector {
+	return &patternDetector{
+		name:    IPAddress,
+		pattern: regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}\b`),
+		valid: func(match string) bool {
+			return net.ParseIP(match) != nil
+		},
+	}
+}
+
+// luhn reports whether a string of digits passes the Luhn checksum.
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,45 @@
+/*
+Package pii detects personal data such as email addresses, US Social
+Security numbers and payment card numbers in prompts and responses, and
+blocks, redacts or tags the messages containing it.
+
+A Policy implements types.MessagePolicy. Set it as a client's MessagePolicy
+and it checks the system prompt and messages of every request before the CLI
+is started, and the text of every response before it is returned.
+
+# Basic Usage
+
+	config := types.NewClaudeCodeConfig()
+	config.MessagePolicy = pii.New(nil) // DefaultRules
+
+	response, err := claudeClient.Query(ctx, request)
+	if errors.Is(err, pii.ErrBlocked) {
+		// A blocking rule matched
+	}
+	tags := response.Metadata["policy_tags"] // e.g. []string{"pii:email"}
+
+# Rules
+
+Each Rule pairs a detector with an action and, optionally, a direction.
+ActionBlock rejects the message with a *BlockedError, ActionRedact replaces
+the data with a marker such as "[REDACTED:us_ssn]", and ActionTag passes the
+message unchanged. The tags of all findings are recorded in the policy_tags
+metadata of requests, responses, messages and stream chunks:
+
+	policy := pii.New([]pii.Rule{
+		{Detector: pii.SSNDetector(), Action: pii.ActionBlock},
+		{Detector: pii.CreditCardDetector(), Action: pii.ActionBlock, Direction: types.MessageOutgoing},
+		{Detector: pii.EmailDetector(), Action: pii.ActionRedact},
+	}, pii.WithOnFinding(func(f pii.Finding) {
+		metrics.Inc("pii_findings", f.Detector, string(f.Action))
+	}))
+
+Any redact.Detector can be used in a rule, including the credential
+detectors of package redact and custom regular expressions. The built-in
+detectors validate candidates, for example with the Luhn checksum for card
+numbers, to limit false positives.
+
+Streaming responses are checked chunk by chunk, so data split across chunks
+can go undetected; use Query or QueryMessages when that matters.
+*/
+package pii
@@ -0,0 +1,221 @@
+package pii
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/redact"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Action is what a Policy does with a message containing personal data.
+type Action string
+
+const (
+	// ActionBlock rejects the message with a *BlockedError
+	ActionBlock Action = "block"
+
+	// ActionRedact replaces the data with a marker such as "[REDACTED:email]"
+	ActionRedact Action = "redact"
+
+	// ActionTag passes the message unchanged and tags it with the finding
+	ActionTag Action = "tag"
+)
+
+// Rule applies an action to what a detector finds.
+type Rule struct {
+	// Detector finds the data, such as EmailDetector or any redact.Detector
+	Detector redact.Detector
+
+	// Action is what to do with a message containing the data
+	Action Action
+
+	// Direction limits the rule to outgoing or incoming messages; empty
+	// applies it to both
+	Direction types.MessageDirection
+}
+
+// DefaultRules redacts email addresses, US Social Security numbers, payment
+// card numbers and phone numbers in both directions, and tags IP addresses.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Detector: SSNDetector(), Action: ActionRedact},
+		{Detector: CreditCardDetector(), Action: ActionRedact},
+		{Detector: EmailDetector(), Action: ActionRedact},
+		{Detector: PhoneDetector(), Action: ActionRedact},
+		{Detector: IPAddressDetector(), Action: ActionTag},
+	}
+}
+
+// Finding is personal data found in a message.
+type Finding struct {
+	// Detector is the name of the detector that found it
+	Detector string
+
+	// Action is the action of the rule that matched
+	Action Action
+
+	// Direction is the direction of the message
+	Direction types.MessageDirection
+
+	// Start and End are the byte offsets of the data in the message
+	Start int
+	End   int
+}
+
+// Tag returns the tag recorded for the finding, such as "pii:email".
+func (f Finding) Tag() string {
+	return "pii:" + f.Detector
+}
+
+// ErrBlocked is matched by errors.Is for messages blocked by a Policy.
+var ErrBlocked = errors.New("message blocked by PII policy")
+
+// BlockedError reports a message rejected by a blocking rule.
+type BlockedError struct {
+	// Direction is the direction of the blocked message
+	Direction types.MessageDirection
+
+	// Findings are the findings of blocking rules
+	Findings []Finding
+}
+
+// Error names the kinds of data that blocked the message, never the data.
+func (e *BlockedError) Error() string {
+	var names []string
+	for _, finding := range e.Findings {
+		if !contains(names, finding.Detector) {
+			names = append(names, finding.Detector)
+		}
+	}
+	return string(e.Direction) + " message blocked by PII policy: contains " + strings.Join(names, ", ")
+}
+
+// Unwrap returns ErrBlocked.
+func (e *BlockedError) Unwrap() error {
+	return ErrBlocked
+}
+
+// Policy scans messages for personal data and blocks, redacts or tags them
+// according to its rules. It implements types.MessagePolicy and is safe for
+// concurrent use.
+type Policy struct {
+	rules     []Rule
+	onFinding func(Finding)
+}
+
+var _ types.MessagePolicy = (*Policy)(nil)
+
+// Option configures a Policy.
+type Option func(*Policy)
+
+// WithOnFinding calls fn for every finding, including those of blocked
+// messages, for example to count or audit them. Findings carry offsets, not
+// the data itself.
+func WithOnFinding(fn func(Finding)) Option {
+	return func(p *Policy) {
+		p.onFinding = fn
+	}
+}
+
+// New creates a policy with the given rules, or DefaultRules when rules is
+// nil. Rules listed first win when matches overlap.
+func New(rules []Rule, options ...Option) *Policy {
+	if rules == nil {
+		rules = DefaultRules()
+	}
+	p := &Policy{rules: rules}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Scan returns the findings of the rules that apply to the direction,
+// sorted by position. Overlapping findings keep the rule listed first.
+func (p *Policy) Scan(direction types.MessageDirection, text string) []Finding {
+	if text == "" {
+		return nil
+	}
+
+	var all []Finding
+	for _, rule := range p.rules {
+		if rule.Detector == nil || (rule.Direction != "" && rule.Direction != direction) {
+			continue
+		}
+		for _, match := range rule.Detector.Find(text) {
+			all = append(all, Finding{
+				Detector:  match.Detector,
+				Action:    rule.Action,
+				Direction: direction,
+				Start:     match.Start,
+				End:       match.End,
+			})
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+
+	// Earlier rules win ties, so sort stably by start only
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Start < all[j].Start })
+	findings := []Finding{all[0]}
+	for _, finding := range all[1:] {
+		if finding.Start < findings[len(findings)-1].End {
+			continue
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// Apply implements types.MessagePolicy. It returns a *BlockedError if a
+// blocking rule matched, and otherwise the text with redacted findings
+// replaced and the tags of all findings.
+func (p *Policy) Apply(ctx context.Context, direction types.MessageDirection, text string) (string, []string, error) {
+	findings := p.Scan(direction, text)
+	if p.onFinding != nil {
+		for _, finding := range findings {
+			p.onFinding(finding)
+		}
+	}
+
+	var blocked []Finding
+	for _, finding := range findings {
+		if finding.Action == ActionBlock {
+			blocked = append(blocked, finding)
+		}
+	}
+	if len(blocked) > 0 {
+		return "", nil, &BlockedError{Direction: direction, Findings: blocked}
+	}
+
+	var tags []string
+	var out strings.Builder
+	previous := 0
+	for _, finding := range findings {
+		if !contains(tags, finding.Tag()) {
+			tags = append(tags, finding.Tag())
+		}
+		if finding.Action != ActionRedact {
+			continue
+		}
+		out.WriteString(text[previous:finding.Start])
+		out.WriteString("[REDACTED:" + finding.Detector + "]")
+		previous = finding.End
+	}
+	out.WriteString(text[previous:])
+	return out.String(), tags, nil
+}
+
+// contains reports whether list contains s.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package pii
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/redact"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestDetectors(t *testing.T) {
+	tests := []struct {
+		detector redact.Detector
+		found    []string
+		ignored  []string
+	}{
+		{EmailDetector(), []string{"jane.doe+work@example.co.uk", "a@b.io"}, []string{"user@localhost", "@example.com"}},
+		{SSNDetector(), []string{"123-45-6789"}, []string{"000-12-3456", "666-12-3456", "912-34-5678", "123-00-4567", "123456789"}},
+		{CreditCardDetector(), []string{"4111 1111 1111 1111", "5500-0000-0000-0004", "378282246310005"}, []string{"4111 1111 1111 1112", "1234567890"}},
+		{PhoneDetector(), []string{"(555) 123-4567", "+1 555.123.4567", "555-123-4567", "+442071838750"}, []string{"123-45-6789", "2024-01-15"}},
+		{IPAddressDetector(), []string{"192.168.0.1"}, []string{"999.1.1.1", "1.2.3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.detector.Name(), func(t *testing.T) {
+			for _, text := range tt.found {
+				matches := tt.detector.Find("see " + text + " here")
+				if len(matches) != 1 || matches[0].Start != 4 || matches[0].End != 4+len(text) {
+					t.Errorf("Expected %q to be found, got %v", text, matches)
+				}
+			}
+			for _, text := range tt.ignored {
+				if matches := tt.detector.Find("see " + text + " here"); len(matches) != 0 {
+					t.Errorf("Expected %q to be ignored, got %v", text, matches)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicy_Apply(t *testing.T) {
+	ctx := context.Background()
+	var findings []Finding
+	policy := New(nil, WithOnFinding(func(f Finding) { findings = append(findings, f) }))
+
+	text, tags, err := policy.Apply(ctx, types.MessageOutgoing, "Email jane@example.com from 10.0.0.1, SSN 123-45-6789")
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if text != "Email [REDACTED:email] from 10.0.0.1, SSN [REDACTED:us_ssn]" {
+		t.Errorf("Unexpected text: %s", text)
+	}
+	if want := []string{"pii:email", "pii:ip_address", "pii:us_ssn"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, tags)
+	}
+	if len(findings) != 3 || findings[1].Action != ActionTag || findings[0].Direction != types.MessageOutgoing {
+		t.Errorf("Unexpected findings: %+v", findings)
+	}
+
+	if text, tags, err := policy.Apply(ctx, types.MessageIncoming, "nothing to see"); err != nil || text != "nothing to see" || tags != nil {
+		t.Errorf("Expected clean text unchanged, got %q %v %v", text, tags, err)
+	}
+}
+
+func TestPolicy_Block(t *testing.T) {
+	ctx := context.Background()
+	policy := New([]Rule{
+		{Detector: CreditCardDetector(), Action: ActionBlock, Direction: types.MessageOutgoing},
+		{Detector: CreditCardDetector(), Action: ActionRedact},
+	})
+
+	_, _, err := policy.Apply(ctx, types.MessageOutgoing, "charge 4111-1111-1111-1111 now")
+	var blocked *BlockedError
+	if !errors.Is(err, ErrBlocked) || !errors.As(err, &blocked) || len(blocked.Findings) != 1 {
+		t.Fatalf("Expected a BlockedError, got %v", err)
+	}
+	if strings.Contains(err.Error(), "4111") || !strings.Contains(err.Error(), "credit_card") {
+		t.Errorf("Expected the error to name the kind of data only: %v", err)
+	}
+
+	// The blocking rule only applies to outgoing messages
+	text, _, err := policy.Apply(ctx, types.MessageIncoming, "card 4111-1111-1111-1111")
+	if err != nil || text != "card [REDACTED:credit_card]" {
+		t.Errorf("Expected incoming card redacted, got %q %v", text, err)
+	}
+}
+
+func TestPolicy_CustomDetector(t *testing.T) {
+	employeeID, err := redact.NewRegexDetector("employee_id", `\bEMP-\d{6}\b`)
+	if err != nil {
+		t.Fatalf("NewRegexDetector failed: %v", err)
+	}
+	policy := New(append(DefaultRules(), Rule{Detector: employeeID, Action: ActionRedact}))
+
+	text, _, err := policy.Apply(context.Background(), types.MessageOutgoing, "promote EMP-123456")
+	if err != nil || text != "promote [REDACTED:employee_id]" {
+		t.Errorf("Expected custom detector applied, got %q %v", text, err)
+	}
+
+	if findings := New([]Rule{}).Scan(types.MessageOutgoing, "jane@example.com"); len(findings) != 0 {
+		t.Errorf("Expected an empty rule list to find nothing, got %v", findings)
+	}
+}
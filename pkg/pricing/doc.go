@@ -0,0 +1,20 @@
+/*
+Package pricing provides per-model token prices and usage cost computation for
+the Claude Code Go SDK.
+
+The built-in table covers Anthropic API list prices in USD. Prices can be
+overridden by supplying a Provider, for example to reflect negotiated rates or
+Amazon Bedrock and Google Vertex AI price lists. Providers can be layered with
+Chain so that overrides take precedence over the defaults:
+
+	provider := pricing.Chain{
+		pricing.Table{"claude-sonnet-4": {InputPerMTok: 2.7, OutputPerMTok: 13.5, Currency: "USD"}},
+		pricing.Default(),
+	}
+	client.SetPricingProvider(provider)
+
+The same provider prices both pre-execution estimates (ClaudeCodeClient.EstimateCost)
+and recorded usage (UsageTracker), so budgets and actual spend are computed
+consistently.
+*/
+package pricing
@@ -0,0 +1,165 @@
+package pricing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Price is the price of a model in a currency per million tokens.
+type Price struct {
+	// InputPerMTok is the price per million input tokens
+	InputPerMTok float64 `json:"input_per_mtok"`
+
+	// OutputPerMTok is the price per million output tokens
+	OutputPerMTok float64 `json:"output_per_mtok"`
+
+	// Currency is the pricing currency (e.g., "USD")
+	Currency string `json:"currency"`
+}
+
+// InputCost returns the cost of the given number of input tokens.
+func (p Price) InputCost(tokens int) float64 {
+	return float64(tokens) / 1e6 * p.InputPerMTok
+}
+
+// OutputCost returns the cost of the given number of output tokens.
+func (p Price) OutputCost(tokens int) float64 {
+	return float64(tokens) / 1e6 * p.OutputPerMTok
+}
+
+// Cost returns the cost of the given token usage.
+func (p Price) Cost(usage types.TokenUsage) float64 {
+	return p.InputCost(usage.InputTokens) + p.OutputCost(usage.OutputTokens)
+}
+
+// ModelPricing converts the price to the per-1K representation used by types.ModelInfo.
+func (p Price) ModelPricing() types.ModelPricing {
+	return types.ModelPricing{
+		InputCostPer1K:  p.InputPerMTok / 1000,
+		OutputCostPer1K: p.OutputPerMTok / 1000,
+		Currency:        p.Currency,
+	}
+}
+
+// FromModelPricing converts a per-1K types.ModelPricing to a Price.
+func FromModelPricing(pricing types.ModelPricing) Price {
+	return Price{
+		InputPerMTok:  pricing.InputCostPer1K * 1000,
+		OutputPerMTok: pricing.OutputCostPer1K * 1000,
+		Currency:      pricing.Currency,
+	}
+}
+
+// Provider looks up the price of a model. Implement it to supply pricing from
+// other sources, such as negotiated rates or Bedrock and Vertex AI price lists.
+type Provider interface {
+	// Price returns the price for the model and whether it is known
+	Price(model string) (Price, bool)
+}
+
+// ProviderFunc adapts a function to the Provider interface.
+type ProviderFunc func(model string) (Price, bool)
+
+// Price calls f(model).
+func (f ProviderFunc) Price(model string) (Price, bool) {
+	return f(model)
+}
+
+// Table is a Provider backed by a map keyed by model name prefix. Lookups
+// match the longest prefix, so "claude-sonnet-4-20250514" uses a
+// "claude-sonnet-4" entry. Bedrock ("anthropic.claude-...-v1:0") and Vertex AI
+// ("claude-...@20250514") model IDs are normalized before matching.
+type Table map[string]Price
+
+// Price returns the price of the entry whose key is the longest prefix of model.
+func (t Table) Price(model string) (Price, bool) {
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+
+	normalized := NormalizeModel(model)
+	for _, key := range keys {
+		if strings.HasPrefix(normalized, key) {
+			return t[key], true
+		}
+	}
+	return Price{}, false
+}
+
+// Chain is a Provider that consults each provider in order and returns the first match.
+type Chain []Provider
+
+// Price returns the price from the first provider that knows the model.
+func (c Chain) Price(model string) (Price, bool) {
+	for _, provider := range c {
+		if provider == nil {
+			continue
+		}
+		if price, ok := provider.Price(model); ok {
+			return price, true
+		}
+	}
+	return Price{}, false
+}
+
+// NormalizeModel converts cloud-provider model IDs to Anthropic model names.
+// For example "us.anthropic.claude-3-5-sonnet-20241022-v2:0" and
+// "claude-3-5-sonnet-v2@20241022" both normalize to a "claude-3-5-sonnet" prefix.
+func NormalizeModel(model string) string {
+	// Bedrock: optional region prefix and "anthropic." vendor prefix
+	if idx := strings.Index(model, "anthropic."); idx >= 0 {
+		model = model[idx+len("anthropic."):]
+	}
+
+	// Vertex AI: version suffix after "@"
+	if idx := strings.Index(model, "@"); idx >= 0 {
+		model = model[:idx]
+	}
+
+	return model
+}
+
+// defaultTable is the built-in price list in USD per million tokens.
+var defaultTable = Table{
+	"claude-opus-4-5":   {InputPerMTok: 5, OutputPerMTok: 25, Currency: "USD"},
+	"claude-opus-4":     {InputPerMTok: 15, OutputPerMTok: 75, Currency: "USD"},
+	"claude-sonnet-4":   {InputPerMTok: 3, OutputPerMTok: 15, Currency: "USD"},
+	"claude-haiku-4-5":  {InputPerMTok: 1, OutputPerMTok: 5, Currency: "USD"},
+	"claude-3-7-sonnet": {InputPerMTok: 3, OutputPerMTok: 15, Currency: "USD"},
+	"claude-3-5-sonnet": {InputPerMTok: 3, OutputPerMTok: 15, Currency: "USD"},
+	"claude-3-5-haiku":  {InputPerMTok: 0.8, OutputPerMTok: 4, Currency: "USD"},
+	"claude-3-opus":     {InputPerMTok: 15, OutputPerMTok: 75, Currency: "USD"},
+	"claude-3-sonnet":   {InputPerMTok: 3, OutputPerMTok: 15, Currency: "USD"},
+	"claude-3-haiku":    {InputPerMTok: 0.25, OutputPerMTok: 1.25, Currency: "USD"},
+	"opus":              {InputPerMTok: 15, OutputPerMTok: 75, Currency: "USD"},
+	"sonnet":            {InputPerMTok: 3, OutputPerMTok: 15, Currency: "USD"},
+	"haiku":             {InputPerMTok: 1, OutputPerMTok: 5, Currency: "USD"},
+}
+
+// Default returns a copy of the built-in price table for Anthropic API models
+// in USD. The copy can be modified without affecting other users.
+func Default() Table {
+	table := make(Table, len(defaultTable))
+	for model, price := range defaultTable {
+		table[model] = price
+	}
+	return table
+}
+
+// Cost computes the cost of a usage record for a model using the provider.
+func Cost(provider Provider, model string, usage types.TokenUsage) (float64, error) {
+	price, ok := provider.Price(model)
+	if !ok {
+		return 0, fmt.Errorf("no pricing available for model %q", model)
+	}
+	return price.Cost(usage), nil
+}
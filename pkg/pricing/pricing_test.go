@@ -0,0 +1,154 @@
+package pricing
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestTable_Price(t *testing.T) {
+	table := Default()
+
+	tests := map[string]float64{
+		"claude-opus-4-5-20251101":                   5,
+		"claude-opus-4-1-20250805":                   15,
+		"claude-3-5-haiku-20241022":                  0.8,
+		"claude-3-5-sonnet-20241022":                 3,
+		"anthropic.claude-3-haiku-20240307-v1:0":     0.25,
+		"us.anthropic.claude-sonnet-4-20250514-v1:0": 3,
+		"claude-3-5-sonnet-v2@20241022":              3,
+		"claude-opus-4@20250514":                     15,
+	}
+
+	for model, expected := range tests {
+		price, ok := table.Price(model)
+		if !ok || price.InputPerMTok != expected {
+			t.Errorf("Price(%s) = %+v, want input %f", model, price, expected)
+		}
+	}
+
+	if _, ok := table.Price("custom-model"); ok {
+		t.Error("Expected no price for unknown model")
+	}
+}
+
+func TestDefault_ReturnsCopy(t *testing.T) {
+	table := Default()
+	table["claude-sonnet-4"] = Price{InputPerMTok: 100}
+
+	if price, _ := Default().Price("claude-sonnet-4"); price.InputPerMTok != 3 {
+		t.Errorf("Expected built-in table to be unaffected, got %+v", price)
+	}
+}
+
+func TestChain_Price(t *testing.T) {
+	chain := Chain{
+		nil,
+		ProviderFunc(func(model string) (Price, bool) {
+			if model == "claude-sonnet-4" {
+				return Price{InputPerMTok: 1, Currency: "EUR"}, true
+			}
+			return Price{}, false
+		}),
+		Default(),
+	}
+
+	if price, _ := chain.Price("claude-sonnet-4"); price.Currency != "EUR" {
+		t.Errorf("Expected first provider to win, got %+v", price)
+	}
+	if price, _ := chain.Price("claude-3-haiku"); price.InputPerMTok != 0.25 {
+		t.Errorf("Expected fallback to default table, got %+v", price)
+	}
+}
+
+func TestPrice_Cost(t *testing.T) {
+	price := Price{InputPerMTok: 3, OutputPerMTok: 15, Currency: "USD"}
+
+	cost := price.Cost(types.TokenUsage{InputTokens: 2000, OutputTokens: 1000})
+	if math.Abs(cost-0.021) > 1e-12 {
+		t.Errorf("Expected cost 0.021, got %f", cost)
+	}
+
+	roundTrip := FromModelPricing(price.ModelPricing())
+	if math.Abs(roundTrip.InputPerMTok-3) > 1e-9 || math.Abs(roundTrip.OutputPerMTok-15) > 1e-9 {
+		t.Errorf("Unexpected round trip: %+v", roundTrip)
+	}
+
+	if _, err := Cost(Default(), "custom-model", types.TokenUsage{}); err == nil {
+		t.Error("Expected error for unknown model")
+	}
+}
+
+func TestUsageTracker(t *testing.T) {
+	tracker := NewUsageTracker(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracker.Record("claude-sonnet-4-20250514", types.TokenUsage{InputTokens: 100000, OutputTokens: 10000})
+		}()
+	}
+	wg.Wait()
+	tracker.Record("custom-model", types.TokenUsage{InputTokens: 10})
+
+	usage := tracker.Usage()
+	if len(usage) != 2 {
+		t.Fatalf("Expected 2 models, got %d", len(usage))
+	}
+	sonnet := usage[0]
+	if sonnet.Requests != 10 || sonnet.InputTokens != 1000000 || sonnet.OutputTokens != 100000 || !sonnet.Priced {
+		t.Errorf("Unexpected usage: %+v", sonnet)
+	}
+	if usage[1].Priced || usage[1].Cost != 0 {
+		t.Errorf("Expected unknown model to be unpriced, got %+v", usage[1])
+	}
+	if math.Abs(tracker.TotalCost()-4.5) > 1e-9 {
+		t.Errorf("Expected total cost 4.5, got %f", tracker.TotalCost())
+	}
+
+	tracker.Reset()
+	if len(tracker.Usage()) != 0 {
+		t.Error("Expected usage to be cleared")
+	}
+}
+
+func TestUsageTracker_Labels(t *testing.T) {
+	tracker := NewUsageTracker(nil)
+	model := "claude-sonnet-4-20250514"
+
+	tracker.RecordLabeled(model, types.TokenUsage{InputTokens: 100}, map[string]string{"feature": "search", "user": "u1"})
+	tracker.RecordLabeled(model, types.TokenUsage{InputTokens: 50}, map[string]string{"feature": "search"})
+	tracker.RecordLabeled(model, types.TokenUsage{InputTokens: 10}, map[string]string{"feature": "chat"})
+	tracker.Record(model, types.TokenUsage{InputTokens: 1})
+
+	if usage := tracker.Usage(); len(usage) != 1 || usage[0].Requests != 4 || usage[0].InputTokens != 161 {
+		t.Errorf("Labels should not split the model totals, got %+v", usage)
+	}
+
+	byFeature := tracker.UsageByLabel("feature")
+	if len(byFeature) != 2 {
+		t.Fatalf("Expected 2 features, got %v", byFeature)
+	}
+	search := byFeature["search"]
+	if len(search) != 1 || search[0].Requests != 2 || search[0].InputTokens != 150 || !search[0].Priced {
+		t.Errorf("Unexpected search usage: %+v", search)
+	}
+	if chat := byFeature["chat"]; len(chat) != 1 || chat[0].InputTokens != 10 {
+		t.Errorf("Unexpected chat usage: %+v", chat)
+	}
+	if users := tracker.UsageByLabel("user"); len(users) != 1 || users["u1"][0].InputTokens != 100 {
+		t.Errorf("Unexpected user usage: %+v", users)
+	}
+	if none := tracker.UsageByLabel("experiment"); len(none) != 0 {
+		t.Errorf("Expected no usage for an unused label, got %v", none)
+	}
+
+	tracker.Reset()
+	if len(tracker.UsageByLabel("feature")) != 0 {
+		t.Error("Expected labeled usage to be cleared")
+	}
+}
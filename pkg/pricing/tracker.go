@@ -0,0 +1,151 @@
+package pricing
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// ModelUsage is the accumulated usage and cost of a single model.
+type ModelUsage struct {
+	// Model is the model name
+	Model string `json:"model"`
+
+	// Requests is the number of recorded requests
+	Requests int `json:"requests"`
+
+	// InputTokens is the total number of input tokens
+	InputTokens int `json:"input_tokens"`
+
+	// OutputTokens is the total number of output tokens
+	OutputTokens int `json:"output_tokens"`
+
+	// Cost is the total cost of the recorded usage
+	Cost float64 `json:"cost"`
+
+	// Priced is false when no price was known for the model, leaving Cost at zero
+	Priced bool `json:"priced"`
+}
+
+// UsageTracker accumulates token usage and cost per model, and per model
+// for each value of the labels usage is recorded with. It is safe for
+// concurrent use.
+type UsageTracker struct {
+	provider Provider
+	usage    map[string]*ModelUsage
+	labeled  map[string]map[string]map[string]*ModelUsage // label, value, model
+	mu       sync.Mutex
+}
+
+// NewUsageTracker creates a tracker that prices usage with the given provider.
+// A nil provider uses the built-in price table.
+func NewUsageTracker(provider Provider) *UsageTracker {
+	if provider == nil {
+		provider = Default()
+	}
+	return &UsageTracker{
+		provider: provider,
+		usage:    make(map[string]*ModelUsage),
+		labeled:  make(map[string]map[string]map[string]*ModelUsage),
+	}
+}
+
+// Record adds the usage of one request to the model's totals.
+func (t *UsageTracker) Record(model string, usage types.TokenUsage) {
+	t.RecordLabeled(model, usage, nil)
+}
+
+// RecordLabeled adds the usage of one request to the model's totals and to
+// its totals for each label, such as the feature or customer the request was
+// made for.
+func (t *UsageTracker) RecordLabeled(model string, usage types.TokenUsage, labels map[string]string) {
+	price, priced := t.provider.Price(model)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	addUsage(t.usage, model, usage, price, priced)
+	for label, value := range labels {
+		values, ok := t.labeled[label]
+		if !ok {
+			values = make(map[string]map[string]*ModelUsage)
+			t.labeled[label] = values
+		}
+		models, ok := values[value]
+		if !ok {
+			models = make(map[string]*ModelUsage)
+			values[value] = models
+		}
+		addUsage(models, model, usage, price, priced)
+	}
+}
+
+// addUsage adds usage to a model's entry in totals.
+func addUsage(totals map[string]*ModelUsage, model string, usage types.TokenUsage, price Price, priced bool) {
+	entry, ok := totals[model]
+	if !ok {
+		entry = &ModelUsage{Model: model}
+		totals[model] = entry
+	}
+
+	entry.Requests++
+	entry.InputTokens += usage.InputTokens
+	entry.OutputTokens += usage.OutputTokens
+	if priced {
+		entry.Cost += price.Cost(usage)
+		entry.Priced = true
+	}
+}
+
+// Usage returns the accumulated usage per model, sorted by model name.
+func (t *UsageTracker) Usage() []ModelUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return sortedUsage(t.usage)
+}
+
+// UsageByLabel returns the usage per model recorded for each value of a
+// label, such as the usage of every "feature". Usage recorded without the
+// label is left out.
+func (t *UsageTracker) UsageByLabel(label string) map[string][]ModelUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	byValue := make(map[string][]ModelUsage, len(t.labeled[label]))
+	for value, models := range t.labeled[label] {
+		byValue[value] = sortedUsage(models)
+	}
+	return byValue
+}
+
+// sortedUsage copies totals into a slice sorted by model name.
+func sortedUsage(totals map[string]*ModelUsage) []ModelUsage {
+	usage := make([]ModelUsage, 0, len(totals))
+	for _, entry := range totals {
+		usage = append(usage, *entry)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Model < usage[j].Model })
+	return usage
+}
+
+// TotalCost returns the total cost across all models.
+func (t *UsageTracker) TotalCost() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	total := 0.0
+	for _, entry := range t.usage {
+		total += entry.Cost
+	}
+	return total
+}
+
+// Reset clears all accumulated usage.
+func (t *UsageTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.usage = make(map[string]*ModelUsage)
+	t.labeled = make(map[string]map[string]map[string]*ModelUsage)
+}
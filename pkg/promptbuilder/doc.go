@@ -0,0 +1,33 @@
+/*
+Package promptbuilder assembles prompts for Claude Code queries within a token budget.
+
+A Builder combines a system prompt, optional project context, and prior
+conversation messages. When the assembled prompt would exceed the budget, the
+oldest messages are dropped first; if a Summarizer is configured, the dropped
+turns are replaced by a short summary message so long sessions keep their
+history without silently overflowing the context window.
+
+# Basic Usage
+
+	builder := promptbuilder.New(100000,
+		promptbuilder.WithResponseReserve(4096),
+	)
+	builder.SetSystemPrompt("You are a careful Go reviewer.")
+	builder.AddProjectContext(projectContext)
+	builder.AddMessages(history...)
+
+	prompt, err := builder.Build(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	request := &types.QueryRequest{MaxTokens: 4096}
+	prompt.Apply(request)
+
+# Token Counting
+
+Token counts are estimates. The default tokenizer is the SDK's shared
+estimator, types.EstimateTokens, which the client's CountTokens also uses;
+supply WithTokenizer to plug in a more accurate counter.
+*/
+package promptbuilder
@@ -0,0 +1,273 @@
+package promptbuilder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// defaultMessageOverhead is the estimated per-message token cost of role markers
+	defaultMessageOverhead = 4
+
+	// summaryPrefix introduces the summary of dropped turns
+	summaryPrefix = "Summary of the earlier conversation:\n"
+)
+
+// Tokenizer counts the tokens in a piece of text.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// TokenizerFunc adapts a function to the Tokenizer interface.
+type TokenizerFunc func(text string) int
+
+// CountTokens calls f(text).
+func (f TokenizerFunc) CountTokens(text string) int {
+	return f(text)
+}
+
+// EstimateTokenizer estimates token counts with types.EstimateTokens, the
+// estimator the client uses for CountTokens.
+type EstimateTokenizer struct{}
+
+// CountTokens returns the estimated number of tokens in text.
+func (EstimateTokenizer) CountTokens(text string) int {
+	return types.EstimateTokens(text)
+}
+
+// Summarizer condenses conversation turns that no longer fit in the budget
+// into a short summary.
+type Summarizer func(ctx context.Context, messages []types.Message) (string, error)
+
+// Option configures a Builder.
+type Option func(*Builder)
+
+// WithTokenizer sets the tokenizer used to measure prompt parts.
+func WithTokenizer(tokenizer Tokenizer) Option {
+	return func(b *Builder) {
+		b.tokenizer = tokenizer
+	}
+}
+
+// WithSummarizer sets the summarizer used for dropped turns. Without a
+// summarizer, dropped turns are simply truncated.
+func WithSummarizer(summarizer Summarizer) Option {
+	return func(b *Builder) {
+		b.summarizer = summarizer
+	}
+}
+
+// WithResponseReserve reserves tokens of the budget for the model's response.
+func WithResponseReserve(tokens int) Option {
+	return func(b *Builder) {
+		b.reserve = tokens
+	}
+}
+
+// WithMessageOverhead sets the estimated token overhead added to each message.
+func WithMessageOverhead(tokens int) Option {
+	return func(b *Builder) {
+		b.messageOverhead = tokens
+	}
+}
+
+// Builder assembles a system prompt, project context, and conversation
+// messages within a token budget.
+type Builder struct {
+	budget          int
+	reserve         int
+	messageOverhead int
+	tokenizer       Tokenizer
+	summarizer      Summarizer
+
+	systemPrompt   string
+	projectContext string
+	messages       []types.Message
+}
+
+// Prompt is the result of assembling a prompt.
+type Prompt struct {
+	// System is the system prompt including any project context
+	System string `json:"system"`
+
+	// Messages are the conversation messages that fit in the budget
+	Messages []types.Message `json:"messages"`
+
+	// TokenCount is the estimated number of prompt tokens
+	TokenCount int `json:"token_count"`
+
+	// Budget is the number of tokens available to the prompt
+	Budget int `json:"budget"`
+
+	// DroppedMessages is the number of oldest messages that did not fit
+	DroppedMessages int `json:"dropped_messages"`
+
+	// Summarized is true when dropped messages were replaced by a summary
+	Summarized bool `json:"summarized"`
+}
+
+// New creates a builder with the given total token budget.
+func New(budget int, opts ...Option) *Builder {
+	b := &Builder{
+		budget:          budget,
+		messageOverhead: defaultMessageOverhead,
+		tokenizer:       EstimateTokenizer{},
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// SetSystemPrompt sets the system prompt.
+func (b *Builder) SetSystemPrompt(prompt string) *Builder {
+	b.systemPrompt = prompt
+	return b
+}
+
+// AddProjectContext appends a description of the project to the system prompt.
+func (b *Builder) AddProjectContext(projectContext *types.ProjectContext) *Builder {
+	if projectContext != nil {
+		b.projectContext = FormatProjectContext(projectContext)
+	}
+	return b
+}
+
+// AddMessages appends conversation messages, oldest first.
+func (b *Builder) AddMessages(messages ...types.Message) *Builder {
+	b.messages = append(b.messages, messages...)
+	return b
+}
+
+// Build assembles the prompt. It returns a validation error if the system
+// prompt or the most recent message alone exceed the budget.
+func (b *Builder) Build(ctx context.Context) (*Prompt, error) {
+	available := b.budget - b.reserve
+	if available <= 0 {
+		return nil, sdkerrors.NewValidationError("budget", fmt.Sprint(b.budget), "greater than response reserve",
+			"prompt budget must be larger than the response reserve")
+	}
+
+	system := b.systemPrompt
+	if b.projectContext != "" {
+		if system != "" {
+			system += "\n\n"
+		}
+		system += b.projectContext
+	}
+
+	used := b.tokenizer.CountTokens(system)
+	if used > available {
+		return nil, sdkerrors.NewValidationError("system", "", fmt.Sprintf("at most %d tokens", available),
+			fmt.Sprintf("system prompt needs %d tokens but only %d are available", used, available))
+	}
+
+	// Keep the newest messages that fit
+	costs := make([]int, len(b.messages))
+	first := len(b.messages)
+	for i := len(b.messages) - 1; i >= 0; i-- {
+		costs[i] = b.messageTokens(b.messages[i])
+		if used+costs[i] > available {
+			break
+		}
+		used += costs[i]
+		first = i
+	}
+
+	if len(b.messages) > 0 && first == len(b.messages) {
+		return nil, sdkerrors.NewValidationError("messages", "", fmt.Sprintf("at most %d tokens", available-b.tokenizer.CountTokens(system)),
+			"the most recent message does not fit in the prompt budget")
+	}
+
+	prompt := &Prompt{
+		System:          system,
+		Messages:        append([]types.Message(nil), b.messages[first:]...),
+		Budget:          available,
+		DroppedMessages: first,
+	}
+
+	if first > 0 && b.summarizer != nil {
+		// Making room for the summary drops more old turns, which must then be
+		// summarized too, so summarize until the dropped range is stable. The
+		// newest message is always kept.
+		summarized, room := first, used
+		for {
+			summary, err := b.summarizer(ctx, b.messages[:summarized])
+			if err != nil {
+				return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SUMMARIZE", "failed to summarize dropped messages")
+			}
+
+			summaryMsg := types.Message{Role: types.RoleUser, Content: summaryPrefix + summary}
+			summaryCost := b.messageTokens(summaryMsg)
+			if room+summaryCost <= available {
+				used = room + summaryCost
+				first = summarized
+				prompt.Messages = append([]types.Message{summaryMsg}, b.messages[first:]...)
+				prompt.DroppedMessages = first
+				prompt.Summarized = true
+				break
+			}
+
+			next := summarized
+			for room+summaryCost > available && next < len(b.messages)-1 {
+				room -= costs[next]
+				next++
+			}
+			if next == summarized {
+				break
+			}
+			summarized = next
+		}
+	}
+
+	prompt.TokenCount = used
+	return prompt, nil
+}
+
+// messageTokens returns the estimated token cost of a message.
+func (b *Builder) messageTokens(msg types.Message) int {
+	tokens := b.tokenizer.CountTokens(msg.Content) + b.messageOverhead
+	for _, call := range msg.ToolCalls {
+		tokens += b.tokenizer.CountTokens(call.Function.Name) + b.tokenizer.CountTokens(call.Function.Arguments)
+	}
+	return tokens
+}
+
+// Apply copies the assembled system prompt and messages into a query request.
+func (p *Prompt) Apply(request *types.QueryRequest) {
+	request.System = p.System
+	request.Messages = p.Messages
+}
+
+// FormatProjectContext renders project context as plain text for a system prompt.
+func FormatProjectContext(projectContext *types.ProjectContext) string {
+	var b strings.Builder
+
+	b.WriteString("Project context:\n")
+	fmt.Fprintf(&b, "Working directory: %s\n", projectContext.WorkingDirectory)
+	for _, dir := range projectContext.AdditionalDirectories {
+		fmt.Fprintf(&b, "Additional directory: %s\n", dir)
+	}
+
+	if projectContext.Git != nil {
+		b.WriteString(projectContext.Git.Summary())
+	}
+
+	if projectContext.Dependencies != nil {
+		direct := projectContext.Dependencies.Direct()
+		if len(direct) > 0 {
+			b.WriteString("Dependencies:\n")
+			for _, dep := range direct {
+				fmt.Fprintf(&b, "  %s %s (%s)\n", dep.Name, dep.Version, dep.Ecosystem)
+			}
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,155 @@
+package promptbuilder
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// wordTokenizer counts one token per whitespace-separated word.
+var wordTokenizer = TokenizerFunc(func(text string) int {
+	return len(strings.Fields(text))
+})
+
+// turns creates alternating user/assistant messages of the given word counts.
+func turns(wordCounts ...int) []types.Message {
+	messages := make([]types.Message, len(wordCounts))
+	for i, count := range wordCounts {
+		role := types.RoleUser
+		if i%2 == 1 {
+			role = types.RoleAssistant
+		}
+		messages[i] = types.Message{Role: role, Content: strings.TrimSpace(strings.Repeat("w ", count))}
+	}
+	return messages
+}
+
+func TestBuilder_FitsWithinBudget(t *testing.T) {
+	builder := New(100, WithTokenizer(wordTokenizer), WithMessageOverhead(0))
+	builder.SetSystemPrompt("be brief").AddMessages(turns(10, 20, 30)...)
+
+	prompt, err := builder.Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if len(prompt.Messages) != 3 || prompt.DroppedMessages != 0 {
+		t.Errorf("Expected all messages to fit, got %d (dropped %d)", len(prompt.Messages), prompt.DroppedMessages)
+	}
+	if prompt.TokenCount != 62 {
+		t.Errorf("Expected 62 tokens, got %d", prompt.TokenCount)
+	}
+}
+
+func TestBuilder_TruncatesOldestTurns(t *testing.T) {
+	builder := New(60, WithTokenizer(wordTokenizer), WithMessageOverhead(0), WithResponseReserve(10))
+	builder.AddMessages(turns(30, 20, 25)...)
+
+	prompt, err := builder.Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if prompt.DroppedMessages != 1 || len(prompt.Messages) != 2 {
+		t.Fatalf("Expected oldest message to be dropped, got %d messages (dropped %d)", len(prompt.Messages), prompt.DroppedMessages)
+	}
+	if prompt.Messages[0].Role != types.RoleAssistant || prompt.TokenCount > prompt.Budget {
+		t.Errorf("Unexpected prompt: %+v", prompt)
+	}
+}
+
+func TestBuilder_SummarizesDroppedTurns(t *testing.T) {
+	var calls []int
+	summarizer := func(ctx context.Context, messages []types.Message) (string, error) {
+		calls = append(calls, len(messages))
+		return "earlier stuff", nil
+	}
+
+	builder := New(50, WithTokenizer(wordTokenizer), WithMessageOverhead(0), WithSummarizer(summarizer))
+	builder.AddMessages(turns(20, 20, 20, 25)...)
+
+	prompt, err := builder.Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if !prompt.Summarized {
+		t.Fatal("Expected dropped turns to be summarized")
+	}
+	if !strings.Contains(prompt.Messages[0].Content, "earlier stuff") {
+		t.Errorf("Expected summary message first, got %q", prompt.Messages[0].Content)
+	}
+	// The third turn is dropped to make room for the summary, so it is
+	// summarized too
+	if len(calls) != 2 || calls[0] != 2 || calls[1] != 3 || prompt.DroppedMessages != 3 {
+		t.Errorf("Expected summaries of 2 then 3 turns with 3 dropped, got %v and %d", calls, prompt.DroppedMessages)
+	}
+	if prompt.TokenCount > prompt.Budget {
+		t.Errorf("Prompt exceeds budget: %d > %d", prompt.TokenCount, prompt.Budget)
+	}
+}
+
+func TestBuilder_BudgetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+	}{
+		{"reserve exceeds budget", New(10, WithResponseReserve(10))},
+		{"system prompt too large", New(5, WithTokenizer(wordTokenizer)).SetSystemPrompt("one two three four five six")},
+		{"latest message too large", New(5, WithTokenizer(wordTokenizer)).AddMessages(turns(10)...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build(context.Background())
+			var validationErr *sdkerrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBuilder_ProjectContext(t *testing.T) {
+	projectContext := &types.ProjectContext{
+		WorkingDirectory: "/src/app",
+		Git:              &types.GitInfo{Branch: "main"},
+		Dependencies: &types.DependencyGraph{Dependencies: []types.Dependency{
+			{Name: "github.com/google/uuid", Version: "v1.6.0", Ecosystem: types.EcosystemGo, Kind: types.DependencyDirect},
+			{Name: "golang.org/x/sys", Version: "v0.1.0", Ecosystem: types.EcosystemGo, Kind: types.DependencyIndirect},
+		}},
+	}
+
+	prompt, err := New(1000).SetSystemPrompt("system").AddProjectContext(projectContext).Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	for _, expected := range []string{"system\n\nProject context:", "/src/app", "Branch: main", "github.com/google/uuid v1.6.0"} {
+		if !strings.Contains(prompt.System, expected) {
+			t.Errorf("Expected system prompt to contain %q, got:\n%s", expected, prompt.System)
+		}
+	}
+	if strings.Contains(prompt.System, "golang.org/x/sys") {
+		t.Error("Expected indirect dependencies to be omitted")
+	}
+
+	request := &types.QueryRequest{}
+	prompt.Apply(request)
+	if request.System != prompt.System {
+		t.Error("Expected Apply to copy the system prompt")
+	}
+}
+
+func TestEstimateTokenizer(t *testing.T) {
+	if got := (EstimateTokenizer{}).CountTokens(""); got != 0 {
+		t.Errorf("Expected 0 tokens for empty text, got %d", got)
+	}
+	if got, want := (EstimateTokenizer{}).CountTokens("func main() {}"), types.EstimateTokens("func main() {}"); got != want {
+		t.Errorf("Expected the shared estimate of %d tokens, got %d", want, got)
+	}
+}
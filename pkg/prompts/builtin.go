@@ -0,0 +1,73 @@
+package prompts
+
+// Names of the built-in templates.
+const (
+	CodeReview    = "code-review"
+	Explain       = "explain"
+	GenerateTests = "generate-tests"
+	FixBug        = "fix-bug"
+	Refactor      = "refactor"
+)
+
+// builtins are registered in the Default library.
+var builtins = []Template{
+	{
+		Name:        CodeReview,
+		Description: "Review code for bugs, security issues and style",
+		SystemPrompt: `You are an experienced {{.language}} reviewer. Report concrete problems ` +
+			`with file and line references, ordered by severity.`,
+		Text: `Review the following {{.language}} code{{if .focus}}, focusing on {{.focus}}{{end}}.
+
+{{.code}}`,
+		Required: []string{"code"},
+		Defaults: map[string]any{"language": "Go", "focus": ""},
+	},
+	{
+		Name:        Explain,
+		Description: "Explain what code does",
+		Text: `Explain what the following {{.language}} code does{{if .audience}} for {{.audience}}{{end}}.
+
+{{.code}}`,
+		Required: []string{"code"},
+		Defaults: map[string]any{"language": "Go", "audience": ""},
+	},
+	{
+		Name:        GenerateTests,
+		Description: "Write tests for code",
+		Text: `Write {{.framework}} tests for the following {{.language}} code. ` +
+			`Cover edge cases and error paths{{if .file}}, and save them next to {{.file}}{{end}}.
+
+{{.code}}`,
+		Required: []string{"code"},
+		Defaults: map[string]any{"language": "Go", "framework": "table-driven testing", "file": ""},
+	},
+	{
+		Name:        FixBug,
+		Description: "Find and fix a described bug",
+		Text: `Fix this bug: {{.description}}
+{{if .file}}
+The bug is likely in {{.file}}.{{end}}{{if .error}}
+The error output is:
+
+{{.error}}{{end}}
+
+Find the root cause, make the smallest fix, and explain it.`,
+		Required: []string{"description"},
+		Defaults: map[string]any{"file": "", "error": ""},
+	},
+	{
+		Name:        Refactor,
+		Description: "Refactor code toward a goal without changing behavior",
+		Text:        `Refactor {{.target}} to {{.goal}}. Keep the behavior unchanged and the existing tests passing.`,
+		Required:    []string{"target", "goal"},
+	},
+}
+
+// newDefaultLibrary returns a library with the built-in templates.
+func newDefaultLibrary() *Library {
+	library := NewLibrary()
+	for _, t := range builtins {
+		library.MustRegister(t)
+	}
+	return library
+}
@@ -0,0 +1,35 @@
+/*
+Package prompts provides a library of named prompt templates with variable
+substitution, replacing ad-hoc fmt.Sprintf prompt building.
+
+Templates use text/template syntax with variables referenced as {{.name}}.
+Required variables must be set to a non-empty value, and every variable a
+template uses must either be set or have a default, so a typo fails loudly
+instead of sending "<no value>" to Claude.
+
+# Basic Usage
+
+	library := prompts.NewLibrary()
+	library.MustRegister(prompts.Template{
+		Name:     "migration",
+		Text:     "Write a {{.dialect}} migration that {{.change}}.",
+		Required: []string{"change"},
+		Defaults: map[string]any{"dialect": "PostgreSQL"},
+	})
+
+	prompt, err := library.Render("migration", map[string]any{"change": "adds an email index"})
+
+With a client, QueryTemplate renders and runs a template in one call:
+
+	client.SetPromptLibrary(library)
+	result, err := client.QueryTemplate(ctx, "migration", vars, nil)
+
+# Built-in Templates
+
+The Default library starts with CodeReview, Explain, GenerateTests, FixBug
+and Refactor. Templates can also be loaded from *.tmpl files with LoadDir.
+
+Helper functions available in templates are join, lower, upper, trim and
+indent.
+*/
+package prompts
@@ -0,0 +1,234 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// ErrTemplateNotFound is returned when no template is registered under a name.
+var ErrTemplateNotFound = errors.New("prompt template not found")
+
+// TemplateExt is the extension of template files loaded by LoadDir.
+const TemplateExt = ".tmpl"
+
+// Template is a named prompt written in text/template syntax, with
+// variables referenced as {{.name}}.
+type Template struct {
+	// Name identifies the template, e.g. "code-review"
+	Name string
+
+	// Description explains what the template is for
+	Description string
+
+	// Text is the prompt template
+	Text string
+
+	// SystemPrompt is an optional system prompt template rendered with the
+	// same variables
+	SystemPrompt string
+
+	// Required lists variables that must be set to a non-empty value
+	Required []string
+
+	// Defaults are used for variables the caller does not set. Every
+	// variable the template uses must be set or have a default.
+	Defaults map[string]any
+}
+
+// Prompt is a rendered template.
+type Prompt struct {
+	// Text is the rendered prompt
+	Text string
+
+	// SystemPrompt is the rendered system prompt, if the template has one
+	SystemPrompt string
+}
+
+// funcs are the helper functions available to templates.
+var funcs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"indent": func(spaces int, text string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(text, "\n", "\n"+pad)
+	},
+}
+
+// compiled is a registered template with its parsed text.
+type compiled struct {
+	spec   Template
+	text   *template.Template
+	system *template.Template
+}
+
+// Library is a set of named templates. It is safe for concurrent use.
+type Library struct {
+	mu        sync.RWMutex
+	templates map[string]*compiled
+}
+
+// NewLibrary returns an empty library.
+func NewLibrary() *Library {
+	return &Library{templates: make(map[string]*compiled)}
+}
+
+// Register parses a template and adds it to the library, replacing any
+// template with the same name.
+func (l *Library) Register(t Template) error {
+	if t.Name == "" {
+		return sdkerrors.NewValidationError("name", "", "required", "template name cannot be empty")
+	}
+	if strings.TrimSpace(t.Text) == "" {
+		return sdkerrors.NewValidationError("text", t.Name, "required", "template text cannot be empty")
+	}
+
+	c := &compiled{spec: t}
+	var err error
+	if c.text, err = parse(t.Name, t.Text); err != nil {
+		return err
+	}
+	if t.SystemPrompt != "" {
+		if c.system, err = parse(t.Name+" system prompt", t.SystemPrompt); err != nil {
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.templates[t.Name] = c
+	return nil
+}
+
+// parse compiles template text, failing on variables that are not set.
+func parse(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "TEMPLATE_PARSE", "failed to parse prompt template "+name)
+	}
+	return tmpl, nil
+}
+
+// MustRegister is like Register but panics if the template does not parse.
+func (l *Library) MustRegister(t Template) {
+	if err := l.Register(t); err != nil {
+		panic(err)
+	}
+}
+
+// LoadDir registers every *.tmpl file in dir, named after the file without
+// its extension. Loaded templates have no required variables or defaults.
+func (l *Library) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+TemplateExt))
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "TEMPLATE_LOAD", "invalid template directory")
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path) // #nosec G304 - templates are read from a caller-supplied directory
+		if err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "FILE_READ", "failed to read prompt template")
+		}
+		name := strings.TrimSuffix(filepath.Base(path), TemplateExt)
+		if err := l.Register(Template{Name: name, Text: string(data)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the template registered under name.
+func (l *Library) Get(name string) (Template, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	c, ok := l.templates[name]
+	if !ok {
+		return Template{}, false
+	}
+	return c.spec, true
+}
+
+// Names returns the registered template names in sorted order.
+func (l *Library) Names() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	names := make([]string, 0, len(l.templates))
+	for name := range l.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render fills a template with vars. It fails if the template is unknown,
+// a required variable is empty, or the template uses a variable that is
+// neither set nor defaulted.
+func (l *Library) Render(name string, vars map[string]any) (*Prompt, error) {
+	l.mu.RLock()
+	c, ok := l.templates[name]
+	l.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	data := make(map[string]any, len(c.spec.Defaults)+len(vars))
+	for key, value := range c.spec.Defaults {
+		data[key] = value
+	}
+	for key, value := range vars {
+		data[key] = value
+	}
+
+	for _, key := range c.spec.Required {
+		if value, ok := data[key]; !ok || value == nil || fmt.Sprint(value) == "" {
+			return nil, sdkerrors.NewValidationError(key, "", "required", fmt.Sprintf("template %s requires variable %q", name, key))
+		}
+	}
+
+	prompt := &Prompt{}
+	var err error
+	if prompt.Text, err = execute(c.text, data); err != nil {
+		return nil, err
+	}
+	if c.system != nil {
+		if prompt.SystemPrompt, err = execute(c.system, data); err != nil {
+			return nil, err
+		}
+	}
+	return prompt, nil
+}
+
+// execute renders a parsed template.
+func execute(tmpl *template.Template, data map[string]any) (string, error) {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "TEMPLATE_RENDER", "failed to render prompt template "+tmpl.Name())
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// Default is the library used by the package-level functions and by
+// client.QueryTemplate unless another library is set. It starts with the
+// built-in templates.
+var Default = newDefaultLibrary()
+
+// Register adds a template to the Default library.
+func Register(t Template) error {
+	return Default.Register(t)
+}
+
+// Render fills a template from the Default library.
+func Render(name string, vars map[string]any) (*Prompt, error) {
+	return Default.Render(name, vars)
+}
@@ -0,0 +1,125 @@
+package prompts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLibrary_Render(t *testing.T) {
+	library := NewLibrary()
+	library.MustRegister(Template{
+		Name:         "summarize",
+		Text:         "Summarize {{.file}} in {{.words}} words.{{if .tags}} Tags: {{join .tags \", \"}}{{end}}",
+		SystemPrompt: "Write for {{.audience}}.",
+		Required:     []string{"file"},
+		Defaults:     map[string]any{"words": 50, "tags": nil, "audience": "engineers"},
+	})
+
+	prompt, err := library.Render("summarize", map[string]any{"file": "main.go", "tags": []string{"go", "cli"}})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if prompt.Text != "Summarize main.go in 50 words. Tags: go, cli" || prompt.SystemPrompt != "Write for engineers." {
+		t.Errorf("Unexpected prompt: %+v", prompt)
+	}
+
+	if _, err := library.Render("summarize", map[string]any{"file": ""}); err == nil || !strings.Contains(err.Error(), "file") {
+		t.Errorf("Expected a required variable error, got %v", err)
+	}
+	if _, err := library.Render("missing", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound, got %v", err)
+	}
+}
+
+func TestLibrary_RenderUndefinedVariable(t *testing.T) {
+	library := NewLibrary()
+	library.MustRegister(Template{Name: "t", Text: "Hello {{.name}}"})
+
+	if _, err := library.Render("t", map[string]any{"other": 1}); err == nil {
+		t.Error("Expected error for a variable that is neither set nor defaulted")
+	}
+}
+
+func TestLibrary_Register(t *testing.T) {
+	library := NewLibrary()
+
+	tests := []struct {
+		name     string
+		template Template
+	}{
+		{"no name", Template{Text: "x"}},
+		{"no text", Template{Name: "t", Text: "  "}},
+		{"bad syntax", Template{Name: "t", Text: "{{.x"}},
+		{"bad system prompt", Template{Name: "t", Text: "x", SystemPrompt: "{{if}}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := library.Register(tt.template); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	if len(library.Names()) != 0 {
+		t.Errorf("Expected no templates, got %v", library.Names())
+	}
+}
+
+func TestLibrary_LoadDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "release-notes.tmpl"), []byte("Write release notes for {{.version}}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a template"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	library := NewLibrary()
+	if err := library.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	if names := library.Names(); !reflect.DeepEqual(names, []string{"release-notes"}) {
+		t.Fatalf("Names() = %v", names)
+	}
+
+	prompt, err := library.Render("release-notes", map[string]any{"version": "v1.2.0"})
+	if err != nil || prompt.Text != "Write release notes for v1.2.0" {
+		t.Errorf("Unexpected render: %+v, %v", prompt, err)
+	}
+}
+
+func TestDefaultTemplates(t *testing.T) {
+	for _, name := range []string{CodeReview, Explain, GenerateTests, FixBug, Refactor} {
+		spec, ok := Default.Get(name)
+		if !ok {
+			t.Fatalf("Missing built-in template %s", name)
+		}
+
+		vars := map[string]any{}
+		for _, key := range spec.Required {
+			vars[key] = "value of " + key
+		}
+		prompt, err := Render(name, vars)
+		if err != nil {
+			t.Errorf("Render(%s) failed: %v", name, err)
+			continue
+		}
+		for _, key := range spec.Required {
+			if !strings.Contains(prompt.Text, "value of "+key) {
+				t.Errorf("Expected %s in %s prompt: %q", key, name, prompt.Text)
+			}
+		}
+	}
+
+	prompt, err := Render(FixBug, map[string]any{"description": "panic on empty input", "file": "parse.go"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(prompt.Text, "The bug is likely in parse.go.") || strings.Contains(prompt.Text, "error output") {
+		t.Errorf("Unexpected fix-bug prompt: %q", prompt.Text)
+	}
+}
@@ -0,0 +1,129 @@
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CassetteVersion is the version of the cassette file format written by this package.
+const CassetteVersion = 1
+
+// Stream names used in frames.
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// Cassette is a portable recording of claude CLI interactions.
+type Cassette struct {
+	// Version is the cassette file format version
+	Version int `json:"version"`
+
+	// RecordedAt is when recording started
+	RecordedAt time.Time `json:"recorded_at"`
+
+	// Interactions are the recorded CLI invocations in the order they started
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded claude CLI invocation.
+type Interaction struct {
+	// ID is the zero-based position of the interaction in the cassette
+	ID int `json:"id"`
+
+	// Request describes how the CLI was invoked
+	Request Request `json:"request"`
+
+	// Frames are the raw protocol frames read from the CLI, in order
+	Frames []Frame `json:"frames"`
+
+	// ExitCode is the exit code of the CLI process
+	ExitCode int `json:"exit_code"`
+
+	// Error is the wait error of the process when it did not exit normally
+	Error string `json:"error,omitempty"`
+
+	// Duration is the time from process start to exit
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Request describes a claude CLI invocation.
+type Request struct {
+	// Args are the command-line arguments passed to the CLI
+	Args []string `json:"args"`
+
+	// Dir is the working directory of the process
+	Dir string `json:"dir,omitempty"`
+
+	// Env lists the names of environment variables set by the client.
+	// Values are never recorded since they commonly hold credentials.
+	Env []string `json:"env,omitempty"`
+
+	// Options are the full request options that produced the invocation
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// Frame is a chunk of output read from the CLI.
+type Frame struct {
+	// Stream is the output stream the frame was read from (stdout or stderr)
+	Stream string `json:"stream"`
+
+	// Offset is the time since the process started
+	Offset time.Duration `json:"offset_ns"`
+
+	// Data is the raw frame content
+	Data string `json:"data"`
+}
+
+// Output returns the concatenated data of all frames on a stream.
+func (i *Interaction) Output(stream string) string {
+	var data []byte
+	for _, frame := range i.Frames {
+		if frame.Stream == stream {
+			data = append(data, frame.Data...)
+		}
+	}
+	return string(data)
+}
+
+// NewCassette creates an empty cassette.
+func NewCassette() *Cassette {
+	return &Cassette{
+		Version:    CassetteVersion,
+		RecordedAt: time.Now(),
+	}
+}
+
+// Load reads a cassette file.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - cassette path is provided by the caller
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if cassette.Version > CassetteVersion {
+		return nil, fmt.Errorf("cassette %s has unsupported version %d", path, cassette.Version)
+	}
+
+	return &cassette, nil
+}
+
+// Save writes the cassette to path, creating parent directories as needed.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
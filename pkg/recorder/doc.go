@@ -0,0 +1,38 @@
+/*
+Package recorder captures claude CLI interactions to portable cassette files
+and replays them for deterministic tests of complex agent flows, in the
+spirit of go-vcr for the CLI protocol.
+
+A cassette stores, for every CLI invocation, the command-line arguments, the
+full request options, the names (never the values) of environment variables
+set by the client, the raw output frames with their timing, and the exit
+status.
+
+# Recording
+
+	rec, err := recorder.New("testdata/refactor.json", recorder.ModeRecord)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client.SetRecorder(rec)
+
+	// ... run queries, sessions, and streams against the real CLI ...
+
+	if err := rec.Save(); err != nil {
+		log.Fatal(err)
+	}
+
+# Playback
+
+In replay mode the CLI is never started. Each invocation is served by the
+first unused recorded interaction whose arguments match; use WithMatcher to
+relax matching (for example MatchAny replays strictly in recorded order) and
+WithRealtime to reproduce the recorded frame timing.
+
+	rec, err := recorder.New("testdata/refactor.json", recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetRecorder(rec)
+*/
+package recorder
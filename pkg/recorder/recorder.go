@@ -0,0 +1,380 @@
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Mode selects whether a Recorder captures or replays interactions.
+type Mode int
+
+const (
+	// ModeRecord runs the real CLI and records every interaction
+	ModeRecord Mode = iota
+
+	// ModeReplay serves interactions from the cassette without running the CLI
+	ModeReplay
+)
+
+// String returns the mode name.
+func (m Mode) String() string {
+	switch m {
+	case ModeRecord:
+		return "record"
+	case ModeReplay:
+		return "replay"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// ErrInteractionNotFound is returned in replay mode when no unused recorded
+// interaction matches a request.
+var ErrInteractionNotFound = errors.New("recorder: no matching interaction in cassette")
+
+// errKilled is returned by Playback.Wait after Kill.
+var errKilled = errors.New("signal: killed")
+
+// Matcher reports whether a recorded request matches a live one.
+type Matcher func(recorded, actual Request) bool
+
+// MatchArgs matches requests with identical CLI arguments. It is the default matcher.
+func MatchArgs(recorded, actual Request) bool {
+	return reflect.DeepEqual(recorded.Args, actual.Args)
+}
+
+// MatchAny matches any request, replaying interactions strictly in recorded order.
+func MatchAny(recorded, actual Request) bool {
+	return true
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithMatcher sets the matcher used to select interactions in replay mode.
+func WithMatcher(matcher Matcher) Option {
+	return func(r *Recorder) {
+		r.matcher = matcher
+	}
+}
+
+// WithRealtime replays frames with their recorded timing instead of as fast
+// as they are read.
+func WithRealtime(realtime bool) Option {
+	return func(r *Recorder) {
+		r.realtime = realtime
+	}
+}
+
+// WithRedactor scrubs secrets from recorded arguments and frames, so cassettes
+// can be committed and shared. Replay serves the redacted content.
+func WithRedactor(redactor interface{ Redact(text string) string }) Option {
+	return func(r *Recorder) {
+		r.redactor = redactor
+	}
+}
+
+// Recorder records claude CLI interactions to a cassette file or replays them.
+// It is safe for concurrent use.
+type Recorder struct {
+	mode     Mode
+	path     string
+	cassette *Cassette
+	matcher  Matcher
+	realtime bool
+	redactor interface{ Redact(text string) string }
+	used     map[int]bool
+	mu       sync.Mutex
+}
+
+// New creates a recorder for the cassette at path. In ModeReplay the cassette
+// is loaded immediately; in ModeRecord a new cassette is started and written
+// by Save.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		mode:    mode,
+		path:    path,
+		matcher: MatchArgs,
+		used:    make(map[int]bool),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeRecord:
+		r.cassette = NewCassette()
+	case ModeReplay:
+		cassette, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+	default:
+		return nil, fmt.Errorf("recorder: unknown mode %v", mode)
+	}
+
+	return r, nil
+}
+
+// Mode returns the recorder mode.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Cassette returns the cassette being recorded or replayed.
+func (r *Recorder) Cassette() *Cassette {
+	return r.cassette
+}
+
+// Save writes the recorded cassette to the recorder's path. It is a no-op in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.Save(r.path)
+}
+
+// Begin starts recording a new interaction.
+func (r *Recorder) Begin(request Request) *Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.redactor != nil {
+		args := make([]string, len(request.Args))
+		for i, arg := range request.Args {
+			args[i] = r.redactor.Redact(arg)
+		}
+		request.Args = args
+		if len(request.Options) > 0 {
+			request.Options = json.RawMessage(r.redactor.Redact(string(request.Options)))
+		}
+	}
+
+	interaction := &Interaction{
+		ID:      len(r.cassette.Interactions),
+		Request: request,
+		Frames:  make([]Frame, 0),
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+
+	return &Recording{
+		recorder:    r,
+		interaction: interaction,
+		start:       time.Now(),
+	}
+}
+
+// Replay finds the first unused interaction matching request and returns a
+// playback of it.
+func (r *Recorder) Replay(request Request) (*Playback, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, interaction := range r.cassette.Interactions {
+		if r.used[interaction.ID] || !r.matcher(interaction.Request, request) {
+			continue
+		}
+		r.used[interaction.ID] = true
+		return newPlayback(interaction, r.realtime), nil
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrInteractionNotFound, request.Args)
+}
+
+// Recording captures the frames and exit status of one interaction.
+type Recording struct {
+	recorder    *Recorder
+	interaction *Interaction
+	start       time.Time
+}
+
+// Wrap returns a reader that records every chunk read from rc as a frame on the given stream.
+func (rec *Recording) Wrap(stream string, rc io.ReadCloser) io.ReadCloser {
+	return &recordingReader{recording: rec, stream: stream, rc: rc}
+}
+
+// Finish records the process exit status from its wait error.
+func (rec *Recording) Finish(waitErr error) {
+	rec.recorder.mu.Lock()
+	defer rec.recorder.mu.Unlock()
+
+	rec.interaction.Duration = time.Since(rec.start)
+	if waitErr == nil {
+		return
+	}
+
+	var exitErr interface{ ExitCode() int }
+	if errors.As(waitErr, &exitErr) && exitErr.ExitCode() > 0 {
+		rec.interaction.ExitCode = exitErr.ExitCode()
+	} else {
+		rec.interaction.Error = waitErr.Error()
+	}
+}
+
+// addFrame appends a frame to the interaction.
+func (rec *Recording) addFrame(stream string, data []byte) {
+	rec.recorder.mu.Lock()
+	defer rec.recorder.mu.Unlock()
+
+	text := string(data)
+	if rec.recorder.redactor != nil {
+		text = rec.recorder.redactor.Redact(text)
+	}
+	rec.interaction.Frames = append(rec.interaction.Frames, Frame{
+		Stream: stream,
+		Offset: time.Since(rec.start),
+		Data:   text,
+	})
+}
+
+// recordingReader tees reads into recorded frames.
+type recordingReader struct {
+	recording *Recording
+	stream    string
+	rc        io.ReadCloser
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	if n > 0 {
+		r.recording.addFrame(r.stream, p[:n])
+	}
+	return n, err
+}
+
+func (r *recordingReader) Close() error {
+	return r.rc.Close()
+}
+
+// ExitError is returned by Playback.Wait when the recorded process exited
+// with a non-zero code.
+type ExitError struct {
+	Code int
+}
+
+// Error implements the error interface.
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode returns the recorded exit code.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// Playback replays one recorded interaction as if it were a running process.
+type Playback struct {
+	interaction *Interaction
+	realtime    bool
+	start       time.Time
+	killed      chan struct{}
+	killOnce    sync.Once
+	wg          sync.WaitGroup
+	readers     []*io.PipeReader
+	mu          sync.Mutex
+}
+
+func newPlayback(interaction *Interaction, realtime bool) *Playback {
+	return &Playback{
+		interaction: interaction,
+		realtime:    realtime,
+		start:       time.Now(),
+		killed:      make(chan struct{}),
+	}
+}
+
+// Interaction returns the interaction being replayed.
+func (p *Playback) Interaction() *Interaction {
+	return p.interaction
+}
+
+// Stdout returns a reader over the recorded stdout frames.
+func (p *Playback) Stdout() io.ReadCloser {
+	return p.stream(StreamStdout)
+}
+
+// Stderr returns a reader over the recorded stderr frames. Only streams that
+// are requested are replayed, so Wait does not block on unread output.
+func (p *Playback) Stderr() io.ReadCloser {
+	return p.stream(StreamStderr)
+}
+
+// stream starts replaying the frames of one stream into a pipe.
+func (p *Playback) stream(name string) io.ReadCloser {
+	reader, writer := io.Pipe()
+
+	p.mu.Lock()
+	p.readers = append(p.readers, reader)
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		for _, frame := range p.interaction.Frames {
+			if frame.Stream != name {
+				continue
+			}
+			if p.realtime {
+				if delay := time.Until(p.start.Add(frame.Offset)); delay > 0 {
+					select {
+					case <-time.After(delay):
+					case <-p.killed:
+						return
+					}
+				}
+			}
+			// Writes fail once the reader is closed or the playback is killed
+			if _, err := writer.Write([]byte(frame.Data)); err != nil {
+				return
+			}
+		}
+		_ = writer.Close() // Ignore error, signals EOF to the reader
+	}()
+
+	return reader
+}
+
+// Wait blocks until all requested streams have been replayed and returns the
+// recorded exit status.
+func (p *Playback) Wait() error {
+	p.wg.Wait()
+
+	select {
+	case <-p.killed:
+		return errKilled
+	default:
+	}
+
+	if p.interaction.ExitCode != 0 {
+		return &ExitError{Code: p.interaction.ExitCode}
+	}
+	if p.interaction.Error != "" {
+		return errors.New(p.interaction.Error)
+	}
+	return nil
+}
+
+// Kill stops the playback, unblocking readers and Wait.
+func (p *Playback) Kill() error {
+	p.killOnce.Do(func() {
+		close(p.killed)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for _, reader := range p.readers {
+			_ = reader.CloseWithError(io.ErrClosedPipe) // Ignore error, unblocks pending writes
+		}
+	})
+	return nil
+}
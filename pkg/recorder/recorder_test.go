@@ -0,0 +1,181 @@
+package recorder
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder_RecordAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "flow.json")
+
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	recording := rec.Begin(Request{Args: []string{"--print", "hello"}, Env: []string{"ANTHROPIC_API_KEY"}})
+	stdout := recording.Wrap(StreamStdout, io.NopCloser(strings.NewReader("line 1\nline 2\n")))
+	if _, err := io.ReadAll(stdout); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	recording.Finish(&ExitError{Code: 2})
+
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	cassette, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cassette.Version != CassetteVersion || len(cassette.Interactions) != 1 {
+		t.Fatalf("Unexpected cassette: %+v", cassette)
+	}
+
+	interaction := cassette.Interactions[0]
+	if interaction.Output(StreamStdout) != "line 1\nline 2\n" {
+		t.Errorf("Unexpected stdout: %q", interaction.Output(StreamStdout))
+	}
+	if interaction.ExitCode != 2 || interaction.Error != "" {
+		t.Errorf("Unexpected exit status: %d %q", interaction.ExitCode, interaction.Error)
+	}
+	if len(interaction.Request.Env) != 1 || interaction.Request.Env[0] != "ANTHROPIC_API_KEY" {
+		t.Errorf("Unexpected env: %v", interaction.Request.Env)
+	}
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flow.json")
+	cassette := NewCassette()
+	cassette.Interactions = []*Interaction{
+		{ID: 0, Request: Request{Args: []string{"a"}}, Frames: []Frame{{Stream: StreamStdout, Data: "first"}}},
+		{ID: 1, Request: Request{Args: []string{"b"}}, Frames: []Frame{
+			{Stream: StreamStdout, Data: "second "},
+			{Stream: StreamStderr, Data: "warning"},
+			{Stream: StreamStdout, Data: "output"},
+		}, ExitCode: 1},
+	}
+	if err := cassette.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	rec, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// Interactions are matched by arguments, not position
+	playback, err := rec.Replay(Request{Args: []string{"b"}})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	stdout, stderr := playback.Stdout(), playback.Stderr()
+	out, _ := io.ReadAll(stdout)
+	errOut, _ := io.ReadAll(stderr)
+	if string(out) != "second output" || string(errOut) != "warning" {
+		t.Errorf("Unexpected output: %q %q", out, errOut)
+	}
+
+	var exitErr *ExitError
+	if err := playback.Wait(); !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Errorf("Expected exit code 1, got %v", err)
+	}
+
+	if _, err := rec.Replay(Request{Args: []string{"b"}}); !errors.Is(err, ErrInteractionNotFound) {
+		t.Errorf("Expected ErrInteractionNotFound, got %v", err)
+	}
+	if _, err := rec.Replay(Request{Args: []string{"a"}}); err != nil {
+		t.Errorf("Expected interaction a to replay, got %v", err)
+	}
+}
+
+func TestRecorder_ReplayKill(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slow.json")
+	cassette := NewCassette()
+	cassette.Interactions = []*Interaction{{
+		Frames: []Frame{
+			{Stream: StreamStdout, Data: "now"},
+			{Stream: StreamStdout, Offset: time.Hour, Data: "later"},
+		},
+	}}
+	if err := cassette.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	rec, err := New(path, ModeReplay, WithMatcher(MatchAny), WithRealtime(true))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	playback, err := rec.Replay(Request{Args: []string{"anything"}})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	stdout := playback.Stdout()
+	buf := make([]byte, 16)
+	if n, err := stdout.Read(buf); err != nil || string(buf[:n]) != "now" {
+		t.Fatalf("Expected first frame, got %q %v", buf[:n], err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- playback.Wait() }()
+
+	_ = playback.Kill()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Expected Wait to report the kill")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not return after Kill")
+	}
+}
+
+func TestNew_ReplayMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("Expected error for missing cassette")
+	}
+}
+
+// fixedRedactor redacts a fixed secret.
+type fixedRedactor struct{ secret string }
+
+func (r fixedRedactor) Redact(text string) string {
+	return strings.ReplaceAll(text, r.secret, "[REDACTED]")
+}
+
+func TestRecorder_WithRedactor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flow.json")
+
+	rec, err := New(path, ModeRecord, WithRedactor(fixedRedactor{secret: "sk-secret"}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	recording := rec.Begin(Request{Args: []string{"--print", "use sk-secret"}, Options: []byte(`{"system":"sk-secret"}`)})
+	stdout := recording.Wrap(StreamStdout, io.NopCloser(strings.NewReader("echo sk-secret\n")))
+	if _, err := io.ReadAll(stdout); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	recording.Finish(nil)
+
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	cassette, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	interaction := cassette.Interactions[0]
+	if interaction.Request.Args[1] != "use [REDACTED]" || strings.Contains(string(interaction.Request.Options), "sk-secret") {
+		t.Errorf("Unexpected request: %+v", interaction.Request)
+	}
+	if interaction.Output(StreamStdout) != "echo [REDACTED]\n" {
+		t.Errorf("Unexpected stdout: %q", interaction.Output(StreamStdout))
+	}
+}
@@ -0,0 +1,172 @@
+package redact
+
+import (
+	"math"
+	"regexp"
+)
+
+// Match is a secret found in a text.
+type Match struct {
+	// Start and End are the byte offsets of the secret
+	Start int
+	End   int
+
+	// Detector is the name of the detector that found it
+	Detector string
+}
+
+// Detector finds secrets in text. Implementations must be safe for
+// concurrent use.
+type Detector interface {
+	// Name identifies the detector in redaction markers
+	Name() string
+
+	// Find returns the secrets in text
+	Find(text string) []Match
+}
+
+// RegexDetector finds secrets with a regular expression. When the expression
+// has capture groups, only the first group is treated as the secret, so
+// surrounding context such as "password=" is kept.
+type RegexDetector struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// NewRegexDetector compiles a regular expression detector.
+func NewRegexDetector(name, pattern string) (*RegexDetector, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &RegexDetector{name: name, pattern: compiled}, nil
+}
+
+// mustRegexDetector compiles a built-in detector.
+func mustRegexDetector(name, pattern string) *RegexDetector {
+	detector, err := NewRegexDetector(name, pattern)
+	if err != nil {
+		panic(err)
+	}
+	return detector
+}
+
+// Name returns the detector name.
+func (d *RegexDetector) Name() string {
+	return d.name
+}
+
+// Find returns the matches of the expression.
+func (d *RegexDetector) Find(text string) []Match {
+	var matches []Match
+	for _, loc := range d.pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if len(loc) >= 4 && loc[2] >= 0 {
+			start, end = loc[2], loc[3]
+		}
+		if start < end {
+			matches = append(matches, Match{Start: start, End: end, Detector: d.name})
+		}
+	}
+	return matches
+}
+
+// Defaults of EntropyDetector.
+const (
+	DefaultEntropyMinLength = 24
+	DefaultEntropyThreshold = 4.2
+)
+
+// entropyTokenPattern matches candidate tokens for the entropy detector.
+var entropyTokenPattern = regexp.MustCompile(`[A-Za-z0-9+/_\-]+=*`)
+
+// EntropyDetector finds long random-looking tokens, catching credentials
+// without a known format. Tokens must mix letters and digits and exceed the
+// Shannon entropy threshold (bits per character); hex digests such as git
+// hashes stay below the default threshold.
+type EntropyDetector struct {
+	// MinLength is the shortest token considered (default DefaultEntropyMinLength)
+	MinLength int
+
+	// Threshold is the minimum entropy in bits per character (default DefaultEntropyThreshold)
+	Threshold float64
+}
+
+// Name returns "high_entropy".
+func (d *EntropyDetector) Name() string {
+	return "high_entropy"
+}
+
+// Find returns the tokens above the entropy threshold.
+func (d *EntropyDetector) Find(text string) []Match {
+	minLength := d.MinLength
+	if minLength <= 0 {
+		minLength = DefaultEntropyMinLength
+	}
+	threshold := d.Threshold
+	if threshold <= 0 {
+		threshold = DefaultEntropyThreshold
+	}
+
+	var matches []Match
+	for _, loc := range entropyTokenPattern.FindAllStringIndex(text, -1) {
+		token := text[loc[0]:loc[1]]
+		if len(token) < minLength || !mixesLettersAndDigits(token) {
+			continue
+		}
+		if shannonEntropy(token) >= threshold {
+			matches = append(matches, Match{Start: loc[0], End: loc[1], Detector: d.Name()})
+		}
+	}
+	return matches
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+
+	entropy := 0.0
+	length := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// mixesLettersAndDigits reports whether s contains both letters and digits.
+func mixesLettersAndDigits(s string) bool {
+	var letter, digit bool
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digit = true
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			letter = true
+		}
+	}
+	return letter && digit
+}
+
+// DefaultDetectors returns detectors for common credential formats (Anthropic,
+// OpenAI, AWS, GitHub, Slack, Google and Stripe keys, private keys, JWTs,
+// bearer tokens and password assignments) plus an EntropyDetector.
+func DefaultDetectors() []Detector {
+	return []Detector{
+		mustRegexDetector("private_key", `-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+		mustRegexDetector("anthropic_api_key", `\bsk-ant-[A-Za-z0-9_\-]{20,}`),
+		mustRegexDetector("openai_api_key", `\bsk-(?:proj-)?[A-Za-z0-9_\-]{20,}`),
+		mustRegexDetector("aws_access_key_id", `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		mustRegexDetector("github_token", `\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`),
+		mustRegexDetector("slack_token", `\bxox[abposr]-[A-Za-z0-9\-]{10,}`),
+		mustRegexDetector("google_api_key", `\bAIza[0-9A-Za-z_\-]{35}`),
+		mustRegexDetector("stripe_key", `\b[rs]k_(?:live|test)_[0-9A-Za-z]{16,}`),
+		mustRegexDetector("jwt", `\beyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`),
+		mustRegexDetector("bearer_token", `(?i)\bbearer\s+([A-Za-z0-9._~+/\-]{16,}=*)`),
+		mustRegexDetector("password", `(?i)\b(?:password|passwd|pwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)["']?\s*[:=]\s*["']?([^\s"',;]{8,})`),
+		&EntropyDetector{},
+	}
+}
@@ -0,0 +1,93 @@
+package redact
+
+import (
+	"sort"
+	"strings"
+)
+
+// Redactor replaces the secrets found by its detectors with markers such as
+// "[REDACTED:github_token]". It is safe for concurrent use.
+type Redactor struct {
+	detectors []Detector
+}
+
+// New creates a redactor with the given detectors, or DefaultDetectors when
+// none are given.
+func New(detectors ...Detector) *Redactor {
+	if len(detectors) == 0 {
+		detectors = DefaultDetectors()
+	}
+	return &Redactor{detectors: detectors}
+}
+
+// Find returns the secrets in text, sorted by position. Overlapping matches
+// are merged and keep the name of the detector listed first.
+func (r *Redactor) Find(text string) []Match {
+	if r == nil || text == "" {
+		return nil
+	}
+
+	var all []Match
+	for _, detector := range r.detectors {
+		all = append(all, detector.Find(text)...)
+	}
+	if len(all) == 0 {
+		return nil
+	}
+
+	// Earlier detectors win ties, so sort stably by start only
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Start < all[j].Start })
+
+	merged := []Match{all[0]}
+	for _, match := range all[1:] {
+		last := &merged[len(merged)-1]
+		if match.Start < last.End {
+			if match.End > last.End {
+				last.End = match.End
+			}
+			continue
+		}
+		merged = append(merged, match)
+	}
+	return merged
+}
+
+// Redact returns text with every secret replaced by a redaction marker.
+func (r *Redactor) Redact(text string) string {
+	matches := r.Find(text)
+	if len(matches) == 0 {
+		return text
+	}
+
+	var out strings.Builder
+	previous := 0
+	for _, match := range matches {
+		out.WriteString(text[previous:match.Start])
+		out.WriteString("[REDACTED:" + match.Detector + "]")
+		previous = match.End
+	}
+	out.WriteString(text[previous:])
+	return out.String()
+}
+
+// RedactValue redacts the strings in a decoded JSON value, such as tool
+// inputs, returning a copy. Other values are returned unchanged.
+func (r *Redactor) RedactValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return r.Redact(v)
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			redacted[key] = r.RedactValue(item)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = r.RedactValue(item)
+		}
+		return redacted
+	}
+	return value
+}
@@ -0,0 +1,93 @@
+/*
+Package replkit builds interactive terminal chat tools on top of the Claude Code Go SDK.
+
+A REPL reads lines from stdin, streams each one to Claude Code, and renders
+the response as it arrives. Ctrl+C interrupts the response in progress and
+returns to the prompt; Ctrl+D or /exit ends the loop. Lines starting with "/"
+run locally registered commands and are otherwise passed through to Claude
+Code, so built-in slash commands such as /compact keep working.
+
+# Basic Usage
+
+	claudeClient, err := client.NewClaudeCodeClient(ctx, types.NewClaudeCodeConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer claudeClient.Close()
+
+	session, err := claudeClient.CreateSession(ctx, "")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	repl := replkit.New(session,
+		replkit.WithPrompt("claude> "),
+		replkit.WithCommand(replkit.Command{
+			Name:        "cost",
+			Description: "Show usage so far",
+			Run: func(ctx context.Context, args string, out io.Writer) error {
+				_, err := fmt.Fprintf(out, "$%.4f\n", claudeClient.UsageTracker().TotalCost())
+				return err
+			},
+		}),
+	)
+	if err := repl.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+# Line Editing
+
+The default line reader relies on the terminal's own line editing and joins
+lines ending in a backslash into multi-line input. Supply WithLineReader with
+an implementation backed by a readline library for history and key bindings.
+
+# Terminal UI
+
+Model is a full-screen chat component following the bubbletea architecture.
+Its View renders the transcript, the reply as it streams, each tool call
+with its state, and the cost reported so far. The package does not import
+bubbletea; a few lines adapt Model to tea.Model, leaving text input to a
+bubble such as textinput:
+
+	type chat struct {
+		model *replkit.Model
+		input textinput.Model
+	}
+
+	func (c chat) Init() tea.Cmd { return textinput.Blink }
+
+	func (c chat) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.Type {
+			case tea.KeyEnter:
+				msg := replkit.SubmitMsg{Prompt: c.input.Value()}
+				c.input.Reset()
+				return c, teaCmd(c.model.Update(msg))
+			case tea.KeyEsc:
+				return c, teaCmd(c.model.Update(replkit.InterruptMsg{}))
+			case tea.KeyCtrlC:
+				return c, tea.Quit
+			}
+		case tea.WindowSizeMsg:
+			c.model.Update(replkit.ResizeMsg{Width: msg.Width, Height: msg.Height})
+			return c, nil
+		}
+		var cmd tea.Cmd
+		c.input, cmd = c.input.Update(msg)
+		return c, tea.Batch(cmd, teaCmd(c.model.Update(msg)))
+	}
+
+	func (c chat) View() string { return c.model.View() + c.input.View() }
+
+	func teaCmd(cmd replkit.Cmd) tea.Cmd {
+		if cmd == nil {
+			return nil
+		}
+		return func() tea.Msg { return cmd() }
+	}
+
+	model := replkit.NewModel(ctx, session)
+	_, err := tea.NewProgram(chat{model: model, input: textinput.New()}, tea.WithAltScreen()).Run()
+*/
+package replkit
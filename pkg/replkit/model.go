@@ -0,0 +1,405 @@
+package replkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Msg is an event delivered to Model.Update. It mirrors bubbletea's tea.Msg,
+// so messages pass between the two unchanged.
+type Msg any
+
+// Cmd performs I/O and returns the next Msg for Model.Update. It mirrors
+// bubbletea's tea.Cmd; convert one with func() tea.Msg { return cmd() }.
+type Cmd func() Msg
+
+// SubmitMsg sends Prompt to Claude Code. It is ignored while a response is
+// streaming.
+type SubmitMsg struct {
+	Prompt string
+}
+
+// InterruptMsg stops the response in progress, keeping what has arrived.
+type InterruptMsg struct{}
+
+// ResizeMsg sets the terminal width the view wraps text at.
+type ResizeMsg struct {
+	Width  int
+	Height int
+}
+
+// streamOpenedMsg delivers the stream started for the prompt of a turn.
+type streamOpenedMsg struct {
+	turn   int
+	stream types.QueryStream
+	err    error
+}
+
+// chunkMsg delivers the next chunk of a stream.
+type chunkMsg struct {
+	stream types.QueryStream
+	chunk  *types.StreamChunk
+	err    error
+}
+
+// ToolActivity is a tool call made during the conversation.
+type ToolActivity struct {
+	// ID and Name identify the call
+	ID   string
+	Name string
+
+	// Summary is a short description of the input, such as the file read
+	Summary string
+
+	// Done is set once the tool returned, and IsError if it failed
+	Done    bool
+	IsError bool
+}
+
+// Entry is one item of the transcript a Model renders.
+type Entry struct {
+	// Role is who the entry is from: the user or the assistant, or the
+	// system for errors and notices
+	Role types.Role
+
+	// Text is the entry's text
+	Text string
+
+	// Tools are the tools the assistant used in this reply
+	Tools []ToolActivity
+}
+
+// Model is a terminal UI component for a conversation, following the
+// bubbletea architecture: Update applies a Msg and returns the Cmd to run
+// next, and View renders the transcript, the streaming reply, tool activity
+// and the cost so far. It does not depend on bubbletea, and can be driven
+// by any event loop that runs the returned commands.
+//
+// A Model is not safe for concurrent use; bubbletea calls Update and View
+// from a single goroutine.
+type Model struct {
+	ctx      context.Context
+	conv     Conversation
+	template types.QueryRequest
+
+	transcript []Entry
+	reply      *Entry
+	turn       int
+	partial    bool
+	stream     types.QueryStream
+	cancel     context.CancelFunc
+	started    time.Time
+
+	cost  float64
+	last  *types.ResultMessage
+	width int
+}
+
+// NewModel creates a Model that sends prompts to conv. Queries run with ctx,
+// which should live as long as the UI.
+func NewModel(ctx context.Context, conv Conversation) *Model {
+	return &Model{ctx: ctx, conv: conv}
+}
+
+// SetRequestTemplate sets the base request for each prompt, as
+// WithRequestTemplate does for a REPL.
+func (m *Model) SetRequestTemplate(template types.QueryRequest) {
+	m.template = template
+}
+
+// Update applies msg and returns the command to run next, or nil. Messages
+// of other types are ignored, so a bubbletea model can pass every message
+// through.
+func (m *Model) Update(msg Msg) Cmd {
+	switch msg := msg.(type) {
+	case SubmitMsg:
+		prompt := strings.TrimSpace(msg.Prompt)
+		if prompt == "" || m.Busy() {
+			return nil
+		}
+		return m.submit(prompt)
+
+	case InterruptMsg:
+		if m.Busy() {
+			m.finish("interrupted")
+		}
+
+	case ResizeMsg:
+		m.width = msg.Width
+
+	case streamOpenedMsg:
+		if msg.turn != m.turn || m.reply == nil {
+			// The response was interrupted before the stream opened
+			if msg.err == nil {
+				_ = msg.stream.Close() // Ignore error, the stream is unused
+			}
+			return nil
+		}
+		if msg.err != nil {
+			m.finish(msg.err.Error())
+			return nil
+		}
+		m.stream = msg.stream
+		return recv(msg.stream)
+
+	case chunkMsg:
+		if msg.stream != m.stream {
+			return nil
+		}
+		switch {
+		case msg.err == io.EOF || (msg.err == nil && msg.chunk.Done):
+			m.finish("")
+		case msg.err != nil:
+			m.finish(msg.err.Error())
+		default:
+			m.apply(msg.chunk)
+			return recv(msg.stream)
+		}
+	}
+	return nil
+}
+
+// submit records the prompt and returns the command starting its stream.
+func (m *Model) submit(prompt string) Cmd {
+	m.transcript = append(m.transcript, Entry{Role: types.RoleUser, Text: prompt})
+	m.reply = &Entry{Role: types.RoleAssistant}
+	m.turn++
+	m.partial = false
+	m.started = time.Now()
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancel = cancel
+
+	request := m.template
+	request.Messages = append(append([]types.Message(nil), m.template.Messages...), types.Message{
+		Role:    types.RoleUser,
+		Content: prompt,
+	})
+	conv, turn := m.conv, m.turn
+	return func() Msg {
+		stream, err := conv.QueryStream(ctx, &request)
+		return streamOpenedMsg{turn: turn, stream: stream, err: err}
+	}
+}
+
+// recv returns the command reading the next chunk of stream.
+func recv(stream types.QueryStream) Cmd {
+	return func() Msg {
+		chunk, err := stream.Recv()
+		return chunkMsg{stream: stream, chunk: chunk, err: err}
+	}
+}
+
+// finish ends the response in progress, adding it to the transcript along
+// with notice, if any.
+func (m *Model) finish(notice string) {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	if m.stream != nil {
+		_ = m.stream.Close() // Ignore error, the response is over
+		m.stream = nil
+	}
+	if m.reply != nil {
+		m.reply.Text = strings.TrimRight(m.reply.Text, "\n")
+		if m.reply.Text != "" || len(m.reply.Tools) > 0 {
+			m.transcript = append(m.transcript, *m.reply)
+		}
+		m.reply = nil
+	}
+	if notice != "" {
+		m.transcript = append(m.transcript, Entry{Role: types.RoleSystem, Text: notice})
+	}
+}
+
+// modelLine is the subset of a stream-json line the model understands.
+type modelLine struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Content []types.ContentBlock `json:"content"`
+	} `json:"message"`
+	Event *struct {
+		Type  string `json:"type"`
+		Delta *struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"delta"`
+	} `json:"event"`
+}
+
+// apply adds a chunk of the response to the reply in progress. Plain text
+// is appended as it arrives; stream-json lines contribute their text, tool
+// calls and result. Text deltas from partial messages replace the text of
+// the complete messages that follow them.
+func (m *Model) apply(chunk *types.StreamChunk) {
+	if chunk.CompactBoundary != nil {
+		m.transcript = append(m.transcript, Entry{Role: types.RoleSystem, Text: "conversation compacted"})
+		return
+	}
+
+	trimmed := strings.TrimSpace(chunk.Content)
+	var line modelLine
+	if !strings.HasPrefix(trimmed, "{") || json.Unmarshal([]byte(trimmed), &line) != nil || line.Type == "" {
+		m.reply.Text += chunk.Content
+		return
+	}
+
+	switch line.Type {
+	case "stream_event":
+		if event := line.Event; event != nil && event.Type == "content_block_delta" && event.Delta != nil && event.Delta.Type == "text_delta" {
+			m.reply.Text += event.Delta.Text
+			m.partial = true
+		}
+
+	case "assistant", "user":
+		if line.Message == nil {
+			return
+		}
+		for _, block := range line.Message.Content {
+			switch block.Type {
+			case "text":
+				if line.Type == "assistant" && !m.partial {
+					if m.reply.Text != "" && !strings.HasSuffix(m.reply.Text, "\n") {
+						m.reply.Text += "\n"
+					}
+					m.reply.Text += block.Text
+				}
+			case "tool_use":
+				use := types.ToolUseFromBlock(block)
+				m.reply.Tools = append(m.reply.Tools, ToolActivity{ID: use.ID, Name: use.Name, Summary: toolSummary(use.Input)})
+			case "tool_result":
+				for i := range m.reply.Tools {
+					if m.reply.Tools[i].ID == block.ToolUseID {
+						m.reply.Tools[i].Done = true
+						m.reply.Tools[i].IsError = block.IsError
+					}
+				}
+			}
+		}
+
+	case "result":
+		if result, err := types.ParseResultMessage([]byte(trimmed)); err == nil {
+			m.last = result
+			m.cost += result.Cost()
+		}
+	}
+}
+
+// toolSummary returns the most descriptive input of a tool call, such as
+// the file path of a Read or the command of a Bash call.
+func toolSummary(input map[string]any) string {
+	for _, key := range []string{"file_path", "path", "command", "pattern", "url", "query", "description"} {
+		if value, ok := input[key].(string); ok && value != "" {
+			if len(value) > 60 {
+				value = value[:57] + "..."
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+// Busy reports whether a response is streaming.
+func (m *Model) Busy() bool {
+	return m.reply != nil
+}
+
+// Cost returns the cost of the conversation's responses in USD, as reported
+// by the CLI.
+func (m *Model) Cost() float64 {
+	return m.cost
+}
+
+// Transcript returns the completed entries of the conversation.
+func (m *Model) Transcript() []Entry {
+	return append([]Entry(nil), m.transcript...)
+}
+
+// View renders the transcript, the reply in progress and a status line.
+func (m *Model) View() string {
+	var b strings.Builder
+	for _, entry := range m.transcript {
+		m.renderEntry(&b, entry, false)
+	}
+	if m.reply != nil {
+		m.renderEntry(&b, *m.reply, true)
+	}
+
+	status := fmt.Sprintf("cost $%.4f", m.cost)
+	if m.Busy() {
+		status = fmt.Sprintf("streaming %s | %s", time.Since(m.started).Round(100*time.Millisecond), status)
+	} else if m.last != nil {
+		status = fmt.Sprintf("%d turns in %s | %s", m.last.NumTurns, m.last.Duration().Round(100*time.Millisecond), status)
+	}
+	b.WriteString("-- " + status + " --\n")
+	return b.String()
+}
+
+// renderEntry writes an entry, marking the end of a reply still streaming.
+func (m *Model) renderEntry(b *strings.Builder, entry Entry, streaming bool) {
+	switch entry.Role {
+	case types.RoleUser:
+		b.WriteString(wrap("> "+entry.Text, m.width) + "\n\n")
+		return
+	case types.RoleSystem:
+		b.WriteString(wrap("[ "+entry.Text+" ]", m.width) + "\n\n")
+		return
+	}
+
+	for _, tool := range entry.Tools {
+		state := "running"
+		if tool.Done {
+			state = "done"
+			if tool.IsError {
+				state = "failed"
+			}
+		}
+		line := "  * " + tool.Name
+		if tool.Summary != "" {
+			line += " " + tool.Summary
+		}
+		b.WriteString(wrap(line+" ("+state+")", m.width) + "\n")
+	}
+	text := entry.Text
+	if streaming {
+		text += "_"
+	}
+	if text != "" {
+		b.WriteString(wrap(text, m.width) + "\n")
+	}
+	b.WriteString("\n")
+}
+
+// wrap breaks the lines of text at spaces so they fit width columns. Words
+// longer than width are left whole. A width of 0 disables wrapping.
+func wrap(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		var wrapped strings.Builder
+		column := 0
+		for _, word := range strings.Split(line, " ") {
+			n := len([]rune(word))
+			if column > 0 && column+1+n > width {
+				wrapped.WriteString("\n")
+				column = 0
+			} else if column > 0 {
+				wrapped.WriteString(" ")
+				column++
+			}
+			wrapped.WriteString(word)
+			column += n
+		}
+		lines[i] = wrapped.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,119 @@
+package replkit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// drive runs cmd and the commands that follow it, as an event loop would.
+func drive(m *Model, cmd Cmd) {
+	for cmd != nil {
+		cmd = m.Update(cmd())
+	}
+}
+
+func TestModel_Stream(t *testing.T) {
+	conv := &fakeConversation{respond: func(prompt string) *fakeStream {
+		return &fakeStream{chunks: []*types.StreamChunk{
+			{Content: `{"type":"assistant","message":{"content":[{"type":"text","text":"Let me look."},{"type":"tool_use","id":"toolu_1","name":"Read","input":{"file_path":"main.go"}}]}}` + "\n"},
+			{Content: `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"package main"}]}}` + "\n"},
+			{Content: `{"type":"assistant","message":{"content":[{"type":"text","text":"It is the main package."}]}}` + "\n"},
+			{Content: `{"type":"result","subtype":"success","is_error":false,"num_turns":2,"duration_ms":1500,"total_cost_usd":0.0125}` + "\n"},
+		}}
+	}}
+
+	m := NewModel(context.Background(), conv)
+	m.Update(ResizeMsg{Width: 80, Height: 24})
+	cmd := m.Update(SubmitMsg{Prompt: " What is main.go? "})
+	if !m.Busy() || !strings.Contains(m.View(), "> What is main.go?") {
+		t.Fatalf("Expected the prompt to start a response:\n%s", m.View())
+	}
+	if m.Update(SubmitMsg{Prompt: "again"}) != nil {
+		t.Error("Expected prompts to be ignored while streaming")
+	}
+
+	// Render the reply part way through
+	cmd = m.Update(cmd())
+	cmd = m.Update(cmd())
+	if view := m.View(); !strings.Contains(view, "* Read main.go (running)") || !strings.Contains(view, "Let me look._") {
+		t.Errorf("Expected the running tool and streaming text:\n%s", view)
+	}
+
+	drive(m, cmd)
+	if m.Busy() || m.Cost() != 0.0125 || len(conv.prompts) != 1 {
+		t.Fatalf("Expected one completed response, got busy=%v cost=%v prompts=%v", m.Busy(), m.Cost(), conv.prompts)
+	}
+	transcript := m.Transcript()
+	if len(transcript) != 2 || transcript[1].Text != "Let me look.\nIt is the main package." || !transcript[1].Tools[0].Done {
+		t.Errorf("Unexpected transcript: %+v", transcript)
+	}
+	if view := m.View(); !strings.Contains(view, "* Read main.go (done)") || !strings.Contains(view, "-- 2 turns in 1.5s | cost $0.0125 --") {
+		t.Errorf("Unexpected view:\n%s", view)
+	}
+}
+
+func TestModel_PartialMessages(t *testing.T) {
+	conv := &fakeConversation{respond: func(prompt string) *fakeStream {
+		return &fakeStream{chunks: []*types.StreamChunk{
+			{Content: `{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"Hel"}}}` + "\n"},
+			{Content: `{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"lo"}}}` + "\n"},
+			{Content: `{"type":"assistant","message":{"content":[{"type":"text","text":"Hello"}]}}` + "\n"},
+		}}
+	}}
+
+	m := NewModel(context.Background(), conv)
+	drive(m, m.Update(SubmitMsg{Prompt: "hi"}))
+	if transcript := m.Transcript(); len(transcript) != 2 || transcript[1].Text != "Hello" {
+		t.Errorf("Expected the deltas once, got %+v", transcript)
+	}
+}
+
+func TestModel_Interrupt(t *testing.T) {
+	conv := &fakeConversation{respond: func(prompt string) *fakeStream {
+		return &fakeStream{chunks: []*types.StreamChunk{{Content: "partial answer\n"}}, block: true}
+	}}
+
+	m := NewModel(context.Background(), conv)
+	cmd := m.Update(m.Update(SubmitMsg{Prompt: "hi"})())
+	cmd = m.Update(cmd())
+
+	m.Update(InterruptMsg{})
+	if m.Busy() {
+		t.Fatal("Expected the interrupt to end the response")
+	}
+	// The pending read ends once the stream is closed and is then ignored
+	if m.Update(cmd()) != nil {
+		t.Error("Expected chunks of an interrupted stream to be ignored")
+	}
+	transcript := m.Transcript()
+	if len(transcript) != 3 || transcript[1].Text != "partial answer" || transcript[2].Text != "interrupted" {
+		t.Errorf("Unexpected transcript: %+v", transcript)
+	}
+}
+
+type failingConversation struct{}
+
+func (failingConversation) QueryStream(ctx context.Context, request *types.QueryRequest) (types.QueryStream, error) {
+	return nil, errors.New("session has expired")
+}
+
+func TestModel_Error(t *testing.T) {
+	m := NewModel(context.Background(), failingConversation{})
+	drive(m, m.Update(SubmitMsg{Prompt: "hi"}))
+	if view := m.View(); m.Busy() || !strings.Contains(view, "[ session has expired ]") {
+		t.Errorf("Expected the error in the view:\n%s", view)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if got := wrap("the quick brown fox\njumps", 10); got != "the quick\nbrown fox\njumps" {
+		t.Errorf("wrap() = %q", got)
+	}
+	if got := wrap("unchanged text", 0); got != "unchanged text" {
+		t.Errorf("wrap() = %q", got)
+	}
+}
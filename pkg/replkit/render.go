@@ -0,0 +1,86 @@
+package replkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Renderer writes streamed chunks to the REPL output.
+type Renderer interface {
+	Render(out io.Writer, chunk *types.StreamChunk) error
+}
+
+// RendererFunc adapts a function to the Renderer interface.
+type RendererFunc func(out io.Writer, chunk *types.StreamChunk) error
+
+// Render calls f(out, chunk).
+func (f RendererFunc) Render(out io.Writer, chunk *types.StreamChunk) error {
+	return f(out, chunk)
+}
+
+// TextRenderer renders the assistant's text. Plain text chunks are written as
+// they arrive; stream-json lines are reduced to their assistant text so
+// protocol frames are not echoed to the terminal.
+type TextRenderer struct {
+	// ShowTools prints a short note for each tool the assistant uses
+	ShowTools bool
+}
+
+// streamLine is the subset of a stream-json line the renderer understands.
+type streamLine struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+			Name string `json:"name"`
+		} `json:"content"`
+	} `json:"message"`
+}
+
+// Render implements Renderer.
+func (t TextRenderer) Render(out io.Writer, chunk *types.StreamChunk) error {
+	if chunk.CompactBoundary != nil {
+		_, err := fmt.Fprintln(out, "[conversation compacted]")
+		return err
+	}
+	if chunk.Content == "" {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(chunk.Content)
+	if !strings.HasPrefix(trimmed, "{") {
+		_, err := io.WriteString(out, chunk.Content)
+		return err
+	}
+
+	var line streamLine
+	if err := json.Unmarshal([]byte(trimmed), &line); err != nil || line.Type == "" {
+		_, err := io.WriteString(out, chunk.Content)
+		return err
+	}
+
+	if line.Type != "assistant" || line.Message == nil {
+		return nil
+	}
+
+	for _, block := range line.Message.Content {
+		switch block.Type {
+		case "text":
+			if _, err := fmt.Fprintln(out, block.Text); err != nil {
+				return err
+			}
+		case "tool_use":
+			if t.ShowTools {
+				if _, err := fmt.Fprintf(out, "[using %s]\n", block.Name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+package replkit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// DefaultPrompt is the input prompt shown when none is configured
+	DefaultPrompt = "> "
+
+	// ContinuationPrompt is shown while reading multi-line input
+	ContinuationPrompt = "... "
+)
+
+// ErrExit can be returned by a command handler to end the REPL.
+var ErrExit = errors.New("replkit: exit")
+
+// Conversation is the query interface the REPL drives. Both
+// *client.ClaudeCodeClient and *client.ClaudeCodeSession implement it; use a
+// session to keep conversation history across turns.
+type Conversation interface {
+	QueryStream(ctx context.Context, request *types.QueryRequest) (types.QueryStream, error)
+}
+
+// LineReader reads one line of user input. Implement it with a readline
+// library to add history and key bindings beyond the terminal's own line editing.
+type LineReader interface {
+	// ReadLine displays prompt and returns the entered line without its newline.
+	// It returns io.EOF when input ends.
+	ReadLine(prompt string) (string, error)
+}
+
+// Command is a slash command handled locally by the REPL instead of being
+// passed through to Claude Code.
+type Command struct {
+	// Name is the command name without the leading slash
+	Name string
+
+	// Description is shown by /help
+	Description string
+
+	// Run executes the command with the text following the command name.
+	// Returning ErrExit ends the REPL.
+	Run func(ctx context.Context, args string, out io.Writer) error
+}
+
+// Option configures a REPL.
+type Option func(*REPL)
+
+// WithInput sets the input read by the default line reader. Defaults to os.Stdin.
+func WithInput(in io.Reader) Option {
+	return func(r *REPL) {
+		r.in = in
+	}
+}
+
+// WithOutput sets where prompts and responses are written. Defaults to os.Stdout.
+func WithOutput(out io.Writer) Option {
+	return func(r *REPL) {
+		r.out = out
+	}
+}
+
+// WithLineReader replaces the default line reader.
+func WithLineReader(reader LineReader) Option {
+	return func(r *REPL) {
+		r.reader = reader
+	}
+}
+
+// WithPrompt sets the input prompt.
+func WithPrompt(prompt string) Option {
+	return func(r *REPL) {
+		r.prompt = prompt
+	}
+}
+
+// WithRenderer sets how streamed chunks are written to the output.
+func WithRenderer(renderer Renderer) Option {
+	return func(r *REPL) {
+		r.renderer = renderer
+	}
+}
+
+// WithRequestTemplate sets the request used for every turn. The user's input
+// is appended to a copy of the template's messages.
+func WithRequestTemplate(template types.QueryRequest) Option {
+	return func(r *REPL) {
+		r.template = template
+	}
+}
+
+// WithCommand registers a local slash command. Registering a command named
+// "help" or "exit" replaces the built-in.
+func WithCommand(cmd Command) Option {
+	return func(r *REPL) {
+		r.commands[cmd.Name] = cmd
+	}
+}
+
+// WithInterrupts sets the channel that delivers interrupts instead of
+// listening for os.Interrupt (Ctrl+C).
+func WithInterrupts(interrupts <-chan os.Signal) Option {
+	return func(r *REPL) {
+		r.interrupts = interrupts
+	}
+}
+
+// REPL is an interactive read-eval-print loop over a Claude Code conversation.
+type REPL struct {
+	conv       Conversation
+	in         io.Reader
+	out        io.Writer
+	reader     LineReader
+	prompt     string
+	renderer   Renderer
+	template   types.QueryRequest
+	commands   map[string]Command
+	interrupts <-chan os.Signal
+}
+
+// New creates a REPL that sends user input to conv.
+func New(conv Conversation, opts ...Option) *REPL {
+	r := &REPL{
+		conv:     conv,
+		in:       os.Stdin,
+		out:      os.Stdout,
+		prompt:   DefaultPrompt,
+		renderer: TextRenderer{},
+		commands: make(map[string]Command),
+	}
+
+	r.commands["exit"] = Command{
+		Name:        "exit",
+		Description: "Exit the REPL",
+		Run: func(ctx context.Context, args string, out io.Writer) error {
+			return ErrExit
+		},
+	}
+	r.commands["quit"] = Command{
+		Name:        "quit",
+		Description: "Exit the REPL",
+		Run:         r.commands["exit"].Run,
+	}
+	r.commands["help"] = Command{
+		Name:        "help",
+		Description: "Show available commands",
+		Run:         r.help,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.reader == nil {
+		r.reader = NewLineReader(r.in, r.out)
+	}
+
+	return r
+}
+
+// Run reads input until EOF, an exit command, or ctx is canceled. Each line
+// is streamed to Claude Code and rendered as it arrives; Ctrl+C interrupts
+// the response in progress and returns to the prompt. Lines starting with "/"
+// run a registered command if one matches and are otherwise passed through
+// to Claude Code as slash commands.
+func (r *REPL) Run(ctx context.Context) error {
+	interrupts := r.interrupts
+	if interrupts == nil {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt)
+		defer signal.Stop(signals)
+		interrupts = signals
+	}
+
+	type readResult struct {
+		line string
+		err  error
+	}
+	requests := make(chan struct{})
+	results := make(chan readResult, 1)
+	defer close(requests)
+
+	// Read lines on demand so a blocked read never delays an interrupt
+	go func() {
+		for range requests {
+			line, err := r.reader.ReadLine(r.prompt)
+			results <- readResult{line: line, err: err}
+		}
+	}()
+
+	for {
+		select {
+		case requests <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		var result readResult
+	wait:
+		for {
+			select {
+			case result = <-results:
+				break wait
+			case <-interrupts:
+				// The terminal discards the partial line; start a fresh one
+				fmt.Fprintln(r.out)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if result.err == io.EOF {
+			fmt.Fprintln(r.out)
+			return nil
+		}
+		if result.err != nil {
+			return result.err
+		}
+
+		line := strings.TrimSpace(result.line)
+		if line == "" {
+			continue
+		}
+
+		if err := r.eval(ctx, line, interrupts); err != nil {
+			if errors.Is(err, ErrExit) {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Fprintf(r.out, "error: %v\n", err)
+		}
+	}
+}
+
+// eval runs a local command or streams the line to Claude Code.
+func (r *REPL) eval(ctx context.Context, line string, interrupts <-chan os.Signal) error {
+	if strings.HasPrefix(line, "/") {
+		name, args, _ := strings.Cut(strings.TrimPrefix(line, "/"), " ")
+		if cmd, ok := r.commands[name]; ok {
+			return cmd.Run(ctx, strings.TrimSpace(args), r.out)
+		}
+	}
+
+	return r.stream(ctx, line, interrupts)
+}
+
+// stream sends one turn and renders the response until it completes or is interrupted.
+func (r *REPL) stream(ctx context.Context, input string, interrupts <-chan os.Signal) error {
+	turnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	request := r.template
+	request.Messages = append(append([]types.Message(nil), r.template.Messages...), types.Message{
+		Role:    types.RoleUser,
+		Content: input,
+	})
+
+	stream, err := r.conv.QueryStream(turnCtx, &request)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	interrupted := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-interrupts:
+			close(interrupted)
+			cancel()
+			_ = stream.Close() // Ignore error, unblocks a pending Recv
+		case <-done:
+		}
+	}()
+
+	for {
+		chunk, err := stream.Recv()
+		if err == nil && !chunk.Done {
+			if err := r.renderer.Render(r.out, chunk); err != nil {
+				return err
+			}
+			continue
+		}
+
+		select {
+		case <-interrupted:
+			fmt.Fprintln(r.out, "\n^C interrupted")
+			return nil
+		default:
+		}
+
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if chunk != nil && chunk.Done {
+			if err := r.renderer.Render(r.out, chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// help lists the local commands.
+func (r *REPL) help(ctx context.Context, args string, out io.Writer) error {
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(out, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(out, "  /%-10s %s\n", name, r.commands[name].Description)
+	}
+	fmt.Fprintln(out, "Other /commands are passed through to Claude Code. Press Ctrl+C to interrupt a response and Ctrl+D to exit.")
+	return nil
+}
+
+// lineReader is the default LineReader. It relies on the terminal's line
+// editing and joins lines ending in a backslash into multi-line input.
+type lineReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+// NewLineReader returns a LineReader that reads lines from in and writes
+// prompts to out. A line ending in "\" continues on the next line.
+func NewLineReader(in io.Reader, out io.Writer) LineReader {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &lineReader{scanner: scanner, out: out}
+}
+
+// ReadLine implements LineReader.
+func (l *lineReader) ReadLine(prompt string) (string, error) {
+	var lines []string
+	for {
+		fmt.Fprint(l.out, prompt)
+		if !l.scanner.Scan() {
+			if err := l.scanner.Err(); err != nil {
+				return "", err
+			}
+			if len(lines) > 0 {
+				return strings.Join(lines, "\n"), nil
+			}
+			return "", io.EOF
+		}
+
+		line := l.scanner.Text()
+		if !strings.HasSuffix(line, "\\") {
+			return strings.Join(append(lines, line), "\n"), nil
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\\"))
+		prompt = ContinuationPrompt
+	}
+}
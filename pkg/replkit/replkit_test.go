@@ -0,0 +1,183 @@
+package replkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeStream replays chunks, optionally blocking before the end until closed.
+type fakeStream struct {
+	chunks []*types.StreamChunk
+	block  bool
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (s *fakeStream) Recv() (*types.StreamChunk, error) {
+	if len(s.chunks) > 0 {
+		chunk := s.chunks[0]
+		s.chunks = s.chunks[1:]
+		return chunk, nil
+	}
+	if s.block {
+		<-s.closed
+		return nil, errors.New("stream closed")
+	}
+	return &types.StreamChunk{Done: true}, nil
+}
+
+func (s *fakeStream) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
+
+// fakeConversation records prompts and answers with canned chunks.
+type fakeConversation struct {
+	prompts []string
+	respond func(prompt string) *fakeStream
+}
+
+func (c *fakeConversation) QueryStream(ctx context.Context, request *types.QueryRequest) (types.QueryStream, error) {
+	prompt := request.Messages[len(request.Messages)-1].Content
+	c.prompts = append(c.prompts, prompt)
+	stream := c.respond(prompt)
+	stream.closed = make(chan struct{})
+	return stream, nil
+}
+
+func TestREPL_Run(t *testing.T) {
+	conv := &fakeConversation{respond: func(prompt string) *fakeStream {
+		text, _ := json.Marshal("echo: " + prompt)
+		return &fakeStream{chunks: []*types.StreamChunk{
+			{Content: `{"type":"system","subtype":"init"}` + "\n"},
+			{Content: `{"type":"assistant","message":{"content":[{"type":"text","text":` + string(text) + `}]}}` + "\n"},
+			{Content: "plain output\n"},
+		}}
+	}}
+
+	var out bytes.Buffer
+	input := "hello\n\n/compact keep the plan\n/ping\nmulti \\\nline\n/exit\nignored\n"
+	repl := New(conv,
+		WithInput(strings.NewReader(input)),
+		WithOutput(&out),
+		WithInterrupts(make(chan os.Signal)),
+		WithCommand(Command{Name: "ping", Description: "Reply pong", Run: func(ctx context.Context, args string, out io.Writer) error {
+			_, err := io.WriteString(out, "pong\n")
+			return err
+		}}),
+	)
+
+	if err := repl.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	expected := []string{"hello", "/compact keep the plan", "multi \nline"}
+	if strings.Join(conv.prompts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected prompts %q, got %q", expected, conv.prompts)
+	}
+
+	output := out.String()
+	for _, want := range []string{"echo: hello\n", "plain output\n", "pong\n", "... "} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, `"subtype"`) {
+		t.Errorf("Expected protocol frames to be hidden, got:\n%s", output)
+	}
+}
+
+func TestREPL_InterruptStopsResponse(t *testing.T) {
+	conv := &fakeConversation{respond: func(prompt string) *fakeStream {
+		return &fakeStream{chunks: []*types.StreamChunk{{Content: "partial\n"}}, block: true}
+	}}
+
+	interrupts := make(chan os.Signal, 1)
+	inputReader, inputWriter := io.Pipe()
+	var out safeBuffer
+
+	repl := New(conv, WithInput(inputReader), WithOutput(&out), WithInterrupts(interrupts))
+
+	done := make(chan error, 1)
+	go func() { done <- repl.Run(context.Background()) }()
+
+	_, _ = io.WriteString(inputWriter, "long task\n")
+	waitFor(t, func() bool { return strings.Contains(out.String(), "partial") })
+
+	interrupts <- os.Interrupt
+	waitFor(t, func() bool { return strings.Contains(out.String(), "^C interrupted") })
+
+	// The REPL returns to the prompt and keeps running
+	_ = inputWriter.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after EOF")
+	}
+}
+
+func TestREPL_Help(t *testing.T) {
+	var out bytes.Buffer
+	repl := New(&fakeConversation{}, WithInput(strings.NewReader("/help\n")), WithOutput(&out), WithInterrupts(make(chan os.Signal)))
+
+	if err := repl.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "/exit") || !strings.Contains(out.String(), "passed through") {
+		t.Errorf("Unexpected help output:\n%s", out.String())
+	}
+}
+
+func TestTextRenderer_ShowTools(t *testing.T) {
+	var out bytes.Buffer
+	chunk := &types.StreamChunk{Content: `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read"}]}}`}
+
+	if err := (TextRenderer{ShowTools: true}).Render(&out, chunk); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if out.String() != "[using Read]\n" {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
+
+// safeBuffer is a bytes.Buffer safe for concurrent writes and reads.
+type safeBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
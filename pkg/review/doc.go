@@ -0,0 +1,27 @@
+/*
+Package review asks Claude for structured code reviews and parses them into
+a Report of issues with severity, file, line and suggestion, instead of
+free-form prose that each caller scrapes differently.
+
+SystemPrompt switches Claude into a JSON-only review mode described by
+Schema. Parse is lenient about what comes back: fenced or embedded JSON, a
+bare issue array, severity aliases such as "warning" or "nit", and line
+ranges are all accepted. Reviewer combines the two and sends unparsable
+replies back to Claude to correct.
+
+# Basic Usage
+
+	reviewer := &review.Reviewer{Executor: claudeClient, Focus: "security"}
+	report, err := reviewer.Review(ctx, diff)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Print(report.Markdown())
+	if report.Count(review.SeverityHigh) > 0 {
+		os.Exit(1)
+	}
+
+With another executor, use Prompt, SystemPrompt and Parse directly.
+*/
+package review
@@ -0,0 +1,135 @@
+package review
+
+import (
+	"encoding/json"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// Parse extracts a Report from Claude's response. The JSON may be wrapped in
+// a code fence or surrounded by prose, and a bare array of issues is accepted
+// too. Severities are normalized and issues sorted most serious first.
+func Parse(text string) (*Report, error) {
+	data := extractJSON(text)
+	if data == "" {
+		return nil, sdkerrors.NewValidationError("review", truncate(text), "JSON object", "response does not contain a review report")
+	}
+
+	var raw struct {
+		Summary string     `json:"summary"`
+		Issues  []rawIssue `json:"issues"`
+	}
+	if strings.HasPrefix(data, "[") {
+		if err := json.Unmarshal([]byte(data), &raw.Issues); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "REVIEW_PARSE", "invalid review issues")
+		}
+	} else if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "REVIEW_PARSE", "invalid review report")
+	}
+
+	report := &Report{Summary: strings.TrimSpace(raw.Summary), Issues: make([]Issue, 0, len(raw.Issues))}
+	for _, issue := range raw.Issues {
+		if strings.TrimSpace(issue.Message) == "" {
+			return nil, sdkerrors.NewValidationError("issues", issue.File, "message", "review issue has no message")
+		}
+		report.Issues = append(report.Issues, Issue{
+			Severity:   ParseSeverity(issue.Severity),
+			File:       issue.File,
+			Line:       int(issue.Line),
+			Category:   issue.Category,
+			Message:    strings.TrimSpace(issue.Message),
+			Suggestion: strings.TrimSpace(issue.Suggestion),
+		})
+	}
+	report.sort()
+	return report, nil
+}
+
+// rawIssue accepts the loose shapes Claude produces: a severity in any case,
+// the line as a number or string, and "description" for the message.
+type rawIssue struct {
+	Severity    string       `json:"severity"`
+	File        string       `json:"file"`
+	Line        flexibleLine `json:"line"`
+	Category    string       `json:"category"`
+	Message     string       `json:"message"`
+	Suggestion  string       `json:"suggestion"`
+	Description string       `json:"description"`
+}
+
+// UnmarshalJSON falls back to the description field for the message.
+func (i *rawIssue) UnmarshalJSON(data []byte) error {
+	type plain rawIssue
+	if err := json.Unmarshal(data, (*plain)(i)); err != nil {
+		return err
+	}
+	if i.Message == "" {
+		i.Message = i.Description
+	}
+	return nil
+}
+
+// flexibleLine decodes a line number given as a number, a numeric string or
+// a range such as "12-15" (keeping the start).
+type flexibleLine int
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *flexibleLine) UnmarshalJSON(data []byte) error {
+	var number float64
+	if err := json.Unmarshal(data, &number); err == nil {
+		*l = flexibleLine(number)
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return nil // Unknown line
+	}
+	line := 0
+	for _, c := range strings.TrimSpace(text) {
+		if c < '0' || c > '9' {
+			break
+		}
+		line = line*10 + int(c-'0')
+	}
+	*l = flexibleLine(line)
+	return nil
+}
+
+// extractJSON returns the JSON document in text: the contents of the first
+// fenced block if there is one, otherwise the outermost object or array.
+func extractJSON(text string) string {
+	if start := strings.Index(text, "```"); start >= 0 {
+		block := text[start+3:]
+		if newline := strings.Index(block, "\n"); newline >= 0 {
+			block = block[newline+1:]
+		}
+		if end := strings.Index(block, "```"); end >= 0 {
+			if data := strings.TrimSpace(block[:end]); strings.HasPrefix(data, "{") || strings.HasPrefix(data, "[") {
+				return data
+			}
+		}
+	}
+
+	objStart, arrStart := strings.Index(text, "{"), strings.Index(text, "[")
+	switch {
+	case objStart >= 0 && (arrStart < 0 || objStart < arrStart):
+		if end := strings.LastIndex(text, "}"); end > objStart {
+			return text[objStart : end+1]
+		}
+	case arrStart >= 0:
+		if end := strings.LastIndex(text, "]"); end > arrStart {
+			return text[arrStart : end+1]
+		}
+	}
+	return ""
+}
+
+// truncate shortens a response for error values.
+func truncate(text string) string {
+	if len(text) > 200 {
+		return text[:200] + "..."
+	}
+	return text
+}
@@ -0,0 +1,167 @@
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity ranks how serious an issue is.
+type Severity string
+
+const (
+	// SeverityCritical issues are exploitable or cause data loss
+	SeverityCritical Severity = "critical"
+
+	// SeverityHigh issues are bugs that will show up in normal use
+	SeverityHigh Severity = "high"
+
+	// SeverityMedium issues are edge-case bugs or risky patterns
+	SeverityMedium Severity = "medium"
+
+	// SeverityLow issues are style, naming and readability problems
+	SeverityLow Severity = "low"
+
+	// SeverityInfo issues are observations that need no change
+	SeverityInfo Severity = "info"
+)
+
+// severityRanks orders severities from most to least serious.
+var severityRanks = map[Severity]int{
+	SeverityCritical: 4,
+	SeverityHigh:     3,
+	SeverityMedium:   2,
+	SeverityLow:      1,
+	SeverityInfo:     0,
+}
+
+// severityAliases maps other common severity names to the scale.
+var severityAliases = map[string]Severity{
+	"blocker": SeverityCritical,
+	"error":   SeverityHigh,
+	"major":   SeverityHigh,
+	"warning": SeverityMedium,
+	"minor":   SeverityLow,
+	"nit":     SeverityLow,
+	"style":   SeverityLow,
+	"note":    SeverityInfo,
+}
+
+// ParseSeverity normalizes a severity name, mapping aliases such as
+// "warning" or "nit" onto the scale. Unknown names are SeverityInfo.
+func ParseSeverity(name string) Severity {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := severityRanks[Severity(name)]; ok {
+		return Severity(name)
+	}
+	if severity, ok := severityAliases[name]; ok {
+		return severity
+	}
+	return SeverityInfo
+}
+
+// AtLeast reports whether s is as serious as threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return severityRanks[s] >= severityRanks[threshold]
+}
+
+// Issue is a single review finding.
+type Issue struct {
+	// Severity ranks the issue
+	Severity Severity `json:"severity"`
+
+	// File is the path of the affected file, when known
+	File string `json:"file,omitempty"`
+
+	// Line is the 1-based line the issue starts at (0 when unknown)
+	Line int `json:"line,omitempty"`
+
+	// Category groups issues, e.g. "bug", "security", "performance" or "style"
+	Category string `json:"category,omitempty"`
+
+	// Message describes the problem
+	Message string `json:"message"`
+
+	// Suggestion describes or shows the fix
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Location returns "file:line", "file" or "" depending on what is known.
+func (i Issue) Location() string {
+	if i.File == "" {
+		return ""
+	}
+	if i.Line > 0 {
+		return fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	return i.File
+}
+
+// Report is the structured result of a code review.
+type Report struct {
+	// Summary is a short overall assessment
+	Summary string `json:"summary"`
+
+	// Issues are the findings, most serious first
+	Issues []Issue `json:"issues"`
+}
+
+// Count returns the number of issues at or above the severity.
+func (r *Report) Count(threshold Severity) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity.AtLeast(threshold) {
+			count++
+		}
+	}
+	return count
+}
+
+// Filter returns the issues at or above the severity.
+func (r *Report) Filter(threshold Severity) []Issue {
+	var issues []Issue
+	for _, issue := range r.Issues {
+		if issue.Severity.AtLeast(threshold) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// sort orders issues by severity, then file and line.
+func (r *Report) sort() {
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		a, b := r.Issues[i], r.Issues[j]
+		if severityRanks[a.Severity] != severityRanks[b.Severity] {
+			return severityRanks[a.Severity] > severityRanks[b.Severity]
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+}
+
+// Markdown renders the report for pull request comments and terminals.
+func (r *Report) Markdown() string {
+	var out strings.Builder
+	if r.Summary != "" {
+		out.WriteString(r.Summary + "\n\n")
+	}
+	if len(r.Issues) == 0 {
+		out.WriteString("No issues found.\n")
+		return out.String()
+	}
+
+	for _, issue := range r.Issues {
+		fmt.Fprintf(&out, "- **%s**", issue.Severity)
+		if location := issue.Location(); location != "" {
+			fmt.Fprintf(&out, " `%s`", location)
+		}
+		out.WriteString(" " + issue.Message + "\n")
+		if issue.Suggestion != "" {
+			out.WriteString("  Suggestion: " + strings.ReplaceAll(issue.Suggestion, "\n", "\n  ") + "\n")
+		}
+	}
+	return out.String()
+}
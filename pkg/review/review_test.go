@@ -0,0 +1,128 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// scriptedExecutor replies with the given responses in order.
+type scriptedExecutor struct {
+	responses []string
+	requests  []*types.QueryRequest
+}
+
+func (e *scriptedExecutor) Query(_ context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	e.requests = append(e.requests, request)
+	if len(e.responses) == 0 {
+		return nil, errors.New("no more responses")
+	}
+	text := e.responses[0]
+	e.responses = e.responses[1:]
+	return &types.QueryResponse{Content: []types.ContentBlock{{Type: "text", Text: text}}}, nil
+}
+
+func TestParse(t *testing.T) {
+	text := "Here is my review:\n```json\n" + `{
+  "summary": "Mostly fine",
+  "issues": [
+    {"severity": "nit", "file": "a.go", "line": 3, "message": "Rename x"},
+    {"severity": "CRITICAL", "file": "db.go", "line": "42-45", "category": "security", "message": "SQL injection", "suggestion": "Use a placeholder"},
+    {"severity": "warning", "file": "a.go", "line": 1, "description": "Unchecked error"}
+  ]
+}` + "\n```\nLet me know if you have questions."
+
+	report, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if report.Summary != "Mostly fine" || len(report.Issues) != 3 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+
+	first := report.Issues[0]
+	if first.Severity != SeverityCritical || first.Location() != "db.go:42" || first.Suggestion != "Use a placeholder" {
+		t.Errorf("Unexpected first issue %+v", first)
+	}
+	if report.Issues[1].Severity != SeverityMedium || report.Issues[1].Message != "Unchecked error" {
+		t.Errorf("Expected the description as message, got %+v", report.Issues[1])
+	}
+	if report.Issues[2].Severity != SeverityLow {
+		t.Errorf("Expected nit to map to low, got %+v", report.Issues[2])
+	}
+	if report.Count(SeverityMedium) != 2 || len(report.Filter(SeverityHigh)) != 1 {
+		t.Errorf("Unexpected counts %d %d", report.Count(SeverityMedium), len(report.Filter(SeverityHigh)))
+	}
+}
+
+func TestParse_Shapes(t *testing.T) {
+	report, err := Parse(`[{"severity":"high","message":"Nil map write"}]`)
+	if err != nil || len(report.Issues) != 1 || report.Issues[0].Severity != SeverityHigh {
+		t.Errorf("Expected a bare issue array to parse, got %+v, %v", report, err)
+	}
+
+	report, err = Parse(`{"summary":"Looks good","issues":[]}`)
+	if err != nil || len(report.Issues) != 0 || !strings.Contains(report.Markdown(), "No issues found") {
+		t.Errorf("Expected an empty report, got %+v, %v", report, err)
+	}
+
+	for _, text := range []string{"The code looks great!", `{"issues":[{"severity":"high"}]}`, `{"issues": [}`} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Expected Parse(%q) to fail", text)
+		}
+	}
+}
+
+func TestReviewer_RetriesUnparsableReplies(t *testing.T) {
+	executor := &scriptedExecutor{responses: []string{
+		"I found one problem: the loop never ends.",
+		`{"summary":"One bug","issues":[{"severity":"high","file":"loop.go","line":7,"message":"Infinite loop"}]}`,
+	}}
+	reviewer := &Reviewer{Executor: executor, Focus: "correctness"}
+
+	report, err := reviewer.Review(context.Background(), "--- loop.go\nfor {}\n")
+	if err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Location() != "loop.go:7" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	if len(executor.requests) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(executor.requests))
+	}
+	if executor.requests[0].System != SystemPrompt || !strings.Contains(executor.requests[0].Messages[0].GetText(), "focusing on correctness") {
+		t.Errorf("Unexpected first request %+v", executor.requests[0])
+	}
+	if retry := executor.requests[1].Messages[0].GetText(); !strings.Contains(retry, "the loop never ends") || !strings.Contains(retry, "only the JSON object") {
+		t.Errorf("Expected the failed reply in the retry prompt, got %q", retry)
+	}
+}
+
+func TestReviewer_GivesUp(t *testing.T) {
+	executor := &scriptedExecutor{responses: []string{"no", "still no"}}
+	reviewer := &Reviewer{Executor: executor}
+
+	if _, err := reviewer.Review(context.Background(), "x := 1"); err == nil {
+		t.Error("Expected Review to fail after MaxAttempts")
+	}
+	if len(executor.requests) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", len(executor.requests))
+	}
+}
+
+func TestReport_Markdown(t *testing.T) {
+	report := &Report{Summary: "Needs work", Issues: []Issue{
+		{Severity: SeverityHigh, File: "main.go", Line: 10, Message: "Leaked file handle", Suggestion: "defer f.Close()"},
+		{Severity: SeverityInfo, Message: "Consider adding docs"},
+	}}
+	want := "Needs work\n\n" +
+		"- **high** `main.go:10` Leaked file handle\n  Suggestion: defer f.Close()\n" +
+		"- **info** Consider adding docs\n"
+	if got := report.Markdown(); got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
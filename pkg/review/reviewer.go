@@ -0,0 +1,108 @@
+package review
+
+import (
+	"context"
+	"strings"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Schema is the JSON Schema of a Report, included in the system prompt so
+// Claude answers in the shape Parse expects.
+const Schema = `{
+  "type": "object",
+  "required": ["summary", "issues"],
+  "properties": {
+    "summary": {"type": "string"},
+    "issues": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["severity", "message"],
+        "properties": {
+          "severity": {"enum": ["critical", "high", "medium", "low", "info"]},
+          "file": {"type": "string"},
+          "line": {"type": "integer", "minimum": 1},
+          "category": {"enum": ["bug", "security", "performance", "style", "maintainability"]},
+          "message": {"type": "string"},
+          "suggestion": {"type": "string"}
+        }
+      }
+    }
+  }
+}`
+
+// SystemPrompt puts Claude in structured review mode: it must answer with a
+// single JSON object matching Schema.
+const SystemPrompt = `You are a meticulous code reviewer. Report concrete problems only, ` +
+	`each with the file and line it occurs at and a suggested fix. ` +
+	`Reply with a single JSON object matching this JSON Schema and nothing else, ` +
+	`without code fences or commentary:
+
+` + Schema
+
+// Prompt builds the review request for code or a diff. Focus, when set,
+// narrows the review, e.g. "security" or "error handling".
+func Prompt(code, focus string) string {
+	var prompt strings.Builder
+	prompt.WriteString("Review the following code")
+	if focus != "" {
+		prompt.WriteString(", focusing on " + focus)
+	}
+	prompt.WriteString(". Use the file paths shown in it for the file field.\n\n")
+	prompt.WriteString(code)
+	return prompt.String()
+}
+
+// Reviewer asks Claude for a structured review and parses the result.
+type Reviewer struct {
+	// Executor runs the review queries
+	Executor types.QueryExecutor
+
+	// Model overrides the executor's model
+	Model string
+
+	// Focus narrows every review (see Prompt)
+	Focus string
+
+	// MaxAttempts bounds the queries per review; replies that fail to parse
+	// are sent back to Claude to correct (defaults to 2)
+	MaxAttempts int
+}
+
+// Review reviews code or a diff and returns the parsed report.
+func (r *Reviewer) Review(ctx context.Context, code string) (*Report, error) {
+	if r.Executor == nil {
+		return nil, sdkerrors.NewConfigurationError("executor", "a reviewer needs an executor")
+	}
+	attempts := r.MaxAttempts
+	if attempts <= 0 {
+		attempts = 2
+	}
+
+	prompt := Prompt(code, r.Focus)
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		response, err := r.Executor.Query(ctx, &types.QueryRequest{
+			Model:    r.Model,
+			System:   SystemPrompt,
+			Messages: []types.Message{{Role: types.RoleUser, Content: prompt}},
+		})
+		if err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "REVIEW", "review query failed")
+		}
+
+		text := response.GetTextContent()
+		report, err := Parse(text)
+		if err == nil {
+			return report, nil
+		}
+		lastErr = err
+
+		// Executors may not keep a conversation, so repeat the request
+		prompt = Prompt(code, r.Focus) + "\n\nYour previous reply could not be parsed (" + err.Error() +
+			"):\n\n" + truncate(text) + "\n\nReply again with only the JSON object."
+	}
+	return nil, lastErr
+}
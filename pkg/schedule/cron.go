@@ -0,0 +1,219 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// Schedule decides when a task runs.
+type Schedule interface {
+	// Next returns the first activation after t, in t's location, or the
+	// zero time if there is none
+	Next(t time.Time) time.Time
+}
+
+// descriptors are the predefined schedules ParseCron accepts.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes the values of a field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronSchedule is a parsed five-field cron expression. Each field is a set
+// of bits, one per allowed value.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// anyDay is set when the day of month or day of week field starts with
+	// "*", such as "*/2", or is "?", in which case a day must match both
+	// fields rather than either, as in Vixie cron
+	anyDay bool
+}
+
+// everySchedule activates at a fixed interval.
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Next implements Schedule.
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Add(e.interval)
+}
+
+// ParseCron parses a standard five-field cron expression (minute, hour, day
+// of month, month, day of week), such as "30 2 * * MON-FRI". Fields accept
+// "*", values, ranges, lists and steps like "*/15" or "1-10/2"; months and
+// weekdays also accept three-letter names, and Sunday is 0 or 7. When both
+// day fields are restricted, a day matching either runs the task, as in
+// cron.
+//
+// The descriptors @yearly, @monthly, @weekly, @daily, @hourly and
+// "@every <duration>" are also accepted.
+func ParseCron(expr string) (Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || interval <= 0 {
+			return nil, sdkerrors.NewValidationError("cron", expr, "positive duration", "invalid @every interval")
+		}
+		return everySchedule{interval: interval}, nil
+	}
+	if descriptor, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, sdkerrors.NewValidationError("cron", expr, "5 fields", "cron expressions have minute, hour, day of month, month and day of week fields")
+	}
+
+	var schedule cronSchedule
+	var err error
+	targets := []*uint64{&schedule.minute, &schedule.hour, &schedule.dom, &schedule.month, &schedule.dow}
+	for i, field := range []cronField{minuteField, hourField, domField, monthField, dowField} {
+		if *targets[i], err = field.parse(fields[i]); err != nil {
+			return nil, sdkerrors.NewValidationError("cron", expr, field.name, err.Error())
+		}
+	}
+	// Sunday is both 0 and 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.anyDay = isUnrestrictedDay(fields[2]) || isUnrestrictedDay(fields[4])
+	return &schedule, nil
+}
+
+// isUnrestrictedDay reports whether a day field leaves the other day field
+// to decide, which cron takes from a leading "*" even with a step.
+func isUnrestrictedDay(field string) bool {
+	return strings.HasPrefix(field, "*") || field == "?"
+}
+
+// isWildcard reports whether a field allows every value.
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parse returns the bits of the values a field allows.
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, stepPart)
+			}
+		}
+
+		low, high := f.min, f.max
+		switch {
+		case isWildcard(rangePart):
+			if f.name == dowField.name {
+				high = 6
+			}
+		default:
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = f.value(lowPart); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = f.value(highPart); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = f.max
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid %s range %q", f.name, rangePart)
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a number or name of the field.
+func (f cronField) value(text string) (int, error) {
+	if value, ok := f.names[strings.ToLower(text)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid %s %q (%d-%d)", f.name, text, f.min, f.max)
+	}
+	return value, nil
+}
+
+// Next implements Schedule. It returns the zero time if no activation
+// exists within five years, such as for "0 0 30 2 *".
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether the schedule runs on t's day.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
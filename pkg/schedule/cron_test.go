@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	// Wednesday
+	from := time.Date(2025, time.January, 15, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, 1, 16, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * MON-FRI", time.Date(2025, 1, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * sun", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,20 jan *", time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)},
+		{"5-10/5 10 * * *", time.Date(2025, 1, 16, 10, 5, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted
+		{"0 0 31 * 5", time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// A stepped "*" still leaves the day to both fields: an odd-numbered Monday
+		{"0 0 */2 * 1", time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", from.Add(90 * time.Minute)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+
+	never, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron failed: %v", err)
+	}
+	if next := never.Next(from); !next.IsZero() {
+		t.Errorf("Expected no activation on February 30, got %s", next)
+	}
+}
+
+func TestParseCron_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	schedule, err := ParseCron("0 3 * * *")
+	if err != nil {
+		t.Fatalf("ParseCron failed: %v", err)
+	}
+	next := schedule.Next(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC).In(loc))
+	if want := time.Date(2025, 1, 15, 3, 0, 0, 0, loc); !next.Equal(want) {
+		t.Errorf("Next = %s, want %s", next, want)
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"10-5 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every soon",
+		"@every -1m",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q): expected an error", expr)
+		}
+	}
+}
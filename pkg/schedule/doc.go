@@ -0,0 +1,52 @@
+/*
+Package schedule runs workflows on cron schedules, for automations such as
+nightly code health reports or weekly dependency reviews.
+
+A Scheduler runs each Task's workflow when its cron expression is due. A
+task never overlaps itself: a run that comes due while the previous run is
+still going is skipped and reported. Each run gets a fresh workflow built
+from the task's Definition, its own copy of the task's values, and a Budget
+limiting its duration and the cost of its responses.
+
+# Basic Usage
+
+	definition, err := workflow.Load("workflows/code-health.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	scheduler := schedule.New(claudeClient, schedule.WithLocation(time.UTC))
+	err = scheduler.Add(schedule.Task{
+		Name:     "code-health",
+		Cron:     "0 2 * * MON-FRI",
+		Workflow: definition,
+		Values:   map[string]any{"repo": "payments"},
+		Budget:   schedule.Budget{MaxDuration: 30 * time.Minute, MaxCostUSD: 5},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := scheduler.Start(); err != nil {
+		log.Fatal(err)
+	}
+	defer scheduler.Stop()
+
+# Events
+
+Subscribe delivers an Event when a run starts, succeeds, fails or is
+skipped. Finished runs carry the workflow report and what the run cost:
+
+	events, unsubscribe := scheduler.Subscribe()
+	defer unsubscribe()
+	for event := range events {
+		if event.Type == schedule.EventFailed {
+			alert(event.Task, event.Error, event.Report)
+		}
+	}
+
+Once a run's responses have cost Budget.MaxCostUSD, its remaining query
+steps fail with ErrBudgetExceeded. RunNow runs a task immediately, outside
+its schedule, for manual triggers and tests; Stop cancels and waits for it
+like any other run.
+*/
+package schedule
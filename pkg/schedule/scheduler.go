@@ -0,0 +1,411 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/pricing"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/workflow"
+)
+
+// ErrBudgetExceeded fails the query steps of a run once its budget is spent.
+var ErrBudgetExceeded = errors.New("run budget exceeded")
+
+// Task is a workflow run on a schedule.
+type Task struct {
+	// Name identifies the task in events
+	Name string
+
+	// Cron is when the task runs (see ParseCron)
+	Cron string
+
+	// Workflow is the workflow each run executes
+	Workflow *workflow.Definition
+
+	// Values are passed to each run of the workflow
+	Values map[string]any
+
+	// Budget limits each run
+	Budget Budget
+
+	// Options configure the workflow, e.g. its approver
+	Options []workflow.Option
+}
+
+// Budget limits the time and cost of a run. Zero fields are unlimited.
+type Budget struct {
+	// MaxDuration cancels a run that takes longer
+	MaxDuration time.Duration
+
+	// MaxCostUSD fails the remaining query steps of a run once its
+	// responses have cost this much
+	MaxCostUSD float64
+
+	// Pricing prices responses by model (nil uses pricing.Default)
+	Pricing pricing.Provider
+}
+
+// EventType identifies what happened to a task.
+type EventType string
+
+const (
+	// EventStarted is sent when a run starts
+	EventStarted EventType = "started"
+
+	// EventSucceeded is sent when a run's workflow succeeds
+	EventSucceeded EventType = "succeeded"
+
+	// EventFailed is sent when a run's workflow fails
+	EventFailed EventType = "failed"
+
+	// EventSkipped is sent when a run is due while the previous run of the
+	// task is still going
+	EventSkipped EventType = "skipped"
+)
+
+// Event reports a step in the life of a task's run.
+type Event struct {
+	Type EventType `json:"type"`
+	Task string    `json:"task"`
+
+	// Scheduled is when the run was due, and Time when the event happened
+	Scheduled time.Time `json:"scheduled"`
+	Time      time.Time `json:"time"`
+
+	// Report is the workflow report of finished runs
+	Report *workflow.Report `json:"report,omitempty"`
+
+	// CostUSD is what a finished run's responses cost
+	CostUSD float64 `json:"cost_usd,omitempty"`
+
+	// Error describes why a run failed or was skipped
+	Error string `json:"error,omitempty"`
+}
+
+// Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithLocation sets the time zone cron expressions are read in (defaults to
+// time.Local).
+func WithLocation(loc *time.Location) Option {
+	return func(s *Scheduler) {
+		s.location = loc
+	}
+}
+
+// scheduledTask is a task with its parsed schedule and run state.
+type scheduledTask struct {
+	Task
+	schedule Schedule
+	running  bool
+}
+
+// Scheduler runs workflow tasks on cron schedules. A task never overlaps
+// itself: a run that is due while the previous one is going is skipped.
+type Scheduler struct {
+	executor types.QueryExecutor
+	location *time.Location
+	now      func() time.Time
+
+	mu          sync.Mutex
+	tasks       map[string]*scheduledTask
+	subscribers []chan Event
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+	stopped bool
+}
+
+// New creates a scheduler that runs workflows with executor. Call Start to
+// begin running tasks.
+func New(executor types.QueryExecutor, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		executor: executor,
+		location: time.Local,
+		now:      time.Now,
+		tasks:    make(map[string]*scheduledTask),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add validates a task and schedules it. Tasks added after Start are
+// scheduled immediately.
+func (s *Scheduler) Add(task Task) error {
+	if task.Name == "" {
+		return sdkerrors.NewValidationError("name", "", "required", "task name cannot be empty")
+	}
+	if task.Workflow == nil {
+		return sdkerrors.NewValidationError("workflow", task.Name, "required", "task "+task.Name+" has no workflow")
+	}
+	if task.Budget.MaxDuration < 0 || task.Budget.MaxCostUSD < 0 {
+		return sdkerrors.NewValidationError("budget", task.Name, "0 or more", "task "+task.Name+" has a negative budget")
+	}
+	schedule, err := ParseCron(task.Cron)
+	if err != nil {
+		return err
+	}
+	if _, err := task.Workflow.Build(s.executor, task.Options...); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[task.Name]; ok {
+		return sdkerrors.NewValidationError("name", task.Name, "unique name", "duplicate task "+task.Name)
+	}
+	scheduled := &scheduledTask{Task: task, schedule: schedule}
+	s.tasks[task.Name] = scheduled
+	if s.started && !s.stopped {
+		s.wg.Add(1)
+		go s.loop(scheduled)
+	}
+	return nil
+}
+
+// Start begins running the tasks on their schedules.
+func (s *Scheduler) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return sdkerrors.NewInternalError("SCHEDULER_STARTED", "scheduler already started")
+	}
+	s.started = true
+	for _, task := range s.tasks {
+		s.wg.Add(1)
+		go s.loop(task)
+	}
+	return nil
+}
+
+// Stop cancels running workflows, including those started by RunNow, waits
+// for them to finish and closes the event subscriptions.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if !s.started || s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.stopped = true
+	s.mu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.subscribers {
+		close(ch)
+	}
+	s.subscribers = nil
+}
+
+// Next returns when the named task next runs.
+func (s *Scheduler) Next(name string) (time.Time, bool) {
+	s.mu.Lock()
+	task, ok := s.tasks[name]
+	s.mu.Unlock()
+	if !ok {
+		return time.Time{}, false
+	}
+	next := task.schedule.Next(s.now().In(s.location))
+	return next, !next.IsZero()
+}
+
+// Subscribe returns a channel that receives the events of every task. The
+// channel is closed when the scheduler stops; call the returned function to
+// unsubscribe early. Slow subscribers miss events rather than delaying runs.
+func (s *Scheduler) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 16)
+
+	s.mu.Lock()
+	s.subscribers = append(s.subscribers, ch)
+	s.mu.Unlock()
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, sub := range s.subscribers {
+			if sub == ch {
+				s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+				close(ch)
+				break
+			}
+		}
+	}
+	return ch, unsubscribe
+}
+
+// RunNow runs the named task immediately and waits for it, outside its
+// schedule. Like a scheduled run, it is canceled and waited for by Stop. It
+// fails if the task is already running or the scheduler has stopped.
+func (s *Scheduler) RunNow(ctx context.Context, name string) (*workflow.Report, error) {
+	s.mu.Lock()
+	task, ok := s.tasks[name]
+	stopped := s.stopped
+	if ok && !stopped {
+		s.wg.Add(1)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, sdkerrors.NewValidationError("name", name, "existing task", "unknown task "+name)
+	}
+	if stopped {
+		return nil, sdkerrors.NewInternalError("SCHEDULER_STOPPED", "scheduler stopped")
+	}
+	defer s.wg.Done()
+
+	if !s.claim(task, s.now()) {
+		return nil, sdkerrors.NewInternalError("TASK_RUNNING", "task "+name+" is already running")
+	}
+
+	// Cancel the run when either the caller or Stop does
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return s.run(ctx, task, s.now())
+}
+
+// loop runs a task each time it is due until the scheduler stops.
+func (s *Scheduler) loop(task *scheduledTask) {
+	defer s.wg.Done()
+	for {
+		next := task.schedule.Next(s.now().In(s.location))
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if s.claim(task, next) {
+			s.wg.Add(1)
+			go func(scheduled time.Time) {
+				defer s.wg.Done()
+				_, _ = s.run(s.ctx, task, scheduled) // Ignore error, the outcome is reported as an event
+			}(next)
+		}
+	}
+}
+
+// claim marks a task as running, or reports a skipped run if it already
+// is.
+func (s *Scheduler) claim(task *scheduledTask, scheduled time.Time) bool {
+	s.mu.Lock()
+	running := task.running
+	task.running = true
+	s.mu.Unlock()
+
+	if running {
+		s.publish(Event{Type: EventSkipped, Task: task.Name, Scheduled: scheduled, Error: "previous run still running"})
+		return false
+	}
+	return true
+}
+
+// run runs a claimed task's workflow within its budget.
+func (s *Scheduler) run(ctx context.Context, task *scheduledTask, scheduled time.Time) (*workflow.Report, error) {
+	defer func() {
+		s.mu.Lock()
+		task.running = false
+		s.mu.Unlock()
+	}()
+
+	if task.Budget.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Budget.MaxDuration)
+		defer cancel()
+	}
+
+	executor := &budgetExecutor{executor: s.executor, budget: task.Budget}
+	s.publish(Event{Type: EventStarted, Task: task.Name, Scheduled: scheduled})
+
+	var report *workflow.Report
+	w, err := task.Workflow.Build(executor, task.Options...)
+	if err == nil {
+		values := make(map[string]any, len(task.Values))
+		for key, value := range task.Values {
+			values[key] = value
+		}
+		report, err = w.Run(ctx, values)
+	}
+
+	event := Event{Type: EventSucceeded, Task: task.Name, Scheduled: scheduled, Report: report, CostUSD: executor.cost()}
+	if err != nil {
+		event.Type, event.Error = EventFailed, err.Error()
+	}
+	s.publish(event)
+	return report, err
+}
+
+// publish sends an event to every subscriber that has room for it.
+func (s *Scheduler) publish(event Event) {
+	event.Time = s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// budgetExecutor counts the cost of a run's responses and refuses queries
+// once the run's budget is spent.
+type budgetExecutor struct {
+	executor types.QueryExecutor
+	budget   Budget
+
+	mu    sync.Mutex
+	spent float64
+}
+
+// Query implements types.QueryExecutor.
+func (b *budgetExecutor) Query(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	if b.budget.MaxCostUSD > 0 && b.cost() >= b.budget.MaxCostUSD {
+		return nil, ErrBudgetExceeded
+	}
+
+	response, err := b.executor.Query(ctx, request)
+	if response != nil && response.Usage != nil {
+		provider := b.budget.Pricing
+		if provider == nil {
+			provider = pricing.Default()
+		}
+		if cost, costErr := pricing.Cost(provider, response.Model, *response.Usage); costErr == nil {
+			b.mu.Lock()
+			b.spent += cost
+			b.mu.Unlock()
+		}
+	}
+	return response, err
+}
+
+// cost returns what the run's responses have cost.
+func (b *budgetExecutor) cost() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
@@ -0,0 +1,230 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/pricing"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/workflow"
+)
+
+// executorFunc adapts a function to types.QueryExecutor.
+type executorFunc func(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error)
+
+func (f executorFunc) Query(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	return f(ctx, request)
+}
+
+// answer returns a response priced at one dollar per million input tokens.
+func answer(text string, inputTokens int) *types.QueryResponse {
+	return &types.QueryResponse{
+		Model:   "test-model",
+		Content: []types.ContentBlock{types.NewTextBlock(text)},
+		Usage:   &types.TokenUsage{InputTokens: inputTokens},
+	}
+}
+
+var testPricing = pricing.Table{"test-model": {InputPerMTok: 1, Currency: "USD"}}
+
+func mustParse(t *testing.T, yaml string) *workflow.Definition {
+	t.Helper()
+	definition, err := workflow.Parse([]byte(yaml))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return definition
+}
+
+func TestScheduler_OverlapPrevention(t *testing.T) {
+	var runs atomic.Int32
+	release := make(chan struct{})
+	executor := executorFunc(func(ctx context.Context, _ *types.QueryRequest) (*types.QueryResponse, error) {
+		if runs.Add(1) == 1 {
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		return answer("healthy", 1000), nil
+	})
+
+	scheduler := New(executor)
+	err := scheduler.Add(Task{
+		Name:     "health",
+		Cron:     "@every 20ms",
+		Workflow: mustParse(t, "name: health\nsteps:\n  - id: report\n    query: Write the code health report\n"),
+		Budget:   Budget{Pricing: testPricing},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	events, unsubscribe := scheduler.Subscribe()
+	defer unsubscribe()
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer scheduler.Stop()
+
+	var seen []EventType
+	timeout := time.After(5 * time.Second)
+	for {
+		var event Event
+		select {
+		case event = <-events:
+		case <-timeout:
+			t.Fatalf("Timed out waiting for events, got %v", seen)
+		}
+		seen = append(seen, event.Type)
+
+		if event.Type == EventSkipped {
+			if event.Task != "health" || event.Error == "" {
+				t.Errorf("Unexpected skipped event %+v", event)
+			}
+			close(release)
+		}
+		if event.Type == EventSucceeded {
+			if event.Report == nil || event.Report.Steps[0].Output != "healthy" || event.CostUSD != 0.001 {
+				t.Errorf("Unexpected succeeded event %+v", event)
+			}
+			break
+		}
+	}
+	if seen[0] != EventStarted || seen[1] != EventSkipped {
+		t.Errorf("Expected the overlapping run to be skipped, got %v", seen)
+	}
+}
+
+func TestScheduler_RunNowBudget(t *testing.T) {
+	var prompts []string
+	executor := executorFunc(func(_ context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+		prompts = append(prompts, request.Messages[0].Content)
+		return answer("done", 600_000), nil
+	})
+
+	scheduler := New(executor)
+	err := scheduler.Add(Task{
+		Name: "nightly",
+		Cron: "0 2 * * *",
+		Workflow: mustParse(t, `
+name: nightly
+steps:
+  - id: audit
+    query: Audit {{.repo}}
+  - id: fix
+    query: Fix the findings
+    after: [audit]
+  - id: summarize
+    query: Summarize
+    after: [fix]
+`),
+		Values: map[string]any{"repo": "sdk"},
+		Budget: Budget{MaxCostUSD: 1, Pricing: testPricing},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	events, unsubscribe := scheduler.Subscribe()
+	defer unsubscribe()
+
+	report, err := scheduler.RunNow(context.Background(), "nightly")
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected the budget to stop the run, got %v", err)
+	}
+	if strings.Join(prompts, "|") != "Audit sdk|Fix the findings" {
+		t.Errorf("Expected the run to stop after the budget was spent, got %q", prompts)
+	}
+	if report.Steps[2].Status != workflow.StatusFailed {
+		t.Errorf("Expected the summarize step to fail, got %+v", report.Steps[2])
+	}
+
+	started, finished := <-events, <-events
+	if started.Type != EventStarted || finished.Type != EventFailed || finished.CostUSD != 1.2 || finished.Report != report {
+		t.Errorf("Unexpected events %+v, %+v", started, finished)
+	}
+
+	next, ok := scheduler.Next("nightly")
+	if !ok || next.Hour() != 2 || next.Minute() != 0 || !next.After(time.Now()) {
+		t.Errorf("Unexpected next run %s", next)
+	}
+}
+
+func TestScheduler_StopCancelsRunNow(t *testing.T) {
+	started := make(chan struct{})
+	executor := executorFunc(func(ctx context.Context, _ *types.QueryRequest) (*types.QueryResponse, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	scheduler := New(executor)
+	err := scheduler.Add(Task{
+		Name:     "audit",
+		Cron:     "0 2 * * *",
+		Workflow: mustParse(t, "name: audit\nsteps:\n  - id: audit\n    query: Audit the repo\n"),
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := scheduler.RunNow(context.Background(), "audit")
+		done <- err
+	}()
+	<-started
+
+	// Stop returns only once the manual run has been canceled and finished
+	scheduler.Stop()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected Stop to cancel the run, got %v", err)
+		}
+	default:
+		t.Fatal("Expected Stop to wait for the manual run")
+	}
+
+	if _, err := scheduler.RunNow(context.Background(), "audit"); err == nil {
+		t.Error("Expected RunNow to fail once the scheduler has stopped")
+	}
+}
+
+func TestScheduler_Add(t *testing.T) {
+	scheduler := New(nil)
+	definition := mustParse(t, "steps:\n  - id: a\n    query: hi\n")
+
+	tests := map[string]Task{
+		"no name":         {Cron: "@daily", Workflow: definition},
+		"no workflow":     {Name: "a", Cron: "@daily"},
+		"bad cron":        {Name: "a", Cron: "every day", Workflow: definition},
+		"negative budget": {Name: "a", Cron: "@daily", Workflow: definition, Budget: Budget{MaxCostUSD: -1}},
+	}
+	for name, task := range tests {
+		var validation *sdkerrors.ValidationError
+		if err := scheduler.Add(task); !errors.As(err, &validation) {
+			t.Errorf("%s: expected a validation error, got %v", name, err)
+		}
+	}
+
+	if err := scheduler.Add(Task{Name: "a", Cron: "@daily", Workflow: definition}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := scheduler.Add(Task{Name: "a", Cron: "@hourly", Workflow: definition}); err == nil {
+		t.Error("Expected a duplicate task to be rejected")
+	}
+	if _, err := scheduler.RunNow(context.Background(), "missing"); err == nil {
+		t.Error("Expected RunNow of an unknown task to fail")
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Conversation runs queries, either statelessly or within a session.
+type Conversation interface {
+	Query(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error)
+	QueryStream(ctx context.Context, request *types.QueryRequest) (types.QueryStream, error)
+}
+
+// Backend is the SDK surface exposed by the server.
+type Backend interface {
+	Conversation
+
+	// CreateSession creates a session and returns its ID. An empty ID generates one.
+	CreateSession(ctx context.Context, sessionID string) (string, error)
+
+	// Session returns an active session.
+	Session(sessionID string) (Conversation, error)
+
+	// ListSessions returns the IDs of active sessions.
+	ListSessions() []string
+
+	// CloseSession closes and removes a session.
+	CloseSession(sessionID string) error
+}
+
+// clientBackend adapts a ClaudeCodeClient to the Backend interface.
+type clientBackend struct {
+	*client.ClaudeCodeClient
+}
+
+// NewClientBackend returns a Backend backed by a ClaudeCodeClient.
+func NewClientBackend(c *client.ClaudeCodeClient) Backend {
+	return clientBackend{ClaudeCodeClient: c}
+}
+
+func (b clientBackend) CreateSession(ctx context.Context, sessionID string) (string, error) {
+	session, err := b.ClaudeCodeClient.CreateSession(ctx, sessionID)
+	if err != nil {
+		return "", err
+	}
+	return session.ID, nil
+}
+
+func (b clientBackend) Session(sessionID string) (Conversation, error) {
+	session, err := b.ClaudeCodeClient.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (b clientBackend) CloseSession(sessionID string) error {
+	return b.ClaudeCodeClient.Sessions().CloseSession(sessionID)
+}
@@ -0,0 +1,35 @@
+/*
+Package server exposes the Claude Code Go SDK as an HTTP service so non-Go
+services can share a centrally managed Claude Code gateway.
+
+Queries and session management are available as JSON endpoints, and streaming
+queries are relayed as Server-Sent Events: each chunk is sent as a "chunk"
+event carrying a types.StreamChunk, followed by a final "done" event, or an
+"error" event if the stream fails. Requests authenticate with an API key in
+the X-API-Key header or as a bearer token.
+
+# Basic Usage
+
+	claudeClient, err := client.NewClaudeCodeClient(ctx, types.NewClaudeCodeConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer claudeClient.Close()
+
+	srv, err := server.New(server.NewClientBackend(claudeClient), server.Config{
+		APIKeys: []string{os.Getenv("GATEWAY_API_KEY")},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Fatal(http.ListenAndServe(":8080", srv))
+
+# Example Requests
+
+	curl -H "X-API-Key: $KEY" -d '{"messages":[{"role":"user","content":"hi"}]}' \
+		http://localhost:8080/v1/query
+
+	curl -N -H "Authorization: Bearer $KEY" -d '{"messages":[{"role":"user","content":"hi"}]}' \
+		http://localhost:8080/v1/query/stream
+*/
+package server
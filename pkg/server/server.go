@@ -0,0 +1,386 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const (
+	// DefaultMaxRequestBytes limits the size of request bodies
+	DefaultMaxRequestBytes = 1 << 20
+
+	// DefaultHeartbeatInterval is how often idle SSE streams send a keep-alive comment
+	DefaultHeartbeatInterval = 15 * time.Second
+
+	// APIKeyHeader is the header carrying the API key. "Authorization: Bearer <key>" is also accepted.
+	APIKeyHeader = "X-API-Key"
+)
+
+// Config configures a Server.
+type Config struct {
+	// APIKeys are the keys accepted from clients. At least one key is required
+	// unless AllowUnauthenticated is set.
+	APIKeys []string
+
+	// AllowUnauthenticated disables API-key checks, for use behind a trusted proxy
+	AllowUnauthenticated bool
+
+	// MaxRequestBytes limits request bodies (defaults to DefaultMaxRequestBytes)
+	MaxRequestBytes int64
+
+	// HeartbeatInterval sets the SSE keep-alive interval (defaults to DefaultHeartbeatInterval)
+	HeartbeatInterval time.Duration
+}
+
+// Server exposes a Backend over HTTP. Streaming endpoints use Server-Sent Events.
+//
+// Routes:
+//
+//	GET    /healthz                      liveness check (no authentication)
+//	POST   /v1/query                     run a query, returns a QueryResponse
+//	POST   /v1/query/stream              run a query, streams StreamChunks over SSE
+//	GET    /v1/sessions                  list session IDs
+//	POST   /v1/sessions                  create a session ({"session_id": "..."} optional)
+//	DELETE /v1/sessions/{id}             close a session
+//	POST   /v1/sessions/{id}/query       run a query within a session
+//	POST   /v1/sessions/{id}/stream      stream a query within a session
+type Server struct {
+	backend Backend
+	config  Config
+}
+
+// New creates a server for backend.
+func New(backend Backend, config Config) (*Server, error) {
+	if backend == nil {
+		return nil, sdkerrors.NewConfigurationError("backend", "backend is required")
+	}
+	if len(config.APIKeys) == 0 && !config.AllowUnauthenticated {
+		return nil, sdkerrors.NewConfigurationError("api_keys", "at least one API key is required unless AllowUnauthenticated is set")
+	}
+	if config.MaxRequestBytes <= 0 {
+		config.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+
+	return &Server{backend: backend, config: config}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/healthz" {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		return
+	}
+
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="claude-code"`)
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "missing or invalid API key")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "v1" {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "unknown endpoint")
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && parts[1] == "query":
+		s.requireMethod(w, r, http.MethodPost, func() { s.handleQuery(w, r, s.backend) })
+	case len(parts) == 3 && parts[1] == "query" && parts[2] == "stream":
+		s.requireMethod(w, r, http.MethodPost, func() { s.handleStream(w, r, s.backend) })
+	case len(parts) == 2 && parts[1] == "sessions":
+		s.handleSessions(w, r)
+	case len(parts) >= 3 && parts[1] == "sessions":
+		s.handleSession(w, r, parts[2], parts[3:])
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "unknown endpoint")
+	}
+}
+
+// authorized checks the request's API key in constant time.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.config.AllowUnauthenticated {
+		return true
+	}
+
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimPrefix(auth, "Bearer ")
+		}
+	}
+	if key == "" {
+		return false
+	}
+
+	authorized := 0
+	for _, candidate := range s.config.APIKeys {
+		authorized |= subtle.ConstantTimeCompare([]byte(key), []byte(candidate))
+	}
+	return authorized == 1
+}
+
+// requireMethod runs handle if the request uses method.
+func (s *Server) requireMethod(w http.ResponseWriter, r *http.Request, method string, handle func()) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+	handle()
+}
+
+// handleSessions lists or creates sessions.
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		sessions := s.backend.ListSessions()
+		sort.Strings(sessions)
+		writeJSON(w, http.StatusOK, map[string][]string{"sessions": sessions})
+
+	case http.MethodPost:
+		var body struct {
+			SessionID string `json:"session_id"`
+		}
+		if r.ContentLength != 0 {
+			if err := s.decode(r, &body); err != nil {
+				writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+				return
+			}
+		}
+
+		sessionID, err := s.backend.CreateSession(r.Context(), body.SessionID)
+		if err != nil {
+			writeSDKError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]string{"session_id": sessionID})
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+	}
+}
+
+// handleSession routes requests for a single session.
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request, sessionID string, rest []string) {
+	if len(rest) == 0 {
+		s.requireMethod(w, r, http.MethodDelete, func() {
+			if err := s.backend.CloseSession(sessionID); err != nil {
+				writeSDKError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+		return
+	}
+
+	if len(rest) != 1 || (rest[0] != "query" && rest[0] != "stream") {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "unknown endpoint")
+		return
+	}
+
+	s.requireMethod(w, r, http.MethodPost, func() {
+		session, err := s.backend.Session(sessionID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "SESSION_NOT_FOUND", err.Error())
+			return
+		}
+
+		if rest[0] == "query" {
+			s.handleQuery(w, r, session)
+		} else {
+			s.handleStream(w, r, session)
+		}
+	})
+}
+
+// handleQuery runs a synchronous query.
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request, conv Conversation) {
+	var request types.QueryRequest
+	if err := s.decode(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	response, err := conv.Query(r.Context(), &request)
+	if err != nil {
+		writeSDKError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleStream runs a streaming query and relays chunks as Server-Sent Events.
+// The query is canceled when the client disconnects.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request, conv Conversation) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "STREAMING_UNSUPPORTED", "response writer does not support streaming")
+		return
+	}
+
+	var request types.QueryRequest
+	if err := s.decode(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	request.Stream = true
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream, err := conv.QueryStream(ctx, &request)
+	if err != nil {
+		writeSDKError(w, err)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	type recvResult struct {
+		chunk *types.StreamChunk
+		err   error
+	}
+	results := make(chan recvResult)
+	go func() {
+		defer close(results)
+		for {
+			chunk, err := stream.Recv()
+			select {
+			case results <- recvResult{chunk: chunk, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil || chunk == nil || chunk.Done {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(s.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case result, ok := <-results:
+			if !ok {
+				return
+			}
+			switch {
+			case result.err == io.EOF || (result.err == nil && (result.chunk == nil || result.chunk.Done)):
+				writeEvent(w, "done", map[string]bool{"done": true})
+				flusher.Flush()
+				return
+			case result.err != nil:
+				writeEvent(w, "error", errorBody(result.err))
+				flusher.Flush()
+				return
+			default:
+				writeEvent(w, "chunk", result.chunk)
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// decode reads a JSON request body within the configured size limit.
+func (s *Server) decode(r *http.Request, v any) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, s.config.MaxRequestBytes))
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
+
+// errorResponse is the JSON body of error responses and SSE error events.
+type errorResponse struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code     string `json:"code"`
+	Category string `json:"category,omitempty"`
+	Message  string `json:"message"`
+}
+
+// errorBody converts an error to an error response.
+func errorBody(err error) errorResponse {
+	detail := errorDetail{Code: "INTERNAL", Message: err.Error()}
+
+	var sdkErr sdkerrors.SDKError
+	if errors.As(err, &sdkErr) {
+		detail.Code = sdkErr.Code()
+		detail.Category = string(sdkErr.Category())
+	}
+	return errorResponse{Error: detail}
+}
+
+// writeSDKError writes an error response with a status derived from the error.
+func writeSDKError(w http.ResponseWriter, err error) {
+	status := sdkerrors.GetHTTPStatusCode(err)
+	if status == 0 {
+		switch sdkerrors.GetCategory(err) {
+		case sdkerrors.CategoryValidation:
+			status = http.StatusBadRequest
+		case sdkerrors.CategoryAuth:
+			status = http.StatusUnauthorized
+		case sdkerrors.CategoryNetwork, sdkerrors.CategoryAPI:
+			status = http.StatusBadGateway
+		default:
+			status = http.StatusInternalServerError
+		}
+	}
+	writeJSON(w, status, errorBody(err))
+}
+
+// writeError writes an error response with an explicit code.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorResponse{Error: errorDetail{Code: code, Message: message}})
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) // Ignore error, the client may have disconnected
+}
+
+// writeEvent writes a Server-Sent Event with a JSON payload.
+func writeEvent(w io.Writer, event string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(errorBody(err))
+		event = "error"
+	}
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data) // Ignore error, the client may have disconnected
+}
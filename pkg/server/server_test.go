@@ -0,0 +1,271 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeStream returns the configured chunks and then a done chunk.
+type fakeStream struct {
+	chunks []string
+}
+
+func (s *fakeStream) Recv() (*types.StreamChunk, error) {
+	if len(s.chunks) == 0 {
+		return &types.StreamChunk{Done: true}, nil
+	}
+	content := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return &types.StreamChunk{Type: types.ChunkTypeContent, Content: content}, nil
+}
+
+func (s *fakeStream) Close() error { return nil }
+
+// fakeConversation echoes the last prompt, prefixed with its name.
+type fakeConversation struct {
+	name string
+}
+
+func (c fakeConversation) Query(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	if len(request.Messages) == 0 {
+		return nil, sdkerrors.NewValidationError("messages", "", "non-empty", "messages are required")
+	}
+	prompt := request.Messages[len(request.Messages)-1].Content
+	return &types.QueryResponse{
+		Model:   request.Model,
+		Content: []types.ContentBlock{{Type: "text", Text: c.name + ": " + prompt}},
+	}, nil
+}
+
+func (c fakeConversation) QueryStream(ctx context.Context, request *types.QueryRequest) (types.QueryStream, error) {
+	return &fakeStream{chunks: []string{c.name + " one\n", c.name + " two\n"}}, nil
+}
+
+// fakeBackend keeps sessions in memory.
+type fakeBackend struct {
+	fakeConversation
+	sessions map[string]bool
+	mu       sync.Mutex
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{fakeConversation: fakeConversation{name: "client"}, sessions: make(map[string]bool)}
+}
+
+func (b *fakeBackend) CreateSession(ctx context.Context, sessionID string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sessionID == "" {
+		sessionID = "generated"
+	}
+	b.sessions[sessionID] = true
+	return sessionID, nil
+}
+
+func (b *fakeBackend) Session(sessionID string) (Conversation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.sessions[sessionID] {
+		return nil, sdkerrors.NewValidationError("sessionID", sessionID, "existing session", "session not found")
+	}
+	return fakeConversation{name: sessionID}, nil
+}
+
+func (b *fakeBackend) ListSessions() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ids := make([]string, 0, len(b.sessions))
+	for id := range b.sessions {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (b *fakeBackend) CloseSession(sessionID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sessions, sessionID)
+	return nil
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv, err := New(newFakeBackend(), Config{APIKeys: []string{"secret"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func doRequest(t *testing.T, method, url, body string, headers map[string]string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	req.Header.Set("X-API-Key", "secret")
+	for key, value := range headers {
+		if value == "" {
+			req.Header.Del(key)
+		} else {
+			req.Header.Set(key, value)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+const queryBody = `{"model":"claude-sonnet-4","messages":[{"role":"user","content":"hello"}]}`
+
+func TestNew_RequiresAPIKeys(t *testing.T) {
+	if _, err := New(newFakeBackend(), Config{}); err == nil {
+		t.Error("Expected error without API keys")
+	}
+	if _, err := New(newFakeBackend(), Config{AllowUnauthenticated: true}); err != nil {
+		t.Errorf("Expected unauthenticated server to be allowed: %v", err)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	ts := newTestServer(t)
+
+	if resp := doRequest(t, http.MethodPost, ts.URL+"/v1/query", queryBody, map[string]string{"X-API-Key": ""}); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without key, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, http.MethodPost, ts.URL+"/v1/query", queryBody, map[string]string{"X-API-Key": "wrong"}); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with wrong key, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, http.MethodPost, ts.URL+"/v1/query", queryBody, map[string]string{"X-API-Key": "", "Authorization": "Bearer secret"}); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with bearer token, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, http.MethodGet, ts.URL+"/healthz", "", map[string]string{"X-API-Key": ""}); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected health check without auth, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_Query(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/query", queryBody, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+
+	var response types.QueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Content[0].Text != "client: hello" || response.Model != "claude-sonnet-4" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	// SDK validation errors map to 400
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/query", `{"messages":[]}`, nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", resp.StatusCode)
+	}
+	var errResp errorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error.Category != "validation" {
+		t.Errorf("Unexpected error body: %+v (%v)", errResp, err)
+	}
+
+	if resp := doRequest(t, http.MethodGet, ts.URL+"/v1/query", "", nil); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, http.MethodPost, ts.URL+"/v1/query", "{", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for malformed JSON, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_Stream(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/query/stream", queryBody, nil)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Unexpected response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	events := readEvents(t, resp.Body)
+	if len(events) != 3 || events[0][0] != "chunk" || events[2][0] != "done" {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+
+	var chunk types.StreamChunk
+	if err := json.Unmarshal([]byte(events[1][1]), &chunk); err != nil || chunk.Content != "client two\n" {
+		t.Errorf("Unexpected chunk: %+v (%v)", chunk, err)
+	}
+}
+
+func TestServer_Sessions(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/sessions", `{"session_id":"abc"}`, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/v1/sessions", "", nil)
+	var list map[string][]string
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil || len(list["sessions"]) != 1 || list["sessions"][0] != "abc" {
+		t.Errorf("Unexpected session list: %v (%v)", list, err)
+	}
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/sessions/abc/query", queryBody, nil)
+	var response types.QueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil || response.Content[0].Text != "abc: hello" {
+		t.Errorf("Unexpected session response: %+v (%v)", response, err)
+	}
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/sessions/abc/stream", queryBody, nil)
+	if events := readEvents(t, resp.Body); len(events) != 3 || !strings.Contains(events[0][1], "abc one") {
+		t.Errorf("Unexpected session events: %v", events)
+	}
+
+	if resp := doRequest(t, http.MethodDelete, ts.URL+"/v1/sessions/abc", "", nil); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, http.MethodPost, ts.URL+"/v1/sessions/abc/query", queryBody, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for closed session, got %d", resp.StatusCode)
+	}
+}
+
+// readEvents parses an SSE body into (event, data) pairs.
+func readEvents(t *testing.T, body io.Reader) [][2]string {
+	t.Helper()
+
+	var events [][2]string
+	var event [2]string
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event[0] = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event[1] = strings.TrimPrefix(line, "data: ")
+		case line == "" && event[0] != "":
+			events = append(events, event)
+			event = [2]string{}
+		}
+	}
+	return events
+}
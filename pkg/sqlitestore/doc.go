@@ -0,0 +1,36 @@
+/*
+Package sqlitestore keeps Claude Code job checkpoints and transcripts in a
+SQLite database, giving small deployments durable session history without
+external infrastructure.
+
+It is a separate module so that the SDK itself does not depend on a SQLite
+driver. The store uses modernc.org/sqlite, a pure-Go driver, so no cgo
+toolchain is needed.
+
+# Basic Usage
+
+	store, err := sqlitestore.Open(ctx, "/var/lib/myapp/sessions.db")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+
+	claudeClient.SetSessionStore(store)
+	response, err := claudeClient.RunJob(ctx, "nightly-report", request)
+
+	// Later, from any process sharing the database
+	messages, err := store.Transcript(ctx, "nightly-report")
+
+# Schema Migrations
+
+Open and New bring the database up to the schema of this version of the
+package, recording applied migrations in a schema_migrations table. Each
+migration runs in its own transaction, so an interrupted upgrade is retried
+on the next Open. A database already migrated by a newer version is refused
+rather than modified.
+
+New accepts a database opened by the caller, for example with a different
+driver or connection settings; it must enforce foreign keys so that deleting
+a checkpoint deletes its transcript.
+*/
+package sqlitestore
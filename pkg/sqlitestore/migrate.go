@@ -0,0 +1,93 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+)
+
+// migrations are the schema changes applied in order, each in its own
+// transaction. Append new migrations; never edit or reorder released ones,
+// since a database records only how many it has applied.
+var migrations = []string{
+	// 1: checkpoints and their transcripts
+	`CREATE TABLE checkpoints (
+		job_id     TEXT PRIMARY KEY,
+		session_id TEXT NOT NULL DEFAULT '',
+		request    TEXT,
+		turns      INTEGER NOT NULL DEFAULT 0,
+		completed  INTEGER NOT NULL DEFAULT 0,
+		response   TEXT,
+		error      TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX checkpoints_session_id ON checkpoints (session_id);
+	CREATE TABLE messages (
+		job_id  TEXT NOT NULL REFERENCES checkpoints (job_id) ON DELETE CASCADE,
+		seq     INTEGER NOT NULL,
+		message TEXT NOT NULL,
+		PRIMARY KEY (job_id, seq)
+	);`,
+
+	// 2: query metadata of jobs
+	`ALTER TABLE checkpoints ADD COLUMN metadata TEXT;`,
+}
+
+// SchemaVersion is the number of migrations this version of the package
+// applies.
+var SchemaVersion = len(migrations)
+
+// migrate applies the migrations a database has not applied yet. It refuses
+// a database migrated by a newer version of the package.
+func migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, applied_at INTEGER NOT NULL)`); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SQLITE_MIGRATE", "failed to create migrations table")
+	}
+
+	version, err := schemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return sdkerrors.NewConfigurationError("database", fmt.Sprintf("database schema version %d is newer than the supported version %d", version, len(migrations)))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		if err := applyMigration(ctx, db, i+1, migrations[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs one migration and records it in the same transaction.
+func applyMigration(ctx context.Context, db *sql.DB, version int, statements string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SQLITE_MIGRATE", "failed to start migration")
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SQLITE_MIGRATE", fmt.Sprintf("migration %d failed", version))
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, strftime('%s', 'now'))`, version); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SQLITE_MIGRATE", fmt.Sprintf("failed to record migration %d", version))
+	}
+	if err := tx.Commit(); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SQLITE_MIGRATE", fmt.Sprintf("failed to commit migration %d", version))
+	}
+	return nil
+}
+
+// schemaVersion returns the number of migrations applied to a database.
+func schemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "SQLITE_MIGRATE", "failed to read schema version")
+	}
+	return version, nil
+}
@@ -0,0 +1,281 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the pure-Go "sqlite" driver
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// DriverName is the database/sql driver Open uses.
+const DriverName = "sqlite"
+
+// Store keeps job checkpoints and their transcripts in a SQLite database. It
+// implements client.SessionStore and is safe for concurrent use.
+type Store struct {
+	db    *sql.DB
+	owned bool
+}
+
+var _ client.SessionStore = (*Store)(nil)
+
+// Open opens or creates the database file at path and migrates it to the
+// current schema. The database uses write-ahead logging so that readers do
+// not block the writer.
+func Open(ctx context.Context, path string) (*Store, error) {
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+	db, err := sql.Open(DriverName, dsn)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryConfiguration, "SQLITE_OPEN", "failed to open session database")
+	}
+
+	store, err := New(ctx, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	store.owned = true
+	return store, nil
+}
+
+// New creates a store in an open database and migrates it to the current
+// schema. The caller keeps ownership of db; Close does not close it. The
+// database must enforce foreign keys for deletes to remove transcripts.
+func New(ctx context.Context, db *sql.DB) (*Store, error) {
+	if db == nil {
+		return nil, sdkerrors.NewValidationError("db", "", "required", "database cannot be nil")
+	}
+	if err := migrate(ctx, db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// DB returns the underlying database.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Close closes the database if the store opened it.
+func (s *Store) Close() error {
+	if !s.owned {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// SaveCheckpoint implements client.SessionStore. The checkpoint and its
+// transcript are replaced in one transaction.
+func (s *Store) SaveCheckpoint(ctx context.Context, checkpoint *client.JobCheckpoint) error {
+	if checkpoint.JobID == "" {
+		return sdkerrors.NewValidationError("jobID", "", "required", "job ID cannot be empty")
+	}
+
+	request, err := encodeJSON(checkpoint.Request)
+	if err != nil {
+		return err
+	}
+	response, err := encodeJSON(checkpoint.Response)
+	if err != nil {
+		return err
+	}
+	var metadata sql.NullString
+	if len(checkpoint.Metadata) > 0 {
+		if metadata, err = encodeJSON(&checkpoint.Metadata); err != nil {
+			return err
+		}
+	}
+	messages := make([][]byte, len(checkpoint.Messages))
+	for i := range checkpoint.Messages {
+		if messages[i], err = json.Marshal(&checkpoint.Messages[i]); err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_ENCODE", "failed to encode checkpoint")
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_SAVE", "failed to save checkpoint")
+	}
+	defer tx.Rollback() // No-op after Commit
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO checkpoints (job_id, session_id, request, turns, completed, response, error, metadata, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (job_id) DO UPDATE SET
+			session_id = excluded.session_id, request = excluded.request, turns = excluded.turns,
+			completed = excluded.completed, response = excluded.response, error = excluded.error,
+			metadata = excluded.metadata, created_at = excluded.created_at, updated_at = excluded.updated_at`,
+		checkpoint.JobID, checkpoint.SessionID, request, checkpoint.Turns, checkpoint.Completed,
+		response, checkpoint.Error, metadata, unixNano(checkpoint.CreatedAt), unixNano(checkpoint.UpdatedAt))
+	if err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_SAVE", "failed to save checkpoint")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM messages WHERE job_id = ?`, checkpoint.JobID); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_SAVE", "failed to save transcript")
+	}
+	for seq, message := range messages {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO messages (job_id, seq, message) VALUES (?, ?, ?)`, checkpoint.JobID, seq, string(message)); err != nil {
+			return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_SAVE", "failed to save transcript")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_SAVE", "failed to save checkpoint")
+	}
+	return nil
+}
+
+// LoadCheckpoint implements client.SessionStore.
+func (s *Store) LoadCheckpoint(ctx context.Context, jobID string) (*client.JobCheckpoint, error) {
+	row := s.db.QueryRowContext(ctx, selectCheckpoints+` WHERE job_id = ?`, jobID)
+	checkpoint, err := scanCheckpoint(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, client.ErrCheckpointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if checkpoint.Messages, err = s.Transcript(ctx, jobID); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// ListCheckpoints implements client.SessionStore. Checkpoints are listed
+// without their messages; use LoadCheckpoint or Transcript for those.
+func (s *Store) ListCheckpoints(ctx context.Context) ([]*client.JobCheckpoint, error) {
+	rows, err := s.db.QueryContext(ctx, selectCheckpoints+` ORDER BY created_at, job_id`)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_LIST", "failed to list checkpoints")
+	}
+	defer rows.Close()
+
+	var checkpoints []*client.JobCheckpoint
+	for rows.Next() {
+		checkpoint, err := scanCheckpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_LIST", "failed to list checkpoints")
+	}
+	return checkpoints, nil
+}
+
+// DeleteCheckpoint implements client.SessionStore, deleting the transcript
+// with it.
+func (s *Store) DeleteCheckpoint(ctx context.Context, jobID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM checkpoints WHERE job_id = ?`, jobID); err != nil {
+		return sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_DELETE", "failed to delete checkpoint")
+	}
+	return nil
+}
+
+// Transcript returns the messages saved with a job's checkpoint, in order.
+func (s *Store) Transcript(ctx context.Context, jobID string) ([]types.Message, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT message FROM messages WHERE job_id = ? ORDER BY seq`, jobID)
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_READ", "failed to read transcript")
+	}
+	defer rows.Close()
+
+	var messages []types.Message
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_READ", "failed to read transcript")
+		}
+		var message types.Message
+		if err := json.Unmarshal([]byte(data), &message); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_DECODE", "failed to decode transcript of "+jobID)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_READ", "failed to read transcript")
+	}
+	return messages, nil
+}
+
+// selectCheckpoints selects the columns scanned by scanCheckpoint.
+const selectCheckpoints = `SELECT job_id, session_id, request, turns, completed, response, error, metadata, created_at, updated_at FROM checkpoints`
+
+// scanner is a *sql.Row or *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCheckpoint reads a checkpoint without its messages.
+func scanCheckpoint(row scanner) (*client.JobCheckpoint, error) {
+	var (
+		checkpoint                  client.JobCheckpoint
+		request, response, metadata sql.NullString
+		createdAt, updatedAt        int64
+	)
+	err := row.Scan(&checkpoint.JobID, &checkpoint.SessionID, &request, &checkpoint.Turns, &checkpoint.Completed,
+		&response, &checkpoint.Error, &metadata, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_READ", "failed to read checkpoint")
+	}
+
+	if request.Valid {
+		if err := json.Unmarshal([]byte(request.String), &checkpoint.Request); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_DECODE", "failed to decode checkpoint "+checkpoint.JobID)
+		}
+	}
+	if response.Valid {
+		if err := json.Unmarshal([]byte(response.String), &checkpoint.Response); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_DECODE", "failed to decode checkpoint "+checkpoint.JobID)
+		}
+	}
+	if metadata.Valid {
+		if err := json.Unmarshal([]byte(metadata.String), &checkpoint.Metadata); err != nil {
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_DECODE", "failed to decode checkpoint "+checkpoint.JobID)
+		}
+	}
+	checkpoint.CreatedAt = fromUnixNano(createdAt)
+	checkpoint.UpdatedAt = fromUnixNano(updatedAt)
+	return &checkpoint, nil
+}
+
+// encodeJSON encodes a JSON column, storing nil as NULL.
+func encodeJSON[T any](value *T) (sql.NullString, error) {
+	if value == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return sql.NullString{}, sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "CHECKPOINT_ENCODE", "failed to encode checkpoint")
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// unixNano stores times as integers so that they sort correctly; the zero
+// time is stored as 0.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromUnixNano reverses unixNano.
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
@@ -0,0 +1,144 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func openTestStore(t *testing.T, path string) *Store {
+	t.Helper()
+	store, err := Open(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store := openTestStore(t, path)
+	now := time.Now()
+
+	checkpoint := &client.JobCheckpoint{
+		JobID:     "b",
+		SessionID: "session-b",
+		Request:   &types.QueryRequest{Messages: []types.Message{{Role: types.RoleUser, Content: "fix the build"}}},
+		Turns:     1,
+		Messages: []types.Message{
+			{Role: types.RoleUser, Content: "fix the build"},
+			{Role: types.RoleAssistant, Content: "Looking at the failure"},
+		},
+		Metadata:  map[string]string{"feature": "ci-fix"},
+		CreatedAt: now.Add(time.Second),
+		UpdatedAt: now.Add(time.Second),
+	}
+	first := &client.JobCheckpoint{JobID: "a", Completed: true, Response: &types.QueryResponse{ID: "msg_1"}, CreatedAt: now}
+	for _, c := range []*client.JobCheckpoint{checkpoint, first} {
+		if err := store.SaveCheckpoint(ctx, c); err != nil {
+			t.Fatalf("SaveCheckpoint failed: %v", err)
+		}
+	}
+
+	// Saving again replaces the checkpoint and its transcript
+	checkpoint.Turns = 2
+	checkpoint.Messages = append(checkpoint.Messages, types.Message{Role: types.RoleAssistant, Content: "Fixed"})
+	if err := store.SaveCheckpoint(ctx, checkpoint); err != nil {
+		t.Fatalf("SaveCheckpoint failed: %v", err)
+	}
+
+	loaded, err := store.LoadCheckpoint(ctx, "b")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	if loaded.SessionID != "session-b" || loaded.Turns != 2 || len(loaded.Messages) != 3 || loaded.Messages[2].Content != "Fixed" ||
+		loaded.Request.Messages[0].Content != "fix the build" || !loaded.CreatedAt.Equal(checkpoint.CreatedAt) ||
+		loaded.Metadata["feature"] != "ci-fix" {
+		t.Errorf("Unexpected checkpoint: %+v", loaded)
+	}
+
+	list, err := store.ListCheckpoints(ctx)
+	if err != nil || len(list) != 2 || list[0].JobID != "a" || list[1].JobID != "b" || list[0].Response.ID != "msg_1" || list[0].Metadata != nil {
+		t.Errorf("Unexpected list: %v %v", list, err)
+	}
+
+	if err := store.DeleteCheckpoint(ctx, "b"); err != nil {
+		t.Fatalf("DeleteCheckpoint failed: %v", err)
+	}
+	if _, err := store.LoadCheckpoint(ctx, "b"); !errors.Is(err, client.ErrCheckpointNotFound) {
+		t.Errorf("Expected ErrCheckpointNotFound, got %v", err)
+	}
+	if messages, err := store.Transcript(ctx, "b"); err != nil || len(messages) != 0 {
+		t.Errorf("Expected the transcript deleted with the checkpoint, got %v %v", messages, err)
+	}
+	if err := store.DeleteCheckpoint(ctx, "b"); err != nil {
+		t.Errorf("Deleting a missing checkpoint should succeed: %v", err)
+	}
+
+	if err := store.SaveCheckpoint(ctx, &client.JobCheckpoint{}); err == nil {
+		t.Error("Expected a checkpoint without a job ID to be rejected")
+	}
+
+	// Checkpoints survive reopening the database
+	store.Close()
+	reopened := openTestStore(t, path)
+	if _, err := reopened.LoadCheckpoint(ctx, "a"); err != nil {
+		t.Errorf("Expected checkpoint to persist: %v", err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open(DriverName, filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	defer db.Close()
+
+	// Migrating twice applies each migration once
+	for i := 0; i < 2; i++ {
+		store, err := New(ctx, db)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		store.Close()
+	}
+	if version, err := schemaVersion(ctx, db); err != nil || version != SchemaVersion {
+		t.Errorf("Expected schema version %d, got %d %v", SchemaVersion, version, err)
+	}
+
+	// Closing a store made with New leaves the database open
+	if err := db.PingContext(ctx); err != nil {
+		t.Errorf("Expected the caller's database to stay open: %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, 0)`, SchemaVersion+1); err != nil {
+		t.Fatalf("Failed to record future migration: %v", err)
+	}
+	if _, err := New(ctx, db); err == nil {
+		t.Error("Expected a database from a newer version to be refused")
+	}
+}
+
+func TestRunJob(t *testing.T) {
+	store := openTestStore(t, filepath.Join(t.TempDir(), "sessions.db"))
+
+	claude, err := client.NewClaudeCodeClient(context.Background(), &types.ClaudeCodeConfig{TestMode: true, WorkingDirectory: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer claude.Close()
+
+	claude.SetSessionStore(store)
+	if _, err := claude.ResumeJob(context.Background(), "missing"); !errors.Is(err, client.ErrCheckpointNotFound) {
+		t.Errorf("Expected ErrCheckpointNotFound from the SQLite store, got %v", err)
+	}
+}
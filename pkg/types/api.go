@@ -1,9 +1,6 @@
 package types
 
-import (
-	"encoding/json"
-	"time"
-)
+import "time"
 
 // QueryRequest represents a request to the Claude Code API.
 // It contains the message content and configuration for how Claude should respond.
@@ -204,6 +201,11 @@ type QueryResponse struct {
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+// QueryMetadataKey is the Metadata key under which responses, messages and
+// stream chunks carry the map[string]string metadata attached to the query
+// that produced them.
+const QueryMetadataKey = "query_metadata"
+
 // GetTextContent extracts all text content from the response.
 // It concatenates text from all text-type content blocks in the response.
 // Non-text blocks (like tool calls) are ignored.
@@ -256,29 +258,46 @@ func (r *QueryResponse) GetToolCalls() []ToolCall {
 	}
 	for _, block := range r.Content {
 		if block.Type == "tool_use" {
-			// Parse tool call from block data
-			if data, ok := block.Data.(map[string]any); ok {
-				toolCall := ToolCall{
-					Type: "function",
-				}
-				if id, ok := data["id"].(string); ok {
-					toolCall.ID = id
-				}
-				if name, ok := data["name"].(string); ok {
-					toolCall.Function.Name = name
-				}
-				if input, ok := data["input"]; ok {
-					if inputBytes, err := json.Marshal(input); err == nil {
-						toolCall.Function.Arguments = string(inputBytes)
-					}
-				}
-				toolCalls = append(toolCalls, toolCall)
-			}
+			toolCalls = append(toolCalls, ToolCallFromBlock(block))
 		}
 	}
 	return toolCalls
 }
 
+// Text returns the text of the response's text blocks joined together. It
+// returns "" for a nil response.
+func (r *QueryResponse) Text() string {
+	if r == nil {
+		return ""
+	}
+	return ExtractText(r)
+}
+
+// ToolUses returns the tools Claude called in the response, in order.
+func (r *QueryResponse) ToolUses() []ToolUse {
+	if r == nil {
+		return nil
+	}
+	return ExtractToolUses(r)
+}
+
+// HasToolUse reports whether the response calls the named tool, or any tool
+// if name is empty.
+func (r *QueryResponse) HasToolUse(name string) bool {
+	return hasToolUse(r.ToolUses(), name)
+}
+
+// hasToolUse reports whether uses include the named tool, or any tool if
+// name is empty.
+func hasToolUse(uses []ToolUse, name string) bool {
+	for _, use := range uses {
+		if name == "" || use.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // APIError represents an error response from the Claude Code API.
 type APIError struct {
 	// Type is the error type identifier
@@ -483,3 +502,43 @@ type UsageInfo struct {
 	// ResetTime is when the usage counters reset
 	ResetTime *time.Time `json:"reset_time,omitempty"`
 }
+
+// TokenCount is the result of counting the input tokens of a request.
+type TokenCount struct {
+	// Model is the model the tokens were counted for
+	Model string `json:"model"`
+
+	// InputTokens is the number of input tokens the request would consume
+	InputTokens int `json:"input_tokens"`
+
+	// Estimated is true when the count comes from the local estimator rather
+	// than the API's count-tokens endpoint
+	Estimated bool `json:"estimated"`
+}
+
+// CostEstimate is the estimated cost range of a request before it runs.
+type CostEstimate struct {
+	// Model is the model the estimate was computed for
+	Model string `json:"model"`
+
+	// MinInputTokens is the lower bound of the input token count
+	MinInputTokens int `json:"min_input_tokens"`
+
+	// MaxInputTokens is the upper bound of the input token count
+	MaxInputTokens int `json:"max_input_tokens"`
+
+	// MaxOutputTokens is the maximum number of output tokens the request allows
+	MaxOutputTokens int `json:"max_output_tokens"`
+
+	// MinCost is the cost if the model produces no output
+	MinCost float64 `json:"min_cost"`
+
+	// MaxCost is the cost if the model uses the full output allowance
+	MaxCost float64 `json:"max_cost"`
+
+	// Currency is the currency of the costs (e.g., "USD")
+	Currency string `json:"currency"`
+
+	// Estimated is true when the input token count was estimated locally
+	Estimated bool `json:"estimated"`
+}
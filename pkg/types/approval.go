@@ -0,0 +1,93 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultApprovalTools is the PreToolUse matcher of tools that need approval
+// when an ApprovalProvider is configured: the tools that change files or run
+// commands.
+const DefaultApprovalTools = "Edit|MultiEdit|Write|NotebookEdit|Bash"
+
+// Kinds of approval requests.
+const (
+	// ApprovalKindTool asks to let Claude run a tool
+	ApprovalKindTool = "tool"
+
+	// ApprovalKindWorkflow asks to continue a workflow past an approval step
+	ApprovalKindWorkflow = "workflow"
+)
+
+// ApprovalRequest describes an action waiting for a human decision.
+type ApprovalRequest struct {
+	// Kind is ApprovalKindTool or ApprovalKindWorkflow
+	Kind string `json:"kind"`
+
+	// ToolName and ToolInput describe the tool call of tool requests
+	ToolName  string         `json:"tool_name,omitempty"`
+	ToolInput map[string]any `json:"tool_input,omitempty"`
+
+	// SessionID and WorkingDir identify where the tool call happens
+	SessionID  string `json:"session_id,omitempty"`
+	WorkingDir string `json:"working_dir,omitempty"`
+
+	// StepID names the workflow step of workflow requests
+	StepID string `json:"step_id,omitempty"`
+
+	// Message is a human-readable description of the request
+	Message string `json:"message,omitempty"`
+}
+
+// Summary returns Message, or a one-line description of the tool call.
+func (r ApprovalRequest) Summary() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	if r.ToolName == "" {
+		return "Approve " + r.StepID
+	}
+
+	for _, key := range []string{"command", "file_path", "notebook_path", "url"} {
+		if value, ok := r.ToolInput[key].(string); ok && value != "" {
+			return fmt.Sprintf("%s: %s", r.ToolName, value)
+		}
+	}
+
+	keys := make([]string, 0, len(r.ToolInput))
+	for key := range r.ToolInput {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value, _ := json.Marshal(r.ToolInput[key])
+		parts = append(parts, key+"="+string(value))
+	}
+	return fmt.Sprintf("%s(%s)", r.ToolName, strings.Join(parts, ", "))
+}
+
+// ApprovalDecision is a human's answer to an ApprovalRequest.
+type ApprovalDecision struct {
+	Approved bool `json:"approved"`
+
+	// Reason is shown to Claude when a tool call is denied
+	Reason string `json:"reason,omitempty"`
+}
+
+// ApprovalProvider asks a human to approve an action. An error denies the
+// action. See package approval for terminal, HTTP and Slack implementations.
+type ApprovalProvider interface {
+	Approve(ctx context.Context, request ApprovalRequest) (ApprovalDecision, error)
+}
+
+// ApprovalFunc adapts a function to the ApprovalProvider interface.
+type ApprovalFunc func(ctx context.Context, request ApprovalRequest) (ApprovalDecision, error)
+
+// Approve calls f(ctx, request).
+func (f ApprovalFunc) Approve(ctx context.Context, request ApprovalRequest) (ApprovalDecision, error) {
+	return f(ctx, request)
+}
@@ -0,0 +1,179 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxImageAttachmentSize is the largest image Claude accepts, in bytes.
+const MaxImageAttachmentSize = 5 * 1024 * 1024
+
+// MaxFileAttachmentSize is the largest text file sent as an attachment, in bytes.
+const MaxFileAttachmentSize = 1024 * 1024
+
+// Content source types for image and document blocks.
+const (
+	ContentSourceBase64 = "base64"
+	ContentSourceURL    = "url"
+	ContentSourceText   = "text"
+)
+
+// ContentSource holds the data of an image or document content block, either
+// inline as base64 or plain text, or as a URL Claude fetches.
+type ContentSource struct {
+	// Type is ContentSourceBase64, ContentSourceText or ContentSourceURL
+	Type string `json:"type"`
+
+	// MediaType is the MIME type of base64 data, e.g. "image/png"
+	MediaType string `json:"media_type,omitempty"`
+
+	// Data is the base64-encoded content, or the text of text sources
+	Data string `json:"data,omitempty"`
+
+	// URL is the location of the content
+	URL string `json:"url,omitempty"`
+}
+
+// supportedImageTypes are the image formats Claude accepts.
+var supportedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// NewImageBlock creates an image content block from raw image data.
+func NewImageBlock(mediaType string, data []byte) ContentBlock {
+	return ContentBlock{
+		Type: "image",
+		Source: &ContentSource{
+			Type:      ContentSourceBase64,
+			MediaType: mediaType,
+			Data:      base64.StdEncoding.EncodeToString(data),
+		},
+	}
+}
+
+// NewImageAttachment attaches the image at path, read when the message is sent.
+func NewImageAttachment(path string) Attachment {
+	return Attachment{Type: AttachmentTypeImage, Name: filepath.Base(path), Path: path}
+}
+
+// NewFileAttachment attaches the text file at path, read when the message is sent.
+func NewFileAttachment(path string) Attachment {
+	return Attachment{Type: AttachmentTypeFile, Name: filepath.Base(path), Path: path}
+}
+
+// HasAttachments reports whether any of the messages carries attachments.
+func HasAttachments(messages []Message) bool {
+	for _, msg := range messages {
+		if len(msg.Attachments) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentBlock converts the attachment to the content block sent to Claude.
+// Images become image blocks and documents document blocks; files and code
+// become text blocks naming the file. Data is used when set, otherwise the
+// file at Path is read, relative to baseDir. Images may also be given by URL.
+// Documents too long for one block need ContentBlocks.
+func (a *Attachment) ContentBlock(baseDir string) (ContentBlock, error) {
+	if a.Type == AttachmentTypeImage && len(a.Data) == 0 && a.Path == "" && a.URL != "" {
+		return ContentBlock{Type: "image", Source: &ContentSource{Type: ContentSourceURL, URL: a.URL}}, nil
+	}
+
+	if a.Type == AttachmentTypeDocument {
+		blocks, err := a.ContentBlocks(baseDir)
+		if err != nil {
+			return ContentBlock{}, err
+		}
+		if len(blocks) > 1 {
+			return ContentBlock{}, a.invalid(fmt.Sprintf("document needs %d blocks, use ContentBlocks", len(blocks)))
+		}
+		return blocks[0], nil
+	}
+
+	data, err := a.load(baseDir)
+	if err != nil {
+		return ContentBlock{}, err
+	}
+
+	switch a.Type {
+	case AttachmentTypeImage:
+		if len(data) > MaxImageAttachmentSize {
+			return ContentBlock{}, a.invalid(fmt.Sprintf("image is %d bytes, the limit is %d", len(data), MaxImageAttachmentSize))
+		}
+		mediaType := a.mediaType(data)
+		if !supportedImageTypes[mediaType] {
+			return ContentBlock{}, a.invalid("unsupported image type " + mediaType)
+		}
+		return NewImageBlock(mediaType, data), nil
+
+	case AttachmentTypeFile, AttachmentTypeCode:
+		if len(data) > MaxFileAttachmentSize {
+			return ContentBlock{}, a.invalid(fmt.Sprintf("file is %d bytes, the limit is %d", len(data), MaxFileAttachmentSize))
+		}
+		if !utf8.Valid(data) {
+			return ContentBlock{}, a.invalid("file is not UTF-8 text")
+		}
+		name := a.Name
+		if name == "" {
+			name = filepath.Base(a.Path)
+		}
+		return NewTextBlock(fmt.Sprintf("<attachment name=%q>\n%s\n</attachment>", name, strings.TrimRight(string(data), "\n"))), nil
+	}
+
+	return ContentBlock{}, a.invalid(fmt.Sprintf("unsupported attachment type %q", a.Type))
+}
+
+// load returns the attachment data, reading Path when Data is empty.
+func (a *Attachment) load(baseDir string) ([]byte, error) {
+	if len(a.Data) > 0 {
+		return a.Data, nil
+	}
+	if a.Path == "" {
+		return nil, a.invalid("attachment needs data or a path")
+	}
+
+	path := a.Path
+	if !filepath.IsAbs(path) && baseDir != "" {
+		path = filepath.Join(baseDir, path)
+	}
+	data, err := os.ReadFile(path) // #nosec G304 - attachments are chosen by the caller
+	if err != nil {
+		return nil, a.invalid(err.Error())
+	}
+	return data, nil
+}
+
+// mediaType returns MimeType, or the type implied by the name or content.
+func (a *Attachment) mediaType(data []byte) string {
+	if a.MimeType != "" {
+		return a.MimeType
+	}
+	name := a.Name
+	if name == "" {
+		name = a.Path
+	}
+	if mediaType := mime.TypeByExtension(strings.ToLower(filepath.Ext(name))); mediaType != "" {
+		return strings.SplitN(mediaType, ";", 2)[0]
+	}
+	return strings.SplitN(http.DetectContentType(data), ";", 2)[0]
+}
+
+// invalid returns a validation error for the attachment.
+func (a *Attachment) invalid(message string) error {
+	value := a.Name
+	if value == "" {
+		value = a.Path
+	}
+	return &ValidationError{Field: "attachments", Message: message, Value: value}
+}
@@ -0,0 +1,129 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ContentBlockBinary is the type of content blocks carrying binary data that
+// is not an image, such as compressed command output. Like image blocks,
+// they hold the data base64 encoded in Source along with its media type.
+const ContentBlockBinary = "binary"
+
+// NewBinaryBlock creates a content block holding raw data. Image types
+// Claude accepts produce image blocks. An empty media type is detected from
+// the data.
+func NewBinaryBlock(mediaType string, data []byte) ContentBlock {
+	if mediaType == "" {
+		mediaType = strings.SplitN(http.DetectContentType(data), ";", 2)[0]
+	}
+	if supportedImageTypes[mediaType] {
+		return NewImageBlock(mediaType, data)
+	}
+	return ContentBlock{
+		Type: ContentBlockBinary,
+		Source: &ContentSource{
+			Type:      ContentSourceBase64,
+			MediaType: mediaType,
+			Data:      base64.StdEncoding.EncodeToString(data),
+		},
+	}
+}
+
+// IsBinary reports whether the block carries inline base64 data, as image
+// and binary blocks do.
+func (b *ContentBlock) IsBinary() bool {
+	return b.Source != nil && b.Source.Type == ContentSourceBase64
+}
+
+// MediaType returns the media type of the block's inline data, or "" if it
+// has none.
+func (b *ContentBlock) MediaType() string {
+	if !b.IsBinary() {
+		return ""
+	}
+	return b.Source.MediaType
+}
+
+// Bytes decodes the block's inline data.
+func (b *ContentBlock) Bytes() ([]byte, error) {
+	if !b.IsBinary() {
+		return nil, &ValidationError{Field: "source", Message: "content block has no inline data", Value: b.Type}
+	}
+	data, err := base64.StdEncoding.DecodeString(b.Source.Data)
+	if err != nil {
+		return nil, &ValidationError{Field: "source.data", Message: "invalid base64 data: " + err.Error(), Value: b.Type}
+	}
+	return data, nil
+}
+
+// Attachment converts a block with inline data to an attachment holding the
+// decoded bytes and their media type, for messages whose content is text.
+func (b *ContentBlock) Attachment() (Attachment, error) {
+	data, err := b.Bytes()
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	attachmentType := AttachmentTypeFile
+	switch {
+	case b.Type == "image" || strings.HasPrefix(b.Source.MediaType, "image/"):
+		attachmentType = AttachmentTypeImage
+	case b.Type == "document":
+		attachmentType = AttachmentTypeDocument
+	}
+	return Attachment{
+		Type:     attachmentType,
+		Data:     data,
+		MimeType: b.Source.MediaType,
+		Size:     int64(len(data)),
+	}, nil
+}
+
+// ParseToolResultContent decodes the content of a tool result, which the CLI
+// writes either as a string or as a list of content blocks. Image and binary
+// blocks keep their base64 data and media type.
+func ParseToolResultContent(raw json.RawMessage) ([]ContentBlock, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	if raw[0] == '"' {
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, err
+		}
+		return []ContentBlock{NewTextBlock(text)}, nil
+	}
+
+	var blocks []ContentBlock
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting tool result content
+// written as a string as well as a list of blocks.
+func (b *ContentBlock) UnmarshalJSON(data []byte) error {
+	type contentBlock ContentBlock
+	var raw struct {
+		contentBlock
+		Content json.RawMessage `json:"content,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	content, err := ParseToolResultContent(raw.Content)
+	if err != nil {
+		return err
+	}
+	*b = ContentBlock(raw.contentBlock)
+	b.Content = content
+	return nil
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// CertificateTrust controls which certificates the CLI trusts for TLS
+// connections, such as those to a gateway or through a TLS-inspecting proxy.
+//
+// Example usage:
+//
+//	config.CertificateTrust = &types.CertificateTrust{
+//		CAFile: "/etc/ssl/corp-root.pem",
+//	}
+type CertificateTrust struct {
+	// CAFile is a PEM bundle of certificates trusted in addition to the
+	// CLI's built-in roots (NODE_EXTRA_CA_CERTS)
+	CAFile string `json:"ca_file,omitempty"`
+
+	// PinnedCertsFile is a PEM bundle of the only certificates the CLI
+	// trusts: its built-in roots are replaced by them, so connections to
+	// servers whose chain does not lead to a pinned certificate fail
+	PinnedCertsFile string `json:"pinned_certs_file,omitempty"`
+}
+
+// Validate checks that the configured files exist and hold PEM
+// certificates, and that CAFile and PinnedCertsFile are not both set. A nil
+// config is valid.
+func (t *CertificateTrust) Validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.CAFile != "" && t.PinnedCertsFile != "" {
+		return &ValidationError{Field: "certificate_trust", Message: "ca_file would widen the trust pinned_certs_file restricts; set only one"}
+	}
+	if t.CAFile != "" {
+		if err := validateCertificateFile("certificate_trust.ca_file", t.CAFile); err != nil {
+			return err
+		}
+	}
+	if t.PinnedCertsFile != "" {
+		if err := validateCertificateFile("certificate_trust.pinned_certs_file", t.PinnedCertsFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Env returns the variables that make the CLI trust the configured
+// certificates. Pinning uses OpenSSL's store, loaded from SSL_CERT_FILE,
+// in place of the built-in roots, and clears inherited variables that would
+// add certificates to it. A nil config returns nil.
+func (t *CertificateTrust) Env() []string {
+	switch {
+	case t == nil:
+		return nil
+	case t.PinnedCertsFile != "":
+		return []string{
+			"NODE_OPTIONS=--use-openssl-ca",
+			"SSL_CERT_FILE=" + t.PinnedCertsFile,
+			"SSL_CERT_DIR=",
+			"NODE_EXTRA_CA_CERTS=",
+		}
+	case t.CAFile != "":
+		return []string{"NODE_EXTRA_CA_CERTS=" + t.CAFile}
+	}
+	return nil
+}
+
+// LoadCertificates reads the certificates in a PEM file. It fails if the
+// file holds no certificates or one does not parse.
+func LoadCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - the path comes from the client's configuration
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificates in %s", path)
+	}
+	return certs, nil
+}
+
+// validateCertificateFile reports a ValidationError for field if path does
+// not hold PEM certificates.
+func validateCertificateFile(field, path string) error {
+	if _, err := LoadCertificates(path); err != nil {
+		return &ValidationError{Field: field, Message: "invalid certificate file: " + err.Error(), Value: path}
+	}
+	return nil
+}
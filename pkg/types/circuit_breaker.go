@@ -0,0 +1,68 @@
+package types
+
+import "time"
+
+// CircuitState is the state of a client's circuit breaker.
+type CircuitState string
+
+const (
+	// CircuitClosed runs queries normally
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen fails queries without running the CLI
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen lets a few probe queries through to test recovery
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitBreakerConfig stops a client from starting CLI processes after
+// repeated failures, so a service does not pile retries onto a CLI or API
+// that is down. After FailureThreshold consecutive failures the circuit
+// opens and queries fail fast with a CircuitOpenError. Once CoolDown has
+// passed it half-opens: up to HalfOpenProbes queries run, and the first to
+// finish closes the circuit if it succeeded or reopens it if it failed.
+//
+// Failures are CLI processes that could not start, exited with an error or
+// ran past their context's deadline. Queries canceled or interrupted by the
+// caller do not count either way.
+//
+// Example usage:
+//
+//	config.CircuitBreaker = &types.CircuitBreakerConfig{
+//		FailureThreshold: 3,
+//		CoolDown:         time.Minute,
+//	}
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens
+	// the circuit (defaults to 5)
+	FailureThreshold int `json:"failure_threshold,omitempty"`
+
+	// CoolDown is how long the circuit stays open before half-opening
+	// (defaults to 30s)
+	CoolDown time.Duration `json:"cool_down,omitempty"`
+
+	// HalfOpenProbes is the number of queries let through at once while
+	// half-open (defaults to 1)
+	HalfOpenProbes int `json:"half_open_probes,omitempty"`
+
+	// OnStateChange is called when the circuit changes state
+	OnStateChange func(from, to CircuitState) `json:"-"`
+}
+
+// Validate checks that no value is negative. A nil config is valid.
+func (c *CircuitBreakerConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.FailureThreshold < 0 {
+		return &ValidationError{Field: "circuit_breaker.failure_threshold", Message: "failure threshold cannot be negative", Value: c.FailureThreshold}
+	}
+	if c.CoolDown < 0 {
+		return &ValidationError{Field: "circuit_breaker.cool_down", Message: "cool-down cannot be negative", Value: c.CoolDown}
+	}
+	if c.HalfOpenProbes < 0 {
+		return &ValidationError{Field: "circuit_breaker.half_open_probes", Message: "half-open probes cannot be negative", Value: c.HalfOpenProbes}
+	}
+	return nil
+}
@@ -30,6 +30,18 @@ type Client interface {
 	Close() error
 }
 
+// QueryExecutor runs queries. ClaudeCodeClient and ClaudeCodeSession implement
+// it, so packages that only send queries, such as jobs and workflow, accept
+// either.
+type QueryExecutor interface {
+	Query(ctx context.Context, request *QueryRequest) (*QueryResponse, error)
+}
+
+// QueryStreamer runs queries as streams. ClaudeCodeClient implements it.
+type QueryStreamer interface {
+	QueryStream(ctx context.Context, request *QueryRequest) (QueryStream, error)
+}
+
 // ClaudeCodeClient extends the basic Client interface with Claude Code-specific features.
 // This includes command execution, project context management, and session handling.
 type ClaudeCodeClient interface {
@@ -94,6 +106,12 @@ type StreamChunk struct {
 	// Metadata contains additional information about this chunk
 	Metadata map[string]any `json:"metadata,omitempty"`
 
+	// CompactBoundary is set for chunks that mark a conversation compaction
+	CompactBoundary *CompactBoundaryMessage `json:"compact_boundary,omitempty"`
+
+	// Images holds the images in the chunk's line, from Claude or tool results
+	Images []ImageBlock `json:"images,omitempty"`
+
 	// Done indicates whether this is the final chunk in the stream
 	Done bool `json:"done"`
 }
@@ -116,6 +134,9 @@ const (
 
 	// ChunkTypeDone indicates the final chunk marking stream completion
 	ChunkTypeDone ChunkType = "done"
+
+	// ChunkTypeCompactBoundary indicates the CLI compacted the conversation history
+	ChunkTypeCompactBoundary ChunkType = "compact_boundary"
 )
 
 // StreamDelta represents incremental changes in a streaming response.
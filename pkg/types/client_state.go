@@ -0,0 +1,44 @@
+package types
+
+import "time"
+
+// ClientState is the lifecycle state of a Claude Code client.
+type ClientState string
+
+const (
+	// ClientDisconnected means the last attempt to start the claude CLI failed
+	ClientDisconnected ClientState = "disconnected"
+
+	// ClientConnecting means a query is starting the claude CLI
+	ClientConnecting ClientState = "connecting"
+
+	// ClientReady means no query is in flight
+	ClientReady ClientState = "ready"
+
+	// ClientBusy means one or more queries are running
+	ClientBusy ClientState = "busy"
+
+	// ClientInterrupting means interrupted queries have not finished yet
+	ClientInterrupting ClientState = "interrupting"
+
+	// ClientClosed means the client has been closed and rejects queries
+	ClientClosed ClientState = "closed"
+)
+
+// CanQuery reports whether a query may be started in this state. Queries may
+// overlap, so only a closed client rejects them.
+func (s ClientState) CanQuery() bool {
+	return s != ClientClosed
+}
+
+// ClientStateChange reports a transition between client states.
+type ClientStateChange struct {
+	// From is the previous state
+	From ClientState `json:"from"`
+
+	// To is the new state
+	To ClientState `json:"to"`
+
+	// Time is when the transition happened
+	Time time.Time `json:"time"`
+}
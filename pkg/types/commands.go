@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // CommandType represents the type of command being executed
 // Simplified to not prescribe specific command types - users can send any prompt
 type CommandType string
@@ -49,11 +51,45 @@ type CommandResult struct {
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
-// ProjectContext represents basic project information
-// Simplified to match official SDK scope (just working directory)
+// ProjectContext represents project information.
+// GetProjectContext returns the directories only; GetEnhancedProjectContext
+// additionally fills in the analysis fields.
 type ProjectContext struct {
 	// WorkingDirectory is the current working directory
 	WorkingDirectory string `json:"working_directory"`
+
+	// AdditionalDirectories are extra workspace roots passed to the CLI via --add-dir
+	AdditionalDirectories []string `json:"additional_directories,omitempty"`
+
+	// Dependencies is the dependency graph extracted from project manifests
+	Dependencies *DependencyGraph `json:"dependencies,omitempty"`
+
+	// Git describes the repository state, nil if the project is not in a git repository
+	Git *GitInfo `json:"git,omitempty"`
+
+	// Symbols is the Go symbol index, nil unless symbol indexing is enabled
+	Symbols *SymbolIndex `json:"symbols,omitempty"`
+
+	// Stack lists the languages, frameworks and tools found by the registered
+	// detectors, nil if none was found
+	Stack *TechStack `json:"stack,omitempty"`
+
+	// Workspace describes the monorepo the project belongs to, nil if the
+	// working directory is not a workspace root
+	Workspace *Workspace `json:"workspace,omitempty"`
+
+	// Focus is the workspace package the context was narrowed to, relative
+	// to the workspace root; WorkingDirectory is then the package directory
+	Focus string `json:"focus,omitempty"`
+}
+
+// FindSymbol returns the indexed Go symbols with the given name, or nil if
+// symbol indexing is not enabled.
+func (p *ProjectContext) FindSymbol(name string) []Symbol {
+	if p.Symbols == nil {
+		return nil
+	}
+	return p.Symbols.FindSymbol(name)
 }
 
 // CommandList represents a list of commands to execute
@@ -108,3 +144,19 @@ type CommandListResult struct {
 	// Errors contains any errors that occurred
 	Errors []string `json:"errors,omitempty"`
 }
+
+// WorkingDirectoryChange reports that SetWorkingDirectory switched the
+// client to a new project directory.
+type WorkingDirectoryChange struct {
+	// From is the previous working directory
+	From string `json:"from"`
+
+	// To is the new working directory
+	To string `json:"to"`
+
+	// Marker is the project marker found in To, if ProjectMarkers is set
+	Marker string `json:"marker,omitempty"`
+
+	// Time is when the change happened
+	Time time.Time `json:"time"`
+}
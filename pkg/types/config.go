@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -89,6 +90,15 @@ type ClaudeCodeConfig struct {
 	// WorkingDirectory is the project directory for context (defaults to current directory)
 	WorkingDirectory string `json:"working_directory,omitempty"`
 
+	// AddDirs lists additional workspace roots Claude may access (maps to --add-dir)
+	// Relative paths are resolved against WorkingDirectory
+	AddDirs []string `json:"add_dirs,omitempty"`
+
+	// ProjectMarkers are files or directories, such as "go.mod" or ".git",
+	// one of which must exist in a directory passed to SetWorkingDirectory
+	// (empty accepts any directory)
+	ProjectMarkers []string `json:"project_markers,omitempty"`
+
 	// SessionID is the session identifier for conversation persistence
 	SessionID string `json:"session_id,omitempty"`
 
@@ -120,6 +130,18 @@ type ClaudeCodeConfig struct {
 	// System is the default system prompt
 	System string `json:"system,omitempty"`
 
+	// Memory recalls facts from earlier conversations for the first prompt of
+	// each new session; they are added to its system prompt
+	Memory MemoryRecaller `json:"-"`
+
+	// MemoryRecallLimit caps the facts recalled per session (0 uses
+	// DefaultMemoryRecallLimit)
+	MemoryRecallLimit int `json:"memory_recall_limit,omitempty"`
+
+	// PinnedContextMaxTokens caps the estimated tokens of the snippets pinned
+	// with PinContext and PinFile (0 uses DefaultPinnedContextMaxTokens)
+	PinnedContextMaxTokens int `json:"pinned_context_max_tokens,omitempty"`
+
 	// Timeout is the default timeout for CLI execution
 	Timeout time.Duration `json:"timeout,omitempty"`
 
@@ -130,8 +152,240 @@ type ClaudeCodeConfig struct {
 	// When true, the client will skip CLI validation and use mock behavior
 	TestMode bool `json:"test_mode,omitempty"`
 
+	// TestScript serves canned responses in test mode without starting the
+	// CLI (nil runs the configured executable, or echo)
+	TestScript *TestScript `json:"test_script,omitempty"`
+
+	// Faults injects failures into the CLI's output for resilience testing
+	// (nil disables them)
+	Faults *FaultConfig `json:"faults,omitempty"`
+
+	// CircuitBreaker fails queries fast after repeated CLI failures (nil
+	// disables it)
+	CircuitBreaker *CircuitBreakerConfig `json:"circuit_breaker,omitempty"`
+
 	// ClaudeExecutable is an alias for ClaudeCodePath for backward compatibility
 	ClaudeExecutable string `json:"claude_executable,omitempty"`
+
+	// KeepAlive enables liveness checks for long-lived CLI processes (nil disables them)
+	KeepAlive *KeepAliveConfig `json:"keep_alive,omitempty"`
+
+	// Webhook sends query progress and completion events to a URL (nil disables it)
+	Webhook *WebhookConfig `json:"webhook,omitempty"`
+
+	// ToolTimeouts limits how long a single tool execution may run, keyed by
+	// tool name (e.g. "Bash"). The key "*" applies to tools without an entry.
+	// A query whose tool exceeds its deadline is interrupted.
+	ToolTimeouts map[string]time.Duration `json:"tool_timeouts,omitempty"`
+
+	// EnvPolicy controls which host environment variables reach the CLI
+	// subprocess (nil passes only DefaultEnvAllowlist)
+	EnvPolicy *EnvPolicy `json:"env_policy,omitempty"`
+
+	// Proxy routes the traffic of the CLI subprocess, and of the MCP servers
+	// it starts, through a proxy by setting HTTPS_PROXY, HTTP_PROXY and
+	// NO_PROXY (nil leaves the inherited proxy variables in effect)
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+
+	// Gateway sends the CLI's API requests to an LLM gateway instead of the
+	// Anthropic API (nil connects directly)
+	Gateway *GatewayConfig `json:"gateway,omitempty"`
+
+	// CertificateTrust adds a CA bundle to the certificates the CLI trusts,
+	// or pins it to a set of certificates (nil uses the CLI's built-in roots)
+	CertificateTrust *CertificateTrust `json:"certificate_trust,omitempty"`
+
+	// IsolatedConfigDir gives the client's CLI processes their own config
+	// directory, set as CLAUDE_CONFIG_DIR and created if missing, so clients
+	// running in parallel do not share settings, session history or locks.
+	// It must be an absolute path (empty uses the CLI's shared ~/.claude).
+	IsolatedConfigDir string `json:"isolated_config_dir,omitempty"`
+
+	// BashSandbox runs Bash tool commands in a container instead of on the
+	// host (nil runs them on the host)
+	BashSandbox *BashSandbox `json:"bash_sandbox,omitempty"`
+
+	// WebDomains restricts the domains the WebFetch and WebSearch tools may
+	// reach (nil leaves them unrestricted)
+	WebDomains *WebDomainPolicy `json:"web_domains,omitempty"`
+
+	// EnforceClaudeIgnore denies Claude's Read and Edit tools access to the
+	// paths listed in the working directory's .claudeignore file
+	EnforceClaudeIgnore bool `json:"enforce_claude_ignore,omitempty"`
+
+	// ExtraArgs appends arbitrary flags to every CLI invocation, so new CLI
+	// features can be used before the SDK supports them. Keys are flag names
+	// with or without the leading "--"; a nil value passes a boolean flag.
+	ExtraArgs map[string]*string `json:"extra_args,omitempty"`
+
+	// ControlProtocol keeps the stdin of streaming queries open so control
+	// requests can be sent to the CLI while it runs. The prompt is written to
+	// stdin as a stream-json message, and stdin is closed when the result
+	// arrives.
+	ControlProtocol bool `json:"control_protocol,omitempty"`
+
+	// ControlRequestTimeout bounds how long a control request waits for the
+	// CLI's response (default 30 seconds)
+	ControlRequestTimeout time.Duration `json:"control_request_timeout,omitempty"`
+
+	// CLIFeatureCheck validates the flags of each CLI invocation against the
+	// installed CLI's --help output (empty disables the check)
+	CLIFeatureCheck CLIFeatureCheck `json:"cli_feature_check,omitempty"`
+
+	// OnCLIWarning receives warnings such as unsupported flags in warn mode
+	// (nil writes them to stderr)
+	OnCLIWarning func(message string) `json:"-"`
+
+	// OnMCPServerUnhealthy is called when an MCP server that was healthy (or
+	// not yet seen) is reported as failed or needing authentication
+	OnMCPServerUnhealthy func(status MCPServerStatus) `json:"-"`
+
+	// Redactor scrubs secrets from prompts, system prompts and the tool
+	// inputs included in webhook events (nil disables redaction). See
+	// package redact for a detector-based implementation.
+	Redactor Redactor `json:"-"`
+
+	// MessagePolicy checks prompts before they are sent and responses
+	// before they are returned, and can rewrite or block them (nil disables
+	// checks). See package pii for personal data detection.
+	MessagePolicy MessagePolicy `json:"-"`
+
+	// OnFileAccess is called for every file read, written or edited through
+	// Claude's tools in streaming queries and jobs
+	OnFileAccess func(event FileAccessEvent) `json:"-"`
+
+	// OnStateChange is called, in order, for every transition of the
+	// client's lifecycle state
+	OnStateChange func(change ClientStateChange) `json:"-"`
+
+	// OnWorkingDirectoryChange is called after SetWorkingDirectory switches
+	// the client to a new directory
+	OnWorkingDirectoryChange func(change WorkingDirectoryChange) `json:"-"`
+
+	// OnToolProgress is called for progress updates reported by tools in the
+	// client's ToolRegistry
+	OnToolProgress func(progress ToolProgress) `json:"-"`
+
+	// ApprovalProvider is asked before Claude runs the tools matched by
+	// ApprovalTools; a tool call runs only when it is approved (nil disables
+	// approvals)
+	ApprovalProvider ApprovalProvider `json:"-"`
+
+	// ApprovalTools is the PreToolUse matcher of tools that need approval
+	// (default DefaultApprovalTools)
+	ApprovalTools string `json:"approval_tools,omitempty"`
+
+	// ApprovalTimeout bounds how long a tool call waits for a decision
+	// before it is denied (default 10 minutes)
+	ApprovalTimeout time.Duration `json:"approval_timeout,omitempty"`
+
+	// Profiles are named presets selectable per query with QueryOptions.Profile
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+
+	// DefaultProfile is applied to queries that do not select a profile
+	DefaultProfile string `json:"default_profile,omitempty"`
+}
+
+// Redactor removes secrets from text before it leaves the process.
+type Redactor interface {
+	Redact(text string) string
+}
+
+// MessageDirection tells a MessagePolicy whether text is being sent to
+// Claude or was received from it.
+type MessageDirection string
+
+const (
+	// MessageOutgoing is prompt and system prompt text
+	MessageOutgoing MessageDirection = "outgoing"
+
+	// MessageIncoming is response text
+	MessageIncoming MessageDirection = "incoming"
+)
+
+// MessagePolicy inspects message text before it is sent or returned. Apply
+// returns the text to use in its place and tags naming what was found, or an
+// error to block the message.
+type MessagePolicy interface {
+	Apply(ctx context.Context, direction MessageDirection, text string) (string, []string, error)
+}
+
+// CLIFeatureCheck controls what happens when an invocation uses a flag the
+// installed CLI does not support.
+type CLIFeatureCheck string
+
+const (
+	// CLIFeatureCheckOff skips the check
+	CLIFeatureCheckOff CLIFeatureCheck = ""
+
+	// CLIFeatureCheckWarn reports unsupported flags and runs the CLI anyway
+	CLIFeatureCheckWarn CLIFeatureCheck = "warn"
+
+	// CLIFeatureCheckError fails the invocation before the CLI is started
+	CLIFeatureCheckError CLIFeatureCheck = "error"
+)
+
+// KeepAliveConfig controls how the client detects dead or stalled CLI processes
+// backing interactive streams.
+//
+// Example usage:
+//
+//	config.KeepAlive = &types.KeepAliveConfig{
+//		Interval:      5 * time.Second,
+//		IdleTimeout:   2 * time.Minute,
+//		AutoReconnect: true,
+//		OnDisconnect: func(event types.DisconnectEvent) {
+//			log.Printf("stream %s disconnected: %s", event.ProcessID, event.Reason)
+//		},
+//	}
+type KeepAliveConfig struct {
+	// Interval is how often active processes are checked (defaults to 10s)
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// IdleTimeout marks a process stale when it produces no output for this long (zero disables)
+	IdleTimeout time.Duration `json:"idle_timeout,omitempty"`
+
+	// AutoReconnect restarts a disconnected stream by resuming its session,
+	// asking Claude to continue rather than sending the prompt again
+	AutoReconnect bool `json:"auto_reconnect,omitempty"`
+
+	// MaxReconnectAttempts limits reconnects per stream (defaults to 3)
+	MaxReconnectAttempts int `json:"max_reconnect_attempts,omitempty"`
+
+	// OnDisconnect is called whenever a stale or dead process is detected
+	OnDisconnect func(event DisconnectEvent) `json:"-"`
+}
+
+// DisconnectReason describes why a CLI process was considered disconnected.
+type DisconnectReason string
+
+const (
+	// DisconnectReasonProcessExited indicates the CLI process is no longer running
+	DisconnectReasonProcessExited DisconnectReason = "process_exited"
+
+	// DisconnectReasonIdleTimeout indicates the CLI process produced no output within the idle timeout
+	DisconnectReasonIdleTimeout DisconnectReason = "idle_timeout"
+)
+
+// DisconnectEvent describes a detected disconnect of a CLI process.
+type DisconnectEvent struct {
+	// ProcessID is the client-internal identifier of the process
+	ProcessID string `json:"process_id"`
+
+	// SessionID is the session the process was serving
+	SessionID string `json:"session_id,omitempty"`
+
+	// Reason explains why the process was considered disconnected
+	Reason DisconnectReason `json:"reason"`
+
+	// IdleFor is how long the process had been silent when the disconnect was detected
+	IdleFor time.Duration `json:"idle_for,omitempty"`
+
+	// Reconnecting indicates the client will attempt to restart the stream
+	Reconnecting bool `json:"reconnecting"`
+
+	// Timestamp is when the disconnect was detected
+	Timestamp time.Time `json:"timestamp"`
 }
 
 // NewClaudeCodeConfig creates a new ClaudeCodeConfig with sensible defaults.
@@ -146,31 +400,19 @@ func NewClaudeCodeConfig() *ClaudeCodeConfig {
 	}
 }
 
-// NewClaudeCodeConfigFromEnvironment creates a new ClaudeCodeConfig from environment variables.
+// NewClaudeCodeConfigFromEnvironment creates a new ClaudeCodeConfig from
+// environment variables (see ApplyEnvironment). Invalid values are ignored.
 func NewClaudeCodeConfigFromEnvironment() *ClaudeCodeConfig {
 	config := NewClaudeCodeConfig()
-
-	if apiKey := os.Getenv("ANTHROPIC_API_KEY"); apiKey != "" {
-		config.APIKey = apiKey
-	}
-
-	if workDir := os.Getenv("CLAUDE_WORKING_DIR"); workDir != "" {
-		config.WorkingDirectory = workDir
-	}
-
-	if model := os.Getenv("CLAUDE_MODEL"); model != "" {
-		config.Model = model
-	}
-
-	if debug := os.Getenv("CLAUDE_DEBUG"); debug == "true" || debug == "1" {
-		config.Debug = true
-	}
-
+	_ = config.ApplyEnvironment() // Ignore error, invalid values leave the defaults in place
 	return config
 }
 
 // MCPServerConfig defines configuration for a Model Context Protocol server.
 type MCPServerConfig struct {
+	// Type is the transport: "stdio" (the default), "sse" or "http"
+	Type string `json:"type,omitempty"`
+
 	// Command is the command to execute for the MCP server
 	Command string `json:"command"`
 
@@ -183,10 +425,22 @@ type MCPServerConfig struct {
 	// WorkingDirectory is the working directory for the MCP server
 	WorkingDirectory string `json:"working_directory,omitempty"`
 
+	// URL is the endpoint of an "sse" or "http" server
+	URL string `json:"url,omitempty"`
+
+	// Headers are sent with every request to an "sse" or "http" server
+	Headers map[string]string `json:"headers,omitempty"`
+
 	// Enabled indicates whether this MCP server should be used
 	Enabled bool `json:"enabled"`
 }
 
+// IsRemote reports whether the server is reached over the network ("sse" or
+// "http") rather than started as a subprocess.
+func (c *MCPServerConfig) IsRemote() bool {
+	return c.Type == "sse" || c.Type == "http"
+}
+
 // Validate performs validation on the Claude Code configuration.
 func (c *ClaudeCodeConfig) Validate() error {
 	if c.WorkingDirectory != "" {
@@ -205,6 +459,13 @@ func (c *ClaudeCodeConfig) Validate() error {
 		}
 	}
 
+	if c.PinnedContextMaxTokens < 0 {
+		return &ValidationError{
+			Field:   "pinned_context_max_tokens",
+			Message: "pinned_context_max_tokens cannot be negative",
+		}
+	}
+
 	if c.Temperature < 0 || c.Temperature > 1 {
 		return &ValidationError{
 			Field:   "temperature",
@@ -219,9 +480,135 @@ func (c *ClaudeCodeConfig) Validate() error {
 		}
 	}
 
+	for tool, timeout := range c.ToolTimeouts {
+		if timeout <= 0 {
+			return &ValidationError{
+				Field:   "tool_timeouts." + tool,
+				Message: "tool timeouts must be positive",
+			}
+		}
+	}
+
+	if c.ControlRequestTimeout < 0 {
+		return &ValidationError{
+			Field:   "control_request_timeout",
+			Message: "control request timeout cannot be negative",
+		}
+	}
+
+	for flag := range c.ExtraArgs {
+		if !validExtraArg(flag) {
+			return &ValidationError{
+				Field:   "extra_args",
+				Message: "invalid CLI flag name " + strconv.Quote(flag),
+			}
+		}
+	}
+
+	for name, profile := range c.Profiles {
+		if err := profile.Validate(name); err != nil {
+			return err
+		}
+	}
+
+	if c.DefaultProfile != "" && c.Profiles[c.DefaultProfile] == nil {
+		return &ValidationError{
+			Field:   "default_profile",
+			Message: "unknown profile " + strconv.Quote(c.DefaultProfile),
+		}
+	}
+
+	switch c.CLIFeatureCheck {
+	case CLIFeatureCheckOff, CLIFeatureCheckWarn, CLIFeatureCheckError:
+	default:
+		return &ValidationError{
+			Field:   "cli_feature_check",
+			Message: "cli_feature_check must be empty, \"warn\" or \"error\"",
+		}
+	}
+
+	if c.IsolatedConfigDir != "" && !filepath.IsAbs(c.IsolatedConfigDir) {
+		return &ValidationError{
+			Field:   "isolated_config_dir",
+			Message: "isolated config directory must be an absolute path",
+			Value:   c.IsolatedConfigDir,
+		}
+	}
+
+	if err := c.EnvPolicy.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Proxy.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Gateway.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.CertificateTrust.Validate(); err != nil {
+		return err
+	}
+	if c.CertificateTrust != nil && c.Gateway != nil && c.Gateway.TLS != nil && c.Gateway.TLS.CAFile != "" {
+		// Both would be passed as NODE_EXTRA_CA_CERTS, and a gateway CA
+		// would widen the trust pinning restricts
+		return &ValidationError{
+			Field:   "gateway.tls.ca_file",
+			Message: "gateway CA file cannot be combined with certificate_trust; set the CA bundle there",
+		}
+	}
+
+	if err := c.BashSandbox.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.WebDomains.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Faults.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.CircuitBreaker.Validate(); err != nil {
+		return err
+	}
+
+	if c.TestScript != nil && !c.TestMode {
+		return &ValidationError{
+			Field:   "test_script",
+			Message: "test script requires test mode",
+		}
+	}
+
+	if c.Webhook != nil {
+		if u, err := url.Parse(c.Webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &ValidationError{
+				Field:   "webhook.url",
+				Message: "webhook URL must be an absolute http or https URL",
+			}
+		}
+		switch c.Webhook.Format {
+		case "", WebhookFormatJSON, WebhookFormatSlack, WebhookFormatTeams:
+		default:
+			return &ValidationError{
+				Field:   "webhook.format",
+				Message: "webhook format must be json, slack or teams",
+				Value:   c.Webhook.Format,
+			}
+		}
+	}
+
 	return nil
 }
 
+// validExtraArg reports whether flag is usable as an ExtraArgs key.
+func validExtraArg(flag string) bool {
+	name := strings.TrimLeft(flag, "-")
+	return name != "" && !strings.ContainsAny(name, " =\t\n")
+}
+
 // ApplyDefaults applies default values to unset configuration options.
 func (c *ClaudeCodeConfig) ApplyDefaults() {
 	if c.Model == "" {
@@ -531,6 +918,14 @@ const (
 	// DefaultMaxTokens is the default maximum tokens for responses
 	DefaultMaxTokens = 4000
 
+	// DefaultMemoryRecallLimit is the default number of facts recalled for a
+	// new session
+	DefaultMemoryRecallLimit = 5
+
+	// DefaultPinnedContextMaxTokens is the default token budget of pinned
+	// context snippets
+	DefaultPinnedContextMaxTokens = 8000
+
 	// DefaultTemperature is the default temperature for responses
 	DefaultTemperature = 0.7
 
@@ -0,0 +1,306 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileError reports a problem with a configuration file, naming the
+// offending field when there is one.
+type ConfigFileError struct {
+	// Path is the configuration file
+	Path string
+
+	// Field is the dotted path of the offending field (empty for syntax errors)
+	Field string
+
+	// Message describes the problem
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ConfigFileError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("config file %s: %s", e.Path, e.Message)
+	}
+	return fmt.Sprintf("config file %s: field '%s': %s", e.Path, e.Field, e.Message)
+}
+
+// LoadConfig reads a ClaudeCodeConfig from a YAML (.yaml, .yml), JSON (.json)
+// or TOML (.toml) file on top of NewClaudeCodeConfig defaults and validates it.
+//
+// Keys use the config's JSON names (e.g. "working_directory"). Durations may be
+// written as strings such as "30s"; unknown keys are rejected.
+//
+// Example file:
+//
+//	model: claude-sonnet-4-20250514
+//	working_directory: /srv/project
+//	timeout: 2m
+//	tool_timeouts:
+//	  Bash: 30s
+func LoadConfig(path string) (*ClaudeCodeConfig, error) {
+	config := NewClaudeCodeConfig()
+	if err := loadConfigFile(path, config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadConfigFromEnvAndFile builds a ClaudeCodeConfig from, in increasing order
+// of precedence: NewClaudeCodeConfig defaults, the config file at path (skipped
+// when path is empty), environment variables (see ApplyEnvironment), and the
+// programmatic overrides. The result is validated.
+func LoadConfigFromEnvAndFile(path string, overrides ...func(*ClaudeCodeConfig)) (*ClaudeCodeConfig, error) {
+	config := NewClaudeCodeConfig()
+
+	if path != "" {
+		if err := loadConfigFile(path, config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.ApplyEnvironment(); err != nil {
+		return nil, err
+	}
+
+	for _, override := range overrides {
+		override(config)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ApplyEnvironment overrides the config with the environment variables the
+// SDK recognizes:
+//
+//	ANTHROPIC_API_KEY       api_key
+//	CLAUDE_WORKING_DIR      working_directory
+//	CLAUDE_MODEL            model
+//	CLAUDE_SESSION_ID       session_id
+//	CLAUDE_CODE_PATH        claude_code_path
+//	CLAUDE_AUTH_METHOD      auth_method
+//	CLAUDE_SYSTEM_PROMPT    system
+//	CLAUDE_MAX_TOKENS       max_tokens
+//	CLAUDE_TIMEOUT          timeout (e.g. "2m")
+//	CLAUDE_DEBUG            debug ("true" or "1")
+//
+// Invalid values return a ValidationError naming the variable.
+func (c *ClaudeCodeConfig) ApplyEnvironment() error {
+	stringVars := map[string]*string{
+		"ANTHROPIC_API_KEY":    &c.APIKey,
+		"CLAUDE_WORKING_DIR":   &c.WorkingDirectory,
+		"CLAUDE_MODEL":         &c.Model,
+		"CLAUDE_SESSION_ID":    &c.SessionID,
+		"CLAUDE_CODE_PATH":     &c.ClaudeCodePath,
+		"CLAUDE_SYSTEM_PROMPT": &c.System,
+	}
+	for name, field := range stringVars {
+		if value := os.Getenv(name); value != "" {
+			*field = value
+		}
+	}
+
+	if value := os.Getenv("CLAUDE_AUTH_METHOD"); value != "" {
+		c.AuthMethod = AuthType(value)
+	}
+
+	if value := os.Getenv("CLAUDE_MAX_TOKENS"); value != "" {
+		maxTokens, err := strconv.Atoi(value)
+		if err != nil {
+			return &ValidationError{Field: "CLAUDE_MAX_TOKENS", Message: "must be an integer", Value: value}
+		}
+		c.MaxTokens = maxTokens
+	}
+
+	if value := os.Getenv("CLAUDE_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return &ValidationError{Field: "CLAUDE_TIMEOUT", Message: "must be a duration such as \"30s\"", Value: value}
+		}
+		c.Timeout = timeout
+	}
+
+	if value := os.Getenv("CLAUDE_DEBUG"); value != "" {
+		c.Debug = value == "true" || value == "1"
+	}
+
+	return nil
+}
+
+// loadConfigFile decodes a config file onto config.
+func loadConfigFile(path string, config *ClaudeCodeConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &ConfigFileError{Path: path, Message: err.Error()}
+	}
+
+	var raw map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return &ConfigFileError{Path: path, Message: "unsupported config format " + strconv.Quote(ext) + " (use .yaml, .yml, .json or .toml)"}
+	}
+	if err != nil {
+		return &ConfigFileError{Path: path, Message: "invalid syntax: " + err.Error()}
+	}
+
+	normalized, err := normalizeConfigValue(raw, reflect.TypeOf(*config), "")
+	if err != nil {
+		var fileErr *ConfigFileError
+		if errors.As(err, &fileErr) {
+			fileErr.Path = path
+		}
+		return err
+	}
+
+	encoded, err := json.Marshal(normalized)
+	if err != nil {
+		return &ConfigFileError{Path: path, Message: err.Error()}
+	}
+	if err := json.Unmarshal(encoded, config); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return &ConfigFileError{Path: path, Field: typeErr.Field, Message: "expected " + typeErr.Type.String() + ", got " + typeErr.Value}
+		}
+		return &ConfigFileError{Path: path, Message: err.Error()}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// normalizeConfigValue checks decoded file values against the config type,
+// rejecting unknown keys and converting duration strings to nanoseconds so the
+// result decodes with encoding/json.
+func normalizeConfigValue(value any, t reflect.Type, path string) (any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	if t == durationType {
+		if text, ok := value.(string); ok {
+			duration, err := time.ParseDuration(text)
+			if err != nil {
+				return nil, &ConfigFileError{Field: path, Message: "invalid duration " + strconv.Quote(text)}
+			}
+			return int64(duration), nil
+		}
+		return value, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, &ConfigFileError{Field: path, Message: fmt.Sprintf("expected a table of settings, got %T", value)}
+		}
+
+		fields := configFields(t)
+		normalized := make(map[string]any, len(object))
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fieldPath := joinConfigPath(path, key)
+			field, ok := fields[key]
+			if !ok {
+				return nil, &ConfigFileError{Field: fieldPath, Message: "unknown field"}
+			}
+			converted, err := normalizeConfigValue(object[key], field.Type, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+			normalized[key] = converted
+		}
+		return normalized, nil
+
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, &ConfigFileError{Field: path, Message: fmt.Sprintf("expected a table, got %T", value)}
+		}
+		normalized := make(map[string]any, len(object))
+		for key, item := range object {
+			converted, err := normalizeConfigValue(item, t.Elem(), joinConfigPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			normalized[key] = converted
+		}
+		return normalized, nil
+
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return value, nil
+		}
+		normalized := make([]any, len(items))
+		for i, item := range items {
+			converted, err := normalizeConfigValue(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			normalized[i] = converted
+		}
+		return normalized, nil
+	}
+
+	return value, nil
+}
+
+// configFields maps the JSON names of a struct's settable fields to the fields.
+func configFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,154 @@
+package types
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig_Formats(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+model: claude-sonnet-4
+working_directory: WORKDIR
+timeout: 2m
+tool_timeouts:
+  Bash: 30s
+keep_alive:
+  interval: 5s
+mcp_servers:
+  fs:
+    command: npx
+    args: [server-filesystem]
+    enabled: true
+`,
+		"config.json": `{
+  "model": "claude-sonnet-4",
+  "working_directory": "WORKDIR",
+  "timeout": "2m",
+  "tool_timeouts": {"Bash": "30s"},
+  "keep_alive": {"interval": "5s"},
+  "mcp_servers": {"fs": {"command": "npx", "args": ["server-filesystem"], "enabled": true}}
+}`,
+		"config.toml": `
+model = "claude-sonnet-4"
+working_directory = "WORKDIR"
+timeout = "2m"
+
+[tool_timeouts]
+Bash = "30s"
+
+[keep_alive]
+interval = "5s"
+
+[mcp_servers.fs]
+command = "npx"
+args = ["server-filesystem"]
+enabled = true
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			workDir := t.TempDir()
+			content = strings.ReplaceAll(content, "WORKDIR", filepath.ToSlash(workDir))
+
+			config, err := LoadConfig(writeConfigFile(t, name, content))
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if config.Model != "claude-sonnet-4" || config.WorkingDirectory != filepath.ToSlash(workDir) || config.Timeout != 2*time.Minute {
+				t.Errorf("Unexpected config: %+v", config)
+			}
+			if config.ToolTimeouts["Bash"] != 30*time.Second || config.KeepAlive.Interval != 5*time.Second {
+				t.Errorf("Durations not decoded: %v %+v", config.ToolTimeouts, config.KeepAlive)
+			}
+			if server := config.MCPServers["fs"]; server == nil || server.Command != "npx" || len(server.Args) != 1 || !server.Enabled {
+				t.Errorf("Unexpected MCP servers: %+v", config.MCPServers)
+			}
+			if config.MaxTokens != 4096 {
+				t.Errorf("Expected defaults to be kept, got max_tokens %d", config.MaxTokens)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		field   string
+	}{
+		{"unknown field", "c.yaml", "keep_alive:\n  intervl: 5s\n", "keep_alive.intervl"},
+		{"bad duration", "c.yaml", "tool_timeouts:\n  Bash: soon\n", "tool_timeouts.Bash"},
+		{"wrong type", "c.json", `{"max_tokens": "many"}`, "max_tokens"},
+		{"syntax", "c.toml", "model = ", ""},
+		{"format", "c.ini", "model=x", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfigFile(t, tt.file, tt.content))
+
+			var fileErr *ConfigFileError
+			if !errors.As(err, &fileErr) {
+				t.Fatalf("Expected ConfigFileError, got %v", err)
+			}
+			if fileErr.Field != tt.field {
+				t.Errorf("Field = %q, want %q (%v)", fileErr.Field, tt.field, err)
+			}
+		})
+	}
+
+	// Semantic validation names the field too
+	_, err := LoadConfig(writeConfigFile(t, "c.yaml", "temperature: 3\n"))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "temperature" {
+		t.Errorf("Expected temperature validation error, got %v", err)
+	}
+}
+
+func TestLoadConfigFromEnvAndFile_Precedence(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "model: from-file\nsystem: file prompt\nmax_tokens: 100\n")
+
+	t.Setenv("CLAUDE_MODEL", "from-env")
+	t.Setenv("CLAUDE_TIMEOUT", "45s")
+
+	config, err := LoadConfigFromEnvAndFile(path, func(c *ClaudeCodeConfig) {
+		c.MaxTokens = 200
+	})
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnvAndFile() error = %v", err)
+	}
+
+	if config.System != "file prompt" {
+		t.Errorf("Expected file value, got %q", config.System)
+	}
+	if config.Model != "from-env" || config.Timeout != 45*time.Second {
+		t.Errorf("Expected environment to override the file, got %q %v", config.Model, config.Timeout)
+	}
+	if config.MaxTokens != 200 {
+		t.Errorf("Expected override to win, got %d", config.MaxTokens)
+	}
+
+	t.Setenv("CLAUDE_MAX_TOKENS", "lots")
+	_, err = LoadConfigFromEnvAndFile("")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "CLAUDE_MAX_TOKENS" {
+		t.Errorf("Expected CLAUDE_MAX_TOKENS error, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package types
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrSkipContent is returned by a ContentVisitor's ToolResult callback to
+// skip the blocks nested in that tool result. It is not returned by
+// WalkContent.
+var ErrSkipContent = errors.New("skip tool result content")
+
+// ToolUseFromBlock converts a tool_use block to a ToolUse.
+func ToolUseFromBlock(block ContentBlock) ToolUse {
+	use := ToolUse{ID: block.ID, Name: block.Name, Input: block.Input}
+	if block.Input == nil {
+		if data, ok := block.Data.(map[string]any); ok {
+			if id, ok := data["id"].(string); ok {
+				use.ID = id
+			}
+			if name, ok := data["name"].(string); ok {
+				use.Name = name
+			}
+			use.Input, _ = data["input"].(map[string]any)
+		}
+	}
+	return use
+}
+
+// ContentVisitor holds the callbacks WalkContent calls for each kind of
+// block; nil callbacks are skipped. A callback that returns an error stops
+// the walk.
+type ContentVisitor struct {
+	// Text is called for text blocks
+	Text func(text string) error
+
+	// ToolUse is called for tool_use blocks
+	ToolUse func(use ToolUse) error
+
+	// ToolResult is called for tool_result blocks before the blocks nested
+	// in them are walked; return ErrSkipContent to skip those
+	ToolResult func(block ContentBlock) error
+
+	// Image is called for image blocks
+	Image func(block ContentBlock) error
+
+	// Other is called for blocks of any other type, such as documents
+	Other func(block ContentBlock) error
+}
+
+// Blocks adapts a slice of content blocks, such as QueryResponse.Content,
+// to AnyMessage for use with WalkContent and the extraction helpers.
+type Blocks []ContentBlock
+
+// MessageRole implements AnyMessage. Blocks have no role.
+func (b Blocks) MessageRole() Role {
+	return ""
+}
+
+// ContentBlocks implements AnyMessage.
+func (b Blocks) ContentBlocks() []ContentBlock {
+	return b
+}
+
+// WalkContent calls the visitor's callbacks for each block of msg in order,
+// descending into the content of tool results, so consumers need no type
+// switch over block types:
+//
+//	err := types.WalkContent(response, types.ContentVisitor{
+//		Text:    func(text string) error { fmt.Print(text); return nil },
+//		ToolUse: func(use types.ToolUse) error { log.Println("tool:", use.Name); return nil },
+//	})
+func WalkContent(msg AnyMessage, visitor ContentVisitor) error {
+	return walkBlocks(msg.ContentBlocks(), &visitor)
+}
+
+func walkBlocks(blocks []ContentBlock, visitor *ContentVisitor) error {
+	for _, block := range blocks {
+		var err error
+		switch block.Type {
+		case "text":
+			if visitor.Text != nil {
+				err = visitor.Text(block.Text)
+			}
+		case "tool_use":
+			if visitor.ToolUse != nil {
+				err = visitor.ToolUse(ToolUseFromBlock(block))
+			}
+		case "tool_result":
+			if visitor.ToolResult != nil {
+				err = visitor.ToolResult(block)
+			}
+			if err == nil {
+				err = walkBlocks(block.Content, visitor)
+			} else if errors.Is(err, ErrSkipContent) {
+				err = nil
+			}
+		case "image":
+			if visitor.Image != nil {
+				err = visitor.Image(block)
+			}
+		default:
+			if visitor.Other != nil {
+				err = visitor.Other(block)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExtractText returns the text of msg's text blocks joined together. Text
+// inside tool results is not included.
+func ExtractText(msg AnyMessage) string {
+	var b strings.Builder
+	for _, block := range msg.ContentBlocks() {
+		if block.Type == "text" {
+			b.WriteString(block.Text)
+		}
+	}
+	return b.String()
+}
+
+// ExtractToolUses returns the tool calls in msg, in order.
+func ExtractToolUses(msg AnyMessage) []ToolUse {
+	var uses []ToolUse
+	for _, block := range msg.ContentBlocks() {
+		if block.Type == "tool_use" {
+			uses = append(uses, ToolUseFromBlock(block))
+		}
+	}
+	return uses
+}
+
+// FilterBlocks returns the blocks of msg for which keep returns true.
+func FilterBlocks(msg AnyMessage, keep func(block ContentBlock) bool) []ContentBlock {
+	var kept []ContentBlock
+	for _, block := range msg.ContentBlocks() {
+		if keep(block) {
+			kept = append(kept, block)
+		}
+	}
+	return kept
+}
+
+// BlockTypes returns a FilterBlocks predicate keeping blocks of the given
+// types, e.g. BlockTypes("text", "image").
+func BlockTypes(types ...string) func(block ContentBlock) bool {
+	return func(block ContentBlock) bool {
+		for _, t := range types {
+			if block.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MapBlocks returns the blocks of msg transformed by fn, such as text with
+// secrets masked. Blocks for which fn returns false are dropped.
+func MapBlocks(msg AnyMessage, fn func(block ContentBlock) (ContentBlock, bool)) []ContentBlock {
+	var mapped []ContentBlock
+	for _, block := range msg.ContentBlocks() {
+		if block, ok := fn(block); ok {
+			mapped = append(mapped, block)
+		}
+	}
+	return mapped
+}
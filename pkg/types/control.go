@@ -0,0 +1,127 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Subtypes of control requests the SDK sends to the CLI.
+const (
+	// ControlSubtypeInterrupt stops the current turn, leaving the process running
+	ControlSubtypeInterrupt = "interrupt"
+
+	// ControlSubtypeSetModel switches the model for the following turns
+	ControlSubtypeSetModel = "set_model"
+
+	// ControlSubtypeSetPermissionMode changes how tool permissions are granted
+	ControlSubtypeSetPermissionMode = "set_permission_mode"
+)
+
+// Subtypes of control responses.
+const (
+	ControlResponseSuccess = "success"
+	ControlResponseError   = "error"
+)
+
+// ControlRequest is a request sent to the CLI over the control protocol.
+type ControlRequest struct {
+	// Subtype names the request, e.g. ControlSubtypeInterrupt
+	Subtype string
+
+	// Params are the request's other fields
+	Params map[string]any
+
+	// Timeout bounds the wait for the response (zero uses the client's
+	// ControlRequestTimeout)
+	Timeout time.Duration
+}
+
+// MarshalJSON implements json.Marshaler, writing Subtype alongside Params.
+func (r ControlRequest) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any, len(r.Params)+1)
+	for key, value := range r.Params {
+		fields[key] = value
+	}
+	fields["subtype"] = r.Subtype
+	return json.Marshal(fields)
+}
+
+// Validate checks that the request has a subtype that its params do not
+// override.
+func (r ControlRequest) Validate() error {
+	if r.Subtype == "" {
+		return &ValidationError{Field: "subtype", Message: "control request subtype is required"}
+	}
+	if _, ok := r.Params["subtype"]; ok {
+		return &ValidationError{Field: "params.subtype", Message: "set the subtype with Subtype, not Params"}
+	}
+	if r.Timeout < 0 {
+		return &ValidationError{Field: "timeout", Message: "control request timeout cannot be negative"}
+	}
+	return nil
+}
+
+// ControlResponse is the CLI's answer to a control request.
+type ControlResponse struct {
+	// Subtype is ControlResponseSuccess or ControlResponseError
+	Subtype string `json:"subtype"`
+
+	// RequestID matches the request the response answers
+	RequestID string `json:"request_id"`
+
+	// Response holds the fields of a successful response, if any
+	Response json.RawMessage `json:"response,omitempty"`
+
+	// Error describes why the request failed
+	Error string `json:"error,omitempty"`
+}
+
+// ParseControlResponse decodes a control_response frame of CLI output.
+func ParseControlResponse(frame []byte) (*ControlResponse, error) {
+	var raw struct {
+		Type     string           `json:"type"`
+		Response *ControlResponse `json:"response"`
+	}
+	if err := json.Unmarshal(frame, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Type != "control_response" || raw.Response == nil {
+		return nil, &ValidationError{Field: "type", Message: "frame is not a control response", Value: raw.Type}
+	}
+	return raw.Response, nil
+}
+
+// Success reports whether the CLI carried out the request.
+func (r *ControlResponse) Success() bool {
+	return r.Subtype == ControlResponseSuccess
+}
+
+// Decode unmarshals the fields of a successful response into v. It does
+// nothing if the response has no fields.
+func (r *ControlResponse) Decode(v any) error {
+	if len(r.Response) == 0 || string(r.Response) == "null" {
+		return nil
+	}
+	return json.Unmarshal(r.Response, v)
+}
+
+// InterruptAck acknowledges a ControlSubtypeInterrupt request.
+type InterruptAck struct {
+	RequestID string `json:"request_id"`
+}
+
+// SetModelAck acknowledges a ControlSubtypeSetModel request.
+type SetModelAck struct {
+	RequestID string `json:"request_id"`
+
+	// Model is the model used from the next turn
+	Model string `json:"model"`
+}
+
+// PermissionModeAck acknowledges a ControlSubtypeSetPermissionMode request.
+type PermissionModeAck struct {
+	RequestID string `json:"request_id"`
+
+	// Mode is the permission mode now in effect, e.g. "acceptEdits"
+	Mode string `json:"mode"`
+}
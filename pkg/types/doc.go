@@ -21,6 +21,14 @@ Configuration supports environment variables and builder patterns:
 		WithTimeout(30 * time.Second).
 		Build()
 
+Configuration can also be loaded from a YAML, JSON or TOML file. With
+LoadConfigFromEnvAndFile, environment variables override the file and the
+override functions override both:
+
+	config, err := types.LoadConfigFromEnvAndFile("claude.yaml", func(c *types.ClaudeCodeConfig) {
+		c.Debug = true
+	})
+
 # Message Types
 
 Messages are the core communication unit with Claude Code:
@@ -44,6 +52,38 @@ Messages support content blocks for structured data:
 		},
 	}
 
+Image and binary blocks hold their data base64 encoded with its media type,
+so tool results such as screenshots or compressed output are never treated
+as text:
+
+	block := types.NewBinaryBlock("application/gzip", data)
+	raw, err := block.Bytes()
+
+ParseImages extracts ImageBlocks from a line of CLI stream-json output,
+including images returned by tools, which name their ToolUseID.
+
+Message, SessionMessage, StreamMessage and QueryResponse all implement
+AnyMessage, so code can read the role and content blocks of a message
+whichever API returned it. ToMessage and ToSessionMessage convert between
+the shapes, and ParseRole turns role names from other systems into a Role:
+
+	func logTurn(m types.AnyMessage) {
+		log.Printf("%s: %d blocks", m.MessageRole(), len(m.ContentBlocks()))
+	}
+
+	history = append(history, types.ToMessage(response))
+
+WalkContent visits each block of a message with a callback per block type,
+descending into tool results, and ExtractText, ExtractToolUses, FilterBlocks
+and MapBlocks cover the common cases. Blocks adapts a plain slice:
+
+	err := types.WalkContent(response, types.ContentVisitor{
+		Text:    func(text string) error { fmt.Print(text); return nil },
+		ToolUse: func(use types.ToolUse) error { log.Println("tool:", use.Name); return nil },
+	})
+
+	images := types.FilterBlocks(types.Blocks(content), types.BlockTypes("image"))
+
 # Command Types
 
 Commands represent Claude Code CLI operations:
@@ -80,6 +120,31 @@ Query requests and responses for Claude Code interactions:
 		Model: "claude-3-opus",
 	}
 
+Assistant replies, QueryResponse and StreamMessage, have accessors for their
+text and tool calls:
+
+	fmt.Println(response.Text())
+	if response.HasToolUse("Bash") {
+		for _, use := range response.ToolUses() {
+			log.Printf("%s %v", use.Name, use.Input)
+		}
+	}
+
+ResultMessage is the final line of stream-json output. Its accessors handle
+fields the CLI omitted and convert units:
+
+	result, err := types.ParseResultMessage(line)
+	if err == nil && result.Succeeded() {
+		log.Printf("cost $%.4f in %s", result.Cost(), result.Duration())
+	}
+
+# Control Protocol Types
+
+ControlRequest describes a request sent to a running CLI process, and
+ControlResponse its answer, decoded from a control_response frame with
+ParseControlResponse. InterruptAck, SetModelAck and PermissionModeAck are the
+acknowledgements of the requests the SDK models.
+
 # Tool Types
 
 Tool definitions for Claude Code's built-in and MCP tools:
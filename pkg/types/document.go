@@ -0,0 +1,123 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxDocumentAttachmentSize is the largest document sent as an attachment, in
+// bytes. It is the request size limit for PDFs.
+const MaxDocumentAttachmentSize = 32 * 1024 * 1024
+
+// DocumentChunkSize is the largest text sent in a single document block.
+// Longer text documents are split into several blocks at line boundaries.
+const DocumentChunkSize = 256 * 1024
+
+// NewDocumentBlock creates a document content block. PDFs are sent base64
+// encoded; other media types are sent as plain text.
+func NewDocumentBlock(title, mediaType string, data []byte) ContentBlock {
+	if mediaType == "application/pdf" {
+		return ContentBlock{
+			Type:  "document",
+			Title: title,
+			Source: &ContentSource{
+				Type:      ContentSourceBase64,
+				MediaType: mediaType,
+				Data:      base64.StdEncoding.EncodeToString(data),
+			},
+		}
+	}
+	return ContentBlock{
+		Type:   "document",
+		Title:  title,
+		Source: &ContentSource{Type: ContentSourceText, MediaType: "text/plain", Data: string(data)},
+	}
+}
+
+// ChunkDocument splits text into document blocks of at most chunkSize bytes,
+// breaking at line boundaries where possible. When there is more than one
+// block, each title is suffixed with its part number.
+func ChunkDocument(title string, text []byte, chunkSize int) []ContentBlock {
+	if chunkSize <= 0 {
+		chunkSize = DocumentChunkSize
+	}
+
+	var chunks [][]byte
+	for len(text) > chunkSize {
+		end := bytes.LastIndexByte(text[:chunkSize], '\n') + 1
+		if end == 0 {
+			// No line break; split between runes
+			end = chunkSize
+			for end > 0 && !utf8.RuneStart(text[end]) {
+				end--
+			}
+			if end == 0 {
+				end = chunkSize
+			}
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	if len(text) > 0 || len(chunks) == 0 {
+		chunks = append(chunks, text)
+	}
+
+	blocks := make([]ContentBlock, len(chunks))
+	for i, chunk := range chunks {
+		partTitle := title
+		if len(chunks) > 1 {
+			partTitle = fmt.Sprintf("%s (part %d of %d)", title, i+1, len(chunks))
+		}
+		blocks[i] = NewDocumentBlock(partTitle, "text/plain", chunk)
+	}
+	return blocks
+}
+
+// NewDocumentAttachment attaches the PDF or text document at path, read when
+// the message is sent.
+func NewDocumentAttachment(path string) Attachment {
+	return Attachment{Type: AttachmentTypeDocument, Name: filepath.Base(path), Path: path}
+}
+
+// ContentBlocks converts the attachment to the content blocks sent to Claude.
+// Documents become document blocks, with text documents longer than
+// DocumentChunkSize split across several; other attachments become the
+// single block returned by ContentBlock.
+func (a *Attachment) ContentBlocks(baseDir string) ([]ContentBlock, error) {
+	if a.Type != AttachmentTypeDocument {
+		block, err := a.ContentBlock(baseDir)
+		if err != nil {
+			return nil, err
+		}
+		return []ContentBlock{block}, nil
+	}
+
+	data, err := a.load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxDocumentAttachmentSize {
+		return nil, a.invalid(fmt.Sprintf("document is %d bytes, the limit is %d", len(data), MaxDocumentAttachmentSize))
+	}
+
+	title := a.Name
+	if title == "" {
+		title = filepath.Base(a.Path)
+	}
+
+	mediaType := a.mediaType(data)
+	switch {
+	case mediaType == "application/pdf":
+		return []ContentBlock{NewDocumentBlock(title, mediaType, data)}, nil
+	case strings.HasPrefix(mediaType, "text/") || utf8.Valid(data):
+		if !utf8.Valid(data) {
+			return nil, a.invalid("document is not UTF-8 text")
+		}
+		return ChunkDocument(title, data, DocumentChunkSize), nil
+	}
+	return nil, a.invalid("unsupported document type " + mediaType)
+}
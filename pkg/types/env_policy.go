@@ -0,0 +1,221 @@
+package types
+
+import (
+	"path"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// DefaultEnvAllowlist lists the host environment variables passed to the CLI
+// subprocess when no policy widens it. Entries may use glob patterns, and on
+// Windows, where variables are spelled "Path" or "windir", names match
+// regardless of case.
+var DefaultEnvAllowlist = []string{
+	// Process basics
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "TZ",
+	"LANG", "LANGUAGE", "LC_*", "TMPDIR", "TMP", "TEMP", "XDG_*",
+
+	// Windows process basics
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT",
+	"USERPROFILE", "APPDATA", "LOCALAPPDATA", "PROGRAMDATA", "PROGRAMFILES",
+
+	// Claude configuration and credentials
+	"ANTHROPIC_*", "CLAUDE_*",
+
+	// Network configuration
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+	"NODE_EXTRA_CA_CERTS", "SSL_CERT_FILE", "SSL_CERT_DIR",
+}
+
+// BedrockEnvAllowlist lists the AWS credentials and settings passed along
+// with the defaults when CLAUDE_CODE_USE_BEDROCK is set.
+var BedrockEnvAllowlist = []string{"AWS_*"}
+
+// VertexEnvAllowlist lists the Google Cloud credentials and settings passed
+// along with the defaults when CLAUDE_CODE_USE_VERTEX is set.
+var VertexEnvAllowlist = []string{
+	"GOOGLE_APPLICATION_CREDENTIALS", "GOOGLE_CLOUD_PROJECT", "GCLOUD_PROJECT",
+	"CLOUDSDK_*", "CLOUD_ML_REGION", "VERTEX_REGION_*",
+}
+
+// EnvPolicy controls which host environment variables reach the CLI
+// subprocess and, through it, any MCP servers it starts. Variables set in
+// ClaudeCodeConfig.Environment and the configured API key are always passed.
+//
+// Names are matched exactly or as glob patterns ("AWS_*"), ignoring case on
+// Windows; regex entries match anywhere in the name unless anchored. Deny
+// rules take precedence over allow rules. Unless NoDefaults is set, the
+// Bedrock or Vertex AI credentials are allowed too when the environment
+// selects that provider.
+//
+// Example usage:
+//
+//	config.EnvPolicy = &types.EnvPolicy{
+//		Allow:     []string{"AWS_*", "GOPATH"},
+//		DenyRegex: []string{"(?i)secret|token"},
+//	}
+type EnvPolicy struct {
+	// InheritAll passes the whole host environment except denied variables
+	InheritAll bool `json:"inherit_all,omitempty"`
+
+	// NoDefaults drops DefaultEnvAllowlist, passing only Allow and AllowRegex matches
+	NoDefaults bool `json:"no_defaults,omitempty"`
+
+	// Allow lists additional variable names or glob patterns to pass
+	Allow []string `json:"allow,omitempty"`
+
+	// AllowRegex lists additional regular expressions for names to pass
+	AllowRegex []string `json:"allow_regex,omitempty"`
+
+	// Deny lists variable names or glob patterns that are never passed
+	Deny []string `json:"deny,omitempty"`
+
+	// DenyRegex lists regular expressions for names that are never passed
+	DenyRegex []string `json:"deny_regex,omitempty"`
+}
+
+// Validate checks that the regular expressions compile.
+func (p *EnvPolicy) Validate() error {
+	_, err := p.compile()
+	return err
+}
+
+// Filter returns the entries of environ ("NAME=value") the policy allows. A nil
+// policy applies the default allowlist.
+func (p *EnvPolicy) Filter(environ []string) ([]string, error) {
+	return p.FilterWith(environ, nil)
+}
+
+// FilterWith is Filter for a subprocess that is also given the variables in
+// overrides, which are not filtered but, like environ, can select the Bedrock
+// or Vertex AI provider.
+func (p *EnvPolicy) FilterWith(environ, overrides []string) ([]string, error) {
+	matcher, err := p.compile()
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || !p.NoDefaults {
+		if providerSelected(environ, overrides, "CLAUDE_CODE_USE_BEDROCK", matcher.foldCase) {
+			matcher.allow = append(matcher.allow, BedrockEnvAllowlist...)
+		}
+		if providerSelected(environ, overrides, "CLAUDE_CODE_USE_VERTEX", matcher.foldCase) {
+			matcher.allow = append(matcher.allow, VertexEnvAllowlist...)
+		}
+	}
+
+	filtered := make([]string, 0, len(environ))
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if name != "" && matcher.allows(name) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
+
+// Allows reports whether the policy passes the named variable.
+func (p *EnvPolicy) Allows(name string) (bool, error) {
+	matcher, err := p.compile()
+	if err != nil {
+		return false, err
+	}
+	return matcher.allows(name), nil
+}
+
+// envMatcher is a compiled EnvPolicy.
+type envMatcher struct {
+	// foldCase matches names regardless of case, as Windows does
+	foldCase bool
+
+	inheritAll bool
+	allow      []string
+	allowRegex []*regexp.Regexp
+	deny       []string
+	denyRegex  []*regexp.Regexp
+}
+
+func (p *EnvPolicy) compile() (*envMatcher, error) {
+	foldCase := runtime.GOOS == "windows"
+	if p == nil {
+		return &envMatcher{foldCase: foldCase, allow: append([]string(nil), DefaultEnvAllowlist...)}, nil
+	}
+
+	matcher := &envMatcher{foldCase: foldCase, inheritAll: p.InheritAll, deny: p.Deny}
+	if !p.NoDefaults {
+		matcher.allow = append(matcher.allow, DefaultEnvAllowlist...)
+	}
+	matcher.allow = append(matcher.allow, p.Allow...)
+
+	var err error
+	if matcher.allowRegex, err = compileEnvRegex("env_policy.allow_regex", p.AllowRegex); err != nil {
+		return nil, err
+	}
+	if matcher.denyRegex, err = compileEnvRegex("env_policy.deny_regex", p.DenyRegex); err != nil {
+		return nil, err
+	}
+	return matcher, nil
+}
+
+func compileEnvRegex(field string, patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, &ValidationError{
+				Field:   field,
+				Message: "invalid regular expression " + pattern + ": " + err.Error(),
+			}
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func (m *envMatcher) allows(name string) bool {
+	if matchEnvName(m.deny, name, m.foldCase) || matchEnvRegex(m.denyRegex, name) {
+		return false
+	}
+	return m.inheritAll || matchEnvName(m.allow, name, m.foldCase) || matchEnvRegex(m.allowRegex, name)
+}
+
+func matchEnvName(patterns []string, name string, foldCase bool) bool {
+	for _, pattern := range patterns {
+		if pattern == name || foldCase && strings.EqualFold(pattern, name) {
+			return true
+		}
+		glob, subject := pattern, name
+		if foldCase {
+			glob, subject = strings.ToUpper(pattern), strings.ToUpper(name)
+		}
+		if matched, _ := path.Match(glob, subject); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// providerSelected reports whether a variable selecting a model provider,
+// such as CLAUDE_CODE_USE_BEDROCK, is set to a true value.
+func providerSelected(environ, overrides []string, variable string, foldCase bool) bool {
+	selected := false
+	for _, entries := range [][]string{environ, overrides} {
+		for _, entry := range entries {
+			name, value, _ := strings.Cut(entry, "=")
+			if name == variable || foldCase && strings.EqualFold(name, variable) {
+				// Later entries, and overrides, win
+				selected = value != "" && value != "0" && !strings.EqualFold(value, "false")
+			}
+		}
+	}
+	return selected
+}
+
+func matchEnvRegex(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package types
+
+import "time"
+
+// FaultConfig injects failures into the output of every CLI process, so
+// retry and recovery logic can be tested against the failures seen in
+// production: lost output, slow output, a process killed mid-conversation
+// and truncated JSON. Faults apply to real, replayed and scripted processes
+// alike.
+//
+// Which lines are dropped or corrupted is chosen by a random source seeded
+// with Seed for each process, so a failing run can be reproduced.
+//
+// Example usage:
+//
+//	config.Faults = &types.FaultConfig{
+//		DropRate:       0.1,
+//		KillAfterTurns: 2,
+//		Seed:           7,
+//	}
+type FaultConfig struct {
+	// DropRate is the fraction of output lines silently dropped (0 to 1)
+	DropRate float64 `json:"drop_rate,omitempty"`
+
+	// Delay holds back each output line
+	Delay time.Duration `json:"delay,omitempty"`
+
+	// KillAfterTurns kills the process once it has written this many
+	// assistant turns (0 never kills it)
+	KillAfterTurns int `json:"kill_after_turns,omitempty"`
+
+	// CorruptRate is the fraction of JSON output lines truncated to invalid
+	// JSON (0 to 1)
+	CorruptRate float64 `json:"corrupt_rate,omitempty"`
+
+	// Seed seeds the choice of dropped and corrupted lines
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Validate checks that the rates are fractions and the other values are not
+// negative.
+func (f *FaultConfig) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	if f.DropRate < 0 || f.DropRate > 1 {
+		return &ValidationError{
+			Field:   "faults.drop_rate",
+			Value:   f.DropRate,
+			Message: "drop rate must be between 0 and 1",
+		}
+	}
+	if f.CorruptRate < 0 || f.CorruptRate > 1 {
+		return &ValidationError{
+			Field:   "faults.corrupt_rate",
+			Value:   f.CorruptRate,
+			Message: "corrupt rate must be between 0 and 1",
+		}
+	}
+	if f.Delay < 0 {
+		return &ValidationError{
+			Field:   "faults.delay",
+			Value:   f.Delay,
+			Message: "delay cannot be negative",
+		}
+	}
+	if f.KillAfterTurns < 0 {
+		return &ValidationError{
+			Field:   "faults.kill_after_turns",
+			Value:   f.KillAfterTurns,
+			Message: "kill after turns cannot be negative",
+		}
+	}
+	return nil
+}
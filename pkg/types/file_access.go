@@ -0,0 +1,50 @@
+package types
+
+import "time"
+
+// FileOp is the kind of file access reported by a FileAccessEvent.
+type FileOp string
+
+const (
+	// FileOpRead is a file read (Read, NotebookRead)
+	FileOpRead FileOp = "read"
+
+	// FileOpWrite is a whole-file write (Write)
+	FileOpWrite FileOp = "write"
+
+	// FileOpEdit is an in-place edit (Edit, MultiEdit, NotebookEdit)
+	FileOpEdit FileOp = "edit"
+)
+
+// FileAccessEvent reports a file Claude read or changed through a tool,
+// for "files touched" views and compliance logs. Events are reported when the
+// tool's result arrives.
+type FileAccessEvent struct {
+	// Path is the absolute, cleaned path of the file
+	Path string `json:"path"`
+
+	// Op is the kind of access
+	Op FileOp `json:"op"`
+
+	// Bytes is the size of the content written, the new text of an edit, or
+	// the content returned by a read
+	Bytes int `json:"bytes"`
+
+	// SessionID is the CLI session the access happened in
+	SessionID string `json:"session_id,omitempty"`
+
+	// Tool is the tool that accessed the file, e.g. "Edit"
+	Tool string `json:"tool"`
+
+	// ToolUseID identifies the tool invocation
+	ToolUseID string `json:"tool_use_id,omitempty"`
+
+	// Failed reports whether the tool returned an error
+	Failed bool `json:"failed,omitempty"`
+
+	// Time is when the result was observed
+	Time time.Time `json:"time"`
+
+	// Metadata is the metadata attached to the query
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// GatewayConfig routes the CLI's API traffic through an LLM gateway, such as
+// a corporate proxy service that logs requests and enforces policy.
+//
+// Example usage:
+//
+//	config.Gateway = &types.GatewayConfig{
+//		BaseURL:   "https://llm-gateway.corp.example/anthropic",
+//		AuthToken: os.Getenv("GATEWAY_TOKEN"),
+//		Headers:   map[string]string{"X-Team": "platform"},
+//		TLS:       &types.TLSConfig{CertFile: "/etc/certs/client.pem", KeyFile: "/etc/certs/client.key"},
+//	}
+type GatewayConfig struct {
+	// BaseURL replaces the Anthropic API endpoint (ANTHROPIC_BASE_URL)
+	BaseURL string `json:"base_url"`
+
+	// AuthToken is sent as a bearer token instead of the API key
+	// (ANTHROPIC_AUTH_TOKEN)
+	AuthToken string `json:"auth_token,omitempty"`
+
+	// Headers are added to every API request (ANTHROPIC_CUSTOM_HEADERS)
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// TLS sets the client certificate and verification for the gateway
+	// connection. The CLI supports CertFile, KeyFile, CAFile and
+	// InsecureSkipVerify.
+	TLS *TLSConfig `json:"tls,omitempty"`
+}
+
+// Validate checks that the base URL is an absolute http or https URL, that
+// headers can be sent, that the TLS options are ones the CLI supports, and
+// that the CA file holds PEM certificates. A nil config is valid.
+func (g *GatewayConfig) Validate() error {
+	if g == nil {
+		return nil
+	}
+
+	u, err := url.Parse(g.BaseURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return &ValidationError{Field: "gateway.base_url", Message: "gateway base URL must be an absolute http or https URL", Value: g.BaseURL}
+	}
+
+	for name, value := range g.Headers {
+		if name == "" || strings.ContainsAny(name, ": \t\r\n") {
+			return &ValidationError{Field: "gateway.headers", Message: "invalid header name", Value: name}
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return &ValidationError{Field: "gateway.headers." + name, Message: "header values cannot contain line breaks"}
+		}
+	}
+
+	if tls := g.TLS; tls != nil {
+		if tls.ServerName != "" || tls.MinVersion != 0 || tls.MaxVersion != 0 {
+			return &ValidationError{Field: "gateway.tls", Message: "the CLI does not support server_name, min_version or max_version"}
+		}
+		if (tls.CertFile == "") != (tls.KeyFile == "") {
+			return &ValidationError{Field: "gateway.tls", Message: "cert_file and key_file must be set together"}
+		}
+		if tls.CAFile != "" {
+			if err := validateCertificateFile("gateway.tls.ca_file", tls.CAFile); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Env returns the variables that point the CLI at the gateway. A nil config
+// returns nil.
+func (g *GatewayConfig) Env() []string {
+	if g == nil {
+		return nil
+	}
+
+	env := []string{"ANTHROPIC_BASE_URL=" + g.BaseURL}
+	if g.AuthToken != "" {
+		env = append(env, "ANTHROPIC_AUTH_TOKEN="+g.AuthToken)
+	}
+
+	if len(g.Headers) > 0 {
+		names := make([]string, 0, len(g.Headers))
+		for name := range g.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		headers := make([]string, len(names))
+		for i, name := range names {
+			headers[i] = name + ": " + g.Headers[name]
+		}
+		env = append(env, "ANTHROPIC_CUSTOM_HEADERS="+strings.Join(headers, "\n"))
+	}
+
+	if tls := g.TLS; tls != nil {
+		if tls.InsecureSkipVerify {
+			env = append(env, "NODE_TLS_REJECT_UNAUTHORIZED=0")
+		}
+		if tls.CAFile != "" {
+			env = append(env, "NODE_EXTRA_CA_CERTS="+tls.CAFile)
+		}
+		if tls.CertFile != "" {
+			env = append(env, "CLAUDE_CODE_CLIENT_CERT="+tls.CertFile, "CLAUDE_CODE_CLIENT_KEY="+tls.KeyFile)
+		}
+	}
+	return env
+}
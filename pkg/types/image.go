@@ -0,0 +1,100 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ImageBlock is an image in CLI output, either in Claude's own message or
+// returned by a tool such as a browser or MCP screenshot tool.
+type ImageBlock struct {
+	// Source is ContentSourceBase64 for inline data or ContentSourceURL
+	Source string `json:"source"`
+
+	// MediaType is the MIME type of inline data, e.g. "image/png"
+	MediaType string `json:"media_type,omitempty"`
+
+	// Data is the base64-encoded image
+	Data string `json:"data,omitempty"`
+
+	// URL is the location of the image
+	URL string `json:"url,omitempty"`
+
+	// ToolUseID identifies the tool result holding the image; it is empty for
+	// images in Claude's messages
+	ToolUseID string `json:"tool_use_id,omitempty"`
+}
+
+// Image returns the image held by an image block.
+func (b *ContentBlock) Image() (*ImageBlock, bool) {
+	if b.Type != "image" || b.Source == nil {
+		return nil, false
+	}
+	return &ImageBlock{
+		Source:    b.Source.Type,
+		MediaType: b.Source.MediaType,
+		Data:      b.Source.Data,
+		URL:       b.Source.URL,
+	}, true
+}
+
+// ContentBlock converts the image back to a content block.
+func (i *ImageBlock) ContentBlock() ContentBlock {
+	return ContentBlock{
+		Type: "image",
+		Source: &ContentSource{
+			Type:      i.Source,
+			MediaType: i.MediaType,
+			Data:      i.Data,
+			URL:       i.URL,
+		},
+	}
+}
+
+// Bytes decodes the image's inline data.
+func (i *ImageBlock) Bytes() ([]byte, error) {
+	block := i.ContentBlock()
+	return block.Bytes()
+}
+
+// DataURL returns a URL a browser or GUI toolkit can render: a data URL for
+// inline images, otherwise the image's URL.
+func (i *ImageBlock) DataURL() string {
+	if i.Source != ContentSourceBase64 {
+		return i.URL
+	}
+	return "data:" + i.MediaType + ";base64," + i.Data
+}
+
+// ParseImages returns the images in a line of CLI stream-json output, from
+// message content and from tool results within it, in order. It returns nil
+// if the line holds no images.
+func ParseImages(line []byte) []ImageBlock {
+	var raw struct {
+		Message *struct {
+			Content []ContentBlock `json:"content"`
+		} `json:"message"`
+	}
+
+	if !bytes.Contains(line, []byte(`"image"`)) {
+		return nil
+	}
+	if err := json.Unmarshal(line, &raw); err != nil || raw.Message == nil {
+		return nil
+	}
+
+	var images []ImageBlock
+	for i := range raw.Message.Content {
+		block := &raw.Message.Content[i]
+		if image, ok := block.Image(); ok {
+			images = append(images, *image)
+		}
+		for j := range block.Content {
+			if image, ok := block.Content[j].Image(); ok {
+				image.ToolUseID = block.ToolUseID
+				images = append(images, *image)
+			}
+		}
+	}
+	return images
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// MCPServerState is the connection state of an MCP server.
+type MCPServerState string
+
+const (
+	// MCPServerConnected means the server is running and serving tools
+	MCPServerConnected MCPServerState = "connected"
+
+	// MCPServerFailed means the CLI could not start or connect to the server
+	MCPServerFailed MCPServerState = "failed"
+
+	// MCPServerNeedsAuth means the server requires authentication
+	MCPServerNeedsAuth MCPServerState = "needs-auth"
+
+	// MCPServerPending means the CLI is still connecting to the server
+	MCPServerPending MCPServerState = "pending"
+
+	// MCPServerDisabled means the server is configured but disabled
+	MCPServerDisabled MCPServerState = "disabled"
+
+	// MCPServerUnknown means no status has been reported for the server yet
+	MCPServerUnknown MCPServerState = "unknown"
+)
+
+// MCPServerStatus reports the health of an MCP server.
+type MCPServerStatus struct {
+	// Name is the server name
+	Name string `json:"name"`
+
+	// State is the connection state
+	State MCPServerState `json:"state"`
+
+	// ToolCount is the number of tools the server provides (if reported)
+	ToolCount int `json:"tool_count"`
+
+	// LastError describes the most recent failure, if any
+	LastError string `json:"last_error,omitempty"`
+
+	// Latency is how long the last health check took
+	Latency time.Duration `json:"latency,omitempty"`
+
+	// CheckedAt is when the status was last updated
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Healthy reports whether the server is connected.
+func (s MCPServerStatus) Healthy() bool {
+	return s.State == MCPServerConnected
+}
+
+// Unhealthy reports whether the server has failed or needs authentication.
+func (s MCPServerStatus) Unhealthy() bool {
+	return s.State == MCPServerFailed || s.State == MCPServerNeedsAuth
+}
+
+// SystemInitMessage is the "system"/"init" message the CLI emits at the start
+// of stream-json output.
+type SystemInitMessage struct {
+	// SessionID is the CLI session
+	SessionID string `json:"session_id,omitempty"`
+
+	// Model is the model serving the session
+	Model string `json:"model,omitempty"`
+
+	// Tools lists the available tools; MCP tools are named mcp__<server>__<tool>
+	Tools []string `json:"tools,omitempty"`
+
+	// MCPServers reports the status of each MCP server
+	MCPServers []SystemInitMCPServer `json:"mcp_servers,omitempty"`
+}
+
+// SystemInitMCPServer is an MCP server entry in a SystemInitMessage.
+type SystemInitMCPServer struct {
+	// Name is the server name
+	Name string `json:"name"`
+
+	// Status is the CLI's status string, e.g. "connected" or "failed"
+	Status string `json:"status"`
+}
+
+// ParseSystemInit parses a line of CLI stream-json output into a
+// SystemInitMessage. It returns false if the line is not an init message.
+func ParseSystemInit(line []byte) (*SystemInitMessage, bool) {
+	var raw struct {
+		Type    string `json:"type"`
+		Subtype string `json:"subtype"`
+		SystemInitMessage
+	}
+
+	if !bytes.Contains(line, []byte(`"init"`)) {
+		return nil, false
+	}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil, false
+	}
+	if raw.Type != "system" || raw.Subtype != "init" {
+		return nil, false
+	}
+
+	return &raw.SystemInitMessage, true
+}
@@ -0,0 +1,12 @@
+package types
+
+import "context"
+
+// MemoryRecaller finds remembered facts relevant to a prompt, such as a
+// memory.Manager over a persistent store. When one is configured, the client
+// adds the facts recalled for the first prompt of each new session to its
+// system prompt.
+type MemoryRecaller interface {
+	// Recall returns up to limit facts relevant to text, best first
+	Recall(ctx context.Context, text string, limit int) ([]string, error)
+}
@@ -153,6 +153,9 @@ type Attachment struct {
 	// Data contains the raw attachment data (for small attachments)
 	Data []byte `json:"data,omitempty"`
 
+	// Path is a local file read when the message is sent, if Data is empty
+	Path string `json:"path,omitempty"`
+
 	// MimeType is the MIME type of the attachment
 	MimeType string `json:"mime_type,omitempty"`
 
@@ -340,6 +343,12 @@ type ContentBlock struct {
 	ToolUseID string         `json:"tool_use_id,omitempty"` // For tool_result blocks
 	Content   []ContentBlock `json:"content,omitempty"`     // For tool_result blocks
 	IsError   bool           `json:"is_error,omitempty"`    // For tool_result blocks
+
+	// Source holds the data of image and document blocks
+	Source *ContentSource `json:"source,omitempty"`
+
+	// Title names the document of document blocks
+	Title string `json:"title,omitempty"`
 }
 
 // NewTextBlock creates a new text content block
@@ -0,0 +1,206 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// AnyMessage is implemented by every message shape in the SDK: Message,
+// used by Query requests and QueryMessages; SessionMessage, used by session
+// history; StreamMessage, used by StreamQuery; and QueryResponse. Code
+// written against AnyMessage works whichever entry point produced the
+// message, and ToMessage and ToSessionMessage convert between the shapes.
+type AnyMessage interface {
+	// MessageRole returns who sent the message
+	MessageRole() Role
+
+	// ContentBlocks returns the message content as blocks, including tool
+	// calls and tool results
+	ContentBlocks() []ContentBlock
+}
+
+var (
+	_ AnyMessage = (*Message)(nil)
+	_ AnyMessage = (*SessionMessage)(nil)
+	_ AnyMessage = (*StreamMessage)(nil)
+	_ AnyMessage = (*QueryResponse)(nil)
+)
+
+// ParseRole converts a role name, in any case, to a Role. "human" is
+// accepted for RoleUser.
+func ParseRole(name string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(name)))
+	if role == "human" {
+		role = RoleUser
+	}
+	if !role.IsValid() {
+		return "", &ValidationError{Field: "role", Message: "role must be user, assistant, system or tool", Value: name}
+	}
+	return role, nil
+}
+
+// String returns the role name.
+func (r Role) String() string {
+	return string(r)
+}
+
+// MessageRole implements AnyMessage.
+func (m *Message) MessageRole() Role {
+	return m.Role
+}
+
+// ContentBlocks implements AnyMessage. A tool message with a ToolCallID
+// becomes a tool_result block; otherwise Content becomes a text block,
+// followed by a tool_use block for each tool call.
+func (m *Message) ContentBlocks() []ContentBlock {
+	if m.Role == RoleTool && m.ToolCallID != "" {
+		return []ContentBlock{NewToolResultBlock(m.ToolCallID, []ContentBlock{NewTextBlock(m.Content)}, false)}
+	}
+
+	var blocks []ContentBlock
+	if m.Content != "" {
+		blocks = append(blocks, NewTextBlock(m.Content))
+	}
+	for _, call := range m.ToolCalls {
+		blocks = append(blocks, ToolUseBlock(call))
+	}
+	return blocks
+}
+
+// MessageRole implements AnyMessage.
+func (m *SessionMessage) MessageRole() Role {
+	return m.Role
+}
+
+// ContentBlocks implements AnyMessage, adding blocks for the tool calls and
+// results not already in Content.
+func (m *SessionMessage) ContentBlocks() []ContentBlock {
+	seen := make(map[string]bool)
+	for _, block := range m.Content {
+		switch block.Type {
+		case "tool_use":
+			seen["use:"+block.ID] = true
+		case "tool_result":
+			seen["result:"+block.ToolUseID] = true
+		}
+	}
+
+	blocks := append([]ContentBlock(nil), m.Content...)
+	for _, call := range m.ToolCalls {
+		if !seen["use:"+call.ID] {
+			blocks = append(blocks, ToolUseBlock(call))
+		}
+	}
+	for _, result := range m.ToolResults {
+		if !seen["result:"+result.ToolUseID] {
+			blocks = append(blocks, NewToolResultBlock(result.ToolUseID, result.Content, result.IsError))
+		}
+	}
+	return blocks
+}
+
+// MessageRole implements AnyMessage.
+func (m *StreamMessage) MessageRole() Role {
+	return m.Role
+}
+
+// ContentBlocks implements AnyMessage.
+func (m *StreamMessage) ContentBlocks() []ContentBlock {
+	return m.Content
+}
+
+// MessageRole implements AnyMessage. Responses without a role are from the
+// assistant.
+func (r *QueryResponse) MessageRole() Role {
+	if r.Role == "" {
+		return RoleAssistant
+	}
+	return r.Role
+}
+
+// ContentBlocks implements AnyMessage.
+func (r *QueryResponse) ContentBlocks() []ContentBlock {
+	return r.Content
+}
+
+// ToMessage converts any message to a Message. Text blocks are joined into
+// Content and tool_use blocks become ToolCalls. A tool result sets
+// ToolCallID, with its text appended to Content.
+func ToMessage(m AnyMessage) Message {
+	message := Message{Role: m.MessageRole()}
+
+	var text []string
+	for _, block := range m.ContentBlocks() {
+		switch block.Type {
+		case "text":
+			text = append(text, block.Text)
+		case "tool_use":
+			message.ToolCalls = append(message.ToolCalls, ToolCallFromBlock(block))
+		case "tool_result":
+			if message.ToolCallID == "" {
+				message.ToolCallID = block.ToolUseID
+			}
+			for _, inner := range block.Content {
+				if inner.Type == "text" {
+					text = append(text, inner.Text)
+				}
+			}
+		}
+	}
+	message.Content = strings.Join(text, "")
+	return message
+}
+
+// ToSessionMessage converts any message to a SessionMessage. Content keeps
+// every block, and ToolCalls and ToolResults list the tool blocks.
+func ToSessionMessage(m AnyMessage) SessionMessage {
+	message := SessionMessage{Role: m.MessageRole(), Content: m.ContentBlocks()}
+	for _, block := range message.Content {
+		switch block.Type {
+		case "tool_use":
+			message.ToolCalls = append(message.ToolCalls, ToolCallFromBlock(block))
+		case "tool_result":
+			message.ToolResults = append(message.ToolResults, ToolResult{
+				ToolUseID: block.ToolUseID,
+				IsError:   block.IsError,
+				Content:   block.Content,
+				Success:   !block.IsError,
+			})
+		}
+	}
+	return message
+}
+
+// ToolCallFromBlock converts a tool_use block to a ToolCall. Blocks that
+// hold the call in Data, as decoded by some parsers, are supported too.
+func ToolCallFromBlock(block ContentBlock) ToolCall {
+	call := ToolCall{ID: block.ID, Type: "function", Function: FunctionCall{Name: block.Name}}
+	input := any(block.Input)
+	if block.Input == nil {
+		if data, ok := block.Data.(map[string]any); ok {
+			if id, ok := data["id"].(string); ok {
+				call.ID = id
+			}
+			if name, ok := data["name"].(string); ok {
+				call.Function.Name = name
+			}
+			input = data["input"]
+		}
+	}
+	if input != nil {
+		if arguments, err := json.Marshal(input); err == nil {
+			call.Function.Arguments = string(arguments)
+		}
+	}
+	return call
+}
+
+// ToolUseBlock converts a ToolCall to a tool_use block. Arguments that are
+// not a JSON object leave the block's Input empty.
+func ToolUseBlock(call ToolCall) ContentBlock {
+	input := call.Function.ParsedArguments
+	if input == nil && call.Function.Arguments != "" {
+		_ = json.Unmarshal([]byte(call.Function.Arguments), &input) // Ignore error, the input is left empty
+	}
+	return NewToolUseBlock(call.ID, call.Function.Name, input)
+}
@@ -0,0 +1,202 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRole(t *testing.T) {
+	for name, want := range map[string]Role{"user": RoleUser, " Assistant ": RoleAssistant, "HUMAN": RoleUser, "tool": RoleTool} {
+		if role, err := ParseRole(name); err != nil || role != want {
+			t.Errorf("ParseRole(%q) = %q, %v; want %q", name, role, err, want)
+		}
+	}
+	if _, err := ParseRole("bot"); err == nil {
+		t.Error("Expected an unknown role to be rejected")
+	}
+}
+
+func TestAnyMessage_Conversions(t *testing.T) {
+	response := &QueryResponse{Content: []ContentBlock{
+		NewTextBlock("Reading "),
+		NewTextBlock("the file."),
+		NewToolUseBlock("toolu_1", "Read", map[string]any{"file_path": "main.go"}),
+	}}
+
+	message := ToMessage(response)
+	if message.Role != RoleAssistant || message.Content != "Reading the file." {
+		t.Errorf("Unexpected message: %+v", message)
+	}
+	if len(message.ToolCalls) != 1 || message.ToolCalls[0].ID != "toolu_1" ||
+		message.ToolCalls[0].Function.Name != "Read" || message.ToolCalls[0].Function.Arguments != `{"file_path":"main.go"}` {
+		t.Errorf("Unexpected tool calls: %+v", message.ToolCalls)
+	}
+
+	// Converting back yields the same blocks, merged text aside
+	blocks := message.ContentBlocks()
+	if len(blocks) != 2 || blocks[0].Text != "Reading the file." || blocks[1].Name != "Read" || blocks[1].Input["file_path"] != "main.go" {
+		t.Errorf("Unexpected blocks: %+v", blocks)
+	}
+
+	session := ToSessionMessage(&message)
+	if session.Role != RoleAssistant || len(session.Content) != 2 || len(session.ToolCalls) != 1 {
+		t.Errorf("Unexpected session message: %+v", session)
+	}
+
+	// Tool results round-trip through the tool role
+	result := &SessionMessage{
+		Role:        RoleUser,
+		ToolResults: []ToolResult{{ToolUseID: "toolu_1", Content: []ContentBlock{NewTextBlock("package main")}}},
+	}
+	toolMessage := ToMessage(result)
+	if toolMessage.ToolCallID != "toolu_1" || toolMessage.Content != "package main" {
+		t.Errorf("Unexpected tool result message: %+v", toolMessage)
+	}
+	toolMessage.Role = RoleTool
+	if blocks := toolMessage.ContentBlocks(); len(blocks) != 1 || blocks[0].Type != "tool_result" || blocks[0].ToolUseID != "toolu_1" {
+		t.Errorf("Unexpected tool result blocks: %+v", blocks)
+	}
+}
+
+func TestQueryResponse_GetToolCalls(t *testing.T) {
+	response := &QueryResponse{Content: []ContentBlock{
+		NewToolUseBlock("toolu_1", "Bash", map[string]any{"command": "ls"}),
+		{Type: "tool_use", Data: map[string]any{"id": "toolu_2", "name": "Read", "input": map[string]any{"file_path": "a.go"}}},
+	}}
+
+	calls := response.GetToolCalls()
+	if len(calls) != 2 || calls[0].Function.Name != "Bash" || calls[1].ID != "toolu_2" || calls[1].Function.Arguments != `{"file_path":"a.go"}` {
+		t.Errorf("Unexpected tool calls: %+v", calls)
+	}
+}
+
+func TestWalkContent(t *testing.T) {
+	response := &QueryResponse{Content: []ContentBlock{
+		NewTextBlock("Listing files."),
+		NewToolUseBlock("toolu_1", "Bash", map[string]any{"command": "ls"}),
+		NewToolResultBlock("toolu_1", []ContentBlock{NewTextBlock("main.go")}, false),
+		NewToolResultBlock("toolu_2", []ContentBlock{NewTextBlock("secret")}, true),
+		{Type: "document", Title: "spec"},
+	}}
+
+	var visited []string
+	err := WalkContent(response, ContentVisitor{
+		Text:    func(text string) error { visited = append(visited, "text:"+text); return nil },
+		ToolUse: func(use ToolUse) error { visited = append(visited, "tool_use:"+use.Name); return nil },
+		ToolResult: func(block ContentBlock) error {
+			visited = append(visited, "tool_result:"+block.ToolUseID)
+			if block.IsError {
+				return ErrSkipContent
+			}
+			return nil
+		},
+		Other: func(block ContentBlock) error { visited = append(visited, block.Type); return nil },
+	})
+	if err != nil {
+		t.Fatalf("WalkContent failed: %v", err)
+	}
+	want := []string{"text:Listing files.", "tool_use:Bash", "tool_result:toolu_1", "text:main.go", "tool_result:toolu_2", "document"}
+	if len(visited) != len(want) {
+		t.Fatalf("Visited %v, want %v", visited, want)
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Fatalf("Visited %v, want %v", visited, want)
+		}
+	}
+
+	stop := errors.New("stop")
+	if err := WalkContent(response, ContentVisitor{ToolUse: func(ToolUse) error { return stop }}); err != stop {
+		t.Errorf("WalkContent error = %v, want the visitor's error", err)
+	}
+
+	if text := ExtractText(response); text != "Listing files." {
+		t.Errorf("ExtractText = %q", text)
+	}
+	if uses := ExtractToolUses(response); len(uses) != 1 || uses[0].ID != "toolu_1" || uses[0].Input["command"] != "ls" {
+		t.Errorf("ExtractToolUses = %+v", uses)
+	}
+	if blocks := FilterBlocks(Blocks(response.Content), BlockTypes("tool_result")); len(blocks) != 2 {
+		t.Errorf("FilterBlocks kept %d blocks, want 2", len(blocks))
+	}
+	masked := MapBlocks(response, func(block ContentBlock) (ContentBlock, bool) {
+		if block.Type != "text" {
+			return block, false
+		}
+		block.Text = strings.ToUpper(block.Text)
+		return block, true
+	})
+	if len(masked) != 1 || masked[0].Text != "LISTING FILES." || response.Content[0].Text != "Listing files." {
+		t.Errorf("Unexpected MapBlocks result: %+v", masked)
+	}
+}
+
+func TestAssistantMessageAccessors(t *testing.T) {
+	content := []ContentBlock{
+		NewTextBlock("I'll check "),
+		NewToolUseBlock("toolu_1", "Read", map[string]any{"file_path": "go.mod"}),
+		NewTextBlock("the module."),
+	}
+
+	for name, msg := range map[string]interface {
+		Text() string
+		ToolUses() []ToolUse
+		HasToolUse(name string) bool
+	}{
+		"QueryResponse": &QueryResponse{Content: content},
+		"StreamMessage": &StreamMessage{Role: RoleAssistant, Content: content},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if text := msg.Text(); text != "I'll check the module." {
+				t.Errorf("Text() = %q", text)
+			}
+			if uses := msg.ToolUses(); len(uses) != 1 || uses[0].Name != "Read" || uses[0].Input["file_path"] != "go.mod" {
+				t.Errorf("ToolUses() = %+v", uses)
+			}
+			if !msg.HasToolUse("Read") || !msg.HasToolUse("") || msg.HasToolUse("Bash") {
+				t.Error("Unexpected HasToolUse results")
+			}
+		})
+	}
+
+	var response *QueryResponse
+	if response.Text() != "" || response.ToolUses() != nil || response.HasToolUse("") {
+		t.Error("Expected a nil response to have no content")
+	}
+}
+
+func TestResultMessage(t *testing.T) {
+	line := `{"type":"result","subtype":"success","is_error":false,"result":"Done.","num_turns":2,"duration_ms":1500,"duration_api_ms":1200,"total_cost_usd":0.0125,"usage":{"input_tokens":10,"output_tokens":5},"session_id":"sess_1"}`
+	result, err := ParseResultMessage([]byte(line))
+	if err != nil {
+		t.Fatalf("ParseResultMessage() error = %v", err)
+	}
+	if !result.Succeeded() || result.Result != "Done." || result.SessionID != "sess_1" || result.NumTurns != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if result.Cost() != 0.0125 || result.Duration() != 1500*time.Millisecond || result.APIDuration() != 1200*time.Millisecond {
+		t.Errorf("Cost() = %v, Duration() = %v, APIDuration() = %v", result.Cost(), result.Duration(), result.APIDuration())
+	}
+	if result.Tokens().OutputTokens != 5 {
+		t.Errorf("Tokens() = %+v", result.Tokens())
+	}
+
+	failed, err := ParseResultMessage([]byte(`{"type":"result","subtype":"error_max_turns","is_error":true}`))
+	if err != nil {
+		t.Fatalf("ParseResultMessage() error = %v", err)
+	}
+	if failed.Succeeded() || failed.Cost() != 0 || failed.Duration() != 0 || failed.Tokens() != (TokenUsage{}) {
+		t.Errorf("Unexpected failed result: %+v", failed)
+	}
+
+	var missing *ResultMessage
+	if missing.Succeeded() || missing.Cost() != 0 || missing.Duration() != 0 || missing.APIDuration() != 0 {
+		t.Error("Expected a nil result to report nothing")
+	}
+
+	if _, err := ParseResultMessage([]byte(`{"type":"assistant"}`)); err == nil {
+		t.Error("Expected an error for a non-result line")
+	}
+}
@@ -0,0 +1,25 @@
+package types
+
+import "time"
+
+// PinnedSnippet is text pinned into the system prompt of every query, such as
+// a style guide or an interface definition the model should always see.
+type PinnedSnippet struct {
+	// Name identifies the snippet; pinning another snippet with the same name
+	// replaces it
+	Name string `json:"name"`
+
+	// Content is the pinned text
+	Content string `json:"content"`
+
+	// Source is the file the snippet was read from, empty for text pinned
+	// directly
+	Source string `json:"source,omitempty"`
+
+	// Tokens is the estimated token count of the snippet as it is sent,
+	// counted against PinnedContextMaxTokens
+	Tokens int `json:"tokens"`
+
+	// PinnedAt is when the snippet was pinned
+	PinnedAt time.Time `json:"pinned_at"`
+}
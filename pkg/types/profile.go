@@ -0,0 +1,86 @@
+package types
+
+import (
+	"strconv"
+	"time"
+)
+
+// Profile is a named preset of query settings, such as "fast-cheap",
+// "deep-review" or "ci", that teams can share across services. Empty fields
+// leave the query's own settings in place.
+//
+// Example usage:
+//
+//	config.Profiles = map[string]*types.Profile{
+//		"ci": {
+//			Model:          "claude-3-5-haiku-20241022",
+//			PermissionMode: "rejectEdits",
+//			AllowedTools:   []string{"Read", "Grep"},
+//			MaxTurns:       5,
+//		},
+//	}
+type Profile struct {
+	// Model is the Claude model to use
+	Model string `json:"model,omitempty"`
+
+	// PermissionMode is "ask", "acceptEdits" or "rejectEdits"
+	PermissionMode string `json:"permission_mode,omitempty"`
+
+	// AllowedTools restricts the tools Claude can use
+	AllowedTools []string `json:"allowed_tools,omitempty"`
+
+	// SystemPrompt is appended to the system prompt
+	SystemPrompt string `json:"system_prompt,omitempty"`
+
+	// MaxTurns limits the number of conversation turns
+	MaxTurns int `json:"max_turns,omitempty"`
+
+	// ToolTimeouts bounds individual tool executions
+	ToolTimeouts map[string]time.Duration `json:"tool_timeouts,omitempty"`
+
+	// ExtraArgs passes additional CLI flags
+	ExtraArgs map[string]*string `json:"extra_args,omitempty"`
+}
+
+// Validate checks the profile's settings. The name is used in error fields.
+func (p *Profile) Validate(name string) error {
+	field := "profiles." + name
+
+	if p == nil {
+		return &ValidationError{Field: field, Message: "profile cannot be empty"}
+	}
+
+	switch p.PermissionMode {
+	case "", "ask", "acceptEdits", "rejectEdits":
+	default:
+		return &ValidationError{
+			Field:   field + ".permission_mode",
+			Message: "permission_mode must be \"ask\", \"acceptEdits\" or \"rejectEdits\"",
+			Value:   p.PermissionMode,
+		}
+	}
+
+	if p.MaxTurns < 0 {
+		return &ValidationError{Field: field + ".max_turns", Message: "max_turns cannot be negative"}
+	}
+
+	for tool, timeout := range p.ToolTimeouts {
+		if timeout <= 0 {
+			return &ValidationError{
+				Field:   field + ".tool_timeouts." + tool,
+				Message: "tool timeouts must be positive",
+			}
+		}
+	}
+
+	for flag := range p.ExtraArgs {
+		if !validExtraArg(flag) {
+			return &ValidationError{
+				Field:   field + ".extra_args",
+				Message: "invalid CLI flag name " + strconv.Quote(flag),
+			}
+		}
+	}
+
+	return nil
+}
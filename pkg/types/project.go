@@ -0,0 +1,480 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DependencyEcosystem identifies the package ecosystem a dependency belongs to.
+type DependencyEcosystem string
+
+const (
+	// EcosystemGo indicates a Go module dependency (go.mod)
+	EcosystemGo DependencyEcosystem = "go"
+
+	// EcosystemNPM indicates an npm package dependency (package.json)
+	EcosystemNPM DependencyEcosystem = "npm"
+
+	// EcosystemPython indicates a Python package dependency (pyproject.toml)
+	EcosystemPython DependencyEcosystem = "python"
+)
+
+// DependencyKind describes how a dependency is related to the project.
+type DependencyKind string
+
+const (
+	// DependencyDirect is a dependency declared by the project itself
+	DependencyDirect DependencyKind = "direct"
+
+	// DependencyIndirect is a transitive dependency pulled in by another dependency
+	DependencyIndirect DependencyKind = "indirect"
+
+	// DependencyDev is a dependency only needed for development or testing
+	DependencyDev DependencyKind = "dev"
+
+	// DependencyOptional is an optional or extra dependency
+	DependencyOptional DependencyKind = "optional"
+)
+
+// Dependency describes a single package the project depends on.
+type Dependency struct {
+	// Name is the module or package name
+	Name string `json:"name"`
+
+	// Version is the resolved version, or the declared constraint if unresolved
+	Version string `json:"version,omitempty"`
+
+	// Constraint is the version constraint as declared in the manifest
+	Constraint string `json:"constraint,omitempty"`
+
+	// Ecosystem is the package ecosystem of the dependency
+	Ecosystem DependencyEcosystem `json:"ecosystem"`
+
+	// Kind describes whether the dependency is direct, indirect, dev, or optional
+	Kind DependencyKind `json:"kind"`
+
+	// License is the SPDX identifier or license name if it could be determined
+	License string `json:"license,omitempty"`
+
+	// Manifest is the manifest file that declared the dependency
+	Manifest string `json:"manifest"`
+
+	// Requires lists the names of dependencies this dependency requires (if known)
+	Requires []string `json:"requires,omitempty"`
+}
+
+// IsDirect returns true if the project declares the dependency itself.
+func (d *Dependency) IsDirect() bool {
+	return d.Kind != DependencyIndirect
+}
+
+// DependencyManifest describes a parsed manifest file.
+type DependencyManifest struct {
+	// Path is the manifest file path
+	Path string `json:"path"`
+
+	// Ecosystem is the package ecosystem of the manifest
+	Ecosystem DependencyEcosystem `json:"ecosystem"`
+
+	// Project is the module or package name declared by the manifest
+	Project string `json:"project,omitempty"`
+
+	// License is the license declared by the manifest for the project itself
+	License string `json:"license,omitempty"`
+}
+
+// DependencyGraph is a typed view of the project's dependencies across all manifests.
+type DependencyGraph struct {
+	// Manifests lists the manifest files that were parsed
+	Manifests []DependencyManifest `json:"manifests"`
+
+	// Dependencies lists all discovered dependencies
+	Dependencies []Dependency `json:"dependencies"`
+
+	// Errors contains messages for manifests that could not be parsed
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Direct returns the dependencies declared by the project itself.
+func (g *DependencyGraph) Direct() []Dependency {
+	var deps []Dependency
+	for _, dep := range g.Dependencies {
+		if dep.IsDirect() {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// ByEcosystem returns the dependencies that belong to the given ecosystem.
+func (g *DependencyGraph) ByEcosystem(ecosystem DependencyEcosystem) []Dependency {
+	var deps []Dependency
+	for _, dep := range g.Dependencies {
+		if dep.Ecosystem == ecosystem {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// Find returns the first dependency with the given name, or nil if not found.
+func (g *DependencyGraph) Find(name string) *Dependency {
+	for i := range g.Dependencies {
+		if g.Dependencies[i].Name == name {
+			return &g.Dependencies[i]
+		}
+	}
+	return nil
+}
+
+// GitCommit summarizes a single commit.
+type GitCommit struct {
+	// Hash is the full commit hash
+	Hash string `json:"hash"`
+
+	// Author is the commit author name
+	Author string `json:"author,omitempty"`
+
+	// Date is the author date of the commit
+	Date time.Time `json:"date"`
+
+	// Subject is the first line of the commit message
+	Subject string `json:"subject"`
+}
+
+// GitInfo describes the state of the git repository containing the project.
+type GitInfo struct {
+	// Root is the top-level directory of the repository
+	Root string `json:"root"`
+
+	// Branch is the current branch name, empty when HEAD is detached
+	Branch string `json:"branch,omitempty"`
+
+	// Head is the commit HEAD points at, nil in a repository without commits
+	Head *GitCommit `json:"head,omitempty"`
+
+	// Dirty is true when the working tree has uncommitted or untracked changes
+	Dirty bool `json:"dirty"`
+
+	// ChangedFiles lists paths with uncommitted or untracked changes
+	ChangedFiles []string `json:"changed_files,omitempty"`
+
+	// RecentCommits lists the most recent commits, newest first
+	RecentCommits []GitCommit `json:"recent_commits,omitempty"`
+
+	// RemoteURL is the URL of the "origin" remote (or the first remote)
+	RemoteURL string `json:"remote_url,omitempty"`
+}
+
+// Summary returns a short plain-text description of the repository state
+// suitable for inclusion in a prompt.
+func (g *GitInfo) Summary() string {
+	var b strings.Builder
+
+	branch := g.Branch
+	if branch == "" {
+		branch = "(detached HEAD)"
+	}
+	fmt.Fprintf(&b, "Branch: %s\n", branch)
+
+	if g.Head != nil {
+		fmt.Fprintf(&b, "HEAD: %s %s\n", shortHash(g.Head.Hash), g.Head.Subject)
+	}
+
+	if g.Dirty {
+		fmt.Fprintf(&b, "Working tree: %d changed file(s)\n", len(g.ChangedFiles))
+	} else {
+		b.WriteString("Working tree: clean\n")
+	}
+
+	if g.RemoteURL != "" {
+		fmt.Fprintf(&b, "Remote: %s\n", g.RemoteURL)
+	}
+
+	if len(g.RecentCommits) > 0 {
+		b.WriteString("Recent commits:\n")
+		for _, commit := range g.RecentCommits {
+			fmt.Fprintf(&b, "  %s %s\n", shortHash(commit.Hash), commit.Subject)
+		}
+	}
+
+	return b.String()
+}
+
+// shortHash abbreviates a commit hash for display.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// SymbolKind identifies the kind of a Go declaration.
+type SymbolKind string
+
+const (
+	// SymbolFunc is a package-level function
+	SymbolFunc SymbolKind = "func"
+
+	// SymbolMethod is a method on a named type
+	SymbolMethod SymbolKind = "method"
+
+	// SymbolType is a named type other than an interface
+	SymbolType SymbolKind = "type"
+
+	// SymbolInterface is a named interface type
+	SymbolInterface SymbolKind = "interface"
+
+	// SymbolConst is a package-level constant
+	SymbolConst SymbolKind = "const"
+
+	// SymbolVar is a package-level variable
+	SymbolVar SymbolKind = "var"
+)
+
+// Symbol describes an exported Go declaration.
+type Symbol struct {
+	// Name is the identifier of the declaration
+	Name string `json:"name"`
+
+	// Kind is the kind of declaration
+	Kind SymbolKind `json:"kind"`
+
+	// Receiver is the receiver type name for methods
+	Receiver string `json:"receiver,omitempty"`
+
+	// Package is the import path of the declaring package
+	Package string `json:"package"`
+
+	// File is the declaring file, relative to the index root
+	File string `json:"file"`
+
+	// Line is the line number of the declaration
+	Line int `json:"line"`
+
+	// Signature is the declaration without its body
+	Signature string `json:"signature,omitempty"`
+
+	// Doc is the first sentence of the doc comment
+	Doc string `json:"doc,omitempty"`
+}
+
+// GoPackage describes a Go package found by the symbol indexer.
+type GoPackage struct {
+	// Name is the package name
+	Name string `json:"name"`
+
+	// ImportPath is the package import path
+	ImportPath string `json:"import_path"`
+
+	// Dir is the package directory, relative to the index root
+	Dir string `json:"dir"`
+
+	// Files lists the non-test source files of the package
+	Files []string `json:"files"`
+}
+
+// SymbolIndex is an index of the exported declarations of a Go project.
+type SymbolIndex struct {
+	// Module is the module path from go.mod, if any
+	Module string `json:"module,omitempty"`
+
+	// Packages lists the indexed packages
+	Packages []GoPackage `json:"packages"`
+
+	// Symbols lists the exported declarations of all packages
+	Symbols []Symbol `json:"symbols"`
+
+	// Files maps each source file to the names of the symbols it declares
+	Files map[string][]string `json:"files"`
+
+	// Errors contains messages for files that could not be parsed
+	Errors []string `json:"errors,omitempty"`
+}
+
+// FindSymbol returns the symbols with the given name. Methods can be looked
+// up either by name or qualified with their receiver ("Type.Method").
+func (idx *SymbolIndex) FindSymbol(name string) []Symbol {
+	receiver, method := "", name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		receiver, method = name[:i], name[i+1:]
+	}
+
+	var matches []Symbol
+	for _, symbol := range idx.Symbols {
+		if symbol.Name != method {
+			continue
+		}
+		if receiver != "" && symbol.Receiver != receiver {
+			continue
+		}
+		matches = append(matches, symbol)
+	}
+	return matches
+}
+
+// PackageSymbols returns the symbols declared by the package with the given import path.
+func (idx *SymbolIndex) PackageSymbols(importPath string) []Symbol {
+	var symbols []Symbol
+	for _, symbol := range idx.Symbols {
+		if symbol.Package == importPath {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// Summary returns a compact plain-text outline of the index suitable for
+// inclusion in a prompt. Methods are omitted to keep the outline short.
+func (idx *SymbolIndex) Summary() string {
+	var b strings.Builder
+
+	for _, pkg := range idx.Packages {
+		fmt.Fprintf(&b, "package %s (%s)\n", pkg.Name, pkg.ImportPath)
+		for _, symbol := range idx.PackageSymbols(pkg.ImportPath) {
+			if symbol.Kind == SymbolMethod {
+				continue
+			}
+			fmt.Fprintf(&b, "  %s %s\n", symbol.Kind, symbol.Name)
+		}
+	}
+
+	return b.String()
+}
+
+// TechnologyKind classifies a technology detected in a project.
+type TechnologyKind string
+
+const (
+	// TechLanguage is a programming language, such as Go or TypeScript
+	TechLanguage TechnologyKind = "language"
+
+	// TechFramework is an application framework, such as gin or React
+	TechFramework TechnologyKind = "framework"
+
+	// TechBuildSystem is a build system, such as Bazel or Make
+	TechBuildSystem TechnologyKind = "build_system"
+
+	// TechInfrastructure is an infrastructure tool, such as Terraform or Docker
+	TechInfrastructure TechnologyKind = "infrastructure"
+
+	// TechTool is any other tool or platform, such as Unity
+	TechTool TechnologyKind = "tool"
+)
+
+// Technology is a language, framework or tool a Detector found in a project.
+type Technology struct {
+	// Name is the technology name, such as "Go" or "Terraform"
+	Name string `json:"name"`
+
+	// Kind classifies the technology
+	Kind TechnologyKind `json:"kind"`
+
+	// Version is the version in use, if the detector could determine it
+	Version string `json:"version,omitempty"`
+
+	// Evidence lists the files that revealed the technology, relative to the
+	// working directory when possible
+	Evidence []string `json:"evidence,omitempty"`
+
+	// Detector is the name of the detector that reported the technology
+	Detector string `json:"detector"`
+}
+
+// TechStack lists the technologies detected in a project.
+type TechStack struct {
+	// Technologies lists the detected technologies in detector order
+	Technologies []Technology `json:"technologies"`
+
+	// Errors contains messages for detectors that failed
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ByKind returns the detected technologies of the given kind.
+func (s *TechStack) ByKind(kind TechnologyKind) []Technology {
+	var technologies []Technology
+	for _, technology := range s.Technologies {
+		if technology.Kind == kind {
+			technologies = append(technologies, technology)
+		}
+	}
+	return technologies
+}
+
+// Languages returns the detected programming languages.
+func (s *TechStack) Languages() []Technology {
+	return s.ByKind(TechLanguage)
+}
+
+// Frameworks returns the detected frameworks.
+func (s *TechStack) Frameworks() []Technology {
+	return s.ByKind(TechFramework)
+}
+
+// Find returns the technology with the given name, compared case
+// insensitively, or nil if it was not detected.
+func (s *TechStack) Find(name string) *Technology {
+	for i := range s.Technologies {
+		if strings.EqualFold(s.Technologies[i].Name, name) {
+			return &s.Technologies[i]
+		}
+	}
+	return nil
+}
+
+// WorkspaceKind identifies the monorepo tool that declares a workspace.
+type WorkspaceKind string
+
+const (
+	// WorkspaceGo is a Go workspace declared by go.work
+	WorkspaceGo WorkspaceKind = "go"
+
+	// WorkspacePNPM is a pnpm workspace declared by pnpm-workspace.yaml
+	WorkspacePNPM WorkspaceKind = "pnpm"
+
+	// WorkspaceNPM is an npm or Yarn workspace declared by package.json
+	WorkspaceNPM WorkspaceKind = "npm"
+
+	// WorkspaceCargo is a Cargo workspace declared by Cargo.toml
+	WorkspaceCargo WorkspaceKind = "cargo"
+)
+
+// WorkspacePackage is one subproject of a monorepo workspace.
+type WorkspacePackage struct {
+	// Name is the module, package or crate name, or the directory name if
+	// the manifest does not declare one
+	Name string `json:"name"`
+
+	// Path is the package directory, relative to the workspace root
+	Path string `json:"path"`
+
+	// Kind is the workspace that lists the package
+	Kind WorkspaceKind `json:"kind"`
+}
+
+// Workspace describes a monorepo and its subprojects.
+type Workspace struct {
+	// Root is the absolute path of the workspace root
+	Root string `json:"root"`
+
+	// Kinds lists the workspace manifests found in the root
+	Kinds []WorkspaceKind `json:"kinds"`
+
+	// Packages lists the subprojects sorted by path
+	Packages []WorkspacePackage `json:"packages"`
+
+	// Errors contains messages for workspace manifests that could not be parsed
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Package returns the package with the given name or path, or nil if the
+// workspace has none.
+func (w *Workspace) Package(nameOrPath string) *WorkspacePackage {
+	for i := range w.Packages {
+		if w.Packages[i].Path == nameOrPath || w.Packages[i].Name == nameOrPath {
+			return &w.Packages[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Validate checks that the proxy URL is an absolute http, https or socks5
+// URL and that NoProxy entries are single host patterns. A nil config is
+// valid.
+func (p *ProxyConfig) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	u, err := url.Parse(p.URL)
+	if err != nil || u.Host == "" {
+		return &ValidationError{Field: "proxy.url", Message: "proxy URL must be an absolute URL", Value: p.URL}
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return &ValidationError{Field: "proxy.url", Message: "proxy URL scheme must be http, https or socks5", Value: p.URL}
+	}
+	if p.Password != "" && p.Username == "" {
+		return &ValidationError{Field: "proxy.username", Message: "proxy password requires a username"}
+	}
+
+	for _, host := range p.NoProxy {
+		if host == "" || strings.ContainsAny(host, ", \t") {
+			return &ValidationError{Field: "proxy.no_proxy", Message: "no_proxy entries must be single host patterns", Value: host}
+		}
+	}
+	return nil
+}
+
+// Env returns the variables that route a subprocess's traffic through the
+// proxy: HTTPS_PROXY and HTTP_PROXY with any credentials in the URL, and
+// NO_PROXY, each also in lower case since tools differ in which they read.
+// A nil config returns nil.
+func (p *ProxyConfig) Env() []string {
+	if p == nil {
+		return nil
+	}
+
+	proxyURL := p.URLWithCredentials()
+	env := []string{
+		"HTTPS_PROXY=" + proxyURL, "https_proxy=" + proxyURL,
+		"HTTP_PROXY=" + proxyURL, "http_proxy=" + proxyURL,
+	}
+	if len(p.NoProxy) > 0 {
+		noProxy := strings.Join(p.NoProxy, ",")
+		env = append(env, "NO_PROXY="+noProxy, "no_proxy="+noProxy)
+	}
+	return env
+}
+
+// URLWithCredentials returns the proxy URL with the username and password,
+// if any, as its user info.
+func (p *ProxyConfig) URLWithCredentials() string {
+	u, err := url.Parse(p.URL)
+	if err != nil || p.Username == "" {
+		return p.URL
+	}
+	if p.Password != "" {
+		u.User = url.UserPassword(p.Username, p.Password)
+	} else {
+		u.User = url.User(p.Username)
+	}
+	return u.String()
+}
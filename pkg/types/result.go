@@ -0,0 +1,95 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ResultMessage is the final line the CLI writes in stream-json output,
+// summarizing the query: whether it succeeded, its text, cost and timing.
+// Optional fields are pointers, so the accessors below handle lines that
+// omit them.
+type ResultMessage struct {
+	// Type is always "result"
+	Type string `json:"type"`
+
+	// Subtype is "success", or the reason the query stopped, such as
+	// "error_max_turns" or "error_during_execution"
+	Subtype string `json:"subtype"`
+
+	// IsError is true when the query ended with an error
+	IsError bool `json:"is_error"`
+
+	// Result is the text of the final reply
+	Result string `json:"result,omitempty"`
+
+	// SessionID identifies the session the query ran in
+	SessionID string `json:"session_id,omitempty"`
+
+	// NumTurns is the number of turns the query took
+	NumTurns int `json:"num_turns"`
+
+	// DurationMS is the wall time of the query in milliseconds
+	DurationMS *int64 `json:"duration_ms,omitempty"`
+
+	// DurationAPIMS is the time spent in API calls in milliseconds
+	DurationAPIMS *int64 `json:"duration_api_ms,omitempty"`
+
+	// TotalCostUSD is the cost of the query as reported by the CLI
+	TotalCostUSD *float64 `json:"total_cost_usd,omitempty"`
+
+	// Usage is the token usage of the query
+	Usage *TokenUsage `json:"usage,omitempty"`
+}
+
+// ParseResultMessage decodes a stream-json result line.
+func ParseResultMessage(line []byte) (*ResultMessage, error) {
+	var result ResultMessage
+	if err := json.Unmarshal(line, &result); err != nil {
+		return nil, err
+	}
+	if result.Type != "result" {
+		return nil, &ValidationError{Field: "type", Message: "line is not a result message", Value: result.Type}
+	}
+	return &result, nil
+}
+
+// Succeeded reports whether the query completed without error. It is false
+// for a nil result.
+func (r *ResultMessage) Succeeded() bool {
+	return r != nil && !r.IsError && (r.Subtype == "" || r.Subtype == "success")
+}
+
+// Cost returns the cost of the query in USD, or 0 if it was not reported.
+func (r *ResultMessage) Cost() float64 {
+	if r == nil || r.TotalCostUSD == nil {
+		return 0
+	}
+	return *r.TotalCostUSD
+}
+
+// Duration returns the wall time of the query, or 0 if it was not reported.
+func (r *ResultMessage) Duration() time.Duration {
+	if r == nil || r.DurationMS == nil {
+		return 0
+	}
+	return time.Duration(*r.DurationMS) * time.Millisecond
+}
+
+// APIDuration returns the time the query spent in API calls, or 0 if it was
+// not reported.
+func (r *ResultMessage) APIDuration() time.Duration {
+	if r == nil || r.DurationAPIMS == nil {
+		return 0
+	}
+	return time.Duration(*r.DurationAPIMS) * time.Millisecond
+}
+
+// Tokens returns the token usage of the query, or zero usage if it was not
+// reported.
+func (r *ResultMessage) Tokens() TokenUsage {
+	if r == nil || r.Usage == nil {
+		return TokenUsage{}
+	}
+	return *r.Usage
+}
@@ -0,0 +1,128 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Container runtimes supported by BashSandbox.
+const (
+	SandboxRuntimeDocker = "docker"
+	SandboxRuntimePodman = "podman"
+)
+
+// DefaultSandboxMount is the container path where the working directory is
+// mounted when a BashSandbox starts a fresh container.
+const DefaultSandboxMount = "/workspace"
+
+// sandboxNamePattern matches container and image references.
+var sandboxNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:/@-]*$`)
+
+// BashSandbox routes the commands of the Bash tool through a container
+// runtime, so commands suggested by Claude run isolated from the host. Each
+// command either runs in a fresh container from Image, with the working
+// directory mounted at Mount, or is executed in the running Container.
+//
+// Example usage:
+//
+//	config.BashSandbox = &types.BashSandbox{
+//		Image:   "golang:1.22",
+//		Network: "none",
+//	}
+type BashSandbox struct {
+	// Runtime is the container CLI, "docker" (default) or "podman"
+	Runtime string `json:"runtime,omitempty"`
+
+	// Image runs each command in a new container from this image
+	Image string `json:"image,omitempty"`
+
+	// Container runs each command in this existing container instead
+	Container string `json:"container,omitempty"`
+
+	// Mount is the container path of the working directory (default
+	// DefaultSandboxMount for Image; the container's own directory for Container)
+	Mount string `json:"mount,omitempty"`
+
+	// Network sets the network of new containers, e.g. "none"
+	Network string `json:"network,omitempty"`
+
+	// Shell runs the command inside the container (default "sh")
+	Shell string `json:"shell,omitempty"`
+
+	// RunArgs are extra arguments for `run` or `exec`, such as resource limits
+	RunArgs []string `json:"run_args,omitempty"`
+}
+
+// Validate checks the sandbox settings. A nil sandbox is valid.
+func (s *BashSandbox) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	switch s.Runtime {
+	case "", SandboxRuntimeDocker, SandboxRuntimePodman:
+	default:
+		return &ValidationError{
+			Field:   "bash_sandbox.runtime",
+			Value:   s.Runtime,
+			Message: "runtime must be \"docker\" or \"podman\"",
+		}
+	}
+
+	if (s.Image == "") == (s.Container == "") {
+		return &ValidationError{
+			Field:   "bash_sandbox",
+			Message: "exactly one of image and container must be set",
+		}
+	}
+	if name := s.Image + s.Container; !sandboxNamePattern.MatchString(name) {
+		return &ValidationError{
+			Field:   "bash_sandbox.image",
+			Value:   name,
+			Message: "invalid image or container name",
+		}
+	}
+
+	if s.Mount != "" && !strings.HasPrefix(s.Mount, "/") {
+		return &ValidationError{
+			Field:   "bash_sandbox.mount",
+			Value:   s.Mount,
+			Message: "mount must be an absolute container path",
+		}
+	}
+
+	return nil
+}
+
+// Command returns the argv that runs command in the sandbox, mounting hostDir
+// as the working directory of new containers.
+func (s *BashSandbox) Command(command, hostDir string) []string {
+	runtime := s.Runtime
+	if runtime == "" {
+		runtime = SandboxRuntimeDocker
+	}
+	shell := s.Shell
+	if shell == "" {
+		shell = "sh"
+	}
+
+	if s.Container != "" {
+		argv := []string{runtime, "exec", "-i"}
+		if s.Mount != "" {
+			argv = append(argv, "-w", s.Mount)
+		}
+		argv = append(argv, s.RunArgs...)
+		return append(argv, s.Container, shell, "-c", command)
+	}
+
+	mount := s.Mount
+	if mount == "" {
+		mount = DefaultSandboxMount
+	}
+	argv := []string{runtime, "run", "--rm", "-i", "-v", hostDir + ":" + mount, "-w", mount}
+	if s.Network != "" {
+		argv = append(argv, "--network", s.Network)
+	}
+	argv = append(argv, s.RunArgs...)
+	return append(argv, s.Image, shell, "-c", command)
+}
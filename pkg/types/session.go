@@ -1,6 +1,8 @@
 package types
 
 import (
+	"bytes"
+	"encoding/json"
 	"time"
 )
 
@@ -23,6 +25,53 @@ type SimpleSessionInfo struct {
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+// StoredSession describes a conversation the Claude Code CLI has saved in
+// its session history, which can be resumed by ID.
+type StoredSession struct {
+	// ID is the session identifier (UUID format)
+	ID string `json:"id"`
+
+	// WorkingDirectory is the directory the CLI ran in
+	WorkingDirectory string `json:"working_directory,omitempty"`
+
+	// Path is the session's transcript file
+	Path string `json:"path"`
+
+	// CreatedAt is when the first message was recorded
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the last message was recorded
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// FirstPrompt is the start of the first user prompt, shortened for display
+	FirstPrompt string `json:"first_prompt,omitempty"`
+
+	// Summary is the title the CLI generated for the conversation, if any
+	Summary string `json:"summary,omitempty"`
+
+	// Messages is the number of user and assistant messages, including tool
+	// results
+	Messages int `json:"messages"`
+
+	// GitBranch is the branch checked out when the session was last used
+	GitBranch string `json:"git_branch,omitempty"`
+}
+
+// SessionPurge reports the data removed when a session was purged.
+type SessionPurge struct {
+	// SessionID is the purged session
+	SessionID string `json:"session_id"`
+
+	// ExternalID is the external ID that mapped to the session, if any
+	ExternalID string `json:"external_id,omitempty"`
+
+	// Checkpoints lists the IDs of the job checkpoints deleted
+	Checkpoints []string `json:"checkpoints,omitempty"`
+
+	// Files lists the CLI transcript files and directories deleted
+	Files []string `json:"files,omitempty"`
+}
+
 // SessionMessage represents a basic message in the session
 // Simplified to match official SDK message structure
 type SessionMessage struct {
@@ -39,3 +88,65 @@ type SimpleSessionHistory struct {
 	SessionInfo SimpleSessionInfo `json:"session_info"`
 	Messages    []SessionMessage  `json:"messages"`
 }
+
+// CompactTrigger describes what caused a conversation to be compacted.
+type CompactTrigger string
+
+const (
+	// CompactTriggerManual indicates compaction was explicitly requested
+	CompactTriggerManual CompactTrigger = "manual"
+
+	// CompactTriggerAuto indicates compaction was triggered automatically
+	CompactTriggerAuto CompactTrigger = "auto"
+)
+
+// CompactBoundaryMessage marks the point at which the CLI compacted (summarized)
+// the conversation history of a session. Messages before the boundary have been
+// replaced by a summary in the model's context.
+type CompactBoundaryMessage struct {
+	// SessionID is the session that was compacted
+	SessionID string `json:"session_id,omitempty"`
+
+	// Trigger is what caused the compaction
+	Trigger CompactTrigger `json:"trigger"`
+
+	// PreTokens is the number of context tokens before compaction (if reported)
+	PreTokens int `json:"pre_tokens,omitempty"`
+
+	// Summary is the CLI's output for the compaction (if available)
+	Summary string `json:"summary,omitempty"`
+
+	// Timestamp is when the boundary was observed
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ParseCompactBoundary parses a line of CLI stream-json output into a
+// CompactBoundaryMessage. It returns false if the line is not a compact boundary.
+func ParseCompactBoundary(line []byte) (*CompactBoundaryMessage, bool) {
+	var raw struct {
+		Type            string `json:"type"`
+		Subtype         string `json:"subtype"`
+		SessionID       string `json:"session_id"`
+		CompactMetadata struct {
+			Trigger   string `json:"trigger"`
+			PreTokens int    `json:"pre_tokens"`
+		} `json:"compact_metadata"`
+	}
+
+	if !bytes.Contains(line, []byte("compact_boundary")) {
+		return nil, false
+	}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil, false
+	}
+	if raw.Type != "system" || raw.Subtype != "compact_boundary" {
+		return nil, false
+	}
+
+	return &CompactBoundaryMessage{
+		SessionID: raw.SessionID,
+		Trigger:   CompactTrigger(raw.CompactMetadata.Trigger),
+		PreTokens: raw.CompactMetadata.PreTokens,
+		Timestamp: time.Now(),
+	}, true
+}
@@ -27,6 +27,8 @@ const (
 	StreamEventPing StreamEventType = "ping"
 	// StreamEventError indicates an error occurred
 	StreamEventError StreamEventType = "error"
+	// StreamEventCompactBoundary indicates the conversation history was compacted
+	StreamEventCompactBoundary StreamEventType = "compact_boundary"
 )
 
 // StreamEvent represents a single event in a streaming response
@@ -55,6 +57,9 @@ type StreamEvent struct {
 	// Usage contains token usage information (for message_stop events)
 	Usage *TokenUsage `json:"usage,omitempty"`
 
+	// CompactBoundary contains compaction details (for compact_boundary events)
+	CompactBoundary *CompactBoundaryMessage `json:"compact_boundary,omitempty"`
+
 	// Timestamp is when the event was generated
 	Timestamp time.Time `json:"timestamp,omitempty"`
 
@@ -89,6 +94,29 @@ type StreamMessage struct {
 	Usage *TokenUsage `json:"usage,omitempty"`
 }
 
+// Text returns the text of the message's text blocks joined together. It
+// returns "" for a nil message.
+func (m *StreamMessage) Text() string {
+	if m == nil {
+		return ""
+	}
+	return ExtractText(m)
+}
+
+// ToolUses returns the tools Claude called in the message, in order.
+func (m *StreamMessage) ToolUses() []ToolUse {
+	if m == nil {
+		return nil
+	}
+	return ExtractToolUses(m)
+}
+
+// HasToolUse reports whether the message calls the named tool, or any tool
+// if name is empty.
+func (m *StreamMessage) HasToolUse(name string) bool {
+	return hasToolUse(m.ToolUses(), name)
+}
+
 // ContentDelta represents incremental content updates in streaming
 type ContentDelta struct {
 	// Type indicates the delta type (e.g., "text_delta")
@@ -118,6 +146,9 @@ type StreamOptions struct {
 	// OnComplete is called when streaming completes
 	OnComplete func(*StreamMessage) error
 
+	// OnCompactBoundary is called when the CLI compacts the conversation history
+	OnCompactBoundary func(*CompactBoundaryMessage) error
+
 	// BufferSize sets the size of the event channel buffer
 	BufferSize int
 
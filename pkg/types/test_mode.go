@@ -0,0 +1,76 @@
+package types
+
+import "time"
+
+// TestScript drives the deterministic fake CLI used in test mode. When set
+// together with ClaudeCodeConfig.TestMode, the client never starts a
+// subprocess: every query is answered in-process from the script, so unit
+// tests do not depend on the CLI, credentials or the network.
+//
+// Each query is served the first unused response whose Match is contained in
+// its prompt. Once no response matches, a reply is generated from Seed and
+// the prompt, so the same prompt always gets the same reply.
+//
+// Example usage:
+//
+//	config := &types.ClaudeCodeConfig{
+//		TestMode: true,
+//		TestScript: &types.TestScript{
+//			Responses: []types.TestResponse{
+//				{Match: "weather", Text: "Sunny."},
+//				{Match: "deploy", Error: "rate limited"},
+//				{Text: "Done.", ToolUses: []types.TestToolUse{
+//					{Name: "Read", Input: map[string]any{"file_path": "main.go"}, Result: "package main"},
+//				}},
+//			},
+//			Seed: 42,
+//		},
+//	}
+type TestScript struct {
+	// Responses are served in order to the queries they match
+	Responses []TestResponse `json:"responses,omitempty"`
+
+	// Seed selects the replies generated for prompts no response matches
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// TestResponse is one canned reply of a TestScript.
+type TestResponse struct {
+	// Match is a substring the prompt must contain (empty matches any prompt)
+	Match string `json:"match,omitempty"`
+
+	// Text is the assistant's final reply
+	Text string `json:"text,omitempty"`
+
+	// ToolUses are the tool calls made, with their results, before the reply
+	ToolUses []TestToolUse `json:"tool_uses,omitempty"`
+
+	// Usage is the reported token usage (nil estimates it from the prompt
+	// and reply)
+	Usage *TokenUsage `json:"usage,omitempty"`
+
+	// Error fails the query as if the CLI exited with this message on stderr
+	Error string `json:"error,omitempty"`
+
+	// Delay holds back the output, to exercise timeouts and cancellation
+	Delay time.Duration `json:"delay,omitempty"`
+
+	// Timeout writes the output but never exits, as a hung CLI would; the
+	// query only ends when its context is done or it is interrupted
+	Timeout bool `json:"timeout,omitempty"`
+}
+
+// TestToolUse is a tool call made by a TestResponse.
+type TestToolUse struct {
+	// Name is the tool name (e.g. "Read" or "Bash")
+	Name string `json:"name"`
+
+	// Input is the tool input
+	Input map[string]any `json:"input,omitempty"`
+
+	// Result is the content returned by the tool
+	Result string `json:"result,omitempty"`
+
+	// IsError marks the result as a tool error
+	IsError bool `json:"is_error,omitempty"`
+}
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"strconv"
+	"time"
+)
+
+// ToolProgress is a progress update from a Go tool registered with the
+// client's ToolRegistry, so UIs can show what a long-running tool is doing.
+type ToolProgress struct {
+	// Tool is the registered tool name, e.g. "lookup_order"
+	Tool string `json:"tool"`
+
+	// Percent is the completion percentage from 0 to 100, or -1 when the
+	// update is only a log line
+	Percent float64 `json:"percent"`
+
+	// Message describes the current step
+	Message string `json:"message,omitempty"`
+
+	// Time is when the update was reported
+	Time time.Time `json:"time"`
+}
+
+// String formats the update as "tool: 40% message".
+func (p ToolProgress) String() string {
+	text := p.Tool + ":"
+	if p.Percent >= 0 {
+		text += " " + strconv.FormatFloat(p.Percent, 'f', -1, 64) + "%"
+	}
+	if p.Message != "" {
+		text += " " + p.Message
+	}
+	return text
+}
@@ -447,3 +447,36 @@ var BuiltinTools = struct {
 		},
 	},
 }
+
+// MCPTool describes a tool provided by an MCP server, as reported by the
+// server's tools/list response.
+type MCPTool struct {
+	// Server is the name of the MCP server providing the tool
+	Server string `json:"server"`
+
+	// Name is the tool name on the server
+	Name string `json:"name"`
+
+	// Description explains what the tool does
+	Description string `json:"description,omitempty"`
+
+	// InputSchema is the tool's JSON Schema for its arguments, kept verbatim
+	InputSchema json.RawMessage `json:"input_schema,omitempty"`
+}
+
+// QualifiedName returns the name Claude Code uses for the tool in allowed
+// and disallowed tool lists, e.g. "mcp__github__create_issue".
+func (t MCPTool) QualifiedName() string {
+	return "mcp__" + t.Server + "__" + t.Name
+}
+
+// Schema decodes InputSchema into a ToolInputSchema. Schema keywords the
+// struct does not model are dropped.
+func (t MCPTool) Schema() (ToolInputSchema, error) {
+	var schema ToolInputSchema
+	if len(t.InputSchema) == 0 {
+		return ToolInputSchema{Type: "object"}, nil
+	}
+	err := json.Unmarshal(t.InputSchema, &schema)
+	return schema, err
+}
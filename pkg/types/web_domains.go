@@ -0,0 +1,96 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+)
+
+// webDomainPattern matches a bare domain name such as "docs.example.com".
+var webDomainPattern = regexp.MustCompile(`^(?i)([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// WebDomainPolicy constrains the domains the WebFetch and WebSearch tools may
+// reach. A domain also covers its subdomains. Blocked domains take precedence;
+// when AllowedDomains is set, every other domain is blocked.
+//
+// Example usage:
+//
+//	config.WebDomains = &types.WebDomainPolicy{
+//		AllowedDomains: []string{"go.dev", "pkg.go.dev", "github.com"},
+//		BlockedDomains: []string{"gist.github.com"},
+//	}
+type WebDomainPolicy struct {
+	// AllowedDomains lists the only domains web tools may reach (empty allows all)
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+
+	// BlockedDomains lists domains web tools may never reach
+	BlockedDomains []string `json:"blocked_domains,omitempty"`
+}
+
+// Validate checks that every entry is a bare domain name. A nil policy is valid.
+func (p *WebDomainPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	for field, domains := range map[string][]string{
+		"web_domains.allowed_domains": p.AllowedDomains,
+		"web_domains.blocked_domains": p.BlockedDomains,
+	} {
+		for _, domain := range domains {
+			if !webDomainPattern.MatchString(domain) {
+				return &ValidationError{
+					Field:   field,
+					Value:   domain,
+					Message: "domains must be bare host names such as example.com, without scheme, port or path",
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Allows reports whether the policy lets web tools reach host.
+func (p *WebDomainPolicy) Allows(host string) bool {
+	if p == nil {
+		return true
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, domain := range p.BlockedDomains {
+		if matchesDomain(host, domain) {
+			return false
+		}
+	}
+	if len(p.AllowedDomains) == 0 {
+		return true
+	}
+	for _, domain := range p.AllowedDomains {
+		if matchesDomain(host, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionRules returns the CLI permission rules for the policy, for
+// --allowedTools and --disallowedTools respectively.
+func (p *WebDomainPolicy) PermissionRules() (allow, deny []string) {
+	if p == nil {
+		return nil, nil
+	}
+
+	for _, domain := range p.AllowedDomains {
+		allow = append(allow, "WebFetch(domain:"+strings.ToLower(domain)+")")
+	}
+	for _, domain := range p.BlockedDomains {
+		deny = append(deny, "WebFetch(domain:"+strings.ToLower(domain)+")")
+	}
+	return allow, deny
+}
+
+// matchesDomain reports whether host is domain or one of its subdomains.
+func matchesDomain(host, domain string) bool {
+	domain = strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
@@ -0,0 +1,180 @@
+package types
+
+import (
+	"net/http"
+	"time"
+)
+
+// WebhookConfig configures webhook notifications for query progress, so job
+// systems can track long agent runs without holding a stream open.
+//
+// Each event is POSTed as JSON. When Secret is set, requests carry an
+// X-Claude-Timestamp header and an X-Claude-Signature header of the form
+// "sha256=<hex HMAC-SHA256 of timestamp + "." + body>".
+//
+// Example usage:
+//
+//	config.Webhook = &types.WebhookConfig{
+//		URL:    "https://jobs.example.com/hooks/claude",
+//		Secret: os.Getenv("WEBHOOK_SECRET"),
+//		Events: []types.WebhookEventType{types.WebhookEventQueryCompleted},
+//	}
+//
+// With Format set to WebhookFormatSlack or WebhookFormatTeams, events are
+// posted as chat messages to a Slack or Microsoft Teams incoming webhook
+// instead, for agents running unattended in CI or cron jobs:
+//
+//	config.Webhook = &types.WebhookConfig{
+//		URL:    os.Getenv("SLACK_WEBHOOK_URL"),
+//		Format: types.WebhookFormatSlack,
+//	}
+type WebhookConfig struct {
+	// URL is the endpoint events are POSTed to
+	URL string `json:"url"`
+
+	// Format is the payload format (defaults to WebhookFormatJSON)
+	Format WebhookFormat `json:"format,omitempty"`
+
+	// Secret signs each request with HMAC-SHA256 (empty disables signing)
+	Secret string `json:"-"`
+
+	// Events limits delivery to the listed event types. Empty sends all
+	// events in the JSON format, and only query.completed and query.failed
+	// in the chat formats, which would otherwise post every turn.
+	Events []WebhookEventType `json:"events,omitempty"`
+
+	// Headers are added to every request
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Timeout bounds each delivery attempt (defaults to 10s)
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// MaxRetries is the number of retries for failed deliveries (defaults to 3)
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// HTTPClient overrides the client used for delivery
+	HTTPClient *http.Client `json:"-"`
+
+	// OnError is called when an event cannot be delivered after all retries
+	OnError func(event *WebhookEvent, err error) `json:"-"`
+}
+
+// Accepts reports whether events of the given type should be delivered.
+func (c *WebhookConfig) Accepts(eventType WebhookEventType) bool {
+	if len(c.Events) == 0 {
+		if c.Format == WebhookFormatSlack || c.Format == WebhookFormatTeams {
+			return eventType == WebhookEventQueryCompleted || eventType == WebhookEventQueryFailed
+		}
+		return true
+	}
+	for _, accepted := range c.Events {
+		if accepted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookFormat selects how webhook events are encoded.
+type WebhookFormat string
+
+const (
+	// WebhookFormatJSON posts each WebhookEvent as JSON
+	WebhookFormatJSON WebhookFormat = "json"
+
+	// WebhookFormatSlack posts Slack incoming webhook messages
+	WebhookFormatSlack WebhookFormat = "slack"
+
+	// WebhookFormatTeams posts Microsoft Teams incoming webhook message cards
+	WebhookFormatTeams WebhookFormat = "teams"
+)
+
+// WebhookEventType identifies a webhook notification.
+type WebhookEventType string
+
+const (
+	// WebhookEventTurnCompleted is sent when the assistant finishes a turn
+	WebhookEventTurnCompleted WebhookEventType = "turn.completed"
+
+	// WebhookEventToolExecuted is sent when a tool call returns a result
+	WebhookEventToolExecuted WebhookEventType = "tool.executed"
+
+	// WebhookEventQueryCompleted is sent with the final result and cost of a query
+	WebhookEventQueryCompleted WebhookEventType = "query.completed"
+
+	// WebhookEventQueryFailed is sent when a query fails
+	WebhookEventQueryFailed WebhookEventType = "query.failed"
+)
+
+// WebhookEvent is the JSON payload of a webhook notification.
+type WebhookEvent struct {
+	// ID uniquely identifies the event, for deduplicating retried deliveries
+	ID string `json:"id"`
+
+	// Type is the event type
+	Type WebhookEventType `json:"type"`
+
+	// Timestamp is when the event occurred
+	Timestamp time.Time `json:"timestamp"`
+
+	// QueryID identifies the query the event belongs to
+	QueryID string `json:"query_id"`
+
+	// SessionID is the Claude Code session, when known
+	SessionID string `json:"session_id,omitempty"`
+
+	// Model is the model serving the query
+	Model string `json:"model,omitempty"`
+
+	// Turn is the number of completed assistant turns so far
+	Turn int `json:"turn,omitempty"`
+
+	// Tool describes the executed tool for tool.executed events
+	Tool *WebhookToolEvent `json:"tool,omitempty"`
+
+	// Result holds the outcome of a completed query
+	Result *WebhookResult `json:"result,omitempty"`
+
+	// Error describes the failure for query.failed events
+	Error string `json:"error,omitempty"`
+
+	// Metadata is the metadata attached to the query
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// WebhookToolEvent describes a tool execution.
+type WebhookToolEvent struct {
+	// ID is the tool_use ID
+	ID string `json:"id"`
+
+	// Name is the tool name
+	Name string `json:"name,omitempty"`
+
+	// Input is the tool input
+	Input map[string]any `json:"input,omitempty"`
+
+	// IsError reports whether the tool returned an error
+	IsError bool `json:"is_error,omitempty"`
+}
+
+// WebhookResult summarizes a completed query.
+type WebhookResult struct {
+	// Text is the final response text
+	Text string `json:"text,omitempty"`
+
+	// StopReason explains why the query ended
+	StopReason string `json:"stop_reason,omitempty"`
+
+	// NumTurns is the number of turns taken
+	NumTurns int `json:"num_turns,omitempty"`
+
+	// Usage is the token usage of the query
+	Usage *TokenUsage `json:"usage,omitempty"`
+
+	// CostUSD is the query cost in US dollars, as reported by the CLI or
+	// estimated from the client's pricing
+	CostUSD float64 `json:"cost_usd"`
+
+	// Duration is the wall-clock duration of the query
+	Duration time.Duration `json:"duration_ns,omitempty"`
+}
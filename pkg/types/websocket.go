@@ -0,0 +1,64 @@
+package types
+
+// WebSocketRequest is the JSON a browser sends over a WebSocket connection
+// served by ClaudeCodeSession.ServeWebSocket to ask a question:
+//
+//	{"prompt": "Explain main.go"}
+type WebSocketRequest struct {
+	// Prompt is the user's message
+	Prompt string `json:"prompt"`
+}
+
+// WebSocketEventType identifies an event sent over a WebSocket connection.
+type WebSocketEventType string
+
+const (
+	// WebSocketEventMessage carries a complete message: an assistant turn,
+	// or the tool results returned to Claude
+	WebSocketEventMessage WebSocketEventType = "message"
+
+	// WebSocketEventDelta carries reply text as it arrives
+	WebSocketEventDelta WebSocketEventType = "delta"
+
+	// WebSocketEventToolUse is sent when Claude calls a tool
+	WebSocketEventToolUse WebSocketEventType = "tool_use"
+
+	// WebSocketEventResult ends a reply with the query's result
+	WebSocketEventResult WebSocketEventType = "result"
+
+	// WebSocketEventError ends a reply that failed, or answers an invalid
+	// request
+	WebSocketEventError WebSocketEventType = "error"
+)
+
+// WebSocketEvent is the JSON sent to the browser for each step of a reply.
+// Every reply is a sequence of message, delta and tool_use events ended by
+// exactly one result or error event. Queries the CLI reports as failed end
+// with a result event whose is_error is true:
+//
+//	{"type":"delta","text":"Let me look"}
+//	{"type":"tool_use","tool_use":{"id":"toolu_1","name":"Read","input":{"file_path":"main.go"}}}
+//	{"type":"message","role":"assistant","content":[...]}
+//	{"type":"result","result":{"type":"result","subtype":"success","total_cost_usd":0.01,...}}
+//	{"type":"error","error":"session has expired","code":"SESSION_EXPIRED"}
+type WebSocketEvent struct {
+	// Type is the event type
+	Type WebSocketEventType `json:"type"`
+
+	// Text is the reply text of delta events
+	Text string `json:"text,omitempty"`
+
+	// Role and Content are the sender and blocks of message events
+	Role    Role           `json:"role,omitempty"`
+	Content []ContentBlock `json:"content,omitempty"`
+
+	// ToolUse is the tool call of tool_use events
+	ToolUse *ToolUse `json:"tool_use,omitempty"`
+
+	// Result is the result of result events, as reported by the CLI
+	Result *ResultMessage `json:"result,omitempty"`
+
+	// Error and Code describe the failure of error events
+	Error string `json:"error,omitempty"`
+	Code  string `json:"code,omitempty"`
+}
@@ -0,0 +1,300 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Definition is a workflow declared in a YAML file, so workflows can be
+// written without Go and run by a Go service:
+//
+//	name: add-feature
+//	dir: ..
+//	steps:
+//	  - id: plan
+//	    query: Plan how to add {{.feature}} to the CLI
+//	  - id: implement
+//	    query: Implement the plan
+//	    model: claude-opus-4
+//	    after: [plan]
+//	    retry: {attempts: 2, backoff: 1m}
+//	    timeout: 15m
+//	  - id: test
+//	    command: [go, test, ./...]
+//	    after: [implement]
+//	  - id: tests-failed
+//	    branch:
+//	      if: {output: test, contains: FAIL}
+//	      then: [fix]
+//	      else: [review]
+//	    after: [test]
+//	  - id: fix
+//	    query: "Fix the failing tests:\n{{output \"test\"}}"
+//	  - id: review
+//	    approval: Tests pass. Keep the change?
+//
+// Each step has exactly one of query, command, approval or branch.
+type Definition struct {
+	// Name names the workflow in reports
+	Name string `yaml:"name"`
+
+	// Dir is the directory command steps run in. Load resolves a relative
+	// directory against the file's directory.
+	Dir string `yaml:"dir,omitempty"`
+
+	// Steps are the workflow's steps
+	Steps []StepDefinition `yaml:"steps"`
+}
+
+// StepDefinition declares a step of a Definition.
+type StepDefinition struct {
+	// ID names the step
+	ID string `yaml:"id"`
+
+	// After lists the steps that must succeed first (see After)
+	After []string `yaml:"after,omitempty"`
+
+	// If runs the step only when the condition holds
+	If *Condition `yaml:"if,omitempty"`
+
+	// Retry retries a failed step (see WithRetry)
+	Retry *RetryDefinition `yaml:"retry,omitempty"`
+
+	// Timeout bounds each attempt of the step, e.g. "10m"
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// Query is the prompt template of a query step (see Query), sent with
+	// Model and System when set
+	Query  string `yaml:"query,omitempty"`
+	Model  string `yaml:"model,omitempty"`
+	System string `yaml:"system,omitempty"`
+
+	// Command is the program and arguments of a command step
+	Command []string `yaml:"command,omitempty"`
+
+	// Approval is the message template of an approval step
+	Approval string `yaml:"approval,omitempty"`
+
+	// Branch makes the step a branch step
+	Branch *BranchDefinition `yaml:"branch,omitempty"`
+}
+
+// RetryDefinition declares the retry policy of a step.
+type RetryDefinition struct {
+	// Attempts is the total number of attempts, including the first
+	Attempts int `yaml:"attempts"`
+
+	// Backoff is the delay between attempts, e.g. "30s"
+	Backoff time.Duration `yaml:"backoff,omitempty"`
+}
+
+// BranchDefinition declares the arms of a branch step.
+type BranchDefinition struct {
+	// If chooses the arm
+	If Condition `yaml:"if"`
+
+	// Then runs when the condition holds, and Else when it does not
+	Then []string `yaml:"then,omitempty"`
+	Else []string `yaml:"else,omitempty"`
+}
+
+// Condition tests the output of a step or a value of the run. With no
+// comparison set, it holds when the output or value is not empty.
+type Condition struct {
+	// Output names the step whose output is tested
+	Output string `yaml:"output,omitempty"`
+
+	// Value names the run value that is tested (see Run.Set)
+	Value string `yaml:"value,omitempty"`
+
+	// Contains, NotContains and Equals compare the text; all that are set
+	// must hold
+	Contains    string `yaml:"contains,omitempty"`
+	NotContains string `yaml:"not_contains,omitempty"`
+	Equals      string `yaml:"equals,omitempty"`
+}
+
+// Holds reports whether the condition holds for run.
+func (c Condition) Holds(run *Run) bool {
+	var text string
+	if c.Output != "" {
+		text = run.Output(c.Output)
+	} else if value := run.Get(c.Value); value != nil {
+		text = fmt.Sprint(value)
+	}
+
+	if c.Contains == "" && c.NotContains == "" && c.Equals == "" {
+		return text != ""
+	}
+	return (c.Contains == "" || strings.Contains(text, c.Contains)) &&
+		(c.NotContains == "" || !strings.Contains(text, c.NotContains)) &&
+		(c.Equals == "" || strings.TrimSpace(text) == c.Equals)
+}
+
+// Load reads and validates a workflow definition from a YAML file.
+func Load(path string) (*Definition, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - definition path is provided by the caller
+	if err != nil {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "WORKFLOW_DEFINITION", "failed to read workflow definition "+path)
+	}
+	definition, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if definition.Dir != "" && !filepath.IsAbs(definition.Dir) {
+		definition.Dir = filepath.Join(filepath.Dir(path), definition.Dir)
+	}
+	return definition, nil
+}
+
+// Parse decodes and validates a workflow definition from YAML. Unknown keys
+// are rejected, and the steps are checked as New checks them.
+func Parse(data []byte) (*Definition, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var definition Definition
+	if err := decoder.Decode(&definition); err != nil && !errors.Is(err, io.EOF) {
+		return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "WORKFLOW_DEFINITION", "invalid workflow definition")
+	}
+	if len(definition.Steps) == 0 {
+		return nil, sdkerrors.NewValidationError("steps", definition.Name, "at least one", "workflow definition has no steps")
+	}
+
+	steps, err := definition.BuildSteps()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := New(definition.Name, nil, steps); err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// Build returns the workflow the definition declares, run with executor.
+// Options apply after the definition's directory.
+func (d *Definition) Build(executor types.QueryExecutor, opts ...Option) (*Workflow, error) {
+	steps, err := d.BuildSteps()
+	if err != nil {
+		return nil, err
+	}
+	if d.Dir != "" {
+		opts = append([]Option{WithDir(d.Dir)}, opts...)
+	}
+	return New(d.Name, executor, steps, opts...)
+}
+
+// BuildSteps returns the steps the definition declares.
+func (d *Definition) BuildSteps() ([]Step, error) {
+	ids := make(map[string]bool, len(d.Steps))
+	for _, def := range d.Steps {
+		ids[def.ID] = true
+	}
+
+	steps := make([]Step, 0, len(d.Steps))
+	for i, def := range d.Steps {
+		step, err := def.build(ids)
+		if err != nil {
+			name := def.ID
+			if name == "" {
+				name = fmt.Sprint(i)
+			}
+			return nil, sdkerrors.WrapError(err, sdkerrors.CategoryValidation, "WORKFLOW_DEFINITION", "invalid step "+name)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// build returns the step def declares. ids holds the IDs of the
+// definition's steps, which conditions may refer to.
+func (def StepDefinition) build(ids map[string]bool) (Step, error) {
+	var opts []StepOption
+	if len(def.After) > 0 {
+		opts = append(opts, After(def.After...))
+	}
+	if def.If != nil {
+		if err := def.If.validate(ids); err != nil {
+			return Step{}, err
+		}
+		opts = append(opts, If(def.If.Holds))
+	}
+	if def.Retry != nil {
+		if def.Retry.Attempts < 1 || def.Retry.Backoff < 0 {
+			return Step{}, errors.New("retry needs at least 1 attempt and a backoff of 0 or more")
+		}
+		opts = append(opts, WithRetry(def.Retry.Attempts, def.Retry.Backoff))
+	}
+	if def.Timeout < 0 {
+		return Step{}, errors.New("timeout cannot be negative")
+	}
+	if def.Timeout > 0 {
+		opts = append(opts, WithTimeout(def.Timeout))
+	}
+
+	kinds := 0
+	for _, set := range []bool{def.Query != "", len(def.Command) > 0, def.Approval != "", def.Branch != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return Step{}, errors.New("a step needs exactly one of query, command, approval or branch")
+	}
+	if (def.Model != "" || def.System != "") && def.Query == "" {
+		return Step{}, errors.New("model and system apply only to query steps")
+	}
+
+	switch {
+	case def.Query != "":
+		tmpl, err := parseTemplate(def.ID, def.Query)
+		step := QueryRequest(def.ID, func(run *Run) (*types.QueryRequest, error) {
+			text, err := run.render(tmpl)
+			if err != nil {
+				return nil, err
+			}
+			return &types.QueryRequest{
+				Model:    def.Model,
+				System:   def.System,
+				Messages: []types.Message{{Role: types.RoleUser, Content: text}},
+			}, nil
+		}, opts...)
+		step.err = err
+		return step, nil
+
+	case len(def.Command) > 0:
+		return Command(def.ID, def.Command[0], def.Command[1:], opts...), nil
+
+	case def.Approval != "":
+		return Approval(def.ID, def.Approval, opts...), nil
+
+	default:
+		if err := def.Branch.If.validate(ids); err != nil {
+			return Step{}, err
+		}
+		return Branch(def.ID, def.Branch.If.Holds, def.Branch.Then, def.Branch.Else, opts...), nil
+	}
+}
+
+// validate checks that the condition tests exactly one existing step output
+// or run value.
+func (c Condition) validate(ids map[string]bool) error {
+	if (c.Output == "") == (c.Value == "") {
+		return errors.New("a condition needs exactly one of output or value")
+	}
+	if c.Output != "" && !ids[c.Output] {
+		return fmt.Errorf("condition refers to unknown step %s", c.Output)
+	}
+	return nil
+}
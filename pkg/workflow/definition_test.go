@@ -0,0 +1,155 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+const testDefinition = `
+name: add-feature
+dir: project
+steps:
+  - id: plan
+    query: Plan how to add {{.feature}}
+  - id: implement
+    query: "Implement: {{output \"plan\"}}"
+    model: claude-opus-4
+    system: Keep changes small.
+    after: [plan]
+    retry: {attempts: 2, backoff: 1ms}
+    timeout: 1m
+  - id: test
+    command: [sh, -c, "echo FAIL: TestParse"]
+    after: [implement]
+  - id: failed
+    branch:
+      if: {output: test, contains: FAIL}
+      then: [fix]
+      else: [review]
+    after: [test]
+  - id: fix
+    query: "Fix {{output \"test\"}}"
+  - id: review
+    approval: Keep the change?
+  - id: notify
+    query: Write release notes
+    if: {value: notify, equals: "yes"}
+`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "project"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "workflow.yaml")
+	if err := os.WriteFile(path, []byte(testDefinition), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	definition, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if definition.Dir != filepath.Join(dir, "project") {
+		t.Errorf("Expected dir to resolve against the file, got %q", definition.Dir)
+	}
+
+	var models []string
+	executor := &fakeExecutor{answer: func(prompt string) (string, error) {
+		return "done: " + prompt, nil
+	}}
+	recording := executorFunc(func(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+		models = append(models, request.Model+"|"+request.System)
+		return executor.Query(ctx, request)
+	})
+
+	w, err := definition.Build(recording, WithApprover(func(context.Context, string, string) (bool, error) {
+		t.Error("Expected the review branch to be skipped")
+		return true, nil
+	}))
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	report, err := w.Run(context.Background(), map[string]any{"feature": "a --json flag", "notify": "no"})
+	if err != nil {
+		t.Fatalf("Run failed: %v\n%s", err, report)
+	}
+
+	want := []string{
+		"Plan how to add a --json flag",
+		"Implement: done: Plan how to add a --json flag",
+		"Fix FAIL: TestParse\n",
+	}
+	if strings.Join(executor.prompts, "|") != strings.Join(want, "|") {
+		t.Errorf("Unexpected prompts %q", executor.prompts)
+	}
+	if models[1] != "claude-opus-4|Keep changes small." {
+		t.Errorf("Expected the implement step's model and system, got %q", models[1])
+	}
+	for _, id := range []string{"review", "notify"} {
+		for _, step := range report.Steps {
+			if step.ID == id && step.Status != StatusSkipped {
+				t.Errorf("Expected %s to be skipped, got %s", id, step.Status)
+			}
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":       "steps:\n  - id: a\n    query: hi\n    retries: 2\n",
+		"no steps":          "name: empty\n",
+		"two kinds":         "steps:\n  - id: a\n    query: hi\n    approval: ok?\n",
+		"no kind":           "steps:\n  - id: a\n",
+		"unknown condition": "steps:\n  - id: a\n    query: hi\n    if: {output: b}\n",
+		"bad retry":         "steps:\n  - id: a\n    query: hi\n    retry: {attempts: 0}\n",
+		"bad template":      "steps:\n  - id: a\n    query: \"{{\"\n",
+		"unknown after":     "steps:\n  - id: a\n    query: hi\n    after: [b]\n",
+		"cycle":             "steps:\n  - id: a\n    query: hi\n    after: [b]\n  - id: b\n    query: hi\n    after: [a]\n",
+		"bad duration":      "steps:\n  - id: a\n    query: hi\n    timeout: soon\n",
+	}
+	for name, yaml := range tests {
+		if _, err := Parse([]byte(yaml)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	var calls atomic.Int32
+	executor := executorFunc(func(ctx context.Context, _ *types.QueryRequest) (*types.QueryResponse, error) {
+		if calls.Add(1) == 1 {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &types.QueryResponse{Content: []types.ContentBlock{types.NewTextBlock("ok")}}, nil
+	})
+
+	w, err := New("timeout", executor, []Step{
+		Query("slow", "Think hard", WithTimeout(20*time.Millisecond), WithRetry(2, 0)),
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	report, err := w.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Expected the timed-out attempt to be retried, got %v\n%s", err, report)
+	}
+	if report.Steps[0].Attempts != 2 || report.Steps[0].Output != "ok" {
+		t.Errorf("Unexpected step result %+v", report.Steps[0])
+	}
+}
+
+// executorFunc adapts a function to types.QueryExecutor.
+type executorFunc func(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error)
+
+func (f executorFunc) Query(ctx context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	return f(ctx, request)
+}
@@ -0,0 +1,65 @@
+/*
+Package workflow composes Claude Code queries, checks and human approvals
+into a DAG of dependent steps, with per-step retries and a final report.
+
+Steps run one at a time in dependency order, so query steps run through a
+ClaudeCodeSession share one conversation. A step runs only after every step
+in its DependsOn has succeeded; the first step to fail after its retries
+stops the run and the remaining steps are skipped.
+
+# Basic Usage
+
+	session, err := claudeClient.CreateSession(ctx, "")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w, err := workflow.New("add-feature", session, []workflow.Step{
+		workflow.Query("plan", "Plan how to add {{.feature}} to the CLI"),
+		workflow.Query("implement", "Implement the plan", workflow.After("plan"), workflow.WithRetry(2, time.Minute)),
+		workflow.Command("test", "go", []string{"test", "./..."}, workflow.After("implement")),
+		workflow.Approval("review", "Tests pass. Keep the change?\n{{output \"plan\"}}", workflow.After("test")),
+	}, workflow.WithDir(projectDir), workflow.WithApprover(askOnTerminal))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report, err := w.Run(ctx, map[string]any{"feature": "a --json flag"})
+	fmt.Print(report)
+
+# Steps
+
+  - Query and QueryRequest send a prompt to Claude. Prompts are templates
+    where {{output "id"}} inserts an earlier step's output and {{.key}} a
+    value passed to Run or stored with Run.Set.
+  - Check and Command verify the state of the project, failing the step on
+    an error or a non-zero exit status.
+  - Approval asks the workflow's Approver to continue and fails with
+    ErrRejected when it declines.
+  - Branch runs one of two sets of steps; the other set, and the steps that
+    depend on it, are skipped.
+
+Any step can be made conditional with If, retried with WithRetry and given
+a time limit per attempt with WithTimeout.
+
+# YAML Definitions
+
+Workflows can also be declared in YAML, so people who do not write Go can
+author agent tasks that a Go service runs. Load validates the definition
+as New would, rejecting unknown keys, and Build creates the workflow (see
+Definition for the format):
+
+	definition, err := workflow.Load("workflows/add-feature.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	w, err := definition.Build(session, workflow.WithApprover(askOnTerminal))
+	if err != nil {
+		log.Fatal(err)
+	}
+	report, err := w.Run(ctx, map[string]any{"feature": "a --json flag"})
+
+Conditions in YAML test a step's output or a run value with contains,
+not_contains and equals, in place of the functions taken by If and Branch.
+*/
+package workflow
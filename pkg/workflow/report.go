@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Status is the outcome of a step or workflow.
+type Status string
+
+const (
+	// StatusSucceeded means the step or workflow completed
+	StatusSucceeded Status = "succeeded"
+
+	// StatusFailed means the step failed after its retries
+	StatusFailed Status = "failed"
+
+	// StatusSkipped means the step did not run
+	StatusSkipped Status = "skipped"
+)
+
+// StepResult records how a step ran.
+type StepResult struct {
+	ID     string `json:"id"`
+	Kind   Kind   `json:"kind"`
+	Status Status `json:"status"`
+
+	// Output is the response text of query steps, the command output of
+	// Command steps, the message of approval steps and "true" or "false" for
+	// branch steps
+	Output string `json:"output,omitempty"`
+
+	// Response is the full response of query steps
+	Response *types.QueryResponse `json:"response,omitempty"`
+
+	// Attempts is the number of times the step ran
+	Attempts int `json:"attempts,omitempty"`
+
+	// Error is the last error of a failed step
+	Error string `json:"error,omitempty"`
+
+	// SkipReason explains why a skipped step did not run
+	SkipReason string `json:"skip_reason,omitempty"`
+
+	StartedAt time.Time     `json:"started_at,omitempty"`
+	Duration  time.Duration `json:"duration,omitempty"`
+}
+
+// skip marks the step as skipped.
+func (r *StepResult) skip(reason string) {
+	r.Status = StatusSkipped
+	r.SkipReason = reason
+}
+
+// Report is the outcome of a workflow run.
+type Report struct {
+	Workflow string `json:"workflow"`
+	Status   Status `json:"status"`
+
+	// Steps are the step results in the order the steps ran
+	Steps []*StepResult `json:"steps"`
+
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+
+	err error
+}
+
+// Err returns the error of the step that failed the workflow, if any.
+func (r *Report) Err() error {
+	return r.err
+}
+
+// Step returns the result of a step, or nil if there is no such step.
+func (r *Report) Step(id string) *StepResult {
+	for _, step := range r.Steps {
+		if step.ID == id {
+			return step
+		}
+	}
+	return nil
+}
+
+// Usage returns the token usage summed over all query steps.
+func (r *Report) Usage() types.TokenUsage {
+	var usage types.TokenUsage
+	for _, step := range r.Steps {
+		if step.Response != nil && step.Response.Usage != nil {
+			usage.InputTokens += step.Response.Usage.InputTokens
+			usage.OutputTokens += step.Response.Usage.OutputTokens
+			usage.TotalTokens += step.Response.Usage.TotalTokens
+		}
+	}
+	return usage
+}
+
+// String summarizes the run, one line per step.
+func (r *Report) String() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "workflow %s %s in %s\n", r.Workflow, r.Status, r.Duration.Round(time.Millisecond))
+	for _, step := range r.Steps {
+		fmt.Fprintf(&out, "  %-8s %-10s %s", step.Status, step.Kind, step.ID)
+		switch {
+		case step.Status == StatusSkipped:
+			fmt.Fprintf(&out, " (%s)", step.SkipReason)
+		case step.Status == StatusFailed:
+			fmt.Fprintf(&out, " after %d attempt(s): %s", step.Attempts, step.Error)
+		case step.Attempts > 1:
+			fmt.Fprintf(&out, " after %d attempts", step.Attempts)
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
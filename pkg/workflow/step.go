@@ -0,0 +1,252 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// ErrRejected is returned by approval steps that a human rejected.
+var ErrRejected = errors.New("approval rejected")
+
+// Kind identifies what a step does.
+type Kind string
+
+const (
+	// KindQuery sends a prompt to Claude
+	KindQuery Kind = "query"
+
+	// KindCheck runs Go code or a command that must succeed
+	KindCheck Kind = "check"
+
+	// KindApproval asks a human to approve before continuing
+	KindApproval Kind = "approval"
+
+	// KindBranch chooses which of two sets of steps runs
+	KindBranch Kind = "branch"
+)
+
+// RetryPolicy controls how a failed step is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first (minimum 1)
+	MaxAttempts int
+
+	// Backoff is the delay between attempts
+	Backoff time.Duration
+
+	// Retryable decides whether an error is worth retrying (defaults to any
+	// error except ErrRejected and context errors)
+	Retryable func(error) bool
+}
+
+// shouldRetry reports whether a step that failed with err on the given
+// attempt should run again.
+func (p RetryPolicy) shouldRetry(err error, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return !errors.Is(err, ErrRejected) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// Step is a node in a workflow. Create steps with Query, QueryRequest,
+// Check, Command, Approval or Branch.
+type Step struct {
+	// ID names the step; later steps refer to it in DependsOn and templates
+	ID string
+
+	// Kind is what the step does
+	Kind Kind
+
+	// DependsOn lists the steps that must succeed before this one runs
+	DependsOn []string
+
+	// Retry controls retries of a failed step (default one attempt)
+	Retry RetryPolicy
+
+	// Timeout bounds each attempt of the step (no limit when zero)
+	Timeout time.Duration
+
+	// If skips the step when it returns false
+	If func(run *Run) bool
+
+	// run executes the step, filling in its output and response
+	run func(ctx context.Context, w *Workflow, run *Run, result *StepResult) error
+
+	// then and otherwise are the arms of a branch step
+	then, otherwise []string
+
+	// err is a construction error reported by New
+	err error
+}
+
+// StepOption configures a step.
+type StepOption func(*Step)
+
+// After makes the step depend on other steps.
+func After(ids ...string) StepOption {
+	return func(s *Step) {
+		s.DependsOn = append(s.DependsOn, ids...)
+	}
+}
+
+// WithRetry retries a failed step up to attempts times in total.
+func WithRetry(attempts int, backoff time.Duration) StepOption {
+	return func(s *Step) {
+		s.Retry.MaxAttempts = attempts
+		s.Retry.Backoff = backoff
+	}
+}
+
+// WithTimeout fails an attempt of the step that runs longer than timeout.
+// Timed-out attempts are retried like other failures.
+func WithTimeout(timeout time.Duration) StepOption {
+	return func(s *Step) {
+		s.Timeout = timeout
+	}
+}
+
+// If runs the step only when cond returns true.
+func If(cond func(run *Run) bool) StepOption {
+	return func(s *Step) {
+		s.If = cond
+	}
+}
+
+// newStep creates a step and applies its options.
+func newStep(id string, kind Kind, opts []StepOption) Step {
+	step := Step{ID: id, Kind: kind, Retry: RetryPolicy{MaxAttempts: 1}}
+	for _, opt := range opts {
+		opt(&step)
+	}
+	return step
+}
+
+// Query creates a step that sends prompt to Claude. The prompt is a
+// text/template where {{output "id"}} inserts the output of an earlier step.
+func Query(id, prompt string, opts ...StepOption) Step {
+	tmpl, err := parseTemplate(id, prompt)
+	step := QueryRequest(id, func(run *Run) (*types.QueryRequest, error) {
+		text, err := run.render(tmpl)
+		if err != nil {
+			return nil, err
+		}
+		return &types.QueryRequest{
+			Messages: []types.Message{{Role: types.RoleUser, Content: text}},
+		}, nil
+	}, opts...)
+	step.err = err
+	return step
+}
+
+// QueryRequest creates a query step from a request built when the step runs.
+func QueryRequest(id string, build func(run *Run) (*types.QueryRequest, error), opts ...StepOption) Step {
+	step := newStep(id, KindQuery, opts)
+	step.run = func(ctx context.Context, w *Workflow, run *Run, result *StepResult) error {
+		request, err := build(run)
+		if err != nil {
+			return err
+		}
+		response, err := w.executor.Query(ctx, request)
+		if err != nil {
+			return err
+		}
+		result.Response = response
+		result.Output = response.GetTextContent()
+		return nil
+	}
+	return step
+}
+
+// Check creates a step that fails when fn returns an error, for example to
+// verify that Claude's changes build.
+func Check(id string, fn func(ctx context.Context, run *Run) error, opts ...StepOption) Step {
+	step := newStep(id, KindCheck, opts)
+	step.run = func(ctx context.Context, _ *Workflow, run *Run, _ *StepResult) error {
+		return fn(ctx, run)
+	}
+	return step
+}
+
+// Command creates a check step that runs a command in the workflow's
+// directory. The combined output becomes the step output, and the step fails
+// if the command exits with a non-zero status.
+func Command(id, name string, args []string, opts ...StepOption) Step {
+	step := newStep(id, KindCheck, opts)
+	step.run = func(ctx context.Context, w *Workflow, _ *Run, result *StepResult) error {
+		cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 - the command is chosen by the workflow author
+		cmd.Dir = w.dir
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		err := cmd.Run()
+		result.Output = output.String()
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.Join(append([]string{name}, args...), " "), err)
+		}
+		return nil
+	}
+	return step
+}
+
+// Approval creates a step that asks the workflow's approver to continue.
+// The message is a template like the prompt of Query. A rejection fails the
+// step with ErrRejected.
+func Approval(id, message string, opts ...StepOption) Step {
+	tmpl, err := parseTemplate(id, message)
+	step := newStep(id, KindApproval, opts)
+	step.err = err
+	step.run = func(ctx context.Context, w *Workflow, run *Run, result *StepResult) error {
+		if w.approver == nil {
+			return errors.New("workflow has no approver")
+		}
+		text, err := run.render(tmpl)
+		if err != nil {
+			return err
+		}
+		result.Output = text
+		approved, err := w.approver(ctx, id, text)
+		if err != nil {
+			return err
+		}
+		if !approved {
+			return ErrRejected
+		}
+		return nil
+	}
+	return step
+}
+
+// Branch creates a step that runs the steps in then when cond is true, and
+// the steps in otherwise when it is false. The steps of the other arm, and
+// any step depending on them, are skipped. Its output is "true" or "false".
+func Branch(id string, cond func(run *Run) bool, then, otherwise []string, opts ...StepOption) Step {
+	step := newStep(id, KindBranch, opts)
+	step.then, step.otherwise = then, otherwise
+	step.run = func(_ context.Context, _ *Workflow, run *Run, result *StepResult) error {
+		result.Output = fmt.Sprint(cond(run))
+		return nil
+	}
+	return step
+}
+
+// parseTemplate parses a prompt or message template. The output function is
+// bound to the run when the template executes.
+func parseTemplate(id, text string) (*template.Template, error) {
+	tmpl, err := template.New(id).Funcs(template.FuncMap{
+		"output": func(string) string { return "" },
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("step %s: invalid template: %w", id, err)
+	}
+	return tmpl, nil
+}
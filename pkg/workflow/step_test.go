@@ -0,0 +1,87 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+func TestCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("command steps use a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	w, err := New("build", nil, []Step{
+		Command("pwd", "sh", []string{"-c", "pwd; echo built"}),
+		Command("fail", "sh", []string{"-c", "echo broken >&2; exit 3"}, After("pwd")),
+	}, WithDir(dir))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	report, err := w.Run(context.Background(), nil)
+	if err == nil {
+		t.Fatal("Expected the failing command to fail the workflow")
+	}
+	if output := report.Step("pwd").Output; !strings.Contains(output, "built") {
+		t.Errorf("Unexpected output %q", output)
+	}
+	if step := report.Step("fail"); step.Status != StatusFailed || step.Output != "broken\n" || !strings.Contains(step.Error, "exit status 3") {
+		t.Errorf("Unexpected failed step %+v", step)
+	}
+}
+
+func TestIfAndMissingApprover(t *testing.T) {
+	w, err := New("conditional", nil, []Step{
+		Check("set", func(_ context.Context, run *Run) error {
+			run.Set("deploy", false)
+			return nil
+		}),
+		Approval("approve", "Deploy?", After("set"), If(func(run *Run) bool { return run.Get("deploy") == true })),
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	report, err := w.Run(context.Background(), nil)
+	if err != nil || report.Step("approve").SkipReason != "condition not met" {
+		t.Errorf("Expected the approval to be skipped, got %v\n%s", err, report)
+	}
+
+	w, _ = New("unapproved", nil, []Step{Approval("approve", "Deploy?")})
+	if _, err := w.Run(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "no approver") {
+		t.Errorf("Expected missing approver error, got %v", err)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w, _ := New("canceled", nil, []Step{Check("a", func(context.Context, *Run) error { return nil }, WithRetry(3, 0))})
+	report, err := w.Run(ctx, nil)
+	if err == nil || report.Step("a").Attempts != 1 {
+		t.Errorf("Expected a canceled run after one attempt, got %v", err)
+	}
+}
+
+func TestWithApprovalProvider(t *testing.T) {
+	var requests []types.ApprovalRequest
+	provider := types.ApprovalFunc(func(_ context.Context, request types.ApprovalRequest) (types.ApprovalDecision, error) {
+		requests = append(requests, request)
+		return types.ApprovalDecision{Reason: "not today"}, nil
+	})
+
+	w, _ := New("release", nil, []Step{Approval("ship", "Ship {{.version}}?")}, WithApprovalProvider(provider))
+	_, err := w.Run(context.Background(), map[string]any{"version": "v2"})
+	if !errors.Is(err, ErrRejected) || !strings.Contains(err.Error(), "not today") {
+		t.Errorf("Expected a rejection with the reason, got %v", err)
+	}
+	if len(requests) != 1 || requests[0].Kind != types.ApprovalKindWorkflow || requests[0].StepID != "ship" || requests[0].Message != "Ship v2?" {
+		t.Errorf("Unexpected requests %+v", requests)
+	}
+}
@@ -0,0 +1,314 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	sdkerrors "github.com/jonwraymond/go-claude-code-sdk/pkg/errors"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// Approver decides whether an approval step may continue. Message describes
+// what is being approved.
+type Approver func(ctx context.Context, stepID, message string) (bool, error)
+
+// Option configures a Workflow.
+type Option func(*Workflow)
+
+// WithApprover sets the approver used by approval steps.
+func WithApprover(approver Approver) Option {
+	return func(w *Workflow) {
+		w.approver = approver
+	}
+}
+
+// WithApprovalProvider asks provider at approval steps, so workflows share
+// the client's terminal, HTTP or Slack approvals. A denial's reason is
+// included in the step error.
+func WithApprovalProvider(provider types.ApprovalProvider) Option {
+	return func(w *Workflow) {
+		w.approver = func(ctx context.Context, stepID, message string) (bool, error) {
+			decision, err := provider.Approve(ctx, types.ApprovalRequest{
+				Kind:    types.ApprovalKindWorkflow,
+				StepID:  stepID,
+				Message: message,
+			})
+			if err != nil {
+				return false, err
+			}
+			if !decision.Approved && decision.Reason != "" {
+				return false, fmt.Errorf("%w: %s", ErrRejected, decision.Reason)
+			}
+			return decision.Approved, nil
+		}
+	}
+}
+
+// WithDir sets the directory Command steps run in (defaults to the current directory).
+func WithDir(dir string) Option {
+	return func(w *Workflow) {
+		w.dir = dir
+	}
+}
+
+// Workflow is a DAG of steps run in dependency order. Steps run one at a
+// time, so query steps can share a session, and a step runs only after every
+// step it depends on has succeeded.
+type Workflow struct {
+	name     string
+	executor types.QueryExecutor
+	steps    []Step
+	approver Approver
+	dir      string
+	now      func() time.Time
+}
+
+// New validates the steps and returns a workflow that runs them with
+// executor. Step IDs must be unique, dependencies and branch arms must name
+// existing steps, and the dependencies must not form a cycle. A session as
+// executor gives every query step the same conversation.
+func New(name string, executor types.QueryExecutor, steps []Step, opts ...Option) (*Workflow, error) {
+	w := &Workflow{name: name, executor: executor, now: time.Now}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	index := make(map[string]int, len(steps))
+	for i, step := range steps {
+		if step.ID == "" {
+			return nil, sdkerrors.NewValidationError("steps", fmt.Sprint(i), "step ID", "step IDs cannot be empty")
+		}
+		if _, ok := index[step.ID]; ok {
+			return nil, sdkerrors.NewValidationError("steps", step.ID, "unique step ID", "duplicate step "+step.ID)
+		}
+		if step.err != nil {
+			return nil, sdkerrors.WrapError(step.err, sdkerrors.CategoryValidation, "WORKFLOW_STEP", "invalid step "+step.ID)
+		}
+		if step.run == nil {
+			return nil, sdkerrors.NewValidationError("steps", step.ID, "constructed step", "step "+step.ID+" has nothing to run")
+		}
+		index[step.ID] = i
+	}
+	for _, step := range steps {
+		for _, ref := range append(append(append([]string(nil), step.DependsOn...), step.then...), step.otherwise...) {
+			if _, ok := index[ref]; !ok {
+				return nil, sdkerrors.NewValidationError("steps", step.ID, "existing step", fmt.Sprintf("step %s refers to unknown step %s", step.ID, ref))
+			}
+		}
+	}
+
+	order, err := topologicalOrder(steps, index)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range order {
+		w.steps = append(w.steps, steps[i])
+	}
+	return w, nil
+}
+
+// topologicalOrder sorts steps so each follows its dependencies, keeping the
+// declared order among independent steps.
+func topologicalOrder(steps []Step, index map[string]int) ([]int, error) {
+	pending := make([]int, len(steps))
+	dependents := make([][]int, len(steps))
+	for i, step := range steps {
+		for _, dep := range step.DependsOn {
+			pending[i]++
+			dependents[index[dep]] = append(dependents[index[dep]], i)
+		}
+	}
+
+	order := make([]int, 0, len(steps))
+	done := make([]bool, len(steps))
+	for len(order) < len(steps) {
+		next := -1
+		for i := range steps {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var cycle []string
+			for i, step := range steps {
+				if !done[i] {
+					cycle = append(cycle, step.ID)
+				}
+			}
+			return nil, sdkerrors.NewValidationError("steps", strings.Join(cycle, ","), "acyclic dependencies", "workflow steps form a dependency cycle")
+		}
+		done[next] = true
+		order = append(order, next)
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+	return order, nil
+}
+
+// Run holds the state of a workflow run. Steps read earlier outputs and
+// share values through it.
+type Run struct {
+	results map[string]*StepResult
+	values  map[string]any
+}
+
+// Output returns the output of a step, or "" if it has not succeeded.
+func (r *Run) Output(id string) string {
+	if result, ok := r.results[id]; ok && result.Status == StatusSucceeded {
+		return result.Output
+	}
+	return ""
+}
+
+// Result returns the result of a step that has finished or been skipped.
+func (r *Run) Result(id string) (*StepResult, bool) {
+	result, ok := r.results[id]
+	return result, ok
+}
+
+// Set stores a value for later steps. Values are also the data of prompt templates.
+func (r *Run) Set(key string, value any) {
+	r.values[key] = value
+}
+
+// Get returns a value stored with Set.
+func (r *Run) Get(key string) any {
+	return r.values[key]
+}
+
+// render executes a prompt template against the run.
+func (r *Run) render(tmpl *template.Template) (string, error) {
+	bound, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := bound.Funcs(template.FuncMap{"output": r.Output}).Execute(&out, r.values); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// Run executes the workflow. The first step to fail after its retries stops
+// the run, and the remaining steps are skipped. The report is returned even
+// when the workflow fails.
+func (w *Workflow) Run(ctx context.Context, values map[string]any) (*Report, error) {
+	run := &Run{results: make(map[string]*StepResult), values: make(map[string]any)}
+	for key, value := range values {
+		run.values[key] = value
+	}
+
+	report := &Report{Workflow: w.name, Status: StatusSucceeded, StartedAt: w.now()}
+	branchSkipped := make(map[string]string)
+
+	for _, step := range w.steps {
+		result := &StepResult{ID: step.ID, Kind: step.Kind}
+		run.results[step.ID] = result
+		report.Steps = append(report.Steps, result)
+
+		if report.Status == StatusFailed {
+			result.skip("workflow failed")
+			continue
+		}
+		if reason := w.skipReason(step, run, branchSkipped); reason != "" {
+			result.skip(reason)
+			continue
+		}
+
+		if err := w.runStep(ctx, step, run, result); err != nil {
+			report.Status = StatusFailed
+			report.err = sdkerrors.WrapError(err, sdkerrors.CategoryInternal, "WORKFLOW_STEP", "workflow step "+step.ID+" failed")
+			continue
+		}
+
+		if step.Kind == KindBranch {
+			skipped, taken := step.otherwise, "true"
+			if result.Output != "true" {
+				skipped, taken = step.then, "false"
+			}
+			for _, id := range skipped {
+				branchSkipped[id] = fmt.Sprintf("branch %s was %s", step.ID, taken)
+			}
+		}
+	}
+
+	report.Duration = w.now().Sub(report.StartedAt)
+	return report, report.err
+}
+
+// skipReason explains why a step should not run, or returns "".
+func (w *Workflow) skipReason(step Step, run *Run, branchSkipped map[string]string) string {
+	if reason, ok := branchSkipped[step.ID]; ok {
+		return reason
+	}
+	for _, dep := range step.DependsOn {
+		if run.results[dep].Status != StatusSucceeded {
+			return "dependency " + dep + " " + string(run.results[dep].Status)
+		}
+	}
+	if step.If != nil && !step.If(run) {
+		return "condition not met"
+	}
+	return ""
+}
+
+// runStep runs a step with retries, recording the outcome in result.
+func (w *Workflow) runStep(ctx context.Context, step Step, run *Run, result *StepResult) error {
+	result.StartedAt = w.now()
+	defer func() { result.Duration = w.now().Sub(result.StartedAt) }()
+
+	retry := step.Retry
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
+
+	for {
+		result.Attempts++
+		result.Output, result.Response = "", nil
+
+		err := ctx.Err()
+		if err == nil {
+			err = w.attempt(ctx, step, run, result)
+		}
+		if err == nil {
+			result.Status = StatusSucceeded
+			result.Error = ""
+			return nil
+		}
+
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		if !retry.shouldRetry(err, result.Attempts) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Error = ctx.Err().Error()
+			return ctx.Err()
+		case <-time.After(retry.Backoff):
+		}
+	}
+}
+
+// attempt runs a step once, within its timeout. A timed-out attempt fails
+// with an error of its own rather than the context's, so it is retried.
+func (w *Workflow) attempt(ctx context.Context, step Step, run *Run, result *StepResult) error {
+	if step.Timeout <= 0 {
+		return step.run(ctx, w, run, result)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, step.Timeout)
+	defer cancel()
+	err := step.run(attemptCtx, w, run, result)
+	if err != nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("step %s timed out after %s", step.ID, step.Timeout)
+	}
+	return err
+}
@@ -0,0 +1,178 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// fakeExecutor answers queries with a function and records the prompts.
+type fakeExecutor struct {
+	mu      sync.Mutex
+	prompts []string
+	answer  func(prompt string) (string, error)
+}
+
+func (f *fakeExecutor) Query(_ context.Context, request *types.QueryRequest) (*types.QueryResponse, error) {
+	prompt := request.Messages[len(request.Messages)-1].Content
+	f.mu.Lock()
+	f.prompts = append(f.prompts, prompt)
+	f.mu.Unlock()
+
+	text, err := f.answer(prompt)
+	if err != nil {
+		return nil, err
+	}
+	return &types.QueryResponse{
+		Content: []types.ContentBlock{types.NewTextBlock(text)},
+		Usage:   &types.TokenUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+func TestWorkflow_Run(t *testing.T) {
+	executor := &fakeExecutor{answer: func(prompt string) (string, error) {
+		return "answer to " + prompt, nil
+	}}
+
+	steps := []Step{
+		Query("summary", "Summarize the {{output \"plan\"}} for {{.project}}", After("plan")),
+		Query("plan", "Plan the change"),
+		Check("verify", func(_ context.Context, run *Run) error {
+			run.Set("verified", true)
+			return nil
+		}, After("summary")),
+	}
+	w, err := New("release", executor, steps)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	report, err := w.Run(context.Background(), map[string]any{"project": "sdk"})
+	if err != nil {
+		t.Fatalf("Run failed: %v\n%s", err, report)
+	}
+	if report.Status != StatusSucceeded || len(report.Steps) != 3 || report.Steps[0].ID != "plan" {
+		t.Fatalf("Unexpected report:\n%s", report)
+	}
+	if executor.prompts[1] != "Summarize the answer to Plan the change for sdk" {
+		t.Errorf("Unexpected prompt %q", executor.prompts[1])
+	}
+	if usage := report.Usage(); usage.TotalTokens != 30 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+	if !strings.Contains(report.String(), "succeeded query      summary") {
+		t.Errorf("Unexpected summary:\n%s", report)
+	}
+}
+
+func TestWorkflow_RetryAndFailure(t *testing.T) {
+	calls := 0
+	executor := &fakeExecutor{answer: func(string) (string, error) { return "ok", nil }}
+
+	steps := []Step{
+		Check("flaky", func(context.Context, *Run) error {
+			calls++
+			if calls < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		}, WithRetry(3, 0)),
+		Check("broken", func(context.Context, *Run) error { return errors.New("boom") }, After("flaky"), WithRetry(2, 0)),
+		Query("after", "never", After("broken")),
+		Query("independent", "also never"),
+	}
+	w, err := New("retries", executor, steps)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	report, err := w.Run(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "boom") || report.Err() != err {
+		t.Fatalf("Expected the broken step error, got %v", err)
+	}
+	if step := report.Step("flaky"); step.Status != StatusSucceeded || step.Attempts != 3 {
+		t.Errorf("Unexpected flaky result %+v", step)
+	}
+	if step := report.Step("broken"); step.Status != StatusFailed || step.Attempts != 2 {
+		t.Errorf("Unexpected broken result %+v", step)
+	}
+	for _, id := range []string{"after", "independent"} {
+		if step := report.Step(id); step.Status != StatusSkipped {
+			t.Errorf("Expected %s to be skipped, got %+v", id, step)
+		}
+	}
+	if len(executor.prompts) != 0 {
+		t.Errorf("Expected no queries, got %v", executor.prompts)
+	}
+}
+
+func TestWorkflow_BranchAndApproval(t *testing.T) {
+	executor := &fakeExecutor{answer: func(string) (string, error) { return "FAIL: TestParse", nil }}
+	var approvals []string
+	approver := func(_ context.Context, stepID, message string) (bool, error) {
+		approvals = append(approvals, stepID+": "+message)
+		return stepID != "deploy", nil
+	}
+
+	steps := []Step{
+		Query("test", "Run the tests"),
+		Branch("passed", func(run *Run) bool {
+			return !strings.Contains(run.Output("test"), "FAIL")
+		}, []string{"deploy"}, []string{"fix"}, After("test")),
+		Approval("deploy", "Deploy?", After("passed")),
+		Approval("fix", "Apply a fix for {{output \"test\"}}?", After("passed")),
+		Query("fix-it", "Fix it", After("fix")),
+		Query("announce", "Announce the release", After("deploy")),
+	}
+	w, err := New("ci", executor, steps, WithApprover(approver))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	report, err := w.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v\n%s", err, report)
+	}
+	if report.Step("passed").Output != "false" || report.Step("deploy").Status != StatusSkipped ||
+		report.Step("announce").Status != StatusSkipped || report.Step("fix-it").Status != StatusSucceeded {
+		t.Errorf("Unexpected branch results:\n%s", report)
+	}
+	if len(approvals) != 1 || approvals[0] != "fix: Apply a fix for FAIL: TestParse?" {
+		t.Errorf("Unexpected approvals %v", approvals)
+	}
+
+	// A rejection fails the workflow without retrying
+	rejected, _ := New("deploy", executor, []Step{Approval("deploy", "Deploy?", WithRetry(3, 0))}, WithApprover(approver))
+	report, err = rejected.Run(context.Background(), nil)
+	if !errors.Is(err, ErrRejected) || report.Step("deploy").Attempts != 1 {
+		t.Errorf("Expected ErrRejected after one attempt, got %v (%d attempts)", err, report.Step("deploy").Attempts)
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	executor := &fakeExecutor{}
+
+	tests := []struct {
+		name  string
+		steps []Step
+		want  string
+	}{
+		{"duplicate", []Step{Query("a", "x"), Query("a", "y")}, "duplicate step a"},
+		{"unknown dependency", []Step{Query("a", "x", After("missing"))}, "unknown step missing"},
+		{"unknown branch arm", []Step{Branch("b", nil, []string{"missing"}, nil)}, "unknown step missing"},
+		{"cycle", []Step{Query("a", "x", After("b")), Query("b", "y", After("a"))}, "cycle"},
+		{"bad template", []Step{Query("a", "{{output")}, "invalid step a"},
+		{"empty step", []Step{{ID: "a"}}, "nothing to run"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New("invalid", executor, tt.steps); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
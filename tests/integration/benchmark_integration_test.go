@@ -0,0 +1,68 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/client"
+	"github.com/jonwraymond/go-claude-code-sdk/pkg/types"
+)
+
+// newBenchmarkClient creates a client for the real CLI, skipping the
+// benchmark when integration tests are disabled or the CLI is missing.
+func newBenchmarkClient(b *testing.B) *client.ClaudeCodeClient {
+	b.Helper()
+
+	if os.Getenv("INTEGRATION_TESTS") != "true" {
+		b.Skip("Integration tests disabled. Set INTEGRATION_TESTS=true to run")
+	}
+	if _, err := exec.LookPath("claude"); err != nil {
+		b.Skip("claude CLI not found in PATH")
+	}
+
+	config := types.NewClaudeCodeConfig()
+	config.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+	config.Timeout = 60 * time.Second
+
+	claudeClient, err := client.NewClaudeCodeClient(context.Background(), config)
+	if err != nil {
+		b.Fatalf("Failed to create client: %v", err)
+	}
+	b.Cleanup(func() { claudeClient.Close() })
+	return claudeClient
+}
+
+// BenchmarkCLIStartup measures starting the CLI without a model call.
+func BenchmarkCLIStartup(b *testing.B) {
+	newBenchmarkClient(b)
+
+	for i := 0; i < b.N; i++ {
+		if err := exec.Command("claude", "--version").Run(); err != nil {
+			b.Fatalf("claude --version failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkQueryRoundTrip measures a minimal query end to end, including the
+// model call. Run it with a small -benchtime such as 3x to bound the cost.
+func BenchmarkQueryRoundTrip(b *testing.B) {
+	claudeClient := newBenchmarkClient(b)
+	if os.Getenv("ANTHROPIC_API_KEY") == "" {
+		b.Skip("ANTHROPIC_API_KEY not set")
+	}
+	request := &types.QueryRequest{
+		Messages: []types.Message{{Role: types.RoleUser, Content: "Reply with the single word: ok"}},
+	}
+
+	for i := 0; i < b.N; i++ {
+		if _, err := claudeClient.Query(context.Background(), request); err != nil {
+			b.Fatalf("Query failed: %v", err)
+		}
+	}
+}